@@ -4,6 +4,7 @@ package api
 
 import (
 	"bytes"
+	"crypto/tls"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -13,6 +14,14 @@ import (
 
 const HTTPTimeout = time.Second * 30
 
+var (
+	// TLSConfig, if set, is used to connect to an HTTPS REST interface.
+	TLSConfig *tls.Config
+
+	// BearerToken, if set, is sent with every request to authenticate it.
+	BearerToken string
+)
+
 func InvokeRESTAPI(method string, url string, requestBody []byte, debug bool) (*http.Response, []byte, error) {
 
 	var request *http.Request
@@ -33,7 +42,15 @@ func InvokeRESTAPI(method string, url string, requestBody []byte, debug bool) (*
 		LogHTTPRequest(request, requestBody)
 	}
 
+	// Add the token after logging the request, so that it isn't printed
+	if BearerToken != "" {
+		request.Header.Set("Authorization", "Bearer "+BearerToken)
+	}
+
 	client := &http.Client{Timeout: HTTPTimeout}
+	if TLSConfig != nil {
+		client.Transport = &http.Transport{TLSClientConfig: TLSConfig}
+	}
 	response, err := client.Do(request)
 
 	responseBody := []byte{}
@@ -2,18 +2,25 @@
 
 package api
 
-import "github.com/netapp/trident/storage"
+import (
+	"github.com/netapp/trident/persistent_store"
+	"github.com/netapp/trident/storage"
+)
 
 type Backend struct {
 	Name   string `json:"name"`
 	Config struct {
-		Version           int      `json:"version"`
-		StorageDriverName string   `json:"storageDriverName"`
-		StoragePrefix     string   `json:"storagePrefix"`
-		SerialNumbers     []string `json:"serialNumbers"`
+		Version           int               `json:"version"`
+		StorageDriverName string            `json:"storageDriverName"`
+		StoragePrefix     string            `json:"storagePrefix"`
+		OrphanPolicy      string            `json:"orphanPolicy"`
+		OrphanGracePeriod string            `json:"orphanGracePeriod"`
+		Labels            map[string]string `json:"labels"`
+		SerialNumbers     []string          `json:"serialNumbers"`
 	} `json:"config"`
 	Storage interface{} `json:"storage"`
 	Online  bool        `json:"online"`
+	State   string      `json:"state"`
 	Volumes []string    `json:"volumes"`
 }
 
@@ -26,6 +33,19 @@ type MultipleBackendResponse struct {
 	Items []Backend `json:"items"`
 }
 
+type BackendUpdate struct {
+	Backend         Backend  `json:"backend"`
+	ConfigChanges   []string `json:"configChanges"`
+	StorageClasses  []string `json:"storageClasses"`
+	OrphanedVolumes []string `json:"orphanedVolumes"`
+	DryRun          bool     `json:"dryRun"`
+}
+
+type UpdateBackendResponse struct {
+	Update BackendUpdate `json:"update"`
+	Error  string        `json:"error"`
+}
+
 type StorageClass struct {
 	Config struct {
 		Version         string              `json:"version"`
@@ -42,6 +62,11 @@ type GetStorageClassResponse struct {
 	Error        string `json:"error"`
 }
 
+type UpdateStorageClassResponse struct {
+	StorageClass StorageClass `json:"storageClass"`
+	Error        string       `json:"error"`
+}
+
 type MultipleStorageClassResponse struct {
 	Items []StorageClass `json:"items"`
 }
@@ -50,6 +75,14 @@ type MultipleVolumeResponse struct {
 	Items []storage.VolumeExternal `json:"items"`
 }
 
+type MultipleSnapshotResponse struct {
+	Items []storage.SnapshotExternal `json:"items"`
+}
+
+type MultipleOperationResponse struct {
+	Items []persistentstore.Operation `json:"items"`
+}
+
 type VersionResponse struct {
 	Server struct {
 		Version       string `json:"version"`
@@ -70,3 +103,15 @@ type VersionResponse struct {
 		APIVersion    string `json:"apiVersion"`
 	} `json:"client"`
 }
+
+type MultipleReplicationResponse struct {
+	Items []storage.ReplicationStatus `json:"items"`
+}
+
+type MultipleOrphanResponse struct {
+	Items []storage.OrphanedVolume `json:"items"`
+}
+
+type MultipleOrphanAuditResponse struct {
+	Items []storage.OrphanAuditRecord `json:"items"`
+}
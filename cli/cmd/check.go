@@ -0,0 +1,139 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+	"github.com/netapp/trident/frontend/rest"
+	"github.com/netapp/trident/storage"
+)
+
+var checkRepair bool
+
+func init() {
+	RootCmd.AddCommand(checkCmd)
+	checkCmd.Flags().BoolVarP(&checkRepair, "repair", "", false,
+		"Repair the differences that are safe to repair by updating Trident's records")
+}
+
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Compare the volumes on the storage backends with Trident's records",
+	Long: "Compare the volumes on the storage backends with Trident's records, and report volumes " +
+		"that are missing from their backends, that differ in size, or that Trident doesn't know about",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if OperatingMode == ModeTunnel {
+			command := []string{"check"}
+			if checkRepair {
+				command = append(command, "--repair")
+			}
+			TunnelCommand(command)
+			return nil
+		} else {
+			return check()
+		}
+	},
+}
+
+func check() error {
+
+	baseURL, err := GetBaseURL()
+	if err != nil {
+		return err
+	}
+
+	report, err := Reconcile(baseURL, checkRepair)
+	if err != nil {
+		return err
+	}
+
+	WriteReconcileReport(report)
+
+	return nil
+}
+
+// Reconcile asks Trident to compare the volumes on its backends with its
+// records, and returns the differences it found.
+func Reconcile(baseURL string, repair bool) (*storage.ReconcileReport, error) {
+
+	url := baseURL + "/reconcile"
+	if repair {
+		url += "?repair=true"
+	}
+
+	response, responseBody, err := api.InvokeRESTAPI("POST", url, nil, Debug)
+	if err != nil {
+		return nil, err
+	} else if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not check volumes. %v", response.Status)
+	}
+
+	var reconcileResponse rest.ReconcileResponse
+	err = json.Unmarshal(responseBody, &reconcileResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	return reconcileResponse.Report, nil
+}
+
+func WriteReconcileReport(report *storage.ReconcileReport) {
+	switch OutputFormat {
+	case FormatJSON:
+		WriteJSON(report)
+	case FormatYAML:
+		WriteYAML(report)
+	default:
+		writeReconcileTable(report)
+	}
+}
+
+func writeReconcileTable(report *storage.ReconcileReport) {
+
+	if len(report.Drifts) == 0 {
+		fmt.Println("No differences were found.")
+	} else {
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Type", "Backend", "Volume", "Internal Name", "Recorded Size",
+			"Actual Size", "Repaired"})
+
+		for _, drift := range report.Drifts {
+			table.Append([]string{
+				string(drift.Type),
+				drift.Backend,
+				drift.Volume,
+				drift.InternalName,
+				drift.RecordedSize,
+				drift.ActualSize,
+				strconv.FormatBool(drift.Repaired),
+			})
+		}
+
+		table.Render()
+	}
+
+	if len(report.BackendErrors) > 0 {
+		backendNames := make([]string, 0, len(report.BackendErrors))
+		for backendName := range report.BackendErrors {
+			backendNames = append(backendNames, backendName)
+		}
+		sort.Strings(backendNames)
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Backend", "Error"})
+		for _, backendName := range backendNames {
+			table.Append([]string{backendName, report.BackendErrors[backendName]})
+		}
+		table.Render()
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+	"github.com/netapp/trident/frontend/rest"
+	"github.com/netapp/trident/storage"
+)
+
+var snapshotVolume string
+
+func init() {
+	createCmd.AddCommand(createSnapshotCmd)
+	createSnapshotCmd.Flags().StringVarP(&snapshotVolume, "volume", "", "", "Name of the volume to snapshot")
+}
+
+var createSnapshotCmd = &cobra.Command{
+	Use:     "snapshot",
+	Short:   "Take a snapshot of a volume in Trident",
+	Aliases: []string{"snap"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if OperatingMode == ModeTunnel {
+			command := []string{"create", "snapshot", "--volume", snapshotVolume}
+			TunnelCommand(append(command, args...))
+			return nil
+		} else {
+			return snapshotCreate(args)
+		}
+	},
+}
+
+func snapshotCreate(snapshotNames []string) error {
+
+	switch len(snapshotNames) {
+	case 0:
+		return errors.New("snapshot name not specified")
+	case 1:
+		break
+	default:
+		return errors.New("multiple snapshot names specified")
+	}
+
+	if snapshotVolume == "" {
+		return errors.New("volume name not specified")
+	}
+
+	baseURL, err := GetBaseURL()
+	if err != nil {
+		return err
+	}
+
+	snapshotName := snapshotNames[0]
+	url := baseURL + "/volume/" + snapshotVolume + "/snapshot"
+
+	request, err := json.Marshal(rest.AddSnapshotRequest{Name: snapshotName})
+	if err != nil {
+		return err
+	}
+
+	response, responseBody, err := api.InvokeRESTAPI("POST", url, request, Debug)
+	if err != nil {
+		return err
+	}
+
+	var addSnapshotResponse rest.AddSnapshotResponse
+	if err = json.Unmarshal(responseBody, &addSnapshotResponse); err != nil {
+		return err
+	}
+
+	if response.StatusCode != http.StatusCreated {
+		if addSnapshotResponse.Error != "" {
+			return fmt.Errorf("could not create snapshot %s: %s", snapshotName, addSnapshotResponse.Error)
+		}
+		return fmt.Errorf("could not create snapshot %s. %v", snapshotName, response.Status)
+	}
+
+	WriteSnapshots([]storage.SnapshotExternal{*addSnapshotResponse.Snapshot})
+
+	return nil
+}
+
+func WriteSnapshots(snapshots []storage.SnapshotExternal) {
+	switch OutputFormat {
+	case FormatJSON:
+		WriteJSON(api.MultipleSnapshotResponse{snapshots})
+	case FormatYAML:
+		WriteYAML(api.MultipleSnapshotResponse{snapshots})
+	case FormatName:
+		writeSnapshotNames(snapshots)
+	default:
+		writeSnapshotTable(snapshots)
+	}
+}
+
+func writeSnapshotTable(snapshots []storage.SnapshotExternal) {
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Name", "Created"})
+
+	for _, snapshot := range snapshots {
+		table.Append([]string{
+			snapshot.Name,
+			snapshot.Created,
+		})
+	}
+
+	table.Render()
+}
+
+func writeSnapshotNames(snapshots []storage.SnapshotExternal) {
+
+	for _, snapshot := range snapshots {
+		fmt.Println(snapshot.Name)
+	}
+}
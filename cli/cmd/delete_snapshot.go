@@ -0,0 +1,62 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+)
+
+func init() {
+	deleteCmd.AddCommand(deleteSnapshotCmd)
+	deleteSnapshotCmd.Flags().StringVarP(&snapshotVolume, "volume", "", "", "Name of the volume the snapshots belong to")
+}
+
+var deleteSnapshotCmd = &cobra.Command{
+	Use:     "snapshot",
+	Short:   "Delete one or more volume snapshots from Trident",
+	Aliases: []string{"snap", "snapshots"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if OperatingMode == ModeTunnel {
+			command := []string{"delete", "snapshot", "--volume", snapshotVolume}
+			TunnelCommand(append(command, args...))
+			return nil
+		} else {
+			return snapshotDelete(args)
+		}
+	},
+}
+
+func snapshotDelete(snapshotNames []string) error {
+
+	if len(snapshotNames) == 0 {
+		return errors.New("snapshot name not specified")
+	}
+
+	if snapshotVolume == "" {
+		return errors.New("volume name not specified")
+	}
+
+	baseURL, err := GetBaseURL()
+	if err != nil {
+		return err
+	}
+
+	for _, snapshotName := range snapshotNames {
+		url := baseURL + "/volume/" + snapshotVolume + "/snapshot/" + snapshotName
+
+		response, _, err := api.InvokeRESTAPI("DELETE", url, nil, Debug)
+		if err != nil {
+			return err
+		} else if response.StatusCode != http.StatusOK {
+			return fmt.Errorf("could not delete snapshot %s. %v", snapshotName, response.Status)
+		}
+	}
+
+	return nil
+}
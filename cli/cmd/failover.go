@@ -0,0 +1,14 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import "github.com/spf13/cobra"
+
+func init() {
+	RootCmd.AddCommand(failoverCmd)
+}
+
+var failoverCmd = &cobra.Command{
+	Use:   "failover",
+	Short: "Fail over a resource in Trident to its replica",
+}
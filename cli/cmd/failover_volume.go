@@ -0,0 +1,76 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+	"github.com/netapp/trident/frontend/rest"
+	"github.com/netapp/trident/storage"
+)
+
+func init() {
+	failoverCmd.AddCommand(failoverVolumeCmd)
+}
+
+var failoverVolumeCmd = &cobra.Command{
+	Use:     "volume",
+	Short:   "Fail over a replicated volume to its replica",
+	Aliases: []string{"v"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if OperatingMode == ModeTunnel {
+			command := []string{"failover", "volume"}
+			TunnelCommand(append(command, args...))
+			return nil
+		} else {
+			return volumeFailover(args)
+		}
+	},
+}
+
+func volumeFailover(volumeNames []string) error {
+
+	switch len(volumeNames) {
+	case 0:
+		return errors.New("volume name not specified")
+	case 1:
+		break
+	default:
+		return errors.New("multiple volume names specified")
+	}
+
+	baseURL, err := GetBaseURL()
+	if err != nil {
+		return err
+	}
+
+	volumeName := volumeNames[0]
+	url := baseURL + "/volume/" + volumeName + "/failover"
+
+	response, responseBody, err := api.InvokeRESTAPI("POST", url, nil, Debug)
+	if err != nil {
+		return err
+	}
+
+	var failoverVolumeResponse rest.FailoverVolumeResponse
+	if err = json.Unmarshal(responseBody, &failoverVolumeResponse); err != nil {
+		return err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		if failoverVolumeResponse.Error != "" {
+			return fmt.Errorf("could not fail over volume %s: %s", volumeName, failoverVolumeResponse.Error)
+		}
+		return fmt.Errorf("could not fail over volume %s. %v", volumeName, response.Status)
+	}
+
+	WriteVolumes([]storage.VolumeExternal{*failoverVolumeResponse.Volume})
+
+	return nil
+}
@@ -122,13 +122,13 @@ func WriteBackends(backends []api.Backend) {
 func writeBackendTable(backends []api.Backend) {
 
 	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"Name", "Storage Driver", "Online", "Volumes"})
+	table.SetHeader([]string{"Name", "Storage Driver", "State", "Volumes"})
 
 	for _, b := range backends {
 		table.Append([]string{
 			b.Name,
 			b.Config.StorageDriverName,
-			strconv.FormatBool(b.Online),
+			b.State,
 			strconv.Itoa(len(b.Volumes)),
 		})
 	}
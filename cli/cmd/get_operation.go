@@ -0,0 +1,183 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/frontend/rest"
+	"github.com/netapp/trident/persistent_store"
+)
+
+func init() {
+	getCmd.AddCommand(getOperationCmd)
+}
+
+var getOperationCmd = &cobra.Command{
+	Use:     "operation",
+	Short:   "Get one or more asynchronous operations from Trident",
+	Aliases: []string{"op", "operations"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if OperatingMode == ModeTunnel {
+			command := []string{"get", "operation"}
+			TunnelCommand(append(command, args...))
+			return nil
+		} else {
+			return operationList(args)
+		}
+	},
+}
+
+func operationList(operationIDs []string) error {
+
+	baseURL, err := GetBaseURL()
+	if err != nil {
+		return err
+	}
+
+	// If no operations were specified, we'll get all of them
+	if len(operationIDs) == 0 {
+		operationIDs, err = GetOperations(baseURL)
+		if err != nil {
+			return err
+		}
+	}
+
+	operations := make([]persistentstore.Operation, 0, 10)
+
+	for _, operationID := range operationIDs {
+
+		operation, err := GetOperation(baseURL, operationID)
+		if err != nil {
+			return err
+		}
+		operations = append(operations, operation)
+	}
+
+	WriteOperations(operations)
+
+	return nil
+}
+
+func GetOperations(baseURL string) ([]string, error) {
+
+	url := baseURL + "/operation"
+
+	response, responseBody, err := api.InvokeRESTAPI("GET", url, nil, Debug)
+	if err != nil {
+		return nil, err
+	} else if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not get operations. %v", response.Status)
+	}
+
+	var listOperationsResponse rest.ListOperationsResponse
+	err = json.Unmarshal(responseBody, &listOperationsResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	return listOperationsResponse.Operations, nil
+}
+
+func GetOperation(baseURL, operationID string) (persistentstore.Operation, error) {
+
+	url := baseURL + "/operation/" + operationID
+
+	response, responseBody, err := api.InvokeRESTAPI("GET", url, nil, Debug)
+	if err != nil {
+		return persistentstore.Operation{}, err
+	} else if response.StatusCode != http.StatusOK {
+		return persistentstore.Operation{}, fmt.Errorf("could not get operation %s. %v",
+			operationID, response.Status)
+	}
+
+	var getOperationResponse rest.GetOperationResponse
+	err = json.Unmarshal(responseBody, &getOperationResponse)
+	if err != nil {
+		return persistentstore.Operation{}, err
+	}
+
+	return *getOperationResponse.Operation, nil
+}
+
+// WaitForOperation polls an operation until it finishes, and returns the
+// finished operation.
+func WaitForOperation(baseURL, operationID string) (persistentstore.Operation, error) {
+	for {
+		operation, err := GetOperation(baseURL, operationID)
+		if err != nil || operation.Done() {
+			return operation, err
+		}
+		time.Sleep(config.OperationPollInterval)
+	}
+}
+
+func WriteOperations(operations []persistentstore.Operation) {
+	switch OutputFormat {
+	case FormatJSON:
+		WriteJSON(api.MultipleOperationResponse{operations})
+	case FormatYAML:
+		WriteYAML(api.MultipleOperationResponse{operations})
+	case FormatName:
+		writeOperationIDs(operations)
+	case FormatWide:
+		writeWideOperationTable(operations)
+	default:
+		writeOperationTable(operations)
+	}
+}
+
+func writeOperationTable(operations []persistentstore.Operation) {
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"ID", "Type", "Target", "State", "Error"})
+
+	for _, op := range operations {
+		table.Append([]string{
+			op.ID,
+			string(op.Type),
+			op.Target,
+			string(op.State),
+			op.Error,
+		})
+	}
+
+	table.Render()
+}
+
+func writeWideOperationTable(operations []persistentstore.Operation) {
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"ID", "Type", "Target", "State", "Progress", "Error", "Created", "Updated"})
+
+	for _, op := range operations {
+		table.Append([]string{
+			op.ID,
+			string(op.Type),
+			op.Target,
+			string(op.State),
+			op.Progress,
+			op.Error,
+			op.Created,
+			op.Updated,
+		})
+	}
+
+	table.Render()
+}
+
+func writeOperationIDs(operations []persistentstore.Operation) {
+
+	for _, op := range operations {
+		fmt.Println(op.ID)
+	}
+}
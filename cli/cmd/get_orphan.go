@@ -0,0 +1,156 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+	"github.com/netapp/trident/frontend/rest"
+	"github.com/netapp/trident/storage"
+)
+
+var getOrphanAudit bool
+
+func init() {
+	getCmd.AddCommand(getOrphanCmd)
+	getOrphanCmd.Flags().BoolVarP(&getOrphanAudit, "audit", "", false,
+		"Get the actions Trident took about orphaned volumes instead")
+}
+
+var getOrphanCmd = &cobra.Command{
+	Use:     "orphan",
+	Short:   "Get the orphaned volumes Trident found on its backends",
+	Aliases: []string{"orphans"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if OperatingMode == ModeTunnel {
+			command := []string{"get", "orphan"}
+			if getOrphanAudit {
+				command = append(command, "--audit")
+			}
+			TunnelCommand(command)
+			return nil
+		} else {
+			return orphanList()
+		}
+	},
+}
+
+func orphanList() error {
+
+	baseURL, err := GetBaseURL()
+	if err != nil {
+		return err
+	}
+
+	orphansResponse, err := GetOrphans(baseURL)
+	if err != nil {
+		return err
+	}
+
+	if getOrphanAudit {
+		WriteOrphanAudit(orphansResponse.Audit)
+	} else {
+		orphans := make([]storage.OrphanedVolume, 0, len(orphansResponse.Orphans))
+		for _, orphan := range orphansResponse.Orphans {
+			orphans = append(orphans, *orphan)
+		}
+		WriteOrphans(orphans)
+	}
+
+	return nil
+}
+
+func GetOrphans(baseURL string) (*rest.OrphansResponse, error) {
+
+	url := baseURL + "/orphan"
+
+	response, responseBody, err := api.InvokeRESTAPI("GET", url, nil, Debug)
+	if err != nil {
+		return nil, err
+	} else if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not get orphaned volumes. %v", response.Status)
+	}
+
+	var orphansResponse rest.OrphansResponse
+	err = json.Unmarshal(responseBody, &orphansResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	return &orphansResponse, nil
+}
+
+func WriteOrphans(orphans []storage.OrphanedVolume) {
+	switch OutputFormat {
+	case FormatJSON:
+		WriteJSON(api.MultipleOrphanResponse{orphans})
+	case FormatYAML:
+		WriteYAML(api.MultipleOrphanResponse{orphans})
+	case FormatName:
+		writeOrphanNames(orphans)
+	default:
+		writeOrphanTable(orphans)
+	}
+}
+
+func WriteOrphanAudit(records []storage.OrphanAuditRecord) {
+	switch OutputFormat {
+	case FormatJSON:
+		WriteJSON(api.MultipleOrphanAuditResponse{records})
+	case FormatYAML:
+		WriteYAML(api.MultipleOrphanAuditResponse{records})
+	default:
+		writeOrphanAuditTable(records)
+	}
+}
+
+func writeOrphanTable(orphans []storage.OrphanedVolume) {
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Backend", "Internal Name", "Size", "First Seen", "Policy"})
+
+	for _, orphan := range orphans {
+		table.Append([]string{
+			orphan.Backend,
+			orphan.InternalName,
+			orphan.Size,
+			orphan.FirstSeen,
+			orphan.Policy,
+		})
+	}
+
+	table.Render()
+}
+
+func writeOrphanAuditTable(records []storage.OrphanAuditRecord) {
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Time", "Action", "Backend", "Internal Name", "Size", "Error"})
+
+	for _, record := range records {
+		table.Append([]string{
+			record.Time,
+			string(record.Action),
+			record.Backend,
+			record.InternalName,
+			record.Size,
+			record.Error,
+		})
+	}
+
+	table.Render()
+}
+
+func writeOrphanNames(orphans []storage.OrphanedVolume) {
+
+	for _, orphan := range orphans {
+		fmt.Println(orphan.InternalName)
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+	"github.com/netapp/trident/frontend/rest"
+	"github.com/netapp/trident/storage"
+)
+
+func init() {
+	getCmd.AddCommand(getReplicationCmd)
+}
+
+var getReplicationCmd = &cobra.Command{
+	Use:     "replication",
+	Short:   "Get the replication status of one or more volumes from Trident",
+	Aliases: []string{"r", "replications"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if OperatingMode == ModeTunnel {
+			command := []string{"get", "replication"}
+			TunnelCommand(append(command, args...))
+			return nil
+		} else {
+			return replicationList(args)
+		}
+	},
+}
+
+func replicationList(volumeNames []string) error {
+
+	if len(volumeNames) == 0 {
+		return errors.New("volume name not specified")
+	}
+
+	baseURL, err := GetBaseURL()
+	if err != nil {
+		return err
+	}
+
+	replications := make([]storage.ReplicationStatus, 0, len(volumeNames))
+
+	for _, volumeName := range volumeNames {
+
+		replication, err := GetReplication(baseURL, volumeName)
+		if err != nil {
+			return err
+		}
+		replications = append(replications, replication)
+	}
+
+	WriteReplications(volumeNames, replications)
+
+	return nil
+}
+
+func GetReplication(baseURL, volumeName string) (storage.ReplicationStatus, error) {
+
+	url := baseURL + "/volume/" + volumeName + "/replication"
+
+	response, responseBody, err := api.InvokeRESTAPI("GET", url, nil, Debug)
+	if err != nil {
+		return storage.ReplicationStatus{}, err
+	}
+
+	var getReplicationResponse rest.GetVolumeReplicationResponse
+	if err = json.Unmarshal(responseBody, &getReplicationResponse); err != nil {
+		return storage.ReplicationStatus{}, err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		if getReplicationResponse.Error != "" {
+			return storage.ReplicationStatus{}, fmt.Errorf("could not get replication of volume %s: %s",
+				volumeName, getReplicationResponse.Error)
+		}
+		return storage.ReplicationStatus{}, fmt.Errorf("could not get replication of volume %s. %v",
+			volumeName, response.Status)
+	}
+
+	return *getReplicationResponse.Replication, nil
+}
+
+func WriteReplications(volumeNames []string, replications []storage.ReplicationStatus) {
+	switch OutputFormat {
+	case FormatJSON:
+		WriteJSON(api.MultipleReplicationResponse{replications})
+	case FormatYAML:
+		WriteYAML(api.MultipleReplicationResponse{replications})
+	case FormatName:
+		writeReplicationNames(volumeNames)
+	case FormatWide:
+		writeWideReplicationTable(volumeNames, replications)
+	default:
+		writeReplicationTable(volumeNames, replications)
+	}
+}
+
+func writeReplicationTable(volumeNames []string, replications []storage.ReplicationStatus) {
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Volume", "Destination", "State", "Healthy", "Lag (s)"})
+
+	for i, replication := range replications {
+		table.Append([]string{
+			volumeNames[i],
+			replication.Destination,
+			replication.State,
+			strconv.FormatBool(replication.Healthy),
+			strconv.Itoa(replication.LagTime),
+		})
+	}
+
+	table.Render()
+}
+
+func writeWideReplicationTable(volumeNames []string, replications []storage.ReplicationStatus) {
+
+	table := tablewriter.NewWriter(os.Stdout)
+	header := []string{
+		"Volume",
+		"Source",
+		"Destination",
+		"State",
+		"Status",
+		"Schedule",
+		"Healthy",
+		"Unhealthy Reason",
+		"Lag (s)",
+	}
+	table.SetHeader(header)
+
+	for i, replication := range replications {
+		table.Append([]string{
+			volumeNames[i],
+			replication.Source,
+			replication.Destination,
+			replication.State,
+			replication.Status,
+			replication.Schedule,
+			strconv.FormatBool(replication.Healthy),
+			replication.UnhealthyReason,
+			strconv.Itoa(replication.LagTime),
+		})
+	}
+
+	table.Render()
+}
+
+func writeReplicationNames(volumeNames []string) {
+
+	for _, name := range volumeNames {
+		fmt.Println(name)
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+	"github.com/netapp/trident/frontend/rest"
+	"github.com/netapp/trident/persistent_store"
+	"github.com/netapp/trident/storage"
+)
+
+var (
+	importVolumeName         string
+	importVolumeStorageClass string
+	importVolumeRename       bool
+	importVolumeAsync        bool
+	importVolumeWait         bool
+)
+
+func init() {
+	importCmd.AddCommand(importVolumeCmd)
+	importVolumeCmd.Flags().StringVarP(&importVolumeName, "name", "", "", "Name of the volume in Trident")
+	importVolumeCmd.Flags().StringVarP(&importVolumeStorageClass, "storage-class", "", "",
+		"Storage class to associate with the volume")
+	importVolumeCmd.Flags().BoolVarP(&importVolumeRename, "rename", "", false,
+		"Rename the volume on the backend to match the Trident volume name")
+	importVolumeCmd.Flags().BoolVarP(&importVolumeAsync, "async", "", false,
+		"Start the import and print the operation that completes it, without waiting for it to finish")
+	importVolumeCmd.Flags().BoolVarP(&importVolumeWait, "wait", "", false,
+		"Import in the background and poll until the import finishes, rather than waiting on a single request")
+}
+
+var importVolumeCmd = &cobra.Command{
+	Use:     "volume <backend> <volumeName>",
+	Short:   "Import an existing volume to Trident",
+	Aliases: []string{"v"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if OperatingMode == ModeTunnel {
+			command := []string{"import", "volume", "--name", importVolumeName,
+				"--storage-class", importVolumeStorageClass}
+			if importVolumeRename {
+				command = append(command, "--rename")
+			}
+			if importVolumeAsync {
+				command = append(command, "--async")
+			}
+			if importVolumeWait {
+				command = append(command, "--wait")
+			}
+			TunnelCommand(append(command, args...))
+			return nil
+		} else {
+			return volumeImport(args)
+		}
+	},
+}
+
+func volumeImport(args []string) error {
+
+	if len(args) != 2 {
+		return errors.New("backend and volume name must be specified")
+	}
+	if importVolumeAsync && importVolumeWait {
+		return errors.New("only one of --async and --wait may be specified")
+	}
+	backendName, internalName := args[0], args[1]
+
+	volumeName := importVolumeName
+	if volumeName == "" {
+		volumeName = internalName
+	}
+
+	baseURL, err := GetBaseURL()
+	if err != nil {
+		return err
+	}
+
+	url := baseURL + "/volume/import"
+	if importVolumeAsync || importVolumeWait {
+		url += "?async=true"
+	}
+
+	request, err := json.Marshal(rest.ImportVolumeRequest{
+		Backend:      backendName,
+		InternalName: internalName,
+		Rename:       importVolumeRename,
+		Config: &storage.VolumeConfig{
+			Name:         volumeName,
+			StorageClass: importVolumeStorageClass,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	response, responseBody, err := api.InvokeRESTAPI("POST", url, request, Debug)
+	if err != nil {
+		return err
+	}
+
+	var importVolumeResponse rest.ImportVolumeResponse
+	if err = json.Unmarshal(responseBody, &importVolumeResponse); err != nil {
+		return err
+	}
+
+	if response.StatusCode != http.StatusCreated && response.StatusCode != http.StatusAccepted {
+		if importVolumeResponse.Error != "" {
+			return fmt.Errorf("could not import volume %s: %s", internalName, importVolumeResponse.Error)
+		}
+		return fmt.Errorf("could not import volume %s. %v", internalName, response.Status)
+	}
+
+	if importVolumeResponse.Operation == nil {
+		WriteVolumes([]storage.VolumeExternal{*importVolumeResponse.Volume})
+		return nil
+	}
+
+	if importVolumeAsync {
+		WriteOperations([]persistentstore.Operation{*importVolumeResponse.Operation})
+		return nil
+	}
+
+	operation, err := WaitForOperation(baseURL, importVolumeResponse.Operation.ID)
+	if err != nil {
+		return err
+	}
+	if operation.State != persistentstore.OperationSucceeded {
+		return fmt.Errorf("could not import volume %s: %s", internalName, operation.Error)
+	}
+
+	volume, err := GetVolume(baseURL, volumeName)
+	if err != nil {
+		return err
+	}
+	WriteVolumes([]storage.VolumeExternal{volume})
+
+	return nil
+}
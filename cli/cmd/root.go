@@ -11,7 +11,9 @@ import (
 	"strings"
 	"syscall"
 
+	"github.com/netapp/trident/cli/api"
 	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/frontend/rest"
 	"github.com/spf13/cobra"
 	k8s "k8s.io/api/core/v1"
 )
@@ -45,6 +47,12 @@ var (
 	Debug        bool
 	Server       string
 	OutputFormat string
+
+	CACertFile            string
+	ClientCertFile        string
+	ClientKeyFile         string
+	Token                 string
+	InsecureSkipTLSVerify bool
 )
 
 var RootCmd = &cobra.Command{
@@ -54,6 +62,9 @@ var RootCmd = &cobra.Command{
 	Long:         `A CLI tool for managing the NetApp Trident external storage provisioner for Kubernetes`,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 		err := discoverOperatingMode(cmd)
+		if err == nil && OperatingMode == ModeDirect {
+			err = configureSecurity()
+		}
 		return err
 	},
 }
@@ -63,6 +74,15 @@ func init() {
 	RootCmd.PersistentFlags().StringVarP(&Server, "server", "s", "", "Address/port of Trident REST interface")
 	RootCmd.PersistentFlags().StringVarP(&OutputFormat, "output", "o", "", "Output format. One of json|yaml|name|wide|ps (default)")
 	RootCmd.PersistentFlags().StringVarP(&TridentPodNamespace, "namespace", "n", "", "Namespace of Trident deployment")
+	RootCmd.PersistentFlags().StringVar(&CACertFile, "certificate-authority", "",
+		"CA certificate for verifying Trident's HTTPS REST interface")
+	RootCmd.PersistentFlags().StringVar(&ClientCertFile, "client-certificate", "",
+		"Client certificate for authenticating to Trident's HTTPS REST interface")
+	RootCmd.PersistentFlags().StringVar(&ClientKeyFile, "client-key", "", "Client certificate private key")
+	RootCmd.PersistentFlags().StringVar(&Token, "token", "",
+		"Bearer token for authenticating to Trident's HTTPS REST interface")
+	RootCmd.PersistentFlags().BoolVar(&InsecureSkipTLSVerify, "insecure-skip-tls-verify", false,
+		"Don't verify the certificate of Trident's HTTPS REST interface")
 }
 
 func discoverOperatingMode(cmd *cobra.Command) error {
@@ -131,6 +151,37 @@ func discoverOperatingMode(cmd *cobra.Command) error {
 	return nil
 }
 
+// configureSecurity prepares the REST client to connect to Trident's HTTPS
+// REST interface if a server URL starting with https:// or any TLS option was
+// given.  A token may also be supplied via the TRIDENT_TOKEN environment
+// variable.
+func configureSecurity() error {
+
+	secure := strings.HasPrefix(Server, "https://") || CACertFile != "" || ClientCertFile != "" ||
+		ClientKeyFile != "" || InsecureSkipTLSVerify
+	Server = strings.TrimPrefix(strings.TrimPrefix(Server, "https://"), "http://")
+
+	if Token == "" {
+		Token = os.Getenv("TRIDENT_TOKEN")
+	}
+	api.BearerToken = Token
+
+	if !secure {
+		return nil
+	}
+	tlsConfig, err := rest.NewClientTLSConfig(&rest.ClientSecurityConfig{
+		CACertFile:         CACertFile,
+		CertFile:           ClientCertFile,
+		KeyFile:            ClientKeyFile,
+		InsecureSkipVerify: InsecureSkipTLSVerify,
+	})
+	if err != nil {
+		return err
+	}
+	api.TLSConfig = tlsConfig
+	return nil
+}
+
 func discoverKubernetesCLI() error {
 
 	// Try the OpenShift CLI first
@@ -215,7 +266,11 @@ func getTridentPod(namespace string) (string, error) {
 
 func GetBaseURL() (string, error) {
 
-	url := fmt.Sprintf("http://%s%s", Server, config.BaseURL)
+	scheme := "http"
+	if api.TLSConfig != nil {
+		scheme = "https"
+	}
+	url := fmt.Sprintf("%s://%s%s", scheme, Server, config.BaseURL)
 
 	if Debug {
 		fmt.Printf("Trident URL: %s\n", url)
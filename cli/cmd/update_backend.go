@@ -0,0 +1,126 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+)
+
+var dryRun bool
+
+func init() {
+	updateCmd.AddCommand(updateBackendCmd)
+	updateBackendCmd.Flags().StringVarP(&filename, "filename", "f", "", "Path to YAML or JSON file")
+	updateBackendCmd.Flags().StringVarP(&b64Data, "base64", "", "", "Base64 encoding")
+	updateBackendCmd.Flags().BoolVarP(&dryRun, "dry-run", "", false, "Report the effects of the update without applying it")
+	updateBackendCmd.Flags().MarkHidden("base64")
+}
+
+var updateBackendCmd = &cobra.Command{
+	Use:     "backend",
+	Short:   "Update a backend in Trident",
+	Aliases: []string{"b"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+
+		jsonData, err := getBackendCreateData()
+		if err != nil {
+			return err
+		}
+
+		if OperatingMode == ModeTunnel {
+			command := []string{"update", "backend", "--base64", base64.StdEncoding.EncodeToString(jsonData)}
+			if dryRun {
+				command = append(command, "--dry-run")
+			}
+			TunnelCommand(append(command, args...))
+			return nil
+		} else {
+			return backendUpdate(args, jsonData)
+		}
+	},
+}
+
+func backendUpdate(backendNames []string, putData []byte) error {
+
+	switch len(backendNames) {
+	case 0:
+		return errors.New("backend name not specified")
+	case 1:
+		break
+	default:
+		return errors.New("multiple backend names specified")
+	}
+
+	baseURL, err := GetBaseURL()
+	if err != nil {
+		return err
+	}
+
+	backendName := backendNames[0]
+	url := baseURL + "/backend/" + backendName
+	if dryRun {
+		url += "?dryRun=true"
+	}
+
+	response, responseBody, err := api.InvokeRESTAPI("PUT", url, putData, Debug)
+	if err != nil {
+		return err
+	}
+
+	var updateBackendResponse api.UpdateBackendResponse
+	if err = json.Unmarshal(responseBody, &updateBackendResponse); err != nil {
+		return err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		if updateBackendResponse.Error != "" {
+			return fmt.Errorf("could not update backend %s: %s", backendName, updateBackendResponse.Error)
+		}
+		return fmt.Errorf("could not update backend %s. %v", backendName, response.Status)
+	}
+
+	WriteBackendUpdate(updateBackendResponse.Update)
+
+	return nil
+}
+
+func WriteBackendUpdate(update api.BackendUpdate) {
+	switch OutputFormat {
+	case FormatJSON:
+		WriteJSON(update)
+	case FormatYAML:
+		WriteYAML(update)
+	case FormatName:
+		fmt.Println(update.Backend.Name)
+	default:
+		writeBackendUpdateTable(update)
+	}
+}
+
+func writeBackendUpdateTable(update api.BackendUpdate) {
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Name", "Changed Settings", "Storage Classes", "Orphaned Volumes"})
+	table.Append([]string{
+		update.Backend.Name,
+		strings.Join(update.ConfigChanges, "\n"),
+		strings.Join(update.StorageClasses, "\n"),
+		strings.Join(update.OrphanedVolumes, "\n"),
+	})
+	table.Render()
+
+	if update.DryRun {
+		fmt.Println("Dry run; the backend was not updated.")
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+)
+
+func init() {
+	updateCmd.AddCommand(updateStorageClassCmd)
+	updateStorageClassCmd.Flags().StringVarP(&filename, "filename", "f", "", "Path to YAML or JSON file")
+	updateStorageClassCmd.Flags().StringVarP(&b64Data, "base64", "", "", "Base64 encoding")
+	updateStorageClassCmd.Flags().MarkHidden("base64")
+}
+
+var updateStorageClassCmd = &cobra.Command{
+	Use:     "storageclass",
+	Short:   "Update a storage class in Trident",
+	Aliases: []string{"sc"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+
+		jsonData, err := getBackendCreateData()
+		if err != nil {
+			return err
+		}
+
+		if OperatingMode == ModeTunnel {
+			command := []string{"update", "storageclass", "--base64", base64.StdEncoding.EncodeToString(jsonData)}
+			TunnelCommand(append(command, args...))
+			return nil
+		} else {
+			return storageClassUpdate(args, jsonData)
+		}
+	},
+}
+
+func storageClassUpdate(storageClassNames []string, putData []byte) error {
+
+	switch len(storageClassNames) {
+	case 0:
+		return errors.New("storage class name not specified")
+	case 1:
+		break
+	default:
+		return errors.New("multiple storage class names specified")
+	}
+
+	baseURL, err := GetBaseURL()
+	if err != nil {
+		return err
+	}
+
+	storageClassName := storageClassNames[0]
+	url := baseURL + "/storageclass/" + storageClassName
+
+	response, responseBody, err := api.InvokeRESTAPI("PUT", url, putData, Debug)
+	if err != nil {
+		return err
+	}
+
+	var updateStorageClassResponse api.UpdateStorageClassResponse
+	if err = json.Unmarshal(responseBody, &updateStorageClassResponse); err != nil {
+		return err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		if updateStorageClassResponse.Error != "" {
+			return fmt.Errorf("could not update storage class %s: %s", storageClassName,
+				updateStorageClassResponse.Error)
+		}
+		return fmt.Errorf("could not update storage class %s. %v", storageClassName, response.Status)
+	}
+
+	WriteStorageClasses([]api.StorageClass{updateStorageClassResponse.StorageClass})
+
+	return nil
+}
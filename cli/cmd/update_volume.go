@@ -0,0 +1,88 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+	"github.com/netapp/trident/frontend/rest"
+	"github.com/netapp/trident/storage"
+)
+
+var volumeSize string
+
+func init() {
+	updateCmd.AddCommand(updateVolumeCmd)
+	updateVolumeCmd.Flags().StringVarP(&volumeSize, "size", "", "", "New size of the volume (e.g. 10Gi)")
+}
+
+var updateVolumeCmd = &cobra.Command{
+	Use:     "volume",
+	Short:   "Resize a volume in Trident",
+	Aliases: []string{"v"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if OperatingMode == ModeTunnel {
+			command := []string{"update", "volume", "--size", volumeSize}
+			TunnelCommand(append(command, args...))
+			return nil
+		} else {
+			return volumeResize(args)
+		}
+	},
+}
+
+func volumeResize(volumeNames []string) error {
+
+	switch len(volumeNames) {
+	case 0:
+		return errors.New("volume name not specified")
+	case 1:
+		break
+	default:
+		return errors.New("multiple volume names specified")
+	}
+
+	if volumeSize == "" {
+		return errors.New("volume size not specified")
+	}
+
+	baseURL, err := GetBaseURL()
+	if err != nil {
+		return err
+	}
+
+	volumeName := volumeNames[0]
+	url := baseURL + "/volume/" + volumeName
+
+	request, err := json.Marshal(rest.ResizeVolumeRequest{Size: volumeSize})
+	if err != nil {
+		return err
+	}
+
+	response, responseBody, err := api.InvokeRESTAPI("PUT", url, request, Debug)
+	if err != nil {
+		return err
+	}
+
+	var resizeVolumeResponse rest.ResizeVolumeResponse
+	if err = json.Unmarshal(responseBody, &resizeVolumeResponse); err != nil {
+		return err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		if resizeVolumeResponse.Error != "" {
+			return fmt.Errorf("could not resize volume %s: %s", volumeName, resizeVolumeResponse.Error)
+		}
+		return fmt.Errorf("could not resize volume %s. %v", volumeName, response.Status)
+	}
+
+	WriteVolumes([]storage.VolumeExternal{*resizeVolumeResponse.Volume})
+
+	return nil
+}
@@ -28,6 +28,18 @@ const (
 	PersistentStoreBootstrapAttempts = 30
 	PersistentStoreBootstrapTimeout  = PersistentStoreBootstrapAttempts * time.Second
 	PersistentStoreTimeout           = 10 * time.Second
+	PoolCapacityRefreshInterval      = 5 * time.Minute
+	ReconcileInterval                = 10 * time.Minute
+	OrphanCollectionInterval         = 1 * time.Hour
+	MaxOrphanAuditRecords            = 1000
+	OperationRetentionPeriod         = 24 * time.Hour
+	OperationPollInterval            = 2 * time.Second
+
+	/* High availability constants */
+	LeaderLeaseDuration          = 15 * time.Second
+	LeaderRenewDeadline          = 10 * time.Second
+	LeaderRetryPeriod            = 2 * time.Second
+	FollowerCacheRefreshInterval = 1 * time.Minute
 
 	/* Protocol constants */
 	File        Protocol = "file"
@@ -60,6 +72,7 @@ const (
 
 	ContextDocker     DriverContext = "docker"
 	ContextKubernetes DriverContext = "kubernetes"
+	ContextCSI        DriverContext = "csi"
 )
 
 var (
@@ -89,8 +102,13 @@ var (
 	BackendURL      = "/" + OrchestratorName + "/v" + OrchestratorAPIVersion + "/backend"
 	VolumeURL       = "/" + OrchestratorName + "/v" + OrchestratorAPIVersion + "/volume"
 	TransactionURL  = "/" + OrchestratorName + "/v" + OrchestratorAPIVersion + "/txn"
+	SnapshotURL     = "/" + OrchestratorName + "/v" + OrchestratorAPIVersion + "/snapshot"
 	StorageClassURL = "/" + OrchestratorName + "/v" + OrchestratorAPIVersion + "/storageclass"
+	OperationURL    = "/" + OrchestratorName + "/v" + OrchestratorAPIVersion + "/operation"
+	ReconcileURL    = "/" + OrchestratorName + "/v" + OrchestratorAPIVersion + "/reconcile"
+	OrphanURL       = "/" + OrchestratorName + "/v" + OrchestratorAPIVersion + "/orphan"
 	StoreURL        = "/" + OrchestratorName + "/store"
+	LeaderURL       = "/" + OrchestratorName + "/leader"
 
 	UsingPassthroughStore bool
 	CurrentDriverContext  DriverContext
@@ -0,0 +1,160 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/persistent_store"
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/storage_class"
+)
+
+// storeCache is a copy of the objects in the persistent store, from which a
+// replica that isn't the leader answers queries.  Its objects are built from
+// their persistent forms, since the replica doesn't run any storage drivers,
+// so backends and storage classes lack their storage pools.
+type storeCache struct {
+	backends       map[string]*storage.BackendExternal
+	volumes        map[string]*storage.VolumeExternal
+	snapshots      map[string]*storage.SnapshotPersistent
+	storageClasses map[string]*storageclass.External
+	operations     map[string]*persistentstore.Operation
+}
+
+// loadStoreCache reads all of Trident's objects from the persistent store.
+func loadStoreCache(client persistentstore.Client) (*storeCache, error) {
+	cache := &storeCache{
+		backends:       make(map[string]*storage.BackendExternal),
+		volumes:        make(map[string]*storage.VolumeExternal),
+		snapshots:      make(map[string]*storage.SnapshotPersistent),
+		storageClasses: make(map[string]*storageclass.External),
+		operations:     make(map[string]*persistentstore.Operation),
+	}
+
+	// As when bootstrapping, missing keys just mean there are no objects
+	backends, err := client.GetBackends()
+	if err != nil && !persistentstore.MatchKeyNotFoundErr(err) {
+		return nil, err
+	}
+	for _, b := range backends {
+		cache.backends[b.Name] = b.ConstructExternal()
+	}
+
+	volumes, err := client.GetVolumes()
+	if err != nil && !persistentstore.MatchKeyNotFoundErr(err) {
+		return nil, err
+	}
+	for _, v := range volumes {
+		cache.volumes[v.Config.Name] = v
+		if backend, ok := cache.backends[v.Backend]; ok {
+			backend.Volumes = append(backend.Volumes, v.Config.Name)
+		}
+	}
+
+	snapshots, err := client.GetSnapshots()
+	if err != nil && !persistentstore.MatchKeyNotFoundErr(err) {
+		return nil, err
+	}
+	for _, s := range snapshots {
+		cache.snapshots[s.ID()] = s
+	}
+
+	storageClasses, err := client.GetStorageClasses()
+	if err != nil && !persistentstore.MatchKeyNotFoundErr(err) {
+		return nil, err
+	}
+	for _, sc := range storageClasses {
+		cache.storageClasses[sc.GetName()] = storageclass.NewFromPersistent(sc).ConstructExternal()
+	}
+
+	operations, err := client.GetOperations()
+	if err != nil && !persistentstore.MatchKeyNotFoundErr(err) {
+		return nil, err
+	}
+	for _, op := range operations {
+		cache.operations[op.ID] = op
+	}
+
+	return cache, nil
+}
+
+// Follow makes the orchestrator answer queries from a copy of the persistent
+// store, which is refreshed whenever the store changes, until the
+// orchestrator is bootstrapped.  A replica that isn't the leader follows the
+// store so that it can serve read-only requests, and bootstraps once it is
+// elected.  Nothing but queries may be made of a following orchestrator.
+func (o *TridentOrchestrator) Follow() error {
+	cache, err := loadStoreCache(o.storeClient)
+	if err != nil {
+		return fmt.Errorf("unable to read the persistent store: %v", err)
+	}
+
+	o.mutex.Lock()
+	o.storeCache = cache
+	o.stopFollowing = make(chan struct{})
+	go o.followStore(o.stopFollowing)
+	o.mutex.Unlock()
+
+	log.WithField("store", o.storeClient.GetType()).Info("Following the persistent store.")
+	return nil
+}
+
+// followStore refreshes the copy of the persistent store whenever the store
+// reports a change, and periodically in case any changes go unreported.
+func (o *TridentOrchestrator) followStore(stop <-chan struct{}) {
+	var changes <-chan struct{}
+	if watcher, ok := o.storeClient.(persistentstore.Watcher); ok {
+		changes = watcher.Watch(stop)
+	}
+	ticker := time.NewTicker(config.FollowerCacheRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-changes:
+		case <-ticker.C:
+		}
+
+		cache, err := loadStoreCache(o.storeClient)
+		if err != nil {
+			log.Warnf("Unable to refresh the copy of the persistent store: %v", err)
+			continue
+		}
+		o.mutex.Lock()
+		select {
+		case <-stop:
+			// Bootstrapping began while the store was being read
+		default:
+			o.storeCache = cache
+		}
+		o.mutex.Unlock()
+	}
+}
+
+// stopFollowingStore switches the orchestrator from its copy of the
+// persistent store to the state it bootstrapped.
+func (o *TridentOrchestrator) stopFollowingStore() {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if o.storeCache != nil {
+		close(o.stopFollowing)
+		o.storeCache = nil
+	}
+}
+
+// IsFollower returns whether the orchestrator is following the persistent
+// store rather than managing Trident's state.
+func (o *TridentOrchestrator) IsFollower() bool {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	return o.storeCache != nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"sort"
+	"sync"
+)
+
+// lockTable hands out a reader/writer lock for each name, so that operations
+// on different volumes or backends may proceed in parallel while operations
+// on the same one are serialized.  A name's lock is discarded once nobody
+// holds it or waits for it.
+type lockTable struct {
+	mutex sync.Mutex
+	locks map[string]*namedLock
+}
+
+type namedLock struct {
+	sync.RWMutex
+	refs int
+}
+
+func newLockTable() *lockTable {
+	return &lockTable{locks: make(map[string]*namedLock)}
+}
+
+// acquire returns the lock for a name, creating it if necessary, and counts
+// the caller as a user of it.
+func (t *lockTable) acquire(name string) *namedLock {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	lock, ok := t.locks[name]
+	if !ok {
+		lock = &namedLock{}
+		t.locks[name] = lock
+	}
+	lock.refs++
+	return lock
+}
+
+// release stops counting the caller as a user of a name's lock, discarding
+// the lock if it was the last one.  The caller must already have unlocked it.
+func (t *lockTable) release(name string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	lock := t.locks[name]
+	lock.refs--
+	if lock.refs == 0 {
+		delete(t.locks, name)
+	}
+}
+
+func (t *lockTable) Lock(name string) {
+	t.acquire(name).Lock()
+}
+
+func (t *lockTable) Unlock(name string) {
+	t.mutex.Lock()
+	lock := t.locks[name]
+	t.mutex.Unlock()
+	lock.Unlock()
+	t.release(name)
+}
+
+func (t *lockTable) RLock(name string) {
+	t.acquire(name).RLock()
+}
+
+func (t *lockTable) RUnlock(name string) {
+	t.mutex.Lock()
+	lock := t.locks[name]
+	t.mutex.Unlock()
+	lock.RUnlock()
+	t.release(name)
+}
+
+// LockAll takes the exclusive locks for several names and returns a function
+// that releases them.  The locks are always taken in the same order, so two
+// callers locking overlapping sets of names can't deadlock.
+func (t *lockTable) LockAll(names ...string) func() {
+	unique := make(map[string]bool)
+	for _, name := range names {
+		if name != "" {
+			unique[name] = true
+		}
+	}
+	sorted := make([]string, 0, len(unique))
+	for name := range unique {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	for _, name := range sorted {
+		t.Lock(name)
+	}
+	return func() {
+		for i := len(sorted) - 1; i >= 0; i-- {
+			t.Unlock(sorted[i])
+		}
+	}
+}
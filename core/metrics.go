@@ -0,0 +1,84 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/netapp/trident/metrics"
+	"github.com/netapp/trident/storage"
+)
+
+var (
+	backendOnlineDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, "", "backend_online"),
+		"Whether a backend is online (1) or offline (0).",
+		[]string{"backend", "state"}, nil,
+	)
+	poolVolumesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, "", "pool_volumes"),
+		"Number of volumes in a storage pool.",
+		[]string{"backend", "pool"}, nil,
+	)
+)
+
+// metricsCollector reports the state of an orchestrator's backends each time
+// the metrics are scraped.
+type metricsCollector struct {
+	orchestrator Orchestrator
+}
+
+// NewMetricsCollector returns a Prometheus collector that reports the online
+// state of the orchestrator's backends and the number of volumes in each of
+// their storage pools.
+func NewMetricsCollector(orchestrator Orchestrator) prometheus.Collector {
+	return &metricsCollector{orchestrator: orchestrator}
+}
+
+func (c *metricsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- backendOnlineDesc
+	ch <- poolVolumesDesc
+}
+
+func (c *metricsCollector) Collect(ch chan<- prometheus.Metric) {
+	poolVolumes := make(map[string]map[string]int)
+	for _, backend := range c.orchestrator.ListBackends() {
+		online := 0.0
+		if backend.Online {
+			online = 1.0
+		}
+		ch <- prometheus.MustNewConstMetric(backendOnlineDesc, prometheus.GaugeValue, online,
+			backend.Name, string(backend.State))
+
+		poolVolumes[backend.Name] = make(map[string]int)
+		for poolName := range backend.Storage {
+			poolVolumes[backend.Name][poolName] = 0
+		}
+	}
+	for _, volume := range c.orchestrator.ListVolumes() {
+		if pools, ok := poolVolumes[volume.Backend]; ok && volume.Pool != "" {
+			pools[volume.Pool]++
+		}
+	}
+	for backendName, pools := range poolVolumes {
+		for poolName, count := range pools {
+			ch <- prometheus.MustNewConstMetric(poolVolumesDesc, prometheus.GaugeValue, float64(count),
+				backendName, poolName)
+		}
+	}
+}
+
+// observeVolumeOperation records the outcome of a volume operation that began
+// at start.  The backend is the one the volume was placed on or, if the
+// operation failed, the last one tried; it is nil if none was tried.
+func observeVolumeOperation(
+	operation string, backend *storage.Backend, storageClass string, start time.Time, err error,
+) {
+	backendName := ""
+	if backend != nil {
+		backendName = backend.Name
+	}
+	metrics.ObserveVolumeOperation(operation, backendName, storageClass, start, err)
+}
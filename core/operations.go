@@ -0,0 +1,224 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/persistent_store"
+	"github.com/netapp/trident/storage"
+)
+
+// operationTimeFormat is the format of an operation's timestamps, which are
+// in UTC.
+const operationTimeFormat = time.RFC3339
+
+// AddVolumeAsync starts creating a volume in the background and returns the
+// operation through which its outcome may be followed.
+func (o *TridentOrchestrator) AddVolumeAsync(volumeConfig *storage.VolumeConfig) (
+	*persistentstore.Operation, error,
+) {
+	if o.GetVolume(volumeConfig.Name) != nil {
+		return nil, fmt.Errorf("volume %s already exists", volumeConfig.Name)
+	}
+	return o.startOperation(persistentstore.AddVolumeOperation, volumeConfig.Name,
+		func(report func(string)) error {
+			_, err := o.addVolume(volumeConfig, report)
+			return err
+		})
+}
+
+// ImportVolumeAsync starts importing a volume in the background and returns
+// the operation through which its outcome may be followed.
+func (o *TridentOrchestrator) ImportVolumeAsync(
+	backendName, internalName string, volumeConfig *storage.VolumeConfig, rename bool,
+) (*persistentstore.Operation, error) {
+	if o.GetVolume(volumeConfig.Name) != nil {
+		return nil, fmt.Errorf("volume %s already exists", volumeConfig.Name)
+	}
+	return o.startOperation(persistentstore.ImportVolumeOperation, volumeConfig.Name,
+		func(report func(string)) error {
+			_, err := o.importVolume(backendName, internalName, volumeConfig, rename, report)
+			return err
+		})
+}
+
+// startOperation records a new operation on the target and runs it in the
+// background.  The operation's progress and outcome are saved to the
+// persistent store as they change, so that they survive a restart.
+func (o *TridentOrchestrator) startOperation(
+	opType persistentstore.OperationType, target string, run func(report func(string)) error,
+) (*persistentstore.Operation, error) {
+	o.pruneOperations()
+
+	now := time.Now().UTC().Format(operationTimeFormat)
+	op := &persistentstore.Operation{
+		ID:      uuid.New(),
+		Type:    opType,
+		Target:  target,
+		State:   persistentstore.OperationRunning,
+		Created: now,
+		Updated: now,
+	}
+	if err := o.storeClient.AddOperation(op); err != nil {
+		return nil, fmt.Errorf("unable to record operation: %v", err)
+	}
+	o.mutex.Lock()
+	o.operations[op.ID] = op
+	started := *op
+	o.mutex.Unlock()
+
+	log.WithFields(log.Fields{
+		"operation": op.ID,
+		"type":      op.Type,
+		"target":    op.Target,
+	}).Info("Started operation.")
+
+	go func() {
+		err := run(func(progress string) {
+			o.updateOperation(op.ID, func(op *persistentstore.Operation) {
+				op.Progress = progress
+			})
+		})
+		o.updateOperation(op.ID, func(op *persistentstore.Operation) {
+			if err != nil {
+				op.State = persistentstore.OperationFailed
+				op.Error = err.Error()
+			} else {
+				op.State = persistentstore.OperationSucceeded
+			}
+		})
+
+		log.WithFields(log.Fields{
+			"operation": op.ID,
+			"type":      op.Type,
+			"target":    op.Target,
+			"error":     err,
+		}).Info("Finished operation.")
+	}()
+
+	return &started, nil
+}
+
+// updateOperation applies a change to an operation and saves it to the
+// persistent store.  Only the goroutine running an operation updates it, so
+// the operation can't change between being copied and being saved.
+func (o *TridentOrchestrator) updateOperation(id string, update func(op *persistentstore.Operation)) {
+	o.mutex.Lock()
+	op, ok := o.operations[id]
+	if !ok {
+		o.mutex.Unlock()
+		return
+	}
+	update(op)
+	op.Updated = time.Now().UTC().Format(operationTimeFormat)
+	opCopy := *op
+	o.mutex.Unlock()
+
+	if err := o.storeClient.UpdateOperation(&opCopy); err != nil {
+		log.WithField("operation", id).Warnf("Unable to update operation: %v", err)
+	}
+}
+
+// pruneOperations forgets operations that finished longer ago than the
+// retention period.
+func (o *TridentOrchestrator) pruneOperations() {
+	cutoff := time.Now().Add(-config.OperationRetentionPeriod)
+
+	o.mutex.Lock()
+	expired := make([]*persistentstore.Operation, 0)
+	for id, op := range o.operations {
+		if op.Done() && operationUpdatedBefore(op, cutoff) {
+			expired = append(expired, op)
+			delete(o.operations, id)
+		}
+	}
+	o.mutex.Unlock()
+
+	for _, op := range expired {
+		if err := o.storeClient.DeleteOperation(op); err != nil && !persistentstore.MatchKeyNotFoundErr(err) {
+			log.WithField("operation", op.ID).Warnf("Unable to delete operation: %v", err)
+		}
+	}
+}
+
+func operationUpdatedBefore(op *persistentstore.Operation, cutoff time.Time) bool {
+	updated, err := time.Parse(operationTimeFormat, op.Updated)
+	return err != nil || updated.Before(cutoff)
+}
+
+// bootstrapOperations restores the operations recorded in the persistent
+// store.  Any that were running when Trident stopped can't be resumed, so
+// they are marked as failed; their volume transactions have already been
+// rolled back.
+func (o *TridentOrchestrator) bootstrapOperations() error {
+	ops, err := o.storeClient.GetOperations()
+	if err != nil {
+		return err
+	}
+	for _, op := range ops {
+		if !op.Done() {
+			op.State = persistentstore.OperationFailed
+			op.Error = "the operation was interrupted by a restart of " + config.OrchestratorName
+			op.Updated = time.Now().UTC().Format(operationTimeFormat)
+			if err = o.storeClient.UpdateOperation(op); err != nil {
+				return fmt.Errorf("failed to update operation %s: %v", op.ID, err)
+			}
+			log.WithFields(log.Fields{
+				"operation": op.ID,
+				"type":      op.Type,
+				"target":    op.Target,
+				"handler":   "Bootstrap",
+			}).Warn("Marked interrupted operation as failed.")
+		}
+		o.operations[op.ID] = op
+	}
+	o.pruneOperations()
+	return nil
+}
+
+// GetOperation returns an operation, or nil if it doesn't exist.
+func (o *TridentOrchestrator) GetOperation(id string) *persistentstore.Operation {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	operations := o.operations
+	if o.storeCache != nil {
+		operations = o.storeCache.operations
+	}
+	op, ok := operations[id]
+	if !ok {
+		return nil
+	}
+	opCopy := *op
+	return &opCopy
+}
+
+// ListOperations returns all operations, oldest first.
+func (o *TridentOrchestrator) ListOperations() []*persistentstore.Operation {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	operations := o.operations
+	if o.storeCache != nil {
+		operations = o.storeCache.operations
+	}
+	ops := make([]*persistentstore.Operation, 0, len(operations))
+	for _, op := range operations {
+		opCopy := *op
+		ops = append(ops, &opCopy)
+	}
+	sort.Slice(ops, func(i, j int) bool {
+		if ops[i].Created != ops[j].Created {
+			return ops[i].Created < ops[j].Created
+		}
+		return ops[i].ID < ops[j].ID
+	})
+	return ops
+}
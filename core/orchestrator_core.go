@@ -4,8 +4,9 @@ package core
 
 import (
 	"fmt"
-	"math/rand"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -14,22 +15,50 @@ import (
 
 	"github.com/netapp/trident/config"
 	"github.com/netapp/trident/frontend"
+	"github.com/netapp/trident/metrics"
 	"github.com/netapp/trident/persistent_store"
 	"github.com/netapp/trident/storage"
 	"github.com/netapp/trident/storage/factory"
+	"github.com/netapp/trident/storage_attribute"
 	"github.com/netapp/trident/storage_class"
 	drivers "github.com/netapp/trident/storage_drivers"
 	"github.com/netapp/trident/utils"
 )
 
+// TridentOrchestrator may be used concurrently.  Its maps, and the state of
+// the objects in them, are guarded by mutex, which is only held briefly and
+// not while waiting on the storage drivers.  An operation on a volume holds
+// that volume's lock, so that operations on the same volume are serialized,
+// and a shared lock on the volume's backend while it calls the driver, so
+// that the backend isn't updated or deleted underneath it.  Operations that
+// replace or delete a backend hold its lock exclusively.  Locks are always
+// taken in the order volume, backend, mutex.
 type TridentOrchestrator struct {
 	backends       map[string]*storage.Backend
 	volumes        map[string]*storage.Volume
+	snapshots      map[string]*storage.SnapshotPersistent
+	attachments    map[string]map[string]bool // volume name -> mount points
 	frontends      map[string]frontend.Plugin
-	mutex          *sync.Mutex
+	mutex          *sync.RWMutex
+	volumeLocks    *lockTable
+	backendLocks   *lockTable
 	storageClasses map[string]*storageclass.StorageClass
+	operations     map[string]*persistentstore.Operation
 	storeClient    persistentstore.Client
 	bootstrapped   bool
+	storeCache     *storeCache // set while following the persistent store
+	stopFollowing  chan struct{}
+	stopPeriodic   chan struct{} // closed when the orchestrator is stopped
+
+	reconcileInterval time.Duration
+	reconcileRepair   bool
+	reconcileMutex    sync.Mutex // serializes reconciliations
+	reconcileReport   *storage.ReconcileReport
+
+	orphanInterval time.Duration
+	orphanMutex    sync.Mutex                                    // serializes orphan collections
+	orphans        map[string]map[string]*storage.OrphanedVolume // backend -> internal name -> orphan
+	orphanAudit    []storage.OrphanAuditRecord
 }
 
 // NewTridentOrchestrator returns a storage orchestrator instance
@@ -37,11 +66,90 @@ func NewTridentOrchestrator(client persistentstore.Client) *TridentOrchestrator
 	return &TridentOrchestrator{
 		backends:       make(map[string]*storage.Backend),
 		volumes:        make(map[string]*storage.Volume),
+		snapshots:      make(map[string]*storage.SnapshotPersistent),
+		attachments:    make(map[string]map[string]bool),
 		frontends:      make(map[string]frontend.Plugin),
 		storageClasses: make(map[string]*storageclass.StorageClass),
-		mutex:          &sync.Mutex{},
+		operations:     make(map[string]*persistentstore.Operation),
+		mutex:          &sync.RWMutex{},
+		volumeLocks:    newLockTable(),
+		backendLocks:   newLockTable(),
 		storeClient:    client,
 		bootstrapped:   false,
+		stopPeriodic:   make(chan struct{}),
+
+		reconcileInterval: config.ReconcileInterval,
+
+		orphanInterval: config.OrphanCollectionInterval,
+		orphans:        make(map[string]map[string]*storage.OrphanedVolume),
+		orphanAudit:    make([]storage.OrphanAuditRecord, 0),
+	}
+}
+
+// lockBackend takes a shared lock on a backend, which keeps the backend from
+// being updated or deleted, and returns the backend along with a function
+// that releases the lock.  The backend is nil if it doesn't exist, but the
+// lock must be released regardless.
+func (o *TridentOrchestrator) lockBackend(backendName string) (*storage.Backend, func()) {
+	o.backendLocks.RLock(backendName)
+	o.mutex.RLock()
+	backend := o.backends[backendName]
+	o.mutex.RUnlock()
+	return backend, func() { o.backendLocks.RUnlock(backendName) }
+}
+
+// getBackendNames returns the names of the backends that currently exist.
+func (o *TridentOrchestrator) getBackendNames() []string {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	names := make([]string, 0, len(o.backends))
+	for name := range o.backends {
+		names = append(names, name)
+	}
+	return names
+}
+
+// getVolume returns a volume, or nil if it doesn't exist.  A caller holding
+// the volume's lock may go on to use the volume without holding the mutex,
+// since only operations holding that lock add, remove or resize it.
+func (o *TridentOrchestrator) getVolume(volumeName string) *storage.Volume {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	return o.volumes[volumeName]
+}
+
+// getAttachments returns the mount points at which a volume is attached.
+func (o *TridentOrchestrator) getAttachments(volumeName string) []string {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	mountpoints := make([]string, 0, len(o.attachments[volumeName]))
+	for mountpoint := range o.attachments[volumeName] {
+		mountpoints = append(mountpoints, mountpoint)
+	}
+	sort.Strings(mountpoints)
+	return mountpoints
+}
+
+func (o *TridentOrchestrator) addAttachment(volumeName, mountpoint string) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if _, ok := o.attachments[volumeName]; !ok {
+		o.attachments[volumeName] = make(map[string]bool)
+	}
+	o.attachments[volumeName][mountpoint] = true
+}
+
+func (o *TridentOrchestrator) removeAttachment(volumeName, mountpoint string) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	delete(o.attachments[volumeName], mountpoint)
+	if len(o.attachments[volumeName]) == 0 {
+		delete(o.attachments, volumeName)
 	}
 }
 
@@ -96,6 +204,9 @@ func (o *TridentOrchestrator) Bootstrap() error {
 	} else if dockerFrontend, found := o.frontends["docker"]; found {
 		config.OrchestratorTelemetry.Platform = dockerFrontend.GetName()
 		config.OrchestratorTelemetry.PlatformVersion = dockerFrontend.Version()
+	} else if csiFrontend, found := o.frontends["csi"]; found {
+		config.OrchestratorTelemetry.Platform = csiFrontend.GetName()
+		config.OrchestratorTelemetry.PlatformVersion = csiFrontend.Version()
 	}
 
 	// Transform persistent state, if necessary
@@ -110,10 +221,68 @@ func (o *TridentOrchestrator) Bootstrap() error {
 		return fmt.Errorf(errMsg)
 	}
 	o.bootstrapped = true
+	o.stopFollowingStore()
 	log.Infof("%s bootstrapped successfully.", config.OrchestratorName)
+
+	go o.periodicallyRefreshPoolCapacity(o.stopPeriodic)
+	if o.reconcileInterval > 0 {
+		go o.periodicallyReconcile(o.stopPeriodic)
+	}
+	if o.orphanInterval > 0 && !config.UsingPassthroughStore {
+		go o.periodicallyCollectOrphans(o.stopPeriodic)
+	}
+
 	return err
 }
 
+// Stop stops the work the orchestrator does periodically once it is
+// bootstrapped.  It may be called more than once.
+func (o *TridentOrchestrator) Stop() {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	select {
+	case <-o.stopPeriodic:
+	default:
+		close(o.stopPeriodic)
+	}
+}
+
+// periodicallyRefreshPoolCapacity keeps the capacity of the storage pools,
+// which steers volume placement, reasonably current.
+func (o *TridentOrchestrator) periodicallyRefreshPoolCapacity(stop <-chan struct{}) {
+	ticker := time.NewTicker(config.PoolCapacityRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			o.refreshPoolCapacity()
+		}
+	}
+}
+
+func (o *TridentOrchestrator) refreshPoolCapacity() {
+	for _, backendName := range o.getBackendNames() {
+		backend, unlock := o.lockBackend(backendName)
+		if backend != nil && backend.Online {
+			capacities, err := backend.GetPoolCapacities()
+			if err != nil {
+				log.WithFields(log.Fields{
+					"backend": backendName,
+				}).Warnf("Unable to refresh storage pool capacity: %v", err)
+			} else {
+				o.mutex.Lock()
+				backend.SetPoolCapacities(capacities)
+				o.mutex.Unlock()
+			}
+		}
+		unlock()
+	}
+}
+
 func (o *TridentOrchestrator) bootstrapBackends() error {
 	persistentBackends, err := o.storeClient.GetBackends()
 	if err != nil {
@@ -135,6 +304,16 @@ func (o *TridentOrchestrator) bootstrapBackends() error {
 		// added backend, so we have to go fetch it manually.
 		newBackend := o.backends[newBackendExternal.Name]
 		newBackend.Online = b.Online
+		newBackend.State = b.State
+		if newBackend.State == "" {
+			// Records written by earlier versions lack a state, but offline
+			// backends were only ever being deleted.
+			if b.Online {
+				newBackend.State = storage.BackendStateOnline
+			} else {
+				newBackend.State = storage.BackendStateDeleting
+			}
+		}
 		log.WithFields(log.Fields{
 			"backend": newBackend.Name,
 			"handler": "Bootstrap",
@@ -193,15 +372,44 @@ func (o *TridentOrchestrator) bootstrapVolumes() error {
 	return nil
 }
 
+func (o *TridentOrchestrator) bootstrapSnapshots() error {
+	snapshots, err := o.storeClient.GetSnapshots()
+	if err != nil {
+		return err
+	}
+	for _, s := range snapshots {
+		if _, ok := o.volumes[s.Volume]; !ok {
+			// Snapshot records are removed before their volume, so this
+			// should only happen if the store was modified out of band.
+			log.WithFields(log.Fields{
+				"snapshot": s.Name,
+				"volume":   s.Volume,
+				"handler":  "Bootstrap",
+			}).Warn("Couldn't find volume for snapshot; deleting snapshot record.")
+			if err = o.storeClient.DeleteSnapshotIgnoreNotFound(s); err != nil {
+				return fmt.Errorf("failed to delete snapshot record %s: %v", s.ID(), err)
+			}
+			continue
+		}
+		o.snapshots[s.ID()] = s
+
+		log.WithFields(log.Fields{
+			"snapshot": s.Name,
+			"volume":   s.Volume,
+			"created":  s.Created,
+			"handler":  "Bootstrap",
+		}).Info("Added an existing snapshot.")
+	}
+	return nil
+}
+
 func (o *TridentOrchestrator) bootstrapVolTxns() error {
 	volTxns, err := o.storeClient.GetVolumeTransactions()
 	if err != nil {
 		log.Warnf("Couldn't retrieve volume transaction logs: %s", err.Error())
 	}
 	for _, v := range volTxns {
-		o.mutex.Lock()
 		err = o.rollBackTransaction(v)
-		o.mutex.Unlock()
 		if err != nil {
 			return err
 		}
@@ -214,7 +422,8 @@ func (o *TridentOrchestrator) bootstrap() error {
 
 	type bootstrapFunc func() error
 	for _, f := range []bootstrapFunc{o.bootstrapBackends,
-		o.bootstrapStorageClasses, o.bootstrapVolumes, o.bootstrapVolTxns} {
+		o.bootstrapStorageClasses, o.bootstrapVolumes, o.bootstrapSnapshots,
+		o.bootstrapVolTxns, o.bootstrapOperations} {
 		err := f()
 		if err != nil {
 			if persistentstore.MatchKeyNotFoundErr(err) {
@@ -228,15 +437,15 @@ func (o *TridentOrchestrator) bootstrap() error {
 		}
 	}
 
-	// Clean up any offline backends that lack volumes.  This can happen if
+	// Clean up any deleting backends that lack volumes.  This can happen if
 	// a connection to etcd fails when attempting to delete a backend.
 	for backendName, backend := range o.backends {
-		if !backend.Online && !backend.HasVolumes() {
+		if backend.State == storage.BackendStateDeleting && !backend.HasVolumes() {
 			backend.Terminate()
 			delete(o.backends, backendName)
 			err := o.storeClient.DeleteBackend(backend)
 			if err != nil {
-				return fmt.Errorf("failed to delete empty offline backend %s:"+
+				return fmt.Errorf("failed to delete empty deleting backend %s: "+
 					"%v", backendName, err)
 			}
 		}
@@ -245,6 +454,9 @@ func (o *TridentOrchestrator) bootstrap() error {
 	return nil
 }
 
+// rollBackTransaction cleans up after an interrupted volume operation.  The
+// caller must hold the lock of the transaction's volume, but none of the
+// backend locks, unless Trident is bootstrapping.
 func (o *TridentOrchestrator) rollBackTransaction(v *persistentstore.VolumeTransaction) error {
 	log.WithFields(log.Fields{
 		"volume":       v.Config.Name,
@@ -259,10 +471,12 @@ func (o *TridentOrchestrator) rollBackTransaction(v *persistentstore.VolumeTrans
 		// 1) Volume transaction created only
 		// 2) Volume created on backend
 		// 3) Volume created in etcd.
-		if _, ok := o.volumes[v.Config.Name]; ok {
+		backendName := ""
+		if volume := o.getVolume(v.Config.Name); volume != nil {
 			// If the volume was added to etcd, we will have loaded the
 			// volume into memory, and we can just delete it normally.
 			// Handles case 3)
+			backendName = volume.Backend
 			err := o.deleteVolume(v.Config.Name)
 			if err != nil {
 				return fmt.Errorf("unable to clean up volume %s: %v", v.Config.Name, err)
@@ -274,21 +488,23 @@ func (o *TridentOrchestrator) rollBackTransaction(v *persistentstore.VolumeTrans
 			// unique across backends, thanks to the StoragePrefix field,
 			// so this should be idempotent.
 			// Handles case 2)
-			for _, backend := range o.backends {
-				if !backend.Online {
+			for _, backendName := range o.getBackendNames() {
+				backend, unlock := o.lockBackend(backendName)
+				if backend == nil || !backend.Online {
 					// Backend offlining is serialized with volume creation,
 					// so we can safely skip offline backends.
+					unlock()
 					continue
 				}
 				// TODO:  Change this to check the error type when backends
 				// return a standardized error when a volume is not found.
 				// For now, though, fail on an error, since backends currently
 				// do not report errors for volumes not present.
-				if err := backend.Driver.Destroy(
-					backend.Driver.GetInternalVolumeName(v.Config.Name),
-				); err != nil {
+				err := backend.Driver.Destroy(backend.Driver.GetInternalVolumeName(v.Config.Name))
+				unlock()
+				if err != nil {
 					return fmt.Errorf("error attempting to clean up volume %s from backend %s: %v", v.Config.Name,
-						backend.Name, err)
+						backendName, err)
 				}
 			}
 		}
@@ -297,17 +513,24 @@ func (o *TridentOrchestrator) rollBackTransaction(v *persistentstore.VolumeTrans
 		if err := o.storeClient.DeleteVolumeTransaction(v); err != nil {
 			return fmt.Errorf("failed to clean up volume addition transaction: %v", err)
 		}
+		if backendName != "" {
+			if err := o.deleteEmptyBackend(backendName); err != nil {
+				return fmt.Errorf("failed to delete empty deleting backend %s: %v", backendName, err)
+			}
+		}
 	case persistentstore.DeleteVolume:
 		// Because we remove the volume from etcd after we remove it from
 		// the backend, we only need to take any special measures if
 		// the volume is still in etcd.  In this case, it will have been
 		// loaded into memory when previously bootstrapping.
-		if _, ok := o.volumes[v.Config.Name]; ok {
+		backendName := ""
+		if volume := o.getVolume(v.Config.Name); volume != nil {
 			// Ignore errors, since the volume may no longer exist on the
 			// backend
 			log.WithFields(log.Fields{
 				"name": v.Config.Name,
 			}).Info("Volume for delete transaction found.")
+			backendName = volume.Backend
 			err := o.deleteVolume(v.Config.Name)
 			if err != nil {
 				return fmt.Errorf("unable to clean up deleted volume %s: %v", v.Config.Name, err)
@@ -320,6 +543,54 @@ func (o *TridentOrchestrator) rollBackTransaction(v *persistentstore.VolumeTrans
 		if err := o.storeClient.DeleteVolumeTransaction(v); err != nil {
 			return fmt.Errorf("failed to clean up volume deletion transaction: %v", err)
 		}
+		if backendName != "" {
+			if err := o.deleteEmptyBackend(backendName); err != nil {
+				return fmt.Errorf("failed to delete empty deleting backend %s: %v", backendName, err)
+			}
+		}
+	case persistentstore.ResizeVolume:
+		// A resize cannot be undone, so we attempt to finish it instead.
+		// The transaction holds the requested size, and the drivers treat a
+		// resize to the current size as a no-op, so this is safe regardless
+		// of how far the original operation got.  If the resize fails again,
+		// the volume keeps its recorded size and the user may retry.
+		if volume := o.getVolume(v.Config.Name); volume != nil {
+			if _, err := o.resizeVolume(volume, v.Config.Size); err != nil {
+				log.WithFields(log.Fields{
+					"name": v.Config.Name,
+					"size": v.Config.Size,
+				}).Warnf("Unable to complete volume resize: %v", err)
+			}
+		} else {
+			log.WithFields(log.Fields{
+				"name": v.Config.Name,
+			}).Info("Volume for resize transaction not found.")
+		}
+		if err := o.storeClient.DeleteVolumeTransaction(v); err != nil {
+			return fmt.Errorf("failed to clean up volume resize transaction: %v", err)
+		}
+	case persistentstore.ImportVolume:
+		// Import never destroys data.  If the volume made it into the store,
+		// the import completed; otherwise, the volume remains on the backend
+		// and the user may import it again.  A volume that was renamed is
+		// given its original name back first, as it would otherwise look like
+		// a volume Trident created and lost, which the orphan collector may
+		// delete.  Until that succeeds, the transaction is kept, which keeps
+		// the volume from being collected.
+		if o.getVolume(v.Config.Name) == nil {
+			if err := o.restoreImportedVolumeNames(v.Config); err != nil {
+				return fmt.Errorf("unable to restore the original name of volume %s: %v",
+					v.Config.ImportOriginalName, err)
+			}
+			log.WithFields(log.Fields{
+				"name":         v.Config.Name,
+				"originalName": v.Config.ImportOriginalName,
+				"internalName": v.Config.InternalName,
+			}).Warn("Volume import did not complete; the volume was left on the backend.")
+		}
+		if err := o.storeClient.DeleteVolumeTransaction(v); err != nil {
+			return fmt.Errorf("failed to clean up volume import transaction: %v", err)
+		}
 	}
 	return nil
 }
@@ -356,17 +627,24 @@ func (o *TridentOrchestrator) GetVersion() string {
 
 func (o *TridentOrchestrator) AddStorageBackend(configJSON string) (
 	*storage.BackendExternal, error) {
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
 
 	storageBackend, err := factory.NewStorageBackendForConfig(configJSON)
 	if err != nil {
 		return nil, err
 	}
+
+	o.backendLocks.Lock(storageBackend.Name)
+	defer o.backendLocks.Unlock(storageBackend.Name)
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
 	newBackend := true
 	originalBackend, ok := o.backends[storageBackend.Name]
 	if ok {
 		newBackend = false
+		if originalBackend.State == storage.BackendStateDeleting {
+			return nil, fmt.Errorf("backend %s is being deleted", storageBackend.Name)
+		}
 		if err = o.validateBackendUpdate(originalBackend, storageBackend); err != nil {
 			return nil, err
 		}
@@ -393,6 +671,9 @@ func (o *TridentOrchestrator) AddStorageBackend(configJSON string) (
 	// such volumes are likely to fail, so here we just warn the users about
 	// such volumes and mark them as orphaned.
 	for volName, vol := range o.volumes {
+		if vol.Backend != storageBackend.Name {
+			continue
+		}
 		updatePersistentStore := false
 		volExternal, _ := storageBackend.Driver.GetVolumeExternal(vol.Config.InternalName)
 		if volExternal == nil {
@@ -445,9 +726,150 @@ func (o *TridentOrchestrator) AddStorageBackend(configJSON string) (
 	return storageBackend.ConstructExternal(), nil
 }
 
-func (o *TridentOrchestrator) GetBackend(backend string) *storage.BackendExternal {
+// UpdateBackend replaces the configuration of an existing backend.  The new
+// configuration must resolve to the same backend name and storage driver.
+// Volumes that the updated backend can no longer find are marked as orphaned.
+// If dryRun is set, the effects of the update are reported but not applied.
+func (o *TridentOrchestrator) UpdateBackend(
+	backendName, configJSON string, dryRun bool,
+) (*storage.BackendUpdateExternal, error) {
+	// Holding the backend's lock keeps its volumes from changing while the
+	// new configuration is evaluated, so the mutex is only needed once the
+	// update is applied.
+	o.backendLocks.Lock(backendName)
+	defer o.backendLocks.Unlock(backendName)
+
+	o.mutex.RLock()
+	originalBackend, found := o.backends[backendName]
+	o.mutex.RUnlock()
+	if !found {
+		return nil, fmt.Errorf("backend %s not found", backendName)
+	}
+	if originalBackend.State == storage.BackendStateDeleting {
+		return nil, fmt.Errorf("backend %s is being deleted", backendName)
+	}
+	storageBackend, err := factory.NewStorageBackendForConfig(configJSON)
+	if err != nil {
+		return nil, err
+	}
+	applied := false
+	defer func() {
+		if !applied {
+			storageBackend.Terminate()
+		}
+	}()
+
+	if storageBackend.Name != backendName {
+		return nil, fmt.Errorf("cannot update backend %s with the configuration of backend %s",
+			backendName, storageBackend.Name)
+	}
+	if err = o.validateBackendUpdate(originalBackend, storageBackend); err != nil {
+		return nil, err
+	}
+
+	changes, err := storage.ChangedConfigFields(&originalBackend.ConstructPersistent().Config,
+		&storageBackend.ConstructPersistent().Config)
+	if err != nil {
+		return nil, fmt.Errorf("unable to compare backend configurations: %v", err)
+	}
+	update := &storage.BackendUpdateExternal{
+		ConfigChanges:   changes,
+		StorageClasses:  make([]string, 0),
+		OrphanedVolumes: make([]string, 0),
+		DryRun:          dryRun,
+	}
+
+	// Identify the volumes that would be orphaned by the new configuration
+	orphaned := make(map[string]bool)
+	for volName, vol := range originalBackend.Volumes {
+		volExternal, _ := storageBackend.Driver.GetVolumeExternal(vol.Config.InternalName)
+		if volExternal == nil {
+			orphaned[volName] = true
+			update.OrphanedVolumes = append(update.OrphanedVolumes, volName)
+		}
+	}
+	sort.Strings(update.OrphanedVolumes)
+
+	if dryRun {
+		// Match the new backend against copies of the storage classes so
+		// that the existing classes are left alone.
+		o.mutex.RLock()
+		for _, sc := range o.storageClasses {
+			scCopy := storageclass.NewFromPersistent(sc.ConstructPersistent())
+			if added := scCopy.CheckAndAddBackend(storageBackend); added > 0 {
+				update.StorageClasses = append(update.StorageClasses, sc.GetName())
+			}
+		}
+		o.mutex.RUnlock()
+		sort.Strings(update.StorageClasses)
+		update.Backend = storageBackend.ConstructExternal()
+		log.WithFields(log.Fields{
+			"backend":         backendName,
+			"configChanges":   strings.Join(update.ConfigChanges, ","),
+			"orphanedVolumes": strings.Join(update.OrphanedVolumes, ","),
+		}).Info("Evaluated backend update without applying it.")
+		return update, nil
+	}
+
+	if err = o.updateBackendOnPersistentStore(storageBackend, false); err != nil {
+		return nil, err
+	}
+	applied = true
+
 	o.mutex.Lock()
 	defer o.mutex.Unlock()
+
+	originalBackend.Terminate()
+	o.backends[backendName] = storageBackend
+
+	for volName, vol := range originalBackend.Volumes {
+		if vol.Orphaned != orphaned[volName] {
+			vol.Orphaned = orphaned[volName]
+			if vol.Orphaned {
+				log.WithFields(log.Fields{
+					"volume":  volName,
+					"backend": backendName,
+				}).Warn("Backend update resulted in an orphaned volume!")
+			} else {
+				log.WithFields(log.Fields{
+					"volume":  volName,
+					"backend": backendName,
+				}).Info("The volume is no longer orphaned as a result of the " +
+					"backend update.")
+			}
+			if err = o.updateVolumeOnPersistentStore(vol); err != nil {
+				log.WithFields(log.Fields{
+					"volume":  volName,
+					"backend": backendName,
+				}).Errorf("Unable to update the volume in the backing store: %v", err)
+			}
+		}
+		storageBackend.Volumes[volName] = vol
+	}
+
+	for _, sc := range o.storageClasses {
+		sc.RemovePoolsForBackend(originalBackend)
+		if added := sc.CheckAndAddBackend(storageBackend); added > 0 {
+			update.StorageClasses = append(update.StorageClasses, sc.GetName())
+		}
+	}
+	sort.Strings(update.StorageClasses)
+	log.WithFields(log.Fields{
+		"backend":        backendName,
+		"configChanges":  strings.Join(update.ConfigChanges, ","),
+		"storageClasses": strings.Join(update.StorageClasses, ","),
+	}).Info("Updated backend.")
+
+	update.Backend = storageBackend.ConstructExternal()
+	return update, nil
+}
+
+func (o *TridentOrchestrator) GetBackend(backend string) *storage.BackendExternal {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+	if o.storeCache != nil {
+		return o.storeCache.backends[backend]
+	}
 	var storageBackend *storage.Backend
 	var found bool
 	if storageBackend, found = o.backends[backend]; !found {
@@ -457,18 +879,30 @@ func (o *TridentOrchestrator) GetBackend(backend string) *storage.BackendExterna
 }
 
 func (o *TridentOrchestrator) ListBackends() []*storage.BackendExternal {
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
-	backends := make([]*storage.BackendExternal, 0)
-	for _, b := range o.backends {
-		if b.Online {
-			backends = append(backends, b.ConstructExternal())
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+	if o.storeCache != nil {
+		backends := make([]*storage.BackendExternal, 0, len(o.storeCache.backends))
+		for _, b := range o.storeCache.backends {
+			backends = append(backends, b)
 		}
+		return backends
+	}
+	backends := make([]*storage.BackendExternal, 0, len(o.backends))
+	for _, b := range o.backends {
+		backends = append(backends, b.ConstructExternal())
 	}
 	return backends
 }
 
-func (o *TridentOrchestrator) OfflineBackend(backendName string) (bool, error) {
+// DeleteBackend removes a backend from Trident.  A backend without volumes is
+// removed from memory and the backing store right away.  Otherwise, the
+// backend is taken offline and left in the deleting state so that no new
+// volumes are placed on it, and DeleteVolume finishes removing it once its
+// last volume is gone.
+func (o *TridentOrchestrator) DeleteBackend(backendName string) (bool, error) {
+	o.backendLocks.Lock(backendName)
+	defer o.backendLocks.Unlock(backendName)
 	o.mutex.Lock()
 	defer o.mutex.Unlock()
 
@@ -477,6 +911,7 @@ func (o *TridentOrchestrator) OfflineBackend(backendName string) (bool, error) {
 		return false, fmt.Errorf("backend %s not found", backendName)
 	}
 	backend.Online = false
+	backend.State = storage.BackendStateDeleting
 	storageClasses := make(map[string]*storageclass.StorageClass, 0)
 	for _, storagePool := range backend.Storage {
 		for _, scName := range storagePool.StorageClasses {
@@ -488,40 +923,50 @@ func (o *TridentOrchestrator) OfflineBackend(backendName string) (bool, error) {
 		sc.RemovePoolsForBackend(backend)
 	}
 	if !backend.HasVolumes() {
+		log.WithField("backend", backendName).Info("Deleting empty backend.")
 		backend.Terminate()
 		delete(o.backends, backendName)
 		return true, o.storeClient.DeleteBackend(backend)
 	}
+	log.WithFields(log.Fields{
+		"backend": backendName,
+		"volumes": len(backend.Volumes),
+	}).Info("Backend still has volumes; it will be deleted along with its " +
+		"last volume.")
 	return true, o.storeClient.UpdateBackend(backend)
 }
 
-func (o *TridentOrchestrator) AddVolume(volumeConfig *storage.VolumeConfig) (
+func (o *TridentOrchestrator) AddVolume(volumeConfig *storage.VolumeConfig) (*storage.VolumeExternal, error) {
+	return o.addVolume(volumeConfig, func(string) {})
+}
+
+// addVolume creates a volume, reporting each storage pool it tries to the
+// supplied function.
+func (o *TridentOrchestrator) addVolume(volumeConfig *storage.VolumeConfig, report func(progress string)) (
 	externalVol *storage.VolumeExternal, err error) {
 	var (
 		backend *storage.Backend
 		vol     *storage.Volume
 	)
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	defer o.volumeLocks.LockAll(volumeConfig.Name)()
+	start := time.Now()
+	defer func() { observeVolumeOperation("add", backend, volumeConfig.StorageClass, start, err) }()
 
-	if _, ok := o.volumes[volumeConfig.Name]; ok {
-		return nil, fmt.Errorf("volume %s already exists", volumeConfig.Name)
-	}
 	volumeConfig.Version = config.OrchestratorAPIVersion
 
-	sc, ok := o.storageClasses[volumeConfig.StorageClass]
-	if !ok {
-		return nil, fmt.Errorf("unknown storage class: %s",
-			volumeConfig.StorageClass)
+	// The size is only used to steer placement, so a size that can't be
+	// parsed here is left for the backend to reject.
+	var sizeBytes uint64
+	if size, err := utils.ConvertSizeToBytes(volumeConfig.Size); err == nil {
+		sizeBytes, _ = strconv.ParseUint(size, 10, 64)
 	}
-	pools := sc.GetStoragePoolsForProtocol(volumeConfig.Protocol)
-	if len(pools) == 0 {
-		return nil, fmt.Errorf("no available backends for storage class %s",
-			volumeConfig.StorageClass)
+	pools, attributes, err := o.getStoragePoolsForVolume(volumeConfig, sizeBytes)
+	if err != nil {
+		return nil, err
 	}
 
 	// Add transaction in case the operation must be rolled back later
-	volTxn, err := o.addVolumeTransaction(volumeConfig)
+	volTxn, err := o.addVolumeTransaction(volumeConfig, persistentstore.AddVolume)
 	if err != nil {
 		return nil, err
 	}
@@ -529,41 +974,39 @@ func (o *TridentOrchestrator) AddVolume(volumeConfig *storage.VolumeConfig) (
 	// Recovery function in case of error
 	defer func() { o.addVolumeCleanup(err, backend, vol, volTxn, volumeConfig) }()
 
-	// Randomize the storage pool list for better distribution of load across all pools.
-	rand.Seed(time.Now().UnixNano())
-
-	log.WithFields(log.Fields{
-		"volume": volumeConfig.Name,
-	}).Debugf("Looking through %d storage pools.", len(pools))
-
 	errorMessages := make([]string, 0)
 
-	// Choose a pool at random.
-	for _, num := range rand.Perm(len(pools)) {
-		backend = pools[num].Backend
-		vol, err = backend.AddVolume(volumeConfig, pools[num], sc.GetAttributes())
-		if vol != nil && err == nil {
-			if vol.Config.Protocol == config.ProtocolAny {
-				vol.Config.Protocol = backend.GetProtocol()
-			}
-			err = o.storeClient.AddVolume(vol)
-			if err != nil {
-				return nil, err
+	// Try the pools in the order chosen by the storage class's policy.
+	for _, pool := range pools {
+		backend = pool.Backend
+		report(fmt.Sprintf("Creating volume on storage pool %s of backend %s.", pool.Name, backend.Name))
+		vol, err = o.addVolumeToBackend(backend, func() (*storage.Volume, error) {
+			if !backend.Online {
+				return nil, fmt.Errorf("backend %s is offline", backend.Name)
 			}
-			o.volumes[volumeConfig.Name] = vol
+			return backend.AddVolume(volumeConfig, pool, attributes)
+		})
+		if vol != nil && err == nil {
+			o.mutex.Lock()
+			pool.ConsumeCapacity(sizeBytes)
+			o.mutex.Unlock()
 			externalVol = vol.ConstructExternal()
 			return externalVol, nil
+		} else if vol != nil {
+			// The volume was created but couldn't be recorded, so the
+			// cleanup function will remove it again.
+			return nil, err
 		} else if err != nil {
 			log.WithFields(log.Fields{
 				"backend": backend.Name,
-				"pool":    pools[num].Name,
+				"pool":    pool.Name,
 				"volume":  volumeConfig.Name,
 				"error":   err,
 			}).Warn("Failed to create the volume on this backend!")
 			errorMessages = append(errorMessages,
 				fmt.Sprintf("[Failed to create volume %s "+
 					"on storage pool %s from backend %s: %s]",
-					volumeConfig.Name, pools[num].Name, backend.Name,
+					volumeConfig.Name, pool.Name, backend.Name,
 					err.Error()))
 		}
 	}
@@ -581,30 +1024,109 @@ func (o *TridentOrchestrator) AddVolume(volumeConfig *storage.VolumeConfig) (
 	return nil, err
 }
 
+// getStoragePoolsForVolume checks that a new volume may be created and
+// returns the storage pools it may be placed in, in the order they should be
+// tried, along with the attributes requested by the volume's storage class.
+func (o *TridentOrchestrator) getStoragePoolsForVolume(
+	volumeConfig *storage.VolumeConfig, sizeBytes uint64,
+) ([]*storage.Pool, map[string]storageattribute.Request, error) {
+	// Choosing pools advances the round-robin state of the storage class,
+	// so this needs the exclusive lock.
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if _, ok := o.volumes[volumeConfig.Name]; ok {
+		return nil, nil, fmt.Errorf("volume %s already exists", volumeConfig.Name)
+	}
+	sc, ok := o.storageClasses[volumeConfig.StorageClass]
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown storage class: %s",
+			volumeConfig.StorageClass)
+	}
+	pools := sc.GetStoragePoolsForVolume(volumeConfig.Protocol, sizeBytes)
+	if len(pools) == 0 {
+		return nil, nil, fmt.Errorf("no available backends for storage class %s",
+			volumeConfig.StorageClass)
+	}
+
+	log.WithFields(log.Fields{
+		"volume": volumeConfig.Name,
+		"policy": sc.GetPoolSelectionPolicy(),
+	}).Debugf("Looking through %d storage pools.", len(pools))
+
+	return pools, sc.GetAttributes(), nil
+}
+
+// addVolumeToBackend calls create to make a new volume on a backend and, if
+// a volume is made, records it in the persistent store and in memory.  The
+// backend is locked throughout, and the volume isn't created if the backend
+// was updated or deleted since the caller looked it up.  If the volume was
+// created but couldn't be recorded, it is returned along with the error so
+// that the caller can remove it from the backend.
+func (o *TridentOrchestrator) addVolumeToBackend(
+	backend *storage.Backend, create func() (*storage.Volume, error),
+) (*storage.Volume, error) {
+
+	currentBackend, unlock := o.lockBackend(backend.Name)
+	defer unlock()
+	if currentBackend != backend {
+		return nil, fmt.Errorf("backend %s was updated or deleted", backend.Name)
+	}
+
+	vol, err := create()
+	if vol == nil || err != nil {
+		return nil, err
+	}
+	if vol.Config.Protocol == config.ProtocolAny {
+		vol.Config.Protocol = backend.GetProtocol()
+	}
+	if err = o.storeClient.AddVolume(vol); err != nil {
+		return vol, err
+	}
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	backend.Volumes[vol.Config.Name] = vol
+	o.volumes[vol.Config.Name] = vol
+	return vol, nil
+}
+
 func (o *TridentOrchestrator) CloneVolume(
 	volumeConfig *storage.VolumeConfig,
-) (*storage.VolumeExternal, error) {
+) (externalVol *storage.VolumeExternal, err error) {
 
 	var (
 		found   bool
 		backend *storage.Backend
 		vol     *storage.Volume
 	)
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	// The source volume is locked as well, so that it isn't deleted or
+	// changed while it is being cloned.
+	defer o.volumeLocks.LockAll(volumeConfig.Name, volumeConfig.CloneSourceVolume)()
+	start := time.Now()
+	defer func() { observeVolumeOperation("clone", backend, volumeConfig.StorageClass, start, err) }()
+
+	o.mutex.RLock()
+	_, exists := o.volumes[volumeConfig.Name]
+	sourceVolume, found := o.volumes[volumeConfig.CloneSourceVolume]
+	orphaned := found && sourceVolume.Orphaned
+	o.mutex.RUnlock()
 
-	if _, ok := o.volumes[volumeConfig.Name]; ok {
+	if exists {
 		return nil, fmt.Errorf("volume %s already exists", volumeConfig.Name)
 	}
 	volumeConfig.Version = config.OrchestratorAPIVersion
 
 	// Get the source volume
-	sourceVolume, found := o.volumes[volumeConfig.CloneSourceVolume]
 	if !found {
 		return nil, fmt.Errorf("source volume not found: %s",
 			volumeConfig.CloneSourceVolume)
 	}
-	if sourceVolume.Orphaned {
+	if err = sourceVolume.CheckNotFailedOver("clone"); err != nil {
+		return nil, err
+	}
+	if orphaned {
 		log.WithFields(log.Fields{
 			"source_volume": sourceVolume.Config.Name,
 			"volume":        volumeConfig.Name,
@@ -626,7 +1148,7 @@ func (o *TridentOrchestrator) CloneVolume(
 	cloneConfig.QoSType = volumeConfig.QoSType
 
 	// Add transaction in case the operation must be rolled back later
-	volTxn, err := o.addVolumeTransaction(volumeConfig)
+	volTxn, err := o.addVolumeTransaction(volumeConfig, persistentstore.AddVolume)
 	if err != nil {
 		return nil, err
 	}
@@ -634,7 +1156,9 @@ func (o *TridentOrchestrator) CloneVolume(
 	// Recovery function in case of error
 	defer func() { o.addVolumeCleanup(err, backend, vol, volTxn, volumeConfig) }()
 
+	o.mutex.RLock()
 	backend, found = o.backends[sourceVolume.Backend]
+	o.mutex.RUnlock()
 	if !found {
 		// Should never get here but just to be safe
 		return nil,
@@ -642,50 +1166,236 @@ func (o *TridentOrchestrator) CloneVolume(
 				volumeConfig.CloneSourceVolume)
 	}
 
-	vol, err = backend.CloneVolume(cloneConfig)
+	vol, err = o.addVolumeToBackend(backend, func() (*storage.Volume, error) {
+		return backend.CloneVolume(cloneConfig)
+	})
 	if err != nil {
+		if vol != nil {
+			// The clone was created but couldn't be recorded, so the
+			// cleanup function will remove it again.
+			return nil, err
+		}
 		return nil, fmt.Errorf("failed to create cloned volume %s on backend %s: %v", cloneConfig.Name,
 			backend.Name, err)
 	}
 
-	// Save references to new volume
-	err = o.storeClient.AddVolume(vol)
-	if err != nil {
-		return nil, err
-	}
-	o.volumes[cloneConfig.Name] = vol
-
 	return vol.ConstructExternal(), nil
 }
 
-// addVolumeTransaction is called from the volume create/clone methods to save
-// a record of the operation in case it fails and must be cleaned up later.
-func (o *TridentOrchestrator) addVolumeTransaction(
-	volumeConfig *storage.VolumeConfig,
-) (*persistentstore.VolumeTransaction, error) {
+// ImportVolume brings a volume that already exists on a backend under
+// Trident's management.  internalName is the volume's name on the backend.
+// The volume's size and protocol are discovered from the backend; the rest of
+// volumeConfig is taken as supplied.  If rename is set, the volume is renamed
+// on the backend to the name Trident would have given it, so that it is
+// indistinguishable from a volume Trident created.  Import never destroys
+// data, so a failed import leaves the volume on the backend for the user to
+// retry or clean up.
+func (o *TridentOrchestrator) ImportVolume(
+	backendName, internalName string, volumeConfig *storage.VolumeConfig, rename bool,
+) (*storage.VolumeExternal, error) {
+	return o.importVolume(backendName, internalName, volumeConfig, rename, func(string) {})
+}
 
-	// Check if an addVolume transaction already exists for this name.
-	// If so, we failed earlier and we need to call the bootstrap cleanup code.
-	// If this fails, return an error.  If it succeeds or no transaction
-	// existed, log a new transaction in the persistent store and proceed.
-	volTxn := &persistentstore.VolumeTransaction{
+// importVolume imports a volume, reporting its progress to the supplied
+// function.
+func (o *TridentOrchestrator) importVolume(
+	backendName, internalName string, volumeConfig *storage.VolumeConfig, rename bool,
+	report func(progress string),
+) (*storage.VolumeExternal, error) {
+
+	defer o.volumeLocks.LockAll(volumeConfig.Name)()
+
+	// An interrupted import of the same volume is rolled back before the
+	// backend is locked, since rolling it back may rename the volume.
+	if err := o.rollBackExistingTransaction(&persistentstore.VolumeTransaction{
 		Config: volumeConfig,
-		Op:     persistentstore.AddVolume,
+		Op:     persistentstore.ImportVolume,
+	}); err != nil {
+		return nil, err
 	}
-	oldTxn, err := o.storeClient.GetExistingVolumeTransaction(volTxn)
+
+	// Lock the backend exclusively, so that no other volume can claim the
+	// internal name while the volume is imported.
+	o.backendLocks.Lock(backendName)
+	defer o.backendLocks.Unlock(backendName)
+
+	backend, err := o.validateVolumeImport(backendName, internalName, volumeConfig)
 	if err != nil {
-		log.Warningf("Unable to check for existing volume transactions: %v", err)
 		return nil, err
 	}
-	if oldTxn != nil {
-		err = o.rollBackTransaction(oldTxn)
-		if err != nil {
-			return nil, fmt.Errorf("Unable to roll back existing transaction "+
-				"for volume %s:  %v", volumeConfig.Name, err)
-		}
+
+	volExternal, err := backend.Driver.GetVolumeExternal(internalName)
+	if err != nil {
+		return nil, fmt.Errorf("volume %s was not found on backend %s: %v", internalName, backendName, err)
+	}
+
+	volumeConfig.Version = config.OrchestratorAPIVersion
+	volumeConfig.Size = volExternal.Config.Size
+	if volExternal.Config.Protocol != "" {
+		volumeConfig.Protocol = volExternal.Config.Protocol
+	} else if volumeConfig.Protocol == "" || volumeConfig.Protocol == config.ProtocolAny {
+		volumeConfig.Protocol = backend.GetProtocol()
+	}
+	volumeConfig.ImportOriginalName = internalName
+	if rename {
+		volumeConfig.InternalName = backend.Driver.GetInternalVolumeName(volumeConfig.Name)
+	} else {
+		volumeConfig.InternalName = internalName
 	}
 
-	err = o.storeClient.AddVolumeTransaction(volTxn)
+	// Add transaction in case the operation must be rolled back later
+	volTxn, err := o.addVolumeTransaction(volumeConfig, persistentstore.ImportVolume)
+	if err != nil {
+		return nil, err
+	}
+
+	report(fmt.Sprintf("Importing volume %s from backend %s.", internalName, backendName))
+	vol, err := backend.ImportVolume(volumeConfig, internalName, volExternal.Pool)
+	if err != nil {
+		if txErr := o.storeClient.DeleteVolumeTransaction(volTxn); txErr != nil {
+			log.WithFields(log.Fields{
+				"volume": volumeConfig.Name,
+			}).Warnf("Unable to delete volume transaction: %v", txErr)
+		}
+		return nil, fmt.Errorf("failed to import volume %s on backend %s: %v", internalName, backendName, err)
+	}
+
+	if err = o.storeClient.AddVolume(vol); err != nil {
+		return nil, err
+	}
+	o.mutex.Lock()
+	backend.Volumes[vol.Config.Name] = vol
+	o.volumes[volumeConfig.Name] = vol
+	o.mutex.Unlock()
+
+	if err = o.storeClient.DeleteVolumeTransaction(volTxn); err != nil {
+		log.WithFields(log.Fields{
+			"volume": volumeConfig.Name,
+		}).Warnf("Unable to delete volume transaction: %v", err)
+	}
+
+	log.WithFields(log.Fields{
+		"volume":       volumeConfig.Name,
+		"backend":      backendName,
+		"originalName": internalName,
+		"internalName": volumeConfig.InternalName,
+	}).Info("Imported volume.")
+
+	return vol.ConstructExternal(), nil
+}
+
+// validateVolumeImport checks that a volume may be imported from a backend
+// and returns the backend.
+func (o *TridentOrchestrator) validateVolumeImport(
+	backendName, internalName string, volumeConfig *storage.VolumeConfig,
+) (*storage.Backend, error) {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	if _, ok := o.volumes[volumeConfig.Name]; ok {
+		return nil, fmt.Errorf("volume %s already exists", volumeConfig.Name)
+	}
+	backend, found := o.backends[backendName]
+	if !found {
+		return nil, fmt.Errorf("backend %s not found", backendName)
+	}
+	if backend.State == storage.BackendStateDeleting {
+		return nil, fmt.Errorf("backend %s is being deleted", backendName)
+	}
+	for _, vol := range backend.Volumes {
+		if vol.Config.InternalName == internalName {
+			return nil, fmt.Errorf("volume %s on backend %s is already managed by Trident as %s",
+				internalName, backendName, vol.Config.Name)
+		}
+	}
+	if volumeConfig.StorageClass != "" {
+		if _, ok := o.storageClasses[volumeConfig.StorageClass]; !ok {
+			return nil, fmt.Errorf("unknown storage class: %s", volumeConfig.StorageClass)
+		}
+	}
+	return backend, nil
+}
+
+// restoreImportedVolumeNames looks for a volume whose import did not complete
+// on each backend and, if the import renamed it, renames it back.
+func (o *TridentOrchestrator) restoreImportedVolumeNames(volConfig *storage.VolumeConfig) error {
+	if volConfig.ImportOriginalName == "" || volConfig.InternalName == volConfig.ImportOriginalName {
+		return nil
+	}
+	for _, backendName := range o.getBackendNames() {
+		found, err := o.restoreImportedVolumeName(backendName, volConfig)
+		if err != nil {
+			return fmt.Errorf("backend %s: %v", backendName, err)
+		}
+		if found {
+			return nil
+		}
+	}
+	return nil
+}
+
+// restoreImportedVolumeName renames a volume whose import did not complete
+// back to its original name, if the volume is found on the backend under the
+// name the import gave it.  It returns whether the volume was found.
+func (o *TridentOrchestrator) restoreImportedVolumeName(
+	backendName string, volConfig *storage.VolumeConfig,
+) (bool, error) {
+	o.backendLocks.Lock(backendName)
+	defer o.backendLocks.Unlock(backendName)
+	o.mutex.RLock()
+	backend := o.backends[backendName]
+	recorded := o.getRecordedInternalNames()
+	o.mutex.RUnlock()
+	if backend == nil || recorded[volConfig.InternalName] {
+		return false, nil
+	}
+
+	// A volume still found under its original name was never renamed.
+	if _, err := backend.Driver.GetVolumeExternal(volConfig.ImportOriginalName); err == nil {
+		return false, nil
+	}
+	volumes, err := listBackendVolumes(backend)
+	if err != nil {
+		return false, err
+	}
+	if _, ok := volumes[volConfig.InternalName]; !ok {
+		return false, nil
+	}
+
+	// Importing the volume under its original name renames it back.
+	restoreConfig := *volConfig
+	restoreConfig.InternalName = volConfig.ImportOriginalName
+	if err = backend.Driver.Import(&restoreConfig, volConfig.InternalName); err != nil {
+		return true, err
+	}
+	log.WithFields(log.Fields{
+		"backend":      backendName,
+		"internalName": volConfig.InternalName,
+		"originalName": volConfig.ImportOriginalName,
+	}).Info("Restored the original name of a volume whose import did not complete.")
+	return true, nil
+}
+
+// addVolumeTransaction is called from the volume create/clone/import methods
+// to save a record of the operation in case it fails and must be cleaned up
+// later.
+func (o *TridentOrchestrator) addVolumeTransaction(
+	volumeConfig *storage.VolumeConfig, op persistentstore.VolumeOperation,
+) (*persistentstore.VolumeTransaction, error) {
+
+	// Check if a transaction already exists for this name.
+	// If so, we failed earlier and we need to call the bootstrap cleanup code.
+	// If this fails, return an error.  If it succeeds or no transaction
+	// existed, log a new transaction in the persistent store and proceed.
+	volTxn := &persistentstore.VolumeTransaction{
+		Config: volumeConfig,
+		Op:     op,
+	}
+	if err := o.rollBackExistingTransaction(volTxn); err != nil {
+		return nil, err
+	}
+
+	err := o.storeClient.AddVolumeTransaction(volTxn)
 	if err != nil {
 		return nil, err
 	}
@@ -693,8 +1403,29 @@ func (o *TridentOrchestrator) addVolumeTransaction(
 	return volTxn, nil
 }
 
+// rollBackExistingTransaction rolls back the transaction of an earlier,
+// interrupted operation on the volume that volTxn is for, if there is one.
+// The caller must hold the volume's lock, but none of the backend locks, as
+// the rollback may need them.
+func (o *TridentOrchestrator) rollBackExistingTransaction(volTxn *persistentstore.VolumeTransaction) error {
+	oldTxn, err := o.storeClient.GetExistingVolumeTransaction(volTxn)
+	if err != nil {
+		log.Warningf("Unable to check for existing volume transactions: %v", err)
+		return err
+	}
+	if oldTxn != nil {
+		err = o.rollBackTransaction(oldTxn)
+		if err != nil {
+			return fmt.Errorf("Unable to roll back existing transaction "+
+				"for volume %s:  %v", volTxn.Config.Name, err)
+		}
+	}
+	return nil
+}
+
 // addVolumeCleanup is used as a deferred method from the volume create/clone methods
-// to clean up in case anything goes wrong during the operation.
+// to clean up in case anything goes wrong during the operation.  The caller
+// must hold the volume's lock.
 func (o *TridentOrchestrator) addVolumeCleanup(
 	err error, backend *storage.Backend, vol *storage.Volume,
 	volTxn *persistentstore.VolumeTransaction, volumeConfig *storage.VolumeConfig) {
@@ -715,7 +1446,9 @@ func (o *TridentOrchestrator) addVolumeCleanup(
 		if backend != nil && vol != nil {
 			// We succeeded in adding the volume to the backend; now
 			// delete it
+			o.backendLocks.RLock(backend.Name)
 			cleanupErr = backend.RemoveVolume(vol)
+			o.backendLocks.RUnlock(backend.Name)
 			if cleanupErr != nil {
 				cleanupErr = fmt.Errorf("Unable to delete volume "+
 					"from backend during cleanup:  %v", cleanupErr)
@@ -734,7 +1467,9 @@ func (o *TridentOrchestrator) addVolumeCleanup(
 	if cleanupErr != nil || txErr != nil {
 		// Remove the volume from memory, if it's there, so that the user
 		// can try to re-add.  This will trigger recovery code.
+		o.mutex.Lock()
 		delete(o.volumes, volumeConfig.Name)
+		o.mutex.Unlock()
 		//externalVol = nil
 		// Report on all errors we encountered.
 		errList := make([]string, 0, 3)
@@ -749,8 +1484,11 @@ func (o *TridentOrchestrator) addVolumeCleanup(
 }
 
 func (o *TridentOrchestrator) GetVolume(volume string) *storage.VolumeExternal {
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+	if o.storeCache != nil {
+		return o.storeCache.volumes[volume]
+	}
 
 	vol, found := o.volumes[volume]
 	if !found {
@@ -762,8 +1500,8 @@ func (o *TridentOrchestrator) GetVolume(volume string) *storage.VolumeExternal {
 func (o *TridentOrchestrator) GetDriverTypeForVolume(
 	vol *storage.VolumeExternal,
 ) string {
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
 
 	if b, ok := o.backends[vol.Backend]; ok {
 		return b.Driver.Name()
@@ -772,8 +1510,8 @@ func (o *TridentOrchestrator) GetDriverTypeForVolume(
 }
 
 func (o *TridentOrchestrator) GetVolumeType(vol *storage.VolumeExternal) config.VolumeType {
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
 
 	// Since the caller has a valid VolumeExternal and we're disallowing
 	// backend deletion, we can assume that this will not hit a nil pointer.
@@ -783,8 +1521,12 @@ func (o *TridentOrchestrator) GetVolumeType(vol *storage.VolumeExternal) config.
 		return config.OntapNFS
 	case driver == drivers.OntapNASQtreeStorageDriverName:
 		return config.OntapNFS
+	case driver == drivers.OntapNASFlexGroupStorageDriverName:
+		return config.OntapNFS
 	case driver == drivers.OntapSANStorageDriverName:
 		return config.OntapISCSI
+	case driver == drivers.OntapSANEconomyStorageDriverName:
+		return config.OntapISCSI
 	case driver == drivers.SolidfireSANStorageDriverName:
 		return config.SolidFireISCSI
 	case driver == drivers.EseriesIscsiStorageDriverName:
@@ -795,8 +1537,15 @@ func (o *TridentOrchestrator) GetVolumeType(vol *storage.VolumeExternal) config.
 }
 
 func (o *TridentOrchestrator) ListVolumes() []*storage.VolumeExternal {
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+	if o.storeCache != nil {
+		volumes := make([]*storage.VolumeExternal, 0, len(o.storeCache.volumes))
+		for _, v := range o.storeCache.volumes {
+			volumes = append(volumes, v)
+		}
+		return volumes
+	}
 
 	volumes := make([]*storage.VolumeExternal, 0, len(o.volumes))
 	for _, v := range o.volumes {
@@ -806,12 +1555,16 @@ func (o *TridentOrchestrator) ListVolumes() []*storage.VolumeExternal {
 }
 
 // deleteVolume does the necessary work to delete a volume entirely.  It does
-// not construct a transaction, nor does it take locks; it assumes that the
-// caller will take care of both of these.  It also assumes that the volume
-// exists in memory.
+// not construct a transaction, and it assumes that the volume exists in
+// memory and that the caller holds the volume's lock, unless Trident is
+// bootstrapping.  It takes the other locks it needs.
 func (o *TridentOrchestrator) deleteVolume(volumeName string) error {
-	volume := o.volumes[volumeName]
-	volumeBackend := o.backends[volume.Backend]
+	volume := o.getVolume(volumeName)
+	volumeBackend, unlock := o.lockBackend(volume.Backend)
+	defer unlock()
+	if volumeBackend == nil {
+		return fmt.Errorf("backend %s for volume %s not found", volume.Backend, volumeName)
+	}
 
 	// Note that this call will only return an error if the backend actually
 	// fails to delete the volume.  If the volume does not exist on the backend,
@@ -823,6 +1576,18 @@ func (o *TridentOrchestrator) deleteVolume(volumeName string) error {
 		}).Error("Unable to delete volume from backend.")
 		return err
 	}
+	o.mutex.Lock()
+	delete(volumeBackend.Volumes, volumeName)
+	o.mutex.Unlock()
+
+	// The volume's snapshots went with it, so forget about them before
+	// forgetting the volume itself.
+	if err := o.deleteSnapshotsForVolume(volumeName); err != nil {
+		log.WithFields(log.Fields{
+			"volume": volumeName,
+		}).Error("Unable to delete snapshots from persistent store.")
+		return err
+	}
 	// Ignore failures to find the volume being deleted, as this may be called
 	// during recovery of a volume that has already been deleted from etcd.
 	// During normal operation, checks on whether the volume is present in the
@@ -833,20 +1598,39 @@ func (o *TridentOrchestrator) deleteVolume(volumeName string) error {
 		}).Error("Unable to delete volume from persistent store.")
 		return err
 	}
-	if !volumeBackend.Online && !volumeBackend.HasVolumes() {
-		if err := o.storeClient.DeleteBackend(volumeBackend); err != nil {
-			log.WithFields(log.Fields{
-				"backend": volume.Backend,
-				"volume":  volumeName,
-			}).Error("Unable to delete offline backend from the backing store" +
-				" after its last volume was deleted.  Delete the volume again" +
-				" to remove the backend.")
-			return err
-		}
-		volumeBackend.Terminate()
-		delete(o.backends, volume.Backend)
-	}
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
 	delete(o.volumes, volumeName)
+	delete(o.attachments, volumeName)
+	return nil
+}
+
+// deleteEmptyBackend finishes deleting a backend that DeleteBackend left in
+// the deleting state, once its last volume is gone.  It is called after the
+// volume's transaction has been cleared, so that a volume whose deletion is
+// retried never refers to a backend that no longer exists.  The caller must
+// not hold the backend's lock, since deleting the backend requires it
+// exclusively.
+func (o *TridentOrchestrator) deleteEmptyBackend(backendName string) error {
+	o.backendLocks.Lock(backendName)
+	defer o.backendLocks.Unlock(backendName)
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	// The backend may have gained or lost volumes, or been deleted, since
+	// the volume was deleted.
+	backend, found := o.backends[backendName]
+	if !found || backend.State != storage.BackendStateDeleting || backend.HasVolumes() {
+		return nil
+	}
+	if err := o.storeClient.DeleteBackend(backend); err != nil {
+		return err
+	}
+	log.WithField("backend", backendName).Info("Deleted backend along with its last volume.")
+	backend.Terminate()
+	delete(o.backends, backendName)
 	return nil
 }
 
@@ -858,13 +1642,16 @@ func (o *TridentOrchestrator) deleteVolume(volumeName string) error {
 // the delete or upon reboot of Trident.
 // Returns true if the volume is found and false otherwise.
 func (o *TridentOrchestrator) DeleteVolume(volumeName string) (found bool, err error) {
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	defer o.volumeLocks.LockAll(volumeName)()
 
-	volume, ok := o.volumes[volumeName]
-	if !ok {
+	volume := o.getVolume(volumeName)
+	if volume == nil {
 		return false, fmt.Errorf("volume %s not found", volumeName)
 	}
+	start := time.Now()
+	defer func() {
+		metrics.ObserveVolumeOperation("delete", volume.Backend, volume.Config.StorageClass, start, err)
+	}()
 
 	volTxn := &persistentstore.VolumeTransaction{
 		Config: volume.Config,
@@ -885,15 +1672,187 @@ func (o *TridentOrchestrator) DeleteVolume(volumeName string) (found bool, err e
 			"volume": volume,
 		}).Warn("Unable to delete volume transaction.  Repeat deletion to " +
 			"finalize.")
-		// Reinsert the volume so that it can be deleted again
+		// Reinsert the volume so that it can be deleted again.  Returning
+		// it to its backend also keeps a deleting backend around until then.
+		o.mutex.Lock()
 		o.volumes[volumeName] = volume
+		if volumeBackend, ok := o.backends[volume.Backend]; ok {
+			volumeBackend.Volumes[volumeName] = volume
+		}
+		o.mutex.Unlock()
+		return true, nil
+	}
+	if err = o.deleteEmptyBackend(volume.Backend); err != nil {
+		log.WithFields(log.Fields{
+			"backend": volume.Backend,
+			"volume":  volumeName,
+		}).Warnf("Unable to delete deleting backend from the backing store "+
+			"after its last volume was deleted.  Delete the backend again to "+
+			"remove it: %v", err)
 	}
 	return true, nil
 }
 
-func (o *TridentOrchestrator) ListVolumesByPlugin(pluginName string) []*storage.VolumeExternal {
+// resizeVolume does the necessary work to grow a volume on its backend and
+// record the new size in the persistent store.  Like deleteVolume, it doesn't
+// construct a transaction, and it assumes that the volume exists in memory
+// and that the caller holds the volume's lock.  It returns whether the
+// backend resized the volume, as a resize that couldn't be recorded must be
+// finished later.
+func (o *TridentOrchestrator) resizeVolume(volume *storage.Volume, newSize string) (bool, error) {
+	volumeBackend, unlock := o.lockBackend(volume.Backend)
+	defer unlock()
+	if volumeBackend == nil {
+		return false, fmt.Errorf("backend %s for volume %s not found", volume.Backend, volume.Config.Name)
+	}
+
+	resizedSize, err := volumeBackend.ResizeVolume(volume, newSize)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"volume":  volume.Config.Name,
+			"backend": volume.Backend,
+			"size":    newSize,
+		}).Error("Unable to resize volume on backend.")
+		return false, err
+	}
+
 	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	previousSize := volume.Config.Size
+	volume.Config.Size = resizedSize
+	o.mutex.Unlock()
+	if err = o.storeClient.UpdateVolume(volume); err != nil {
+		log.WithFields(log.Fields{
+			"volume": volume.Config.Name,
+		}).Error("Unable to update resized volume in persistent store.")
+		o.mutex.Lock()
+		volume.Config.Size = previousSize
+		o.mutex.Unlock()
+		return true, err
+	}
+	return true, nil
+}
+
+// ResizeVolume grows an existing volume to the requested size.  A transaction
+// recording the new size is kept until the resize has been persisted, so a
+// resize interrupted by a crash is completed when Trident next bootstraps.
+func (o *TridentOrchestrator) ResizeVolume(volumeName, newSize string) error {
+	defer o.volumeLocks.LockAll(volumeName)()
+
+	volume := o.getVolume(volumeName)
+	if volume == nil {
+		return fmt.Errorf("volume %s not found", volumeName)
+	}
+
+	txnConfig := *volume.Config
+	txnConfig.Size = newSize
+	volTxn := &persistentstore.VolumeTransaction{
+		Config: &txnConfig,
+		Op:     persistentstore.ResizeVolume,
+	}
+	if err := o.storeClient.AddVolumeTransaction(volTxn); err != nil {
+		return err
+	}
+
+	if resized, err := o.resizeVolume(volume, newSize); err != nil {
+		if !resized {
+			// The backend rejected the resize, so there is nothing to
+			// finish later.
+			if txErr := o.storeClient.DeleteVolumeTransaction(volTxn); txErr != nil {
+				log.WithFields(log.Fields{
+					"volume": volumeName,
+				}).Warnf("Unable to delete volume transaction: %v", txErr)
+			}
+		}
+		return err
+	}
+
+	if err := o.storeClient.DeleteVolumeTransaction(volTxn); err != nil {
+		log.WithFields(log.Fields{
+			"volume": volumeName,
+		}).Warn("Unable to delete volume transaction.  Repeat resize to " +
+			"finalize.")
+	}
+	return nil
+}
+
+// GetVolumeReplication returns the state of the relationship that replicates
+// a volume to its backend's peer for disaster recovery.
+func (o *TridentOrchestrator) GetVolumeReplication(volumeName string) (*storage.ReplicationStatus, error) {
+
+	volume := o.getVolume(volumeName)
+	if volume == nil {
+		return nil, fmt.Errorf("volume %s not found", volumeName)
+	}
+
+	volumeBackend, unlock := o.lockBackend(volume.Backend)
+	defer unlock()
+	if volumeBackend == nil {
+		return nil, fmt.Errorf("backend %s for volume %s not found", volume.Backend, volumeName)
+	}
+	status, err := volumeBackend.GetVolumeReplication(volume)
+	if err != nil {
+		return nil, err
+	}
+	if status == nil {
+		return nil, fmt.Errorf("volume %s is not replicated", volumeName)
+	}
+	return status, nil
+}
+
+// FailoverVolume breaks the replication relationship of a volume and switches
+// the volume to its replica, recording the replica's access information in
+// the persistent store.  Hosts must remount the volume to use the replica.
+func (o *TridentOrchestrator) FailoverVolume(volumeName string) (*storage.VolumeExternal, error) {
+	defer o.volumeLocks.LockAll(volumeName)()
+
+	volume := o.getVolume(volumeName)
+	if volume == nil {
+		return nil, fmt.Errorf("volume %s not found", volumeName)
+	}
+
+	volumeBackend, unlock := o.lockBackend(volume.Backend)
+	defer unlock()
+	if volumeBackend == nil {
+		return nil, fmt.Errorf("backend %s for volume %s not found", volume.Backend, volumeName)
+	}
+
+	accessInfo, err := volumeBackend.FailoverVolume(volume)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"volume":  volumeName,
+			"backend": volume.Backend,
+		}).Error("Unable to fail over volume on backend.")
+		return nil, err
+	}
+
+	o.mutex.Lock()
+	previousAccessInfo := volume.Config.AccessInfo
+	volume.Config.AccessInfo = *accessInfo
+	volume.Config.FailedOver = true
+	o.mutex.Unlock()
+	if err = o.storeClient.UpdateVolume(volume); err != nil {
+		log.WithFields(log.Fields{
+			"volume": volumeName,
+		}).Error("Unable to update failed over volume in persistent store.  " +
+			"Repeat failover to record it.")
+		o.mutex.Lock()
+		volume.Config.AccessInfo = previousAccessInfo
+		volume.Config.FailedOver = false
+		o.mutex.Unlock()
+		return nil, err
+	}
+
+	log.WithFields(log.Fields{
+		"volume":  volumeName,
+		"backend": volume.Backend,
+	}).Info("Failed over volume to its replica.")
+
+	return volume.ConstructExternal(), nil
+}
+
+func (o *TridentOrchestrator) ListVolumesByPlugin(pluginName string) []*storage.VolumeExternal {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
 
 	volumes := make([]*storage.VolumeExternal, 0)
 	for _, backend := range o.backends {
@@ -909,17 +1868,47 @@ func (o *TridentOrchestrator) ListVolumesByPlugin(pluginName string) []*storage.
 
 // AttachVolume mounts a volume to the local host.  It ensures the mount point exists,
 // and it calls the underlying storage driver to perform the attach operation as appropriate
-// for the protocol and storage controller type.
+// for the protocol and storage controller type.  A ReadWriteOnce volume may only be
+// attached at one mount point at a time, and a ReadOnlyMany volume is always mounted
+// read-only.
 func (o *TridentOrchestrator) AttachVolume(volumeName, mountpoint string, options map[string]string) error {
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	defer o.volumeLocks.LockAll(volumeName)()
 
-	volume, ok := o.volumes[volumeName]
-	if !ok {
+	volume := o.getVolume(volumeName)
+	if volume == nil {
 		return fmt.Errorf("volume %s not found", volumeName)
 	}
+	if err := volume.CheckNotFailedOver("attach"); err != nil {
+		return err
+	}
 
-	log.WithFields(log.Fields{"volume": volumeName, "mountpoint": mountpoint}).Debug("Mounting volume.")
+	log.WithFields(log.Fields{
+		"volume":     volumeName,
+		"mountpoint": mountpoint,
+		"accessMode": volume.Config.AccessMode,
+	}).Debug("Mounting volume.")
+
+	// Enforce the volume's access mode
+	attachOptions := make(map[string]string, len(options)+1)
+	for k, v := range options {
+		attachOptions[k] = v
+	}
+	attachments := o.getAttachments(volumeName)
+	for _, attachment := range attachments {
+		if attachment == mountpoint {
+			log.Debugf("%v is already attached", mountpoint)
+			return nil
+		}
+	}
+	switch volume.Config.AccessMode {
+	case config.ReadWriteOnce:
+		if len(attachments) > 0 {
+			return fmt.Errorf("volume %s has access mode %s and is already attached at %s",
+				volumeName, volume.Config.AccessMode, attachments[0])
+		}
+	case config.ReadOnlyMany:
+		attachOptions[drivers.AttachOptionReadOnly] = "true"
+	}
 
 	// Ensure mount point exists and is a directory
 	fileInfo, err := os.Lstat(mountpoint)
@@ -943,21 +1932,31 @@ func (o *TridentOrchestrator) AttachVolume(volumeName, mountpoint string, option
 	for _, e := range dfOutput {
 		if e.Target == mountpoint {
 			log.Debugf("%v is already mounted", mountpoint)
+			o.addAttachment(volumeName, mountpoint)
 			return nil
 		}
 	}
 
-	return o.backends[volume.Backend].Driver.Attach(volume.Config.InternalName, mountpoint,
-		options)
+	volumeBackend, unlock := o.lockBackend(volume.Backend)
+	defer unlock()
+	if volumeBackend == nil {
+		return fmt.Errorf("backend %s for volume %s not found", volume.Backend, volumeName)
+	}
+	if err = volumeBackend.Driver.Attach(volume.Config.InternalName, mountpoint, attachOptions); err != nil {
+		return err
+	}
+	o.addAttachment(volumeName, mountpoint)
+	return nil
 }
 
 // DetachVolume unmounts a volume from the local host.  It ensures the volume is already
 // mounted, and it calls the underlying storage driver to perform the detach operation as
 // appropriate for the protocol and storage controller type.
 func (o *TridentOrchestrator) DetachVolume(volumeName, mountpoint string) error {
+	defer o.volumeLocks.LockAll(volumeName)()
 
-	volume, ok := o.volumes[volumeName]
-	if !ok {
+	volume := o.getVolume(volumeName)
+	if volume == nil {
 		return fmt.Errorf("volume %s not found", volumeName)
 	}
 
@@ -967,15 +1966,21 @@ func (o *TridentOrchestrator) DetachVolume(volumeName, mountpoint string) error
 	_, err := os.Stat(mountpoint)
 	if err != nil {
 		// Not attached, so nothing to do
+		o.removeAttachment(volumeName, mountpoint)
 		return nil
 	}
 
 	// Unmount the volume
-	err = o.backends[volume.Backend].Driver.Detach(volume.Config.InternalName,
-		mountpoint)
+	volumeBackend, unlock := o.lockBackend(volume.Backend)
+	defer unlock()
+	if volumeBackend == nil {
+		return fmt.Errorf("backend %s for volume %s not found", volume.Backend, volumeName)
+	}
+	err = volumeBackend.Driver.Detach(volume.Config.InternalName, mountpoint)
 	if err != nil {
 		return err
 	}
+	o.removeAttachment(volumeName, mountpoint)
 
 	// Best effort removal of the mount point
 	os.Remove(mountpoint)
@@ -984,12 +1989,20 @@ func (o *TridentOrchestrator) DetachVolume(volumeName, mountpoint string) error
 
 func (o *TridentOrchestrator) ListVolumeSnapshots(volumeName string) ([]*storage.SnapshotExternal, error) {
 
-	volume, ok := o.volumes[volumeName]
-	if !ok {
+	volume := o.getVolume(volumeName)
+	if volume == nil {
 		return nil, fmt.Errorf("volume %s not found", volumeName)
 	}
+	if err := volume.CheckNotFailedOver("list the snapshots of"); err != nil {
+		return nil, err
+	}
 
-	snapshots, err := o.backends[volume.Backend].Driver.SnapshotList(volume.Config.InternalName)
+	volumeBackend, unlock := o.lockBackend(volume.Backend)
+	defer unlock()
+	if volumeBackend == nil {
+		return nil, fmt.Errorf("backend %s for volume %s not found", volume.Backend, volumeName)
+	}
+	snapshots, err := volumeBackend.Driver.SnapshotList(volume.Config.InternalName)
 	if err != nil {
 		return nil, err
 	}
@@ -1001,9 +2014,241 @@ func (o *TridentOrchestrator) ListVolumeSnapshots(volumeName string) ([]*storage
 	return externalSnapshots, nil
 }
 
+// CreateSnapshot takes a snapshot of a volume and records it in the
+// persistent store.
+func (o *TridentOrchestrator) CreateSnapshot(volumeName, snapshotName string) (*storage.SnapshotExternal, error) {
+	defer o.volumeLocks.LockAll(volumeName)()
+
+	o.mutex.RLock()
+	volume, ok := o.volumes[volumeName]
+	_, exists := o.snapshots[storage.MakeSnapshotID(volumeName, snapshotName)]
+	o.mutex.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("volume %s not found", volumeName)
+	}
+	if exists {
+		return nil, fmt.Errorf("snapshot %s already exists for volume %s", snapshotName, volumeName)
+	}
+	volumeBackend, unlock := o.lockBackend(volume.Backend)
+	defer unlock()
+	if volumeBackend == nil {
+		return nil, fmt.Errorf("backend %s for volume %s not found", volume.Backend, volumeName)
+	}
+
+	snapshot, err := volumeBackend.CreateSnapshot(volume, snapshotName)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"volume":   volumeName,
+			"snapshot": snapshotName,
+			"backend":  volume.Backend,
+		}).Error("Unable to create snapshot on backend.")
+		return nil, err
+	}
+
+	snapshotPersistent := snapshot.ConstructPersistent(volumeName)
+	if err = o.storeClient.AddSnapshot(snapshotPersistent); err != nil {
+		// Don't leave an untracked snapshot behind on the backend
+		if errDelete := volumeBackend.DeleteSnapshot(volume, snapshotName); errDelete != nil {
+			log.WithFields(log.Fields{
+				"volume":   volumeName,
+				"snapshot": snapshotName,
+				"backend":  volume.Backend,
+			}).Warnf("Unable to delete snapshot after failing to record it: %v. "+
+				"Snapshot needs to be manually deleted.", errDelete)
+		}
+		return nil, fmt.Errorf("failed to record snapshot %s for volume %s: %v", snapshotName, volumeName, err)
+	}
+	o.mutex.Lock()
+	o.snapshots[snapshotPersistent.ID()] = snapshotPersistent
+	o.mutex.Unlock()
+
+	return snapshotPersistent.ConstructExternal(), nil
+}
+
+// GetSnapshot returns a snapshot that was created through Trident, or nil if
+// no such snapshot exists.
+func (o *TridentOrchestrator) GetSnapshot(volumeName, snapshotName string) *storage.SnapshotExternal {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	snapshots := o.snapshots
+	if o.storeCache != nil {
+		snapshots = o.storeCache.snapshots
+	}
+	snapshot, ok := snapshots[storage.MakeSnapshotID(volumeName, snapshotName)]
+	if !ok {
+		return nil
+	}
+	return snapshot.ConstructExternal()
+}
+
+// ListSnapshots returns the snapshots of a volume that were created through
+// Trident.  Unlike ListVolumeSnapshots, it does not consult the backend.
+func (o *TridentOrchestrator) ListSnapshots(volumeName string) ([]*storage.SnapshotExternal, error) {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	volumeFound, snapshotsByID := false, o.snapshots
+	if o.storeCache != nil {
+		_, volumeFound = o.storeCache.volumes[volumeName]
+		snapshotsByID = o.storeCache.snapshots
+	} else {
+		_, volumeFound = o.volumes[volumeName]
+	}
+	if !volumeFound {
+		return nil, fmt.Errorf("volume %s not found", volumeName)
+	}
+
+	snapshots := make([]*storage.SnapshotExternal, 0)
+	for _, snapshot := range snapshotsByID {
+		if snapshot.Volume == volumeName {
+			snapshots = append(snapshots, snapshot.ConstructExternal())
+		}
+	}
+	return snapshots, nil
+}
+
+// DeleteSnapshot deletes a snapshot from its volume's backend and then
+// removes its record from the persistent store.
+// Returns true if the snapshot is found and false otherwise.
+func (o *TridentOrchestrator) DeleteSnapshot(volumeName, snapshotName string) (found bool, err error) {
+	defer o.volumeLocks.LockAll(volumeName)()
+
+	o.mutex.RLock()
+	volume, volumeFound := o.volumes[volumeName]
+	snapshot, snapshotFound := o.snapshots[storage.MakeSnapshotID(volumeName, snapshotName)]
+	o.mutex.RUnlock()
+
+	if !volumeFound {
+		return false, fmt.Errorf("volume %s not found", volumeName)
+	}
+	if !snapshotFound {
+		return false, fmt.Errorf("snapshot %s not found for volume %s", snapshotName, volumeName)
+	}
+	volumeBackend, unlock := o.lockBackend(volume.Backend)
+	defer unlock()
+	if volumeBackend == nil {
+		return true, fmt.Errorf("backend %s for volume %s not found", volume.Backend, volumeName)
+	}
+
+	if err = volumeBackend.DeleteSnapshot(volume, snapshotName); err != nil {
+		log.WithFields(log.Fields{
+			"volume":   volumeName,
+			"snapshot": snapshotName,
+			"backend":  volume.Backend,
+		}).Error("Unable to delete snapshot from backend.")
+		return true, err
+	}
+	if err = o.storeClient.DeleteSnapshotIgnoreNotFound(snapshot); err != nil {
+		log.WithFields(log.Fields{
+			"volume":   volumeName,
+			"snapshot": snapshotName,
+		}).Error("Unable to delete snapshot from persistent store.")
+		return true, err
+	}
+	o.mutex.Lock()
+	delete(o.snapshots, snapshot.ID())
+	o.mutex.Unlock()
+	return true, nil
+}
+
+// RestoreSnapshot reverts a volume to the state captured by one of the
+// snapshots created through Trident.
+func (o *TridentOrchestrator) RestoreSnapshot(volumeName, snapshotName string) error {
+	defer o.volumeLocks.LockAll(volumeName)()
+
+	o.mutex.RLock()
+	volume, volumeFound := o.volumes[volumeName]
+	_, snapshotFound := o.snapshots[storage.MakeSnapshotID(volumeName, snapshotName)]
+	o.mutex.RUnlock()
+
+	if !volumeFound {
+		return fmt.Errorf("volume %s not found", volumeName)
+	}
+	if !snapshotFound {
+		return fmt.Errorf("snapshot %s not found for volume %s", snapshotName, volumeName)
+	}
+	volumeBackend, unlock := o.lockBackend(volume.Backend)
+	defer unlock()
+	if volumeBackend == nil {
+		return fmt.Errorf("backend %s for volume %s not found", volume.Backend, volumeName)
+	}
+
+	if err := volumeBackend.RestoreSnapshot(volume, snapshotName); err != nil {
+		log.WithFields(log.Fields{
+			"volume":   volumeName,
+			"snapshot": snapshotName,
+			"backend":  volume.Backend,
+		}).Error("Unable to restore snapshot on backend.")
+		return err
+	}
+
+	// Some backends discard any snapshots newer than the one being restored,
+	// so drop the records of snapshots that no longer exist.
+	backendSnapshots, err := volumeBackend.Driver.SnapshotList(volume.Config.InternalName)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"volume": volumeName,
+		}).Warnf("Unable to list snapshots after restore: %v", err)
+		return nil
+	}
+	remaining := make(map[string]bool)
+	for _, s := range backendSnapshots {
+		remaining[s.Name] = true
+	}
+	for _, s := range o.getSnapshotsForVolume(volumeName) {
+		if remaining[s.Name] {
+			continue
+		}
+		if err = o.storeClient.DeleteSnapshotIgnoreNotFound(s); err != nil {
+			log.WithFields(log.Fields{
+				"volume":   volumeName,
+				"snapshot": s.Name,
+			}).Warnf("Unable to delete record of discarded snapshot: %v", err)
+			continue
+		}
+		o.mutex.Lock()
+		delete(o.snapshots, s.ID())
+		o.mutex.Unlock()
+	}
+	return nil
+}
+
+// getSnapshotsForVolume returns the records of all snapshots belonging to a
+// volume.
+func (o *TridentOrchestrator) getSnapshotsForVolume(volumeName string) []*storage.SnapshotPersistent {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	snapshots := make([]*storage.SnapshotPersistent, 0)
+	for _, snapshot := range o.snapshots {
+		if snapshot.Volume == volumeName {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	return snapshots
+}
+
+// deleteSnapshotsForVolume removes the records of all snapshots belonging to
+// a volume from memory and from the persistent store.  It does not touch the
+// backend, and the caller must hold the volume's lock.
+func (o *TridentOrchestrator) deleteSnapshotsForVolume(volumeName string) error {
+	for _, snapshot := range o.getSnapshotsForVolume(volumeName) {
+		if err := o.storeClient.DeleteSnapshotIgnoreNotFound(snapshot); err != nil {
+			return err
+		}
+		o.mutex.Lock()
+		delete(o.snapshots, snapshot.ID())
+		o.mutex.Unlock()
+	}
+	return nil
+}
+
 func (o *TridentOrchestrator) ReloadVolumes() error {
 
 	// Lock out all other workflows while we reload the volumes
+	defer o.backendLocks.LockAll(o.getBackendNames()...)()
 	o.mutex.Lock()
 	defer o.mutex.Unlock()
 
@@ -1058,6 +2303,12 @@ func (o *TridentOrchestrator) getProtocol(mode config.AccessMode) config.Protoco
 func (o *TridentOrchestrator) AddStorageClass(scConfig *storageclass.Config) (*storageclass.External, error) {
 	o.mutex.Lock()
 	defer o.mutex.Unlock()
+	if err := storageclass.ValidatePoolSelectionPolicy(scConfig.PoolSelection); err != nil {
+		return nil, err
+	}
+	if err := storageattribute.ValidateRequests(scConfig.Attributes); err != nil {
+		return nil, err
+	}
 	sc := storageclass.New(scConfig)
 	if _, ok := o.storageClasses[sc.GetName()]; ok {
 		return nil, fmt.Errorf("storage class %s already exists", sc.GetName())
@@ -1083,9 +2334,46 @@ func (o *TridentOrchestrator) AddStorageClass(scConfig *storageclass.Config) (*s
 	return sc.ConstructExternal(), nil
 }
 
-func (o *TridentOrchestrator) GetStorageClass(scName string) *storageclass.External {
+// UpdateStorageClass replaces the configuration of an existing storage class,
+// and matches the storage class against the storage pools of every backend
+// again.  Volumes already provisioned from the storage class are unaffected.
+func (o *TridentOrchestrator) UpdateStorageClass(scConfig *storageclass.Config) (*storageclass.External, error) {
 	o.mutex.Lock()
 	defer o.mutex.Unlock()
+	if err := storageclass.ValidatePoolSelectionPolicy(scConfig.PoolSelection); err != nil {
+		return nil, err
+	}
+	if err := storageattribute.ValidateRequests(scConfig.Attributes); err != nil {
+		return nil, err
+	}
+	sc := storageclass.New(scConfig)
+	oldSC, ok := o.storageClasses[sc.GetName()]
+	if !ok {
+		return nil, fmt.Errorf("storage class %s not found", sc.GetName())
+	}
+	if err := o.storeClient.UpdateStorageClass(sc); err != nil {
+		return nil, err
+	}
+	for _, storagePool := range oldSC.GetStoragePoolsForProtocol(config.ProtocolAny) {
+		storagePool.RemoveStorageClass(sc.GetName())
+	}
+	o.storageClasses[sc.GetName()] = sc
+	added := 0
+	for _, backend := range o.backends {
+		added += sc.CheckAndAddBackend(backend)
+	}
+	log.WithFields(log.Fields{
+		"storageClass": sc.GetName(),
+	}).Infof("Storage class updated; satisfied by %d storage pools.", added)
+	return sc.ConstructExternal(), nil
+}
+
+func (o *TridentOrchestrator) GetStorageClass(scName string) *storageclass.External {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+	if o.storeCache != nil {
+		return o.storeCache.storageClasses[scName]
+	}
 	sc, ok := o.storageClasses[scName]
 	if !ok {
 		return nil
@@ -1096,8 +2384,15 @@ func (o *TridentOrchestrator) GetStorageClass(scName string) *storageclass.Exter
 }
 
 func (o *TridentOrchestrator) ListStorageClasses() []*storageclass.External {
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+	if o.storeCache != nil {
+		ret := make([]*storageclass.External, 0, len(o.storeCache.storageClasses))
+		for _, sc := range o.storeCache.storageClasses {
+			ret = append(ret, sc)
+		}
+		return ret
+	}
 	ret := make([]*storageclass.External, 0, len(o.storageClasses))
 	for _, sc := range o.storageClasses {
 		ret = append(ret, sc.ConstructExternal())
@@ -1106,6 +2401,9 @@ func (o *TridentOrchestrator) ListStorageClasses() []*storageclass.External {
 }
 
 func (o *TridentOrchestrator) DeleteStorageClass(scName string) (bool, error) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
 	sc, found := o.storageClasses[scName]
 	if !found {
 		return found, fmt.Errorf("storage class %s not found", scName)
@@ -6,10 +6,17 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"reflect"
+	"sort"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	log "github.com/sirupsen/logrus"
 
 	"github.com/netapp/trident/config"
@@ -19,6 +26,7 @@ import (
 	sa "github.com/netapp/trident/storage_attribute"
 	"github.com/netapp/trident/storage_class"
 	tu "github.com/netapp/trident/storage_class/test_utils"
+	drivers "github.com/netapp/trident/storage_drivers"
 	fakedriver "github.com/netapp/trident/storage_drivers/fake"
 )
 
@@ -73,6 +81,15 @@ func cleanup(t *testing.T, o *TridentOrchestrator) {
 	if err != nil && !persistentstore.MatchKeyNotFoundErr(err) {
 		t.Fatal("Unable to clean up volumes:  ", err)
 	}
+	ops, err := o.storeClient.GetOperations()
+	if err != nil {
+		t.Fatal("Unable to retrieve operations:  ", err)
+	}
+	for _, op := range ops {
+		if err = o.storeClient.DeleteOperation(op); err != nil {
+			t.Fatalf("Unable to clean up operation %s:  %v", op.ID, err)
+		}
+	}
 	if *etcdV2 == "" && *etcdV3 == "" {
 		// Clear the InMemoryClient state so that it looks like we're
 		// bootstrapping afresh next time.
@@ -302,6 +319,7 @@ func getOrchestrator() *TridentOrchestrator {
 		storeClient = inMemoryClient
 	}
 	o := NewTridentOrchestrator(storeClient)
+	defer o.Stop()
 	if err = o.Bootstrap(); err != nil {
 		log.Fatal("Failure occurred during bootstrapping: ", err)
 	}
@@ -405,6 +423,7 @@ func validateStorageClass(
 func TestAddStorageClassVolumes(t *testing.T) {
 	mockPools := tu.GetFakePools()
 	orchestrator := getOrchestrator()
+	defer orchestrator.Stop()
 
 	errored := false
 	for _, c := range []struct {
@@ -881,6 +900,7 @@ func TestAddStorageClassVolumes(t *testing.T) {
 func TestCloneVolumes(t *testing.T) {
 	mockPools := tu.GetFakePools()
 	orchestrator := getOrchestrator()
+	defer orchestrator.Stop()
 
 	errored := false
 	for _, c := range []struct {
@@ -1210,6 +1230,7 @@ func TestBackendUpdateAndDelete(t *testing.T) {
 	)
 	// Test setup
 	orchestrator := getOrchestrator()
+	defer orchestrator.Stop()
 	addBackendStorageClass(t, orchestrator, backendName, scName)
 
 	orchestrator.mutex.Lock()
@@ -1361,13 +1382,13 @@ func TestBackendUpdateAndDelete(t *testing.T) {
 	backend := previousBackends[len(previousBackends)-1]
 	pool := volume.Pool
 
-	// Test backend offlining.
-	found, err := orchestrator.OfflineBackend(backendName)
+	// Test backend deletion.
+	found, err := orchestrator.DeleteBackend(backendName)
 	if !found {
 		t.Fatal("Backend not found in orchestrator.")
 	}
 	if err != nil {
-		t.Fatal("Unable to offline backend:  ", err)
+		t.Fatal("Unable to delete backend:  ", err)
 	}
 	if !backend.Driver.Initialized() {
 		t.Errorf("Offlined backend with volumes %s is not initialized.", backendName)
@@ -1384,14 +1405,14 @@ func TestBackendUpdateAndDelete(t *testing.T) {
 			"storage class.")
 	}
 	if orchestrator.backends[volume.Backend] != backend {
-		t.Error("Backend changed for volume after offlining.")
+		t.Error("Backend changed for volume after backend deletion.")
 	}
 	if volume.Pool != pool {
-		t.Error("Storage pool changed for volume after backend offlined.")
+		t.Error("Storage pool changed for volume after backend deletion.")
 	}
 	persistentBackend, err := orchestrator.storeClient.GetBackend(backendName)
 	if err != nil {
-		t.Error("Unable to retrieve backend from store client after offlining:"+
+		t.Error("Unable to retrieve backend from store client after backend deletion:"+
 			"  ", err)
 	} else if persistentBackend.Online {
 		t.Error("Online not set to true in the backend.")
@@ -1410,7 +1431,7 @@ func TestBackendUpdateAndDelete(t *testing.T) {
 		},
 	)
 	if err != nil {
-		t.Fatal("Unable to add new storage class after offlining:  ", err)
+		t.Fatal("Unable to add new storage class after backend deletion:  ", err)
 	}
 	if _, ok = newSCExternal.StoragePools[backendName]; ok {
 		t.Error("Offline backend added to new storage class.")
@@ -1418,6 +1439,7 @@ func TestBackendUpdateAndDelete(t *testing.T) {
 
 	// Test that online gets set properly after bootstrapping.
 	newOrchestrator := getOrchestrator()
+	defer newOrchestrator.Stop()
 	// We need to lock the orchestrator mutex here because we call
 	// ConstructExternal on the original backend in the else if clause.
 	orchestrator.mutex.Lock()
@@ -1461,7 +1483,7 @@ func TestBackendUpdateAndDelete(t *testing.T) {
 	orchestrator.mutex.Lock()
 	_, ok = orchestrator.backends[backendName]
 	if ok {
-		t.Error("Empty offlined backend not removed from memory.")
+		t.Error("Empty deleted backend not removed from memory.")
 	}
 	orchestrator.mutex.Unlock()
 	cleanup(t, orchestrator)
@@ -1473,14 +1495,15 @@ func TestEmptyBackendDeletion(t *testing.T) {
 	)
 
 	orchestrator := getOrchestrator()
+	defer orchestrator.Stop()
 	// Note that we don't care about the storage class here, but it's easier
 	// to reuse functionality.
 	addBackendStorageClass(t, orchestrator, backendName, "none")
 	backend := orchestrator.backends[backendName]
 
-	found, err := orchestrator.OfflineBackend(backendName)
+	found, err := orchestrator.DeleteBackend(backendName)
 	if err != nil {
-		t.Fatal("Unable to offline backend:  ", err)
+		t.Fatal("Unable to delete backend:  ", err)
 	} else if !found {
 		t.Fatalf("Backend %s not found in orchestrator", backendName)
 	}
@@ -1489,12 +1512,12 @@ func TestEmptyBackendDeletion(t *testing.T) {
 	}
 	_, err = orchestrator.storeClient.GetBackend(backendName)
 	if err == nil {
-		t.Error("Empty backend remained on store client after offlining")
+		t.Error("Empty backend remained on store client after deletion")
 	}
 	orchestrator.mutex.Lock()
 	_, ok := orchestrator.backends[backendName]
 	if ok {
-		t.Error("Empty offlined backend not removed from memory.")
+		t.Error("Empty deleted backend not removed from memory.")
 	}
 	orchestrator.mutex.Unlock()
 	cleanup(t, orchestrator)
@@ -1509,6 +1532,7 @@ func TestBackendCleanup(t *testing.T) {
 	)
 
 	orchestrator := getOrchestrator()
+	defer orchestrator.Stop()
 	addBackendStorageClass(t, orchestrator, offlineBackendName, scName)
 	_, err := orchestrator.AddVolume(generateVolumeConfig(volumeName, 50,
 		scName, config.File))
@@ -1517,15 +1541,15 @@ func TestBackendCleanup(t *testing.T) {
 	}
 
 	// This needs to go after the volume addition to ensure that the volume
-	// ends up on the backend to be offlined.
+	// ends up on the backend to be deleted.
 	addBackend(t, orchestrator, onlineBackendName)
 
-	found, err := orchestrator.OfflineBackend(offlineBackendName)
+	found, err := orchestrator.DeleteBackend(offlineBackendName)
 	if err != nil {
-		t.Fatal("Unable to offline backend.")
+		t.Fatal("Unable to delete backend:  ", err)
 	}
 	if !found {
-		t.Fatalf("Backend %s not found when trying to offline.", offlineBackendName)
+		t.Fatalf("Backend %s not found when trying to delete.", offlineBackendName)
 	}
 	// Simulate deleting the existing volume and then bootstrapping
 	orchestrator.mutex.Lock()
@@ -1540,8 +1564,9 @@ func TestBackendCleanup(t *testing.T) {
 	orchestrator.mutex.Unlock()
 
 	newOrchestrator := getOrchestrator()
+	defer newOrchestrator.Stop()
 	if bootstrappedBackend := newOrchestrator.GetBackend(offlineBackendName); bootstrappedBackend != nil {
-		t.Error("Empty offline backend not deleted during bootstrap.")
+		t.Error("Empty deleting backend not deleted during bootstrap.")
 	}
 	if bootstrappedBackend := newOrchestrator.GetBackend(onlineBackendName); bootstrappedBackend == nil {
 		t.Error("Empty online backend deleted during bootstrap.")
@@ -1553,6 +1578,7 @@ func TestLoadBackend(t *testing.T) {
 		backendName = "load-backend-test"
 	)
 	orchestrator := getOrchestrator()
+	defer orchestrator.Stop()
 	configJSON, err := fakedriver.NewFakeStorageDriverConfigJSON(
 		backendName,
 		config.File,
@@ -1588,6 +1614,7 @@ func TestLoadBackend(t *testing.T) {
 	}
 
 	newOrchestrator := getOrchestrator()
+	defer newOrchestrator.Stop()
 	if bootstrappedBackend := newOrchestrator.GetBackend(backendName); bootstrappedBackend == nil {
 		t.Error("Unable to find backend after bootstrapping.")
 	} else if !reflect.DeepEqual(bootstrappedBackend, originalBackend) {
@@ -1654,6 +1681,7 @@ func runRecoveryTests(
 				err)
 		}
 		newOrchestrator := getOrchestrator()
+		defer newOrchestrator.Stop()
 		newOrchestrator.mutex.Lock()
 		if _, ok := newOrchestrator.volumes[c.volumeConfig.Name]; ok {
 			t.Errorf("%s: volume still present in orchestrator.", c.name)
@@ -1699,6 +1727,7 @@ func TestAddVolumeRecovery(t *testing.T) {
 		txOnlyVolumeName = "addRecoveryVolumeTxOnly"
 	)
 	orchestrator := getOrchestrator()
+	defer orchestrator.Stop()
 	prepRecoveryTest(t, orchestrator, backendName, scName)
 	// It's easier to add the volume and then reinject the transaction begin
 	// afterwards
@@ -1727,6 +1756,7 @@ func TestDeleteVolumeRecovery(t *testing.T) {
 		txOnlyVolumeName = "deleteRecoveryVolumeTxOnly"
 	)
 	orchestrator := getOrchestrator()
+	defer orchestrator.Stop()
 	prepRecoveryTest(t, orchestrator, backendName, scName)
 	// For the full test, we delete everything but the ending transaction.
 	fullVolumeConfig := generateVolumeConfig(fullVolumeName, 50, scName,
@@ -1793,6 +1823,7 @@ func TestBootstrapEtcdV2ToEtcdV3Migration(t *testing.T) {
 	etcdV2Orig := etcdV2
 	etcdV2 = etcdV3
 	orchestratorV2 := getOrchestrator()
+	defer orchestratorV2.Stop()
 	addBackendStorageClass(t, orchestratorV2, backendName, scName)
 
 	orchestratorV2.mutex.Lock()
@@ -1815,6 +1846,7 @@ func TestBootstrapEtcdV2ToEtcdV3Migration(t *testing.T) {
 	// Bootstrap etcdv3 orchestrator with etcdv2 data
 	etcdV2 = etcdV2Orig
 	orchestratorV3 := getOrchestrator()
+	defer orchestratorV3.Stop()
 
 	// Verify etcdv2 to etcdv3 transformation
 	if orchestratorV3.GetBackend(backendName) == nil {
@@ -1871,6 +1903,7 @@ func TestStorageClassOnlyBootstrap(t *testing.T) {
 	const scName = "storageclass-only"
 
 	orchestrator := getOrchestrator()
+	defer orchestrator.Stop()
 	originalSC, err := orchestrator.AddStorageClass(
 		&storageclass.Config{
 			Name: scName,
@@ -1885,6 +1918,7 @@ func TestStorageClassOnlyBootstrap(t *testing.T) {
 		t.Fatal("Unable to add storage class: ", err)
 	}
 	newOrchestrator := getOrchestrator()
+	defer newOrchestrator.Stop()
 	if bootstrappedSC := newOrchestrator.GetStorageClass(scName); bootstrappedSC == nil {
 		t.Error("Unable to find storage class after bootstrapping.")
 	} else if !reflect.DeepEqual(bootstrappedSC, originalSC) {
@@ -1902,6 +1936,7 @@ func TestFirstVolumeRecovery(t *testing.T) {
 		txOnlyVolumeName = "firstRecoveryVolumeTxOnly"
 	)
 	orchestrator := getOrchestrator()
+	defer orchestrator.Stop()
 	prepRecoveryTest(t, orchestrator, backendName, scName)
 	txOnlyVolumeConfig := generateVolumeConfig(txOnlyVolumeName, 50, scName,
 		config.File)
@@ -1913,3 +1948,1409 @@ func TestFirstVolumeRecovery(t *testing.T) {
 		})
 	cleanup(t, orchestrator)
 }
+
+func TestResizeVolume(t *testing.T) {
+	const (
+		backendName = "resizeBackend"
+		scName      = "resizeBackendSC"
+		volumeName  = "resizeVolume"
+	)
+	orchestrator := getOrchestrator()
+	defer orchestrator.Stop()
+	addBackendStorageClass(t, orchestrator, backendName, scName)
+	_, err := orchestrator.AddVolume(generateVolumeConfig(volumeName, 50,
+		scName, config.File))
+	if err != nil {
+		t.Fatal("Unable to add volume: ", err)
+	}
+
+	newSize := fmt.Sprintf("%d", 60*1024*1024*1024)
+	if err = orchestrator.ResizeVolume(volumeName, newSize); err != nil {
+		t.Fatal("Unable to resize volume: ", err)
+	}
+	if size := orchestrator.GetVolume(volumeName).Config.Size; size != newSize {
+		t.Errorf("Wrong volume size in memory; expected %s, got %s", newSize,
+			size)
+	}
+	storedVolume, err := orchestrator.storeClient.GetVolume(volumeName)
+	if err != nil {
+		t.Fatal("Unable to retrieve volume from the backing store: ", err)
+	}
+	if storedVolume.Config.Size != newSize {
+		t.Errorf("Wrong volume size in the backing store; expected %s, got "+
+			"%s", newSize, storedVolume.Config.Size)
+	}
+	f := orchestrator.backends[backendName].Driver.(*fakedriver.StorageDriver)
+	internalName := orchestrator.volumes[volumeName].Config.InternalName
+	if sizeBytes := f.Volumes[internalName].SizeBytes; fmt.Sprintf("%d",
+		sizeBytes) != newSize {
+		t.Errorf("Wrong volume size on the backend; expected %s, got %d",
+			newSize, sizeBytes)
+	}
+
+	// Shrinking a volume is not allowed
+	smallSize := fmt.Sprintf("%d", 10*1024*1024*1024)
+	if err = orchestrator.ResizeVolume(volumeName, smallSize); err == nil {
+		t.Error("Shrinking a volume should have failed.")
+	}
+	if size := orchestrator.GetVolume(volumeName).Config.Size; size != newSize {
+		t.Errorf("Failed resize changed the volume size to %s", size)
+	}
+
+	if err = orchestrator.ResizeVolume("missingVolume", newSize); err == nil {
+		t.Error("Resizing a nonexistent volume should have failed.")
+	}
+
+	if txns, err := orchestrator.storeClient.GetVolumeTransactions(); err != nil {
+		t.Errorf("Unable to retrieve transactions from backing store: %v",
+			err)
+	} else if len(txns) > 0 {
+		t.Error("Transaction not cleared from the backing store.")
+	}
+	cleanup(t, orchestrator)
+}
+
+func TestResizeVolumeRecovery(t *testing.T) {
+	const (
+		backendName = "resizeRecoveryBackend"
+		scName      = "resizeRecoveryBackendSC"
+		volumeName  = "resizeRecoveryVolume"
+	)
+	orchestrator := getOrchestrator()
+	defer orchestrator.Stop()
+	prepRecoveryTest(t, orchestrator, backendName, scName)
+	volumeConfig := generateVolumeConfig(volumeName, 50, scName, config.File)
+	_, err := orchestrator.AddVolume(volumeConfig)
+	if err != nil {
+		t.Fatal("Unable to add volume: ", err)
+	}
+
+	// Simulate a crash after logging the resize transaction but before
+	// resizing the volume on the backend.
+	txnConfig := *orchestrator.volumes[volumeName].Config
+	txnConfig.Size = fmt.Sprintf("%d", 60*1024*1024*1024)
+	volTxn := &persistentstore.VolumeTransaction{
+		Config: &txnConfig,
+		Op:     persistentstore.ResizeVolume,
+	}
+	if err = orchestrator.storeClient.AddVolumeTransaction(volTxn); err != nil {
+		t.Fatal("Unable to create volume transaction: ", err)
+	}
+	unlock := orchestrator.volumeLocks.LockAll(volumeName)
+	err = orchestrator.rollBackTransaction(volTxn)
+	unlock()
+	if err != nil {
+		t.Fatal("Unable to recover resize transaction: ", err)
+	}
+
+	if size := orchestrator.GetVolume(volumeName).Config.Size; size != txnConfig.Size {
+		t.Errorf("Resize not completed; expected size %s, got %s",
+			txnConfig.Size, size)
+	}
+	storedVolume, err := orchestrator.storeClient.GetVolume(volumeName)
+	if err != nil {
+		t.Fatal("Unable to retrieve volume from the backing store: ", err)
+	}
+	if storedVolume.Config.Size != txnConfig.Size {
+		t.Errorf("Resize not persisted; expected size %s, got %s",
+			txnConfig.Size, storedVolume.Config.Size)
+	}
+	if txns, err := orchestrator.storeClient.GetVolumeTransactions(); err != nil {
+		t.Errorf("Unable to retrieve transactions from backing store: %v",
+			err)
+	} else if len(txns) > 0 {
+		t.Error("Transaction not cleared from the backing store.")
+	}
+	cleanup(t, orchestrator)
+}
+
+func TestSnapshots(t *testing.T) {
+	const (
+		backendName  = "snapshotBackend"
+		scName       = "snapshotBackendSC"
+		volumeName   = "snapshotVolume"
+		snapshotName = "snapshot1"
+	)
+	orchestrator := getOrchestrator()
+	defer orchestrator.Stop()
+	addBackendStorageClass(t, orchestrator, backendName, scName)
+	_, err := orchestrator.AddVolume(generateVolumeConfig(volumeName, 50,
+		scName, config.File))
+	if err != nil {
+		t.Fatal("Unable to add volume: ", err)
+	}
+
+	snapshot, err := orchestrator.CreateSnapshot(volumeName, snapshotName)
+	if err != nil {
+		t.Fatal("Unable to create snapshot: ", err)
+	}
+	if snapshot.Name != snapshotName {
+		t.Errorf("Wrong snapshot name; expected %s, got %s", snapshotName,
+			snapshot.Name)
+	}
+	if _, err = orchestrator.CreateSnapshot(volumeName, snapshotName); err == nil {
+		t.Error("Creating a duplicate snapshot should have failed.")
+	}
+	if _, err = orchestrator.CreateSnapshot("missingVolume", snapshotName); err == nil {
+		t.Error("Snapshotting a nonexistent volume should have failed.")
+	}
+	if orchestrator.GetSnapshot(volumeName, snapshotName) == nil {
+		t.Error("Unable to find snapshot in memory.")
+	}
+	if _, err = orchestrator.storeClient.GetSnapshot(volumeName,
+		snapshotName); err != nil {
+		t.Error("Unable to retrieve snapshot from the backing store: ", err)
+	}
+	snapshots, err := orchestrator.ListSnapshots(volumeName)
+	if err != nil {
+		t.Fatal("Unable to list snapshots: ", err)
+	}
+	if len(snapshots) != 1 {
+		t.Errorf("Wrong number of snapshots; expected 1, got %d",
+			len(snapshots))
+	}
+
+	// Snapshot records survive a restart
+	newOrchestrator := NewTridentOrchestrator(orchestrator.storeClient)
+	defer newOrchestrator.Stop()
+	if err = newOrchestrator.Bootstrap(); err != nil {
+		t.Fatal("Unable to bootstrap new orchestrator: ", err)
+	}
+	if newOrchestrator.GetSnapshot(volumeName, snapshotName) == nil {
+		t.Error("Snapshot not bootstrapped.")
+	}
+
+	if err = orchestrator.RestoreSnapshot(volumeName, snapshotName); err != nil {
+		t.Error("Unable to restore snapshot: ", err)
+	}
+	if err = orchestrator.RestoreSnapshot(volumeName, "missingSnapshot"); err == nil {
+		t.Error("Restoring a nonexistent snapshot should have failed.")
+	}
+
+	found, err := orchestrator.DeleteSnapshot(volumeName, snapshotName)
+	if !found || err != nil {
+		t.Fatalf("Unable to delete snapshot; found: %t, error: %v", found,
+			err)
+	}
+	if orchestrator.GetSnapshot(volumeName, snapshotName) != nil {
+		t.Error("Snapshot not deleted from memory.")
+	}
+	if _, err = orchestrator.storeClient.GetSnapshot(volumeName,
+		snapshotName); err == nil {
+		t.Error("Snapshot not deleted from the backing store.")
+	}
+	if found, _ = orchestrator.DeleteSnapshot(volumeName,
+		snapshotName); found {
+		t.Error("Deleting a nonexistent snapshot reported it as found.")
+	}
+
+	// Deleting a volume deletes its snapshot records
+	if _, err = orchestrator.CreateSnapshot(volumeName, snapshotName); err != nil {
+		t.Fatal("Unable to create snapshot: ", err)
+	}
+	if _, err = orchestrator.DeleteVolume(volumeName); err != nil {
+		t.Fatal("Unable to delete volume: ", err)
+	}
+	if _, err = orchestrator.storeClient.GetSnapshot(volumeName,
+		snapshotName); err == nil {
+		t.Error("Snapshot record not deleted along with its volume.")
+	}
+	cleanup(t, orchestrator)
+}
+
+func TestVolumeReplication(t *testing.T) {
+	const (
+		backendName       = "replicationBackend"
+		scName            = "replicationBackendSC"
+		volumeName        = "replicatedVolume"
+		plainBackendName  = "plainBackend"
+		plainSCName       = "plainBackendSC"
+		plainVolumeName   = "unreplicatedVolume"
+		originalNfsServer = "192.0.2.1"
+	)
+	orchestrator := getOrchestrator()
+	defer orchestrator.Stop()
+	configJSON, err := fakedriver.NewFakeStorageDriverConfigJSON(
+		backendName,
+		config.File,
+		map[string]*fake.StoragePool{
+			"replicated": {
+				Attrs: map[string]sa.Offer{
+					sa.Replication:      sa.NewBoolOffer(true),
+					sa.TestingAttribute: sa.NewBoolOffer(true),
+				},
+				Bytes: 100 * 1024 * 1024 * 1024,
+			},
+		},
+	)
+	if err != nil {
+		t.Fatal("Unable to create mock driver config JSON: ", err)
+	}
+	if _, err = orchestrator.AddStorageBackend(configJSON); err != nil {
+		t.Fatal("Unable to add backend: ", err)
+	}
+	if _, err = orchestrator.AddStorageClass(&storageclass.Config{
+		Name: scName,
+		Attributes: map[string]sa.Request{
+			sa.Replication:      sa.NewBoolRequest(true),
+			sa.TestingAttribute: sa.NewBoolRequest(true),
+		},
+	}); err != nil {
+		t.Fatal("Unable to add storage class: ", err)
+	}
+	addBackendStorageClass(t, orchestrator, plainBackendName, plainSCName)
+
+	if _, err = orchestrator.AddVolume(generateVolumeConfig(volumeName, 50,
+		scName, config.File)); err != nil {
+		t.Fatal("Unable to add volume: ", err)
+	}
+	if _, err = orchestrator.AddVolume(generateVolumeConfig(plainVolumeName, 50,
+		plainSCName, config.File)); err != nil {
+		t.Fatal("Unable to add volume: ", err)
+	}
+
+	status, err := orchestrator.GetVolumeReplication(volumeName)
+	if err != nil {
+		t.Fatal("Unable to get volume replication: ", err)
+	}
+	if !status.Healthy || status.State != "snapmirrored" {
+		t.Errorf("Wrong replication status before failover: %+v", status)
+	}
+	if _, err = orchestrator.GetVolumeReplication(plainVolumeName); err == nil {
+		t.Error("Getting the replication of an unreplicated volume should have failed.")
+	}
+	if _, err = orchestrator.GetVolumeReplication("missingVolume"); err == nil {
+		t.Error("Getting the replication of a nonexistent volume should have failed.")
+	}
+
+	if server := orchestrator.GetVolume(volumeName).Config.AccessInfo.NfsServerIP; server != originalNfsServer {
+		t.Fatalf("Wrong NFS server before failover; expected %s, got %s", originalNfsServer, server)
+	}
+	volume, err := orchestrator.FailoverVolume(volumeName)
+	if err != nil {
+		t.Fatal("Unable to fail over volume: ", err)
+	}
+	failoverNfsServer := volume.Config.AccessInfo.NfsServerIP
+	if failoverNfsServer == originalNfsServer {
+		t.Error("Failover didn't change the volume's NFS server.")
+	}
+	storedVolume, err := orchestrator.storeClient.GetVolume(volumeName)
+	if err != nil {
+		t.Fatal("Unable to retrieve volume from the backing store: ", err)
+	}
+	if server := storedVolume.Config.AccessInfo.NfsServerIP; server != failoverNfsServer {
+		t.Errorf("Failover not persisted; expected NFS server %s, got %s", failoverNfsServer, server)
+	}
+	if status, err = orchestrator.GetVolumeReplication(volumeName); err != nil {
+		t.Fatal("Unable to get volume replication: ", err)
+	} else if status.State != "broken-off" {
+		t.Errorf("Wrong replication state after failover; expected broken-off, got %s", status.State)
+	}
+	if !storedVolume.Config.FailedOver {
+		t.Error("Failover not recorded in the volume's config.")
+	}
+	if err = orchestrator.ResizeVolume(volumeName, "2GiB"); err == nil {
+		t.Error("Resizing a failed over volume should have failed.")
+	}
+	if _, err = orchestrator.CreateSnapshot(volumeName, "snap1"); err == nil {
+		t.Error("Snapshotting a failed over volume should have failed.")
+	}
+	if _, err = orchestrator.ListVolumeSnapshots(volumeName); err == nil {
+		t.Error("Listing the snapshots of a failed over volume should have failed.")
+	}
+	if err = orchestrator.AttachVolume(volumeName, "/mnt/failedOver", nil); err == nil {
+		t.Error("Attaching a failed over volume should have failed.")
+	}
+
+	if _, err = orchestrator.FailoverVolume(plainVolumeName); err == nil {
+		t.Error("Failing over an unreplicated volume should have failed.")
+	}
+	if server := orchestrator.GetVolume(plainVolumeName).Config.AccessInfo.NfsServerIP; server != originalNfsServer {
+		t.Errorf("Failed failover changed the NFS server to %s", server)
+	}
+	if _, err = orchestrator.FailoverVolume("missingVolume"); err == nil {
+		t.Error("Failing over a nonexistent volume should have failed.")
+	}
+	cleanup(t, orchestrator)
+}
+
+func TestUpdateBackend(t *testing.T) {
+	const (
+		backendName = "updateConfigBackend"
+		scName      = "updateConfigBackendSC"
+		volumeName  = "updateConfigVolume"
+	)
+	orchestrator := getOrchestrator()
+	defer orchestrator.Stop()
+	addBackendStorageClass(t, orchestrator, backendName, scName)
+	_, err := orchestrator.AddVolume(generateVolumeConfig(volumeName, 50,
+		scName, config.File))
+	if err != nil {
+		t.Fatal("Unable to add volume: ", err)
+	}
+	originalBackend := orchestrator.backends[backendName]
+
+	// The new config offers SSDs, so it no longer satisfies the storage
+	// class.  Since fake driver instances don't share state, the volume will
+	// also be orphaned.
+	configJSON, err := fakedriver.NewFakeStorageDriverConfigJSON(
+		backendName,
+		config.File,
+		map[string]*fake.StoragePool{
+			"primary": {
+				Attrs: map[string]sa.Offer{
+					sa.Media:            sa.NewStringOffer("ssd"),
+					sa.ProvisioningType: sa.NewStringOffer("thick", "thin"),
+					sa.TestingAttribute: sa.NewBoolOffer(true),
+				},
+				Bytes: 100 * 1024 * 1024 * 1024,
+			},
+		},
+	)
+	if err != nil {
+		t.Fatal("Unable to create mock driver config JSON: ", err)
+	}
+
+	update, err := orchestrator.UpdateBackend(backendName, configJSON, true)
+	if err != nil {
+		t.Fatal("Unable to evaluate backend update: ", err)
+	}
+	if len(update.ConfigChanges) == 0 {
+		t.Error("Dry run reported no config changes.")
+	}
+	if len(update.StorageClasses) != 0 {
+		t.Errorf("Updated backend should satisfy no storage classes; got %v",
+			update.StorageClasses)
+	}
+	if len(update.OrphanedVolumes) != 1 || update.OrphanedVolumes[0] != volumeName {
+		t.Errorf("Wrong orphaned volumes; expected [%s], got %v", volumeName,
+			update.OrphanedVolumes)
+	}
+	if orchestrator.backends[backendName] != originalBackend {
+		t.Error("Dry run replaced the backend.")
+	}
+	if orchestrator.volumes[volumeName].Orphaned {
+		t.Error("Dry run orphaned the volume.")
+	}
+	if pools := orchestrator.storageClasses[scName].ConstructExternal().StoragePools; len(pools[backendName]) == 0 {
+		t.Error("Dry run removed the backend from the storage class.")
+	}
+
+	update, err = orchestrator.UpdateBackend(backendName, configJSON, false)
+	if err != nil {
+		t.Fatal("Unable to update backend: ", err)
+	}
+	if update.DryRun {
+		t.Error("Update reported as a dry run.")
+	}
+	if orchestrator.backends[backendName] == originalBackend {
+		t.Error("Backend not replaced.")
+	}
+	if _, ok := orchestrator.backends[backendName].Volumes[volumeName]; !ok {
+		t.Error("Volume not moved to the updated backend.")
+	}
+	if !orchestrator.volumes[volumeName].Orphaned {
+		t.Error("Volume not marked as orphaned.")
+	}
+	storedVolume, err := orchestrator.storeClient.GetVolume(volumeName)
+	if err != nil {
+		t.Fatal("Unable to retrieve volume from the backing store: ", err)
+	}
+	if !storedVolume.Orphaned {
+		t.Error("Orphaned volume not updated in the backing store.")
+	}
+	if pools := orchestrator.storageClasses[scName].ConstructExternal().StoragePools; len(pools[backendName]) != 0 {
+		t.Error("Storage class still uses the updated backend.")
+	}
+	storedBackend, err := orchestrator.storeClient.GetBackend(backendName)
+	if err != nil {
+		t.Fatal("Unable to retrieve backend from the backing store: ", err)
+	}
+	media := storedBackend.Config.FakeStorageDriverConfig.Pools["primary"].Attrs[sa.Media]
+	if !media.Matches(sa.NewStringRequest("ssd")) {
+		t.Error("Updated config not saved in the backing store.")
+	}
+
+	if _, err = orchestrator.UpdateBackend("missingBackend", configJSON,
+		false); err == nil {
+		t.Error("Updating a nonexistent backend should have failed.")
+	}
+	otherConfigJSON, err := fakedriver.NewFakeStorageDriverConfigJSON(
+		"otherBackend", config.File, map[string]*fake.StoragePool{})
+	if err != nil {
+		t.Fatal("Unable to create mock driver config JSON: ", err)
+	}
+	if _, err = orchestrator.UpdateBackend(backendName, otherConfigJSON,
+		false); err == nil {
+		t.Error("Updating a backend with another backend's config should " +
+			"have failed.")
+	}
+	cleanup(t, orchestrator)
+}
+
+func TestUpdateStorageClass(t *testing.T) {
+	const (
+		backendName = "updateSCBackend"
+		scName      = "updateSC"
+	)
+	orchestrator := getOrchestrator()
+	defer orchestrator.Stop()
+	addBackendStorageClass(t, orchestrator, backendName, scName)
+	pool := orchestrator.backends[backendName].Storage["primary"]
+	if len(pool.StorageClasses) != 1 || pool.StorageClasses[0] != scName {
+		t.Fatalf("Wrong storage classes for pool before update; got %v",
+			pool.StorageClasses)
+	}
+
+	// The backend only offers HDDs, so the updated class no longer matches it.
+	ssdConfig := &storageclass.Config{
+		Name: scName,
+		Attributes: map[string]sa.Request{
+			sa.Media:            sa.NewStringRequest("ssd"),
+			sa.TestingAttribute: sa.NewBoolRequest(true),
+		},
+	}
+	sc, err := orchestrator.UpdateStorageClass(ssdConfig)
+	if err != nil {
+		t.Fatal("Unable to update storage class: ", err)
+	}
+	if len(sc.StoragePools) != 0 {
+		t.Errorf("Updated storage class should match no pools; got %v",
+			sc.StoragePools)
+	}
+	if len(pool.StorageClasses) != 0 {
+		t.Errorf("Pool still lists the updated storage class: %v",
+			pool.StorageClasses)
+	}
+	storedSC, err := orchestrator.storeClient.GetStorageClass(scName)
+	if err != nil {
+		t.Fatal("Unable to retrieve storage class from the backing store: ", err)
+	}
+	if !reflect.DeepEqual(storedSC.Config, ssdConfig) {
+		t.Errorf("Updated config not saved in the backing store; got %v",
+			storedSC.Config)
+	}
+
+	_, err = orchestrator.UpdateStorageClass(&storageclass.Config{
+		Name: scName,
+		Attributes: map[string]sa.Request{
+			sa.Media: sa.NewStringRequest("hdd"),
+		},
+	})
+	if err != nil {
+		t.Fatal("Unable to update storage class: ", err)
+	}
+	if len(pool.StorageClasses) != 1 || pool.StorageClasses[0] != scName {
+		t.Errorf("Pool should list the updated storage class; got %v",
+			pool.StorageClasses)
+	}
+	if pools := orchestrator.GetStorageClass(scName).StoragePools; len(pools[backendName]) != 1 {
+		t.Errorf("Updated storage class should match the backend; got %v",
+			pools)
+	}
+
+	if _, err = orchestrator.UpdateStorageClass(&storageclass.Config{
+		Name: "missingSC",
+	}); err == nil {
+		t.Error("Updating a nonexistent storage class should have failed.")
+	}
+	if _, err = orchestrator.storeClient.GetStorageClass("missingSC"); err == nil {
+		t.Error("Updating a nonexistent storage class saved it in the backing store.")
+	}
+	cleanup(t, orchestrator)
+}
+
+func TestDeleteBackendWithVolumes(t *testing.T) {
+	const (
+		backendName = "deletingBackend"
+		scName      = "deletingBackendSC"
+		volumeName  = "deletingBackendVolume"
+	)
+	orchestrator := getOrchestrator()
+	defer orchestrator.Stop()
+	addBackendStorageClass(t, orchestrator, backendName, scName)
+	_, err := orchestrator.AddVolume(generateVolumeConfig(volumeName, 50,
+		scName, config.File))
+	if err != nil {
+		t.Fatal("Unable to add volume: ", err)
+	}
+
+	found, err := orchestrator.DeleteBackend(backendName)
+	if !found || err != nil {
+		t.Fatalf("Unable to delete backend; found: %t, error: %v", found, err)
+	}
+	backend := orchestrator.GetBackend(backendName)
+	if backend == nil {
+		t.Fatal("Backend with volumes removed from memory.")
+	}
+	if backend.State != storage.BackendStateDeleting || backend.Online {
+		t.Errorf("Wrong backend state; expected offline and %s, got "+
+			"online: %t, state: %s", storage.BackendStateDeleting,
+			backend.Online, backend.State)
+	}
+	listed := false
+	for _, b := range orchestrator.ListBackends() {
+		if b.Name == backendName {
+			listed = true
+		}
+	}
+	if !listed {
+		t.Error("Deleting backend not listed.")
+	}
+	persistentBackend, err := orchestrator.storeClient.GetBackend(backendName)
+	if err != nil {
+		t.Fatal("Unable to retrieve backend from the backing store: ", err)
+	}
+	if persistentBackend.State != storage.BackendStateDeleting {
+		t.Errorf("Wrong backend state in the backing store; expected %s, "+
+			"got %s", storage.BackendStateDeleting, persistentBackend.State)
+	}
+	if _, err = orchestrator.UpdateBackend(backendName, "{}", false); err == nil {
+		t.Error("Updating a deleting backend should have failed.")
+	}
+	configJSON, err := persistentBackend.MarshalConfig()
+	if err != nil {
+		t.Fatal("Unable to marshal backend config: ", err)
+	}
+	if _, err = orchestrator.AddStorageBackend(configJSON); err == nil {
+		t.Error("Re-adding a deleting backend should have failed.")
+	}
+	if backend = orchestrator.GetBackend(backendName); backend.State != storage.BackendStateDeleting {
+		t.Errorf("Re-adding a deleting backend changed its state to %s", backend.State)
+	}
+
+	// Records written before backends had a state are still recognized as
+	// being deleted.
+	orchestrator.mutex.Lock()
+	legacyBackend := *orchestrator.backends[backendName]
+	legacyBackend.State = ""
+	err = orchestrator.storeClient.UpdateBackend(&legacyBackend)
+	orchestrator.mutex.Unlock()
+	if err != nil {
+		t.Fatal("Unable to update backend in the backing store: ", err)
+	}
+	newOrchestrator := getOrchestrator()
+	defer newOrchestrator.Stop()
+	if bootstrappedBackend := newOrchestrator.GetBackend(backendName); bootstrappedBackend == nil {
+		t.Error("Deleting backend with volumes removed during bootstrap.")
+	} else if bootstrappedBackend.State != storage.BackendStateDeleting {
+		t.Errorf("Wrong backend state after bootstrap; expected %s, got %s",
+			storage.BackendStateDeleting, bootstrappedBackend.State)
+	}
+
+	if _, err = orchestrator.DeleteVolume(volumeName); err != nil {
+		t.Fatal("Unable to delete volume: ", err)
+	}
+	if orchestrator.GetBackend(backendName) != nil {
+		t.Error("Backend not removed along with its last volume.")
+	}
+	if _, err = orchestrator.storeClient.GetBackend(backendName); err == nil {
+		t.Error("Backend not removed from the backing store along with its " +
+			"last volume.")
+	}
+	cleanup(t, orchestrator)
+}
+
+func TestImportVolume(t *testing.T) {
+	const (
+		backendName = "importBackend"
+		scName      = "importBackendSC"
+	)
+	orchestrator := getOrchestrator()
+	defer orchestrator.Stop()
+	addBackendStorageClass(t, orchestrator, backendName, scName)
+	f := orchestrator.backends[backendName].Driver.(*fakedriver.StorageDriver)
+	size := uint64(20 * 1024 * 1024 * 1024)
+	for _, name := range []string{"legacy1", "legacy2"} {
+		f.Volumes[name] = fake.Volume{Name: name, PoolName: "primary", SizeBytes: size}
+	}
+
+	for _, c := range []struct {
+		volumeName   string
+		originalName string
+		rename       bool
+		internalName string
+	}{
+		{"importedVolume", "legacy1", false, "legacy1"},
+		{"renamedVolume", "legacy2", true, f.GetInternalVolumeName("renamedVolume")},
+	} {
+		vol, err := orchestrator.ImportVolume(backendName, c.originalName,
+			&storage.VolumeConfig{Name: c.volumeName, StorageClass: scName},
+			c.rename)
+		if err != nil {
+			t.Errorf("%s: unable to import volume: %v", c.volumeName, err)
+			continue
+		}
+		if vol.Config.InternalName != c.internalName {
+			t.Errorf("%s: wrong internal name; expected %s, got %s",
+				c.volumeName, c.internalName, vol.Config.InternalName)
+		}
+		if vol.Config.ImportOriginalName != c.originalName {
+			t.Errorf("%s: wrong original name; expected %s, got %s",
+				c.volumeName, c.originalName, vol.Config.ImportOriginalName)
+		}
+		if vol.Config.Size != fmt.Sprintf("%d", size) {
+			t.Errorf("%s: wrong size; expected %d, got %s", c.volumeName,
+				size, vol.Config.Size)
+		}
+		if vol.Backend != backendName || vol.Pool != "primary" {
+			t.Errorf("%s: wrong placement; expected %s/primary, got %s/%s",
+				c.volumeName, backendName, vol.Backend, vol.Pool)
+		}
+		if _, ok := f.Volumes[c.internalName]; !ok {
+			t.Errorf("%s: volume %s not found on the backend", c.volumeName,
+				c.internalName)
+		}
+		if _, ok := orchestrator.backends[backendName].Volumes[c.volumeName]; !ok {
+			t.Errorf("%s: volume not added to its backend", c.volumeName)
+		}
+		storedVolume, err := orchestrator.storeClient.GetVolume(c.volumeName)
+		if err != nil {
+			t.Errorf("%s: unable to retrieve volume from the backing store: %v",
+				c.volumeName, err)
+		} else if storedVolume.Config.InternalName != c.internalName {
+			t.Errorf("%s: wrong internal name in the backing store; expected "+
+				"%s, got %s", c.volumeName, c.internalName,
+				storedVolume.Config.InternalName)
+		}
+	}
+	if _, ok := f.Volumes["legacy2"]; ok {
+		t.Error("Renamed volume still present under its original name.")
+	}
+
+	// Volumes may only be imported once, and only if they exist.
+	if _, err := orchestrator.ImportVolume(backendName, "legacy1",
+		&storage.VolumeConfig{Name: "importedTwice"}, false); err == nil {
+		t.Error("Importing a managed volume should have failed.")
+	}
+	if _, err := orchestrator.ImportVolume(backendName, "missing",
+		&storage.VolumeConfig{Name: "missingVolume"}, false); err == nil {
+		t.Error("Importing a nonexistent volume should have failed.")
+	}
+	if _, err := orchestrator.ImportVolume("missingBackend", "legacy1",
+		&storage.VolumeConfig{Name: "missingBackendVolume"}, false); err == nil {
+		t.Error("Importing from a nonexistent backend should have failed.")
+	}
+	if orchestrator.GetVolume("importedTwice") != nil ||
+		orchestrator.GetVolume("missingVolume") != nil {
+		t.Error("Failed import left a volume behind.")
+	}
+
+	if txns, err := orchestrator.storeClient.GetVolumeTransactions(); err != nil {
+		t.Errorf("Unable to retrieve transactions from backing store: %v",
+			err)
+	} else if len(txns) > 0 {
+		t.Error("Transaction not cleared from the backing store.")
+	}
+	cleanup(t, orchestrator)
+}
+
+func TestImportVolumeRollback(t *testing.T) {
+	const (
+		backendName  = "importRollbackBackend"
+		scName       = "importRollbackBackendSC"
+		volumeName   = "interruptedImport"
+		originalName = "legacy3"
+	)
+	orchestrator := getOrchestrator()
+	defer orchestrator.Stop()
+	addBackendStorageClass(t, orchestrator, backendName, scName)
+	f := orchestrator.backends[backendName].Driver.(*fakedriver.StorageDriver)
+	internalName := f.GetInternalVolumeName(volumeName)
+
+	// Simulate an import that renamed the volume but was interrupted before
+	// the volume was recorded.
+	f.Volumes[internalName] = fake.Volume{Name: internalName, PoolName: "primary", SizeBytes: 1024}
+	volTxn := &persistentstore.VolumeTransaction{
+		Config: &storage.VolumeConfig{
+			Name:               volumeName,
+			InternalName:       internalName,
+			ImportOriginalName: originalName,
+		},
+		Op: persistentstore.ImportVolume,
+	}
+	if err := orchestrator.storeClient.AddVolumeTransaction(volTxn); err != nil {
+		t.Fatal("Unable to add volume transaction: ", err)
+	}
+	if err := orchestrator.rollBackTransaction(volTxn); err != nil {
+		t.Fatal("Unable to roll back volume transaction: ", err)
+	}
+	if _, ok := f.Volumes[originalName]; !ok {
+		t.Error("Volume not renamed back to its original name.")
+	}
+	if _, ok := f.Volumes[internalName]; ok {
+		t.Error("Volume still present under the name the import gave it.")
+	}
+	if txns, err := orchestrator.storeClient.GetVolumeTransactions(); err != nil {
+		t.Errorf("Unable to retrieve transactions from backing store: %v", err)
+	} else if len(txns) > 0 {
+		t.Error("Transaction not cleared from the backing store.")
+	}
+	cleanup(t, orchestrator)
+}
+
+func TestPoolCapacity(t *testing.T) {
+	const (
+		backendName = "capacityBackend"
+		scName      = "capacityBackendSC"
+		volumeName  = "capacityVolume"
+	)
+	orchestrator := getOrchestrator()
+	defer orchestrator.Stop()
+	addBackendStorageClass(t, orchestrator, backendName, scName)
+	pool := orchestrator.backends[backendName].Storage["primary"]
+	initialBytes := uint64(100 * 1024 * 1024 * 1024)
+	volumeBytes := uint64(50 * 1024 * 1024 * 1024)
+	if pool.Capacity == nil || pool.Capacity.AvailableBytes != initialBytes {
+		t.Fatalf("Wrong initial pool capacity; expected %d, got %+v",
+			initialBytes, pool.Capacity)
+	}
+
+	if _, err := orchestrator.AddVolume(generateVolumeConfig(volumeName, 50,
+		scName, config.File)); err != nil {
+		t.Fatal("Unable to add volume: ", err)
+	}
+	if pool.Capacity.AvailableBytes != initialBytes-volumeBytes {
+		t.Errorf("Volume not deducted from pool capacity; expected %d, got %d",
+			initialBytes-volumeBytes, pool.Capacity.AvailableBytes)
+	}
+
+	// Refreshing picks up space consumed outside of Trident.
+	f := orchestrator.backends[backendName].Driver.(*fakedriver.StorageDriver)
+	f.Config.Pools["primary"].Bytes -= volumeBytes
+	orchestrator.refreshPoolCapacity()
+	if pool.Capacity.AvailableBytes != initialBytes-2*volumeBytes {
+		t.Errorf("Wrong pool capacity after refresh; expected %d, got %d",
+			initialBytes-2*volumeBytes, pool.Capacity.AvailableBytes)
+	}
+	if scs := pool.StorageClasses; len(scs) != 1 || scs[0] != scName {
+		t.Errorf("Refresh changed the pool's storage classes to %v", scs)
+	}
+
+	if _, err := orchestrator.AddStorageClass(&storageclass.Config{
+		Name:          "badPolicySC",
+		PoolSelection: "fullest",
+	}); err == nil {
+		t.Error("Storage class with an unknown pool selection policy was added.")
+	}
+	cleanup(t, orchestrator)
+}
+
+func TestConcurrentVolumeOperations(t *testing.T) {
+	const (
+		backendName = "concurrentBackend"
+		scName      = "concurrentBackendSC"
+		volumeCount = 20
+	)
+	orchestrator := getOrchestrator()
+	defer orchestrator.Stop()
+	addBackendStorageClass(t, orchestrator, backendName, scName)
+
+	volumeNames := make([]string, volumeCount)
+	for i := range volumeNames {
+		volumeNames[i] = fmt.Sprintf("concurrentVolume%d", i)
+	}
+
+	// Create the volumes in parallel while other goroutines read the
+	// orchestrator's state.
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*volumeCount)
+	for _, name := range volumeNames {
+		wg.Add(2)
+		go func(name string) {
+			defer wg.Done()
+			if _, err := orchestrator.AddVolume(generateVolumeConfig(name, 1,
+				scName, config.File)); err != nil {
+				errs <- fmt.Errorf("unable to add volume %s: %v", name, err)
+			}
+		}(name)
+		go func(name string) {
+			defer wg.Done()
+			orchestrator.GetVolume(name)
+			orchestrator.ListVolumes()
+			orchestrator.ListBackends()
+		}(name)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	if volumes := orchestrator.ListVolumes(); len(volumes) != volumeCount {
+		t.Errorf("Expected %d volumes, got %d", volumeCount, len(volumes))
+	}
+	if backend := orchestrator.GetBackend(backendName); len(backend.Volumes) != volumeCount {
+		t.Errorf("Expected %d volumes on backend, got %d", volumeCount, len(backend.Volumes))
+	}
+
+	// Resize, snapshot and delete the volumes in parallel, operating on
+	// each volume more than once.
+	errs = make(chan error, 3*volumeCount)
+	for _, name := range volumeNames {
+		wg.Add(3)
+		go func(name string) {
+			defer wg.Done()
+			if err := orchestrator.ResizeVolume(name, "2Gi"); err != nil {
+				errs <- fmt.Errorf("unable to resize volume %s: %v", name, err)
+			}
+		}(name)
+		go func(name string) {
+			defer wg.Done()
+			if _, err := orchestrator.CreateSnapshot(name, "snap"); err != nil {
+				errs <- fmt.Errorf("unable to snapshot volume %s: %v", name, err)
+			}
+		}(name)
+		go func(name string) {
+			defer wg.Done()
+			orchestrator.ListSnapshots(name)
+		}(name)
+	}
+	wg.Wait()
+	for _, name := range volumeNames {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			if _, err := orchestrator.DeleteVolume(name); err != nil {
+				errs <- fmt.Errorf("unable to delete volume %s: %v", name, err)
+			}
+		}(name)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	if volumes := orchestrator.ListVolumes(); len(volumes) != 0 {
+		t.Errorf("Expected no volumes after deletion, got %d", len(volumes))
+	}
+	if len(orchestrator.snapshots) != 0 {
+		t.Errorf("Expected no snapshots after deletion, got %d", len(orchestrator.snapshots))
+	}
+	if txns, err := orchestrator.storeClient.GetVolumeTransactions(); err != nil {
+		t.Error("Unable to list volume transactions: ", err)
+	} else if len(txns) != 0 {
+		t.Errorf("Expected no volume transactions, got %d", len(txns))
+	}
+	if len(orchestrator.volumeLocks.locks) != 0 || len(orchestrator.backendLocks.locks) != 0 {
+		t.Error("Locks were left behind after all operations completed.")
+	}
+	cleanup(t, orchestrator)
+}
+
+func TestAttachVolumeAccessModes(t *testing.T) {
+	const (
+		backendName = "attachBackend"
+		scName      = "attachBackendSC"
+	)
+	orchestrator := getOrchestrator()
+	defer orchestrator.Stop()
+	addBackendStorageClass(t, orchestrator, backendName, scName)
+	f := orchestrator.backends[backendName].Driver.(*fakedriver.StorageDriver)
+
+	tempDir, err := ioutil.TempDir("", "trident-attach")
+	if err != nil {
+		t.Fatal("Unable to create temporary directory: ", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	for _, test := range []struct {
+		accessMode config.AccessMode
+		multiple   bool
+		readOnly   bool
+	}{
+		{config.ReadWriteOnce, false, false},
+		{config.ReadOnlyMany, true, true},
+		{config.ReadWriteMany, true, false},
+		{config.ModeAny, true, false},
+	} {
+		volumeName := "attach" + string(test.accessMode)
+		volumeConfig := generateVolumeConfig(volumeName, 1, scName, config.File)
+		volumeConfig.AccessMode = test.accessMode
+		if _, err := orchestrator.AddVolume(volumeConfig); err != nil {
+			t.Fatalf("Unable to add volume %s: %v", volumeName, err)
+		}
+		first := filepath.Join(tempDir, volumeName+"-1")
+		second := filepath.Join(tempDir, volumeName+"-2")
+
+		if err := orchestrator.AttachVolume(volumeName, first, nil); err != nil {
+			t.Errorf("%s: unable to attach volume: %v", volumeName, err)
+			continue
+		}
+		if f.Mounts[first] != test.readOnly {
+			t.Errorf("%s: expected read-only %t, got %t", volumeName, test.readOnly, f.Mounts[first])
+		}
+		// Attaching again at the same mount point is a no-op.
+		if err := orchestrator.AttachVolume(volumeName, first, nil); err != nil {
+			t.Errorf("%s: reattaching at the same mount point failed: %v", volumeName, err)
+		}
+
+		err := orchestrator.AttachVolume(volumeName, second, nil)
+		if test.multiple && err != nil {
+			t.Errorf("%s: unable to attach volume a second time: %v", volumeName, err)
+		} else if !test.multiple && err == nil {
+			t.Errorf("%s: second attach should have failed", volumeName)
+		}
+		expected := 1
+		if test.multiple {
+			expected = 2
+		}
+		if attachments := orchestrator.getAttachments(volumeName); len(attachments) != expected {
+			t.Errorf("%s: expected %d attachments, got %v", volumeName, expected, attachments)
+		}
+
+		for _, mountpoint := range []string{first, second} {
+			if err := orchestrator.DetachVolume(volumeName, mountpoint); err != nil {
+				t.Errorf("%s: unable to detach volume from %s: %v", volumeName, mountpoint, err)
+			}
+		}
+		if attachments := orchestrator.getAttachments(volumeName); len(attachments) != 0 {
+			t.Errorf("%s: attachments left after detaching: %v", volumeName, attachments)
+		}
+
+		// Once detached, a ReadWriteOnce volume may be attached elsewhere.
+		if err := orchestrator.AttachVolume(volumeName, second, nil); err != nil {
+			t.Errorf("%s: unable to attach volume after detaching: %v", volumeName, err)
+		}
+		if _, err := orchestrator.DeleteVolume(volumeName); err != nil {
+			t.Errorf("%s: unable to delete volume: %v", volumeName, err)
+		}
+		if _, ok := orchestrator.attachments[volumeName]; ok {
+			t.Errorf("%s: attachments left after deleting volume", volumeName)
+		}
+	}
+	cleanup(t, orchestrator)
+}
+
+func TestMetricsCollector(t *testing.T) {
+	const (
+		backendName = "metricsBackend"
+		scName      = "metricsBackendSC"
+	)
+	orchestrator := getOrchestrator()
+	defer orchestrator.Stop()
+	addBackendStorageClass(t, orchestrator, backendName, scName)
+	for _, name := range []string{"metricsVolume1", "metricsVolume2"} {
+		if _, err := orchestrator.AddVolume(generateVolumeConfig(name, 1, scName, config.File)); err != nil {
+			t.Fatalf("Unable to add volume %s: %v", name, err)
+		}
+	}
+
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(NewMetricsCollector(orchestrator))
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal("Unable to gather metrics: ", err)
+	}
+
+	online, poolVolumes, pools := -1.0, 0.0, 0
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			labels := make(map[string]string)
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["backend"] != backendName {
+				continue
+			}
+			switch family.GetName() {
+			case "trident_backend_online":
+				online = metric.GetGauge().GetValue()
+			case "trident_pool_volumes":
+				pools++
+				poolVolumes += metric.GetGauge().GetValue()
+			}
+		}
+	}
+	if online != 1 {
+		t.Errorf("Expected backend %s to be reported online, got %v", backendName, online)
+	}
+	if expected := len(orchestrator.GetBackend(backendName).Storage); pools != expected {
+		t.Errorf("Expected volume counts for %d pools, got %d", expected, pools)
+	}
+	if poolVolumes != 2 {
+		t.Errorf("Expected 2 volumes across pools, got %v", poolVolumes)
+	}
+	cleanup(t, orchestrator)
+}
+
+func TestFollowerFailover(t *testing.T) {
+	const (
+		backendName  = "followerBackend"
+		scName       = "followerBackendSC"
+		volumeName   = "followerVolume"
+		txnName      = "followerTxnVolume"
+		snapshotName = "followerSnapshot"
+	)
+	leader := getOrchestrator()
+	defer leader.Stop()
+	addBackendStorageClass(t, leader, backendName, scName)
+	if _, err := leader.AddVolume(generateVolumeConfig(volumeName, 1, scName, config.File)); err != nil {
+		t.Fatal("Unable to add volume: ", err)
+	}
+	if _, err := leader.CreateSnapshot(volumeName, snapshotName); err != nil {
+		t.Fatal("Unable to create snapshot: ", err)
+	}
+	// Leave a volume creation unfinished, as if the leader had failed
+	txn := &persistentstore.VolumeTransaction{
+		Config: generateVolumeConfig(txnName, 1, scName, config.File),
+		Op:     persistentstore.AddVolume,
+	}
+	if err := leader.storeClient.AddVolumeTransaction(txn); err != nil {
+		t.Fatal("Unable to add volume transaction: ", err)
+	}
+
+	follower := NewTridentOrchestrator(leader.storeClient)
+	defer follower.Stop()
+	if err := follower.Follow(); err != nil {
+		t.Fatal("Unable to follow the store: ", err)
+	}
+	if !follower.IsFollower() {
+		t.Error("Expected the orchestrator to be following the store.")
+	}
+	if backend := follower.GetBackend(backendName); backend == nil {
+		t.Error("Follower couldn't find backend.")
+	} else if len(backend.Volumes) != 1 || backend.Volumes[0] != volumeName {
+		t.Errorf("Unexpected backend volumes %v", backend.Volumes)
+	}
+	if follower.GetVolume(volumeName) == nil || len(follower.ListVolumes()) != 1 {
+		t.Errorf("Unexpected volumes %v", follower.ListVolumes())
+	}
+	if follower.GetStorageClass(scName) == nil || len(follower.ListStorageClasses()) != 1 {
+		t.Errorf("Unexpected storage classes %v", follower.ListStorageClasses())
+	}
+	if snapshots, err := follower.ListSnapshots(volumeName); err != nil || len(snapshots) != 1 {
+		t.Errorf("Unexpected snapshots %v, error %v", snapshots, err)
+	}
+	if follower.GetSnapshot(volumeName, snapshotName) == nil {
+		t.Error("Follower couldn't find snapshot.")
+	}
+
+	// Once elected, the follower takes over and rolls back the transaction
+	if err := follower.Bootstrap(); err != nil {
+		t.Fatal("Unable to bootstrap the follower: ", err)
+	}
+	if follower.IsFollower() {
+		t.Error("Expected the orchestrator to have stopped following the store.")
+	}
+	if backend := follower.GetBackend(backendName); backend == nil || len(backend.Storage) == 0 {
+		t.Errorf("Unexpected backend %v", backend)
+	}
+	if volTxns, err := follower.storeClient.GetVolumeTransactions(); err != nil || len(volTxns) != 0 {
+		t.Errorf("Unexpected transactions %v, error %v", volTxns, err)
+	}
+	if follower.GetVolume(txnName) != nil {
+		t.Error("Expected the unfinished volume to have been rolled back.")
+	}
+	cleanup(t, follower)
+}
+
+// waitForOperation polls an operation until it finishes.
+func waitForOperation(t *testing.T, o *TridentOrchestrator, id string) *persistentstore.Operation {
+	for i := 0; i < 100; i++ {
+		op := o.GetOperation(id)
+		if op == nil {
+			t.Fatalf("Operation %s was not found.", id)
+		}
+		if op.Done() {
+			return op
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("Operation %s didn't finish.", id)
+	return nil
+}
+
+func TestAsyncOperations(t *testing.T) {
+	const (
+		backendName = "asyncBackend"
+		scName      = "asyncBackendSC"
+		volumeName  = "asyncVolume"
+	)
+	orchestrator := getOrchestrator()
+	defer orchestrator.Stop()
+	addBackendStorageClass(t, orchestrator, backendName, scName)
+
+	op, err := orchestrator.AddVolumeAsync(generateVolumeConfig(volumeName, 1, scName, config.File))
+	if err != nil {
+		t.Fatal("Unable to start adding volume: ", err)
+	}
+	if op.Type != persistentstore.AddVolumeOperation || op.Target != volumeName {
+		t.Errorf("Unexpected operation %v", op)
+	}
+	if op = waitForOperation(t, orchestrator, op.ID); op.State != persistentstore.OperationSucceeded {
+		t.Errorf("Expected the operation to succeed, got %v", op)
+	}
+	if orchestrator.GetVolume(volumeName) == nil {
+		t.Error("Couldn't find the volume added in the background.")
+	}
+	if _, err = orchestrator.AddVolumeAsync(generateVolumeConfig(volumeName, 1, scName, config.File)); err == nil {
+		t.Error("Expected an error starting to add an existing volume.")
+	}
+
+	// Failures are reported through the operation
+	failed, err := orchestrator.AddVolumeAsync(generateVolumeConfig("asyncFailed", 1, "nonexistentSC", config.File))
+	if err != nil {
+		t.Fatal("Unable to start adding volume: ", err)
+	}
+	if failed = waitForOperation(t, orchestrator, failed.ID); failed.State != persistentstore.OperationFailed ||
+		failed.Error == "" {
+		t.Errorf("Expected the operation to fail, got %v", failed)
+	}
+	if ops := orchestrator.ListOperations(); len(ops) != 2 {
+		t.Errorf("Unexpected operations %v", ops)
+	}
+
+	// Operations outlive a restart, and those that were running fail
+	interrupted := &persistentstore.Operation{
+		ID:      "interrupted",
+		Type:    persistentstore.ImportVolumeOperation,
+		Target:  "asyncInterrupted",
+		State:   persistentstore.OperationRunning,
+		Created: time.Now().UTC().Format(time.RFC3339),
+		Updated: time.Now().UTC().Format(time.RFC3339),
+	}
+	if err = orchestrator.storeClient.AddOperation(interrupted); err != nil {
+		t.Fatal("Unable to add operation: ", err)
+	}
+	expired := &persistentstore.Operation{
+		ID:      "expired",
+		Type:    persistentstore.AddVolumeOperation,
+		Target:  "asyncExpired",
+		State:   persistentstore.OperationSucceeded,
+		Created: "2018-01-01T00:00:00Z",
+		Updated: "2018-01-01T00:00:00Z",
+	}
+	if err = orchestrator.storeClient.AddOperation(expired); err != nil {
+		t.Fatal("Unable to add operation: ", err)
+	}
+
+	restarted := NewTridentOrchestrator(orchestrator.storeClient)
+	defer restarted.Stop()
+	if err = restarted.Bootstrap(); err != nil {
+		t.Fatal("Unable to bootstrap orchestrator: ", err)
+	}
+	if recovered := restarted.GetOperation(op.ID); recovered == nil || *recovered != *op {
+		t.Errorf("Unexpected operation %v after restart", recovered)
+	}
+	if recovered := restarted.GetOperation(interrupted.ID); recovered == nil ||
+		recovered.State != persistentstore.OperationFailed {
+		t.Errorf("Expected the interrupted operation to have failed, got %v", recovered)
+	}
+	if restarted.GetOperation(expired.ID) != nil {
+		t.Error("Expected the expired operation to have been pruned.")
+	}
+	if _, err = restarted.storeClient.GetOperation(expired.ID); !persistentstore.MatchKeyNotFoundErr(err) {
+		t.Errorf("Expected the expired operation to have been deleted, got %v", err)
+	}
+	cleanup(t, restarted)
+}
+
+func TestReconcile(t *testing.T) {
+	const (
+		backendName = "reconcileBackend"
+		scName      = "reconcileBackendSC"
+	)
+	orchestrator := getOrchestrator()
+	defer orchestrator.Stop()
+	addBackendStorageClass(t, orchestrator, backendName, scName)
+	f := orchestrator.backends[backendName].Driver.(*fakedriver.StorageDriver)
+
+	internalNames := make(map[string]string)
+	for _, name := range []string{"reconcileIntact", "reconcileDeleted", "reconcileResized"} {
+		vol, err := orchestrator.AddVolume(generateVolumeConfig(name, 1, scName, config.File))
+		if err != nil {
+			t.Fatalf("Unable to add volume %s: %v", name, err)
+		}
+		internalNames[name] = vol.Config.InternalName
+	}
+	if report, err := orchestrator.Reconcile(false); err != nil || len(report.Drifts) != 0 {
+		t.Fatalf("Expected no drift, got %v, %v", report, err)
+	}
+
+	// Change the backend behind Trident's back
+	if err := f.Destroy(internalNames["reconcileDeleted"]); err != nil {
+		t.Fatal("Unable to destroy volume: ", err)
+	}
+	resizedBytes := uint64(2 * 1024 * 1024 * 1024)
+	if err := f.Resize(internalNames["reconcileResized"], resizedBytes); err != nil {
+		t.Fatal("Unable to resize volume: ", err)
+	}
+	if err := f.Create("reconcileUnknown", 1024*1024*1024,
+		map[string]string{fakedriver.FakePoolAttribute: "primary"}); err != nil {
+		t.Fatal("Unable to create volume: ", err)
+	}
+
+	expected := []storage.VolumeDrift{
+		{
+			Type:         storage.DriftMissingVolume,
+			Backend:      backendName,
+			Volume:       "reconcileDeleted",
+			InternalName: internalNames["reconcileDeleted"],
+			RecordedSize: "1073741824",
+		},
+		{
+			Type:         storage.DriftSizeMismatch,
+			Backend:      backendName,
+			Volume:       "reconcileResized",
+			InternalName: internalNames["reconcileResized"],
+			RecordedSize: "1073741824",
+			ActualSize:   "2147483648",
+		},
+		{
+			Type:         storage.DriftUnknownVolume,
+			Backend:      backendName,
+			InternalName: "reconcileUnknown",
+			ActualSize:   "1073741824",
+		},
+	}
+	sort.Slice(expected, func(i, j int) bool { return expected[i].InternalName < expected[j].InternalName })
+
+	// Reconciling without repair only reports the drift
+	report, err := orchestrator.Reconcile(false)
+	if err != nil {
+		t.Fatal("Unable to reconcile: ", err)
+	}
+	if !reflect.DeepEqual(report.Drifts, expected) {
+		t.Errorf("Wrong drift; expected %v, got %v", expected, report.Drifts)
+	}
+	if orchestrator.GetReconcileReport() != report {
+		t.Error("Expected the last report to be kept.")
+	}
+	if vol := orchestrator.GetVolume("reconcileDeleted"); vol.Orphaned {
+		t.Error("Volume was marked as orphaned without repair.")
+	}
+
+	// Repairing updates Trident's records, but never the backend
+	report, err = orchestrator.Reconcile(true)
+	if err != nil {
+		t.Fatal("Unable to reconcile: ", err)
+	}
+	for i := range expected {
+		expected[i].Repaired = expected[i].Type != storage.DriftUnknownVolume
+	}
+	if !reflect.DeepEqual(report.Drifts, expected) {
+		t.Errorf("Wrong drift; expected %v, got %v", expected, report.Drifts)
+	}
+	if vol := orchestrator.GetVolume("reconcileDeleted"); !vol.Orphaned {
+		t.Error("Missing volume wasn't marked as orphaned.")
+	}
+	if vol := orchestrator.GetVolume("reconcileResized"); vol.Config.Size != "2147483648" {
+		t.Errorf("Resized volume's size wasn't updated; got %s", vol.Config.Size)
+	}
+	persistentVol, err := orchestrator.storeClient.GetVolume("reconcileResized")
+	if err != nil {
+		t.Fatal("Unable to get volume from the store: ", err)
+	}
+	if persistentVol.Config.Size != "2147483648" {
+		t.Errorf("Resized volume's size wasn't saved; got %s", persistentVol.Config.Size)
+	}
+	if _, ok := f.Volumes["reconcileUnknown"]; !ok {
+		t.Error("Unknown volume was removed from the backend.")
+	}
+
+	// A volume that reappears on its backend is no longer orphaned
+	if err = f.Create(internalNames["reconcileDeleted"], 1024*1024*1024,
+		map[string]string{fakedriver.FakePoolAttribute: "primary"}); err != nil {
+		t.Fatal("Unable to create volume: ", err)
+	}
+	report, err = orchestrator.Reconcile(true)
+	if err != nil {
+		t.Fatal("Unable to reconcile: ", err)
+	}
+	repaired := false
+	for _, drift := range report.Drifts {
+		if drift.Volume == "reconcileDeleted" {
+			repaired = drift.Type == storage.DriftOrphanedVolumePresent && drift.Repaired
+		}
+	}
+	if !repaired || len(report.Drifts) != 2 {
+		t.Errorf("Expected the reappeared volume to be repaired, got %v", report.Drifts)
+	}
+	if vol := orchestrator.GetVolume("reconcileDeleted"); vol.Orphaned {
+		t.Error("Reappeared volume is still orphaned.")
+	}
+	cleanup(t, orchestrator)
+}
+
+func TestCollectOrphans(t *testing.T) {
+	const (
+		backendName = "orphanBackend"
+		scName      = "orphanBackendSC"
+		volumeName  = "orphanRecorded"
+		orphanName  = "orphanUnrecorded"
+	)
+	orchestrator := getOrchestrator()
+	defer orchestrator.Stop()
+	addBackendStorageClass(t, orchestrator, backendName, scName)
+	f := orchestrator.backends[backendName].Driver.(*fakedriver.StorageDriver)
+	vol, err := orchestrator.AddVolume(generateVolumeConfig(volumeName, 1, scName, config.File))
+	if err != nil {
+		t.Fatal("Unable to add volume: ", err)
+	}
+	if err = f.Create(orphanName, 1024*1024*1024,
+		map[string]string{fakedriver.FakePoolAttribute: "primary"}); err != nil {
+		t.Fatal("Unable to create volume: ", err)
+	}
+
+	// Orphans are only reported by default
+	orphans, err := orchestrator.CollectOrphans()
+	if err != nil {
+		t.Fatal("Unable to collect orphans: ", err)
+	}
+	if len(orphans) != 1 || orphans[0].InternalName != orphanName || orphans[0].Backend != backendName ||
+		orphans[0].Policy != drivers.OrphanPolicyReport {
+		t.Fatalf("Unexpected orphans %v", orphans)
+	}
+	firstSeen := orphans[0].FirstSeen
+	if _, ok := f.Volumes[orphanName]; !ok {
+		t.Error("Orphan was deleted without the delete policy.")
+	}
+
+	// Orphans are deleted once they outlast the grace period
+	f.Config.OrphanPolicy = drivers.OrphanPolicyDelete
+	f.Config.OrphanGracePeriod = "1h"
+	if orphans, err = orchestrator.CollectOrphans(); err != nil {
+		t.Fatal("Unable to collect orphans: ", err)
+	}
+	if len(orphans) != 1 || orphans[0].FirstSeen != firstSeen {
+		t.Errorf("Unexpected orphans %v", orphans)
+	}
+	if _, ok := f.Volumes[orphanName]; !ok {
+		t.Error("Orphan was deleted within the grace period.")
+	}
+	f.Config.OrphanGracePeriod = "0s"
+	if orphans, err = orchestrator.CollectOrphans(); err != nil {
+		t.Fatal("Unable to collect orphans: ", err)
+	}
+	if len(orphans) != 0 {
+		t.Errorf("Unexpected orphans %v", orphans)
+	}
+	if _, ok := f.Volumes[orphanName]; ok {
+		t.Error("Orphan wasn't deleted.")
+	}
+	if _, ok := f.Volumes[vol.Config.InternalName]; !ok {
+		t.Error("Recorded volume was deleted.")
+	}
+
+	audit := orchestrator.GetOrphanAudit()
+	if len(audit) != 2 || audit[0].Action != storage.OrphanDetected || audit[1].Action != storage.OrphanDeleted ||
+		audit[1].InternalName != orphanName {
+		t.Errorf("Unexpected audit trail %v", audit)
+	}
+
+	// Orphans are neither reported nor deleted with the ignore policy
+	f.Config.OrphanPolicy = drivers.OrphanPolicyIgnore
+	if err = f.Create(orphanName, 1024*1024*1024,
+		map[string]string{fakedriver.FakePoolAttribute: "primary"}); err != nil {
+		t.Fatal("Unable to create volume: ", err)
+	}
+	if orphans, err = orchestrator.CollectOrphans(); err != nil || len(orphans) != 0 {
+		t.Errorf("Unexpected orphans %v, %v", orphans, err)
+	}
+	if _, ok := f.Volumes[orphanName]; !ok {
+		t.Error("Orphan was deleted with the ignore policy.")
+	}
+	cleanup(t, orchestrator)
+}
@@ -15,6 +15,7 @@ import (
 
 	"github.com/netapp/trident/config"
 	"github.com/netapp/trident/frontend"
+	"github.com/netapp/trident/persistent_store"
 	"github.com/netapp/trident/storage"
 	"github.com/netapp/trident/storage_class"
 	drivers "github.com/netapp/trident/storage_drivers"
@@ -50,6 +51,8 @@ type MockOrchestrator struct {
 	mockBackends   map[string]*mockBackend
 	storageClasses map[string]*storageclass.StorageClass
 	volumes        map[string]*storage.Volume
+	snapshots      map[string]*storage.SnapshotPersistent
+	operations     map[string]*persistentstore.Operation
 	mutex          *sync.Mutex
 }
 
@@ -57,6 +60,16 @@ func (m *MockOrchestrator) Bootstrap() error {
 	return nil
 }
 
+func (m *MockOrchestrator) Stop() {}
+
+func (m *MockOrchestrator) Follow() error {
+	return nil
+}
+
+func (m *MockOrchestrator) IsFollower() bool {
+	return false
+}
+
 func (m *MockOrchestrator) AddFrontend(f frontend.Plugin) {
 	// NOP for the time being, since users of MockOrchestrator don't need this
 }
@@ -73,6 +86,7 @@ func (m *MockOrchestrator) AddStorageBackend(configJSON string) (*storage.Backen
 		Name:    fmt.Sprintf("mock-%d", len(m.backends)),
 		Driver:  nil,
 		Online:  true,
+		State:   storage.BackendStateOnline,
 		Storage: make(map[string]*storage.Pool),
 	}
 	mock := newMockBackend(backend.GetProtocol())
@@ -93,6 +107,7 @@ func (m *MockOrchestrator) addMockBackend(
 		Name:    name,
 		Driver:  nil,
 		Online:  true,
+		State:   storage.BackendStateOnline,
 		Storage: make(map[string]*storage.Pool),
 	}
 	m.backends[backend.Name] = backend
@@ -138,7 +153,14 @@ func (m *MockOrchestrator) ListBackends() []*storage.BackendExternal {
 	return backends
 }
 
-func (m *MockOrchestrator) OfflineBackend(backend string) (bool, error) {
+func (m *MockOrchestrator) UpdateBackend(
+	backendName, configJSON string, dryRun bool,
+) (*storage.BackendUpdateExternal, error) {
+	// Implement this if it becomes necessary to test.
+	return nil, nil
+}
+
+func (m *MockOrchestrator) DeleteBackend(backend string) (bool, error) {
 	// Implement this if it becomes necessary to test.
 	return false, nil
 }
@@ -205,6 +227,102 @@ func (m *MockOrchestrator) CloneVolume(volumeConfig *storage.VolumeConfig) (*sto
 	return nil, nil
 }
 
+func (m *MockOrchestrator) ImportVolume(
+	backendName, internalName string, volumeConfig *storage.VolumeConfig, rename bool,
+) (*storage.VolumeExternal, error) {
+	// TODO: write this method to enable ImportVolume unit tests
+	return nil, nil
+}
+
+// AddVolumeAsync creates the volume before returning, so the operation it
+// returns has already finished.
+func (m *MockOrchestrator) AddVolumeAsync(volumeConfig *storage.VolumeConfig) (*persistentstore.Operation, error) {
+	_, err := m.AddVolume(volumeConfig)
+	return m.addOperation(persistentstore.AddVolumeOperation, volumeConfig.Name, err), nil
+}
+
+func (m *MockOrchestrator) ImportVolumeAsync(
+	backendName, internalName string, volumeConfig *storage.VolumeConfig, rename bool,
+) (*persistentstore.Operation, error) {
+	_, err := m.ImportVolume(backendName, internalName, volumeConfig, rename)
+	return m.addOperation(persistentstore.ImportVolumeOperation, volumeConfig.Name, err), nil
+}
+
+func (m *MockOrchestrator) addOperation(
+	opType persistentstore.OperationType, target string, err error,
+) *persistentstore.Operation {
+	now := time.Now().UTC().Format(time.RFC3339)
+	op := &persistentstore.Operation{
+		ID:      fmt.Sprintf("mock-%d", len(m.operations)),
+		Type:    opType,
+		Target:  target,
+		State:   persistentstore.OperationSucceeded,
+		Created: now,
+		Updated: now,
+	}
+	if err != nil {
+		op.State = persistentstore.OperationFailed
+		op.Error = err.Error()
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.operations[op.ID] = op
+	opCopy := *op
+	return &opCopy
+}
+
+func (m *MockOrchestrator) GetOperation(id string) *persistentstore.Operation {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	op, ok := m.operations[id]
+	if !ok {
+		return nil
+	}
+	opCopy := *op
+	return &opCopy
+}
+
+func (m *MockOrchestrator) ListOperations() []*persistentstore.Operation {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	ops := make([]*persistentstore.Operation, 0, len(m.operations))
+	for _, op := range m.operations {
+		opCopy := *op
+		ops = append(ops, &opCopy)
+	}
+	return ops
+}
+
+// Reconcile reports no drift, since the mock's backends never change outside
+// of the mock.
+func (m *MockOrchestrator) Reconcile(repair bool) (*storage.ReconcileReport, error) {
+	now := time.Now().UTC().Format(time.RFC3339)
+	return &storage.ReconcileReport{
+		Started:  now,
+		Finished: now,
+		Repair:   repair,
+		Drifts:   make([]storage.VolumeDrift, 0),
+	}, nil
+}
+
+func (m *MockOrchestrator) GetReconcileReport() *storage.ReconcileReport {
+	return nil
+}
+
+// CollectOrphans finds no orphaned volumes, since the mock records every
+// volume it creates.
+func (m *MockOrchestrator) CollectOrphans() ([]*storage.OrphanedVolume, error) {
+	return m.ListOrphans(), nil
+}
+
+func (m *MockOrchestrator) ListOrphans() []*storage.OrphanedVolume {
+	return make([]*storage.OrphanedVolume, 0)
+}
+
+func (m *MockOrchestrator) GetOrphanAudit() []storage.OrphanAuditRecord {
+	return make([]storage.OrphanAuditRecord, 0)
+}
+
 func (m *MockOrchestrator) ValidateVolumes(
 	t *testing.T,
 	expectedConfigs []*storage.VolumeConfig,
@@ -296,6 +414,43 @@ func (m *MockOrchestrator) DeleteVolume(volumeName string) (found bool, err erro
 	return true, nil
 }
 
+func (m *MockOrchestrator) ResizeVolume(volumeName, newSize string) error {
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	volume, ok := m.volumes[volumeName]
+	if !ok {
+		return fmt.Errorf("volume %s not found", volumeName)
+	}
+
+	volume.Config.Size = newSize
+	return nil
+}
+
+func (m *MockOrchestrator) GetVolumeReplication(volumeName string) (*storage.ReplicationStatus, error) {
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if _, ok := m.volumes[volumeName]; !ok {
+		return nil, fmt.Errorf("volume %s not found", volumeName)
+	}
+	// The mock backends don't replicate volumes
+	return nil, fmt.Errorf("volume %s is not replicated", volumeName)
+}
+
+func (m *MockOrchestrator) FailoverVolume(volumeName string) (*storage.VolumeExternal, error) {
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if _, ok := m.volumes[volumeName]; !ok {
+		return nil, fmt.Errorf("volume %s not found", volumeName)
+	}
+	return nil, fmt.Errorf("volume %s is not replicated", volumeName)
+}
+
 func (m *MockOrchestrator) ListVolumesByPlugin(pluginName string) []*storage.VolumeExternal {
 	// Currently returns nil, since this is backend agnostic.  Change this
 	// if we ever have non-apiserver functionality depend on this function.
@@ -318,12 +473,93 @@ func (m *MockOrchestrator) ReloadVolumes() error {
 	return nil
 }
 
+func (m *MockOrchestrator) CreateSnapshot(volumeName, snapshotName string) (*storage.SnapshotExternal, error) {
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if _, ok := m.volumes[volumeName]; !ok {
+		return nil, fmt.Errorf("volume %s not found", volumeName)
+	}
+	snapshotID := storage.MakeSnapshotID(volumeName, snapshotName)
+	if _, ok := m.snapshots[snapshotID]; ok {
+		return nil, fmt.Errorf("snapshot %s already exists for volume %s", snapshotName, volumeName)
+	}
+
+	snapshot := &storage.Snapshot{
+		Name:    snapshotName,
+		Created: time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+	}
+	m.snapshots[snapshotID] = snapshot.ConstructPersistent(volumeName)
+	return snapshot.ConstructExternal(), nil
+}
+
+func (m *MockOrchestrator) GetSnapshot(volumeName, snapshotName string) *storage.SnapshotExternal {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	snapshot, found := m.snapshots[storage.MakeSnapshotID(volumeName, snapshotName)]
+	if !found {
+		return nil
+	}
+	return snapshot.ConstructExternal()
+}
+
+func (m *MockOrchestrator) ListSnapshots(volumeName string) ([]*storage.SnapshotExternal, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if _, ok := m.volumes[volumeName]; !ok {
+		return nil, fmt.Errorf("volume %s not found", volumeName)
+	}
+	snapshots := make([]*storage.SnapshotExternal, 0)
+	for _, snapshot := range m.snapshots {
+		if snapshot.Volume == volumeName {
+			snapshots = append(snapshots, snapshot.ConstructExternal())
+		}
+	}
+	return snapshots, nil
+}
+
+func (m *MockOrchestrator) DeleteSnapshot(volumeName, snapshotName string) (found bool, err error) {
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// Copied verbatim from orchestrator_core so that error returns are identical
+	if _, ok := m.volumes[volumeName]; !ok {
+		return false, fmt.Errorf("volume %s not found", volumeName)
+	}
+	snapshotID := storage.MakeSnapshotID(volumeName, snapshotName)
+	if _, ok := m.snapshots[snapshotID]; !ok {
+		return false, fmt.Errorf("snapshot %s not found for volume %s", snapshotName, volumeName)
+	}
+
+	delete(m.snapshots, snapshotID)
+	return true, nil
+}
+
+func (m *MockOrchestrator) RestoreSnapshot(volumeName, snapshotName string) error {
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if _, ok := m.volumes[volumeName]; !ok {
+		return fmt.Errorf("volume %s not found", volumeName)
+	}
+	if _, ok := m.snapshots[storage.MakeSnapshotID(volumeName, snapshotName)]; !ok {
+		return fmt.Errorf("snapshot %s not found for volume %s", snapshotName, volumeName)
+	}
+	return nil
+}
+
 func NewMockOrchestrator() *MockOrchestrator {
 	return &MockOrchestrator{
 		backends:       make(map[string]*storage.Backend),
 		mockBackends:   make(map[string]*mockBackend),
 		storageClasses: make(map[string]*storageclass.StorageClass),
 		volumes:        make(map[string]*storage.Volume),
+		snapshots:      make(map[string]*storage.SnapshotPersistent),
+		operations:     make(map[string]*persistentstore.Operation),
 		mutex:          &sync.Mutex{},
 	}
 }
@@ -351,6 +587,17 @@ func (m *MockOrchestrator) ListStorageClasses() []*storageclass.External {
 	return ret
 }
 
+func (m *MockOrchestrator) UpdateStorageClass(
+	scConfig *storageclass.Config,
+) (*storageclass.External, error) {
+	sc := storageclass.New(scConfig)
+	if _, ok := m.storageClasses[sc.GetName()]; !ok {
+		return nil, fmt.Errorf("storage class %s not found", sc.GetName())
+	}
+	m.storageClasses[sc.GetName()] = sc
+	return sc.ConstructExternal(), nil
+}
+
 func (m *MockOrchestrator) DeleteStorageClass(scName string) (bool, error) {
 	_, ok := m.storageClasses[scName]
 	if !ok {
@@ -0,0 +1,288 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/persistent_store"
+	"github.com/netapp/trident/storage"
+	drivers "github.com/netapp/trident/storage_drivers"
+)
+
+// SetOrphanCollectionInterval sets how often the orchestrator looks for
+// orphaned volumes on its backends.  An interval of zero disables periodic
+// collection.  It must be called before the orchestrator is bootstrapped.
+func (o *TridentOrchestrator) SetOrphanCollectionInterval(interval time.Duration) {
+	o.orphanInterval = interval
+}
+
+// periodicallyCollectOrphans keeps looking for orphaned volumes.
+func (o *TridentOrchestrator) periodicallyCollectOrphans(stop <-chan struct{}) {
+	ticker := time.NewTicker(o.orphanInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if _, err := o.CollectOrphans(); err != nil {
+				log.Warnf("Unable to collect orphaned volumes: %v", err)
+			}
+		}
+	}
+}
+
+// CollectOrphans looks for the objects on each backend that carry the
+// backend's storage prefix, but that Trident has no record of, and returns
+// them.  What becomes of them depends on the orphan policy of their backend:
+// they are ignored, reported, or deleted once they have gone without a
+// record for the backend's grace period.  As the time an object was first
+// found without a record is kept only in memory, the grace period starts
+// over when Trident restarts.
+func (o *TridentOrchestrator) CollectOrphans() ([]*storage.OrphanedVolume, error) {
+	if config.UsingPassthroughStore {
+		return nil, errors.New("orphaned volumes can't be found without a persistent store, " +
+			"as the backends are the only record of their volumes")
+	}
+
+	o.orphanMutex.Lock()
+	defer o.orphanMutex.Unlock()
+
+	for _, backendName := range o.getBackendNames() {
+		if err := o.collectBackendOrphans(backendName); err != nil {
+			log.WithFields(log.Fields{
+				"backend": backendName,
+			}).Warnf("Unable to collect orphaned volumes: %v", err)
+		}
+	}
+
+	// Forget the orphans of backends that no longer exist
+	o.mutex.Lock()
+	for backendName := range o.orphans {
+		if _, ok := o.backends[backendName]; !ok {
+			delete(o.orphans, backendName)
+		}
+	}
+	o.mutex.Unlock()
+
+	return o.ListOrphans(), nil
+}
+
+// collectBackendOrphans finds the orphaned volumes on a backend and applies
+// the backend's orphan policy to them.
+func (o *TridentOrchestrator) collectBackendOrphans(backendName string) error {
+	backend, unlock := o.lockBackend(backendName)
+	defer unlock()
+	if backend == nil {
+		return nil
+	}
+
+	commonConfig := backend.Driver.GetCommonConfig()
+	policy := commonConfig.OrphanPolicy
+	if policy == "" {
+		policy = drivers.DefaultOrphanPolicy
+	}
+	if policy == drivers.OrphanPolicyIgnore {
+		o.mutex.Lock()
+		delete(o.orphans, backendName)
+		o.mutex.Unlock()
+		return nil
+	}
+	gracePeriod, err := time.ParseDuration(commonConfig.OrphanGracePeriod)
+	if err != nil {
+		gracePeriod, _ = time.ParseDuration(drivers.DefaultOrphanGracePeriod)
+	}
+	prefix := ""
+	if commonConfig.StoragePrefix != nil {
+		prefix = *commonConfig.StoragePrefix
+	}
+
+	found, err := listBackendVolumes(backend)
+	if err != nil {
+		return err
+	}
+
+	// Volumes are recorded after they are created, so a volume created since
+	// the backend was listed either has a transaction or has been recorded.
+	pending, err := o.getPendingInternalNames(backend)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	detected := make([]storage.OrphanAuditRecord, 0)
+	expired := make([]storage.OrphanedVolume, 0)
+	o.mutex.Lock()
+	recorded := o.getRecordedInternalNames()
+	previous := o.orphans[backendName]
+	orphans := make(map[string]*storage.OrphanedVolume)
+	for internalName, volExternal := range found {
+		if !strings.HasPrefix(internalName, prefix) || recorded[internalName] || pending[internalName] {
+			continue
+		}
+		orphan, ok := previous[internalName]
+		if !ok {
+			orphan = &storage.OrphanedVolume{
+				Backend:      backendName,
+				InternalName: internalName,
+				FirstSeen:    now.Format(time.RFC3339),
+			}
+			detected = append(detected, storage.OrphanAuditRecord{
+				Action:       storage.OrphanDetected,
+				Backend:      backendName,
+				InternalName: internalName,
+				Size:         volExternal.Config.Size,
+			})
+		}
+		orphan.Size = volExternal.Config.Size
+		orphan.Policy = policy
+		orphans[internalName] = orphan
+
+		firstSeen, err := time.Parse(time.RFC3339, orphan.FirstSeen)
+		if policy == drivers.OrphanPolicyDelete && err == nil && now.Sub(firstSeen) >= gracePeriod {
+			expired = append(expired, *orphan)
+		}
+	}
+	o.orphans[backendName] = orphans
+	o.mutex.Unlock()
+
+	o.auditOrphans(detected...)
+	for _, orphan := range expired {
+		o.deleteOrphan(backend, &orphan)
+	}
+	return nil
+}
+
+// deleteOrphan deletes an orphaned volume from its backend.  The caller must
+// hold the backend's lock.
+func (o *TridentOrchestrator) deleteOrphan(backend *storage.Backend, orphan *storage.OrphanedVolume) {
+
+	// Make sure the volume wasn't recorded since it was found
+	o.mutex.RLock()
+	recorded := o.getRecordedInternalNames()[orphan.InternalName]
+	o.mutex.RUnlock()
+	if recorded {
+		return
+	}
+
+	record := storage.OrphanAuditRecord{
+		Action:       storage.OrphanDeleted,
+		Backend:      orphan.Backend,
+		InternalName: orphan.InternalName,
+		Size:         orphan.Size,
+	}
+	if err := backend.Driver.Destroy(orphan.InternalName); err != nil {
+		record.Action = storage.OrphanDeleteFailed
+		record.Error = err.Error()
+	} else {
+		o.mutex.Lock()
+		delete(o.orphans[orphan.Backend], orphan.InternalName)
+		o.mutex.Unlock()
+	}
+	o.auditOrphans(record)
+}
+
+// getPendingInternalNames returns the internal names of the volumes on a
+// backend that are being created or imported.  The internal names of volumes
+// being created are derived from their names, as their configurations are
+// still being filled in.
+func (o *TridentOrchestrator) getPendingInternalNames(backend *storage.Backend) (map[string]bool, error) {
+	volTxns, err := o.storeClient.GetVolumeTransactions()
+	if err != nil && !persistentstore.MatchKeyNotFoundErr(err) {
+		return nil, fmt.Errorf("unable to read volume transactions: %v", err)
+	}
+	pending := make(map[string]bool)
+	for _, volTxn := range volTxns {
+		pending[backend.Driver.GetInternalVolumeName(volTxn.Config.Name)] = true
+		if volTxn.Op == persistentstore.ImportVolume {
+			pending[volTxn.Config.ImportOriginalName] = true
+			pending[volTxn.Config.InternalName] = true
+		}
+	}
+	return pending, nil
+}
+
+// getRecordedInternalNames returns the internal names of all of Trident's
+// volumes.  Volumes on every backend are included, since backends may share
+// their storage.  The caller must hold the mutex.
+func (o *TridentOrchestrator) getRecordedInternalNames() map[string]bool {
+	recorded := make(map[string]bool, len(o.volumes))
+	for _, vol := range o.volumes {
+		recorded[vol.Config.InternalName] = true
+	}
+	return recorded
+}
+
+// auditOrphans logs actions taken about orphaned volumes and adds them to the
+// audit trail, which keeps the most recent MaxOrphanAuditRecords actions.
+func (o *TridentOrchestrator) auditOrphans(records ...storage.OrphanAuditRecord) {
+	if len(records) == 0 {
+		return
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	for _, record := range records {
+		record.Time = now
+		fields := log.Fields{
+			"action":       record.Action,
+			"backend":      record.Backend,
+			"internalName": record.InternalName,
+			"size":         record.Size,
+		}
+		switch record.Action {
+		case storage.OrphanDeleted:
+			log.WithFields(fields).Warn("Deleted orphaned volume.")
+		case storage.OrphanDeleteFailed:
+			log.WithFields(fields).Errorf("Unable to delete orphaned volume: %s", record.Error)
+		default:
+			log.WithFields(fields).Warn("Found orphaned volume.")
+		}
+		o.orphanAudit = append(o.orphanAudit, record)
+	}
+	if excess := len(o.orphanAudit) - config.MaxOrphanAuditRecords; excess > 0 {
+		o.orphanAudit = append([]storage.OrphanAuditRecord(nil), o.orphanAudit[excess:]...)
+	}
+}
+
+// ListOrphans returns the orphaned volumes found by the last collection,
+// sorted by backend and internal name.
+func (o *TridentOrchestrator) ListOrphans() []*storage.OrphanedVolume {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	orphans := make([]*storage.OrphanedVolume, 0)
+	for _, backendOrphans := range o.orphans {
+		for _, orphan := range backendOrphans {
+			orphanCopy := *orphan
+			orphans = append(orphans, &orphanCopy)
+		}
+	}
+	sort.Slice(orphans, func(i, j int) bool {
+		if orphans[i].Backend != orphans[j].Backend {
+			return orphans[i].Backend < orphans[j].Backend
+		}
+		return orphans[i].InternalName < orphans[j].InternalName
+	})
+	return orphans
+}
+
+// GetOrphanAudit returns the actions taken about orphaned volumes, oldest
+// first.
+func (o *TridentOrchestrator) GetOrphanAudit() []storage.OrphanAuditRecord {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	return append(make([]storage.OrphanAuditRecord, 0, len(o.orphanAudit)), o.orphanAudit...)
+}
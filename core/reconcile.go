@@ -0,0 +1,296 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/utils"
+)
+
+// SetReconcileOptions sets how often the orchestrator compares the volumes on
+// its backends with its records, and whether it repairs the differences that
+// are safe to repair.  An interval of zero disables periodic reconciliation.
+// It must be called before the orchestrator is bootstrapped.
+func (o *TridentOrchestrator) SetReconcileOptions(interval time.Duration, repair bool) {
+	o.reconcileInterval = interval
+	o.reconcileRepair = repair
+}
+
+// periodicallyReconcile keeps looking for volumes that were changed on their
+// backends without Trident's knowledge.
+func (o *TridentOrchestrator) periodicallyReconcile(stop <-chan struct{}) {
+	ticker := time.NewTicker(o.reconcileInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if _, err := o.Reconcile(o.reconcileRepair); err != nil {
+				log.Warnf("Unable to reconcile volumes: %v", err)
+			}
+		}
+	}
+}
+
+// Reconcile compares the volumes on each backend with Trident's records of
+// them, and reports any differences.  If repair is set, differences that are
+// safe to repair are resolved by updating Trident's records:  volumes missing
+// from their backends are marked as orphaned, volumes that reappear are no
+// longer marked as orphaned, and volumes grown on their backends have their
+// size updated.  Trident never creates or deletes volumes when reconciling,
+// so volumes unknown to Trident are only reported.
+func (o *TridentOrchestrator) Reconcile(repair bool) (*storage.ReconcileReport, error) {
+	o.reconcileMutex.Lock()
+	defer o.reconcileMutex.Unlock()
+
+	report := &storage.ReconcileReport{
+		Started:       time.Now().UTC().Format(time.RFC3339),
+		Repair:        repair,
+		Drifts:        make([]storage.VolumeDrift, 0),
+		BackendErrors: make(map[string]string),
+	}
+	for _, backendName := range o.getBackendNames() {
+		drifts, err := o.reconcileBackend(backendName, repair)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"backend": backendName,
+			}).Warnf("Unable to list the volumes on the backend: %v", err)
+			report.BackendErrors[backendName] = err.Error()
+			continue
+		}
+		report.Drifts = append(report.Drifts, drifts...)
+	}
+	sort.Slice(report.Drifts, func(i, j int) bool {
+		if report.Drifts[i].Backend != report.Drifts[j].Backend {
+			return report.Drifts[i].Backend < report.Drifts[j].Backend
+		}
+		return report.Drifts[i].InternalName < report.Drifts[j].InternalName
+	})
+	report.Finished = time.Now().UTC().Format(time.RFC3339)
+
+	for _, drift := range report.Drifts {
+		log.WithFields(log.Fields{
+			"type":         drift.Type,
+			"backend":      drift.Backend,
+			"volume":       drift.Volume,
+			"internalName": drift.InternalName,
+			"recordedSize": drift.RecordedSize,
+			"actualSize":   drift.ActualSize,
+			"repaired":     drift.Repaired,
+		}).Warn("Volume differs from its backend.")
+	}
+	log.WithFields(log.Fields{
+		"drifts":        len(report.Drifts),
+		"backendErrors": len(report.BackendErrors),
+	}).Info("Reconciled volumes with their backends.")
+
+	o.mutex.Lock()
+	o.reconcileReport = report
+	o.mutex.Unlock()
+
+	return report, nil
+}
+
+// GetReconcileReport returns the report of the last reconciliation, or nil if
+// there hasn't been one.
+func (o *TridentOrchestrator) GetReconcileReport() *storage.ReconcileReport {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	return o.reconcileReport
+}
+
+// reconcileBackend compares the volumes on a backend with the volumes Trident
+// has recorded on it.
+func (o *TridentOrchestrator) reconcileBackend(backendName string, repair bool) ([]storage.VolumeDrift, error) {
+	backend, unlock := o.lockBackend(backendName)
+	if backend == nil {
+		// The backend was deleted since the reconciliation began
+		unlock()
+		return nil, nil
+	}
+	found, err := listBackendVolumes(backend)
+	unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	// Volumes are recorded after they are created and forgotten after they
+	// are destroyed, so volumes that were being created while the backend was
+	// listed either have been recorded by now or still have a transaction.
+	pending, err := o.getPendingInternalNames(backend)
+	if err != nil {
+		return nil, err
+	}
+
+	tracked := make(map[string]string) // internal name -> volume name
+	o.mutex.RLock()
+	if current, ok := o.backends[backendName]; ok {
+		for volName, vol := range current.Volumes {
+			tracked[vol.Config.InternalName] = volName
+		}
+	}
+	o.mutex.RUnlock()
+
+	drifts := make([]storage.VolumeDrift, 0)
+	for internalName, volExternal := range found {
+		if _, ok := tracked[internalName]; !ok && !pending[internalName] {
+			drifts = append(drifts, storage.VolumeDrift{
+				Type:         storage.DriftUnknownVolume,
+				Backend:      backendName,
+				InternalName: internalName,
+				ActualSize:   volExternal.Config.Size,
+			})
+		}
+	}
+	for internalName, volName := range tracked {
+		if drift := o.reconcileVolume(backendName, volName, found[internalName], repair); drift != nil {
+			drifts = append(drifts, *drift)
+		}
+	}
+	return drifts, nil
+}
+
+// listBackendVolumes returns the volumes a backend's driver manages, keyed by
+// their internal names.
+func listBackendVolumes(backend *storage.Backend) (map[string]*storage.VolumeExternal, error) {
+	channel := make(chan *storage.VolumeExternalWrapper)
+	go backend.Driver.GetVolumeExternalWrappers(channel)
+
+	// Drain the channel even after an error, so the driver isn't left blocked
+	volumes := make(map[string]*storage.VolumeExternal)
+	var err error
+	for wrapper := range channel {
+		if wrapper.Error != nil {
+			err = wrapper.Error
+		} else if wrapper.Volume != nil {
+			volumes[wrapper.Volume.Config.InternalName] = wrapper.Volume
+		}
+	}
+	return volumes, err
+}
+
+// reconcileVolume compares a volume with the volume found on its backend,
+// which is nil if none was found, and repairs any difference if requested.
+// Differences are confirmed with the backend while holding the volume's lock,
+// as the volume may have changed since the backend was listed.
+func (o *TridentOrchestrator) reconcileVolume(
+	backendName, volumeName string, listed *storage.VolumeExternal, repair bool,
+) *storage.VolumeDrift {
+	defer o.volumeLocks.LockAll(volumeName)()
+
+	vol := o.getVolume(volumeName)
+	if vol == nil || vol.Backend != backendName {
+		return nil
+	}
+	o.mutex.RLock()
+	orphaned := vol.Orphaned
+	o.mutex.RUnlock()
+	if listed != nil && !orphaned && sameSize(vol.Config.Size, listed.Config.Size) {
+		return nil
+	}
+
+	backend, unlock := o.lockBackend(backendName)
+	defer unlock()
+	if backend == nil {
+		return nil
+	}
+	volExternal, err := backend.Driver.GetVolumeExternal(vol.Config.InternalName)
+	if volExternal == nil && listed != nil {
+		// The volume was listed, so this is more likely a transient error
+		// than a deletion.
+		return nil
+	}
+	if err != nil {
+		// The drivers don't tell a missing volume apart from any other
+		// error, so the volume is only taken to be missing if listing the
+		// backend again confirms it.
+		if relisted, listErr := listBackendVolumes(backend); listErr != nil ||
+			relisted[vol.Config.InternalName] != nil {
+			log.WithFields(log.Fields{
+				"volume":  volumeName,
+				"backend": backendName,
+			}).Warnf("Unable to check volume on backend; skipping it: %v", err)
+			return nil
+		}
+	}
+
+	drift := &storage.VolumeDrift{
+		Backend:      backendName,
+		Volume:       volumeName,
+		InternalName: vol.Config.InternalName,
+		RecordedSize: vol.Config.Size,
+	}
+	recordedSize := vol.Config.Size
+	switch {
+	case volExternal == nil:
+		drift.Type = storage.DriftMissingVolume
+		if repair && !orphaned {
+			drift.Repaired = o.repairVolume(vol, func() { vol.Orphaned = true }, func() { vol.Orphaned = false })
+		}
+	case orphaned:
+		drift.Type = storage.DriftOrphanedVolumePresent
+		drift.ActualSize = volExternal.Config.Size
+		if repair {
+			drift.Repaired = o.repairVolume(vol, func() { vol.Orphaned = false }, func() { vol.Orphaned = true })
+		}
+	case !sameSize(recordedSize, volExternal.Config.Size):
+		drift.Type = storage.DriftSizeMismatch
+		drift.ActualSize = volExternal.Config.Size
+		// Only growth is recorded, since a volume can't be shrunk safely
+		if repair && sizeBytes(volExternal.Config.Size) > sizeBytes(recordedSize) {
+			drift.Repaired = o.repairVolume(vol,
+				func() { vol.Config.Size = volExternal.Config.Size },
+				func() { vol.Config.Size = recordedSize })
+		}
+	default:
+		return nil
+	}
+	return drift
+}
+
+// repairVolume applies a change to a volume and saves the volume to the
+// persistent store, undoing the change if it can't be saved.  The caller
+// must hold the volume's lock.
+func (o *TridentOrchestrator) repairVolume(vol *storage.Volume, apply, undo func()) bool {
+	o.mutex.Lock()
+	apply()
+	o.mutex.Unlock()
+
+	if err := o.updateVolumeOnPersistentStore(vol); err != nil {
+		log.WithFields(log.Fields{
+			"volume": vol.Config.Name,
+		}).Errorf("Unable to update the volume in the backing store: %v", err)
+		o.mutex.Lock()
+		undo()
+		o.mutex.Unlock()
+		return false
+	}
+	return true
+}
+
+// sizeBytes returns a volume size in bytes, or zero if it can't be parsed.
+func sizeBytes(size string) uint64 {
+	sizeString, err := utils.ConvertSizeToBytes(size)
+	if err != nil {
+		return 0
+	}
+	bytes, _ := strconv.ParseUint(sizeString, 10, 64)
+	return bytes
+}
+
+// sameSize returns whether a volume's recorded size matches the size reported
+// by its backend.  Sizes that aren't known are assumed to match, since some
+// drivers don't report the sizes of their volumes.
+func sameSize(recorded, actual string) bool {
+	recordedBytes, actualBytes := sizeBytes(recorded), sizeBytes(actual)
+	return recordedBytes == 0 || actualBytes == 0 || recordedBytes == actualBytes
+}
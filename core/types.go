@@ -3,37 +3,81 @@
 package core
 
 import (
+	"context"
+
 	"github.com/netapp/trident/config"
 	"github.com/netapp/trident/frontend"
+	"github.com/netapp/trident/persistent_store"
 	"github.com/netapp/trident/storage"
 	"github.com/netapp/trident/storage_class"
 )
 
 type Orchestrator interface {
 	Bootstrap() error
+	Stop()
+	Follow() error
+	IsFollower() bool
 	AddFrontend(f frontend.Plugin)
 	GetVersion() string
 
 	AddStorageBackend(configJSON string) (*storage.BackendExternal, error)
 	GetBackend(backend string) *storage.BackendExternal
 	ListBackends() []*storage.BackendExternal
-	OfflineBackend(backend string) (bool, error)
+	UpdateBackend(backendName, configJSON string, dryRun bool) (*storage.BackendUpdateExternal, error)
+	DeleteBackend(backend string) (bool, error)
 
 	AddVolume(volumeConfig *storage.VolumeConfig) (*storage.VolumeExternal, error)
 	CloneVolume(volumeConfig *storage.VolumeConfig) (*storage.VolumeExternal, error)
+	ImportVolume(backendName, internalName string, volumeConfig *storage.VolumeConfig, rename bool) (
+		*storage.VolumeExternal, error)
+	AddVolumeAsync(volumeConfig *storage.VolumeConfig) (*persistentstore.Operation, error)
+	ImportVolumeAsync(backendName, internalName string, volumeConfig *storage.VolumeConfig, rename bool) (
+		*persistentstore.Operation, error)
 	GetVolume(volume string) *storage.VolumeExternal
 	GetDriverTypeForVolume(vol *storage.VolumeExternal) string
 	GetVolumeType(vol *storage.VolumeExternal) config.VolumeType
 	ListVolumes() []*storage.VolumeExternal
 	DeleteVolume(volume string) (found bool, err error)
+	ResizeVolume(volume, newSize string) error
+	GetVolumeReplication(volumeName string) (*storage.ReplicationStatus, error)
+	FailoverVolume(volumeName string) (*storage.VolumeExternal, error)
 	ListVolumesByPlugin(pluginName string) []*storage.VolumeExternal
 	AttachVolume(volumeName, mountpoint string, options map[string]string) error
 	DetachVolume(volumeName, mountpoint string) error
 	ListVolumeSnapshots(volumeName string) ([]*storage.SnapshotExternal, error)
 	ReloadVolumes() error
 
+	CreateSnapshot(volumeName, snapshotName string) (*storage.SnapshotExternal, error)
+	GetSnapshot(volumeName, snapshotName string) *storage.SnapshotExternal
+	ListSnapshots(volumeName string) ([]*storage.SnapshotExternal, error)
+	DeleteSnapshot(volumeName, snapshotName string) (found bool, err error)
+	RestoreSnapshot(volumeName, snapshotName string) error
+
 	AddStorageClass(scConfig *storageclass.Config) (*storageclass.External, error)
 	GetStorageClass(scName string) *storageclass.External
 	ListStorageClasses() []*storageclass.External
+	UpdateStorageClass(scConfig *storageclass.Config) (*storageclass.External, error)
 	DeleteStorageClass(scName string) (bool, error)
+
+	GetOperation(id string) *persistentstore.Operation
+	ListOperations() []*persistentstore.Operation
+
+	Reconcile(repair bool) (*storage.ReconcileReport, error)
+	GetReconcileReport() *storage.ReconcileReport
+
+	CollectOrphans() ([]*storage.OrphanedVolume, error)
+	ListOrphans() []*storage.OrphanedVolume
+	GetOrphanAudit() []storage.OrphanAuditRecord
+}
+
+// LeaderElector elects one of several Trident replicas sharing a persistent
+// store to manage Trident's state, while the others follow the store.
+type LeaderElector interface {
+	// Campaign blocks until this replica is elected or the context is done.
+	Campaign(ctx context.Context) error
+	// Lost returns a channel that is closed if this replica, once elected,
+	// is no longer the leader.
+	Lost() <-chan struct{}
+	// Resign gives up leadership, if held.
+	Resign() error
 }
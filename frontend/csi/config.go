@@ -0,0 +1,18 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package csi
+
+const (
+	pluginName = "csi"
+
+	// csiPluginName is the name under which Trident registers with the container orchestrator
+	csiPluginName = "csi.trident.netapp.io"
+	csiVersion    = "1.5.0"
+
+	autoStorageClassPrefix = "auto_sc_%d"
+	defaultVolumeSizeBytes = 1 << 30 // 1 GiB
+
+	// storageClassParameter names an existing Trident storage class in the
+	// parameters of a CreateVolume or GetCapacity request
+	storageClassParameter = "storageClass"
+)
@@ -0,0 +1,473 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package csi
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage"
+)
+
+func (p *Plugin) CreateVolume(
+	ctx context.Context, req *csi.CreateVolumeRequest,
+) (*csi.CreateVolumeResponse, error) {
+
+	name := req.GetName()
+	if name == "" {
+		return nil, status.Error(codes.InvalidArgument, "volume name is required")
+	}
+	accessMode, err := getAccessMode(req.GetVolumeCapabilities())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	sizeBytes, limitBytes, err := getRequestedSize(req.GetCapacityRange())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	// A volume that already exists must match the request
+	if volume := p.orchestrator.GetVolume(name); volume != nil {
+		existingBytes := getVolumeSizeBytes(volume)
+		if existingBytes < sizeBytes || (limitBytes > 0 && existingBytes > limitBytes) {
+			return nil, status.Errorf(codes.AlreadyExists,
+				"volume %s already exists with a different size", name)
+		}
+		return &csi.CreateVolumeResponse{Volume: getCSIVolume(volume)}, nil
+	}
+
+	// Find a matching storage class, or register a new one
+	scConfig, err := getStorageClass(req.GetParameters(), p.orchestrator)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	fsType := ""
+	for _, capability := range req.GetVolumeCapabilities() {
+		if mount := capability.GetMount(); mount != nil && mount.GetFsType() != "" {
+			fsType = mount.GetFsType()
+			break
+		}
+	}
+
+	// Convert the request into a Trident volume config
+	volConfig := getVolumeConfig(name, scConfig.Name, sizeBytes, accessMode, fsType, req.GetParameters())
+
+	// A volume created from a snapshot or another volume is a clone
+	if source := req.GetVolumeContentSource(); source != nil {
+		if snapshot := source.GetSnapshot(); snapshot != nil {
+			volumeName, snapshotName, err := parseSnapshotID(snapshot.GetSnapshotId())
+			if err != nil || p.orchestrator.GetSnapshot(volumeName, snapshotName) == nil {
+				return nil, status.Errorf(codes.NotFound, "snapshot %s not found", snapshot.GetSnapshotId())
+			}
+			volConfig.CloneSourceVolume = volumeName
+			volConfig.CloneSourceSnapshot = snapshotName
+		} else if sourceVolume := source.GetVolume(); sourceVolume != nil {
+			if p.orchestrator.GetVolume(sourceVolume.GetVolumeId()) == nil {
+				return nil, status.Errorf(codes.NotFound, "volume %s not found", sourceVolume.GetVolumeId())
+			}
+			volConfig.CloneSourceVolume = sourceVolume.GetVolumeId()
+		}
+	}
+
+	// Invoke the orchestrator to create or clone the new volume
+	var volume *storage.VolumeExternal
+	if volConfig.CloneSourceVolume != "" {
+		volume, err = p.orchestrator.CloneVolume(volConfig)
+	} else {
+		volume, err = p.orchestrator.AddVolume(volConfig)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &csi.CreateVolumeResponse{Volume: getCSIVolume(volume)}, nil
+}
+
+// getRequestedSize returns the size of a new volume and the largest size
+// allowed, which is zero if there is no limit.
+func getRequestedSize(capacityRange *csi.CapacityRange) (int64, int64, error) {
+
+	requiredBytes := capacityRange.GetRequiredBytes()
+	limitBytes := capacityRange.GetLimitBytes()
+
+	if requiredBytes < 0 || limitBytes < 0 {
+		return 0, 0, errors.New("capacity range may not be negative")
+	}
+	if limitBytes > 0 && requiredBytes > limitBytes {
+		return 0, 0, errors.New("required bytes exceed limit bytes")
+	}
+
+	if requiredBytes == 0 {
+		requiredBytes = defaultVolumeSizeBytes
+		if limitBytes > 0 && limitBytes < requiredBytes {
+			requiredBytes = limitBytes
+		}
+	}
+	return requiredBytes, limitBytes, nil
+}
+
+func (p *Plugin) DeleteVolume(
+	ctx context.Context, req *csi.DeleteVolumeRequest,
+) (*csi.DeleteVolumeResponse, error) {
+
+	volumeID := req.GetVolumeId()
+	if volumeID == "" {
+		return nil, status.Error(codes.InvalidArgument, "volume ID is required")
+	}
+
+	found, err := p.orchestrator.DeleteVolume(volumeID)
+	if !found {
+		// Deleting a volume that doesn't exist succeeds, so that retries are harmless
+		log.WithField("volume", volumeID).Warn("Volume not found.")
+		return &csi.DeleteVolumeResponse{}, nil
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	p.mutex.Lock()
+	delete(p.publishedNodes, volumeID)
+	p.mutex.Unlock()
+
+	return &csi.DeleteVolumeResponse{}, nil
+}
+
+// ControllerPublishVolume makes a volume available to a node.  Trident's
+// drivers grant nodes access to a volume when it is attached, so this only
+// checks that the volume may be used by the node and records that it is.
+func (p *Plugin) ControllerPublishVolume(
+	ctx context.Context, req *csi.ControllerPublishVolumeRequest,
+) (*csi.ControllerPublishVolumeResponse, error) {
+
+	volumeID := req.GetVolumeId()
+	nodeID := req.GetNodeId()
+	if volumeID == "" {
+		return nil, status.Error(codes.InvalidArgument, "volume ID is required")
+	}
+	if nodeID == "" {
+		return nil, status.Error(codes.InvalidArgument, "node ID is required")
+	}
+	if req.GetVolumeCapability() == nil {
+		return nil, status.Error(codes.InvalidArgument, "volume capability is required")
+	}
+
+	volume := p.orchestrator.GetVolume(volumeID)
+	if volume == nil {
+		return nil, status.Errorf(codes.NotFound, "volume %s not found", volumeID)
+	}
+	if !isCapabilitySupported(volume.Config.AccessMode, req.GetVolumeCapability()) {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"volume %s does not support the requested capability", volumeID)
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	nodes, ok := p.publishedNodes[volumeID]
+	if !ok {
+		nodes = make(map[string]bool)
+		p.publishedNodes[volumeID] = nodes
+	}
+	if volume.Config.AccessMode == config.ReadWriteOnce && !nodes[nodeID] && len(nodes) > 0 {
+		return nil, status.Errorf(codes.FailedPrecondition,
+			"volume %s is already published to another node", volumeID)
+	}
+	nodes[nodeID] = true
+
+	return &csi.ControllerPublishVolumeResponse{}, nil
+}
+
+func (p *Plugin) ControllerUnpublishVolume(
+	ctx context.Context, req *csi.ControllerUnpublishVolumeRequest,
+) (*csi.ControllerUnpublishVolumeResponse, error) {
+
+	volumeID := req.GetVolumeId()
+	if volumeID == "" {
+		return nil, status.Error(codes.InvalidArgument, "volume ID is required")
+	}
+	if p.orchestrator.GetVolume(volumeID) == nil {
+		return nil, status.Errorf(codes.NotFound, "volume %s not found", volumeID)
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	// An empty node ID unpublishes the volume from every node
+	if req.GetNodeId() == "" {
+		delete(p.publishedNodes, volumeID)
+	} else if nodes, ok := p.publishedNodes[volumeID]; ok {
+		delete(nodes, req.GetNodeId())
+		if len(nodes) == 0 {
+			delete(p.publishedNodes, volumeID)
+		}
+	}
+
+	return &csi.ControllerUnpublishVolumeResponse{}, nil
+}
+
+func (p *Plugin) ValidateVolumeCapabilities(
+	ctx context.Context, req *csi.ValidateVolumeCapabilitiesRequest,
+) (*csi.ValidateVolumeCapabilitiesResponse, error) {
+
+	volumeID := req.GetVolumeId()
+	if volumeID == "" {
+		return nil, status.Error(codes.InvalidArgument, "volume ID is required")
+	}
+	if len(req.GetVolumeCapabilities()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "volume capabilities are required")
+	}
+
+	volume := p.orchestrator.GetVolume(volumeID)
+	if volume == nil {
+		return nil, status.Errorf(codes.NotFound, "volume %s not found", volumeID)
+	}
+
+	for _, capability := range req.GetVolumeCapabilities() {
+		if !isCapabilitySupported(volume.Config.AccessMode, capability) {
+			return &csi.ValidateVolumeCapabilitiesResponse{
+				Message: "volume does not support the requested capabilities",
+			}, nil
+		}
+	}
+
+	return &csi.ValidateVolumeCapabilitiesResponse{
+		Confirmed: &csi.ValidateVolumeCapabilitiesResponse_Confirmed{
+			VolumeContext:      req.GetVolumeContext(),
+			VolumeCapabilities: req.GetVolumeCapabilities(),
+			Parameters:         req.GetParameters(),
+		},
+	}, nil
+}
+
+// isCapabilitySupported returns whether a volume with the supplied Trident
+// access mode may be used as described by a CSI volume capability.
+func isCapabilitySupported(accessMode config.AccessMode, capability *csi.VolumeCapability) bool {
+
+	if capability.GetBlock() != nil || capability.GetAccessMode() == nil {
+		return false
+	}
+
+	switch accessMode {
+	case config.ReadWriteOnce:
+		switch capability.GetAccessMode().GetMode() {
+		case csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER,
+			csi.VolumeCapability_AccessMode_SINGLE_NODE_READER_ONLY,
+			csi.VolumeCapability_AccessMode_SINGLE_NODE_SINGLE_WRITER,
+			csi.VolumeCapability_AccessMode_SINGLE_NODE_MULTI_WRITER:
+			return true
+		}
+		return false
+	case config.ReadOnlyMany:
+		return isReadOnlyCapability(capability)
+	default:
+		return capability.GetAccessMode().GetMode() != csi.VolumeCapability_AccessMode_UNKNOWN
+	}
+}
+
+func (p *Plugin) ListVolumes(
+	ctx context.Context, req *csi.ListVolumesRequest,
+) (*csi.ListVolumesResponse, error) {
+
+	volumes := p.orchestrator.ListVolumes()
+	sort.Slice(volumes, func(i, j int) bool {
+		return volumes[i].Config.Name < volumes[j].Config.Name
+	})
+
+	start, end, nextToken, err := paginate(len(volumes), req.GetStartingToken(), req.GetMaxEntries())
+	if err != nil {
+		return nil, status.Error(codes.Aborted, err.Error())
+	}
+
+	entries := make([]*csi.ListVolumesResponse_Entry, 0, end-start)
+	for _, volume := range volumes[start:end] {
+		entries = append(entries, &csi.ListVolumesResponse_Entry{Volume: getCSIVolume(volume)})
+	}
+
+	return &csi.ListVolumesResponse{Entries: entries, NextToken: nextToken}, nil
+}
+
+// GetCapacity returns the space available in the pools of all online
+// backends or, if a storage class is named in the parameters, in the pools
+// of that storage class.
+func (p *Plugin) GetCapacity(
+	ctx context.Context, req *csi.GetCapacityRequest,
+) (*csi.GetCapacityResponse, error) {
+
+	scName, filterByStorageClass := req.GetParameters()[storageClassParameter]
+
+	var availableBytes uint64
+	for _, backend := range p.orchestrator.ListBackends() {
+		if !backend.Online {
+			continue
+		}
+		for _, pool := range backend.Storage {
+			if pool.Capacity == nil {
+				continue
+			}
+			if filterByStorageClass && !containsString(pool.StorageClasses, scName) {
+				continue
+			}
+			availableBytes += pool.Capacity.AvailableBytes
+		}
+	}
+
+	return &csi.GetCapacityResponse{AvailableCapacity: int64(availableBytes)}, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Plugin) ControllerGetCapabilities(
+	ctx context.Context, req *csi.ControllerGetCapabilitiesRequest,
+) (*csi.ControllerGetCapabilitiesResponse, error) {
+
+	capabilityTypes := []csi.ControllerServiceCapability_RPC_Type{
+		csi.ControllerServiceCapability_RPC_CREATE_DELETE_VOLUME,
+		csi.ControllerServiceCapability_RPC_PUBLISH_UNPUBLISH_VOLUME,
+		csi.ControllerServiceCapability_RPC_LIST_VOLUMES,
+		csi.ControllerServiceCapability_RPC_GET_CAPACITY,
+		csi.ControllerServiceCapability_RPC_CREATE_DELETE_SNAPSHOT,
+		csi.ControllerServiceCapability_RPC_LIST_SNAPSHOTS,
+		csi.ControllerServiceCapability_RPC_CLONE_VOLUME,
+	}
+
+	capabilities := make([]*csi.ControllerServiceCapability, 0, len(capabilityTypes))
+	for _, capabilityType := range capabilityTypes {
+		capabilities = append(capabilities, &csi.ControllerServiceCapability{
+			Type: &csi.ControllerServiceCapability_Rpc{
+				Rpc: &csi.ControllerServiceCapability_RPC{Type: capabilityType},
+			},
+		})
+	}
+
+	return &csi.ControllerGetCapabilitiesResponse{Capabilities: capabilities}, nil
+}
+
+func (p *Plugin) CreateSnapshot(
+	ctx context.Context, req *csi.CreateSnapshotRequest,
+) (*csi.CreateSnapshotResponse, error) {
+
+	volumeID := req.GetSourceVolumeId()
+	name := req.GetName()
+	if volumeID == "" {
+		return nil, status.Error(codes.InvalidArgument, "source volume ID is required")
+	}
+	if name == "" {
+		return nil, status.Error(codes.InvalidArgument, "snapshot name is required")
+	}
+
+	volume := p.orchestrator.GetVolume(volumeID)
+	if volume == nil {
+		return nil, status.Errorf(codes.NotFound, "volume %s not found", volumeID)
+	}
+
+	// Snapshot names are unique across all volumes, and an existing snapshot
+	// of the same volume is returned as is
+	for _, otherVolume := range p.orchestrator.ListVolumes() {
+		snapshot := p.orchestrator.GetSnapshot(otherVolume.Config.Name, name)
+		if snapshot == nil {
+			continue
+		}
+		if otherVolume.Config.Name != volumeID {
+			return nil, status.Errorf(codes.AlreadyExists,
+				"snapshot %s already exists for volume %s", name, otherVolume.Config.Name)
+		}
+		return &csi.CreateSnapshotResponse{Snapshot: getCSISnapshot(volume, snapshot)}, nil
+	}
+
+	snapshot, err := p.orchestrator.CreateSnapshot(volumeID, name)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &csi.CreateSnapshotResponse{Snapshot: getCSISnapshot(volume, snapshot)}, nil
+}
+
+func (p *Plugin) DeleteSnapshot(
+	ctx context.Context, req *csi.DeleteSnapshotRequest,
+) (*csi.DeleteSnapshotResponse, error) {
+
+	snapshotID := req.GetSnapshotId()
+	if snapshotID == "" {
+		return nil, status.Error(codes.InvalidArgument, "snapshot ID is required")
+	}
+
+	// Deleting a snapshot that doesn't exist succeeds, so that retries are harmless
+	volumeName, snapshotName, err := parseSnapshotID(snapshotID)
+	if err != nil || p.orchestrator.GetSnapshot(volumeName, snapshotName) == nil {
+		log.WithField("snapshot", snapshotID).Warn("Snapshot not found.")
+		return &csi.DeleteSnapshotResponse{}, nil
+	}
+
+	if _, err = p.orchestrator.DeleteSnapshot(volumeName, snapshotName); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &csi.DeleteSnapshotResponse{}, nil
+}
+
+func (p *Plugin) ListSnapshots(
+	ctx context.Context, req *csi.ListSnapshotsRequest,
+) (*csi.ListSnapshotsResponse, error) {
+
+	snapshots := make([]*csi.Snapshot, 0)
+
+	if snapshotID := req.GetSnapshotId(); snapshotID != "" {
+		volumeName, snapshotName, err := parseSnapshotID(snapshotID)
+		if err == nil && (req.GetSourceVolumeId() == "" || req.GetSourceVolumeId() == volumeName) {
+			volume := p.orchestrator.GetVolume(volumeName)
+			snapshot := p.orchestrator.GetSnapshot(volumeName, snapshotName)
+			if volume != nil && snapshot != nil {
+				snapshots = append(snapshots, getCSISnapshot(volume, snapshot))
+			}
+		}
+	} else {
+		var volumes []*storage.VolumeExternal
+		if req.GetSourceVolumeId() != "" {
+			if volume := p.orchestrator.GetVolume(req.GetSourceVolumeId()); volume != nil {
+				volumes = append(volumes, volume)
+			}
+		} else {
+			volumes = p.orchestrator.ListVolumes()
+		}
+		for _, volume := range volumes {
+			volumeSnapshots, err := p.orchestrator.ListSnapshots(volume.Config.Name)
+			if err != nil {
+				return nil, status.Error(codes.Internal, err.Error())
+			}
+			for _, snapshot := range volumeSnapshots {
+				snapshots = append(snapshots, getCSISnapshot(volume, snapshot))
+			}
+		}
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].SnapshotId < snapshots[j].SnapshotId
+	})
+
+	start, end, nextToken, err := paginate(len(snapshots), req.GetStartingToken(), req.GetMaxEntries())
+	if err != nil {
+		return nil, status.Error(codes.Aborted, err.Error())
+	}
+
+	entries := make([]*csi.ListSnapshotsResponse_Entry, 0, end-start)
+	for _, snapshot := range snapshots[start:end] {
+		entries = append(entries, &csi.ListSnapshotsResponse_Entry{Snapshot: snapshot})
+	}
+
+	return &csi.ListSnapshotsResponse{Entries: entries, NextToken: nextToken}, nil
+}
@@ -0,0 +1,45 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package csi
+
+import (
+	"context"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/golang/protobuf/ptypes/wrappers"
+
+	"github.com/netapp/trident/config"
+)
+
+func (p *Plugin) GetPluginInfo(
+	ctx context.Context, req *csi.GetPluginInfoRequest,
+) (*csi.GetPluginInfoResponse, error) {
+
+	return &csi.GetPluginInfoResponse{
+		Name:          csiPluginName,
+		VendorVersion: config.OrchestratorVersion.String(),
+	}, nil
+}
+
+func (p *Plugin) GetPluginCapabilities(
+	ctx context.Context, req *csi.GetPluginCapabilitiesRequest,
+) (*csi.GetPluginCapabilitiesResponse, error) {
+
+	return &csi.GetPluginCapabilitiesResponse{
+		Capabilities: []*csi.PluginCapability{
+			{
+				Type: &csi.PluginCapability_Service_{
+					Service: &csi.PluginCapability_Service{
+						Type: csi.PluginCapability_Service_CONTROLLER_SERVICE,
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+// Probe always reports that the plugin is ready, since it is only activated
+// after the orchestrator has bootstrapped.
+func (p *Plugin) Probe(ctx context.Context, req *csi.ProbeRequest) (*csi.ProbeResponse, error) {
+	return &csi.ProbeResponse{Ready: &wrappers.BoolValue{Value: true}}, nil
+}
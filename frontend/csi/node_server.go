@@ -0,0 +1,191 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package csi
+
+import (
+	"context"
+	"os"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	drivers "github.com/netapp/trident/storage_drivers"
+	"github.com/netapp/trident/utils"
+)
+
+// NodeStageVolume attaches a volume to this node at its staging path, using
+// the volume's driver to mount the NFS export or log in to the iSCSI target
+// and mount the LUN.
+func (p *Plugin) NodeStageVolume(
+	ctx context.Context, req *csi.NodeStageVolumeRequest,
+) (*csi.NodeStageVolumeResponse, error) {
+
+	volumeID := req.GetVolumeId()
+	stagingPath := req.GetStagingTargetPath()
+	capability := req.GetVolumeCapability()
+	if volumeID == "" {
+		return nil, status.Error(codes.InvalidArgument, "volume ID is required")
+	}
+	if stagingPath == "" {
+		return nil, status.Error(codes.InvalidArgument, "staging target path is required")
+	}
+	if capability == nil {
+		return nil, status.Error(codes.InvalidArgument, "volume capability is required")
+	}
+	if capability.GetBlock() != nil {
+		return nil, status.Error(codes.InvalidArgument, "block access is not supported")
+	}
+
+	if p.orchestrator.GetVolume(volumeID) == nil {
+		return nil, status.Errorf(codes.NotFound, "volume %s not found", volumeID)
+	}
+
+	options := make(map[string]string)
+	if isReadOnlyCapability(capability) {
+		options[drivers.AttachOptionReadOnly] = "true"
+	}
+
+	if err := p.orchestrator.AttachVolume(volumeID, stagingPath, options); err != nil {
+		log.WithFields(log.Fields{
+			"volume":      volumeID,
+			"stagingPath": stagingPath,
+		}).Error(err)
+		return nil, status.Errorf(codes.Internal, "error attaching volume %s at %s: %v",
+			volumeID, stagingPath, err)
+	}
+
+	return &csi.NodeStageVolumeResponse{}, nil
+}
+
+func (p *Plugin) NodeUnstageVolume(
+	ctx context.Context, req *csi.NodeUnstageVolumeRequest,
+) (*csi.NodeUnstageVolumeResponse, error) {
+
+	volumeID := req.GetVolumeId()
+	stagingPath := req.GetStagingTargetPath()
+	if volumeID == "" {
+		return nil, status.Error(codes.InvalidArgument, "volume ID is required")
+	}
+	if stagingPath == "" {
+		return nil, status.Error(codes.InvalidArgument, "staging target path is required")
+	}
+
+	if p.orchestrator.GetVolume(volumeID) == nil {
+		return nil, status.Errorf(codes.NotFound, "volume %s not found", volumeID)
+	}
+
+	if err := p.orchestrator.DetachVolume(volumeID, stagingPath); err != nil {
+		log.WithFields(log.Fields{
+			"volume":      volumeID,
+			"stagingPath": stagingPath,
+		}).Error(err)
+		return nil, status.Errorf(codes.Internal, "error detaching volume %s from %s: %v",
+			volumeID, stagingPath, err)
+	}
+
+	return &csi.NodeUnstageVolumeResponse{}, nil
+}
+
+// NodePublishVolume makes a staged volume available at the target path with
+// a bind mount.
+func (p *Plugin) NodePublishVolume(
+	ctx context.Context, req *csi.NodePublishVolumeRequest,
+) (*csi.NodePublishVolumeResponse, error) {
+
+	volumeID := req.GetVolumeId()
+	stagingPath := req.GetStagingTargetPath()
+	targetPath := req.GetTargetPath()
+	capability := req.GetVolumeCapability()
+	if volumeID == "" {
+		return nil, status.Error(codes.InvalidArgument, "volume ID is required")
+	}
+	if stagingPath == "" {
+		return nil, status.Error(codes.InvalidArgument, "staging target path is required")
+	}
+	if targetPath == "" {
+		return nil, status.Error(codes.InvalidArgument, "target path is required")
+	}
+	if capability == nil {
+		return nil, status.Error(codes.InvalidArgument, "volume capability is required")
+	}
+	if capability.GetBlock() != nil {
+		return nil, status.Error(codes.InvalidArgument, "block access is not supported")
+	}
+
+	if p.orchestrator.GetVolume(volumeID) == nil {
+		return nil, status.Errorf(codes.NotFound, "volume %s not found", volumeID)
+	}
+
+	mounted, err := utils.IsMounted(targetPath)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error checking if %s is mounted: %v", targetPath, err)
+	}
+	if mounted {
+		log.WithField("targetPath", targetPath).Debug("Volume is already published.")
+		return &csi.NodePublishVolumeResponse{}, nil
+	}
+
+	readOnly := req.GetReadonly() || isReadOnlyCapability(capability)
+	if err = utils.BindMount(stagingPath, targetPath, readOnly); err != nil {
+		return nil, status.Errorf(codes.Internal, "error publishing volume %s at %s: %v",
+			volumeID, targetPath, err)
+	}
+
+	return &csi.NodePublishVolumeResponse{}, nil
+}
+
+func (p *Plugin) NodeUnpublishVolume(
+	ctx context.Context, req *csi.NodeUnpublishVolumeRequest,
+) (*csi.NodeUnpublishVolumeResponse, error) {
+
+	volumeID := req.GetVolumeId()
+	targetPath := req.GetTargetPath()
+	if volumeID == "" {
+		return nil, status.Error(codes.InvalidArgument, "volume ID is required")
+	}
+	if targetPath == "" {
+		return nil, status.Error(codes.InvalidArgument, "target path is required")
+	}
+
+	mounted, err := utils.IsMounted(targetPath)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error checking if %s is mounted: %v", targetPath, err)
+	}
+	if mounted {
+		if err = utils.Umount(targetPath); err != nil {
+			return nil, status.Errorf(codes.Internal, "error unpublishing volume %s from %s: %v",
+				volumeID, targetPath, err)
+		}
+	}
+
+	// Best effort removal of the target path
+	os.Remove(targetPath)
+
+	return &csi.NodeUnpublishVolumeResponse{}, nil
+}
+
+func (p *Plugin) NodeGetCapabilities(
+	ctx context.Context, req *csi.NodeGetCapabilitiesRequest,
+) (*csi.NodeGetCapabilitiesResponse, error) {
+
+	return &csi.NodeGetCapabilitiesResponse{
+		Capabilities: []*csi.NodeServiceCapability{
+			{
+				Type: &csi.NodeServiceCapability_Rpc{
+					Rpc: &csi.NodeServiceCapability_RPC{
+						Type: csi.NodeServiceCapability_RPC_STAGE_UNSTAGE_VOLUME,
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+func (p *Plugin) NodeGetInfo(
+	ctx context.Context, req *csi.NodeGetInfoRequest,
+) (*csi.NodeGetInfoResponse, error) {
+
+	return &csi.NodeGetInfoResponse{NodeId: p.nodeName}, nil
+}
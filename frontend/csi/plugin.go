@@ -0,0 +1,150 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package csi
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"sync"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+
+	"github.com/netapp/trident/core"
+)
+
+// Plugin serves the CSI Identity, Controller and Node services on behalf of
+// the orchestrator.
+type Plugin struct {
+	csi.UnimplementedIdentityServer
+	csi.UnimplementedControllerServer
+	csi.UnimplementedNodeServer
+
+	orchestrator core.Orchestrator
+	nodeName     string
+	endpoint     string
+	server       *grpc.Server
+
+	// publishedNodes records the nodes each volume is published to
+	publishedNodes map[string]map[string]bool
+	mutex          *sync.Mutex
+}
+
+func NewPlugin(nodeName, endpoint string, orchestrator core.Orchestrator) (*Plugin, error) {
+
+	if nodeName == "" {
+		return nil, fmt.Errorf("a node name is required to serve CSI")
+	}
+	if _, _, err := parseEndpoint(endpoint); err != nil {
+		return nil, err
+	}
+
+	plugin := &Plugin{
+		orchestrator:   orchestrator,
+		nodeName:       nodeName,
+		endpoint:       endpoint,
+		publishedNodes: make(map[string]map[string]bool),
+		mutex:          &sync.Mutex{},
+	}
+
+	log.WithFields(log.Fields{
+		"name":     csiPluginName,
+		"nodeName": nodeName,
+		"endpoint": endpoint,
+	}).Info("Initializing Trident plugin for CSI.")
+
+	return plugin, nil
+}
+
+// parseEndpoint splits a CSI endpoint, such as unix:///var/lib/csi/csi.sock
+// or tcp://127.0.0.1:10000, into a network and an address.
+func parseEndpoint(endpoint string) (string, string, error) {
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid CSI endpoint %s: %v", endpoint, err)
+	}
+
+	switch u.Scheme {
+	case "unix":
+		if address := u.Host + u.Path; address != "" {
+			return "unix", address, nil
+		}
+	case "tcp":
+		if u.Host != "" {
+			return "tcp", u.Host, nil
+		}
+	default:
+		return "", "", fmt.Errorf("invalid CSI endpoint %s: scheme must be unix or tcp", endpoint)
+	}
+	return "", "", fmt.Errorf("invalid CSI endpoint %s: address is missing", endpoint)
+}
+
+func (p *Plugin) Activate() error {
+
+	network, address, err := parseEndpoint(p.endpoint)
+	if err != nil {
+		return err
+	}
+
+	// Remove a socket left behind by an earlier instance
+	if network == "unix" {
+		if err = os.Remove(address); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("could not remove stale CSI socket %s: %v", address, err)
+		}
+	}
+
+	listener, err := net.Listen(network, address)
+	if err != nil {
+		return fmt.Errorf("could not listen on CSI endpoint %s: %v", p.endpoint, err)
+	}
+
+	p.server = grpc.NewServer(grpc.UnaryInterceptor(logGRPC))
+	csi.RegisterIdentityServer(p.server, p)
+	csi.RegisterControllerServer(p.server, p)
+	csi.RegisterNodeServer(p.server, p)
+
+	go func() {
+		log.WithField("endpoint", p.endpoint).Info("Serving CSI.")
+		if err := p.server.Serve(listener); err != nil {
+			log.Fatal(err)
+		}
+	}()
+	return nil
+}
+
+func (p *Plugin) Deactivate() error {
+	if p.server != nil {
+		p.server.GracefulStop()
+	}
+	return nil
+}
+
+func (p *Plugin) GetName() string {
+	return pluginName
+}
+
+func (p *Plugin) Version() string {
+	return csiVersion
+}
+
+// logGRPC logs each CSI call and any error it returns.
+func logGRPC(
+	ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+) (interface{}, error) {
+
+	log.WithField("method", info.FullMethod).Debug("CSI frontend method is invoked.")
+
+	resp, err := handler(ctx, req)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"method": info.FullMethod,
+			"error":  err,
+		}).Error("CSI frontend method failed.")
+	}
+	return resp, err
+}
@@ -0,0 +1,273 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package csi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/core"
+	"github.com/netapp/trident/storage_attribute"
+	"github.com/netapp/trident/storage_class"
+)
+
+const testStorageClass = "gold"
+
+func newTestPlugin(t *testing.T) *Plugin {
+	orchestrator := core.NewMockOrchestrator()
+	orchestrator.AddMockONTAPNFSBackend("nfs", "127.0.0.1")
+	if _, err := orchestrator.AddStorageClass(&storageclass.Config{
+		Name:       testStorageClass,
+		Attributes: map[string]storageattribute.Request{},
+	}); err != nil {
+		t.Fatalf("Unable to add storage class: %v", err)
+	}
+	plugin, err := NewPlugin("node1", "unix:///tmp/csi.sock", orchestrator)
+	if err != nil {
+		t.Fatalf("Unable to create plugin: %v", err)
+	}
+	return plugin
+}
+
+func newCapability(mode csi.VolumeCapability_AccessMode_Mode) *csi.VolumeCapability {
+	return &csi.VolumeCapability{
+		AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{}},
+		AccessMode: &csi.VolumeCapability_AccessMode{Mode: mode},
+	}
+}
+
+func newCreateVolumeRequest(name string, requiredBytes int64) *csi.CreateVolumeRequest {
+	return &csi.CreateVolumeRequest{
+		Name:          name,
+		CapacityRange: &csi.CapacityRange{RequiredBytes: requiredBytes},
+		VolumeCapabilities: []*csi.VolumeCapability{
+			newCapability(csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER),
+		},
+		Parameters: map[string]string{storageClassParameter: testStorageClass},
+	}
+}
+
+func TestNewPluginEndpoint(t *testing.T) {
+	orchestrator := core.NewMockOrchestrator()
+	for _, endpoint := range []string{"unix:///var/lib/csi.sock", "tcp://127.0.0.1:10000"} {
+		if _, err := NewPlugin("node1", endpoint, orchestrator); err != nil {
+			t.Errorf("Expected endpoint %s to be accepted: %v", endpoint, err)
+		}
+	}
+	for _, endpoint := range []string{"", "/var/lib/csi.sock", "http://127.0.0.1", "tcp://"} {
+		if _, err := NewPlugin("node1", endpoint, orchestrator); err == nil {
+			t.Errorf("Expected endpoint %s to be rejected.", endpoint)
+		}
+	}
+}
+
+func TestGetAccessMode(t *testing.T) {
+	for _, test := range []struct {
+		modes    []csi.VolumeCapability_AccessMode_Mode
+		expected config.AccessMode
+	}{
+		{[]csi.VolumeCapability_AccessMode_Mode{
+			csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER,
+		}, config.ReadWriteOnce},
+		{[]csi.VolumeCapability_AccessMode_Mode{
+			csi.VolumeCapability_AccessMode_MULTI_NODE_READER_ONLY,
+		}, config.ReadOnlyMany},
+		{[]csi.VolumeCapability_AccessMode_Mode{
+			csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+		}, config.ReadWriteMany},
+		{[]csi.VolumeCapability_AccessMode_Mode{
+			csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER,
+			csi.VolumeCapability_AccessMode_MULTI_NODE_READER_ONLY,
+		}, config.ReadWriteMany},
+	} {
+		capabilities := make([]*csi.VolumeCapability, 0)
+		for _, mode := range test.modes {
+			capabilities = append(capabilities, newCapability(mode))
+		}
+		accessMode, err := getAccessMode(capabilities)
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", test.modes, err)
+		} else if accessMode != test.expected {
+			t.Errorf("%v: expected %s, got %s", test.modes, test.expected, accessMode)
+		}
+	}
+
+	blockCapability := &csi.VolumeCapability{
+		AccessType: &csi.VolumeCapability_Block{Block: &csi.VolumeCapability_BlockVolume{}},
+		AccessMode: &csi.VolumeCapability_AccessMode{
+			Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER,
+		},
+	}
+	if _, err := getAccessMode([]*csi.VolumeCapability{blockCapability}); err == nil {
+		t.Error("Expected block access to be rejected.")
+	}
+}
+
+func TestCreateVolume(t *testing.T) {
+	plugin := newTestPlugin(t)
+	ctx := context.Background()
+
+	response, err := plugin.CreateVolume(ctx, newCreateVolumeRequest("vol1", 2<<30))
+	if err != nil {
+		t.Fatalf("Unable to create volume: %v", err)
+	}
+	if response.Volume.VolumeId != "vol1" || response.Volume.CapacityBytes != 2<<30 {
+		t.Errorf("Unexpected volume %v", response.Volume)
+	}
+
+	// Creating the same volume again succeeds
+	if _, err = plugin.CreateVolume(ctx, newCreateVolumeRequest("vol1", 2<<30)); err != nil {
+		t.Errorf("Expected a repeated request to succeed: %v", err)
+	}
+
+	// Creating it with a larger size fails
+	_, err = plugin.CreateVolume(ctx, newCreateVolumeRequest("vol1", 4<<30))
+	if status.Code(err) != codes.AlreadyExists {
+		t.Errorf("Expected AlreadyExists, got %v", err)
+	}
+
+	// An unknown storage class is rejected
+	request := newCreateVolumeRequest("vol2", 1<<30)
+	request.Parameters[storageClassParameter] = "silver"
+	if _, err = plugin.CreateVolume(ctx, request); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument, got %v", err)
+	}
+
+	// Deleting the volume twice succeeds
+	for i := 0; i < 2; i++ {
+		if _, err = plugin.DeleteVolume(ctx, &csi.DeleteVolumeRequest{VolumeId: "vol1"}); err != nil {
+			t.Errorf("Unable to delete volume: %v", err)
+		}
+	}
+}
+
+func TestControllerPublishVolume(t *testing.T) {
+	plugin := newTestPlugin(t)
+	ctx := context.Background()
+
+	if _, err := plugin.CreateVolume(ctx, newCreateVolumeRequest("vol1", 1<<30)); err != nil {
+		t.Fatalf("Unable to create volume: %v", err)
+	}
+
+	publish := func(nodeID string) error {
+		_, err := plugin.ControllerPublishVolume(ctx, &csi.ControllerPublishVolumeRequest{
+			VolumeId:         "vol1",
+			NodeId:           nodeID,
+			VolumeCapability: newCapability(csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER),
+		})
+		return err
+	}
+
+	if err := publish("node1"); err != nil {
+		t.Fatalf("Unable to publish volume: %v", err)
+	}
+	if err := publish("node1"); err != nil {
+		t.Errorf("Expected publishing to the same node again to succeed: %v", err)
+	}
+	if err := publish("node2"); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition, got %v", err)
+	}
+
+	if _, err := plugin.ControllerUnpublishVolume(ctx, &csi.ControllerUnpublishVolumeRequest{
+		VolumeId: "vol1",
+		NodeId:   "node1",
+	}); err != nil {
+		t.Fatalf("Unable to unpublish volume: %v", err)
+	}
+	if err := publish("node2"); err != nil {
+		t.Errorf("Expected publishing to another node after unpublishing to succeed: %v", err)
+	}
+}
+
+func TestListVolumesPagination(t *testing.T) {
+	plugin := newTestPlugin(t)
+	ctx := context.Background()
+
+	for _, name := range []string{"vol3", "vol1", "vol2"} {
+		if _, err := plugin.CreateVolume(ctx, newCreateVolumeRequest(name, 1<<30)); err != nil {
+			t.Fatalf("Unable to create volume %s: %v", name, err)
+		}
+	}
+
+	names := make([]string, 0)
+	token := ""
+	for {
+		response, err := plugin.ListVolumes(ctx, &csi.ListVolumesRequest{MaxEntries: 2, StartingToken: token})
+		if err != nil {
+			t.Fatalf("Unable to list volumes: %v", err)
+		}
+		for _, entry := range response.Entries {
+			names = append(names, entry.Volume.VolumeId)
+		}
+		if token = response.NextToken; token == "" {
+			break
+		}
+	}
+	if len(names) != 3 || names[0] != "vol1" || names[1] != "vol2" || names[2] != "vol3" {
+		t.Errorf("Unexpected volumes %v", names)
+	}
+
+	_, err := plugin.ListVolumes(ctx, &csi.ListVolumesRequest{StartingToken: "invalid"})
+	if status.Code(err) != codes.Aborted {
+		t.Errorf("Expected Aborted, got %v", err)
+	}
+}
+
+func TestSnapshots(t *testing.T) {
+	plugin := newTestPlugin(t)
+	ctx := context.Background()
+
+	for _, name := range []string{"vol1", "vol2"} {
+		if _, err := plugin.CreateVolume(ctx, newCreateVolumeRequest(name, 1<<30)); err != nil {
+			t.Fatalf("Unable to create volume %s: %v", name, err)
+		}
+	}
+
+	request := &csi.CreateSnapshotRequest{SourceVolumeId: "vol1", Name: "snap1"}
+	response, err := plugin.CreateSnapshot(ctx, request)
+	if err != nil {
+		t.Fatalf("Unable to create snapshot: %v", err)
+	}
+	if response.Snapshot.SnapshotId != "vol1/snap1" || response.Snapshot.SourceVolumeId != "vol1" {
+		t.Errorf("Unexpected snapshot %v", response.Snapshot)
+	}
+	if response.Snapshot.CreationTime == nil {
+		t.Error("Expected the snapshot to have a creation time.")
+	}
+
+	// Creating the same snapshot again succeeds, but not for another volume
+	if _, err = plugin.CreateSnapshot(ctx, request); err != nil {
+		t.Errorf("Expected a repeated request to succeed: %v", err)
+	}
+	_, err = plugin.CreateSnapshot(ctx, &csi.CreateSnapshotRequest{SourceVolumeId: "vol2", Name: "snap1"})
+	if status.Code(err) != codes.AlreadyExists {
+		t.Errorf("Expected AlreadyExists, got %v", err)
+	}
+
+	listResponse, err := plugin.ListSnapshots(ctx, &csi.ListSnapshotsRequest{SourceVolumeId: "vol1"})
+	if err != nil {
+		t.Fatalf("Unable to list snapshots: %v", err)
+	}
+	if len(listResponse.Entries) != 1 || listResponse.Entries[0].Snapshot.SnapshotId != "vol1/snap1" {
+		t.Errorf("Unexpected snapshots %v", listResponse.Entries)
+	}
+
+	// Deleting the snapshot twice succeeds
+	for i := 0; i < 2; i++ {
+		if _, err = plugin.DeleteSnapshot(ctx, &csi.DeleteSnapshotRequest{SnapshotId: "vol1/snap1"}); err != nil {
+			t.Errorf("Unable to delete snapshot: %v", err)
+		}
+	}
+	listResponse, err = plugin.ListSnapshots(ctx, &csi.ListSnapshotsRequest{})
+	if err != nil {
+		t.Fatalf("Unable to list snapshots: %v", err)
+	}
+	if len(listResponse.Entries) != 0 {
+		t.Errorf("Unexpected snapshots %v", listResponse.Entries)
+	}
+}
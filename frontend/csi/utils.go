@@ -0,0 +1,70 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package csi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/golang/protobuf/ptypes"
+
+	"github.com/netapp/trident/storage"
+)
+
+// parseSnapshotID splits a CSI snapshot ID, which is the Trident snapshot ID
+// of the form <volume>/<snapshot>, into its volume and snapshot names.
+func parseSnapshotID(snapshotID string) (string, string, error) {
+	parts := strings.SplitN(snapshotID, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid snapshot ID %s", snapshotID)
+	}
+	return parts[0], parts[1], nil
+}
+
+// getCSISnapshot returns the CSI representation of a Trident snapshot.
+func getCSISnapshot(volume *storage.VolumeExternal, snapshot *storage.SnapshotExternal) *csi.Snapshot {
+
+	csiSnapshot := &csi.Snapshot{
+		SnapshotId:     storage.MakeSnapshotID(volume.Config.Name, snapshot.Name),
+		SourceVolumeId: volume.Config.Name,
+		ReadyToUse:     true,
+	}
+	if created, err := time.Parse(time.RFC3339, snapshot.Created); err == nil {
+		if creationTime, err := ptypes.TimestampProto(created); err == nil {
+			csiSnapshot.CreationTime = creationTime
+		}
+	}
+	return csiSnapshot
+}
+
+// paginate returns the range of a list of the supplied length to return for
+// a CSI List call, along with the token for the next page, if any.  The token
+// is simply the index of the first entry on the next page.
+func paginate(length int, startingToken string, maxEntries int32) (int, int, string, error) {
+
+	if maxEntries < 0 {
+		return 0, 0, "", fmt.Errorf("invalid max entries %d", maxEntries)
+	}
+
+	start := 0
+	if startingToken != "" {
+		var err error
+		if start, err = strconv.Atoi(startingToken); err != nil || start < 0 || start > length {
+			return 0, 0, "", fmt.Errorf("invalid starting token %s", startingToken)
+		}
+	}
+
+	end := length
+	if maxEntries > 0 && start+int(maxEntries) < length {
+		end = start + int(maxEntries)
+	}
+
+	nextToken := ""
+	if end < length {
+		nextToken = strconv.Itoa(end)
+	}
+	return start, end, nextToken, nil
+}
@@ -0,0 +1,229 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package csi
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	hash "github.com/mitchellh/hashstructure"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/core"
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/storage_attribute"
+	"github.com/netapp/trident/storage_class"
+	"github.com/netapp/trident/utils"
+)
+
+// getStorageClass accepts the parameters of a CreateVolume request and
+// returns a matching storage class.  If the parameters name a storage class,
+// that is returned.  Otherwise, if the orchestrator already has a storage
+// class matching the parameters, that is returned; failing that, a new one
+// is created and registered with the orchestrator.
+func getStorageClass(parameters map[string]string, o core.Orchestrator) (*storageclass.Config, error) {
+
+	if scName, ok := parameters[storageClassParameter]; ok {
+		sc := o.GetStorageClass(scName)
+		if sc == nil {
+			return nil, fmt.Errorf("storage class %s not found", scName)
+		}
+		return sc.Config, nil
+	}
+
+	// Create a storage class based on available parameters
+	newScConfig, err := makeStorageClass(parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check existing storage classes for a match based on the name
+	sc := o.GetStorageClass(newScConfig.Name)
+	if sc != nil {
+		log.WithField("storageClass", sc.Config.Name).Debug("Matched existing storage class.")
+		return sc.Config, nil
+	}
+
+	// No match found, so register the new storage class
+	addedSc, err := o.AddStorageClass(newScConfig)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"storageClass": newScConfig.Name,
+		}).Error("CSI frontend couldn't add the storage class: ", err)
+		return nil, err
+	}
+
+	return addedSc.Config, nil
+}
+
+// makeStorageClass accepts the parameters of a CreateVolume request and
+// creates a matching storage class.  The name of the new storage class
+// contains a hash of the attributes it contains, thereby enabling comparison
+// of storage classes generated by this method by simply comparing their names.
+func makeStorageClass(parameters map[string]string) (*storageclass.Config, error) {
+
+	scConfig := new(storageclass.Config)
+
+	// Map parameters to storage class attributes
+	scConfig.Attributes = make(map[string]storageattribute.Request)
+	for k, v := range parameters {
+		// format: attribute: "type:value"
+		req, err := storageattribute.CreateAttributeRequestFromAttributeValue(k, v)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"storageClass_parameters": parameters,
+			}).Debug("CSI frontend ignoring storage class attribute: ", err)
+			continue
+		}
+		scConfig.Attributes[k] = req
+	}
+
+	// Set name based on hash value
+	scHash, err := hash.Hash(scConfig, nil)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"storageClass_parameters": parameters,
+		}).Error("CSI frontend couldn't hash the storage class attributes: ", err)
+		return nil, err
+	}
+	scConfig.Name = fmt.Sprintf(autoStorageClassPrefix, scHash)
+
+	return scConfig, nil
+}
+
+// getVolumeConfig accepts the contents of a CreateVolume request and returns
+// a volume config structure suitable for passing to the orchestrator core.
+func getVolumeConfig(
+	name, storageClass string, sizeBytes int64, accessMode config.AccessMode, fsType string,
+	parameters map[string]string,
+) *storage.VolumeConfig {
+
+	if fsType == "" {
+		fsType = utils.GetV(parameters, "fstype|fileSystemType", "")
+	}
+
+	return &storage.VolumeConfig{
+		Name:            name,
+		Size:            fmt.Sprintf("%d", sizeBytes),
+		StorageClass:    storageClass,
+		Protocol:        config.ProtocolAny,
+		AccessMode:      accessMode,
+		SpaceReserve:    utils.GetV(parameters, "spaceReserve", ""),
+		SecurityStyle:   utils.GetV(parameters, "securityStyle", ""),
+		SplitOnClone:    utils.GetV(parameters, "splitOnClone", ""),
+		SnapshotPolicy:  utils.GetV(parameters, "snapshotPolicy", ""),
+		ExportPolicy:    utils.GetV(parameters, "exportPolicy", ""),
+		SnapshotDir:     utils.GetV(parameters, "snapshotDir", ""),
+		UnixPermissions: utils.GetV(parameters, "unixPermissions", ""),
+		BlockSize:       utils.GetV(parameters, "blocksize", ""),
+		QoS:             utils.GetV(parameters, "qos", ""),
+		QoSType:         utils.GetV(parameters, "type", ""),
+		FileSystem:      fsType,
+		Encryption:      utils.GetV(parameters, "encryption", ""),
+	}
+}
+
+// getAccessMode returns the Trident access mode that satisfies all of the
+// supplied volume capabilities.  Trident can only mount volumes, so any
+// request for raw block access is refused.
+func getAccessMode(capabilities []*csi.VolumeCapability) (config.AccessMode, error) {
+
+	if len(capabilities) == 0 {
+		return config.ModeAny, fmt.Errorf("volume capabilities are required")
+	}
+
+	singleNode, multiNodeReader, multiNodeWriter := false, false, false
+	for _, capability := range capabilities {
+		if capability.GetBlock() != nil {
+			return config.ModeAny, fmt.Errorf("block access is not supported")
+		}
+		if capability.GetAccessMode() == nil {
+			return config.ModeAny, fmt.Errorf("volume capability is missing an access mode")
+		}
+		switch capability.GetAccessMode().GetMode() {
+		case csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER,
+			csi.VolumeCapability_AccessMode_SINGLE_NODE_READER_ONLY,
+			csi.VolumeCapability_AccessMode_SINGLE_NODE_SINGLE_WRITER,
+			csi.VolumeCapability_AccessMode_SINGLE_NODE_MULTI_WRITER:
+			singleNode = true
+		case csi.VolumeCapability_AccessMode_MULTI_NODE_READER_ONLY:
+			multiNodeReader = true
+		case csi.VolumeCapability_AccessMode_MULTI_NODE_SINGLE_WRITER,
+			csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER:
+			multiNodeWriter = true
+		default:
+			return config.ModeAny, fmt.Errorf("unsupported access mode %s",
+				capability.GetAccessMode().GetMode())
+		}
+	}
+
+	switch {
+	case multiNodeWriter, multiNodeReader && singleNode:
+		return config.ReadWriteMany, nil
+	case multiNodeReader:
+		return config.ReadOnlyMany, nil
+	default:
+		return config.ReadWriteOnce, nil
+	}
+}
+
+// isReadOnlyCapability returns whether a volume capability only allows reading.
+func isReadOnlyCapability(capability *csi.VolumeCapability) bool {
+	switch capability.GetAccessMode().GetMode() {
+	case csi.VolumeCapability_AccessMode_SINGLE_NODE_READER_ONLY,
+		csi.VolumeCapability_AccessMode_MULTI_NODE_READER_ONLY:
+		return true
+	default:
+		return false
+	}
+}
+
+// getCSIVolume returns the CSI representation of a Trident volume.
+func getCSIVolume(volume *storage.VolumeExternal) *csi.Volume {
+
+	csiVolume := &csi.Volume{
+		VolumeId:      volume.Config.Name,
+		CapacityBytes: getVolumeSizeBytes(volume),
+		VolumeContext: map[string]string{
+			"backend":      volume.Backend,
+			"internalName": volume.Config.InternalName,
+			"protocol":     string(volume.Config.Protocol),
+		},
+	}
+
+	if volume.Config.CloneSourceVolume != "" {
+		if volume.Config.CloneSourceSnapshot != "" {
+			csiVolume.ContentSource = &csi.VolumeContentSource{
+				Type: &csi.VolumeContentSource_Snapshot{
+					Snapshot: &csi.VolumeContentSource_SnapshotSource{
+						SnapshotId: storage.MakeSnapshotID(
+							volume.Config.CloneSourceVolume, volume.Config.CloneSourceSnapshot),
+					},
+				},
+			}
+		} else {
+			csiVolume.ContentSource = &csi.VolumeContentSource{
+				Type: &csi.VolumeContentSource_Volume{
+					Volume: &csi.VolumeContentSource_VolumeSource{
+						VolumeId: volume.Config.CloneSourceVolume,
+					},
+				},
+			}
+		}
+	}
+
+	return csiVolume
+}
+
+// getVolumeSizeBytes returns the size of a Trident volume, or zero if it
+// can't be parsed.
+func getVolumeSizeBytes(volume *storage.VolumeExternal) int64 {
+	sizeBytesStr, err := utils.ConvertSizeToBytes(volume.Config.Size)
+	if err != nil {
+		return 0
+	}
+	sizeBytes, _ := strconv.ParseInt(sizeBytesStr, 10, 64)
+	return sizeBytes
+}
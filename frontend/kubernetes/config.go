@@ -39,6 +39,11 @@ const (
 	AnnCloneFromPVC    = AnnPrefix + "/cloneFromPVC"
 	AnnSplitOnClone    = AnnPrefix + "/splitOnClone"
 
+	// Annotations that request import of an existing backend volume
+	AnnImportBackend      = AnnPrefix + "/importBackend"
+	AnnImportOriginalName = AnnPrefix + "/importOriginalName"
+	AnnImportRename       = AnnPrefix + "/importRename"
+
 	// Minimum and maximum supported Kubernetes versions
 	KubernetesVersionMin = "v1.5.0"
 	KubernetesVersionMax = "v1.9.0"
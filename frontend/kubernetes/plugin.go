@@ -7,6 +7,7 @@ package kubernetes
 import (
 	"fmt"
 	"io/ioutil"
+	"reflect"
 	"strings"
 	"sync"
 
@@ -15,6 +16,7 @@ import (
 	"k8s.io/api/core/v1"
 	k8sstoragev1 "k8s.io/api/storage/v1"
 	k8sstoragev1beta "k8s.io/api/storage/v1beta1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	k8sversion "k8s.io/apimachinery/pkg/version"
@@ -640,10 +642,13 @@ func (p *Plugin) createVolumeAndPV(uniqueName string, claim *v1.PersistentVolume
 		iscsiSource        *v1.ISCSIPersistentVolumeSource
 		vol                *storage.VolumeExternal
 		storageClassParams map[string]string
+		importing          bool
 	)
 
 	defer func() {
-		if vol != nil && err != nil {
+		// An imported volume holds data that Trident didn't create, so it
+		// is left alone if anything goes wrong.
+		if vol != nil && err != nil && !importing {
 			err1 := err
 			// Delete the volume on the backend
 			_, err = p.orchestrator.DeleteVolume(vol.Config.Name)
@@ -694,7 +699,23 @@ func (p *Plugin) createVolumeAndPV(uniqueName string, claim *v1.PersistentVolume
 
 	// Create the volume configuration object
 	volConfig := getVolumeConfig(accessModes, uniqueName, size, annotations)
-	if volConfig.CloneSourceVolume == "" {
+	importBackend, importing := annotations[AnnImportBackend]
+	if importing {
+		// A previous attempt may have imported the volume before failing to
+		// create the PV, so reuse the volume if Trident already has it.
+		if vol = p.orchestrator.GetVolume(uniqueName); vol == nil {
+			rename := strings.ToLower(annotations[AnnImportRename]) == "true"
+			vol, err = p.orchestrator.ImportVolume(importBackend, annotations[AnnImportOriginalName],
+				volConfig, rename)
+		}
+		if err == nil {
+			// The PV reflects the size of the imported volume rather than
+			// the size requested by the claim.
+			if importedSize, parseErr := resource.ParseQuantity(vol.Config.Size); parseErr == nil {
+				size = importedSize
+			}
+		}
+	} else if volConfig.CloneSourceVolume == "" {
 		vol, err = p.orchestrator.AddVolume(volConfig)
 	} else {
 		var (
@@ -813,6 +834,7 @@ func (p *Plugin) createVolumeAndPV(uniqueName string, claim *v1.PersistentVolume
 	switch {
 	case driverType == drivers.SolidfireSANStorageDriverName ||
 		driverType == drivers.OntapSANStorageDriverName ||
+		driverType == drivers.OntapSANEconomyStorageDriverName ||
 		driverType == drivers.EseriesIscsiStorageDriverName:
 		iscsiSource, err = CreateISCSIPersistentVolumeSource(k8sClientCHAP, kubeVersion, vol)
 		if err != nil {
@@ -820,7 +842,8 @@ func (p *Plugin) createVolumeAndPV(uniqueName string, claim *v1.PersistentVolume
 		}
 		pv.Spec.ISCSI = iscsiSource
 	case driverType == drivers.OntapNASStorageDriverName ||
-		driverType == drivers.OntapNASQtreeStorageDriverName:
+		driverType == drivers.OntapNASQtreeStorageDriverName ||
+		driverType == drivers.OntapNASFlexGroupStorageDriverName:
 		nfsSource = CreateNFSVolumeSource(vol)
 		pv.Spec.NFS = nfsSource
 	case driverType == drivers.FakeStorageDriverName:
@@ -1149,7 +1172,12 @@ func (p *Plugin) processClass(
 	}
 }
 
-func (p *Plugin) processAddedClass(class *k8sstoragev1.StorageClass) {
+// getStorageClassConfig translates the parameters of a Kubernetes storage
+// class into a Trident storage class config.  It also returns the parameters
+// that are processed only by the frontend.
+func getStorageClassConfig(
+	class *k8sstoragev1.StorageClass,
+) (*storageclass.Config, map[string]string, error) {
 	scConfig := new(storageclass.Config)
 	scConfig.Name = class.Name
 	scConfig.Attributes = make(map[string]storageattribute.Request)
@@ -1188,6 +1216,10 @@ func (p *Plugin) processAddedClass(class *k8sstoragev1.StorageClass) {
 			}
 			scConfig.Pools = pools
 
+		case storageattribute.PoolSelectionPolicy:
+			// format:  poolSelectionPolicy: "mostFreeSpace"
+			scConfig.PoolSelection = storageclass.PoolSelectionPolicy(v)
+
 		default:
 			// format:  attribute: "value"
 			req, err := storageattribute.CreateAttributeRequestFromAttributeValue(k, v)
@@ -1198,12 +1230,21 @@ func (p *Plugin) processAddedClass(class *k8sstoragev1.StorageClass) {
 					"storageClass_parameters":  class.Parameters,
 					"error":                    err,
 				}).Errorf("Kubernetes frontend couldn't process the storage class attribute %s", k)
-				return
+				return nil, nil, err
 			}
 			scConfig.Attributes[k] = req
 		}
 	}
 
+	return scConfig, k8sStorageClassParams, nil
+}
+
+func (p *Plugin) processAddedClass(class *k8sstoragev1.StorageClass) {
+	scConfig, k8sStorageClassParams, err := getStorageClassConfig(class)
+	if err != nil {
+		return
+	}
+
 	// Update Kubernetes-defined storage class parameters maintained by the
 	// frontend. Note that these parameters are only processed by the frontend
 	// and not by Trident core.
@@ -1281,7 +1322,8 @@ func (p *Plugin) processDeletedClass(class *k8sstoragev1.StorageClass) {
 }
 
 func (p *Plugin) processUpdatedClass(class *k8sstoragev1.StorageClass) {
-	// Here we only check for updates associated with the default storage class.
+	p.updateClassConfig(class)
+
 	p.mutex.Lock()
 	defer func() {
 		p.mutex.Unlock()
@@ -1292,13 +1334,7 @@ func (p *Plugin) processUpdatedClass(class *k8sstoragev1.StorageClass) {
 		// Check to see if it's still a default storage class.
 		if getAnnotation(class.Annotations, AnnDefaultStorageClass) != "true" {
 			delete(p.defaultStorageClasses, class.Name)
-			return
 		}
-		log.WithFields(log.Fields{
-			"storageClass":           class.Name,
-			"default_storageClasses": p.getDefaultStorageClasses(),
-		}).Debug("Kubernetes frontend only supports updating the " +
-			"default storage class tag.")
 		return
 	} else {
 		// It's an update to a non-default storage class.
@@ -1309,15 +1345,53 @@ func (p *Plugin) processUpdatedClass(class *k8sstoragev1.StorageClass) {
 				"storageClass":           class.Name,
 				"default_storageClasses": p.getDefaultStorageClasses(),
 			}).Info("Kubernetes frontend added a new default storage class.")
-			return
 		}
+		return
+	}
+}
+
+// updateClassConfig updates Trident's storage class if its config no longer
+// matches the parameters of the Kubernetes storage class, such as when the
+// Kubernetes class was recreated with new parameters while Trident wasn't
+// watching.
+func (p *Plugin) updateClassConfig(class *k8sstoragev1.StorageClass) {
+	scConfig, k8sStorageClassParams, err := getStorageClassConfig(class)
+	if err != nil {
+		return
+	}
+
+	p.mutex.Lock()
+	p.storageClassCache[class.Name] = &StorageClassSummary{
+		Parameters:                    k8sStorageClassParams,
+		MountOptions:                  class.MountOptions,
+		PersistentVolumeReclaimPolicy: class.ReclaimPolicy,
+	}
+	p.mutex.Unlock()
+
+	existing := p.orchestrator.GetStorageClass(class.Name)
+	if existing == nil {
+		return
+	}
+	scConfig.Version = existing.Config.Version
+	if reflect.DeepEqual(scConfig, existing.Config) {
+		return
+	}
+
+	sc, err := p.orchestrator.UpdateStorageClass(scConfig)
+	if err != nil {
 		log.WithFields(log.Fields{
-			"storageClass":           class.Name,
-			"default_storageClasses": p.getDefaultStorageClasses(),
-		}).Debug("Kubernetes frontend only supports updating the " +
-			"default storage class tag.")
+			"storageClass":             class.Name,
+			"storageClass_provisioner": class.Provisioner,
+			"storageClass_parameters":  class.Parameters,
+		}).Error("Kubernetes frontend couldn't update the storage class: ", err)
 		return
 	}
+	log.WithFields(log.Fields{
+		"storageClass":             class.Name,
+		"storageClass_provisioner": class.Provisioner,
+		"storageClass_parameters":  class.Parameters,
+		"storagePools":             sc.StoragePools,
+	}).Info("Kubernetes frontend successfully updated the storage class.")
 }
 
 func (p *Plugin) getDefaultStorageClasses() string {
@@ -21,6 +21,7 @@ var orchestrator core.Orchestrator
 
 type APIServer struct {
 	server *http.Server
+	name   string
 }
 
 func NewAPIServer(p core.Orchestrator, address, port string) *APIServer {
@@ -37,13 +38,58 @@ func NewAPIServer(p core.Orchestrator, address, port string) *APIServer {
 			ReadTimeout:  httpTimeout,
 			WriteTimeout: httpTimeout,
 		},
+		name: "REST",
 	}
 }
 
+// NewHTTPSAPIServer returns an APIServer that serves the REST API over TLS
+// and only to authenticated clients, so that it may be exposed beyond the
+// local host.
+func NewHTTPSAPIServer(
+	p core.Orchestrator, address, port string, security *ServerSecurityConfig,
+) (*APIServer, error) {
+
+	tlsConfig, err := NewServerTLSConfig(security)
+	if err != nil {
+		return nil, err
+	}
+	var tokens []string
+	if security.TokenFile != "" {
+		if tokens, err = loadTokens(security.TokenFile); err != nil {
+			return nil, err
+		}
+	}
+
+	orchestrator = p
+
+	addressPort := address + ":" + port
+	log.WithFields(log.Fields{
+		"clientCertificates": security.ClientCAFile != "",
+		"bearerTokens":       len(tokens) > 0,
+	}).Infof("Starting HTTPS REST interface on %s", addressPort)
+
+	return &APIServer{
+		server: &http.Server{
+			Addr:         addressPort,
+			Handler:      Authenticator(NewRouter(), tokens),
+			TLSConfig:    tlsConfig,
+			ReadTimeout:  httpTimeout,
+			WriteTimeout: httpTimeout,
+		},
+		name: "HTTPS REST",
+	}, nil
+}
+
 func (s *APIServer) Activate() error {
 	go func() {
-		err := s.server.ListenAndServe()
-		if err != nil {
+		var err error
+		if s.server.TLSConfig != nil {
+			// The certificate is already loaded into the TLS config.
+			err = s.server.ListenAndServeTLS("", "")
+		} else {
+			err = s.server.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			log.Fatal(err)
 		}
 	}()
@@ -57,7 +103,7 @@ func (s *APIServer) Deactivate() error {
 }
 
 func (s *APIServer) GetName() string {
-	return "REST"
+	return s.name
 }
 
 func (s *APIServer) Version() string {
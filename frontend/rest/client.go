@@ -4,6 +4,7 @@ package rest
 
 import (
 	"bytes"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -39,9 +40,11 @@ type Interface interface {
 }
 
 type TridentClient struct {
-	ip     string
-	port   int
-	client *http.Client
+	ip        string
+	port      int
+	client    *http.Client
+	tlsConfig *tls.Config
+	token     string
 }
 
 func NewTridentClient(ip string, port, timeout int) *TridentClient {
@@ -54,35 +57,66 @@ func NewTridentClient(ip string, port, timeout int) *TridentClient {
 	}
 }
 
+// NewSecureTridentClient returns a client for an HTTPS REST interface, which
+// authenticates with the certificate or token in the security config.
+func NewSecureTridentClient(
+	ip string, port, timeout int, security *ClientSecurityConfig,
+) (*TridentClient, error) {
+
+	tlsConfig, err := NewClientTLSConfig(security)
+	if err != nil {
+		return nil, err
+	}
+	client := &TridentClient{tlsConfig: tlsConfig, token: security.Token}
+	client.Configure(ip, port, timeout)
+	return client, nil
+}
+
 func (client *TridentClient) Configure(ip string, port, timeout int) Interface {
 	client.ip = ip
 	client.port = port
 	client.client = &http.Client{
 		Timeout: time.Duration(timeout) * time.Second,
 	}
+	if client.tlsConfig != nil {
+		client.client.Transport = &http.Transport{TLSClientConfig: client.tlsConfig}
+	}
 	return client
 }
 
+func (client *TridentClient) url(endpoint string) string {
+	scheme := "http"
+	if client.tlsConfig != nil {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s:%d/trident/v%s/%s",
+		scheme, client.ip, client.port, config.OrchestratorAPIVersion, endpoint)
+}
+
+func (client *TridentClient) do(method, endpoint string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequest(method, client.url(endpoint), body)
+	if err != nil {
+		return &http.Response{}, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if client.token != "" {
+		req.Header.Set("Authorization", "Bearer "+client.token)
+	}
+	return client.client.Do(req)
+}
+
 func (client *TridentClient) Get(endpoint string) (*http.Response, error) {
-	return client.client.Get(fmt.Sprintf("http://%s:%d/trident/v%s/%s",
-		client.ip, client.port, config.OrchestratorAPIVersion, endpoint))
+	return client.do(http.MethodGet, endpoint, nil)
 }
 
 func (client *TridentClient) Post(endpoint string, body io.Reader) (*http.Response, error) {
-	return client.client.Post(fmt.Sprintf("http://%s:%d/trident/v%s/%s",
-		client.ip, client.port, config.OrchestratorAPIVersion, endpoint),
-		contentType, body)
+	return client.do(http.MethodPost, endpoint, body)
 }
 
 func (client *TridentClient) Delete(endpoint string) (*http.Response, error) {
-	req, err := http.NewRequest(http.MethodDelete,
-		fmt.Sprintf("http://%s:%d/trident/v%s/%s",
-			client.ip, client.port, config.OrchestratorAPIVersion, endpoint),
-		nil)
-	if err != nil {
-		return &http.Response{}, err
-	}
-	return client.client.Do(req)
+	return client.do(http.MethodDelete, endpoint, nil)
 }
 
 func (client *TridentClient) GetBackend(backendID string) (*GetBackendResponse, error) {
@@ -13,6 +13,7 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/persistent_store"
 	"github.com/netapp/trident/storage"
 	"github.com/netapp/trident/storage_class"
 )
@@ -78,6 +79,17 @@ type addResponse interface {
 	logFailure()
 }
 
+// asyncResponse is implemented by the responses to requests that may be
+// completed in the background, which carry the operation that completes them.
+type asyncResponse interface {
+	getOperation() *persistentstore.Operation
+}
+
+// isAsync returns whether a request asked to be completed in the background.
+func isAsync(r *http.Request) bool {
+	return r.URL.Query().Get("async") == "true"
+}
+
 func AddGeneric(
 	w http.ResponseWriter,
 	r *http.Request,
@@ -91,6 +103,10 @@ func AddGeneric(
 		if response.isError() {
 			response.logFailure()
 			w.WriteHeader(http.StatusBadRequest)
+		} else if op := getOperation(response); op != nil {
+			response.logSuccess()
+			w.Header().Set("Location", config.OperationURL+"/"+op.ID)
+			w.WriteHeader(http.StatusAccepted)
 		} else {
 			response.logSuccess()
 			w.WriteHeader(http.StatusCreated)
@@ -112,6 +128,55 @@ func AddGeneric(
 	add(body)
 }
 
+// getOperation returns the operation started by a request, or nil if the
+// request was completed before the response was sent.
+func getOperation(response addResponse) *persistentstore.Operation {
+	if async, ok := response.(asyncResponse); ok {
+		return async.getOperation()
+	}
+	return nil
+}
+
+type updateFunc func(name string, body []byte) int
+
+// UpdateGeneric reads the request body and passes it, along with the name of
+// the target object, to the supplied update function.  The update function
+// returns the HTTP status code to send back to the client.
+func UpdateGeneric(
+	w http.ResponseWriter,
+	r *http.Request,
+	varName string,
+	response addResponse,
+	update updateFunc,
+) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	status := http.StatusBadRequest
+	defer func() {
+		if response.isError() {
+			response.logFailure()
+		} else {
+			response.logSuccess()
+		}
+		w.WriteHeader(status)
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			panic(err)
+		}
+	}()
+
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, config.MaxRESTRequestSize))
+	if err != nil {
+		response.setError(err)
+		return
+	}
+	if err := r.Body.Close(); err != nil {
+		response.setError(err)
+		return
+	}
+	vars := mux.Vars(r)
+	status = update(vars[varName], body)
+}
+
 type DeleteResponse struct {
 	Error string `json:"error,omitempty"`
 }
@@ -254,16 +319,75 @@ func GetBackend(w http.ResponseWriter, r *http.Request) {
 	)
 }
 
-// DeleteBackend calls OfflineBackend in the orchestrator, as we currently do
-// not allow for full deletion of backends due to the potential for race
-// conditions and the additional bookkeeping that would be required.
+type UpdateBackendResponse struct {
+	Update *storage.BackendUpdateExternal `json:"update"`
+	Error  string                         `json:"error,omitempty"`
+}
+
+func (u *UpdateBackendResponse) setError(err error) {
+	u.Error = err.Error()
+}
+
+func (u *UpdateBackendResponse) isError() bool {
+	return u.Error != ""
+}
+
+func (u *UpdateBackendResponse) logSuccess() {
+	log.WithFields(log.Fields{
+		"handler": "UpdateBackend",
+		"backend": u.Update.Backend.Name,
+		"dryRun":  u.Update.DryRun,
+	}).Info("Updated a backend.")
+}
+
+func (u *UpdateBackendResponse) logFailure() {
+	log.WithFields(log.Fields{
+		"handler": "UpdateBackend",
+	}).Error(u.Error)
+}
+
+// UpdateBackend replaces the configuration of a backend with the one in the
+// request body.  If the dryRun query parameter is true, the update is only
+// evaluated.
+func UpdateBackend(w http.ResponseWriter, r *http.Request) {
+	response := &UpdateBackendResponse{
+		Update: nil,
+		Error:  "",
+	}
+	dryRun := r.URL.Query().Get("dryRun") == "true"
+	UpdateGeneric(w, r, "backend", response,
+		func(backendName string, body []byte) int {
+			if orchestrator.GetBackend(backendName) == nil {
+				response.Error = fmt.Sprintf("Backend %v was not found!",
+					backendName)
+				return http.StatusNotFound
+			}
+			update, err := orchestrator.UpdateBackend(backendName, string(body), dryRun)
+			if err != nil {
+				response.setError(err)
+				return http.StatusBadRequest
+			}
+			response.Update = update
+			return http.StatusOK
+		},
+	)
+}
+
+// DeleteBackend deletes the backend right away if it has no volumes.
+// Otherwise the backend is kept in the deleting state until its last volume
+// is deleted.
 func DeleteBackend(w http.ResponseWriter, r *http.Request) {
-	DeleteGeneric(w, r, orchestrator.OfflineBackend, "backend")
+	DeleteGeneric(w, r, orchestrator.DeleteBackend, "backend")
 }
 
 type AddVolumeResponse struct {
-	BackendID string `json:"backend"`
-	Error     string `json:"error,omitempty"`
+	BackendID string                     `json:"backend"`
+	Operation *persistentstore.Operation `json:"operation,omitempty"`
+	Error     string                     `json:"error,omitempty"`
+}
+
+func (a *AddVolumeResponse) getOperation() *persistentstore.Operation {
+	return a.Operation
 }
 
 func (a *AddVolumeResponse) setError(err error) {
@@ -275,6 +399,13 @@ func (a *AddVolumeResponse) isError() bool {
 }
 
 func (a *AddVolumeResponse) logSuccess() {
+	if a.Operation != nil {
+		log.WithFields(log.Fields{
+			"handler":   "AddVolume",
+			"operation": a.Operation.ID,
+		}).Info("Started adding a new volume.")
+		return
+	}
 	log.WithFields(log.Fields{
 		"handler": "AddVolume",
 		"backend": a.BackendID,
@@ -286,11 +417,15 @@ func (a *AddVolumeResponse) logFailure() {
 	}).Error(a.Error)
 }
 
+// AddVolume creates a volume.  If the async query parameter is true, the
+// volume is created in the background, and the response carries the operation
+// through which its creation may be followed.
 func AddVolume(w http.ResponseWriter, r *http.Request) {
 	response := &AddVolumeResponse{
 		BackendID: "",
 		Error:     "",
 	}
+	async := isAsync(r)
 	AddGeneric(w, r, response,
 		func(body []byte) {
 			volumeConfig := new(storage.VolumeConfig)
@@ -303,6 +438,13 @@ func AddVolume(w http.ResponseWriter, r *http.Request) {
 				response.setError(err)
 				return
 			}
+			if async {
+				response.Operation, err = orchestrator.AddVolumeAsync(volumeConfig)
+				if err != nil {
+					response.setError(err)
+				}
+				return
+			}
 			volume, err := orchestrator.AddVolume(volumeConfig)
 			if err != nil {
 				response.setError(err)
@@ -365,6 +507,403 @@ func DeleteVolume(w http.ResponseWriter, r *http.Request) {
 	DeleteGeneric(w, r, orchestrator.DeleteVolume, "volume")
 }
 
+type ResizeVolumeRequest struct {
+	Size string `json:"size"`
+}
+
+type ResizeVolumeResponse struct {
+	Volume *storage.VolumeExternal `json:"volume"`
+	Error  string                  `json:"error,omitempty"`
+}
+
+func (a *ResizeVolumeResponse) setError(err error) {
+	a.Error = err.Error()
+}
+
+func (a *ResizeVolumeResponse) isError() bool {
+	return a.Error != ""
+}
+
+func (a *ResizeVolumeResponse) logSuccess() {
+	log.WithFields(log.Fields{
+		"handler": "ResizeVolume",
+		"volume":  a.Volume.Config.Name,
+		"size":    a.Volume.Config.Size,
+	}).Info("Resized a volume.")
+}
+
+func (a *ResizeVolumeResponse) logFailure() {
+	log.WithFields(log.Fields{
+		"handler": "ResizeVolume",
+	}).Error(a.Error)
+}
+
+func ResizeVolume(w http.ResponseWriter, r *http.Request) {
+	response := &ResizeVolumeResponse{
+		Volume: nil,
+		Error:  "",
+	}
+	UpdateGeneric(w, r, "volume", response,
+		func(volName string, body []byte) int {
+			request := new(ResizeVolumeRequest)
+			err := json.Unmarshal(body, request)
+			if err != nil {
+				response.Error = "Invalid JSON: " + err.Error()
+				return http.StatusBadRequest
+			}
+			if request.Size == "" {
+				response.Error = "Volume size must be specified."
+				return http.StatusBadRequest
+			}
+			if orchestrator.GetVolume(volName) == nil {
+				response.Error = fmt.Sprintf("Volume %v was not found!",
+					volName)
+				return http.StatusNotFound
+			}
+			if err = orchestrator.ResizeVolume(volName, request.Size); err != nil {
+				response.setError(err)
+				return http.StatusBadRequest
+			}
+			response.Volume = orchestrator.GetVolume(volName)
+			return http.StatusOK
+		},
+	)
+}
+
+type GetVolumeReplicationResponse struct {
+	Replication *storage.ReplicationStatus `json:"replication"`
+	Error       string                     `json:"error,omitempty"`
+}
+
+func GetVolumeReplication(w http.ResponseWriter, r *http.Request) {
+	response := &GetVolumeReplicationResponse{
+		Replication: nil,
+		Error:       "",
+	}
+	GetGeneric(w, r, "volume", response,
+		func(volName string) int {
+			if orchestrator.GetVolume(volName) == nil {
+				response.Error = fmt.Sprintf("Volume %v was not found!",
+					volName)
+				return http.StatusNotFound
+			}
+			status, err := orchestrator.GetVolumeReplication(volName)
+			if err != nil {
+				response.Error = err.Error()
+				return http.StatusBadRequest
+			}
+			response.Replication = status
+			return http.StatusOK
+		},
+	)
+}
+
+type FailoverVolumeResponse struct {
+	Volume *storage.VolumeExternal `json:"volume"`
+	Error  string                  `json:"error,omitempty"`
+}
+
+func (a *FailoverVolumeResponse) setError(err error) {
+	a.Error = err.Error()
+}
+
+func (a *FailoverVolumeResponse) isError() bool {
+	return a.Error != ""
+}
+
+func (a *FailoverVolumeResponse) logSuccess() {
+	log.WithFields(log.Fields{
+		"handler": "FailoverVolume",
+		"volume":  a.Volume.Config.Name,
+	}).Info("Failed over a volume to its replica.")
+}
+
+func (a *FailoverVolumeResponse) logFailure() {
+	log.WithFields(log.Fields{
+		"handler": "FailoverVolume",
+	}).Error(a.Error)
+}
+
+func FailoverVolume(w http.ResponseWriter, r *http.Request) {
+	response := &FailoverVolumeResponse{
+		Volume: nil,
+		Error:  "",
+	}
+	UpdateGeneric(w, r, "volume", response,
+		func(volName string, body []byte) int {
+			if orchestrator.GetVolume(volName) == nil {
+				response.Error = fmt.Sprintf("Volume %v was not found!",
+					volName)
+				return http.StatusNotFound
+			}
+			volume, err := orchestrator.FailoverVolume(volName)
+			if err != nil {
+				response.setError(err)
+				return http.StatusBadRequest
+			}
+			response.Volume = volume
+			return http.StatusOK
+		},
+	)
+}
+
+type ImportVolumeRequest struct {
+	Backend      string                `json:"backend"`
+	InternalName string                `json:"internalName"`
+	Rename       bool                  `json:"rename"`
+	Config       *storage.VolumeConfig `json:"config"`
+}
+
+type ImportVolumeResponse struct {
+	Volume    *storage.VolumeExternal    `json:"volume"`
+	Operation *persistentstore.Operation `json:"operation,omitempty"`
+	Error     string                     `json:"error,omitempty"`
+}
+
+func (a *ImportVolumeResponse) getOperation() *persistentstore.Operation {
+	return a.Operation
+}
+
+func (a *ImportVolumeResponse) setError(err error) {
+	a.Error = err.Error()
+}
+
+func (a *ImportVolumeResponse) isError() bool {
+	return a.Error != ""
+}
+
+func (a *ImportVolumeResponse) logSuccess() {
+	if a.Operation != nil {
+		log.WithFields(log.Fields{
+			"handler":   "ImportVolume",
+			"volume":    a.Operation.Target,
+			"operation": a.Operation.ID,
+		}).Info("Started importing a volume.")
+		return
+	}
+	log.WithFields(log.Fields{
+		"handler":      "ImportVolume",
+		"volume":       a.Volume.Config.Name,
+		"backend":      a.Volume.Backend,
+		"originalName": a.Volume.Config.ImportOriginalName,
+	}).Info("Imported a volume.")
+}
+
+func (a *ImportVolumeResponse) logFailure() {
+	log.WithFields(log.Fields{
+		"handler": "ImportVolume",
+	}).Error(a.Error)
+}
+
+// ImportVolume imports a volume.  Like AddVolume, it may be completed in the
+// background.
+func ImportVolume(w http.ResponseWriter, r *http.Request) {
+	response := &ImportVolumeResponse{
+		Volume: nil,
+		Error:  "",
+	}
+	async := isAsync(r)
+	AddGeneric(w, r, response,
+		func(body []byte) {
+			request := new(ImportVolumeRequest)
+			err := json.Unmarshal(body, request)
+			if err != nil {
+				response.Error = "Invalid JSON: " + err.Error()
+				return
+			}
+			if request.Backend == "" || request.InternalName == "" {
+				response.Error = "Backend and internal volume name must be specified."
+				return
+			}
+			if request.Config == nil || request.Config.Name == "" {
+				response.Error = "Volume name must be specified."
+				return
+			}
+			if async {
+				response.Operation, err = orchestrator.ImportVolumeAsync(request.Backend, request.InternalName,
+					request.Config, request.Rename)
+				if err != nil {
+					response.setError(err)
+				}
+				return
+			}
+			volume, err := orchestrator.ImportVolume(request.Backend, request.InternalName, request.Config,
+				request.Rename)
+			if err != nil {
+				response.setError(err)
+			}
+			response.Volume = volume
+		},
+	)
+}
+
+type AddSnapshotRequest struct {
+	Name string `json:"name"`
+}
+
+type AddSnapshotResponse struct {
+	Snapshot *storage.SnapshotExternal `json:"snapshot"`
+	Error    string                    `json:"error,omitempty"`
+}
+
+func (a *AddSnapshotResponse) setError(err error) {
+	a.Error = err.Error()
+}
+
+func (a *AddSnapshotResponse) isError() bool {
+	return a.Error != ""
+}
+
+func (a *AddSnapshotResponse) logSuccess() {
+	log.WithFields(log.Fields{
+		"handler":  "AddSnapshot",
+		"snapshot": a.Snapshot.Name,
+	}).Info("Added a new snapshot.")
+}
+
+func (a *AddSnapshotResponse) logFailure() {
+	log.WithFields(log.Fields{
+		"handler": "AddSnapshot",
+	}).Error(a.Error)
+}
+
+func AddSnapshot(w http.ResponseWriter, r *http.Request) {
+	response := &AddSnapshotResponse{
+		Snapshot: nil,
+		Error:    "",
+	}
+	AddGeneric(w, r, response,
+		func(body []byte) {
+			request := new(AddSnapshotRequest)
+			err := json.Unmarshal(body, request)
+			if err != nil {
+				response.Error = "Invalid JSON: " + err.Error()
+				return
+			}
+			if request.Name == "" {
+				response.Error = "Snapshot name must be specified."
+				return
+			}
+			volName := mux.Vars(r)["volume"]
+			snapshot, err := orchestrator.CreateSnapshot(volName, request.Name)
+			if err != nil {
+				response.setError(err)
+			}
+			response.Snapshot = snapshot
+		},
+	)
+}
+
+type ListSnapshotsResponse struct {
+	Snapshots []string `json:"snapshots"`
+	Error     string   `json:"error,omitempty"`
+}
+
+func ListSnapshots(w http.ResponseWriter, r *http.Request) {
+	response := &ListSnapshotsResponse{
+		Snapshots: make([]string, 0),
+		Error:     "",
+	}
+	GetGeneric(w, r, "volume", response,
+		func(volName string) int {
+			snapshots, err := orchestrator.ListSnapshots(volName)
+			if err != nil {
+				response.Error = err.Error()
+				return http.StatusNotFound
+			}
+			for _, s := range snapshots {
+				response.Snapshots = append(response.Snapshots, s.Name)
+			}
+			return http.StatusOK
+		},
+	)
+}
+
+type GetSnapshotResponse struct {
+	Snapshot *storage.SnapshotExternal `json:"snapshot"`
+	Error    string                    `json:"error,omitempty"`
+}
+
+func GetSnapshot(w http.ResponseWriter, r *http.Request) {
+	response := &GetSnapshotResponse{
+		Snapshot: nil,
+		Error:    "",
+	}
+	GetGeneric(w, r, "snapshot", response,
+		func(snapName string) int {
+			volName := mux.Vars(r)["volume"]
+			snapshot := orchestrator.GetSnapshot(volName, snapName)
+			if snapshot == nil {
+				response.Error = fmt.Sprintf("Snapshot %v of volume %v was not found!",
+					snapName, volName)
+				return http.StatusNotFound
+			}
+			response.Snapshot = snapshot
+			return http.StatusOK
+		},
+	)
+}
+
+func DeleteSnapshot(w http.ResponseWriter, r *http.Request) {
+	volName := mux.Vars(r)["volume"]
+	DeleteGeneric(w, r,
+		func(snapName string) (bool, error) {
+			return orchestrator.DeleteSnapshot(volName, snapName)
+		},
+		"snapshot",
+	)
+}
+
+type RestoreSnapshotResponse struct {
+	Snapshot *storage.SnapshotExternal `json:"snapshot"`
+	Error    string                    `json:"error,omitempty"`
+}
+
+func (a *RestoreSnapshotResponse) setError(err error) {
+	a.Error = err.Error()
+}
+
+func (a *RestoreSnapshotResponse) isError() bool {
+	return a.Error != ""
+}
+
+func (a *RestoreSnapshotResponse) logSuccess() {
+	log.WithFields(log.Fields{
+		"handler":  "RestoreSnapshot",
+		"snapshot": a.Snapshot.Name,
+	}).Info("Restored a volume from a snapshot.")
+}
+
+func (a *RestoreSnapshotResponse) logFailure() {
+	log.WithFields(log.Fields{
+		"handler": "RestoreSnapshot",
+	}).Error(a.Error)
+}
+
+func RestoreSnapshot(w http.ResponseWriter, r *http.Request) {
+	response := &RestoreSnapshotResponse{
+		Snapshot: nil,
+		Error:    "",
+	}
+	UpdateGeneric(w, r, "snapshot", response,
+		func(snapName string, body []byte) int {
+			volName := mux.Vars(r)["volume"]
+			snapshot := orchestrator.GetSnapshot(volName, snapName)
+			if snapshot == nil {
+				response.Error = fmt.Sprintf("Snapshot %v of volume %v was not found!",
+					snapName, volName)
+				return http.StatusNotFound
+			}
+			if err := orchestrator.RestoreSnapshot(volName, snapName); err != nil {
+				response.setError(err)
+				return http.StatusBadRequest
+			}
+			response.Snapshot = snapshot
+			return http.StatusOK
+		},
+	)
+}
+
 type AddStorageClassResponse struct {
 	StorageClassID string `json:"storageClass"`
 	Error          string `json:"error,omitempty"`
@@ -415,6 +954,68 @@ func AddStorageClass(w http.ResponseWriter, r *http.Request) {
 	)
 }
 
+type UpdateStorageClassResponse struct {
+	StorageClass *storageclass.External `json:"storageClass"`
+	Error        string                 `json:"error,omitempty"`
+}
+
+func (u *UpdateStorageClassResponse) setError(err error) {
+	u.Error = err.Error()
+}
+
+func (u *UpdateStorageClassResponse) isError() bool {
+	return u.Error != ""
+}
+
+func (u *UpdateStorageClassResponse) logSuccess() {
+	log.WithFields(log.Fields{
+		"handler":      "UpdateStorageClass",
+		"storageClass": u.StorageClass.GetName(),
+	}).Info("Updated a storage class.")
+}
+
+func (u *UpdateStorageClassResponse) logFailure() {
+	log.WithFields(log.Fields{
+		"handler": "UpdateStorageClass",
+	}).Error(u.Error)
+}
+
+// UpdateStorageClass replaces the configuration of a storage class with the
+// one in the request body, and matches the class against the storage pools
+// again.
+func UpdateStorageClass(w http.ResponseWriter, r *http.Request) {
+	response := &UpdateStorageClassResponse{
+		StorageClass: nil,
+		Error:        "",
+	}
+	UpdateGeneric(w, r, "storageClass", response,
+		func(scName string, body []byte) int {
+			if orchestrator.GetStorageClass(scName) == nil {
+				response.Error = fmt.Sprintf("Storage class %v was not found!",
+					scName)
+				return http.StatusNotFound
+			}
+			scConfig := new(storageclass.Config)
+			if err := json.Unmarshal(body, scConfig); err != nil {
+				response.Error = "Invalid JSON: " + err.Error()
+				return http.StatusBadRequest
+			}
+			if scConfig.Name != scName {
+				response.Error = fmt.Sprintf("Storage class name %v doesn't match %v!",
+					scConfig.Name, scName)
+				return http.StatusBadRequest
+			}
+			sc, err := orchestrator.UpdateStorageClass(scConfig)
+			if err != nil {
+				response.setError(err)
+				return http.StatusBadRequest
+			}
+			response.StorageClass = sc
+			return http.StatusOK
+		},
+	)
+}
+
 type ListStorageClassesResponse struct {
 	StorageClasses []string `json:"storageClasses"`
 	Error          string   `json:"error,omitempty"`
@@ -462,3 +1063,118 @@ func GetStorageClass(w http.ResponseWriter, r *http.Request) {
 func DeleteStorageClass(w http.ResponseWriter, r *http.Request) {
 	DeleteGeneric(w, r, orchestrator.DeleteStorageClass, "storageClass")
 }
+
+type ListOperationsResponse struct {
+	Operations []string `json:"operations"`
+	Error      string   `json:"error,omitempty"`
+}
+
+func (l *ListOperationsResponse) setList(payload []string) {
+	l.Operations = payload
+}
+
+func ListOperations(w http.ResponseWriter, r *http.Request) {
+	ListGeneric(w, r,
+		&ListOperationsResponse{},
+		func() []string {
+			ops := orchestrator.ListOperations()
+			opIDs := make([]string, 0, len(ops))
+			for _, op := range ops {
+				opIDs = append(opIDs, op.ID)
+			}
+			return opIDs
+		},
+	)
+}
+
+type GetOperationResponse struct {
+	Operation *persistentstore.Operation `json:"operation"`
+	Error     string                     `json:"error,omitempty"`
+}
+
+func GetOperation(w http.ResponseWriter, r *http.Request) {
+	response := &GetOperationResponse{}
+	GetGeneric(w, r, "operation", response,
+		func(id string) int {
+			op := orchestrator.GetOperation(id)
+			if op == nil {
+				response.Error = fmt.Sprintf("Operation %s was not found!", id)
+				return http.StatusNotFound
+			}
+			response.Operation = op
+			return http.StatusOK
+		},
+	)
+}
+
+type ReconcileResponse struct {
+	Report *storage.ReconcileReport `json:"report"`
+	Error  string                   `json:"error,omitempty"`
+}
+
+func GetReconcileReport(w http.ResponseWriter, r *http.Request) {
+	response := &ReconcileResponse{}
+	GetGenericNoArg(w, r, response,
+		func() int {
+			report := orchestrator.GetReconcileReport()
+			if report == nil {
+				response.Error = "No reconciliation has finished yet!"
+				return http.StatusNotFound
+			}
+			response.Report = report
+			return http.StatusOK
+		},
+	)
+}
+
+// Reconcile runs a reconciliation and returns its report.  Differences that
+// are safe to repair are repaired if the request includes "repair=true".
+func Reconcile(w http.ResponseWriter, r *http.Request) {
+	response := &ReconcileResponse{}
+	GetGenericNoArg(w, r, response,
+		func() int {
+			report, err := orchestrator.Reconcile(r.URL.Query().Get("repair") == "true")
+			if err != nil {
+				response.Error = err.Error()
+				return http.StatusInternalServerError
+			}
+			response.Report = report
+			return http.StatusOK
+		},
+	)
+}
+
+type OrphansResponse struct {
+	Orphans []*storage.OrphanedVolume   `json:"orphans"`
+	Audit   []storage.OrphanAuditRecord `json:"audit"`
+	Error   string                      `json:"error,omitempty"`
+}
+
+func ListOrphans(w http.ResponseWriter, r *http.Request) {
+	response := &OrphansResponse{}
+	GetGenericNoArg(w, r, response,
+		func() int {
+			response.Orphans = orchestrator.ListOrphans()
+			response.Audit = orchestrator.GetOrphanAudit()
+			return http.StatusOK
+		},
+	)
+}
+
+// CollectOrphans looks for orphaned volumes now, applying the orphan policy of
+// each backend, and returns those found along with the audit trail.
+func CollectOrphans(w http.ResponseWriter, r *http.Request) {
+	response := &OrphansResponse{}
+	GetGenericNoArg(w, r, response,
+		func() int {
+			orphans, err := orchestrator.CollectOrphans()
+			if err != nil {
+				response.Error = err.Error()
+				return http.StatusBadRequest
+			}
+			response.Orphans = orphans
+			response.Audit = orchestrator.GetOrphanAudit()
+			return http.StatusOK
+		},
+	)
+}
@@ -0,0 +1,66 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package rest
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/core"
+)
+
+// MetricsServer serves Trident's Prometheus metrics at /metrics.  It listens
+// separately from the APIServer, so that the metrics may be scraped without
+// exposing the REST API.
+type MetricsServer struct {
+	server *http.Server
+}
+
+func NewMetricsServer(p core.Orchestrator, address, port string) *MetricsServer {
+
+	prometheus.MustRegister(core.NewMetricsCollector(p))
+
+	addressPort := address + ":" + port
+	log.Infof("Starting metrics interface on %s", addressPort)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+
+	return &MetricsServer{
+		server: &http.Server{
+			Addr:         addressPort,
+			Handler:      mux,
+			ReadTimeout:  httpTimeout,
+			WriteTimeout: httpTimeout,
+		},
+	}
+}
+
+func (s *MetricsServer) Activate() error {
+	go func() {
+		err := s.server.ListenAndServe()
+		if err != nil && err != http.ErrServerClosed {
+			log.Fatal(err)
+		}
+	}()
+	return nil
+}
+
+func (s *MetricsServer) Deactivate() error {
+	ctx, cancel := context.WithTimeout(context.Background(), httpTimeout)
+	defer cancel()
+	return s.server.Shutdown(ctx)
+}
+
+func (s *MetricsServer) GetName() string {
+	return "metrics"
+}
+
+func (s *MetricsServer) Version() string {
+	return config.OrchestratorAPIVersion
+}
@@ -3,6 +3,7 @@
 package rest
 
 import (
+	"encoding/json"
 	"net/http"
 
 	"github.com/gorilla/mux"
@@ -15,6 +16,9 @@ func NewRouter() *mux.Router {
 		var handler http.Handler
 
 		handler = route.HandlerFunc
+		if route.Method != "GET" {
+			handler = LeaderOnly(handler)
+		}
 		handler = Logger(handler, route.Name)
 
 		router.
@@ -27,3 +31,23 @@ func NewRouter() *mux.Router {
 
 	return router
 }
+
+// LeaderOnly rejects requests while the orchestrator is following the
+// persistent store, as only the leader among Trident's replicas may change
+// Trident's state.
+func LeaderOnly(inner http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if orchestrator.IsFollower() {
+			w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			response := struct {
+				Error string `json:"error"`
+			}{"this Trident replica isn't the leader; only the leader may make changes"}
+			if err := json.NewEncoder(w).Encode(response); err != nil {
+				panic(err)
+			}
+			return
+		}
+		inner.ServeHTTP(w, r)
+	})
+}
@@ -49,6 +49,12 @@ var routes = Routes{
 		config.BackendURL,
 		ListBackends,
 	},
+	Route{
+		"UpdateBackend",
+		"PUT",
+		config.BackendURL + "/{backend}",
+		UpdateBackend,
+	},
 	Route{
 		"DeleteBackend",
 		"DELETE",
@@ -61,6 +67,12 @@ var routes = Routes{
 		config.VolumeURL,
 		AddVolume,
 	},
+	Route{
+		"ImportVolume",
+		"POST",
+		config.VolumeURL + "/import",
+		ImportVolume,
+	},
 	Route{
 		"GetVolume",
 		"GET",
@@ -79,6 +91,54 @@ var routes = Routes{
 		config.VolumeURL + "/{volume}",
 		DeleteVolume,
 	},
+	Route{
+		"ResizeVolume",
+		"PUT",
+		config.VolumeURL + "/{volume}",
+		ResizeVolume,
+	},
+	Route{
+		"GetVolumeReplication",
+		"GET",
+		config.VolumeURL + "/{volume}/replication",
+		GetVolumeReplication,
+	},
+	Route{
+		"FailoverVolume",
+		"POST",
+		config.VolumeURL + "/{volume}/failover",
+		FailoverVolume,
+	},
+	Route{
+		"AddSnapshot",
+		"POST",
+		config.VolumeURL + "/{volume}/snapshot",
+		AddSnapshot,
+	},
+	Route{
+		"GetSnapshot",
+		"GET",
+		config.VolumeURL + "/{volume}/snapshot/{snapshot}",
+		GetSnapshot,
+	},
+	Route{
+		"ListSnapshots",
+		"GET",
+		config.VolumeURL + "/{volume}/snapshot",
+		ListSnapshots,
+	},
+	Route{
+		"DeleteSnapshot",
+		"DELETE",
+		config.VolumeURL + "/{volume}/snapshot/{snapshot}",
+		DeleteSnapshot,
+	},
+	Route{
+		"RestoreSnapshot",
+		"POST",
+		config.VolumeURL + "/{volume}/snapshot/{snapshot}/restore",
+		RestoreSnapshot,
+	},
 	Route{
 		"AddStorageClass",
 		"POST",
@@ -97,10 +157,52 @@ var routes = Routes{
 		config.StorageClassURL,
 		ListStorageClasses,
 	},
+	Route{
+		"UpdateStorageClass",
+		"PUT",
+		config.StorageClassURL + "/{storageClass}",
+		UpdateStorageClass,
+	},
 	Route{
 		"DeleteStorageClass",
 		"DELETE",
 		config.StorageClassURL + "/{storageClass}",
 		DeleteStorageClass,
 	},
+	Route{
+		"GetOperation",
+		"GET",
+		config.OperationURL + "/{operation}",
+		GetOperation,
+	},
+	Route{
+		"ListOperations",
+		"GET",
+		config.OperationURL,
+		ListOperations,
+	},
+	Route{
+		"GetReconcileReport",
+		"GET",
+		config.ReconcileURL,
+		GetReconcileReport,
+	},
+	Route{
+		"Reconcile",
+		"POST",
+		config.ReconcileURL,
+		Reconcile,
+	},
+	Route{
+		"ListOrphans",
+		"GET",
+		config.OrphanURL,
+		ListOrphans,
+	},
+	Route{
+		"CollectOrphans",
+		"POST",
+		config.OrphanURL,
+		CollectOrphans,
+	},
 }
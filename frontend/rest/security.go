@@ -0,0 +1,178 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package rest
+
+import (
+	"bufio"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ServerSecurityConfig describes how the REST API is secured.  CertFile and
+// KeyFile hold the server's certificate and private key.  Clients may then
+// authenticate with a certificate signed by a CA in ClientCAFile, with a
+// bearer token listed in TokenFile (one per line), or with either if both
+// are specified.
+type ServerSecurityConfig struct {
+	CertFile     string
+	KeyFile      string
+	ClientCAFile string
+	TokenFile    string
+}
+
+// ClientSecurityConfig describes how a client connects to a secured REST API.
+// CACertFile holds the CAs used to verify the server, which default to the
+// system's.  CertFile and KeyFile hold the client's certificate and private
+// key, and Token is a bearer token; either may be used to authenticate.
+type ClientSecurityConfig struct {
+	CACertFile         string
+	CertFile           string
+	KeyFile            string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+// NewServerTLSConfig returns the TLS configuration for a REST API server.
+func NewServerTLSConfig(security *ServerSecurityConfig) (*tls.Config, error) {
+
+	if security.CertFile == "" || security.KeyFile == "" {
+		return nil, errors.New("a certificate and key are required to serve HTTPS")
+	}
+	if security.ClientCAFile == "" && security.TokenFile == "" {
+		return nil, errors.New("a client CA or a token file is required to authenticate clients")
+	}
+
+	cert, err := tls.LoadX509KeyPair(security.CertFile, security.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("loading TLS certificate failed: %v", err)
+	}
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if security.ClientCAFile != "" {
+		tlsConfig.ClientCAs, err = loadCertPool(security.ClientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		if security.TokenFile == "" {
+			tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		} else {
+			// Clients without a certificate may present a token instead.
+			tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+		}
+	}
+
+	return tlsConfig, nil
+}
+
+// NewClientTLSConfig returns the TLS configuration for a REST API client.
+func NewClientTLSConfig(security *ClientSecurityConfig) (*tls.Config, error) {
+
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: security.InsecureSkipVerify,
+		MinVersion:         tls.VersionTLS12,
+	}
+
+	if security.CACertFile != "" {
+		caCertPool, err := loadCertPool(security.CACertFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = caCertPool
+	}
+
+	if security.CertFile != "" || security.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(security.CertFile, security.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading client certificate failed: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+func loadCertPool(caCertFile string) (*x509.CertPool, error) {
+	caCertBytes, err := ioutil.ReadFile(caCertFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading CA certificate failed: %v", err)
+	}
+	caCertPool := x509.NewCertPool()
+	if !caCertPool.AppendCertsFromPEM(caCertBytes) {
+		return nil, fmt.Errorf("parsing CA certificate %s failed", caCertFile)
+	}
+	return caCertPool, nil
+}
+
+// loadTokens reads the bearer tokens in a token file, ignoring blank lines
+// and lines starting with '#'.
+func loadTokens(tokenFile string) ([]string, error) {
+	file, err := os.Open(tokenFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading token file failed: %v", err)
+	}
+	defer file.Close()
+
+	tokens := make([]string, 0)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		token := strings.TrimSpace(scanner.Text())
+		if token != "" && !strings.HasPrefix(token, "#") {
+			tokens = append(tokens, token)
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading token file failed: %v", err)
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("token file %s contains no tokens", tokenFile)
+	}
+	return tokens, nil
+}
+
+// Authenticator rejects requests that present neither a verified client
+// certificate nor one of the bearer tokens.  A nil list of tokens means only
+// client certificates are accepted.
+func Authenticator(inner http.Handler, tokens []string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+			inner.ServeHTTP(w, r)
+			return
+		}
+		if token := getBearerToken(r); token != "" {
+			for _, valid := range tokens {
+				if subtle.ConstantTimeCompare([]byte(token), []byte(valid)) == 1 {
+					inner.ServeHTTP(w, r)
+					return
+				}
+			}
+		}
+		log.WithFields(log.Fields{
+			"method": r.Method,
+			"uri":    r.RequestURI,
+			"remote": r.RemoteAddr,
+		}).Warning("Rejected unauthenticated API server REST call.")
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+	})
+}
+
+func getBearerToken(r *http.Request) string {
+	const prefix = "Bearer "
+	authorization := r.Header.Get("Authorization")
+	if len(authorization) <= len(prefix) || !strings.EqualFold(authorization[:len(prefix)], prefix) {
+		return ""
+	}
+	return strings.TrimSpace(authorization[len(prefix):])
+}
@@ -0,0 +1,56 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package rest
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAuthenticator(t *testing.T) {
+	handler := Authenticator(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}), []string{"token1", "token2"})
+
+	for _, test := range []struct {
+		name          string
+		authorization string
+		verifiedCert  bool
+		expected      int
+	}{
+		{"no credentials", "", false, http.StatusUnauthorized},
+		{"valid token", "Bearer token2", false, http.StatusOK},
+		{"lowercase scheme", "bearer token1", false, http.StatusOK},
+		{"invalid token", "Bearer token3", false, http.StatusUnauthorized},
+		{"token prefix", "Bearer token", false, http.StatusUnauthorized},
+		{"basic auth", "Basic dG9rZW4xOg==", false, http.StatusUnauthorized},
+		{"verified certificate", "", true, http.StatusOK},
+	} {
+		request := httptest.NewRequest(http.MethodGet, "/trident/v1/backend", nil)
+		if test.authorization != "" {
+			request.Header.Set("Authorization", test.authorization)
+		}
+		if test.verifiedCert {
+			request.TLS = &tls.ConnectionState{
+				VerifiedChains: [][]*x509.Certificate{{&x509.Certificate{}}},
+			}
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		if recorder.Code != test.expected {
+			t.Errorf("%s: expected status %d, got %d", test.name, test.expected, recorder.Code)
+		}
+	}
+}
+
+func TestNewServerTLSConfigRequiresAuthentication(t *testing.T) {
+	if _, err := NewServerTLSConfig(&ServerSecurityConfig{CertFile: "cert", KeyFile: "key"}); err == nil {
+		t.Error("Expected an error when neither a client CA nor a token file is given.")
+	}
+	if _, err := NewServerTLSConfig(&ServerSecurityConfig{TokenFile: "tokens"}); err == nil {
+		t.Error("Expected an error when no certificate is given.")
+	}
+}
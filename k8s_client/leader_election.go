@@ -0,0 +1,101 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package k8sclient
+
+import (
+	"context"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/netapp/trident/config"
+)
+
+// LeaderLockName is the name of the ConfigMap whose annotation records which
+// Trident replica is the leader.
+const LeaderLockName = "trident-leader"
+
+// LeaderElector elects a leader among the Trident replicas in a namespace,
+// using a lock held on a ConfigMap.  The leader must renew the lock before it
+// expires, or another replica may take it.
+type LeaderElector struct {
+	config  leaderelection.LeaderElectionConfig
+	elected chan struct{}
+	lost    chan struct{}
+}
+
+// NewLeaderElector returns an elector that campaigns under the given
+// identity, which should be unique among the replicas.
+func NewLeaderElector(kubeConfig *rest.Config, namespace, identity string) (*LeaderElector, error) {
+	clientset, err := kubernetes.NewForConfig(kubeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&corev1.EventSinkImpl{
+		Interface: clientset.CoreV1().Events(namespace),
+	})
+	recorder := broadcaster.NewRecorder(scheme.Scheme,
+		v1.EventSource{Component: config.OrchestratorName})
+
+	lock, err := resourcelock.New(resourcelock.ConfigMapsResourceLock, namespace, LeaderLockName,
+		clientset.CoreV1(), resourcelock.ResourceLockConfig{
+			Identity:      identity,
+			EventRecorder: recorder,
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	e := &LeaderElector{
+		elected: make(chan struct{}),
+		lost:    make(chan struct{}),
+	}
+	e.config = leaderelection.LeaderElectionConfig{
+		Lock:          lock,
+		LeaseDuration: config.LeaderLeaseDuration,
+		RenewDeadline: config.LeaderRenewDeadline,
+		RetryPeriod:   config.LeaderRetryPeriod,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(stop <-chan struct{}) { close(e.elected) },
+			OnStoppedLeading: func() { close(e.lost) },
+		},
+	}
+	return e, nil
+}
+
+// Campaign blocks until this replica is elected or the context is done.  A
+// replica should campaign only once.
+func (e *LeaderElector) Campaign(ctx context.Context) error {
+	elector, err := leaderelection.NewLeaderElector(e.config)
+	if err != nil {
+		return err
+	}
+	go elector.Run()
+
+	select {
+	case <-e.elected:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Lost returns a channel that is closed if this replica fails to renew its
+// lock, after which it is no longer the leader.
+func (e *LeaderElector) Lost() <-chan struct{} {
+	return e.lost
+}
+
+// Resign does nothing, as the lock can't be released early; another replica
+// is elected once the lock expires.
+func (e *LeaderElector) Resign() error {
+	return nil
+}
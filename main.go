@@ -3,8 +3,10 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"os/signal"
 	"runtime"
@@ -12,17 +14,26 @@ import (
 	"syscall"
 
 	log "github.com/sirupsen/logrus"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
 
+	"github.com/netapp/trident/cli/cmd"
 	"github.com/netapp/trident/config"
 	"github.com/netapp/trident/core"
 	"github.com/netapp/trident/frontend"
+	"github.com/netapp/trident/frontend/csi"
 	"github.com/netapp/trident/frontend/docker"
 	"github.com/netapp/trident/frontend/kubernetes"
 	"github.com/netapp/trident/frontend/rest"
+	"github.com/netapp/trident/k8s_client"
 	"github.com/netapp/trident/logging"
 	"github.com/netapp/trident/persistent_store"
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/storage/factory"
 )
 
+const tridentNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
 var (
 	// Logging
 	debug    = flag.Bool("debug", false, "Enable debugging output")
@@ -42,6 +53,12 @@ var (
 		"Unix domain socket")
 	configPath = flag.String("config", "", "Path to configuration file(s)")
 
+	// CSI
+	csiEndpoint = flag.String("csi_endpoint", "", "Serve CSI at this endpoint (e.g., "+
+		"-csi_endpoint=unix:///var/lib/trident/csi.sock)")
+	csiNodeName = flag.String("csi_node_name", "", "Name of this node reported to the CSI "+
+		"container orchestrator (the host name if unspecified)")
+
 	// Persistence
 	etcdV2 = flag.String("etcd_v2", "", "etcd server (v2 API) for "+
 		"persisting orchestrator state (e.g., -etcd_v2=http://127.0.0.1:8001)")
@@ -57,15 +74,55 @@ var (
 		"any metadata.  WILL LOSE TRACK OF VOLUMES ON REBOOT/CRASH.")
 	usePassthrough = flag.Bool("passthrough", false, "Uses the storage backends "+
 		"as the source of truth.  No data is stored anywhere else.")
+	useCRDs = flag.Bool("crd_persistence", false, "Persists orchestrator state as "+
+		"Kubernetes custom resources.  If an etcd server is also specified, its state "+
+		"is migrated to custom resources the first time Trident starts.")
+	storeFile = flag.String("store_file", "", "Persists orchestrator state in this local "+
+		"file (e.g., -store_file=/var/lib/trident/trident.db).  If a backend configuration is "+
+		"also specified, its backends and volumes are imported when the file is created.")
+
+	// High availability
+	enableHA = flag.Bool("ha", false, "Run as one of several replicas sharing the "+
+		"persistent store, of which an elected leader manages Trident's state.  The "+
+		"others serve read-only REST requests until one of them is elected.")
+	haIdentity = flag.String("ha_identity", "", "Name of this replica in the leader "+
+		"election (the host name if unspecified)")
+
+	// Reconciliation
+	reconcileInterval = flag.Duration("reconcile_interval", config.ReconcileInterval, "How often to "+
+		"compare the volumes on the storage backends with Trident's records (0 to disable)")
+	reconcileRepair = flag.Bool("reconcile_repair", false, "Repair the differences found "+
+		"between the storage backends and Trident's records that are safe to repair")
+	orphanInterval = flag.Duration("orphan_interval", config.OrphanCollectionInterval, "How often to "+
+		"look for orphaned volumes on the storage backends, which are handled according to "+
+		"each backend's orphan policy (0 to disable)")
 
 	// REST interface
 	address    = flag.String("address", "localhost", "Storage orchestrator API address")
 	port       = flag.String("port", "8000", "Storage orchestrator API port")
 	enableREST = flag.Bool("rest", true, "Enable REST interface")
 
+	// HTTPS REST interface
+	enableHTTPS   = flag.Bool("https_rest", false, "Enable authenticated HTTPS REST interface")
+	httpsAddress  = flag.String("https_address", "", "HTTPS REST interface address (all interfaces if unspecified)")
+	httpsPort     = flag.String("https_port", "8443", "HTTPS REST interface port")
+	httpsCert     = flag.String("https_cert", "", "HTTPS REST interface server certificate")
+	httpsKey      = flag.String("https_key", "", "HTTPS REST interface server private key")
+	httpsClientCA = flag.String("https_client_ca", "", "CA certificate for authenticating HTTPS REST "+
+		"clients by their certificates")
+	httpsTokenFile = flag.String("https_token_file", "", "File of bearer tokens (one per line) for "+
+		"authenticating HTTPS REST clients")
+
+	// Metrics interface
+	metricsAddress = flag.String("metrics_address", "", "Prometheus metrics address "+
+		"(all interfaces if unspecified)")
+	metricsPort   = flag.String("metrics_port", "8001", "Prometheus metrics port")
+	enableMetrics = flag.Bool("metrics", false, "Enable Prometheus metrics at /metrics")
+
 	storeClient      persistentstore.Client
 	enableKubernetes bool
 	enableDocker     bool
+	enableCSI        bool
 )
 
 func shouldEnableTLS() bool {
@@ -96,21 +153,41 @@ func processCmdLineArgs() {
 
 	// Infer frontend from arguments
 	enableKubernetes = *k8sPod || *k8sAPIServer != ""
-	enableDocker = *configPath != ""
+	enableCSI = *csiEndpoint != ""
+	enableDocker = *configPath != "" && !enableCSI
 
 	if enableKubernetes && enableDocker {
 		log.Fatal("Trident cannot serve both Docker and Kubernetes at the same time.")
-	} else if !enableKubernetes && !enableDocker && !*useInMemory {
+	} else if enableKubernetes && enableCSI {
+		log.Fatal("Trident cannot serve both CSI and Kubernetes at the same time.")
+	} else if !enableKubernetes && !enableDocker && !enableCSI && !*useInMemory {
 		log.Fatal("Insufficient arguments provided for Trident to start.  Specify either " +
-			"k8sAPIServer (for Kubernetes) or configPath (for Docker).")
+			"k8sAPIServer (for Kubernetes), configPath (for Docker), or csiEndpoint (for CSI).")
+	}
+
+	if *useCRDs && !enableKubernetes {
+		log.Fatal("CRD persistence requires Trident to serve Kubernetes.")
+	}
+	if *useCRDs && *etcdV2 != "" && *etcdV3 != "" {
+		log.Fatal("Only one etcd server may be specified to migrate from.")
+	}
+	if *enableHA && !*useCRDs && *etcdV3 == "" {
+		log.Fatal("High availability requires CRD persistence or an etcd v3 server.")
 	}
 
-	// Determine persistent store type from arguments
+	// Determine persistent store type from arguments.  An etcd server
+	// specified with CRD persistence is only the source for migration.
 	storeCount := 0
-	if *etcdV2 != "" {
+	if *etcdV2 != "" && !*useCRDs {
 		storeCount++
 	}
-	if *etcdV3 != "" {
+	if *etcdV3 != "" && !*useCRDs {
+		storeCount++
+	}
+	if *useCRDs {
+		storeCount++
+	}
+	if *storeFile != "" {
 		storeCount++
 	}
 	if *useInMemory {
@@ -120,7 +197,7 @@ func processCmdLineArgs() {
 		storeCount++
 	}
 	// Infer persistent store type if not explicitly specified
-	if storeCount == 0 && enableDocker {
+	if storeCount == 0 && *configPath != "" {
 		log.Debug("Inferred passthrough persistent store.")
 		*usePassthrough = true
 		storeCount++
@@ -132,39 +209,219 @@ func processCmdLineArgs() {
 	// Don't bother validating the Kubernetes API server address; we'll know if
 	// it's invalid during start-up.  Given that users can specify DNS names,
 	// validation would be more trouble than it's worth.
+	if *useCRDs {
+		log.Debug("Trident is configured with a CRD store client.")
+		storeClient, err = newCRDClient()
+		if err != nil {
+			log.Fatalf("Unable to create the CRD client. %v", err)
+		}
+	} else if *storeFile != "" {
+		log.Debug("Trident is configured with a local store file.")
+		storeClient, err = persistentstore.NewBoltClient(*storeFile)
+		if err != nil {
+			log.Fatalf("Unable to create the local store client. %v", err)
+		}
+	} else if *etcdV3 != "" || *etcdV2 != "" {
+		storeClient = newEtcdClient()
+	} else if *useInMemory {
+		log.Debug("Trident is configured with an in-memory store client.")
+		storeClient = persistentstore.NewInMemoryClient()
+	} else if *usePassthrough {
+		log.Debug("Trident is configured with passthrough store client.")
+		storeClient, err = persistentstore.NewPassthroughClient(*configPath)
+		if err != nil {
+			log.Fatalf("Unable to create the passthrough store client. %v", err)
+		}
+	}
+
+	config.UsingPassthroughStore = storeClient.GetType() == persistentstore.PassthroughStore
+}
+
+// newEtcdClient returns a client for the etcd server specified on the command line.
+func newEtcdClient() persistentstore.Client {
+	var (
+		etcdClient persistentstore.Client
+		err        error
+	)
 	if *etcdV3 != "" {
 		if shouldEnableTLS() {
 			log.Debug("Trident is configured with an etcdv3 client with TLS.")
-			storeClient, err = persistentstore.NewEtcdClientV3WithTLS(*etcdV3,
+			etcdClient, err = persistentstore.NewEtcdClientV3WithTLS(*etcdV3,
 				*etcdV3Cert, *etcdV3CACert, *etcdV3Key)
 		} else {
 			log.Debug("Trident is configured with an etcdv3 client without TLS.")
 			if !strings.Contains(*etcdV3, "127.0.0.1") {
 				log.Warn("Trident's etcdv3 client should be configured with TLS!")
 			}
-			storeClient, err = persistentstore.NewEtcdClientV3(*etcdV3)
+			etcdClient, err = persistentstore.NewEtcdClientV3(*etcdV3)
 		}
 		if err != nil {
 			log.Fatalf("Unable to create the etcd V3 client. %v", err)
 		}
-	} else if *etcdV2 != "" {
+	} else {
 		log.Debug("Trident is configured with an etcdv2 client.")
-		storeClient, err = persistentstore.NewEtcdClientV2(*etcdV2)
+		etcdClient, err = persistentstore.NewEtcdClientV2(*etcdV2)
 		if err != nil {
 			log.Fatalf("Unable to create the etcd V2 client. %v", err)
 		}
-	} else if *useInMemory {
-		log.Debug("Trident is configured with an in-memory store client.")
-		storeClient = persistentstore.NewInMemoryClient()
-	} else if *usePassthrough {
-		log.Debug("Trident is configured with passthrough store client.")
-		storeClient, err = persistentstore.NewPassthroughClient(*configPath)
+	}
+	return etcdClient
+}
+
+// getKubeConfig returns the configuration for the same API server as the
+// Kubernetes frontend, along with Trident's namespace.
+func getKubeConfig() (*rest.Config, string, error) {
+	var (
+		kubeConfig     *rest.Config
+		namespace      string
+		namespaceBytes []byte
+		err            error
+	)
+	if *k8sAPIServer != "" {
+		if kubeConfig, err = clientcmd.BuildConfigFromFlags(*k8sAPIServer, *k8sConfigPath); err != nil {
+			return nil, "", err
+		}
+		// When running in binary mode, we use the current namespace
+		if namespace, err = cmd.GetCurrentNamespace(); err != nil {
+			return nil, "", err
+		}
+	} else {
+		if kubeConfig, err = rest.InClusterConfig(); err != nil {
+			return nil, "", err
+		}
+		// When running in a pod, we use the Trident pod's namespace
+		if namespaceBytes, err = ioutil.ReadFile(tridentNamespaceFile); err != nil {
+			return nil, "", fmt.Errorf("unable to read Trident's namespace from %s: %v",
+				tridentNamespaceFile, err)
+		}
+		namespace = strings.TrimSpace(string(namespaceBytes))
+	}
+	return kubeConfig, namespace, nil
+}
+
+// newCRDClient returns a client for Trident's custom resources in Trident's
+// namespace.
+func newCRDClient() (persistentstore.Client, error) {
+	kubeConfig, namespace, err := getKubeConfig()
+	if err != nil {
+		return nil, err
+	}
+	return persistentstore.NewCRDClientV1(kubeConfig, namespace)
+}
+
+// newLeaderElector returns an elector for the replicas sharing the persistent
+// store, which holds a ConfigMap lock alongside a CRD store, or campaigns in
+// an etcd election.
+func newLeaderElector() (core.LeaderElector, error) {
+	identity := *haIdentity
+	if identity == "" {
+		var err error
+		if identity, err = os.Hostname(); err != nil {
+			return nil, fmt.Errorf("unable to determine this replica's identity: %v", err)
+		}
+	}
+	log.WithField("identity", identity).Debug("Creating leader elector.")
+
+	switch client := storeClient.(type) {
+	case *persistentstore.CRDClientV1:
+		kubeConfig, namespace, err := getKubeConfig()
 		if err != nil {
-			log.Fatalf("Unable to create the passthrough store client. %v", err)
+			return nil, err
 		}
+		return k8sclient.NewLeaderElector(kubeConfig, namespace, identity)
+	case *persistentstore.EtcdClientV3:
+		return client.NewLeaderElector(identity)
+	default:
+		return nil, fmt.Errorf("leader election isn't supported with the %s store",
+			storeClient.GetType())
 	}
+}
 
-	config.UsingPassthroughStore = storeClient.GetType() == persistentstore.PassthroughStore
+// migratePersistentState copies Trident's state into a new CRD store from
+// etcd, or into a new local store file from the backend configuration.  It
+// must run after the driver context is set, as that determines which volumes
+// the backends report.
+func migratePersistentState() {
+	switch storeClient.GetType() {
+	case persistentstore.CRDV1Store:
+		if (*etcdV3 != "" || *etcdV2 != "") && storeNeedsMigration() {
+			migrateStore(newEtcdClient())
+		}
+	case persistentstore.BoltStore:
+		if *configPath != "" && storeNeedsMigration() {
+			passthroughClient, backends := newPassthroughSourceClient()
+			migrateStore(passthroughClient)
+			for _, backend := range backends {
+				backend.Terminate()
+			}
+		}
+	}
+}
+
+// newPassthroughSourceClient returns a passthrough store client for the
+// backend configuration specified on the command line, with each backend
+// initialized so that the client can read its volumes.  The caller should
+// terminate the returned backends once done with the client.
+func newPassthroughSourceClient() (persistentstore.Client, []*storage.Backend) {
+	passthroughClient, err := persistentstore.NewPassthroughClient(*configPath)
+	if err != nil {
+		log.Fatalf("Unable to create the passthrough store client. %v", err)
+	}
+	persistentBackends, err := passthroughClient.GetBackends()
+	if err != nil {
+		log.Fatalf("Unable to read the backend configuration. %v", err)
+	}
+	backends := make([]*storage.Backend, 0, len(persistentBackends))
+	for _, b := range persistentBackends {
+		configJSON, err := b.MarshalConfig()
+		if err != nil {
+			log.Fatalf("Unable to read the configuration of backend %s. %v", b.Name, err)
+		}
+		backend, err := factory.NewStorageBackendForConfig(configJSON)
+		if err != nil {
+			log.Fatalf("Unable to initialize backend %s. %v", b.Name, err)
+		}
+		if err = passthroughClient.AddBackend(backend); err != nil {
+			log.Fatalf("Unable to add backend %s to the passthrough store. %v", b.Name, err)
+		}
+		backends = append(backends, backend)
+	}
+	return passthroughClient, backends
+}
+
+// storeNeedsMigration returns whether the persistent store is new, so that
+// any state in the store specified as the source should be copied to it.
+func storeNeedsMigration() bool {
+	if _, err := storeClient.GetVersion(); err == nil {
+		log.WithField("store", storeClient.GetType()).Debug(
+			"Persistent state was already migrated.")
+		return false
+	} else if !persistentstore.MatchKeyNotFoundErr(err) {
+		log.Fatalf("Unable to read the persistent state version. %v", err)
+	}
+	return true
+}
+
+// migrateStore copies Trident's state from another store.  The orchestrator
+// sees the new store's version afterward, so it doesn't attempt any further
+// migration.
+func migrateStore(sourceClient persistentstore.Client) {
+	defer sourceClient.Stop()
+
+	dataMigrator := persistentstore.NewDataMigrator(storeClient, sourceClient.GetType())
+	dataMigrator.SourceClient = sourceClient
+	if err := dataMigrator.Run("/"+config.OrchestratorName, false); err != nil {
+		log.Fatalf("Unable to migrate persistent state to the %s store. %v",
+			storeClient.GetType(), err)
+	}
+
+	version := &persistentstore.PersistentStateVersion{
+		PersistentStoreVersion: string(storeClient.GetType()),
+		OrchestratorAPIVersion: config.OrchestratorAPIVersion,
+	}
+	if err := storeClient.SetVersion(version); err != nil {
+		log.Fatalf("Unable to set the persistent state version after migration. %v", err)
+	}
 }
 
 func main() {
@@ -174,6 +431,7 @@ func main() {
 	runtime.GOMAXPROCS(runtime.NumCPU())
 	flag.Parse()
 	frontends := make([]frontend.Plugin, 0)
+	apiFrontends := make([]frontend.Plugin, 0)
 
 	// Set log level
 	err = logging.InitLogLevel(*debug, *logLevel)
@@ -190,8 +448,10 @@ func main() {
 	processCmdLineArgs()
 
 	orchestrator := core.NewTridentOrchestrator(storeClient)
+	orchestrator.SetReconcileOptions(*reconcileInterval, *reconcileRepair)
+	orchestrator.SetOrphanCollectionInterval(*orphanInterval)
 
-	// Create Kubernetes *or* Docker frontend
+	// Create Kubernetes, Docker *or* CSI frontend
 	if enableKubernetes {
 
 		var kubernetesFrontend frontend.Plugin
@@ -225,6 +485,24 @@ func main() {
 		}
 		orchestrator.AddFrontend(dockerFrontend)
 		frontends = append(frontends, dockerFrontend)
+
+	} else if enableCSI {
+
+		config.CurrentDriverContext = config.ContextCSI
+
+		nodeName := *csiNodeName
+		if nodeName == "" {
+			if nodeName, err = os.Hostname(); err != nil {
+				log.Fatalf("Unable to determine the CSI node name. %v", err)
+			}
+		}
+
+		csiFrontend, err := csi.NewPlugin(nodeName, *csiEndpoint, orchestrator)
+		if err != nil {
+			log.Fatalf("Unable to start the CSI frontend. %v", err)
+		}
+		orchestrator.AddFrontend(csiFrontend)
+		frontends = append(frontends, csiFrontend)
 	}
 
 	// Create REST frontend
@@ -233,17 +511,74 @@ func main() {
 			log.Warning("REST interface will not be available (port not specified).")
 		} else {
 			restServer := rest.NewAPIServer(orchestrator, *address, *port)
-			frontends = append(frontends, restServer)
+			apiFrontends = append(apiFrontends, restServer)
 			log.WithFields(log.Fields{"name": "REST"}).Info("Added frontend.")
 		}
 	}
 
+	// Create HTTPS REST frontend
+	if *enableHTTPS {
+		if *httpsPort == "" {
+			log.Warning("HTTPS REST interface will not be available (port not specified).")
+		} else {
+			httpsServer, err := rest.NewHTTPSAPIServer(orchestrator, *httpsAddress, *httpsPort,
+				&rest.ServerSecurityConfig{
+					CertFile:     *httpsCert,
+					KeyFile:      *httpsKey,
+					ClientCAFile: *httpsClientCA,
+					TokenFile:    *httpsTokenFile,
+				})
+			if err != nil {
+				log.Fatalf("Unable to start the HTTPS REST frontend. %v", err)
+			}
+			apiFrontends = append(apiFrontends, httpsServer)
+			log.WithFields(log.Fields{"name": "HTTPS REST"}).Info("Added frontend.")
+		}
+	}
+
+	// Create metrics frontend
+	if *enableMetrics {
+		if *metricsPort == "" {
+			log.Warning("Metrics interface will not be available (port not specified).")
+		} else {
+			metricsServer := rest.NewMetricsServer(orchestrator, *metricsAddress, *metricsPort)
+			apiFrontends = append(apiFrontends, metricsServer)
+			log.WithFields(log.Fields{"name": "metrics"}).Info("Added frontend.")
+		}
+	}
+
+	// With high availability, a replica serves the REST and metrics
+	// interfaces from its copy of the persistent store until it is elected,
+	// and only then bootstraps the orchestrator.  The new leader rolls back
+	// any volume transactions the previous leader left unfinished.
+	var elector core.LeaderElector
+	if *enableHA {
+		if elector, err = newLeaderElector(); err != nil {
+			log.Fatalf("Unable to create the leader elector. %v", err)
+		}
+		if err = orchestrator.Follow(); err != nil {
+			log.Fatal(err.Error())
+		}
+		activateFrontends(apiFrontends)
+		log.Info("Campaigning to be the leader.")
+		if err = elector.Campaign(context.Background()); err != nil {
+			log.Fatalf("Leader election failed. %v", err)
+		}
+		log.Info("Elected leader.")
+		go func() {
+			<-elector.Lost()
+			log.Fatal("No longer the leader; exiting so that another replica may take over.")
+		}()
+	}
+
 	// Bootstrap the orchestrator and start its frontends
+	migratePersistentState()
 	if err = orchestrator.Bootstrap(); err != nil {
 		log.Fatal(err.Error())
 	}
-	for _, f := range frontends {
-		f.Activate()
+	activateFrontends(frontends)
+	if !*enableHA {
+		activateFrontends(apiFrontends)
 	}
 
 	// Register and wait for a shutdown signal
@@ -251,8 +586,20 @@ func main() {
 	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
 	<-c
 	log.Info("Shutting down.")
-	for _, f := range frontends {
+	for _, f := range append(frontends, apiFrontends...) {
 		f.Deactivate()
 	}
+	orchestrator.Stop()
+	if elector != nil {
+		elector.Resign()
+	}
 	storeClient.Stop()
 }
+
+func activateFrontends(frontends []frontend.Plugin) {
+	for _, f := range frontends {
+		if err := f.Activate(); err != nil {
+			log.Fatalf("Unable to activate the %s frontend. %v", f.GetName(), err)
+		}
+	}
+}
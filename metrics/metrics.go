@@ -0,0 +1,107 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+// Package metrics defines the Prometheus metrics that Trident exports.  The
+// metrics are registered with the default Prometheus registry, which the
+// metrics frontend serves.
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	Namespace = "trident"
+
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+
+	// CodeTransportError is the error code recorded for a storage API call
+	// that got no response at all.
+	CodeTransportError = "transport"
+)
+
+var (
+	volumeOperations = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "volume_operations_total",
+			Help:      "Number of volume operations by backend, storage class and result.",
+		},
+		[]string{"operation", "backend", "storage_class", "result"},
+	)
+	volumeOperationDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "volume_operation_duration_seconds",
+			Help:      "Time taken by volume operations.",
+			Buckets:   []float64{0.1, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300},
+		},
+		[]string{"operation", "backend", "storage_class", "result"},
+	)
+	storageAPICallDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "storage_api_call_duration_seconds",
+			Help:      "Time taken by calls to storage controller APIs.",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"api", "method"},
+	)
+	storageAPIErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "storage_api_errors_total",
+			Help:      "Number of failed calls to storage controller APIs by error code.",
+		},
+		[]string{"api", "method", "code"},
+	)
+	storeOperationDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "store_operation_duration_seconds",
+			Help:      "Time taken by persistent store operations.",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"store", "operation"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(
+		volumeOperations,
+		volumeOperationDuration,
+		storageAPICallDuration,
+		storageAPIErrors,
+		storeOperationDuration,
+	)
+}
+
+// ObserveVolumeOperation records the outcome and duration of a volume
+// operation that began at start.
+func ObserveVolumeOperation(operation, backend, storageClass string, start time.Time, err error) {
+	result := ResultSuccess
+	if err != nil {
+		result = ResultFailure
+	}
+	volumeOperations.WithLabelValues(operation, backend, storageClass, result).Inc()
+	volumeOperationDuration.WithLabelValues(operation, backend, storageClass, result).Observe(
+		time.Since(start).Seconds())
+}
+
+// ObserveStorageAPICall records the duration of a call to a storage
+// controller API that began at start.  A non-empty code is the error the
+// call failed with.
+func ObserveStorageAPICall(api, method string, start time.Time, code string) {
+	storageAPICallDuration.WithLabelValues(api, method).Observe(time.Since(start).Seconds())
+	if code != "" {
+		storageAPIErrors.WithLabelValues(api, method, code).Inc()
+	}
+}
+
+// ObserveStoreOperation records the duration of a persistent store operation
+// that began at start.
+func ObserveStoreOperation(store, operation string, start time.Time) {
+	storeOperationDuration.WithLabelValues(store, operation).Observe(time.Since(start).Seconds())
+}
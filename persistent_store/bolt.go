@@ -0,0 +1,508 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package persistentstore
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/boltdb/bolt"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/metrics"
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/storage_class"
+)
+
+var (
+	boltVersionKey = []byte("version")
+
+	boltMetadataBucket     = []byte("metadata")
+	boltBackendBucket      = []byte("backends")
+	boltVolumeBucket       = []byte("volumes")
+	boltStorageClassBucket = []byte("storageclasses")
+	boltTransactionBucket  = []byte("transactions")
+	boltSnapshotBucket     = []byte("snapshots")
+	boltOperationBucket    = []byte("operations")
+
+	boltBuckets = [][]byte{
+		boltMetadataBucket,
+		boltBackendBucket,
+		boltVolumeBucket,
+		boltStorageClassBucket,
+		boltTransactionBucket,
+		boltSnapshotBucket,
+		boltOperationBucket,
+	}
+)
+
+// BoltClient stores Trident's state in a local BoltDB file, which gives
+// single-host deployments, such as the Docker volume plugin, a transactional
+// store without running etcd.  Each object is stored as the same JSON that
+// the etcd clients store, keyed by name in a bucket per object type.
+type BoltClient struct {
+	db   *bolt.DB
+	path string
+}
+
+// NewBoltClient opens the BoltDB file at the specified path, creating it if
+// necessary.  Only one process may open the file at a time.
+func NewBoltClient(path string) (*BoltClient, error) {
+
+	if path == "" {
+		return nil, fmt.Errorf("a store file path must be specified")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("unable to create the directory for store file %s: %v", path, err)
+	}
+
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: config.PersistentStoreTimeout})
+	if err != nil {
+		return nil, fmt.Errorf("unable to open store file %s: %v", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range boltBuckets {
+			if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("unable to initialize store file %s: %v", path, err)
+	}
+
+	log.WithField("path", path).Debug("Opened local store file.")
+
+	return &BoltClient{
+		db:   db,
+		path: path,
+	}, nil
+}
+
+func (p *BoltClient) create(bucket []byte, key string, value interface{}) error {
+	defer metrics.ObserveStoreOperation(string(BoltStore), "create", time.Now())
+
+	valueJSON, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return p.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucket)
+		if b.Get([]byte(key)) != nil {
+			return NewPersistentStoreError(KeyExistsErr, key)
+		}
+		return b.Put([]byte(key), valueJSON)
+	})
+}
+
+func (p *BoltClient) read(bucket []byte, key string, value interface{}) error {
+	defer metrics.ObserveStoreOperation(string(BoltStore), "read", time.Now())
+
+	return p.db.View(func(tx *bolt.Tx) error {
+		valueJSON := tx.Bucket(bucket).Get([]byte(key))
+		if valueJSON == nil {
+			return NewPersistentStoreError(KeyNotFoundErr, key)
+		}
+		return json.Unmarshal(valueJSON, value)
+	})
+}
+
+func (p *BoltClient) update(bucket []byte, key string, value interface{}) error {
+	defer metrics.ObserveStoreOperation(string(BoltStore), "update", time.Now())
+
+	valueJSON, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return p.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucket)
+		if b.Get([]byte(key)) == nil {
+			return NewPersistentStoreError(KeyNotFoundErr, key)
+		}
+		return b.Put([]byte(key), valueJSON)
+	})
+}
+
+// set creates an object or, if it already exists, replaces it.
+func (p *BoltClient) set(bucket []byte, key string, value interface{}) error {
+	defer metrics.ObserveStoreOperation(string(BoltStore), "set", time.Now())
+
+	valueJSON, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return p.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucket).Put([]byte(key), valueJSON)
+	})
+}
+
+func (p *BoltClient) delete(bucket []byte, key string) error {
+	defer metrics.ObserveStoreOperation(string(BoltStore), "delete", time.Now())
+
+	return p.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucket)
+		if b.Get([]byte(key)) == nil {
+			return NewPersistentStoreError(KeyNotFoundErr, key)
+		}
+		return b.Delete([]byte(key))
+	})
+}
+
+// forEach calls fn with the JSON of each object in a bucket, in key order.
+// The JSON is only valid for the duration of the call.
+func (p *BoltClient) forEach(bucket []byte, fn func(valueJSON []byte) error) error {
+	defer metrics.ObserveStoreOperation(string(BoltStore), "list", time.Now())
+
+	return p.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucket).ForEach(func(key, valueJSON []byte) error {
+			return fn(valueJSON)
+		})
+	})
+}
+
+// deleteAll deletes all objects in a bucket in a single transaction.
+func (p *BoltClient) deleteAll(bucket []byte) error {
+	defer metrics.ObserveStoreOperation(string(BoltStore), "delete", time.Now())
+
+	return p.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.DeleteBucket(bucket); err != nil {
+			return err
+		}
+		_, err := tx.CreateBucket(bucket)
+		return err
+	})
+}
+
+// GetType returns the persistent store type
+func (p *BoltClient) GetType() StoreType {
+	return BoltStore
+}
+
+// Stop closes the store file
+func (p *BoltClient) Stop() error {
+	return p.db.Close()
+}
+
+// GetConfig returns the configuration for the local store client
+func (p *BoltClient) GetConfig() *ClientConfig {
+	return &ClientConfig{}
+}
+
+// GetVersion returns the version of the persistent data
+func (p *BoltClient) GetVersion() (*PersistentStateVersion, error) {
+	version := &PersistentStateVersion{}
+	if err := p.read(boltMetadataBucket, string(boltVersionKey), version); err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
+// SetVersion sets the version of the persistent data
+func (p *BoltClient) SetVersion(version *PersistentStateVersion) error {
+	return p.set(boltMetadataBucket, string(boltVersionKey), version)
+}
+
+// AddBackend saves the minimally required backend state to the persistent store
+func (p *BoltClient) AddBackend(b *storage.Backend) error {
+	backend := b.ConstructPersistent()
+	return p.create(boltBackendBucket, backend.Name, backend)
+}
+
+// GetBackend retrieves a backend from the persistent store
+func (p *BoltClient) GetBackend(backendName string) (*storage.BackendPersistent, error) {
+	backend := &storage.BackendPersistent{}
+	if err := p.read(boltBackendBucket, backendName, backend); err != nil {
+		return nil, err
+	}
+	return backend, nil
+}
+
+// UpdateBackend updates the backend state on the persistent store
+func (p *BoltClient) UpdateBackend(b *storage.Backend) error {
+	backend := b.ConstructPersistent()
+	return p.update(boltBackendBucket, backend.Name, backend)
+}
+
+// DeleteBackend deletes the backend state on the persistent store
+func (p *BoltClient) DeleteBackend(backend *storage.Backend) error {
+	return p.delete(boltBackendBucket, backend.Name)
+}
+
+// GetBackends retrieves all backends
+func (p *BoltClient) GetBackends() ([]*storage.BackendPersistent, error) {
+	backendList := make([]*storage.BackendPersistent, 0)
+	err := p.forEach(boltBackendBucket, func(backendJSON []byte) error {
+		backend := &storage.BackendPersistent{}
+		if err := json.Unmarshal(backendJSON, backend); err != nil {
+			return err
+		}
+		backendList = append(backendList, backend)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return backendList, nil
+}
+
+// DeleteBackends deletes all backends
+func (p *BoltClient) DeleteBackends() error {
+	return p.deleteAll(boltBackendBucket)
+}
+
+// AddVolume saves a volume's state to the persistent store
+func (p *BoltClient) AddVolume(vol *storage.Volume) error {
+	return p.create(boltVolumeBucket, vol.Config.Name, vol.ConstructExternal())
+}
+
+// GetVolume retrieves a volume's state from the persistent store
+func (p *BoltClient) GetVolume(volName string) (*storage.VolumeExternal, error) {
+	volExternal := &storage.VolumeExternal{}
+	if err := p.read(boltVolumeBucket, volName, volExternal); err != nil {
+		return nil, err
+	}
+	return volExternal, nil
+}
+
+// UpdateVolume updates a volume's state on the persistent store
+func (p *BoltClient) UpdateVolume(vol *storage.Volume) error {
+	return p.update(boltVolumeBucket, vol.Config.Name, vol.ConstructExternal())
+}
+
+// DeleteVolume deletes a volume's state from the persistent store
+func (p *BoltClient) DeleteVolume(vol *storage.Volume) error {
+	return p.delete(boltVolumeBucket, vol.Config.Name)
+}
+
+func (p *BoltClient) DeleteVolumeIgnoreNotFound(vol *storage.Volume) error {
+	err := p.DeleteVolume(vol)
+	if err != nil && MatchKeyNotFoundErr(err) {
+		return nil
+	}
+	return err
+}
+
+// GetVolumes retrieves all volumes
+func (p *BoltClient) GetVolumes() ([]*storage.VolumeExternal, error) {
+	volumeList := make([]*storage.VolumeExternal, 0)
+	err := p.forEach(boltVolumeBucket, func(volJSON []byte) error {
+		volExternal := &storage.VolumeExternal{}
+		if err := json.Unmarshal(volJSON, volExternal); err != nil {
+			return err
+		}
+		volumeList = append(volumeList, volExternal)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return volumeList, nil
+}
+
+// DeleteVolumes deletes all volumes
+func (p *BoltClient) DeleteVolumes() error {
+	return p.deleteAll(boltVolumeBucket)
+}
+
+// AddVolumeTransaction logs an AddVolume operation.  The log is on disk by the
+// time this returns, so the operation can be rolled back after a crash.
+func (p *BoltClient) AddVolumeTransaction(volTxn *VolumeTransaction) error {
+	return p.set(boltTransactionBucket, volTxn.getKey(), volTxn)
+}
+
+// GetVolumeTransactions retrieves AddVolume logs
+func (p *BoltClient) GetVolumeTransactions() ([]*VolumeTransaction, error) {
+	volTxnList := make([]*VolumeTransaction, 0)
+	err := p.forEach(boltTransactionBucket, func(volTxnJSON []byte) error {
+		volTxn := &VolumeTransaction{}
+		if err := json.Unmarshal(volTxnJSON, volTxn); err != nil {
+			return err
+		}
+		volTxnList = append(volTxnList, volTxn)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return volTxnList, nil
+}
+
+// GetExistingVolumeTransaction returns an existing version of the current
+// volume transaction, if it exists.  If no volume transaction with the same
+// key exists, it returns nil.
+func (p *BoltClient) GetExistingVolumeTransaction(
+	volTxn *VolumeTransaction,
+) (*VolumeTransaction, error) {
+
+	key := volTxn.getKey()
+	existing := &VolumeTransaction{}
+	if err := p.read(boltTransactionBucket, key, existing); err != nil {
+		if MatchKeyNotFoundErr(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("unable to read volume transaction %s: %v", key, err)
+	}
+	return existing, nil
+}
+
+// DeleteVolumeTransaction deletes an AddVolume log
+func (p *BoltClient) DeleteVolumeTransaction(volTxn *VolumeTransaction) error {
+	return p.delete(boltTransactionBucket, volTxn.getKey())
+}
+
+// AddSnapshot saves a snapshot's state to the persistent store
+func (p *BoltClient) AddSnapshot(snapshot *storage.SnapshotPersistent) error {
+	return p.create(boltSnapshotBucket, snapshot.ID(), snapshot)
+}
+
+// GetSnapshot retrieves a snapshot's state from the persistent store
+func (p *BoltClient) GetSnapshot(volumeName, snapshotName string) (*storage.SnapshotPersistent, error) {
+	snapshot := &storage.SnapshotPersistent{}
+	if err := p.read(boltSnapshotBucket, storage.MakeSnapshotID(volumeName, snapshotName), snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// GetSnapshots retrieves all snapshots
+func (p *BoltClient) GetSnapshots() ([]*storage.SnapshotPersistent, error) {
+	snapshotList := make([]*storage.SnapshotPersistent, 0)
+	err := p.forEach(boltSnapshotBucket, func(snapJSON []byte) error {
+		snapshot := &storage.SnapshotPersistent{}
+		if err := json.Unmarshal(snapJSON, snapshot); err != nil {
+			return err
+		}
+		snapshotList = append(snapshotList, snapshot)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return snapshotList, nil
+}
+
+// DeleteSnapshot deletes a snapshot's state from the persistent store
+func (p *BoltClient) DeleteSnapshot(snapshot *storage.SnapshotPersistent) error {
+	return p.delete(boltSnapshotBucket, snapshot.ID())
+}
+
+func (p *BoltClient) DeleteSnapshotIgnoreNotFound(snapshot *storage.SnapshotPersistent) error {
+	err := p.DeleteSnapshot(snapshot)
+	if err != nil && MatchKeyNotFoundErr(err) {
+		return nil
+	}
+	return err
+}
+
+// DeleteSnapshots deletes all snapshots
+func (p *BoltClient) DeleteSnapshots() error {
+	return p.deleteAll(boltSnapshotBucket)
+}
+
+func (p *BoltClient) AddStorageClass(sc *storageclass.StorageClass) error {
+	sClass := sc.ConstructPersistent()
+	return p.create(boltStorageClassBucket, sClass.GetName(), sClass)
+}
+
+func (p *BoltClient) GetStorageClass(scName string) (*storageclass.Persistent, error) {
+	persistent := &storageclass.Persistent{}
+	if err := p.read(boltStorageClassBucket, scName, persistent); err != nil {
+		return nil, err
+	}
+	return persistent, nil
+}
+
+func (p *BoltClient) GetStorageClasses() ([]*storageclass.Persistent, error) {
+	storageClassList := make([]*storageclass.Persistent, 0)
+	err := p.forEach(boltStorageClassBucket, func(scJSON []byte) error {
+		persistent := &storageclass.Persistent{}
+		if err := json.Unmarshal(scJSON, persistent); err != nil {
+			return err
+		}
+		storageClassList = append(storageClassList, persistent)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return storageClassList, nil
+}
+
+// UpdateStorageClass updates a storage class's state on the persistent store
+func (p *BoltClient) UpdateStorageClass(sc *storageclass.StorageClass) error {
+	sClass := sc.ConstructPersistent()
+	return p.update(boltStorageClassBucket, sClass.GetName(), sClass)
+}
+
+// DeleteStorageClass deletes a storage class's state from the persistent store
+func (p *BoltClient) DeleteStorageClass(sc *storageclass.StorageClass) error {
+	return p.delete(boltStorageClassBucket, sc.GetName())
+}
+
+// AddOperation saves an operation's state to the persistent store
+func (p *BoltClient) AddOperation(op *Operation) error {
+	return p.create(boltOperationBucket, op.ID, op)
+}
+
+// GetOperation retrieves an operation's state from the persistent store
+func (p *BoltClient) GetOperation(id string) (*Operation, error) {
+	op := &Operation{}
+	if err := p.read(boltOperationBucket, id, op); err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+// GetOperations retrieves all operations
+func (p *BoltClient) GetOperations() ([]*Operation, error) {
+	opList := make([]*Operation, 0)
+	err := p.forEach(boltOperationBucket, func(opJSON []byte) error {
+		op := &Operation{}
+		if err := json.Unmarshal(opJSON, op); err != nil {
+			return err
+		}
+		opList = append(opList, op)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return opList, nil
+}
+
+// UpdateOperation updates an operation's state on the persistent store
+func (p *BoltClient) UpdateOperation(op *Operation) error {
+	return p.update(boltOperationBucket, op.ID, op)
+}
+
+// DeleteOperation deletes an operation's state from the persistent store
+func (p *BoltClient) DeleteOperation(op *Operation) error {
+	return p.delete(boltOperationBucket, op.ID)
+}
+
+func (p *BoltClient) setBackend(backend *storage.BackendPersistent) error {
+	return p.set(boltBackendBucket, backend.Name, backend)
+}
+
+func (p *BoltClient) setVolume(volume *storage.VolumeExternal) error {
+	return p.set(boltVolumeBucket, volume.Config.Name, volume)
+}
+
+func (p *BoltClient) setStorageClass(sc *storageclass.Persistent) error {
+	return p.set(boltStorageClassBucket, sc.GetName(), sc)
+}
+
+func (p *BoltClient) setVolumeTransaction(volTxn *VolumeTransaction) error {
+	return p.set(boltTransactionBucket, volTxn.getKey(), volTxn)
+}
+
+func (p *BoltClient) setSnapshot(snapshot *storage.SnapshotPersistent) error {
+	return p.set(boltSnapshotBucket, snapshot.ID(), snapshot)
+}
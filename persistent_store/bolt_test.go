@@ -0,0 +1,270 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package persistentstore
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage"
+	sa "github.com/netapp/trident/storage_attribute"
+	sc "github.com/netapp/trident/storage_class"
+)
+
+func newTestBoltClient(t *testing.T) (*BoltClient, func()) {
+	dir, err := ioutil.TempDir("", "trident-bolt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := NewBoltClient(filepath.Join(dir, "state", "trident.db"))
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatalf("Unable to create bolt client: %v", err)
+	}
+	return p, func() {
+		p.Stop()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestBoltVersion(t *testing.T) {
+	p, cleanup := newTestBoltClient(t)
+	defer cleanup()
+
+	if _, err := p.GetVersion(); !MatchKeyNotFoundErr(err) {
+		t.Fatalf("Expected KeyNotFound, got %v", err)
+	}
+	version := &PersistentStateVersion{string(BoltStore), config.OrchestratorAPIVersion}
+	if err := p.SetVersion(version); err != nil {
+		t.Fatalf("Unable to set version: %v", err)
+	}
+	if recovered, err := p.GetVersion(); err != nil || *recovered != *version {
+		t.Errorf("Unexpected version %v, error %v", recovered, err)
+	}
+}
+
+func TestBoltReopen(t *testing.T) {
+	dir, err := ioutil.TempDir("", "trident-bolt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "trident.db")
+
+	p, err := NewBoltClient(path)
+	if err != nil {
+		t.Fatalf("Unable to create bolt client: %v", err)
+	}
+	vol := getFakeVolume()
+	if err = p.AddVolume(vol); err != nil {
+		t.Fatalf("Unable to add volume: %v", err)
+	}
+	if err = p.AddVolumeTransaction(getFakeVolumeTransaction()); err != nil {
+		t.Fatalf("Unable to add transaction: %v", err)
+	}
+	if err = p.Stop(); err != nil {
+		t.Fatalf("Unable to stop bolt client: %v", err)
+	}
+
+	// Everything written is still there after the file is reopened
+	if p, err = NewBoltClient(path); err != nil {
+		t.Fatalf("Unable to reopen bolt client: %v", err)
+	}
+	defer p.Stop()
+	if _, err = p.GetVolume(vol.Config.Name); err != nil {
+		t.Errorf("Unable to get volume after reopening: %v", err)
+	}
+	if volTxns, err := p.GetVolumeTransactions(); err != nil || len(volTxns) != 1 {
+		t.Errorf("Unexpected transactions %v, error %v", volTxns, err)
+	}
+}
+
+func TestBoltBackend(t *testing.T) {
+	p, cleanup := newTestBoltClient(t)
+	defer cleanup()
+	backend := getFakeBackend()
+
+	if err := p.AddBackend(backend); err != nil {
+		t.Fatalf("Unable to add backend: %v", err)
+	}
+	if err := p.AddBackend(backend); err == nil || err.Error() != KeyExistsErr {
+		t.Errorf("Expected KeyExists, got %v", err)
+	}
+
+	backend.Online = false
+	if err := p.UpdateBackend(backend); err != nil {
+		t.Fatalf("Unable to update backend: %v", err)
+	}
+	recovered, err := p.GetBackend(backend.Name)
+	if err != nil {
+		t.Fatalf("Unable to get backend: %v", err)
+	}
+	if recovered.Name != backend.Name || recovered.Online {
+		t.Errorf("Unexpected backend %v", recovered)
+	}
+
+	if err = p.DeleteBackends(); err != nil {
+		t.Fatalf("Unable to delete backends: %v", err)
+	}
+	if backends, err := p.GetBackends(); err != nil || len(backends) != 0 {
+		t.Errorf("Unexpected backends %v, error %v", backends, err)
+	}
+	if err = p.DeleteBackend(backend); !MatchKeyNotFoundErr(err) {
+		t.Errorf("Expected KeyNotFound, got %v", err)
+	}
+	if err = p.UpdateBackend(backend); !MatchKeyNotFoundErr(err) {
+		t.Errorf("Expected KeyNotFound, got %v", err)
+	}
+}
+
+func TestBoltVolumeTransactions(t *testing.T) {
+	p, cleanup := newTestBoltClient(t)
+	defer cleanup()
+	volTxn := getFakeVolumeTransaction()
+
+	if existing, err := p.GetExistingVolumeTransaction(volTxn); err != nil || existing != nil {
+		t.Errorf("Unexpected transaction %v, error %v", existing, err)
+	}
+
+	// Adding a transaction for the same volume replaces the existing one
+	if err := p.AddVolumeTransaction(volTxn); err != nil {
+		t.Fatalf("Unable to add transaction: %v", err)
+	}
+	deleteTxn := &VolumeTransaction{Config: volTxn.Config, Op: DeleteVolume}
+	if err := p.AddVolumeTransaction(deleteTxn); err != nil {
+		t.Fatalf("Unable to add transaction: %v", err)
+	}
+	existing, err := p.GetExistingVolumeTransaction(volTxn)
+	if err != nil || existing == nil || existing.Op != DeleteVolume {
+		t.Errorf("Unexpected transaction %v, error %v", existing, err)
+	}
+
+	if err = p.DeleteVolumeTransaction(volTxn); err != nil {
+		t.Fatalf("Unable to delete transaction: %v", err)
+	}
+	if volTxns, err := p.GetVolumeTransactions(); err != nil || len(volTxns) != 0 {
+		t.Errorf("Unexpected transactions %v, error %v", volTxns, err)
+	}
+}
+
+func TestBoltSnapshotsAndStorageClasses(t *testing.T) {
+	p, cleanup := newTestBoltClient(t)
+	defer cleanup()
+
+	snapshot := &storage.SnapshotPersistent{
+		Snapshot: storage.Snapshot{Name: "snap1", Created: "2018-01-01T00:00:00Z"},
+		Volume:   "vol1",
+	}
+	if err := p.AddSnapshot(snapshot); err != nil {
+		t.Fatalf("Unable to add snapshot: %v", err)
+	}
+	if recovered, err := p.GetSnapshot("vol1", "snap1"); err != nil || *recovered != *snapshot {
+		t.Errorf("Unexpected snapshot %v, error %v", recovered, err)
+	}
+	if err := p.DeleteSnapshot(snapshot); err != nil {
+		t.Errorf("Unable to delete snapshot: %v", err)
+	}
+	if err := p.DeleteSnapshotIgnoreNotFound(snapshot); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	storageClass := getFakeStorageClass()
+	if err := p.AddStorageClass(storageClass); err != nil {
+		t.Fatalf("Unable to add storage class: %v", err)
+	}
+	recovered, err := p.GetStorageClass(storageClass.GetName())
+	if err != nil {
+		t.Fatalf("Unable to get storage class: %v", err)
+	}
+	if recovered.Config.Attributes[sa.IOPS].Value() != 40 {
+		t.Errorf("Unexpected storage class attributes %v", recovered.Config.Attributes)
+	}
+	updated := sc.New(&sc.Config{
+		Version:    recovered.Config.Version,
+		Name:       recovered.Config.Name,
+		Attributes: map[string]sa.Request{sa.IOPS: sa.NewIntRequest(80)},
+	})
+	if err = p.UpdateStorageClass(updated); err != nil {
+		t.Fatalf("Unable to update storage class: %v", err)
+	}
+	if recovered, err = p.GetStorageClass(storageClass.GetName()); err != nil ||
+		recovered.Config.Attributes[sa.IOPS].Value() != 80 {
+		t.Errorf("Unexpected storage class %v, error %v", recovered, err)
+	}
+	if err = p.DeleteStorageClass(storageClass); err != nil {
+		t.Fatalf("Unable to delete storage class: %v", err)
+	}
+	if storageClasses, err := p.GetStorageClasses(); err != nil || len(storageClasses) != 0 {
+		t.Errorf("Unexpected storage classes %v, error %v", storageClasses, err)
+	}
+}
+
+func TestBoltOperations(t *testing.T) {
+	p, cleanup := newTestBoltClient(t)
+	defer cleanup()
+	op := &Operation{
+		ID:      "4c3c2a0e-3d4e-4b0c-9d8e-6a3b0a6f2f11",
+		Type:    ImportVolumeOperation,
+		Target:  "vol1",
+		State:   OperationRunning,
+		Created: "2018-01-01T00:00:00Z",
+		Updated: "2018-01-01T00:00:00Z",
+	}
+
+	if err := p.UpdateOperation(op); !MatchKeyNotFoundErr(err) {
+		t.Errorf("Expected KeyNotFound, got %v", err)
+	}
+	if err := p.AddOperation(op); err != nil {
+		t.Fatalf("Unable to add operation: %v", err)
+	}
+	op.State = OperationSucceeded
+	if err := p.UpdateOperation(op); err != nil {
+		t.Fatalf("Unable to update operation: %v", err)
+	}
+	if recovered, err := p.GetOperation(op.ID); err != nil || *recovered != *op {
+		t.Errorf("Unexpected operation %v, error %v", recovered, err)
+	}
+
+	if err := p.DeleteOperation(op); err != nil {
+		t.Fatalf("Unable to delete operation: %v", err)
+	}
+	if ops, err := p.GetOperations(); err != nil || len(ops) != 0 {
+		t.Errorf("Unexpected operations %v, error %v", ops, err)
+	}
+}
+
+func TestBoltPassthroughMigration(t *testing.T) {
+	p, cleanup := newTestBoltClient(t)
+	defer cleanup()
+
+	// The passthrough store reads volumes from its live backends
+	backend := getFakeBackend()
+	volConfig := &storage.VolumeConfig{Name: "vol1", InternalName: "vol1", Size: "1073741824"}
+	if _, err := backend.AddVolume(volConfig, backend.Storage["pool-0"], make(map[string]sa.Request)); err != nil {
+		t.Fatalf("Unable to create volume: %v", err)
+	}
+	source := newPassthroughClient()
+	source.bootBackends = append(source.bootBackends, backend.ConstructPersistent())
+	source.AddBackend(backend)
+
+	migrator := NewDataMigrator(p, source.GetType())
+	migrator.SourceClient = source
+	if err := migrator.Run("/"+config.OrchestratorName, false); err != nil {
+		t.Fatalf("Data migration failed: %v", err)
+	}
+
+	if _, err := p.GetBackend(backend.Name); err != nil {
+		t.Errorf("Unable to get migrated backend: %v", err)
+	}
+	// Passthrough volumes are named for their volumes on the backend
+	volumes, err := p.GetVolumes()
+	if err != nil {
+		t.Fatalf("Unable to get migrated volumes: %v", err)
+	}
+	if len(volumes) != 1 || volumes[0].Backend != backend.Name {
+		t.Errorf("Unexpected migrated volumes %v", volumes)
+	}
+}
@@ -0,0 +1,694 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package persistentstore
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/metrics"
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/storage_class"
+)
+
+const (
+	CRDGroup   = "trident.netapp.io"
+	CRDVersion = "v1"
+
+	// crdVersionObjectName is the name of the single TridentVersion object
+	crdVersionObjectName = "trident"
+	// crdNameHashLength is the number of hex digits of a key's hash appended
+	// to object names that had to be sanitized
+	crdNameHashLength = 8
+)
+
+// crdKind describes one of the custom resources Trident persists its state in.
+type crdKind struct {
+	Kind     string
+	Resource string
+}
+
+var (
+	crdVersionKind      = crdKind{"TridentVersion", "tridentversions"}
+	crdBackendKind      = crdKind{"TridentBackend", "tridentbackends"}
+	crdVolumeKind       = crdKind{"TridentVolume", "tridentvolumes"}
+	crdStorageClassKind = crdKind{"TridentStorageClass", "tridentstorageclasses"}
+	crdTransactionKind  = crdKind{"TridentTransaction", "tridenttransactions"}
+	crdSnapshotKind     = crdKind{"TridentSnapshot", "tridentsnapshots"}
+	crdOperationKind    = crdKind{"TridentOperation", "tridentoperations"}
+
+	crdKinds = []crdKind{
+		crdVersionKind,
+		crdBackendKind,
+		crdVolumeKind,
+		crdStorageClassKind,
+		crdTransactionKind,
+		crdSnapshotKind,
+		crdOperationKind,
+	}
+
+	invalidCRDNameChars = regexp.MustCompile("[^a-z0-9.-]+")
+)
+
+// crdResource is the subset of the dynamic client's ResourceInterface used to
+// manage the objects of a single custom resource.
+type crdResource interface {
+	List(opts metav1.ListOptions) (runtime.Object, error)
+	Get(name string, opts metav1.GetOptions) (*unstructured.Unstructured, error)
+	Delete(name string, opts *metav1.DeleteOptions) error
+	Create(obj *unstructured.Unstructured) (*unstructured.Unstructured, error)
+	Update(obj *unstructured.Unstructured) (*unstructured.Unstructured, error)
+	Watch(opts metav1.ListOptions) (watch.Interface, error)
+}
+
+// CRDClientV1 stores Trident's state as custom resources in the Trident
+// namespace, so that Trident may run in Kubernetes without etcd.  Each object
+// holds the same JSON that the etcd clients store in its spec.
+type CRDClientV1 struct {
+	namespace string
+	resources map[string]crdResource
+}
+
+// NewCRDClientV1 returns a client for Trident's custom resources in the
+// specified namespace.  The custom resource definitions must already exist.
+func NewCRDClientV1(config *rest.Config, namespace string) (*CRDClientV1, error) {
+
+	// Avoid changing the original config
+	crdConfig := *config
+	crdConfig.APIPath = "/apis"
+	crdConfig.GroupVersion = &schema.GroupVersion{Group: CRDGroup, Version: CRDVersion}
+
+	client, err := dynamic.NewClient(&crdConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make(map[string]crdResource, len(crdKinds))
+	for _, kind := range crdKinds {
+		resources[kind.Kind] = client.Resource(&metav1.APIResource{
+			Name:       kind.Resource,
+			Kind:       kind.Kind,
+			Namespaced: true,
+		}, namespace)
+	}
+
+	p := newCRDClientV1(namespace, resources)
+
+	// Fail early if the custom resource definitions haven't been created
+	if _, err = p.list(crdVersionKind); err != nil {
+		return nil, fmt.Errorf("unable to list Trident custom resources in namespace %s; "+
+			"have the custom resource definitions been created? %v", namespace, err)
+	}
+
+	log.WithField("namespace", namespace).Debug("Created CRD persistent store client.")
+
+	return p, nil
+}
+
+func newCRDClientV1(namespace string, resources map[string]crdResource) *CRDClientV1 {
+	return &CRDClientV1{
+		namespace: namespace,
+		resources: resources,
+	}
+}
+
+// crdObjectName returns the Kubernetes object name for a persistent store key.
+// Keys that are already valid DNS subdomains are used as is; others are
+// lowercased, stripped of invalid characters, and suffixed with a hash of the
+// original key to keep them unique.
+func crdObjectName(key string) string {
+	if len(validation.IsDNS1123Subdomain(key)) == 0 {
+		return key
+	}
+
+	sum := sha256.Sum256([]byte(key))
+	suffix := hex.EncodeToString(sum[:])[:crdNameHashLength]
+
+	name := invalidCRDNameChars.ReplaceAllString(strings.ToLower(key), "-")
+	maxLength := validation.DNS1123SubdomainMaxLength - crdNameHashLength - 1
+	if len(name) > maxLength {
+		name = name[:maxLength]
+	}
+	name = strings.Trim(name, ".-")
+	if name == "" {
+		return suffix
+	}
+	return name + "-" + suffix
+}
+
+// crdError converts a Kubernetes API error into a persistent store error where
+// callers expect one.
+func crdError(err error, key string) error {
+	switch {
+	case k8serrors.IsNotFound(err):
+		return NewPersistentStoreError(KeyNotFoundErr, key)
+	case k8serrors.IsAlreadyExists(err):
+		return NewPersistentStoreError(KeyExistsErr, key)
+	default:
+		return err
+	}
+}
+
+// newCRDObject returns a custom resource object of the specified kind whose
+// spec holds the JSON representation of value.
+func (p *CRDClientV1) newCRDObject(kind crdKind, key string, value interface{}) (*unstructured.Unstructured, error) {
+	spec, err := toCRDSpec(value)
+	if err != nil {
+		return nil, err
+	}
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": CRDGroup + "/" + CRDVersion,
+			"kind":       kind.Kind,
+			"metadata": map[string]interface{}{
+				"name":      crdObjectName(key),
+				"namespace": p.namespace,
+			},
+			"spec": spec,
+		},
+	}, nil
+}
+
+// toCRDSpec converts a persistent object to the unstructured form of a custom
+// resource's spec.  Numbers are kept as json.Number to avoid any loss of
+// precision.
+func toCRDSpec(value interface{}) (map[string]interface{}, error) {
+	valueJSON, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	spec := make(map[string]interface{})
+	decoder := json.NewDecoder(bytes.NewReader(valueJSON))
+	decoder.UseNumber()
+	if err = decoder.Decode(&spec); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// fromCRDObject unmarshals the spec of a custom resource object into value.
+func fromCRDObject(obj *unstructured.Unstructured, value interface{}) error {
+	spec, ok := obj.Object["spec"]
+	if !ok {
+		return fmt.Errorf("%s %s has no spec", obj.GetKind(), obj.GetName())
+	}
+	specJSON, err := json.Marshal(spec)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(specJSON, value)
+}
+
+func (p *CRDClientV1) create(kind crdKind, key string, value interface{}) error {
+	defer metrics.ObserveStoreOperation(string(CRDV1Store), "create", time.Now())
+
+	obj, err := p.newCRDObject(kind, key, value)
+	if err != nil {
+		return err
+	}
+	if _, err = p.resources[kind.Kind].Create(obj); err != nil {
+		return crdError(err, key)
+	}
+	return nil
+}
+
+func (p *CRDClientV1) get(kind crdKind, key string) (*unstructured.Unstructured, error) {
+	defer metrics.ObserveStoreOperation(string(CRDV1Store), "read", time.Now())
+
+	obj, err := p.resources[kind.Kind].Get(crdObjectName(key), metav1.GetOptions{})
+	if err != nil {
+		return nil, crdError(err, key)
+	}
+	return obj, nil
+}
+
+func (p *CRDClientV1) read(kind crdKind, key string, value interface{}) error {
+	obj, err := p.get(kind, key)
+	if err != nil {
+		return err
+	}
+	return fromCRDObject(obj, value)
+}
+
+// update replaces the spec of an existing object.  The object is read first
+// so that the update carries its current resource version.
+func (p *CRDClientV1) update(kind crdKind, key string, value interface{}) error {
+	obj, err := p.get(kind, key)
+	if err != nil {
+		return err
+	}
+
+	defer metrics.ObserveStoreOperation(string(CRDV1Store), "update", time.Now())
+
+	spec, err := toCRDSpec(value)
+	if err != nil {
+		return err
+	}
+	obj.Object["spec"] = spec
+	if _, err = p.resources[kind.Kind].Update(obj); err != nil {
+		return crdError(err, key)
+	}
+	return nil
+}
+
+// set creates an object or, if it already exists, updates it.
+func (p *CRDClientV1) set(kind crdKind, key string, value interface{}) error {
+	err := p.create(kind, key, value)
+	if err != nil && err.Error() == KeyExistsErr {
+		return p.update(kind, key, value)
+	}
+	return err
+}
+
+func (p *CRDClientV1) delete(kind crdKind, key string) error {
+	defer metrics.ObserveStoreOperation(string(CRDV1Store), "delete", time.Now())
+
+	if err := p.resources[kind.Kind].Delete(crdObjectName(key), &metav1.DeleteOptions{}); err != nil {
+		return crdError(err, key)
+	}
+	return nil
+}
+
+func (p *CRDClientV1) list(kind crdKind) ([]unstructured.Unstructured, error) {
+	defer metrics.ObserveStoreOperation(string(CRDV1Store), "list", time.Now())
+
+	result, err := p.resources[kind.Kind].List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	list, ok := result.(*unstructured.UnstructuredList)
+	if !ok {
+		return nil, fmt.Errorf("unexpected list type %T for %s", result, kind.Resource)
+	}
+	return list.Items, nil
+}
+
+// deleteAll deletes all objects of the specified kind.
+func (p *CRDClientV1) deleteAll(kind crdKind) error {
+	objects, err := p.list(kind)
+	if err != nil {
+		return err
+	}
+	for _, obj := range objects {
+		err = p.resources[kind.Kind].Delete(obj.GetName(), &metav1.DeleteOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetType returns the persistent store type
+func (p *CRDClientV1) GetType() StoreType {
+	return CRDV1Store
+}
+
+// Stop is a no-op, as the CRD client holds no connections of its own
+func (p *CRDClientV1) Stop() error {
+	return nil
+}
+
+// GetConfig returns the configuration for the CRD client
+func (p *CRDClientV1) GetConfig() *ClientConfig {
+	return &ClientConfig{}
+}
+
+// Watch notifies the caller of changes to any of Trident's custom resources.
+func (p *CRDClientV1) Watch(stop <-chan struct{}) <-chan struct{} {
+	changes := make(chan struct{}, 1)
+	for _, kind := range crdKinds {
+		go p.watch(kind, stop, changes)
+	}
+	return changes
+}
+
+// watch watches the objects of one custom resource, starting a new watch
+// whenever the API server ends the current one.
+func (p *CRDClientV1) watch(kind crdKind, stop <-chan struct{}, changes chan<- struct{}) {
+	for {
+		w, err := p.resources[kind.Kind].Watch(metav1.ListOptions{})
+		if err != nil {
+			log.WithField("kind", kind.Kind).Warnf("Unable to watch custom resources: %v", err)
+			select {
+			case <-stop:
+				return
+			case <-time.After(config.LeaderRetryPeriod):
+				continue
+			}
+		}
+		if !p.forwardWatchEvents(w, stop, changes) {
+			return
+		}
+	}
+}
+
+// forwardWatchEvents notifies the caller of each event from a watch, and
+// returns whether the watch ended before the caller stopped watching.
+func (p *CRDClientV1) forwardWatchEvents(
+	w watch.Interface, stop <-chan struct{}, changes chan<- struct{},
+) bool {
+	defer w.Stop()
+	for {
+		select {
+		case <-stop:
+			return false
+		case _, ok := <-w.ResultChan():
+			if !ok {
+				return true
+			}
+			select {
+			case changes <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// GetVersion returns the version of the persistent data
+func (p *CRDClientV1) GetVersion() (*PersistentStateVersion, error) {
+	version := &PersistentStateVersion{}
+	if err := p.read(crdVersionKind, crdVersionObjectName, version); err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
+// SetVersion sets the version of the persistent data
+func (p *CRDClientV1) SetVersion(version *PersistentStateVersion) error {
+	return p.set(crdVersionKind, crdVersionObjectName, version)
+}
+
+// AddBackend saves the minimally required backend state to the persistent store
+func (p *CRDClientV1) AddBackend(b *storage.Backend) error {
+	backend := b.ConstructPersistent()
+	return p.create(crdBackendKind, backend.Name, backend)
+}
+
+// GetBackend retrieves a backend from the persistent store
+func (p *CRDClientV1) GetBackend(backendName string) (*storage.BackendPersistent, error) {
+	backend := &storage.BackendPersistent{}
+	if err := p.read(crdBackendKind, backendName, backend); err != nil {
+		return nil, err
+	}
+	return backend, nil
+}
+
+// UpdateBackend updates the backend state on the persistent store
+func (p *CRDClientV1) UpdateBackend(b *storage.Backend) error {
+	backend := b.ConstructPersistent()
+	return p.update(crdBackendKind, backend.Name, backend)
+}
+
+// DeleteBackend deletes the backend state on the persistent store
+func (p *CRDClientV1) DeleteBackend(backend *storage.Backend) error {
+	return p.delete(crdBackendKind, backend.Name)
+}
+
+// GetBackends retrieves all backends
+func (p *CRDClientV1) GetBackends() ([]*storage.BackendPersistent, error) {
+	objects, err := p.list(crdBackendKind)
+	if err != nil {
+		return nil, err
+	}
+	backendList := make([]*storage.BackendPersistent, 0, len(objects))
+	for i := range objects {
+		backend := &storage.BackendPersistent{}
+		if err = fromCRDObject(&objects[i], backend); err != nil {
+			return nil, err
+		}
+		backendList = append(backendList, backend)
+	}
+	return backendList, nil
+}
+
+// DeleteBackends deletes all backends
+func (p *CRDClientV1) DeleteBackends() error {
+	return p.deleteAll(crdBackendKind)
+}
+
+// AddVolume saves a volume's state to the persistent store
+func (p *CRDClientV1) AddVolume(vol *storage.Volume) error {
+	return p.create(crdVolumeKind, vol.Config.Name, vol.ConstructExternal())
+}
+
+// GetVolume retrieves a volume's state from the persistent store
+func (p *CRDClientV1) GetVolume(volName string) (*storage.VolumeExternal, error) {
+	volExternal := &storage.VolumeExternal{}
+	if err := p.read(crdVolumeKind, volName, volExternal); err != nil {
+		return nil, err
+	}
+	return volExternal, nil
+}
+
+// UpdateVolume updates a volume's state on the persistent store
+func (p *CRDClientV1) UpdateVolume(vol *storage.Volume) error {
+	return p.update(crdVolumeKind, vol.Config.Name, vol.ConstructExternal())
+}
+
+// DeleteVolume deletes a volume's state from the persistent store
+func (p *CRDClientV1) DeleteVolume(vol *storage.Volume) error {
+	return p.delete(crdVolumeKind, vol.Config.Name)
+}
+
+func (p *CRDClientV1) DeleteVolumeIgnoreNotFound(vol *storage.Volume) error {
+	err := p.DeleteVolume(vol)
+	if err != nil && MatchKeyNotFoundErr(err) {
+		return nil
+	}
+	return err
+}
+
+// GetVolumes retrieves all volumes
+func (p *CRDClientV1) GetVolumes() ([]*storage.VolumeExternal, error) {
+	objects, err := p.list(crdVolumeKind)
+	if err != nil {
+		return nil, err
+	}
+	volumeList := make([]*storage.VolumeExternal, 0, len(objects))
+	for i := range objects {
+		volExternal := &storage.VolumeExternal{}
+		if err = fromCRDObject(&objects[i], volExternal); err != nil {
+			return nil, err
+		}
+		volumeList = append(volumeList, volExternal)
+	}
+	return volumeList, nil
+}
+
+// DeleteVolumes deletes all volumes
+func (p *CRDClientV1) DeleteVolumes() error {
+	return p.deleteAll(crdVolumeKind)
+}
+
+// AddVolumeTransaction logs an AddVolume operation
+func (p *CRDClientV1) AddVolumeTransaction(volTxn *VolumeTransaction) error {
+	return p.set(crdTransactionKind, volTxn.getKey(), volTxn)
+}
+
+// GetVolumeTransactions retrieves AddVolume logs
+func (p *CRDClientV1) GetVolumeTransactions() ([]*VolumeTransaction, error) {
+	objects, err := p.list(crdTransactionKind)
+	if err != nil {
+		return nil, err
+	}
+	volTxnList := make([]*VolumeTransaction, 0, len(objects))
+	for i := range objects {
+		volTxn := &VolumeTransaction{}
+		if err = fromCRDObject(&objects[i], volTxn); err != nil {
+			return nil, err
+		}
+		volTxnList = append(volTxnList, volTxn)
+	}
+	return volTxnList, nil
+}
+
+// GetExistingVolumeTransaction returns an existing version of the current
+// volume transaction, if it exists.  If no volume transaction with the same
+// key exists, it returns nil.
+func (p *CRDClientV1) GetExistingVolumeTransaction(
+	volTxn *VolumeTransaction,
+) (*VolumeTransaction, error) {
+
+	key := volTxn.getKey()
+	existing := &VolumeTransaction{}
+	if err := p.read(crdTransactionKind, key, existing); err != nil {
+		if MatchKeyNotFoundErr(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("unable to read volume transaction %s: %v", key, err)
+	}
+	return existing, nil
+}
+
+// DeleteVolumeTransaction deletes an AddVolume log
+func (p *CRDClientV1) DeleteVolumeTransaction(volTxn *VolumeTransaction) error {
+	return p.delete(crdTransactionKind, volTxn.getKey())
+}
+
+// AddSnapshot saves a snapshot's state to the persistent store
+func (p *CRDClientV1) AddSnapshot(snapshot *storage.SnapshotPersistent) error {
+	return p.create(crdSnapshotKind, snapshot.ID(), snapshot)
+}
+
+// GetSnapshot retrieves a snapshot's state from the persistent store
+func (p *CRDClientV1) GetSnapshot(volumeName, snapshotName string) (*storage.SnapshotPersistent, error) {
+	snapshot := &storage.SnapshotPersistent{}
+	if err := p.read(crdSnapshotKind, storage.MakeSnapshotID(volumeName, snapshotName), snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// GetSnapshots retrieves all snapshots
+func (p *CRDClientV1) GetSnapshots() ([]*storage.SnapshotPersistent, error) {
+	objects, err := p.list(crdSnapshotKind)
+	if err != nil {
+		return nil, err
+	}
+	snapshotList := make([]*storage.SnapshotPersistent, 0, len(objects))
+	for i := range objects {
+		snapshot := &storage.SnapshotPersistent{}
+		if err = fromCRDObject(&objects[i], snapshot); err != nil {
+			return nil, err
+		}
+		snapshotList = append(snapshotList, snapshot)
+	}
+	return snapshotList, nil
+}
+
+// DeleteSnapshot deletes a snapshot's state from the persistent store
+func (p *CRDClientV1) DeleteSnapshot(snapshot *storage.SnapshotPersistent) error {
+	return p.delete(crdSnapshotKind, snapshot.ID())
+}
+
+func (p *CRDClientV1) DeleteSnapshotIgnoreNotFound(snapshot *storage.SnapshotPersistent) error {
+	err := p.DeleteSnapshot(snapshot)
+	if err != nil && MatchKeyNotFoundErr(err) {
+		return nil
+	}
+	return err
+}
+
+// DeleteSnapshots deletes all snapshots
+func (p *CRDClientV1) DeleteSnapshots() error {
+	return p.deleteAll(crdSnapshotKind)
+}
+
+func (p *CRDClientV1) AddStorageClass(sc *storageclass.StorageClass) error {
+	sClass := sc.ConstructPersistent()
+	return p.create(crdStorageClassKind, sClass.GetName(), sClass)
+}
+
+func (p *CRDClientV1) GetStorageClass(scName string) (*storageclass.Persistent, error) {
+	persistent := &storageclass.Persistent{}
+	if err := p.read(crdStorageClassKind, scName, persistent); err != nil {
+		return nil, err
+	}
+	return persistent, nil
+}
+
+func (p *CRDClientV1) GetStorageClasses() ([]*storageclass.Persistent, error) {
+	objects, err := p.list(crdStorageClassKind)
+	if err != nil {
+		return nil, err
+	}
+	storageClassList := make([]*storageclass.Persistent, 0, len(objects))
+	for i := range objects {
+		persistent := &storageclass.Persistent{}
+		if err = fromCRDObject(&objects[i], persistent); err != nil {
+			return nil, err
+		}
+		storageClassList = append(storageClassList, persistent)
+	}
+	return storageClassList, nil
+}
+
+// UpdateStorageClass updates a storage class's state on the persistent store
+func (p *CRDClientV1) UpdateStorageClass(sc *storageclass.StorageClass) error {
+	sClass := sc.ConstructPersistent()
+	return p.update(crdStorageClassKind, sClass.GetName(), sClass)
+}
+
+// DeleteStorageClass deletes a storage class's state from the persistent store
+func (p *CRDClientV1) DeleteStorageClass(sc *storageclass.StorageClass) error {
+	return p.delete(crdStorageClassKind, sc.GetName())
+}
+
+// AddOperation saves an operation's state to the persistent store
+func (p *CRDClientV1) AddOperation(op *Operation) error {
+	return p.create(crdOperationKind, op.ID, op)
+}
+
+// GetOperation retrieves an operation's state from the persistent store
+func (p *CRDClientV1) GetOperation(id string) (*Operation, error) {
+	op := &Operation{}
+	if err := p.read(crdOperationKind, id, op); err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+// GetOperations retrieves all operations
+func (p *CRDClientV1) GetOperations() ([]*Operation, error) {
+	objects, err := p.list(crdOperationKind)
+	if err != nil {
+		return nil, err
+	}
+	opList := make([]*Operation, 0, len(objects))
+	for i := range objects {
+		op := &Operation{}
+		if err = fromCRDObject(&objects[i], op); err != nil {
+			return nil, err
+		}
+		opList = append(opList, op)
+	}
+	return opList, nil
+}
+
+// UpdateOperation updates an operation's state on the persistent store
+func (p *CRDClientV1) UpdateOperation(op *Operation) error {
+	return p.update(crdOperationKind, op.ID, op)
+}
+
+// DeleteOperation deletes an operation's state from the persistent store
+func (p *CRDClientV1) DeleteOperation(op *Operation) error {
+	return p.delete(crdOperationKind, op.ID)
+}
+
+func (p *CRDClientV1) setBackend(backend *storage.BackendPersistent) error {
+	return p.set(crdBackendKind, backend.Name, backend)
+}
+
+func (p *CRDClientV1) setVolume(volume *storage.VolumeExternal) error {
+	return p.set(crdVolumeKind, volume.Config.Name, volume)
+}
+
+func (p *CRDClientV1) setStorageClass(sc *storageclass.Persistent) error {
+	return p.set(crdStorageClassKind, sc.GetName(), sc)
+}
+
+func (p *CRDClientV1) setVolumeTransaction(volTxn *VolumeTransaction) error {
+	return p.set(crdTransactionKind, volTxn.getKey(), volTxn)
+}
+
+func (p *CRDClientV1) setSnapshot(snapshot *storage.SnapshotPersistent) error {
+	return p.set(crdSnapshotKind, snapshot.ID(), snapshot)
+}
@@ -0,0 +1,439 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package persistentstore
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/watch"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage"
+	sa "github.com/netapp/trident/storage_attribute"
+	sc "github.com/netapp/trident/storage_class"
+)
+
+// fakeCRDResource keeps the objects of one custom resource in memory.
+type fakeCRDResource struct {
+	resource string
+	objects  map[string]*unstructured.Unstructured
+	watcher  *watch.RaceFreeFakeWatcher
+}
+
+func (r *fakeCRDResource) notFound(name string) error {
+	return k8serrors.NewNotFound(schema.GroupResource{Group: CRDGroup, Resource: r.resource}, name)
+}
+
+func (r *fakeCRDResource) List(opts metav1.ListOptions) (runtime.Object, error) {
+	list := &unstructured.UnstructuredList{}
+	for _, obj := range r.objects {
+		list.Items = append(list.Items, *obj.DeepCopy())
+	}
+	return list, nil
+}
+
+func (r *fakeCRDResource) Get(name string, opts metav1.GetOptions) (*unstructured.Unstructured, error) {
+	obj, ok := r.objects[name]
+	if !ok {
+		return nil, r.notFound(name)
+	}
+	return obj.DeepCopy(), nil
+}
+
+func (r *fakeCRDResource) Delete(name string, opts *metav1.DeleteOptions) error {
+	if _, ok := r.objects[name]; !ok {
+		return r.notFound(name)
+	}
+	if r.watcher != nil {
+		r.watcher.Delete(r.objects[name])
+	}
+	delete(r.objects, name)
+	return nil
+}
+
+func (r *fakeCRDResource) Create(obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	if _, ok := r.objects[obj.GetName()]; ok {
+		return nil, k8serrors.NewAlreadyExists(
+			schema.GroupResource{Group: CRDGroup, Resource: r.resource}, obj.GetName())
+	}
+	r.objects[obj.GetName()] = obj.DeepCopy()
+	if r.watcher != nil {
+		r.watcher.Add(obj.DeepCopy())
+	}
+	return obj, nil
+}
+
+func (r *fakeCRDResource) Update(obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	if _, ok := r.objects[obj.GetName()]; !ok {
+		return nil, r.notFound(obj.GetName())
+	}
+	r.objects[obj.GetName()] = obj.DeepCopy()
+	if r.watcher != nil {
+		r.watcher.Modify(obj.DeepCopy())
+	}
+	return obj, nil
+}
+
+// Watch returns the resource's watcher, which a test must set beforehand.
+func (r *fakeCRDResource) Watch(opts metav1.ListOptions) (watch.Interface, error) {
+	if r.watcher == nil {
+		return nil, fmt.Errorf("%s are not being watched", r.resource)
+	}
+	return r.watcher, nil
+}
+
+func newFakeCRDClient() *CRDClientV1 {
+	resources := make(map[string]crdResource, len(crdKinds))
+	for _, kind := range crdKinds {
+		resources[kind.Kind] = &fakeCRDResource{
+			resource: kind.Resource,
+			objects:  make(map[string]*unstructured.Unstructured),
+		}
+	}
+	return newCRDClientV1("trident", resources)
+}
+
+func TestCRDObjectName(t *testing.T) {
+	for _, key := range []string{
+		"default-pvc-1234",
+		"ontapnas_10.0.0.1",
+		"vol1/snap1",
+		"Gold",
+		"__",
+		strings.Repeat("a", 300),
+	} {
+		name := crdObjectName(key)
+		if errs := validation.IsDNS1123Subdomain(name); len(errs) != 0 {
+			t.Errorf("Name %s for key %s is invalid: %v", name, key, errs)
+		}
+	}
+	if crdObjectName("default-pvc-1234") != "default-pvc-1234" {
+		t.Error("Expected a valid key to be used as the object name.")
+	}
+	if crdObjectName("Gold") == crdObjectName("gold") {
+		t.Error("Expected keys differing only in case to have different object names.")
+	}
+}
+
+func TestCRDVersion(t *testing.T) {
+	p := newFakeCRDClient()
+
+	if _, err := p.GetVersion(); !MatchKeyNotFoundErr(err) {
+		t.Fatalf("Expected KeyNotFound, got %v", err)
+	}
+	for _, apiVersion := range []string{"1", "2"} {
+		if err := p.SetVersion(&PersistentStateVersion{string(CRDV1Store), apiVersion}); err != nil {
+			t.Fatalf("Unable to set version: %v", err)
+		}
+		version, err := p.GetVersion()
+		if err != nil {
+			t.Fatalf("Unable to get version: %v", err)
+		}
+		if version.PersistentStoreVersion != string(CRDV1Store) || version.OrchestratorAPIVersion != apiVersion {
+			t.Errorf("Unexpected version %v", version)
+		}
+	}
+}
+
+func TestCRDBackend(t *testing.T) {
+	p := newFakeCRDClient()
+	backend := getFakeBackend()
+
+	if err := p.AddBackend(backend); err != nil {
+		t.Fatalf("Unable to add backend: %v", err)
+	}
+	if err := p.AddBackend(backend); err == nil || err.Error() != KeyExistsErr {
+		t.Errorf("Expected KeyExists, got %v", err)
+	}
+
+	recovered, err := p.GetBackend(backend.Name)
+	if err != nil {
+		t.Fatalf("Unable to get backend: %v", err)
+	}
+	if recovered.Name != backend.Name || recovered.Version != backend.ConstructPersistent().Version {
+		t.Errorf("Unexpected backend %v", recovered)
+	}
+
+	backend.Online = false
+	if err = p.UpdateBackend(backend); err != nil {
+		t.Fatalf("Unable to update backend: %v", err)
+	}
+	if recovered, err = p.GetBackend(backend.Name); err != nil {
+		t.Fatalf("Unable to get backend: %v", err)
+	} else if recovered.Online {
+		t.Error("Expected the backend update to be persisted.")
+	}
+
+	backends, err := p.GetBackends()
+	if err != nil || len(backends) != 1 {
+		t.Errorf("Unexpected backends %v, error %v", backends, err)
+	}
+
+	if err = p.DeleteBackend(backend); err != nil {
+		t.Fatalf("Unable to delete backend: %v", err)
+	}
+	if _, err = p.GetBackend(backend.Name); !MatchKeyNotFoundErr(err) {
+		t.Errorf("Expected KeyNotFound, got %v", err)
+	}
+	if err = p.UpdateBackend(backend); !MatchKeyNotFoundErr(err) {
+		t.Errorf("Expected KeyNotFound, got %v", err)
+	}
+}
+
+func TestCRDVolume(t *testing.T) {
+	p := newFakeCRDClient()
+	vol := getFakeVolume()
+
+	if err := p.AddVolume(vol); err != nil {
+		t.Fatalf("Unable to add volume: %v", err)
+	}
+	recovered, err := p.GetVolume(vol.Config.Name)
+	if err != nil {
+		t.Fatalf("Unable to get volume: %v", err)
+	}
+	if recovered.Config.Size != vol.Config.Size || recovered.Backend != vol.Backend {
+		t.Errorf("Unexpected volume %v", recovered)
+	}
+
+	vol.Config.Size = "2GB"
+	if err = p.UpdateVolume(vol); err != nil {
+		t.Fatalf("Unable to update volume: %v", err)
+	}
+	if recovered, err = p.GetVolume(vol.Config.Name); err != nil {
+		t.Fatalf("Unable to get volume: %v", err)
+	} else if recovered.Config.Size != "2GB" {
+		t.Errorf("Expected the volume update to be persisted, got size %s", recovered.Config.Size)
+	}
+
+	if err = p.DeleteVolume(vol); err != nil {
+		t.Fatalf("Unable to delete volume: %v", err)
+	}
+	if err = p.DeleteVolume(vol); !MatchKeyNotFoundErr(err) {
+		t.Errorf("Expected KeyNotFound, got %v", err)
+	}
+	if err = p.DeleteVolumeIgnoreNotFound(vol); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestCRDVolumeTransactions(t *testing.T) {
+	p := newFakeCRDClient()
+	volTxn := &VolumeTransaction{
+		Config: &storage.VolumeConfig{Name: "vol1", Size: "1GB"},
+		Op:     AddVolume,
+	}
+
+	if existing, err := p.GetExistingVolumeTransaction(volTxn); err != nil || existing != nil {
+		t.Errorf("Unexpected transaction %v, error %v", existing, err)
+	}
+
+	// Adding a transaction for the same volume replaces the existing one
+	if err := p.AddVolumeTransaction(volTxn); err != nil {
+		t.Fatalf("Unable to add transaction: %v", err)
+	}
+	volTxn.Op = DeleteVolume
+	if err := p.AddVolumeTransaction(volTxn); err != nil {
+		t.Fatalf("Unable to add transaction: %v", err)
+	}
+
+	existing, err := p.GetExistingVolumeTransaction(volTxn)
+	if err != nil || existing == nil || existing.Op != DeleteVolume {
+		t.Errorf("Unexpected transaction %v, error %v", existing, err)
+	}
+	volTxns, err := p.GetVolumeTransactions()
+	if err != nil || len(volTxns) != 1 {
+		t.Errorf("Unexpected transactions %v, error %v", volTxns, err)
+	}
+
+	if err = p.DeleteVolumeTransaction(volTxn); err != nil {
+		t.Fatalf("Unable to delete transaction: %v", err)
+	}
+	if volTxns, err = p.GetVolumeTransactions(); err != nil || len(volTxns) != 0 {
+		t.Errorf("Unexpected transactions %v, error %v", volTxns, err)
+	}
+}
+
+func TestCRDSnapshots(t *testing.T) {
+	p := newFakeCRDClient()
+	snapshot := &storage.SnapshotPersistent{
+		Snapshot: storage.Snapshot{Name: "snap1", Created: "2018-01-01T00:00:00Z"},
+		Volume:   "vol1",
+	}
+
+	if err := p.AddSnapshot(snapshot); err != nil {
+		t.Fatalf("Unable to add snapshot: %v", err)
+	}
+	recovered, err := p.GetSnapshot("vol1", "snap1")
+	if err != nil {
+		t.Fatalf("Unable to get snapshot: %v", err)
+	}
+	if recovered.ID() != snapshot.ID() || recovered.Created != snapshot.Created {
+		t.Errorf("Unexpected snapshot %v", recovered)
+	}
+
+	if err = p.DeleteSnapshots(); err != nil {
+		t.Fatalf("Unable to delete snapshots: %v", err)
+	}
+	if snapshots, err := p.GetSnapshots(); err != nil || len(snapshots) != 0 {
+		t.Errorf("Unexpected snapshots %v, error %v", snapshots, err)
+	}
+	if err = p.DeleteSnapshotIgnoreNotFound(snapshot); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestCRDOperations(t *testing.T) {
+	p := newFakeCRDClient()
+	op := &Operation{
+		ID:      "4c3c2a0e-3d4e-4b0c-9d8e-6a3b0a6f2f11",
+		Type:    AddVolumeOperation,
+		Target:  "vol1",
+		State:   OperationRunning,
+		Created: "2018-01-01T00:00:00Z",
+		Updated: "2018-01-01T00:00:00Z",
+	}
+
+	if err := p.AddOperation(op); err != nil {
+		t.Fatalf("Unable to add operation: %v", err)
+	}
+	op.State = OperationFailed
+	op.Error = "no suitable backend"
+	if err := p.UpdateOperation(op); err != nil {
+		t.Fatalf("Unable to update operation: %v", err)
+	}
+	if recovered, err := p.GetOperation(op.ID); err != nil || *recovered != *op {
+		t.Errorf("Unexpected operation %v, error %v", recovered, err)
+	}
+	if ops, err := p.GetOperations(); err != nil || len(ops) != 1 {
+		t.Errorf("Unexpected operations %v, error %v", ops, err)
+	}
+
+	if err := p.DeleteOperation(op); err != nil {
+		t.Fatalf("Unable to delete operation: %v", err)
+	}
+	if _, err := p.GetOperation(op.ID); !MatchKeyNotFoundErr(err) {
+		t.Errorf("Expected KeyNotFound, got %v", err)
+	}
+}
+
+func TestCRDStorageClass(t *testing.T) {
+	p := newFakeCRDClient()
+	storageClass := sc.New(&sc.Config{
+		Name: "gold",
+		Attributes: map[string]sa.Request{
+			sa.IOPS:             sa.NewIntRequest(1000),
+			sa.Snapshots:        sa.NewBoolRequest(true),
+			sa.ProvisioningType: sa.NewStringRequest("thin"),
+		},
+	})
+
+	if err := p.AddStorageClass(storageClass); err != nil {
+		t.Fatalf("Unable to add storage class: %v", err)
+	}
+	recovered, err := p.GetStorageClass("gold")
+	if err != nil {
+		t.Fatalf("Unable to get storage class: %v", err)
+	}
+	if len(recovered.Config.Attributes) != 3 {
+		t.Errorf("Unexpected storage class attributes %v", recovered.Config.Attributes)
+	}
+
+	updated := sc.New(&sc.Config{
+		Name:       "gold",
+		Attributes: map[string]sa.Request{sa.IOPS: sa.NewIntRequest(2000)},
+	})
+	if err = p.UpdateStorageClass(updated); err != nil {
+		t.Fatalf("Unable to update storage class: %v", err)
+	}
+	if recovered, err = p.GetStorageClass("gold"); err != nil || len(recovered.Config.Attributes) != 1 {
+		t.Errorf("Unexpected storage class %v, error %v", recovered, err)
+	}
+	if err = p.UpdateStorageClass(sc.New(&sc.Config{Name: "silver"})); !MatchKeyNotFoundErr(err) {
+		t.Errorf("Expected KeyNotFound updating a missing storage class, got %v", err)
+	}
+
+	if err = p.DeleteStorageClass(storageClass); err != nil {
+		t.Fatalf("Unable to delete storage class: %v", err)
+	}
+	if storageClasses, err := p.GetStorageClasses(); err != nil || len(storageClasses) != 0 {
+		t.Errorf("Unexpected storage classes %v, error %v", storageClasses, err)
+	}
+}
+
+func TestCRDDataMigration(t *testing.T) {
+	source := NewInMemoryClient()
+	backend := getFakeBackend()
+	vol := getFakeVolume()
+	if err := source.AddBackend(backend); err != nil {
+		t.Fatal(err)
+	}
+	if err := source.AddVolume(vol); err != nil {
+		t.Fatal(err)
+	}
+	if err := source.AddVolumeTransaction(&VolumeTransaction{Config: vol.Config, Op: DeleteVolume}); err != nil {
+		t.Fatal(err)
+	}
+	if err := source.AddStorageClass(sc.New(&sc.Config{Name: "gold"})); err != nil {
+		t.Fatal(err)
+	}
+
+	dest := newFakeCRDClient()
+
+	// Without a source client there is nothing to migrate
+	if err := NewDataMigrator(dest, EtcdV2Store).Run("/"+config.OrchestratorName, false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if backends, _ := dest.GetBackends(); len(backends) != 0 {
+		t.Errorf("Unexpected backends %v", backends)
+	}
+
+	// Migrating twice has the same result as migrating once
+	for i := 0; i < 2; i++ {
+		migrator := NewDataMigrator(dest, source.GetType())
+		migrator.SourceClient = source
+		if err := migrator.Run("/"+config.OrchestratorName, false); err != nil {
+			t.Fatalf("Data migration failed: %v", err)
+		}
+	}
+
+	if _, err := dest.GetBackend(backend.Name); err != nil {
+		t.Errorf("Unable to get migrated backend: %v", err)
+	}
+	if _, err := dest.GetVolume(vol.Config.Name); err != nil {
+		t.Errorf("Unable to get migrated volume: %v", err)
+	}
+	if _, err := dest.GetStorageClass("gold"); err != nil {
+		t.Errorf("Unable to get migrated storage class: %v", err)
+	}
+	if volTxns, err := dest.GetVolumeTransactions(); err != nil || len(volTxns) != 1 {
+		t.Errorf("Unexpected transactions %v, error %v", volTxns, err)
+	}
+}
+
+func TestCRDWatch(t *testing.T) {
+	p := newFakeCRDClient()
+	for _, resource := range p.resources {
+		resource.(*fakeCRDResource).watcher = watch.NewRaceFreeFake()
+	}
+	stop := make(chan struct{})
+	defer close(stop)
+	changes := p.Watch(stop)
+
+	if err := p.AddVolume(getFakeVolume()); err != nil {
+		t.Fatalf("Unable to add volume: %v", err)
+	}
+	select {
+	case <-changes:
+	case <-time.After(5 * time.Second):
+		t.Error("Expected a change to be reported.")
+	}
+}
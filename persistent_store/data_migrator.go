@@ -6,6 +6,9 @@ import (
 	"fmt"
 
 	log "github.com/sirupsen/logrus"
+
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/storage_class"
 )
 
 type DataMigrator struct {
@@ -26,8 +29,13 @@ func (m *DataMigrator) Run(keyPrefix string, deleteSrc bool) error {
 	if m.DestClient.GetType() == m.SourceType {
 		return nil
 	}
+	// Migration to a store that can be written from persistent objects
+	// copies them through the Client interface, so it works from any source.
+	if destinationClient, ok := m.DestClient.(persistentStateWriter); ok {
+		return m.runCopyMigration(destinationClient, keyPrefix, deleteSrc)
+	}
 	// Determine if this is a supported data migration
-	// 1) DataMigrator currently only supports etcdv2 to etcdv3 migration
+	// 1) DataMigrator otherwise only supports etcdv2 to etcdv3 migration
 	if m.DestClient.GetType() != EtcdV3Store &&
 		m.SourceType != EtcdV2Store {
 		// No transformation
@@ -83,3 +91,117 @@ func (m *DataMigrator) Run(keyPrefix string, deleteSrc bool) error {
 	}
 	return nil
 }
+
+// persistentStateWriter is implemented by stores that can save Trident's
+// objects in the form they are read from another store, which lets them be
+// the destination of a migration from any store.  Each method creates the
+// object or, if it already exists, replaces it.
+type persistentStateWriter interface {
+	setBackend(backend *storage.BackendPersistent) error
+	setVolume(volume *storage.VolumeExternal) error
+	setStorageClass(sc *storageclass.Persistent) error
+	setVolumeTransaction(volTxn *VolumeTransaction) error
+	setSnapshot(snapshot *storage.SnapshotPersistent) error
+}
+
+// runCopyMigration copies all Trident objects from the source client to the
+// destination.  Without a source client, there is nothing to migrate (e.g.,
+// on a fresh install).
+func (m *DataMigrator) runCopyMigration(
+	destinationClient persistentStateWriter, keyPrefix string, deleteSrc bool,
+) error {
+	if m.SourceClient == nil {
+		log.WithFields(log.Fields{
+			"source_store_version":  string(m.SourceType),
+			"desired_store_version": string(m.DestClient.GetType()),
+		}).Debug("No source persistent store to migrate from.")
+		return nil
+	}
+
+	log.WithFields(log.Fields{
+		"current_store_version": string(m.SourceClient.GetType()),
+		"desired_store_version": string(m.DestClient.GetType()),
+	}).Info("Transforming persistent state.")
+
+	if err := copyPersistentState(m.SourceClient, destinationClient); err != nil {
+		return fmt.Errorf("migration to the %s store failed: %v", m.DestClient.GetType(), err)
+	}
+
+	if deleteSrc {
+		etcdClient, ok := m.SourceClient.(EtcdClient)
+		if !ok {
+			return fmt.Errorf("unable to delete the source data from a %s store",
+				m.SourceClient.GetType())
+		}
+		if err := etcdClient.DeleteKeys(keyPrefix); err != nil {
+			return fmt.Errorf("failed to delete the source data after migration: %v", err)
+		}
+	}
+	return nil
+}
+
+// copyPersistentState copies all of Trident's state from one store to
+// another.  Objects that already exist are overwritten, so an interrupted
+// copy may simply be repeated.
+func copyPersistentState(source Client, dest persistentStateWriter) error {
+
+	backends, err := source.GetBackends()
+	if err != nil {
+		return fmt.Errorf("unable to read backends: %v", err)
+	}
+	for _, backend := range backends {
+		if err = dest.setBackend(backend); err != nil {
+			return fmt.Errorf("unable to copy backend %s: %v", backend.Name, err)
+		}
+	}
+
+	storageClasses, err := source.GetStorageClasses()
+	if err != nil {
+		return fmt.Errorf("unable to read storage classes: %v", err)
+	}
+	for _, sc := range storageClasses {
+		if err = dest.setStorageClass(sc); err != nil {
+			return fmt.Errorf("unable to copy storage class %s: %v", sc.GetName(), err)
+		}
+	}
+
+	volumes, err := source.GetVolumes()
+	if err != nil {
+		return fmt.Errorf("unable to read volumes: %v", err)
+	}
+	for _, vol := range volumes {
+		if err = dest.setVolume(vol); err != nil {
+			return fmt.Errorf("unable to copy volume %s: %v", vol.Config.Name, err)
+		}
+	}
+
+	volTxns, err := source.GetVolumeTransactions()
+	if err != nil {
+		return fmt.Errorf("unable to read volume transactions: %v", err)
+	}
+	for _, volTxn := range volTxns {
+		if err = dest.setVolumeTransaction(volTxn); err != nil {
+			return fmt.Errorf("unable to copy volume transaction %s: %v", volTxn.getKey(), err)
+		}
+	}
+
+	snapshots, err := source.GetSnapshots()
+	if err != nil {
+		return fmt.Errorf("unable to read snapshots: %v", err)
+	}
+	for _, snapshot := range snapshots {
+		if err = dest.setSnapshot(snapshot); err != nil {
+			return fmt.Errorf("unable to copy snapshot %s: %v", snapshot.ID(), err)
+		}
+	}
+
+	log.WithFields(log.Fields{
+		"backends":       len(backends),
+		"storageClasses": len(storageClasses),
+		"volumes":        len(volumes),
+		"transactions":   len(volTxns),
+		"snapshots":      len(snapshots),
+	}).Info("Copied persistent state.")
+
+	return nil
+}
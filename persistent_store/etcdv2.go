@@ -13,6 +13,7 @@ import (
 	"golang.org/x/net/context"
 
 	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/metrics"
 	"github.com/netapp/trident/storage"
 	"github.com/netapp/trident/storage_class"
 )
@@ -74,6 +75,8 @@ func NewEtcdClientV2FromConfig(etcdConfig *ClientConfig) (*EtcdClientV2, error)
 
 // Create is the abstract CRUD interface
 func (p *EtcdClientV2) Create(key, value string) error {
+	defer metrics.ObserveStoreOperation(string(EtcdV2Store), "create", time.Now())
+
 	ctx, cancel := context.WithTimeout(context.Background(), config.PersistentStoreTimeout)
 	_, err := p.keysAPI.Create(ctx, key, value)
 	cancel()
@@ -84,6 +87,8 @@ func (p *EtcdClientV2) Create(key, value string) error {
 }
 
 func (p *EtcdClientV2) Read(key string) (string, error) {
+	defer metrics.ObserveStoreOperation(string(EtcdV2Store), "read", time.Now())
+
 	ctx, cancel := context.WithTimeout(context.Background(), config.PersistentStoreTimeout)
 	resp, err := p.keysAPI.Get(ctx, key, &etcdclientv2.GetOptions{Recursive: true, Sort: true, Quorum: true})
 	cancel()
@@ -98,6 +103,8 @@ func (p *EtcdClientV2) Read(key string) (string, error) {
 
 // ReadKeys returns all the keys with the designated prefix
 func (p *EtcdClientV2) ReadKeys(keyPrefix string) ([]string, error) {
+	defer metrics.ObserveStoreOperation(string(EtcdV2Store), "read_keys", time.Now())
+
 	keys := make([]string, 0)
 	ctx, cancel := context.WithTimeout(context.Background(), config.PersistentStoreTimeout)
 	resp, err := p.keysAPI.Get(ctx, keyPrefix, &etcdclientv2.GetOptions{Recursive: true, Sort: true, Quorum: true})
@@ -131,6 +138,8 @@ func (p *EtcdClientV2) ReadKeys(keyPrefix string) ([]string, error) {
 }
 
 func (p *EtcdClientV2) Update(key, value string) error {
+	defer metrics.ObserveStoreOperation(string(EtcdV2Store), "update", time.Now())
+
 	ctx, cancel := context.WithTimeout(context.Background(), config.PersistentStoreTimeout)
 	_, err := p.keysAPI.Update(ctx, key, value)
 	cancel()
@@ -144,6 +153,8 @@ func (p *EtcdClientV2) Update(key, value string) error {
 }
 
 func (p *EtcdClientV2) Set(key, value string) error {
+	defer metrics.ObserveStoreOperation(string(EtcdV2Store), "set", time.Now())
+
 	ctx, cancel := context.WithTimeout(context.Background(), config.PersistentStoreTimeout)
 	_, err := p.keysAPI.Set(ctx, key, value, &etcdclientv2.SetOptions{})
 	cancel()
@@ -157,6 +168,8 @@ func (p *EtcdClientV2) Set(key, value string) error {
 }
 
 func (p *EtcdClientV2) Delete(key string) error {
+	defer metrics.ObserveStoreOperation(string(EtcdV2Store), "delete", time.Now())
+
 	ctx, cancel := context.WithTimeout(context.Background(), config.PersistentStoreTimeout)
 	_, err := p.keysAPI.Delete(ctx, key, &etcdclientv2.DeleteOptions{Recursive: true})
 	cancel()
@@ -469,6 +482,88 @@ func (p *EtcdClientV2) DeleteVolumeTransaction(volTxn *VolumeTransaction) error
 	return nil
 }
 
+// AddSnapshot saves a snapshot's state to the persistent store
+func (p *EtcdClientV2) AddSnapshot(snapshot *storage.SnapshotPersistent) error {
+	snapJSON, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	err = p.Create(config.SnapshotURL+"/"+snapshot.ID(), string(snapJSON))
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetSnapshot retrieves a snapshot's state from the persistent store
+func (p *EtcdClientV2) GetSnapshot(volumeName, snapshotName string) (*storage.SnapshotPersistent, error) {
+	snapJSON, err := p.Read(config.SnapshotURL + "/" + storage.MakeSnapshotID(volumeName, snapshotName))
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &storage.SnapshotPersistent{}
+	err = json.Unmarshal([]byte(snapJSON), snapshot)
+	if err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// GetSnapshots retrieves all snapshots
+func (p *EtcdClientV2) GetSnapshots() ([]*storage.SnapshotPersistent, error) {
+	snapshotList := make([]*storage.SnapshotPersistent, 0)
+	keys, err := p.ReadKeys(config.SnapshotURL)
+	if err != nil && MatchKeyNotFoundErr(err) {
+		return snapshotList, nil
+	} else if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		snapshot := &storage.SnapshotPersistent{}
+		snapJSON, err := p.Read(key)
+		if err != nil {
+			return nil, err
+		}
+		err = json.Unmarshal([]byte(snapJSON), snapshot)
+		if err != nil {
+			return nil, err
+		}
+		snapshotList = append(snapshotList, snapshot)
+	}
+	return snapshotList, nil
+}
+
+// DeleteSnapshot deletes a snapshot's state from the persistent store
+func (p *EtcdClientV2) DeleteSnapshot(snapshot *storage.SnapshotPersistent) error {
+	err := p.Delete(config.SnapshotURL + "/" + snapshot.ID())
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (p *EtcdClientV2) DeleteSnapshotIgnoreNotFound(snapshot *storage.SnapshotPersistent) error {
+	err := p.DeleteSnapshot(snapshot)
+	if err != nil && MatchKeyNotFoundErr(err) {
+		return nil
+	}
+	return err
+}
+
+// DeleteSnapshots deletes all snapshots
+func (p *EtcdClientV2) DeleteSnapshots() error {
+	snapshots, err := p.ReadKeys(config.SnapshotURL)
+	if err != nil {
+		return err
+	}
+	for _, snapshot := range snapshots {
+		if err = p.Delete(snapshot); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (p *EtcdClientV2) AddStorageClass(sc *storageclass.StorageClass) error {
 	sClass := sc.ConstructPersistent()
 	storageClassJSON, err := json.Marshal(sClass)
@@ -515,6 +610,20 @@ func (p *EtcdClientV2) GetStorageClasses() ([]*storageclass.Persistent, error) {
 	return storageClassList, nil
 }
 
+// UpdateStorageClass updates a storage class's state on the persistent store
+func (p *EtcdClientV2) UpdateStorageClass(sc *storageclass.StorageClass) error {
+	sClass := sc.ConstructPersistent()
+	storageClassJSON, err := json.Marshal(sClass)
+	if err != nil {
+		return err
+	}
+	err = p.Update(config.StorageClassURL+"/"+sClass.GetName(), string(storageClassJSON))
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
 // DeleteStorageClass deletes a storage class's state from the persistent store
 func (p *EtcdClientV2) DeleteStorageClass(sc *storageclass.StorageClass) error {
 	err := p.Delete(config.StorageClassURL + "/" + sc.GetName())
@@ -523,3 +632,58 @@ func (p *EtcdClientV2) DeleteStorageClass(sc *storageclass.StorageClass) error {
 	}
 	return nil
 }
+
+// AddOperation saves an operation's state to the persistent store
+func (p *EtcdClientV2) AddOperation(op *Operation) error {
+	opJSON, err := json.Marshal(op)
+	if err != nil {
+		return err
+	}
+	return p.Create(config.OperationURL+"/"+op.ID, string(opJSON))
+}
+
+// GetOperation retrieves an operation's state from the persistent store
+func (p *EtcdClientV2) GetOperation(id string) (*Operation, error) {
+	opJSON, err := p.Read(config.OperationURL + "/" + id)
+	if err != nil {
+		return nil, err
+	}
+	op := &Operation{}
+	if err = json.Unmarshal([]byte(opJSON), op); err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+// GetOperations retrieves all operations
+func (p *EtcdClientV2) GetOperations() ([]*Operation, error) {
+	opList := make([]*Operation, 0)
+	keys, err := p.ReadKeys(config.OperationURL)
+	if err != nil && MatchKeyNotFoundErr(err) {
+		return opList, nil
+	} else if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		op, err := p.GetOperation(strings.TrimPrefix(key, config.OperationURL+"/"))
+		if err != nil {
+			return nil, err
+		}
+		opList = append(opList, op)
+	}
+	return opList, nil
+}
+
+// UpdateOperation updates an operation's state on the persistent store
+func (p *EtcdClientV2) UpdateOperation(op *Operation) error {
+	opJSON, err := json.Marshal(op)
+	if err != nil {
+		return err
+	}
+	return p.Update(config.OperationURL+"/"+op.ID, string(opJSON))
+}
+
+// DeleteOperation deletes an operation's state from the persistent store
+func (p *EtcdClientV2) DeleteOperation(op *Operation) error {
+	return p.Delete(config.OperationURL + "/" + op.ID)
+}
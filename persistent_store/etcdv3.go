@@ -10,14 +10,17 @@ import (
 	"fmt"
 	"io/ioutil"
 	"strings"
+	"time"
 
 	"github.com/coreos/etcd/clientv3"
+	log "github.com/sirupsen/logrus"
 	//TODO: Change for the later versions of etcd (etcd v3.1.5 doesn't return any error for unfound keys but later versions do)
 	//"github.com/coreos/etcd/etcdserver"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 
 	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/metrics"
 	"github.com/netapp/trident/storage"
 	"github.com/netapp/trident/storage_class"
 )
@@ -127,6 +130,8 @@ func (p *EtcdClientV3) Create(key, value string) error {
 }
 
 func (p *EtcdClientV3) Read(key string) (string, error) {
+	defer metrics.ObserveStoreOperation(string(EtcdV3Store), "read", time.Now())
+
 	ctx, cancel := context.WithTimeout(context.Background(), config.PersistentStoreTimeout)
 	resp, err := p.clientV3.Get(ctx, key)
 	cancel()
@@ -150,6 +155,8 @@ func (p *EtcdClientV3) Read(key string) (string, error) {
 
 // ReadKeys returns all the keys with the designated prefix
 func (p *EtcdClientV3) ReadKeys(keyPrefix string) ([]string, error) {
+	defer metrics.ObserveStoreOperation(string(EtcdV3Store), "read_keys", time.Now())
+
 	keys := make([]string, 0)
 	ctx, cancel := context.WithTimeout(context.Background(), config.PersistentStoreTimeout)
 	resp, err := p.clientV3.Get(ctx, keyPrefix,
@@ -182,6 +189,8 @@ func (p *EtcdClientV3) Update(key, value string) error {
 }
 
 func (p *EtcdClientV3) Set(key, value string) error {
+	defer metrics.ObserveStoreOperation(string(EtcdV3Store), "set", time.Now())
+
 	ctx, cancel := context.WithTimeout(context.Background(), config.PersistentStoreTimeout)
 	_, err := p.clientV3.Put(ctx, key, value)
 	cancel()
@@ -192,6 +201,8 @@ func (p *EtcdClientV3) Set(key, value string) error {
 }
 
 func (p *EtcdClientV3) Delete(key string) error {
+	defer metrics.ObserveStoreOperation(string(EtcdV3Store), "delete", time.Now())
+
 	ctx, cancel := context.WithTimeout(context.Background(), config.PersistentStoreTimeout)
 	resp, err := p.clientV3.Delete(ctx, key)
 	cancel()
@@ -236,6 +247,35 @@ func (p *EtcdClientV3) GetConfig() *ClientConfig {
 	}
 }
 
+// Watch notifies the caller of changes to any of Trident's objects.
+func (p *EtcdClientV3) Watch(stop <-chan struct{}) <-chan struct{} {
+	changes := make(chan struct{}, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	watchChan := p.clientV3.Watch(ctx, config.BaseURL, clientv3.WithPrefix())
+	go func() {
+		defer cancel()
+		for {
+			select {
+			case <-stop:
+				return
+			case resp, ok := <-watchChan:
+				if !ok {
+					return
+				}
+				if err := resp.Err(); err != nil {
+					log.Warnf("Watch on the etcd store failed: %v", err)
+					continue
+				}
+				select {
+				case changes <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+	return changes
+}
+
 // GetVersion returns the version of the persistent data
 func (p *EtcdClientV3) GetVersion() (*PersistentStateVersion, error) {
 	versionJSON, err := p.Read(config.StoreURL)
@@ -505,6 +545,88 @@ func (p *EtcdClientV3) DeleteVolumeTransaction(volTxn *VolumeTransaction) error
 	return nil
 }
 
+// AddSnapshot saves a snapshot's state to the persistent store
+func (p *EtcdClientV3) AddSnapshot(snapshot *storage.SnapshotPersistent) error {
+	snapJSON, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	err = p.Create(config.SnapshotURL+"/"+snapshot.ID(), string(snapJSON))
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetSnapshot retrieves a snapshot's state from the persistent store
+func (p *EtcdClientV3) GetSnapshot(volumeName, snapshotName string) (*storage.SnapshotPersistent, error) {
+	snapJSON, err := p.Read(config.SnapshotURL + "/" + storage.MakeSnapshotID(volumeName, snapshotName))
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &storage.SnapshotPersistent{}
+	err = json.Unmarshal([]byte(snapJSON), snapshot)
+	if err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// GetSnapshots retrieves all snapshots
+func (p *EtcdClientV3) GetSnapshots() ([]*storage.SnapshotPersistent, error) {
+	snapshotList := make([]*storage.SnapshotPersistent, 0)
+	keys, err := p.ReadKeys(config.SnapshotURL)
+	if err != nil && MatchKeyNotFoundErr(err) {
+		return snapshotList, nil
+	} else if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		snapshot := &storage.SnapshotPersistent{}
+		snapJSON, err := p.Read(key)
+		if err != nil {
+			return nil, err
+		}
+		err = json.Unmarshal([]byte(snapJSON), snapshot)
+		if err != nil {
+			return nil, err
+		}
+		snapshotList = append(snapshotList, snapshot)
+	}
+	return snapshotList, nil
+}
+
+// DeleteSnapshot deletes a snapshot's state from the persistent store
+func (p *EtcdClientV3) DeleteSnapshot(snapshot *storage.SnapshotPersistent) error {
+	err := p.Delete(config.SnapshotURL + "/" + snapshot.ID())
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (p *EtcdClientV3) DeleteSnapshotIgnoreNotFound(snapshot *storage.SnapshotPersistent) error {
+	err := p.DeleteSnapshot(snapshot)
+	if err != nil && MatchKeyNotFoundErr(err) {
+		return nil
+	}
+	return err
+}
+
+// DeleteSnapshots deletes all snapshots
+func (p *EtcdClientV3) DeleteSnapshots() error {
+	snapshots, err := p.ReadKeys(config.SnapshotURL)
+	if err != nil {
+		return err
+	}
+	for _, snapshot := range snapshots {
+		if err = p.Delete(snapshot); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (p *EtcdClientV3) AddStorageClass(sc *storageclass.StorageClass) error {
 	sClass := sc.ConstructPersistent()
 	storageClassJSON, err := json.Marshal(sClass)
@@ -551,6 +673,20 @@ func (p *EtcdClientV3) GetStorageClasses() ([]*storageclass.Persistent, error) {
 	return storageClassList, nil
 }
 
+// UpdateStorageClass updates a storage class's state on the persistent store
+func (p *EtcdClientV3) UpdateStorageClass(sc *storageclass.StorageClass) error {
+	sClass := sc.ConstructPersistent()
+	storageClassJSON, err := json.Marshal(sClass)
+	if err != nil {
+		return err
+	}
+	err = p.Update(config.StorageClassURL+"/"+sClass.GetName(), string(storageClassJSON))
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
 // DeleteStorageClass deletes a storage class's state from the persistent store
 func (p *EtcdClientV3) DeleteStorageClass(sc *storageclass.StorageClass) error {
 	err := p.Delete(config.StorageClassURL + "/" + sc.GetName())
@@ -559,3 +695,58 @@ func (p *EtcdClientV3) DeleteStorageClass(sc *storageclass.StorageClass) error {
 	}
 	return nil
 }
+
+// AddOperation saves an operation's state to the persistent store
+func (p *EtcdClientV3) AddOperation(op *Operation) error {
+	opJSON, err := json.Marshal(op)
+	if err != nil {
+		return err
+	}
+	return p.Create(config.OperationURL+"/"+op.ID, string(opJSON))
+}
+
+// GetOperation retrieves an operation's state from the persistent store
+func (p *EtcdClientV3) GetOperation(id string) (*Operation, error) {
+	opJSON, err := p.Read(config.OperationURL + "/" + id)
+	if err != nil {
+		return nil, err
+	}
+	op := &Operation{}
+	if err = json.Unmarshal([]byte(opJSON), op); err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+// GetOperations retrieves all operations
+func (p *EtcdClientV3) GetOperations() ([]*Operation, error) {
+	opList := make([]*Operation, 0)
+	keys, err := p.ReadKeys(config.OperationURL)
+	if err != nil && MatchKeyNotFoundErr(err) {
+		return opList, nil
+	} else if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		op, err := p.GetOperation(strings.TrimPrefix(key, config.OperationURL+"/"))
+		if err != nil {
+			return nil, err
+		}
+		opList = append(opList, op)
+	}
+	return opList, nil
+}
+
+// UpdateOperation updates an operation's state on the persistent store
+func (p *EtcdClientV3) UpdateOperation(op *Operation) error {
+	opJSON, err := json.Marshal(op)
+	if err != nil {
+		return err
+	}
+	return p.Update(config.OperationURL+"/"+op.ID, string(opJSON))
+}
+
+// DeleteOperation deletes an operation's state from the persistent store
+func (p *EtcdClientV3) DeleteOperation(op *Operation) error {
+	return p.Delete(config.OperationURL + "/" + op.ID)
+}
@@ -0,0 +1,55 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package persistentstore
+
+import (
+	"github.com/coreos/etcd/clientv3/concurrency"
+	"golang.org/x/net/context"
+
+	"github.com/netapp/trident/config"
+)
+
+// EtcdLeaderElectorV3 elects a leader among the Trident replicas sharing an
+// etcd cluster.  The leader holds a key tied to a lease, which lapses if the
+// leader stops renewing it.
+type EtcdLeaderElectorV3 struct {
+	session  *concurrency.Session
+	election *concurrency.Election
+	identity string
+}
+
+// NewLeaderElector returns an elector that campaigns under the given
+// identity, which should be unique among the replicas.
+func (p *EtcdClientV3) NewLeaderElector(identity string) (*EtcdLeaderElectorV3, error) {
+	session, err := concurrency.NewSession(p.clientV3,
+		concurrency.WithTTL(int(config.LeaderLeaseDuration.Seconds())))
+	if err != nil {
+		return nil, err
+	}
+	return &EtcdLeaderElectorV3{
+		session:  session,
+		election: concurrency.NewElection(session, config.LeaderURL),
+		identity: identity,
+	}, nil
+}
+
+// Campaign blocks until this replica is elected or the context is done.
+func (e *EtcdLeaderElectorV3) Campaign(ctx context.Context) error {
+	return e.election.Campaign(ctx, e.identity)
+}
+
+// Lost returns a channel that is closed if this replica's lease lapses, after
+// which it is no longer the leader.
+func (e *EtcdLeaderElectorV3) Lost() <-chan struct{} {
+	return e.session.Done()
+}
+
+// Resign gives up leadership, if held, and revokes this replica's lease.
+func (e *EtcdLeaderElectorV3) Resign() error {
+	ctx, cancel := context.WithTimeout(context.Background(), config.PersistentStoreTimeout)
+	defer cancel()
+	if err := e.election.Resign(ctx); err != nil {
+		return err
+	}
+	return e.session.Close()
+}
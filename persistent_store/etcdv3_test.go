@@ -514,6 +514,81 @@ func TestEtcdv3Volumes(t *testing.T) {
 	}
 }
 
+func TestEtcdv3Snapshot(t *testing.T) {
+	p, err := NewEtcdClientV3(*etcdV3)
+
+	// Adding a snapshot
+	snap1 := storage.Snapshot{
+		Name:    "snap1",
+		Created: "2018-01-01T00:00:00Z",
+	}
+	snap1Persistent := snap1.ConstructPersistent("vol1")
+	err = p.AddSnapshot(snap1Persistent)
+	if err != nil {
+		t.Error(err.Error())
+		t.FailNow()
+	}
+
+	// Adding a duplicate snapshot
+	if err = p.AddSnapshot(snap1Persistent); err == nil {
+		t.Error("Second snapshot add should have failed!")
+	}
+
+	// Getting a snapshot
+	var recoveredSnapshot *storage.SnapshotPersistent
+	recoveredSnapshot, err = p.GetSnapshot("vol1", "snap1")
+	if err != nil {
+		t.Error(err.Error())
+		t.FailNow()
+	}
+	if !reflect.DeepEqual(recoveredSnapshot, snap1Persistent) {
+		t.Error("Recovered snapshot does not match!")
+	}
+
+	// Adding a snapshot with the same name to another volume
+	snap2Persistent := snap1.ConstructPersistent("vol2")
+	if err = p.AddSnapshot(snap2Persistent); err != nil {
+		t.Error(err.Error())
+	}
+
+	// Retrieving all snapshots
+	var snapshots []*storage.SnapshotPersistent
+	if snapshots, err = p.GetSnapshots(); err != nil {
+		t.Error(err.Error())
+		t.FailNow()
+	}
+	found := 0
+	for _, snapshot := range snapshots {
+		if snapshot.Name == "snap1" {
+			found++
+		}
+	}
+	if found != 2 {
+		t.Errorf("Expected 2 snapshots named snap1; retrieved %d", found)
+	}
+
+	// Deleting a snapshot
+	if err = p.DeleteSnapshot(snap1Persistent); err != nil {
+		t.Error(err.Error())
+	}
+	if _, err = p.GetSnapshot("vol1", "snap1"); err == nil || !MatchKeyNotFoundErr(err) {
+		t.Error("Snapshot should have been deleted!")
+	}
+	if err = p.DeleteSnapshotIgnoreNotFound(snap1Persistent); err != nil {
+		t.Error(err.Error())
+	}
+
+	// Deleting all snapshots
+	if err = p.DeleteSnapshots(); err != nil {
+		t.Error(err.Error())
+	}
+	if snapshots, err = p.GetSnapshots(); err != nil {
+		t.Error(err.Error())
+	} else if len(snapshots) != 0 {
+		t.Error("Deleting snapshots failed!")
+	}
+}
+
 func TestEtcdv3VolumeTransactions(t *testing.T) {
 	p, err := NewEtcdClientV3(*etcdV3)
 
@@ -4,6 +4,7 @@ package persistentstore
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/netapp/trident/config"
 	"github.com/netapp/trident/storage"
@@ -11,6 +12,7 @@ import (
 )
 
 type InMemoryClient struct {
+	mutex               sync.Mutex
 	backends            map[string]*storage.BackendPersistent
 	backendsAdded       int
 	volumes             map[string]*storage.VolumeExternal
@@ -19,6 +21,10 @@ type InMemoryClient struct {
 	storageClassesAdded int
 	volumeTxns          map[string]*VolumeTransaction
 	volumeTxnsAdded     int
+	snapshots           map[string]*storage.SnapshotPersistent
+	snapshotsAdded      int
+	operations          map[string]*Operation
+	operationsAdded     int
 	version             *PersistentStateVersion
 }
 
@@ -28,6 +34,8 @@ func NewInMemoryClient() *InMemoryClient {
 		volumes:        make(map[string]*storage.VolumeExternal),
 		storageClasses: make(map[string]*sc.Persistent),
 		volumeTxns:     make(map[string]*VolumeTransaction),
+		snapshots:      make(map[string]*storage.SnapshotPersistent),
+		operations:     make(map[string]*Operation),
 		version: &PersistentStateVersion{
 			"memory", config.OrchestratorAPIVersion,
 		},
@@ -39,10 +47,15 @@ func (c *InMemoryClient) GetType() StoreType {
 }
 
 func (c *InMemoryClient) Stop() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	c.backendsAdded = 0
 	c.volumesAdded = 0
 	c.storageClassesAdded = 0
 	c.volumeTxnsAdded = 0
+	c.snapshotsAdded = 0
+	c.operationsAdded = 0
 	return nil
 }
 
@@ -51,14 +64,23 @@ func (c *InMemoryClient) GetConfig() *ClientConfig {
 }
 
 func (c *InMemoryClient) GetVersion() (*PersistentStateVersion, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	return c.version, nil
 }
 
 func (c *InMemoryClient) SetVersion(version *PersistentStateVersion) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	return nil
 }
 
 func (c *InMemoryClient) AddBackend(b *storage.Backend) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	backend := b.ConstructPersistent()
 	if _, ok := c.backends[backend.Name]; ok {
 		return fmt.Errorf("backend %s already exists", backend.Name)
@@ -69,6 +91,9 @@ func (c *InMemoryClient) AddBackend(b *storage.Backend) error {
 }
 
 func (c *InMemoryClient) GetBackend(backendName string) (*storage.BackendPersistent, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	ret, ok := c.backends[backendName]
 	if !ok {
 		return nil, NewPersistentStoreError(KeyNotFoundErr, backendName)
@@ -77,6 +102,9 @@ func (c *InMemoryClient) GetBackend(backendName string) (*storage.BackendPersist
 }
 
 func (c *InMemoryClient) UpdateBackend(b *storage.Backend) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	// UpdateBackend requires the backend to already exist.
 	if _, ok := c.backends[b.Name]; !ok {
 		return NewPersistentStoreError(KeyNotFoundErr, b.Name)
@@ -86,6 +114,9 @@ func (c *InMemoryClient) UpdateBackend(b *storage.Backend) error {
 }
 
 func (c *InMemoryClient) DeleteBackend(b *storage.Backend) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	if _, ok := c.backends[b.Name]; !ok {
 		return NewPersistentStoreError(KeyNotFoundErr, b.Name)
 	}
@@ -94,6 +125,9 @@ func (c *InMemoryClient) DeleteBackend(b *storage.Backend) error {
 }
 
 func (c *InMemoryClient) GetBackends() ([]*storage.BackendPersistent, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	backendList := make([]*storage.BackendPersistent, 0)
 	if c.backendsAdded == 0 {
 		// Try to match etcd semantics as closely as possible.
@@ -106,6 +140,9 @@ func (c *InMemoryClient) GetBackends() ([]*storage.BackendPersistent, error) {
 }
 
 func (c *InMemoryClient) DeleteBackends() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	if c.backendsAdded == 0 {
 		// Try to match etcd semantics as closely as possible.
 		return NewPersistentStoreError(KeyNotFoundErr, "Backends")
@@ -115,6 +152,9 @@ func (c *InMemoryClient) DeleteBackends() error {
 }
 
 func (c *InMemoryClient) AddVolume(vol *storage.Volume) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	volume := vol.ConstructExternal()
 	if _, ok := c.volumes[volume.Config.Name]; ok {
 		return fmt.Errorf("volume %s already exists", volume.Config.Name)
@@ -127,6 +167,9 @@ func (c *InMemoryClient) AddVolume(vol *storage.Volume) error {
 func (c *InMemoryClient) GetVolume(volumeName string) (
 	*storage.VolumeExternal, error,
 ) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	ret, ok := c.volumes[volumeName]
 	if !ok {
 		return nil, NewPersistentStoreError(KeyNotFoundErr, volumeName)
@@ -135,6 +178,9 @@ func (c *InMemoryClient) GetVolume(volumeName string) (
 }
 
 func (c *InMemoryClient) UpdateVolume(vol *storage.Volume) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	// UpdateVolume requires the volume to already exist.
 	if _, ok := c.volumes[vol.Config.Name]; !ok {
 		return NewPersistentStoreError(KeyNotFoundErr, vol.Config.Name)
@@ -144,6 +190,9 @@ func (c *InMemoryClient) UpdateVolume(vol *storage.Volume) error {
 }
 
 func (c *InMemoryClient) DeleteVolume(vol *storage.Volume) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	if _, ok := c.volumes[vol.Config.Name]; !ok {
 		return NewPersistentStoreError(KeyNotFoundErr, vol.Config.Name)
 	}
@@ -152,11 +201,17 @@ func (c *InMemoryClient) DeleteVolume(vol *storage.Volume) error {
 }
 
 func (c *InMemoryClient) DeleteVolumeIgnoreNotFound(vol *storage.Volume) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	delete(c.volumes, vol.Config.Name)
 	return nil
 }
 
 func (c *InMemoryClient) GetVolumes() ([]*storage.VolumeExternal, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	ret := make([]*storage.VolumeExternal, 0, len(c.volumes))
 	if c.volumesAdded == 0 {
 		// Try to match etcd semantics as closely as possible.
@@ -169,6 +224,9 @@ func (c *InMemoryClient) GetVolumes() ([]*storage.VolumeExternal, error) {
 }
 
 func (c *InMemoryClient) DeleteVolumes() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	if c.volumesAdded == 0 {
 		// Try to match etcd semantics as closely as possible.
 		return NewPersistentStoreError(KeyNotFoundErr, "Volumes")
@@ -178,6 +236,9 @@ func (c *InMemoryClient) DeleteVolumes() error {
 }
 
 func (c *InMemoryClient) AddVolumeTransaction(volTxn *VolumeTransaction) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	// AddVolumeTransaction overwrites existing keys, unlike the other methods
 	c.volumeTxns[volTxn.getKey()] = volTxn
 	c.volumeTxnsAdded++
@@ -185,6 +246,9 @@ func (c *InMemoryClient) AddVolumeTransaction(volTxn *VolumeTransaction) error {
 }
 
 func (c *InMemoryClient) GetVolumeTransactions() ([]*VolumeTransaction, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	if c.volumeTxnsAdded == 0 {
 		// Try to match etcd semantics as closely as possible.
 		return nil, NewPersistentStoreError(KeyNotFoundErr, "VolumesTransactions")
@@ -199,6 +263,9 @@ func (c *InMemoryClient) GetVolumeTransactions() ([]*VolumeTransaction, error) {
 func (c *InMemoryClient) GetExistingVolumeTransaction(
 	volTxn *VolumeTransaction) (*VolumeTransaction, error,
 ) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	vt, ok := c.volumeTxns[volTxn.getKey()]
 	if !ok {
 		return nil, nil
@@ -207,6 +274,9 @@ func (c *InMemoryClient) GetExistingVolumeTransaction(
 }
 
 func (c *InMemoryClient) DeleteVolumeTransaction(volTxn *VolumeTransaction) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	if _, ok := c.volumeTxns[volTxn.getKey()]; !ok {
 		return NewPersistentStoreError(KeyNotFoundErr, "VolumesTransactions")
 	}
@@ -214,7 +284,83 @@ func (c *InMemoryClient) DeleteVolumeTransaction(volTxn *VolumeTransaction) erro
 	return nil
 }
 
+func (c *InMemoryClient) AddSnapshot(snapshot *storage.SnapshotPersistent) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, ok := c.snapshots[snapshot.ID()]; ok {
+		return fmt.Errorf("snapshot %s already exists", snapshot.ID())
+	}
+	snapshotCopy := *snapshot
+	c.snapshots[snapshot.ID()] = &snapshotCopy
+	c.snapshotsAdded++
+	return nil
+}
+
+func (c *InMemoryClient) GetSnapshot(volumeName, snapshotName string) (
+	*storage.SnapshotPersistent, error,
+) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	snapshotID := storage.MakeSnapshotID(volumeName, snapshotName)
+	ret, ok := c.snapshots[snapshotID]
+	if !ok {
+		return nil, NewPersistentStoreError(KeyNotFoundErr, snapshotID)
+	}
+	return ret, nil
+}
+
+func (c *InMemoryClient) GetSnapshots() ([]*storage.SnapshotPersistent, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	ret := make([]*storage.SnapshotPersistent, 0, len(c.snapshots))
+	if c.snapshotsAdded == 0 {
+		// Try to match etcd semantics as closely as possible.
+		return ret, nil
+	}
+	for _, s := range c.snapshots {
+		ret = append(ret, s)
+	}
+	return ret, nil
+}
+
+func (c *InMemoryClient) DeleteSnapshot(snapshot *storage.SnapshotPersistent) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, ok := c.snapshots[snapshot.ID()]; !ok {
+		return NewPersistentStoreError(KeyNotFoundErr, snapshot.ID())
+	}
+	delete(c.snapshots, snapshot.ID())
+	return nil
+}
+
+func (c *InMemoryClient) DeleteSnapshotIgnoreNotFound(snapshot *storage.SnapshotPersistent) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.snapshots, snapshot.ID())
+	return nil
+}
+
+func (c *InMemoryClient) DeleteSnapshots() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.snapshotsAdded == 0 {
+		// Try to match etcd semantics as closely as possible.
+		return NewPersistentStoreError(KeyNotFoundErr, "Snapshots")
+	}
+	c.snapshots = make(map[string]*storage.SnapshotPersistent)
+	return nil
+}
+
 func (c *InMemoryClient) AddStorageClass(s *sc.StorageClass) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	storageClass := s.ConstructPersistent()
 	if _, ok := c.storageClasses[storageClass.GetName()]; ok {
 		return fmt.Errorf("storage class %s already exists", storageClass.GetName())
@@ -227,6 +373,9 @@ func (c *InMemoryClient) AddStorageClass(s *sc.StorageClass) error {
 func (c *InMemoryClient) GetStorageClass(scName string) (
 	*sc.Persistent, error,
 ) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	ret, ok := c.storageClasses[scName]
 	if !ok {
 		return nil, NewPersistentStoreError(KeyNotFoundErr, scName)
@@ -237,6 +386,9 @@ func (c *InMemoryClient) GetStorageClass(scName string) (
 func (c *InMemoryClient) GetStorageClasses() (
 	[]*sc.Persistent, error,
 ) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	ret := make([]*sc.Persistent, 0, len(c.storageClasses))
 	if c.storageClassesAdded == 0 {
 		// Try to match etcd semantics as closely as possible.
@@ -248,10 +400,89 @@ func (c *InMemoryClient) GetStorageClasses() (
 	return ret, nil
 }
 
+func (c *InMemoryClient) UpdateStorageClass(s *sc.StorageClass) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	// UpdateStorageClass requires the storage class to already exist.
+	if _, ok := c.storageClasses[s.GetName()]; !ok {
+		return NewPersistentStoreError(KeyNotFoundErr, s.GetName())
+	}
+	c.storageClasses[s.GetName()] = s.ConstructPersistent()
+	return nil
+}
+
 func (c *InMemoryClient) DeleteStorageClass(s *sc.StorageClass) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	if _, ok := c.storageClasses[s.GetName()]; !ok {
 		return NewPersistentStoreError(KeyNotFoundErr, s.GetName())
 	}
 	delete(c.storageClasses, s.GetName())
 	return nil
 }
+
+func (c *InMemoryClient) AddOperation(op *Operation) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, ok := c.operations[op.ID]; ok {
+		return fmt.Errorf("operation %s already exists", op.ID)
+	}
+	opCopy := *op
+	c.operations[op.ID] = &opCopy
+	c.operationsAdded++
+	return nil
+}
+
+func (c *InMemoryClient) GetOperation(id string) (*Operation, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	op, ok := c.operations[id]
+	if !ok {
+		return nil, NewPersistentStoreError(KeyNotFoundErr, id)
+	}
+	opCopy := *op
+	return &opCopy, nil
+}
+
+func (c *InMemoryClient) GetOperations() ([]*Operation, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	ret := make([]*Operation, 0, len(c.operations))
+	if c.operationsAdded == 0 {
+		// Try to match etcd semantics as closely as possible.
+		return ret, nil
+	}
+	for _, op := range c.operations {
+		opCopy := *op
+		ret = append(ret, &opCopy)
+	}
+	return ret, nil
+}
+
+func (c *InMemoryClient) UpdateOperation(op *Operation) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, ok := c.operations[op.ID]; !ok {
+		return NewPersistentStoreError(KeyNotFoundErr, op.ID)
+	}
+	opCopy := *op
+	c.operations[op.ID] = &opCopy
+	return nil
+}
+
+func (c *InMemoryClient) DeleteOperation(op *Operation) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, ok := c.operations[op.ID]; !ok {
+		return NewPersistentStoreError(KeyNotFoundErr, op.ID)
+	}
+	delete(c.operations, op.ID)
+	return nil
+}
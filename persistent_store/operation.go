@@ -0,0 +1,38 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package persistentstore
+
+type OperationType string
+
+const (
+	AddVolumeOperation    OperationType = "addVolume"
+	ImportVolumeOperation OperationType = "importVolume"
+)
+
+type OperationState string
+
+const (
+	OperationRunning   OperationState = "running"
+	OperationSucceeded OperationState = "succeeded"
+	OperationFailed    OperationState = "failed"
+)
+
+// Operation records the progress of a long-running request that Trident
+// completes in the background, so that clients may poll for its outcome
+// rather than wait on a single HTTP request.  Operations are persisted so
+// that their outcome outlives a restart of Trident.
+type Operation struct {
+	ID       string         `json:"id"`
+	Type     OperationType  `json:"type"`
+	Target   string         `json:"target"`
+	State    OperationState `json:"state"`
+	Progress string         `json:"progress,omitempty"`
+	Error    string         `json:"error,omitempty"`
+	Created  string         `json:"created"`
+	Updated  string         `json:"updated"`
+}
+
+// Done returns whether the operation has finished, successfully or not.
+func (op *Operation) Done() bool {
+	return op.State == OperationSucceeded || op.State == OperationFailed
+}
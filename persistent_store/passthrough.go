@@ -22,6 +22,7 @@ import (
 )
 
 type PassthroughClient struct {
+	mutex        sync.RWMutex
 	liveBackends map[string]*storage.Backend
 	bootBackends []*storage.BackendPersistent
 	version      *PersistentStateVersion
@@ -152,7 +153,9 @@ func (c *PassthroughClient) unmarshalConfig(fileContents []byte) (string, error)
 
 	var configType string
 	switch commonConfig.StorageDriverName {
-	case drivers.OntapNASStorageDriverName, drivers.OntapNASQtreeStorageDriverName, drivers.OntapSANStorageDriverName:
+	case drivers.OntapNASStorageDriverName, drivers.OntapNASQtreeStorageDriverName,
+		drivers.OntapNASFlexGroupStorageDriverName, drivers.OntapSANStorageDriverName,
+		drivers.OntapSANEconomyStorageDriverName:
 		configType = "ontap_config"
 	case drivers.SolidfireSANStorageDriverName:
 		configType = "solidfire_config"
@@ -169,6 +172,7 @@ func (c *PassthroughClient) unmarshalConfig(fileContents []byte) (string, error)
 		Config:  storage.PersistentStorageBackendConfig{},
 		Name:    "",
 		Online:  true,
+		State:   storage.BackendStateOnline,
 	}
 	persistentBackendJSON, _ := json.Marshal(persistentBackend)
 
@@ -182,6 +186,9 @@ func (c *PassthroughClient) GetType() StoreType {
 }
 
 func (c *PassthroughClient) Stop() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	c.liveBackends = make(map[string]*storage.Backend)
 	c.bootBackends = make([]*storage.BackendPersistent, 0)
 	return nil
@@ -206,11 +213,16 @@ func (c *PassthroughClient) AddBackend(backend *storage.Backend) error {
 	// back to a file system for subsequent bootstrapping, that logic will live
 	// here and in UpdateBackend().
 	log.WithField("backend", backend.Name).Debugf("Passthrough store adding backend.")
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	c.liveBackends[backend.Name] = backend
 	return nil
 }
 
 func (c *PassthroughClient) GetBackend(backendName string) (*storage.BackendPersistent, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
 
 	existingBackend, ok := c.liveBackends[backendName]
 	if !ok {
@@ -221,6 +233,8 @@ func (c *PassthroughClient) GetBackend(backendName string) (*storage.BackendPers
 }
 
 func (c *PassthroughClient) UpdateBackend(backend *storage.Backend) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
 
 	if _, ok := c.liveBackends[backend.Name]; !ok {
 		return NewPersistentStoreError(KeyNotFoundErr, backend.Name)
@@ -232,6 +246,8 @@ func (c *PassthroughClient) UpdateBackend(backend *storage.Backend) error {
 }
 
 func (c *PassthroughClient) DeleteBackend(backend *storage.Backend) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
 
 	if _, ok := c.liveBackends[backend.Name]; !ok {
 		return NewPersistentStoreError(KeyNotFoundErr, backend.Name)
@@ -256,6 +272,9 @@ func (c *PassthroughClient) GetBackends() ([]*storage.BackendPersistent, error)
 }
 
 func (c *PassthroughClient) DeleteBackends() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	c.liveBackends = make(map[string]*storage.Backend)
 	return nil
 }
@@ -291,11 +310,18 @@ func (c *PassthroughClient) GetVolumes() ([]*storage.VolumeExternal, error) {
 
 	volumeChannel := make(chan *storage.VolumeExternalWrapper)
 
+	c.mutex.RLock()
+	backends := make([]*storage.Backend, 0, len(c.liveBackends))
+	for _, backend := range c.liveBackends {
+		backends = append(backends, backend)
+	}
+	c.mutex.RUnlock()
+
 	var waitGroup sync.WaitGroup
-	waitGroup.Add(len(c.liveBackends))
+	waitGroup.Add(len(backends))
 
 	// Get volumes from each backend in a goroutine
-	for _, backend := range c.liveBackends {
+	for _, backend := range backends {
 		go c.getVolumesFromBackend(backend, volumeChannel, &waitGroup)
 	}
 
@@ -359,6 +385,32 @@ func (c *PassthroughClient) DeleteVolumeTransaction(volTxn *VolumeTransaction) e
 	return nil
 }
 
+func (c *PassthroughClient) AddSnapshot(snapshot *storage.SnapshotPersistent) error {
+	return nil
+}
+
+// GetSnapshot is not called by the orchestrator, which caches all snapshots in
+// memory after bootstrapping.  So this method need not do anything.
+func (c *PassthroughClient) GetSnapshot(volumeName, snapshotName string) (*storage.SnapshotPersistent, error) {
+	return nil, NewPersistentStoreError(KeyNotFoundErr, storage.MakeSnapshotID(volumeName, snapshotName))
+}
+
+func (c *PassthroughClient) GetSnapshots() ([]*storage.SnapshotPersistent, error) {
+	return make([]*storage.SnapshotPersistent, 0), nil
+}
+
+func (c *PassthroughClient) DeleteSnapshot(snapshot *storage.SnapshotPersistent) error {
+	return nil
+}
+
+func (c *PassthroughClient) DeleteSnapshotIgnoreNotFound(snapshot *storage.SnapshotPersistent) error {
+	return nil
+}
+
+func (c *PassthroughClient) DeleteSnapshots() error {
+	return nil
+}
+
 func (c *PassthroughClient) AddStorageClass(sc *sc.StorageClass) error {
 	return nil
 }
@@ -371,6 +423,32 @@ func (c *PassthroughClient) GetStorageClasses() ([]*sc.Persistent, error) {
 	return make([]*sc.Persistent, 0), nil
 }
 
+func (c *PassthroughClient) UpdateStorageClass(sc *sc.StorageClass) error {
+	return nil
+}
+
 func (c *PassthroughClient) DeleteStorageClass(sc *sc.StorageClass) error {
 	return nil
 }
+
+// Operations are only recorded in memory by the orchestrator when using the
+// passthrough store, so they don't survive a restart.
+func (c *PassthroughClient) AddOperation(op *Operation) error {
+	return nil
+}
+
+func (c *PassthroughClient) GetOperation(id string) (*Operation, error) {
+	return nil, NewPersistentStoreError(KeyNotFoundErr, id)
+}
+
+func (c *PassthroughClient) GetOperations() ([]*Operation, error) {
+	return make([]*Operation, 0), nil
+}
+
+func (c *PassthroughClient) UpdateOperation(op *Operation) error {
+	return nil
+}
+
+func (c *PassthroughClient) DeleteOperation(op *Operation) error {
+	return nil
+}
@@ -16,6 +16,8 @@ const (
 	EtcdV2Store      StoreType = "etcdv2"
 	EtcdV3Store      StoreType = "etcdv3"
 	PassthroughStore StoreType = "passthrough"
+	CRDV1Store       StoreType = "crdv1"
+	BoltStore        StoreType = "bolt"
 )
 
 type PersistentStateVersion struct {
@@ -56,10 +58,24 @@ type Client interface {
 		error)
 	DeleteVolumeTransaction(volTxn *VolumeTransaction) error
 
+	AddSnapshot(snapshot *storage.SnapshotPersistent) error
+	GetSnapshot(volumeName, snapshotName string) (*storage.SnapshotPersistent, error)
+	GetSnapshots() ([]*storage.SnapshotPersistent, error)
+	DeleteSnapshot(snapshot *storage.SnapshotPersistent) error
+	DeleteSnapshotIgnoreNotFound(snapshot *storage.SnapshotPersistent) error
+	DeleteSnapshots() error
+
 	AddStorageClass(sc *storageclass.StorageClass) error
 	GetStorageClass(scName string) (*storageclass.Persistent, error)
 	GetStorageClasses() ([]*storageclass.Persistent, error)
+	UpdateStorageClass(sc *storageclass.StorageClass) error
 	DeleteStorageClass(sc *storageclass.StorageClass) error
+
+	AddOperation(op *Operation) error
+	GetOperation(id string) (*Operation, error)
+	GetOperations() ([]*Operation, error)
+	UpdateOperation(op *Operation) error
+	DeleteOperation(op *Operation) error
 }
 
 type EtcdClient interface {
@@ -72,3 +88,11 @@ type EtcdClient interface {
 	Delete(key string) error
 	DeleteKeys(keyPrefix string) error
 }
+
+// Watcher is implemented by stores that can tell a client when other clients
+// change the persisted state.
+type Watcher interface {
+	// Watch returns a channel that receives a value after each change to the
+	// persisted state, until stop is closed.  Changes may be coalesced.
+	Watch(stop <-chan struct{}) <-chan struct{}
+}
@@ -13,6 +13,8 @@ type VolumeOperation string
 const (
 	AddVolume    VolumeOperation = "addVolume"
 	DeleteVolume VolumeOperation = "deleteVolume"
+	ResizeVolume VolumeOperation = "resizeVolume"
+	ImportVolume VolumeOperation = "importVolume"
 )
 
 type VolumeTransaction struct {
@@ -6,6 +6,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"reflect"
+	"sort"
 	"strconv"
 
 	log "github.com/sirupsen/logrus"
@@ -26,14 +28,28 @@ type Driver interface {
 	Create(name string, sizeBytes uint64, opts map[string]string) error
 	CreateClone(name, source, snapshot string, opts map[string]string) error
 	Destroy(name string) error
+	// Resize grows the named volume to the requested size.  Shrinking a
+	// volume is not supported.
+	Resize(name string, sizeBytes uint64) error
 	Attach(name, mountpoint string, opts map[string]string) error
 	Detach(name, mountpoint string) error
 	SnapshotList(name string) ([]Snapshot, error)
+	CreateSnapshot(name, snapshot string) (*Snapshot, error)
+	DeleteSnapshot(name, snapshot string) error
+	// RestoreSnapshot reverts the named volume to the state captured by one
+	// of its snapshots, discarding any changes made after it was taken.
+	RestoreSnapshot(name, snapshot string) error
 	List() ([]string, error)
 	Get(name string) error
 	CreatePrepare(volConfig *VolumeConfig) bool
 	// CreateFollowup adds necessary information for accessing the volume to VolumeConfig.
 	CreateFollowup(volConfig *VolumeConfig) error
+	// Import prepares a volume that was not created by Trident to be managed
+	// by it.  originalName is the name of the volume on the backend; if it
+	// differs from volConfig.InternalName, the volume is renamed to match.
+	// Like CreateFollowup, Import adds the information needed to access the
+	// volume to volConfig.
+	Import(volConfig *VolumeConfig, originalName string) error
 	// GetInternalVolumeName will return a name that satisfies any character
 	// constraints present on the backend and that will be unique to Trident.
 	// The latter requirement should generally be done by prepending the
@@ -50,15 +66,41 @@ type Driver interface {
 	// GetExternalConfig returns a version of the driver configuration that
 	// lacks confidential information, such as usernames and passwords.
 	GetExternalConfig() interface{}
+	// GetCommonConfig returns the settings the driver shares with all
+	// drivers, such as its storage prefix and orphan policy.
+	GetCommonConfig() *drivers.CommonStorageDriverConfig
 	GetVolumeExternal(name string) (*VolumeExternal, error)
 	GetVolumeExternalWrappers(chan *VolumeExternalWrapper)
+	// GetReplicationStatus returns the state of the relationship that
+	// replicates the named volume, or nil if the volume isn't replicated.
+	GetReplicationStatus(name string) (*ReplicationStatus, error)
+	// Failover breaks the replication relationship of a volume, making its
+	// replica writable, and updates the access information in volConfig to
+	// reach the replica in place of the original volume.
+	Failover(volConfig *VolumeConfig) error
 }
 
+// BackendState describes where a backend is in its lifecycle.
+type BackendState string
+
+const (
+	// BackendStateOnline backends may be used for new volumes.
+	BackendStateOnline = BackendState("online")
+	// BackendStateDeleting backends have been deleted by the user but still
+	// hold volumes.  They are offline, and they are removed once their last
+	// volume is deleted.
+	BackendStateDeleting = BackendState("deleting")
+)
+
 type Backend struct {
 	Driver  Driver
 	Name    string
 	Online  bool
+	State   BackendState
 	Storage map[string]*Pool
+	// Volumes is maintained by the orchestrator rather than by the volume
+	// methods below, so that it can be guarded by the orchestrator's lock
+	// while the slow calls to the storage run concurrently.
 	Volumes map[string]*Volume
 }
 
@@ -66,6 +108,7 @@ func NewStorageBackend(driver Driver) (*Backend, error) {
 	backend := Backend{
 		Driver:  driver,
 		Online:  true,
+		State:   BackendStateOnline,
 		Storage: make(map[string]*Pool),
 		Volumes: make(map[string]*Volume),
 	}
@@ -74,14 +117,62 @@ func NewStorageBackend(driver Driver) (*Backend, error) {
 	if err := backend.Driver.GetStorageBackendSpecs(&backend); err != nil {
 		return nil, err
 	}
+	backend.addLabels()
 
 	return &backend, nil
 }
 
+// addLabels offers the labels in the backend's config on each of its storage
+// pools, unless the driver gave the pool a label of the same name.
+func (b *Backend) addLabels() {
+	commonConfig := b.Driver.GetCommonConfig()
+	if commonConfig == nil {
+		return
+	}
+	for _, pool := range b.Storage {
+		for label, value := range commonConfig.Labels {
+			attrName := storageattribute.LabelAttribute(label)
+			if _, ok := pool.Attributes[attrName]; !ok {
+				pool.Attributes[attrName] = storageattribute.NewStringOffer(value)
+			}
+		}
+	}
+}
+
 func (b *Backend) AddStoragePool(pool *Pool) {
 	b.Storage[pool.Name] = pool
 }
 
+// GetPoolCapacities asks the driver for the current capacity of the
+// backend's storage pools, keyed by pool name.  It doesn't modify the
+// backend, so it may be called without holding the orchestrator's lock.
+func (b *Backend) GetPoolCapacities() (map[string]*PoolCapacity, error) {
+	current := &Backend{
+		Driver:  b.Driver,
+		Name:    b.Name,
+		Storage: make(map[string]*Pool),
+	}
+	if err := b.Driver.GetStorageBackendSpecs(current); err != nil {
+		return nil, err
+	}
+	capacities := make(map[string]*PoolCapacity, len(current.Storage))
+	for name, pool := range current.Storage {
+		capacities[name] = pool.Capacity
+	}
+	return capacities, nil
+}
+
+// SetPoolCapacities records capacities returned by GetPoolCapacities on the
+// existing pools, leaving the rest of the pools' state, such as their storage
+// classes, alone.
+func (b *Backend) SetPoolCapacities(capacities map[string]*PoolCapacity) {
+	for name, pool := range b.Storage {
+		if capacity, ok := capacities[name]; ok {
+			pool.Capacity = capacity
+		}
+	}
+}
+
 func (b *Backend) GetDriverName() string {
 	return b.Driver.Name()
 }
@@ -149,7 +240,6 @@ func (b *Backend) AddVolume(
 			return nil, err
 		}
 		vol := NewVolume(volConfig, b.Name, storagePool.Name, false)
-		b.Volumes[vol.Config.Name] = vol
 		return vol, err
 	} else {
 		log.WithFields(log.Fields{
@@ -208,7 +298,25 @@ func (b *Backend) CloneVolume(volConfig *VolumeConfig) (*Volume, error) {
 		return nil, err
 	}
 	vol := NewVolume(volConfig, b.Name, drivers.UnsetPool, false)
-	b.Volumes[vol.Config.Name] = vol
+	return vol, nil
+}
+
+// ImportVolume brings a volume that already exists on the backend under
+// Trident's management.  originalName is the volume's current name on the
+// backend, and poolName is the pool the backend reports it in.
+func (b *Backend) ImportVolume(volConfig *VolumeConfig, originalName, poolName string) (*Volume, error) {
+
+	log.WithFields(log.Fields{
+		"backend":      b.Name,
+		"volume":       volConfig.Name,
+		"originalName": originalName,
+		"internalName": volConfig.InternalName,
+	}).Debug("Attempting volume import.")
+
+	if err := b.Driver.Import(volConfig, originalName); err != nil {
+		return nil, err
+	}
+	vol := NewVolume(volConfig, b.Name, poolName, false)
 	return vol, nil
 }
 
@@ -224,12 +332,115 @@ func (b *Backend) RemoveVolume(vol *Volume) error {
 		// for volumes that aren't found.
 		return err
 	}
-	if _, ok := b.Volumes[vol.Config.Name]; ok {
-		delete(b.Volumes, vol.Config.Name)
-	}
 	return nil
 }
 
+// ResizeVolume grows a volume on the backend to the requested size, which is
+// given in the same format as VolumeConfig.Size.  On success, it returns the
+// new size in bytes, in the form the volume's config records it; the volume
+// itself is left alone for the caller to update.
+func (b *Backend) ResizeVolume(vol *Volume, newSize string) (string, error) {
+
+	if err := vol.CheckNotFailedOver("resize"); err != nil {
+		return "", err
+	}
+
+	// Determine volume size in bytes
+	requestedSize, err := utils.ConvertSizeToBytes(newSize)
+	if err != nil {
+		return "", fmt.Errorf("could not convert volume size %s: %v", newSize, err)
+	}
+	newSizeBytes, err := strconv.ParseUint(requestedSize, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("%v is an invalid volume size: %v", newSize, err)
+	}
+
+	log.WithFields(log.Fields{
+		"backend": b.Name,
+		"volume":  vol.Config.InternalName,
+		"size":    newSizeBytes,
+	}).Debug("Attempting volume resize.")
+
+	if err = b.Driver.Resize(vol.Config.InternalName, newSizeBytes); err != nil {
+		return "", err
+	}
+	return requestedSize, nil
+}
+
+// CreateSnapshot takes a snapshot of a volume on the backend.
+func (b *Backend) CreateSnapshot(vol *Volume, snapshotName string) (*Snapshot, error) {
+
+	log.WithFields(log.Fields{
+		"backend":  b.Name,
+		"volume":   vol.Config.InternalName,
+		"snapshot": snapshotName,
+	}).Debug("Attempting snapshot create.")
+
+	if err := vol.CheckNotFailedOver("snapshot"); err != nil {
+		return nil, err
+	}
+
+	return b.Driver.CreateSnapshot(vol.Config.InternalName, snapshotName)
+}
+
+// DeleteSnapshot deletes a snapshot of a volume on the backend.
+func (b *Backend) DeleteSnapshot(vol *Volume, snapshotName string) error {
+
+	log.WithFields(log.Fields{
+		"backend":  b.Name,
+		"volume":   vol.Config.InternalName,
+		"snapshot": snapshotName,
+	}).Debug("Attempting snapshot delete.")
+
+	if err := vol.CheckNotFailedOver("delete a snapshot of"); err != nil {
+		return err
+	}
+
+	return b.Driver.DeleteSnapshot(vol.Config.InternalName, snapshotName)
+}
+
+// RestoreSnapshot restores a volume on the backend from one of its snapshots.
+func (b *Backend) RestoreSnapshot(vol *Volume, snapshotName string) error {
+
+	log.WithFields(log.Fields{
+		"backend":  b.Name,
+		"volume":   vol.Config.InternalName,
+		"snapshot": snapshotName,
+	}).Debug("Attempting snapshot restore.")
+
+	if err := vol.CheckNotFailedOver("restore a snapshot of"); err != nil {
+		return err
+	}
+
+	return b.Driver.RestoreSnapshot(vol.Config.InternalName, snapshotName)
+}
+
+// GetVolumeReplication returns the state of a volume's replication
+// relationship, or nil if the volume isn't replicated.
+func (b *Backend) GetVolumeReplication(vol *Volume) (*ReplicationStatus, error) {
+	return b.Driver.GetReplicationStatus(vol.Config.InternalName)
+}
+
+// FailoverVolume breaks a volume's replication relationship so that its
+// replica may be used in its place, and returns the access information that
+// reaches the replica.  The volume itself is left alone; the caller records
+// the new access information and marks the volume as failed over, which
+// rules out the operations that would reach the original volume through the
+// driver.
+func (b *Backend) FailoverVolume(vol *Volume) (*VolumeAccessInfo, error) {
+
+	log.WithFields(log.Fields{
+		"backend": b.Name,
+		"volume":  vol.Config.InternalName,
+	}).Debug("Attempting volume failover.")
+
+	volConfig := *vol.Config
+	if err := b.Driver.Failover(&volConfig); err != nil {
+		return nil, err
+	}
+	return &volConfig.AccessInfo, nil
+}
+
 // Terminate informs the backend that it is being deleted from the core
 // and will not be called again.  This may be a signal to the storage
 // driver to clean up and stop any ongoing operations.
@@ -248,6 +459,7 @@ type BackendExternal struct {
 	Config  interface{}              `json:"config"`
 	Storage map[string]*PoolExternal `json:"storage"`
 	Online  bool                     `json:"online"`
+	State   BackendState             `json:"state"`
 	Volumes []string                 `json:"volumes"`
 }
 
@@ -257,6 +469,7 @@ func (b *Backend) ConstructExternal() *BackendExternal {
 		Config:  b.Driver.GetExternalConfig(),
 		Storage: make(map[string]*PoolExternal),
 		Online:  b.Online,
+		State:   b.State,
 		Volumes: make([]string, 0),
 	}
 
@@ -269,6 +482,19 @@ func (b *Backend) ConstructExternal() *BackendExternal {
 	return &backendExternal
 }
 
+// BackendUpdateExternal describes the effect of replacing the configuration
+// of an existing backend.  ConfigChanges lists the names of the settings that
+// differ, StorageClasses lists the storage classes the updated backend
+// satisfies, and OrphanedVolumes lists the volumes the updated backend can no
+// longer find.  If DryRun is set, the update was evaluated but not applied.
+type BackendUpdateExternal struct {
+	Backend         *BackendExternal `json:"backend"`
+	ConfigChanges   []string         `json:"configChanges"`
+	StorageClasses  []string         `json:"storageClasses"`
+	OrphanedVolumes []string         `json:"orphanedVolumes"`
+	DryRun          bool             `json:"dryRun"`
+}
+
 // Used to store the requisite info for a backend in etcd.  Other than
 // the configuration, all other data will be reconstructed during the bootstrap
 // phase
@@ -285,6 +511,7 @@ type BackendPersistent struct {
 	Config  PersistentStorageBackendConfig `json:"config"`
 	Name    string                         `json:"name"`
 	Online  bool                           `json:"online"`
+	State   BackendState                   `json:"state"`
 }
 
 func (b *Backend) ConstructPersistent() *BackendPersistent {
@@ -293,6 +520,7 @@ func (b *Backend) ConstructPersistent() *BackendPersistent {
 		Config:  PersistentStorageBackendConfig{},
 		Name:    b.Name,
 		Online:  b.Online,
+		State:   b.State,
 	}
 	b.Driver.StoreConfig(&persistentBackend.Config)
 	return persistentBackend
@@ -324,3 +552,105 @@ func (p *BackendPersistent) MarshalConfig() (string, error) {
 	}
 	return string(bytes), err
 }
+
+// ConstructExternal returns an external representation of a backend as it was
+// persisted, for use where the backend's driver isn't running.  Its config
+// includes only the settings common to all drivers, and it has no storage
+// pools or volumes.
+func (p *BackendPersistent) ConstructExternal() *BackendExternal {
+	backendExternal := &BackendExternal{
+		Name:    p.Name,
+		Storage: make(map[string]*PoolExternal),
+		Online:  p.Online,
+		State:   p.State,
+		Volumes: make([]string, 0),
+	}
+	if commonConfig := p.Config.commonConfig(); commonConfig != nil {
+		backendExternal.Config = drivers.GetCommonStorageDriverConfigExternal(commonConfig)
+	}
+	return backendExternal
+}
+
+// commonConfig returns the settings common to all drivers, or nil if no
+// recognized config is present.
+func (c *PersistentStorageBackendConfig) commonConfig() *drivers.CommonStorageDriverConfig {
+	switch {
+	case c.OntapConfig != nil:
+		return c.OntapConfig.CommonStorageDriverConfig
+	case c.SolidfireConfig != nil:
+		return c.SolidfireConfig.CommonStorageDriverConfig
+	case c.EseriesConfig != nil:
+		return c.EseriesConfig.CommonStorageDriverConfig
+	case c.FakeStorageDriverConfig != nil:
+		return c.FakeStorageDriverConfig.CommonStorageDriverConfig
+	default:
+		return nil
+	}
+}
+
+// ChangedConfigFields returns the sorted names of the settings that differ
+// between two backend configurations.  Nested settings are named with dotted
+// paths (e.g. "defaults.spaceReserve").  Only the names are returned, since
+// the values may include credentials.
+func ChangedConfigFields(
+	oldConfig, newConfig *PersistentStorageBackendConfig,
+) ([]string, error) {
+
+	oldFields, err := flattenConfig(oldConfig)
+	if err != nil {
+		return nil, err
+	}
+	newFields, err := flattenConfig(newConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := make([]string, 0)
+	for name, oldValue := range oldFields {
+		if newValue, ok := newFields[name]; !ok || !reflect.DeepEqual(oldValue, newValue) {
+			changed = append(changed, name)
+		}
+	}
+	for name := range newFields {
+		if _, ok := oldFields[name]; !ok {
+			changed = append(changed, name)
+		}
+	}
+	sort.Strings(changed)
+	return changed, nil
+}
+
+// flattenConfig converts a backend configuration to a map of setting names to
+// values, using the same names as the JSON configuration files.
+func flattenConfig(c *PersistentStorageBackendConfig) (map[string]interface{}, error) {
+
+	bytes, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	var tree map[string]interface{}
+	if err = json.Unmarshal(bytes, &tree); err != nil {
+		return nil, err
+	}
+
+	// Only one driver config is ever set, so its key is left out of the names.
+	fields := make(map[string]interface{})
+	for _, driverConfig := range tree {
+		flattenConfigValue("", driverConfig, fields)
+	}
+	return fields, nil
+}
+
+func flattenConfigValue(prefix string, value interface{}, fields map[string]interface{}) {
+	if values, ok := value.(map[string]interface{}); ok {
+		for key, v := range values {
+			name := key
+			if prefix != "" {
+				name = prefix + "." + key
+			}
+			flattenConfigValue(name, v, fields)
+		}
+		return
+	}
+	fields[prefix] = value
+}
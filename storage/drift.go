@@ -0,0 +1,45 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package storage
+
+// DriftType describes how a volume on a backend differs from Trident's record
+// of it.
+type DriftType string
+
+const (
+	// DriftMissingVolume volumes are known to Trident but no longer exist on
+	// their backend, typically because they were deleted out of band.
+	DriftMissingVolume = DriftType("missingVolume")
+	// DriftSizeMismatch volumes are a different size on their backend than
+	// Trident recorded, typically because they were resized out of band.
+	DriftSizeMismatch = DriftType("sizeMismatch")
+	// DriftUnknownVolume volumes exist on a backend with Trident's storage
+	// prefix, but aren't known to Trident.
+	DriftUnknownVolume = DriftType("unknownVolume")
+	// DriftOrphanedVolumePresent volumes are marked as orphaned, yet exist on
+	// their backend.
+	DriftOrphanedVolumePresent = DriftType("orphanedVolumePresent")
+)
+
+// VolumeDrift is a single difference between a backend and Trident's record
+// of the volumes on it.  Volume is empty for volumes unknown to Trident.
+type VolumeDrift struct {
+	Type         DriftType `json:"type"`
+	Backend      string    `json:"backend"`
+	Volume       string    `json:"volume,omitempty"`
+	InternalName string    `json:"internalName"`
+	RecordedSize string    `json:"recordedSize,omitempty"`
+	ActualSize   string    `json:"actualSize,omitempty"`
+	Repaired     bool      `json:"repaired"`
+}
+
+// ReconcileReport describes the differences found between the backends and
+// Trident's records by a single reconciliation.  Backends that couldn't be
+// checked are listed in BackendErrors.
+type ReconcileReport struct {
+	Started       string            `json:"started"`
+	Finished      string            `json:"finished"`
+	Repair        bool              `json:"repair"`
+	Drifts        []VolumeDrift     `json:"drifts"`
+	BackendErrors map[string]string `json:"backendErrors,omitempty"`
+}
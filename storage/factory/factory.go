@@ -55,8 +55,12 @@ func NewStorageBackendForConfig(configJSON string) (sb *storage.Backend, err err
 		storageDriver = &ontap.NASStorageDriver{}
 	case drivers.OntapNASQtreeStorageDriverName:
 		storageDriver = &ontap.NASQtreeStorageDriver{}
+	case drivers.OntapNASFlexGroupStorageDriverName:
+		storageDriver = &ontap.NASFlexGroupStorageDriver{}
 	case drivers.OntapSANStorageDriverName:
 		storageDriver = &ontap.SANStorageDriver{}
+	case drivers.OntapSANEconomyStorageDriverName:
+		storageDriver = &ontap.SANEconomyStorageDriver{}
 	case drivers.SolidfireSANStorageDriverName:
 		storageDriver = &solidfire.SANStorageDriver{}
 	case drivers.EseriesIscsiStorageDriverName:
@@ -84,10 +88,14 @@ func NewStorageBackendForConfig(configJSON string) (sb *storage.Backend, err err
 	case drivers.OntapNASQtreeStorageDriverName:
 		break
 
-	case drivers.OntapSANStorageDriverName:
-		driver := storageDriver.(*ontap.SANStorageDriver)
+	case drivers.OntapNASFlexGroupStorageDriverName:
+		break
+
+	case drivers.OntapSANStorageDriverName, drivers.OntapSANEconomyStorageDriverName:
+		driver := storageDriver.(ontap.StorageDriver)
+		ontapConfig := driver.GetConfig()
 
-		iGroupResponse, err := driver.API.IgroupList()
+		iGroupResponse, err := driver.GetAPI().IgroupList()
 		if err = ontapi.GetError(iGroupResponse, err); err != nil {
 			return nil, err
 		}
@@ -95,8 +103,8 @@ func NewStorageBackendForConfig(configJSON string) (sb *storage.Backend, err err
 		found := false
 		initiators := ""
 		for _, igroupInfo := range iGroupResponse.Result.AttributesList() {
-			if igroupInfo.Vserver() == driver.Config.SVM &&
-				igroupInfo.InitiatorGroupName() == driver.Config.IgroupName {
+			if igroupInfo.Vserver() == ontapConfig.SVM &&
+				igroupInfo.InitiatorGroupName() == ontapConfig.IgroupName {
 				found = true
 				initiatorList := igroupInfo.Initiators()
 				for _, initiator := range initiatorList {
@@ -108,12 +116,12 @@ func NewStorageBackendForConfig(configJSON string) (sb *storage.Backend, err err
 		}
 		if !found {
 			return nil, fmt.Errorf("initiator group %v doesn't exist for SVM %v and needs to be manually created"+
-				"; please also ensure all relevant hosts are added to the igroup", driver.Config.IgroupName, driver.Config.SVM)
+				"; please also ensure all relevant hosts are added to the igroup", ontapConfig.IgroupName, ontapConfig.SVM)
 		} else {
 			log.WithFields(log.Fields{
-				"driver":     drivers.OntapSANStorageDriverName,
-				"SVM":        driver.Config.SVM,
-				"igroup":     driver.Config.IgroupName,
+				"driver":     commonConfig.StorageDriverName,
+				"SVM":        ontapConfig.SVM,
+				"igroup":     ontapConfig.IgroupName,
 				"initiators": initiators,
 			}).Warn("Please ensure all relevant hosts are added to the initiator group.")
 		}
@@ -1,7 +1,9 @@
 package fake
 
 type Volume struct {
-	Name      string
-	PoolName  string
-	SizeBytes uint64
+	Name       string
+	PoolName   string
+	SizeBytes  uint64
+	Replicated bool
+	FailedOver bool
 }
@@ -0,0 +1,39 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package storage
+
+// OrphanedVolume is an object on a backend that carries Trident's storage
+// prefix, but that Trident has no record of, typically because a failed
+// volume creation couldn't be rolled back.  FirstSeen is when Trident first
+// found the object without a record.
+type OrphanedVolume struct {
+	Backend      string `json:"backend"`
+	InternalName string `json:"internalName"`
+	Size         string `json:"size,omitempty"`
+	FirstSeen    string `json:"firstSeen"`
+	Policy       string `json:"policy"`
+}
+
+// OrphanAction is what Trident did about an orphaned volume.
+type OrphanAction string
+
+const (
+	// OrphanDetected volumes were found without a record for the first time.
+	OrphanDetected = OrphanAction("detected")
+	// OrphanDeleted volumes were deleted from their backend, as their
+	// backend's orphan policy asks once the grace period has passed.
+	OrphanDeleted = OrphanAction("deleted")
+	// OrphanDeleteFailed volumes couldn't be deleted from their backend, and
+	// will be tried again.
+	OrphanDeleteFailed = OrphanAction("deleteFailed")
+)
+
+// OrphanAuditRecord records an action Trident took about an orphaned volume.
+type OrphanAuditRecord struct {
+	Time         string       `json:"time"`
+	Action       OrphanAction `json:"action"`
+	Backend      string       `json:"backend"`
+	InternalName string       `json:"internalName"`
+	Size         string       `json:"size,omitempty"`
+	Error        string       `json:"error,omitempty"`
+}
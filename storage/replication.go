@@ -0,0 +1,16 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package storage
+
+// ReplicationStatus describes the relationship that replicates a volume to
+// a peer storage system for disaster recovery.
+type ReplicationStatus struct {
+	Source          string `json:"source"`
+	Destination     string `json:"destination"`
+	State           string `json:"state"`  // e.g. "snapmirrored", or "broken-off" after a failover
+	Status          string `json:"status"` // e.g. "idle" or "transferring"
+	Schedule        string `json:"schedule,omitempty"`
+	Healthy         bool   `json:"healthy"`
+	UnhealthyReason string `json:"unhealthyReason,omitempty"`
+	LagTime         int    `json:"lagTime"` // seconds since the replica was last brought up to date
+}
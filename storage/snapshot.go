@@ -15,3 +15,28 @@ type SnapshotExternal struct {
 func (s *Snapshot) ConstructExternal() *SnapshotExternal {
 	return &SnapshotExternal{*s}
 }
+
+// SnapshotPersistent is the record of a Trident-managed snapshot that is
+// saved in the persistent store.  Volume is the Trident name of the volume
+// the snapshot belongs to.
+type SnapshotPersistent struct {
+	Snapshot
+	Volume string
+}
+
+func (s *Snapshot) ConstructPersistent(volumeName string) *SnapshotPersistent {
+	return &SnapshotPersistent{
+		Snapshot: *s,
+		Volume:   volumeName,
+	}
+}
+
+// ID returns the key under which the snapshot is stored, which is unique
+// across all volumes.
+func (s *SnapshotPersistent) ID() string {
+	return MakeSnapshotID(s.Volume, s.Name)
+}
+
+func MakeSnapshotID(volumeName, snapshotName string) string {
+	return volumeName + "/" + snapshotName
+}
@@ -15,6 +15,16 @@ type Pool struct {
 	StorageClasses []string
 	Backend        *Backend
 	Attributes     map[string]sa.Offer
+	// Capacity is the space the backend last reported for the pool, or nil
+	// if the backend doesn't report it.
+	Capacity *PoolCapacity
+}
+
+// PoolCapacity describes the space in a storage pool.  TotalBytes is zero if
+// the backend only reports the space that remains.
+type PoolCapacity struct {
+	TotalBytes     uint64 `json:"totalBytes,omitempty"`
+	AvailableBytes uint64 `json:"availableBytes"`
 }
 
 func NewStoragePool(backend *Backend, name string) *Pool {
@@ -45,11 +55,43 @@ func (pool *Pool) RemoveStorageClass(class string) bool {
 	return found
 }
 
+// HasSpaceFor reports whether the pool has room for a volume of the given
+// size.  Pools that don't report their capacity are assumed to have
+// room.
+func (pool *Pool) HasSpaceFor(sizeBytes uint64) bool {
+	return pool.Capacity == nil || pool.Capacity.AvailableBytes >= sizeBytes
+}
+
+// ConsumeCapacity deducts a newly placed volume from the pool's available
+// space so that placement decisions remain reasonable between refreshes.
+func (pool *Pool) ConsumeCapacity(sizeBytes uint64) {
+	if pool.Capacity == nil {
+		return
+	}
+	if pool.Capacity.AvailableBytes < sizeBytes {
+		pool.Capacity.AvailableBytes = 0
+	} else {
+		pool.Capacity.AvailableBytes -= sizeBytes
+	}
+}
+
+// VolumeCount returns the number of Trident volumes placed in the pool.
+func (pool *Pool) VolumeCount() int {
+	count := 0
+	for _, vol := range pool.Backend.Volumes {
+		if vol.Pool == pool.Name {
+			count++
+		}
+	}
+	return count
+}
+
 type PoolExternal struct {
 	Name           string   `json:"name"`
 	StorageClasses []string `json:"storageClasses"`
 	//TODO: can't have an interface here for unmarshalling
 	Attributes map[string]sa.Offer `json:"storageAttributes"`
+	Capacity   *PoolCapacity       `json:"capacity,omitempty"`
 }
 
 func (pool *Pool) ConstructExternal() *PoolExternal {
@@ -61,6 +103,10 @@ func (pool *Pool) ConstructExternal() *PoolExternal {
 	for k, v := range pool.Attributes {
 		external.Attributes[k] = v
 	}
+	if pool.Capacity != nil {
+		capacity := *pool.Capacity
+		external.Capacity = &capacity
+	}
 
 	// We want to sort these so that the output remains consistent;
 	// there are cases where the order won't always be the same.
@@ -33,8 +33,10 @@ type VolumeConfig struct {
 	CloneSourceVolumeInternal string            `json:"cloneSourceVolumeInternal"`
 	CloneSourceSnapshot       string            `json:"cloneSourceSnapshot"`
 	SplitOnClone              string            `json:"splitOnClone"`
+	ImportOriginalName        string            `json:"importOriginalName,omitempty"`
 	QoS                       string            `json:"qos,omitempty"`
 	QoSType                   string            `json:"type,omitempty"`
+	FailedOver                bool              `json:"failedOver,omitempty"`
 }
 
 type VolumeAccessInfo struct {
@@ -103,6 +105,16 @@ type VolumeExternal struct {
 	Orphaned bool   `json:"orphaned"`
 }
 
+// CheckNotFailedOver returns an error if the volume was failed over to its
+// replica.  The backend only manages the original volume, so the replica may
+// only be reached through the volume's access information.
+func (v *Volume) CheckNotFailedOver(operation string) error {
+	if v.Config.FailedOver {
+		return fmt.Errorf("cannot %s volume %s, as it was failed over to its replica", operation, v.Config.Name)
+	}
+	return nil
+}
+
 func (v *Volume) ConstructExternal() *VolumeExternal {
 	return &VolumeExternal{
 		Config:   v.Config,
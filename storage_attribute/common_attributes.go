@@ -2,14 +2,17 @@
 
 package storageattribute
 
+import "strings"
+
 const (
 	// Constants for integer storage category attributes
 	IOPS = "IOPS"
 
 	// Constants for boolean storage category attributes
-	Snapshots  = "snapshots"
-	Clones     = "clones"
-	Encryption = "encryption"
+	Snapshots   = "snapshots"
+	Clones      = "clones"
+	Encryption  = "encryption"
+	Replication = "replication"
 
 	// Constants for string list attributes
 	ProvisioningType = "provisioningType"
@@ -27,9 +30,15 @@ const (
 	SSD    = "ssd"
 	Hybrid = "hybrid"
 
+	// LabelPrefix starts the names of the string attributes that match the
+	// labels given to storage pools in their backend's config, so that
+	// "label.tier" matches a pool's "tier" label.
+	LabelPrefix = "label."
+
 	RequiredStorage        = "requiredStorage" // deprecated, use additionalStoragePools
 	StoragePools           = "storagePools"
 	AdditionalStoragePools = "additionalStoragePools"
+	PoolSelectionPolicy    = "poolSelectionPolicy"
 )
 
 var attrTypes = map[string]Type{
@@ -37,6 +46,7 @@ var attrTypes = map[string]Type{
 	Snapshots:        boolType,
 	Clones:           boolType,
 	Encryption:       boolType,
+	Replication:      boolType,
 	ProvisioningType: stringType,
 	BackendType:      stringType,
 	Media:            stringType,
@@ -45,3 +55,23 @@ var attrTypes = map[string]Type{
 	TestingAttribute: boolType,
 	NonexistentBool:  boolType,
 }
+
+// attrValues lists every value that string attributes with a fixed set of
+// values may have, so that requests that can never match are rejected.
+var attrValues = map[string][]string{
+	ProvisioningType: {"thick", "thin"},
+	Media:            {HDD, Hybrid, SSD},
+}
+
+// LabelAttribute returns the name of the attribute that matches a label.
+func LabelAttribute(label string) string {
+	return LabelPrefix + label
+}
+
+func getAttributeType(name string) (Type, bool) {
+	if strings.HasPrefix(name, LabelPrefix) && len(name) > len(LabelPrefix) {
+		return stringType, true
+	}
+	attrType, ok := attrTypes[name]
+	return attrType, ok
+}
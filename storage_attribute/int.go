@@ -6,6 +6,11 @@ import (
 	"fmt"
 )
 
+const (
+	maxInt = int(^uint(0) >> 1)
+	minInt = -maxInt - 1
+)
+
 func NewIntOffer(min, max int) Offer {
 	return &intOffer{
 		Min: min,
@@ -13,18 +18,47 @@ func NewIntOffer(min, max int) Offer {
 	}
 }
 
+// Matches is true for a request for a value in the offered range, a request
+// for a range that overlaps the offered range, and a request to avoid a value
+// unless that value is all that is offered.
 func (o *intOffer) Matches(r Request) bool {
-	ir, ok := r.(*intRequest)
-	if !ok {
+	switch ir := r.(type) {
+	case *intRequest:
+		return ir.Request >= o.Min && ir.Request <= o.Max
+	case *intRangeRequest:
+		return ir.Min <= o.Max && ir.Max >= o.Min
+	case *intNotRequest:
+		return o.Min <= o.Max && (o.Min != ir.Request || o.Max != ir.Request)
+	default:
 		return false
 	}
-	return ir.Request >= o.Min && ir.Request <= o.Max
 }
 
 func (o *intOffer) String() string {
 	return fmt.Sprintf("{Min: %d, Max: %d}", o.Min, o.Max)
 }
 
+// IntRequestLimits returns the bounds of an int request.  The value of an
+// exact request is both its floor and its ceiling, while a range request has
+// only the bounds it was requested with; a bound that doesn't apply is zero.
+// The result is false for requests that aren't bounded, such as "!= 0".
+func IntRequestLimits(r Request) (floor, ceiling int, ok bool) {
+	switch ir := r.(type) {
+	case *intRequest:
+		return ir.Request, ir.Request, true
+	case *intRangeRequest:
+		if ir.Min != minInt {
+			floor = ir.Min
+		}
+		if ir.Max != maxInt {
+			ceiling = ir.Max
+		}
+		return floor, ceiling, true
+	default:
+		return 0, 0, false
+	}
+}
+
 func NewIntRequest(request int) Request {
 	return &intRequest{
 		Request: request,
@@ -42,3 +76,66 @@ func (r *intRequest) GetType() Type {
 func (r *intRequest) String() string {
 	return fmt.Sprintf("%d", r.Request)
 }
+
+// NewIntMinRequest asks for a value of at least min.
+func NewIntMinRequest(min int) Request {
+	return &intRangeRequest{
+		Min: min,
+		Max: maxInt,
+	}
+}
+
+// NewIntMaxRequest asks for a value of at most max.
+func NewIntMaxRequest(max int) Request {
+	return &intRangeRequest{
+		Min: minInt,
+		Max: max,
+	}
+}
+
+// NewIntRangeRequest asks for a value from min to max, inclusive.
+func NewIntRangeRequest(min, max int) Request {
+	return &intRangeRequest{
+		Min: min,
+		Max: max,
+	}
+}
+
+func (r *intRangeRequest) Value() interface{} {
+	return nil
+}
+
+func (r *intRangeRequest) GetType() Type {
+	return intType
+}
+
+// String returns the range in the form it is requested in.
+func (r *intRangeRequest) String() string {
+	switch {
+	case r.Max == maxInt:
+		return fmt.Sprintf("%s %d", opGreaterOrEqual, r.Min)
+	case r.Min == minInt:
+		return fmt.Sprintf("%s %d", opLessOrEqual, r.Max)
+	default:
+		return fmt.Sprintf("%d%s%d", r.Min, rangeSeparator, r.Max)
+	}
+}
+
+// NewIntNotRequest asks for any value other than request.
+func NewIntNotRequest(request int) Request {
+	return &intNotRequest{
+		Request: request,
+	}
+}
+
+func (r *intNotRequest) Value() interface{} {
+	return nil
+}
+
+func (r *intNotRequest) GetType() Type {
+	return intType
+}
+
+func (r *intNotRequest) String() string {
+	return fmt.Sprintf("%s %d", opNotEqual, r.Request)
+}
@@ -21,7 +21,7 @@ func UnmarshalOfferMap(mapJSON json.RawMessage) (
 		var (
 			final Offer
 		)
-		baseType, ok := attrTypes[name]
+		baseType, ok := getAttributeType(name)
 		if !ok {
 			return nil, fmt.Errorf("unknown storage attribute: %s", name)
 		}
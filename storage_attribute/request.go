@@ -5,6 +5,7 @@ package storageattribute
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -42,33 +43,218 @@ func MarshalRequestMap(requestMap map[string]Request) ([]byte, error) {
 	return json.Marshal(genericMap)
 }
 
+// Operators that may start a storage attribute request.  A request without
+// one asks for the value as given.
+const (
+	opEqual          = "="
+	opNotEqual       = "!="
+	opGreater        = ">"
+	opGreaterOrEqual = ">="
+	opLess           = "<"
+	opLessOrEqual    = "<="
+	opIn             = "in"
+	opNotIn          = "notin"
+)
+
+// rangeSeparator separates the bounds of an integer range, as in "1000-5000".
+const rangeSeparator = "-"
+
+// Longer operators come first, so that ">=" isn't taken for ">".
+var (
+	symbolOperators = []string{opGreaterOrEqual, opLessOrEqual, opNotEqual, opGreater, opLess, opEqual}
+	setOperators    = []string{opNotIn, opIn}
+)
+
+// parseExpression splits a request such as ">= 5000" or "in [ssd, hybrid]"
+// into its operator and operand.
+func parseExpression(val string) (string, string) {
+	val = strings.TrimSpace(val)
+	for _, op := range symbolOperators {
+		if strings.HasPrefix(val, op) {
+			return op, strings.TrimSpace(val[len(op):])
+		}
+	}
+	for _, op := range setOperators {
+		if strings.HasPrefix(val, op) {
+			operand := strings.TrimSpace(val[len(op):])
+			if strings.HasPrefix(operand, "[") {
+				return op, operand
+			}
+		}
+	}
+	return opEqual, val
+}
+
+// parseSet parses a set of values in the form "[a, b]".
+func parseSet(operand string) ([]string, error) {
+	if !strings.HasPrefix(operand, "[") || !strings.HasSuffix(operand, "]") {
+		return nil, fmt.Errorf("set (%s) must be enclosed in brackets", operand)
+	}
+	inner := strings.TrimSpace(operand[1 : len(operand)-1])
+	if inner == "" {
+		return []string{}, nil
+	}
+	values := strings.Split(inner, ",")
+	for i, value := range values {
+		values[i] = strings.TrimSpace(value)
+		if values[i] == "" {
+			return nil, fmt.Errorf("set (%s) has an empty value", operand)
+		}
+	}
+	return values, nil
+}
+
+// CreateAttributeRequestFromAttributeValue parses a request for a storage
+// attribute.  Besides a plain value, integer attributes accept "!=", ">",
+// ">=", "<" and "<=", as in ">= 5000", and ranges such as "1000-5000";
+// string attributes accept "!=", and sets such as "in [ssd, hybrid]" or
+// "notin [hdd]"; and boolean attributes accept "!=".  Requests that can never match are rejected.
 func CreateAttributeRequestFromAttributeValue(name, val string) (Request, error) {
 	var req Request
-	valType, ok := attrTypes[name]
+	valType, ok := getAttributeType(name)
 	if !ok {
 		return nil, fmt.Errorf("unrecognized storage attribute: %s", name)
 	}
+	op, operand := parseExpression(val)
+	unsupported := fmt.Errorf("operator %s isn't supported for the %s storage attribute %s",
+		op, valType, name)
 	switch valType {
 	case boolType:
-		v, err := strconv.ParseBool(val)
+		v, err := strconv.ParseBool(operand)
 		if err != nil {
 			return nil, fmt.Errorf("storage attribute value (%s) doesn't match the specified type (%s)", val, valType)
 		}
-		req = NewBoolRequest(v)
+		switch op {
+		case opEqual:
+			req = NewBoolRequest(v)
+		case opNotEqual:
+			req = NewBoolRequest(!v)
+		default:
+			return nil, unsupported
+		}
 	case intType:
-		v, err := strconv.ParseInt(val, 10, 0)
+		if op == opIn || op == opNotIn {
+			return nil, unsupported
+		}
+		if i := strings.Index(operand, rangeSeparator); op == opEqual && i > 0 {
+			min, minErr := strconv.ParseInt(strings.TrimSpace(operand[:i]), 10, 0)
+			max, maxErr := strconv.ParseInt(strings.TrimSpace(operand[i+1:]), 10, 0)
+			if minErr != nil || maxErr != nil {
+				return nil, fmt.Errorf("storage attribute value (%s) doesn't match the specified type (%s)", val, valType)
+			}
+			req = NewIntRangeRequest(int(min), int(max))
+			break
+		}
+		parsed, err := strconv.ParseInt(operand, 10, 0)
 		if err != nil {
 			return nil, fmt.Errorf("storage attribute value (%s) doesn't match the specified type (%s)", val, valType)
 		}
-		req = NewIntRequest(int(v))
+		v := int(parsed)
+		switch op {
+		case opEqual:
+			req = NewIntRequest(v)
+		case opNotEqual:
+			req = NewIntNotRequest(v)
+		case opGreaterOrEqual:
+			req = NewIntMinRequest(v)
+		case opLessOrEqual:
+			req = NewIntMaxRequest(v)
+		case opGreater:
+			if v == maxInt {
+				return nil, fmt.Errorf("storage attribute request %s %s can never match", name, val)
+			}
+			req = NewIntMinRequest(v + 1)
+		case opLess:
+			if v == minInt {
+				return nil, fmt.Errorf("storage attribute request %s %s can never match", name, val)
+			}
+			req = NewIntMaxRequest(v - 1)
+		}
 	case stringType:
-		req = NewStringRequest(val)
+		switch op {
+		case opEqual:
+			req = NewStringRequest(operand)
+		case opNotEqual:
+			req = NewStringNotRequest(operand)
+		case opIn, opNotIn:
+			values, err := parseSet(operand)
+			if err != nil {
+				return nil, fmt.Errorf("storage attribute %s: %v", name, err)
+			}
+			if op == opIn {
+				req = NewStringSetRequest(values...)
+			} else {
+				req = NewStringNotRequest(values...)
+			}
+		default:
+			return nil, unsupported
+		}
 	default:
 		return nil, fmt.Errorf("unrecognized type for a storage attribute request: %s", valType)
 	}
+	if err := ValidateRequest(name, req); err != nil {
+		return nil, err
+	}
 	return req, nil
 }
 
+// ValidateRequest checks that a request is of the type its storage attribute
+// takes, and that some storage pool could match it.  Integer attributes are
+// never negative, and some string attributes only have a fixed set of values.
+func ValidateRequest(name string, r Request) error {
+	valType, ok := getAttributeType(name)
+	if !ok {
+		return fmt.Errorf("unrecognized storage attribute: %s", name)
+	}
+	if r.GetType() != valType {
+		return fmt.Errorf("storage attribute %s takes a %s value, not a %s value (%s)",
+			name, valType, r.GetType(), r)
+	}
+	neverMatches := fmt.Errorf("storage attribute request %s %s can never match", name, r)
+	switch req := r.(type) {
+	case *intRequest:
+		if req.Request < 0 {
+			return fmt.Errorf("%v, as %s is never negative", neverMatches, name)
+		}
+	case *intRangeRequest:
+		if req.Max < req.Min {
+			return neverMatches
+		}
+		if req.Max < 0 {
+			return fmt.Errorf("%v, as %s is never negative", neverMatches, name)
+		}
+	case *stringSetRequest:
+		if len(req.Requests) == 0 && !req.Exclude {
+			return fmt.Errorf("%v, as the set is empty", neverMatches)
+		}
+	}
+	if values, ok := attrValues[name]; ok {
+		for _, value := range values {
+			if acceptsString(r, value) {
+				return nil
+			}
+		}
+		return fmt.Errorf("%v, as %s is always one of %s", neverMatches, name,
+			strings.Join(values, ", "))
+	}
+	return nil
+}
+
+// ValidateRequests validates each request in a map, as ValidateRequest does.
+func ValidateRequests(requests map[string]Request) error {
+	names := make([]string, 0, len(requests))
+	for name := range requests {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := ValidateRequest(name, requests[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func CreateBackendStoragePoolsMapFromEncodedString(
 	arg string,
 ) (map[string][]string, error) {
@@ -30,6 +30,21 @@ func TestMatches(t *testing.T) {
 		{NewIntRequest(5), NewStringOffer("foo", "bar"), false},
 		{NewIntRequest(5), NewBoolOffer(true), false},
 		{NewBoolRequest(false), NewIntOffer(0, 10), false},
+		{NewIntMinRequest(5000), NewIntOffer(1000, 6000), true},
+		{NewIntMinRequest(5000), NewIntOffer(1000, 4999), false},
+		{NewIntMaxRequest(1000), NewIntOffer(1000, 6000), true},
+		{NewIntMaxRequest(999), NewIntOffer(1000, 6000), false},
+		{NewIntRangeRequest(1000, 5000), NewIntOffer(4000, 8000), true},
+		{NewIntRangeRequest(1000, 5000), NewIntOffer(6000, 8000), false},
+		{NewIntNotRequest(5), NewIntOffer(5, 5), false},
+		{NewIntNotRequest(5), NewIntOffer(5, 6), true},
+		{NewStringSetRequest("ssd", "hybrid"), NewStringOffer("hybrid"), true},
+		{NewStringSetRequest("ssd", "hybrid"), NewStringOffer("hdd"), false},
+		{NewStringNotRequest("eseries-iscsi"), NewStringOffer("eseries-iscsi"), false},
+		{NewStringNotRequest("eseries-iscsi"), NewStringOffer("ontap-nas"), true},
+		{NewStringNotRequest("thick"), NewStringOffer("thick", "thin"), true},
+		{NewIntMinRequest(5), NewStringOffer("foo"), false},
+		{NewStringSetRequest("foo"), NewIntOffer(0, 10), false},
 	} {
 		if test.o.Matches(test.r) != test.expected {
 			t.Errorf("Test case %d failed", i)
@@ -81,6 +96,17 @@ func TestUnmarshalRequest(t *testing.T) {
 		BackendType: &stringRequest{
 			Request: "foo",
 		},
+		Media: &stringSetRequest{
+			Requests: []string{SSD, Hybrid},
+		},
+		ProvisioningType: &stringSetRequest{
+			Requests: []string{"thick"},
+			Exclude:  true,
+		},
+		LabelAttribute("tier"): &stringSetRequest{
+			Requests: []string{"gold", "silver"},
+			Exclude:  true,
+		},
 	}
 	//data, err := json.Marshal(requestMap)
 	data, err := MarshalRequestMap(requestMap)
@@ -97,3 +123,124 @@ func TestUnmarshalRequest(t *testing.T) {
 			targetRequestMap)
 	}
 }
+
+func TestUnmarshalRangeRequest(t *testing.T) {
+	for _, request := range []Request{
+		NewIntMinRequest(5000),
+		NewIntMaxRequest(100),
+		NewIntRangeRequest(1000, 5000),
+		NewIntNotRequest(0),
+	} {
+		data, err := MarshalRequestMap(map[string]Request{IOPS: request})
+		if err != nil {
+			t.Fatal("Unable to marshal:  ", err)
+		}
+		targetRequestMap, err := UnmarshalRequestMap(data)
+		if err != nil {
+			t.Fatal("Unable to unmarshal: ", err)
+		}
+		if !reflect.DeepEqual(request, targetRequestMap[IOPS]) {
+			t.Errorf("Requests are unequal.\n Expected: %s\nGot: %s\n", request,
+				targetRequestMap[IOPS])
+		}
+	}
+}
+
+func TestCreateAttributeRequest(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		value    string
+		expected Request
+	}{
+		{IOPS, "5000", NewIntRequest(5000)},
+		{IOPS, ">= 5000", NewIntMinRequest(5000)},
+		{IOPS, ">5000", NewIntMinRequest(5001)},
+		{IOPS, "<= 5000", NewIntMaxRequest(5000)},
+		{IOPS, "< 5000", NewIntMaxRequest(4999)},
+		{IOPS, "!= 5000", NewIntNotRequest(5000)},
+		{IOPS, "= 5000", NewIntRequest(5000)},
+		{IOPS, "1000-5000", NewIntRangeRequest(1000, 5000)},
+		{IOPS, "1000 - 5000", NewIntRangeRequest(1000, 5000)},
+		{Media, "ssd", NewStringRequest(SSD)},
+		{Media, "in [ssd, hybrid]", NewStringSetRequest(SSD, Hybrid)},
+		{Media, "notin [hdd]", NewStringNotRequest(HDD)},
+		{BackendType, "!= eseries-iscsi", NewStringNotRequest("eseries-iscsi")},
+		{Snapshots, "!= false", NewBoolRequest(true)},
+		{LabelAttribute("tier"), "gold", NewStringRequest("gold")},
+		{LabelAttribute("zone"), "in [a,b]", NewStringSetRequest("a", "b")},
+		{LabelAttribute("owner"), "inventory", NewStringRequest("inventory")},
+	} {
+		request, err := CreateAttributeRequestFromAttributeValue(test.name, test.value)
+		if err != nil {
+			t.Errorf("%s: %s: unexpected error: %v", test.name, test.value, err)
+		} else if !reflect.DeepEqual(request, test.expected) {
+			t.Errorf("%s: %s: expected %v, got %v", test.name, test.value, test.expected, request)
+		}
+	}
+
+	for _, test := range []struct {
+		name  string
+		value string
+	}{
+		{"color", "blue"},
+		{LabelPrefix, "blue"},
+		{IOPS, "lots"},
+		{IOPS, "in [1, 2]"},
+		{IOPS, "< 0"},
+		{IOPS, "-1"},
+		{IOPS, "5000-1000"},
+		{IOPS, "1000-lots"},
+		{Snapshots, ">= true"},
+		{Media, ">= ssd"},
+		{Media, "flash"},
+		{Media, "in []"},
+		{Media, "in [ssd,]"},
+		{Media, "notin [hdd, hybrid, ssd]"},
+		{ProvisioningType, "in [thinner]"},
+	} {
+		if _, err := CreateAttributeRequestFromAttributeValue(test.name, test.value); err == nil {
+			t.Errorf("%s: %s: expected an error", test.name, test.value)
+		}
+	}
+}
+
+func TestResolveStringRequest(t *testing.T) {
+	for _, test := range []struct {
+		request  Request
+		expected string
+		resolved bool
+	}{
+		{NewStringRequest("thick"), "thick", true},
+		{NewStringNotRequest("thick"), "thin", true},
+		{NewStringSetRequest("thin"), "thin", true},
+		{NewStringSetRequest("thick", "thin"), "", false},
+	} {
+		value, ok := ResolveStringRequest(test.request, "thick", "thin")
+		if ok != test.resolved || (ok && value != test.expected) {
+			t.Errorf("%s: expected %s/%t, got %s/%t", test.request, test.expected, test.resolved,
+				value, ok)
+		}
+	}
+}
+
+func TestIntRequestLimits(t *testing.T) {
+	for _, test := range []struct {
+		request Request
+		floor   int
+		ceiling int
+		ok      bool
+	}{
+		{NewIntRequest(5000), 5000, 5000, true},
+		{NewIntMinRequest(1000), 1000, 0, true},
+		{NewIntMaxRequest(8000), 0, 8000, true},
+		{NewIntRangeRequest(1000, 5000), 1000, 5000, true},
+		{NewIntNotRequest(0), 0, 0, false},
+		{NewStringRequest("5000"), 0, 0, false},
+	} {
+		floor, ceiling, ok := IntRequestLimits(test.request)
+		if floor != test.floor || ceiling != test.ceiling || ok != test.ok {
+			t.Errorf("%s: expected %d/%d/%t, got %d/%d/%t", test.request, test.floor, test.ceiling,
+				test.ok, floor, ceiling, ok)
+		}
+	}
+}
@@ -13,13 +13,11 @@ func NewStringOffer(offers ...string) Offer {
 	}
 }
 
+// Matches is true if any of the offered values is one that the request
+// accepts.
 func (o *stringOffer) Matches(r Request) bool {
-	sr, ok := r.(*stringRequest)
-	if !ok {
-		return false
-	}
 	for _, s := range o.Offers {
-		if s == sr.Request {
+		if acceptsString(r, s) {
 			return true
 		}
 	}
@@ -30,6 +28,42 @@ func (o *stringOffer) String() string {
 	return fmt.Sprintf("{Offers: %s}", strings.Join(o.Offers, ","))
 }
 
+// acceptsString reports whether a string request accepts a value.
+func acceptsString(r Request, value string) bool {
+	switch sr := r.(type) {
+	case *stringRequest:
+		return sr.Request == value
+	case *stringSetRequest:
+		for _, request := range sr.Requests {
+			if request == value {
+				return !sr.Exclude
+			}
+		}
+		return sr.Exclude
+	default:
+		return false
+	}
+}
+
+// ResolveStringRequest returns the value a string request asks for.  If the
+// request accepts more than one value, such as "!= thick", the one value out
+// of the given choices that it accepts is returned instead; the result is
+// false if there isn't exactly one.
+func ResolveStringRequest(r Request, choices ...string) (string, bool) {
+	if value, ok := r.Value().(string); ok {
+		return value, true
+	}
+	resolved := ""
+	accepted := 0
+	for _, choice := range choices {
+		if acceptsString(r, choice) {
+			resolved = choice
+			accepted++
+		}
+	}
+	return resolved, accepted == 1
+}
+
 func NewStringRequest(request string) Request {
 	return &stringRequest{
 		Request: request,
@@ -47,3 +81,40 @@ func (r *stringRequest) GetType() Type {
 func (r *stringRequest) String() string {
 	return r.Request
 }
+
+// NewStringSetRequest asks for any of the given values.
+func NewStringSetRequest(requests ...string) Request {
+	return &stringSetRequest{
+		Requests: requests,
+	}
+}
+
+// NewStringNotRequest asks for any value other than the given ones.
+func NewStringNotRequest(requests ...string) Request {
+	return &stringSetRequest{
+		Requests: requests,
+		Exclude:  true,
+	}
+}
+
+func (r *stringSetRequest) Value() interface{} {
+	if len(r.Requests) == 1 && !r.Exclude {
+		return r.Requests[0]
+	}
+	return nil
+}
+
+func (r *stringSetRequest) GetType() Type {
+	return stringType
+}
+
+func (r *stringSetRequest) String() string {
+	switch {
+	case r.Exclude && len(r.Requests) == 1:
+		return fmt.Sprintf("%s %s", opNotEqual, r.Requests[0])
+	case r.Exclude:
+		return fmt.Sprintf("%s [%s]", opNotIn, strings.Join(r.Requests, ", "))
+	default:
+		return fmt.Sprintf("%s [%s]", opIn, strings.Join(r.Requests, ", "))
+	}
+}
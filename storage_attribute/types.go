@@ -6,8 +6,8 @@ type Offer interface {
 	Matches(requested Request) bool
 }
 
-// At the moment, there aren't any terribly useful methods to put here, but
-// there might be.  This is more here for symmetry at the moment.
+// Value returns the value a request asks for, or nil if the request accepts
+// more than one value, as a range or a negated value does.
 type Request interface {
 	GetType() Type
 	Value() interface{}
@@ -31,6 +31,17 @@ type intRequest struct {
 	Request int `json:"request"`
 }
 
+// intRangeRequest asks for any value from Min to Max, inclusive.
+type intRangeRequest struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
+
+// intNotRequest asks for any value other than Request.
+type intNotRequest struct {
+	Request int `json:"request"`
+}
+
 type boolOffer struct {
 	Offer bool `json:"offer"`
 }
@@ -46,3 +57,10 @@ type stringOffer struct {
 type stringRequest struct {
 	Request string `json:"request"`
 }
+
+// stringSetRequest asks for any of the values in Requests or, if Exclude is
+// set, any value that isn't in Requests.
+type stringSetRequest struct {
+	Requests []string `json:"requests"`
+	Exclude  bool     `json:"exclude"`
+}
@@ -17,6 +17,7 @@ func (c *Config) UnmarshalJSON(data []byte) error {
 		Pools           map[string][]string `json:"storagePools,omitempty"`
 		RequiredStorage map[string][]string `json:"requiredStorage,omitempty"`
 		AdditionalPools map[string][]string `json:"additionalStoragePools,omitempty"`
+		PoolSelection   PoolSelectionPolicy `json:"poolSelectionPolicy,omitempty"`
 	}
 	err := json.Unmarshal(data, &tmp)
 	if err != nil {
@@ -26,6 +27,7 @@ func (c *Config) UnmarshalJSON(data []byte) error {
 	c.Name = tmp.Name
 	c.Attributes, err = storageattribute.UnmarshalRequestMap(tmp.Attributes)
 	c.Pools = tmp.Pools
+	c.PoolSelection = tmp.PoolSelection
 
 	// Handle the renaming of "requiredStorage" to "additionalStoragePools"
 	if tmp.RequiredStorage != nil && tmp.AdditionalPools == nil {
@@ -45,11 +47,13 @@ func (c *Config) MarshalJSON() ([]byte, error) {
 		Attributes      json.RawMessage     `json:"attributes,omitempty"`
 		Pools           map[string][]string `json:"storagePools,omitempty"`
 		AdditionalPools map[string][]string `json:"additionalStoragePools,omitempty"`
+		PoolSelection   PoolSelectionPolicy `json:"poolSelectionPolicy,omitempty"`
 	}
 	tmp.Version = c.Version
 	tmp.Name = c.Name
 	tmp.Pools = c.Pools
 	tmp.AdditionalPools = c.AdditionalPools
+	tmp.PoolSelection = c.PoolSelection
 	attrs, err := storageattribute.MarshalRequestMap(c.Attributes)
 	if err != nil {
 		return nil, err
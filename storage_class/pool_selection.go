@@ -0,0 +1,123 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package storageclass
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage"
+)
+
+// PoolSelectionPolicy determines the order in which the storage pools of a
+// storage class are tried when placing a new volume.
+type PoolSelectionPolicy string
+
+const (
+	// PoolSelectionRandom tries the pools in random order.  It is the default.
+	PoolSelectionRandom = PoolSelectionPolicy("random")
+	// PoolSelectionMostFreeSpace tries the pools with the most available
+	// space first.
+	PoolSelectionMostFreeSpace = PoolSelectionPolicy("mostFreeSpace")
+	// PoolSelectionLeastVolumes tries the pools holding the fewest Trident
+	// volumes first.
+	PoolSelectionLeastVolumes = PoolSelectionPolicy("leastVolumes")
+	// PoolSelectionRoundRobin starts each placement with the pool after the
+	// one the previous placement started with.
+	PoolSelectionRoundRobin = PoolSelectionPolicy("roundRobin")
+)
+
+var poolSelectionPolicies = []PoolSelectionPolicy{
+	PoolSelectionRandom,
+	PoolSelectionMostFreeSpace,
+	PoolSelectionLeastVolumes,
+	PoolSelectionRoundRobin,
+}
+
+// ValidatePoolSelectionPolicy returns an error if the policy is not known.
+// An empty policy selects the default.
+func ValidatePoolSelectionPolicy(policy PoolSelectionPolicy) error {
+	if policy == "" {
+		return nil
+	}
+	for _, p := range poolSelectionPolicies {
+		if policy == p {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown pool selection policy %s; acceptable values: %v", policy,
+		poolSelectionPolicies)
+}
+
+func (s *StorageClass) GetPoolSelectionPolicy() PoolSelectionPolicy {
+	if s.config.PoolSelection == "" {
+		return PoolSelectionRandom
+	}
+	return s.config.PoolSelection
+}
+
+// GetStoragePoolsForVolume returns the storage class's pools that offer the
+// given protocol in the order they should be tried for a volume of the given
+// size.  The order comes from the class's pool selection policy, except that
+// pools known to lack room for the volume are always tried last.
+func (s *StorageClass) GetStoragePoolsForVolume(p config.Protocol, sizeBytes uint64) []*storage.Pool {
+
+	pools := s.GetStoragePoolsForProtocol(p)
+	if len(pools) == 0 {
+		return pools
+	}
+
+	switch s.GetPoolSelectionPolicy() {
+	case PoolSelectionMostFreeSpace:
+		pools = shufflePools(pools)
+		sort.SliceStable(pools, func(i, j int) bool {
+			return availableBytes(pools[i]) > availableBytes(pools[j])
+		})
+	case PoolSelectionLeastVolumes:
+		pools = shufflePools(pools)
+		sort.SliceStable(pools, func(i, j int) bool {
+			return pools[i].VolumeCount() < pools[j].VolumeCount()
+		})
+	case PoolSelectionRoundRobin:
+		sort.Slice(pools, func(i, j int) bool {
+			if pools[i].Backend.Name != pools[j].Backend.Name {
+				return pools[i].Backend.Name < pools[j].Backend.Name
+			}
+			return pools[i].Name < pools[j].Name
+		})
+		start := s.nextPool % len(pools)
+		s.nextPool = start + 1
+		rotated := make([]*storage.Pool, 0, len(pools))
+		rotated = append(rotated, pools[start:]...)
+		pools = append(rotated, pools[:start]...)
+	default:
+		pools = shufflePools(pools)
+	}
+
+	sort.SliceStable(pools, func(i, j int) bool {
+		return pools[i].HasSpaceFor(sizeBytes) && !pools[j].HasSpaceFor(sizeBytes)
+	})
+	return pools
+}
+
+// shufflePools returns the pools in random order.
+func shufflePools(pools []*storage.Pool) []*storage.Pool {
+	rand.Seed(time.Now().UnixNano())
+	shuffled := make([]*storage.Pool, len(pools))
+	for i, num := range rand.Perm(len(pools)) {
+		shuffled[i] = pools[num]
+	}
+	return shuffled
+}
+
+// availableBytes returns the space available in a pool, treating pools that
+// don't report their capacity as empty so that they sort after those that do.
+func availableBytes(pool *storage.Pool) uint64 {
+	if pool.Capacity == nil {
+		return 0
+	}
+	return pool.Capacity.AvailableBytes
+}
@@ -3,6 +3,7 @@
 package storageclass
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
@@ -305,3 +306,71 @@ func TestSpecificBackends(t *testing.T) {
 		}
 	}
 }
+
+func TestPoolSelectionPolicies(t *testing.T) {
+	backend := &storage.Backend{
+		Name:    "selection",
+		Storage: make(map[string]*storage.Pool),
+		Volumes: make(map[string]*storage.Volume),
+	}
+	newPool := func(name string, availableBytes uint64, volumes int) *storage.Pool {
+		pool := storage.NewStoragePool(backend, name)
+		pool.Capacity = &storage.PoolCapacity{AvailableBytes: availableBytes}
+		for i := 0; i < volumes; i++ {
+			volName := fmt.Sprintf("%s-%d", name, i)
+			backend.Volumes[volName] = storage.NewVolume(&storage.VolumeConfig{Name: volName},
+				backend.Name, name, false)
+		}
+		backend.AddStoragePool(pool)
+		return pool
+	}
+	pools := []*storage.Pool{
+		newPool("small", 10, 0),
+		newPool("large", 1000, 2),
+		newPool("medium", 100, 1),
+	}
+	names := func(pools []*storage.Pool) string {
+		poolNames := make([]string, 0, len(pools))
+		for _, pool := range pools {
+			poolNames = append(poolNames, pool.Name)
+		}
+		return strings.Join(poolNames, ",")
+	}
+
+	for _, test := range []struct {
+		policy    PoolSelectionPolicy
+		sizeBytes uint64
+		expected  []string
+	}{
+		{PoolSelectionMostFreeSpace, 1, []string{"large,medium,small"}},
+		{PoolSelectionLeastVolumes, 1, []string{"small,medium,large"}},
+		// Pools without room for the volume are tried last.
+		{PoolSelectionLeastVolumes, 50, []string{"medium,large,small"}},
+		{PoolSelectionRoundRobin, 1, []string{"large,medium,small", "medium,small,large",
+			"small,large,medium", "large,medium,small"}},
+	} {
+		sc := New(&Config{Name: "selection", PoolSelection: test.policy})
+		sc.pools = pools
+		for i, expected := range test.expected {
+			if actual := names(sc.GetStoragePoolsForVolume(config.ProtocolAny, test.sizeBytes)); actual != expected {
+				t.Errorf("%s, pass %d: expected pools %s, got %s", test.policy, i, expected, actual)
+			}
+		}
+	}
+
+	sc := New(&Config{Name: "default"})
+	sc.pools = pools
+	if policy := sc.GetPoolSelectionPolicy(); policy != PoolSelectionRandom {
+		t.Errorf("Expected default policy %s, got %s", PoolSelectionRandom, policy)
+	}
+	if actual := sc.GetStoragePoolsForVolume(config.ProtocolAny, 1); len(actual) != len(pools) {
+		t.Errorf("Random policy returned %d pools; expected %d", len(actual), len(pools))
+	}
+
+	if err := ValidatePoolSelectionPolicy("fullest"); err == nil {
+		t.Error("Unknown pool selection policy accepted.")
+	}
+	if err := ValidatePoolSelectionPolicy(""); err != nil {
+		t.Errorf("Empty pool selection policy rejected: %v", err)
+	}
+}
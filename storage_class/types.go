@@ -10,6 +10,8 @@ import (
 type StorageClass struct {
 	config *Config
 	pools  []*storage.Pool
+	// nextPool is where the round-robin policy starts its next pass
+	nextPool int
 }
 
 type Config struct {
@@ -20,6 +22,7 @@ type Config struct {
 	Attributes      map[string]storageattribute.Request `json:"attributes,omitempty"`
 	Pools           map[string][]string                 `json:"storagePools,omitempty"`
 	AdditionalPools map[string][]string                 `json:"additionalStoragePools,omitempty"`
+	PoolSelection   PoolSelectionPolicy                 `json:"poolSelectionPolicy,omitempty"`
 }
 
 type External struct {
@@ -13,13 +13,34 @@ const DefaultTridentIgroupName = "trident"
 
 // Storage driver names specified in the config file, etc.
 const (
-	EseriesIscsiStorageDriverName  = "eseries-iscsi"
-	OntapNASStorageDriverName      = "ontap-nas"
-	OntapNASQtreeStorageDriverName = "ontap-nas-economy"
-	OntapSANStorageDriverName      = "ontap-san"
-	SolidfireSANStorageDriverName  = "solidfire-san"
-	FakeStorageDriverName          = "fake"
+	EseriesIscsiStorageDriverName      = "eseries-iscsi"
+	OntapNASStorageDriverName          = "ontap-nas"
+	OntapNASQtreeStorageDriverName     = "ontap-nas-economy"
+	OntapNASFlexGroupStorageDriverName = "ontap-nas-flexgroup"
+	OntapSANStorageDriverName          = "ontap-san"
+	OntapSANEconomyStorageDriverName   = "ontap-san-economy"
+	SolidfireSANStorageDriverName      = "solidfire-san"
+	FakeStorageDriverName              = "fake"
 )
 
 const UnsetPool = ""
+
+// AttachOptionReadOnly is set to "true" in the options passed to a driver's
+// Attach method when the volume must be mounted read-only.
+const AttachOptionReadOnly = "readOnly"
 const DefaultVolumeSize = "1G"
+
+// Orphan policies, which say what Trident does with the objects on a backend
+// that carry its storage prefix but that it has no record of
+const (
+	OrphanPolicyIgnore = "ignore"
+	OrphanPolicyReport = "report"
+	OrphanPolicyDelete = "delete"
+)
+
+// DefaultOrphanPolicy is the orphan policy of backends that don't specify one
+const DefaultOrphanPolicy = OrphanPolicyReport
+
+// DefaultOrphanGracePeriod is how long an object must go unrecorded before
+// it is treated as an orphan, in backends that don't specify a grace period
+const DefaultOrphanGracePeriod = "24h"
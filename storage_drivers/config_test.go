@@ -40,3 +40,69 @@ func TestGetCommonInternalVolumeName(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateCommonSettingsOrphanPolicy(t *testing.T) {
+	for _, test := range []struct {
+		configJSON  string
+		policy      string
+		gracePeriod string
+		valid       bool
+	}{
+		{`{"version": 1, "storageDriverName": "fake"}`, OrphanPolicyReport, DefaultOrphanGracePeriod, true},
+		{`{"version": 1, "storageDriverName": "fake", "orphanPolicy": "delete", "orphanGracePeriod": "1h"}`,
+			OrphanPolicyDelete, "1h", true},
+		{`{"version": 1, "storageDriverName": "fake", "orphanPolicy": "ignore"}`,
+			OrphanPolicyIgnore, DefaultOrphanGracePeriod, true},
+		{`{"version": 1, "storageDriverName": "fake", "orphanPolicy": "purge"}`, "", "", false},
+		{`{"version": 1, "storageDriverName": "fake", "orphanGracePeriod": "a day"}`, "", "", false},
+	} {
+		c, err := ValidateCommonSettings(test.configJSON)
+		if !test.valid {
+			if err == nil {
+				t.Errorf("%s: expected an error", test.configJSON)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.configJSON, err)
+			continue
+		}
+		if c.OrphanPolicy != test.policy || c.OrphanGracePeriod != test.gracePeriod {
+			t.Errorf("%s: expected %s/%s, got %s/%s", test.configJSON, test.policy, test.gracePeriod,
+				c.OrphanPolicy, c.OrphanGracePeriod)
+		}
+	}
+
+	empty := ""
+	if err := ValidateOrphanPolicy(&CommonStorageDriverConfig{
+		OrphanPolicy:  OrphanPolicyDelete,
+		StoragePrefix: &empty,
+	}); err == nil {
+		t.Error("Expected an error deleting orphans without a storage prefix.")
+	}
+}
+
+func TestValidateCommonSettingsLabels(t *testing.T) {
+	for _, test := range []struct {
+		configJSON string
+		valid      bool
+	}{
+		{`{"version": 1, "storageDriverName": "fake", "labels": {"tier": "gold"}}`, true},
+		{`{"version": 1, "storageDriverName": "fake", "labels": {"": "gold"}}`, false},
+		{`{"version": 1, "storageDriverName": "fake", "labels": {" tier": "gold"}}`, false},
+		{`{"version": 1, "storageDriverName": "fake", "labels": {"tier": ""}}`, false},
+	} {
+		c, err := ValidateCommonSettings(test.configJSON)
+		if !test.valid {
+			if err == nil {
+				t.Errorf("%s: expected an error", test.configJSON)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.configJSON, err)
+		} else if c.Labels["tier"] != "gold" {
+			t.Errorf("%s: expected label tier=gold, got %v", test.configJSON, c.Labels)
+		}
+	}
+}
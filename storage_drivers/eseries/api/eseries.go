@@ -20,6 +20,7 @@ import (
 
 	log "github.com/sirupsen/logrus"
 
+	"github.com/netapp/trident/metrics"
 	"github.com/netapp/trident/utils"
 )
 
@@ -30,6 +31,8 @@ const hostMappingType = "host"
 const hostGroupMappingType = "cluster"
 const defaultPoolSearchPattern = ".+"
 
+var resourceSegmentRegex = regexp.MustCompile(`^[a-z-]*$`)
+
 // ClientConfig holds configuration data for the API driver object.
 type ClientConfig struct {
 	// Web Proxy Services Info
@@ -166,9 +169,12 @@ func (d Client) InvokeAPI(requestBody []byte, method string, resourcePath string
 		Transport: tr,
 		Timeout:   time.Duration(httpTimeoutSeconds * time.Second),
 	}
+	apiMethod := method + " " + getResourceName(resourcePath)
+	start := time.Now()
 	response, err := client.Do(request)
 	if err != nil {
 		log.Warnf("Error communicating with Web Services Proxy. %v", err)
+		metrics.ObserveStorageAPICall("eseries", apiMethod, start, metrics.CodeTransportError)
 		return nil, nil, err
 	}
 	defer response.Body.Close()
@@ -194,9 +200,32 @@ func (d Client) InvokeAPI(requestBody []byte, method string, resourcePath string
 		}
 	}
 
+	errorCode := ""
+	if err != nil {
+		errorCode = metrics.CodeTransportError
+	} else if response.StatusCode >= 300 {
+		errorCode = strconv.Itoa(response.StatusCode)
+	}
+	metrics.ObserveStorageAPICall("eseries", apiMethod, start, errorCode)
+
 	return response, responseBody, err
 }
 
+// getResourceName returns a resource path with the object references replaced
+// by a placeholder, so that calls to the same API can be grouped together.
+func getResourceName(resourcePath string) string {
+	segments := strings.Split(resourcePath, "/")
+	for i, segment := range segments {
+		if !resourceSegmentRegex.MatchString(segment) {
+			segments[i] = "{ref}"
+		}
+	}
+	if name := strings.Join(segments, "/"); name != "" {
+		return name
+	}
+	return "/"
+}
+
 // Connect connects to the Web Services Proxy and registers the array with it.
 func (d Client) Connect() (string, error) {
 
@@ -616,6 +645,52 @@ func (d Client) CreateVolume(
 	}
 }
 
+// ResizeVolume expands a volume (i.e. a LUN) on the array to the specified size.
+func (d Client) ResizeVolume(volume VolumeEx, size uint64) error {
+
+	if d.config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "ResizeVolume",
+			"Type":   "Client",
+			"name":   volume.Label,
+			"size":   size,
+		}
+		log.WithFields(fields).Debug(">>>> ResizeVolume")
+		defer log.WithFields(fields).Debug("<<<< ResizeVolume")
+	}
+
+	// Set up the volume resize request
+	request := VolumeResizeRequest{
+		SizeUnit:      "kb",
+		ExpansionSize: int(size / 1024), // The API requires ExpansionSize to be an int (not int64) so pass in KB.
+	}
+
+	jsonRequest, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("could not marshal JSON request: %v; %v", request, err)
+	}
+
+	// Expand the volume
+	response, responseBody, err := d.InvokeAPI(jsonRequest, "POST", "/volumes/"+volume.VolumeRef+"/expand")
+	if err != nil {
+		return fmt.Errorf("API invocation failed. %v", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		err = d.getErrorFromHTTPResponse(response, responseBody)
+		return fmt.Errorf("could not resize volume %s: %v", volume.Label, err)
+	}
+
+	log.WithFields(log.Fields{
+		"Name":           volume.Label,
+		"VolumeRef":      volume.VolumeRef,
+		"VolumeGroupRef": volume.VolumeGroupRef,
+		"Size":           size,
+	}).Debug("Resized volume.")
+
+	return nil
+}
+
 // DeleteVolume deletes a volume from the array.
 func (d Client) DeleteVolume(volume VolumeEx) error {
 
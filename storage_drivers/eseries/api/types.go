@@ -63,12 +63,13 @@ type Controller struct {
 }
 
 type VolumeGroupEx struct {
-	IsOffline      bool   `json:"offline"`
-	WorldWideName  string `json:"worldWideName"`
-	VolumeGroupRef string `json:"volumeGroupRef"`
-	Label          string `json:"label"`
-	FreeSpace      string `json:"freeSpace"`      // Documentation says this is an int but really it is a string!
-	DriveMediaType string `json:"driveMediaType"` // 'hdd', 'ssd'
+	IsOffline        bool   `json:"offline"`
+	WorldWideName    string `json:"worldWideName"`
+	VolumeGroupRef   string `json:"volumeGroupRef"`
+	Label            string `json:"label"`
+	FreeSpace        string `json:"freeSpace"`        // Documentation says this is an int but really it is a string!
+	TotalRaidedSpace string `json:"totalRaidedSpace"` // Also a string
+	DriveMediaType   string `json:"driveMediaType"`   // 'hdd', 'ssd'
 }
 
 // Functions to allow sorting storage pools by free space
@@ -95,6 +96,11 @@ type VolumeCreateRequest struct {
 	VolumeTags       []VolumeTag `json:"metaTags,omitempty"`
 }
 
+type VolumeResizeRequest struct {
+	SizeUnit      string `json:"sizeUnit"` //bytes, b, kb, mb, gb, tb, pb, eb, zb, yb
+	ExpansionSize int    `json:"expansionSize"`
+}
+
 type VolumeTag struct {
 	Key   string `json:"key"`
 	Value string `json:"value"`
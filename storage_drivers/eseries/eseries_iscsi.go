@@ -206,6 +206,9 @@ func (d *SANStorageDriver) validate() error {
 		return errors.New("HostDataIP is empty! You need to specify at least one of the iSCSI interface " +
 			"IP addresses that is connected to the E-Series array")
 	}
+	if err := drivers.ValidateOrphanPolicy(d.Config.CommonStorageDriverConfig); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -317,6 +320,49 @@ func (d *SANStorageDriver) Destroy(name string) error {
 	return nil
 }
 
+// Resize expands a volume on the storage array to the requested size.
+func (d *SANStorageDriver) Resize(name string, sizeBytes uint64) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":    "Resize",
+			"Type":      "SANStorageDriver",
+			"name":      name,
+			"sizeBytes": sizeBytes,
+		}
+		log.WithFields(fields).Debug(">>>> Resize")
+		defer log.WithFields(fields).Debug("<<<< Resize")
+	}
+
+	vol, err := d.API.GetVolume(name)
+	if err != nil {
+		return fmt.Errorf("could not find volume %s: %v", name, err)
+	}
+	if !d.API.IsRefValid(vol.VolumeRef) {
+		return fmt.Errorf("could not find volume %s", name)
+	}
+
+	currentSizeBytes, err := strconv.ParseUint(vol.VolumeSize, 10, 64)
+	if err != nil {
+		return fmt.Errorf("could not determine size of volume %s: %v", name, err)
+	}
+
+	if sizeBytes < currentSizeBytes {
+		return fmt.Errorf("requested volume size (%d bytes) is smaller than the current volume size (%d bytes)",
+			sizeBytes, currentSizeBytes)
+	}
+	if sizeBytes == currentSizeBytes {
+		log.WithField("Name", name).Debug("Volume already has the requested size.")
+		return nil
+	}
+
+	if err = d.API.ResizeVolume(vol, sizeBytes); err != nil {
+		return fmt.Errorf("could not resize volume %s: %v", name, err)
+	}
+
+	return nil
+}
+
 // Attach is called by Docker when attaching a container volume to a container. This method is expected to map the volume
 // to the local host, discover it on the SCSI bus, format it with a filesystem, and mount it at the specified mount point.
 // This method has an opts parameter, but no options are presently handled by this method.
@@ -334,6 +380,8 @@ func (d *SANStorageDriver) Attach(name, mountpoint string, opts map[string]strin
 		defer log.WithFields(fields).Debug("<<<< Attach")
 	}
 
+	readOnly := drivers.IsReadOnlyAttach(opts)
+
 	// Get the volume
 	vol, err := d.API.GetVolume(name)
 	if err != nil {
@@ -404,6 +452,9 @@ func (d *SANStorageDriver) Attach(name, mountpoint string, opts map[string]strin
 
 	// Put a filesystem on the volume if there isn't one already there
 	if deviceToUse.Filesystem == "" {
+		if readOnly {
+			return fmt.Errorf("volume %s has no file system, so it cannot be mounted read-only", name)
+		}
 		log.WithFields(log.Fields{"LUN": name, "fstype": fstype}).Debug("Formatting LUN.")
 		err := utils.FormatVolume(deviceRef, fstype)
 		if err != nil {
@@ -420,7 +471,7 @@ func (d *SANStorageDriver) Attach(name, mountpoint string, opts map[string]strin
 	}
 
 	// Mount the volume
-	err = utils.Mount(deviceRef, mountpoint)
+	err = utils.Mount(deviceRef, mountpoint, readOnly)
 	if err != nil {
 		return fmt.Errorf("could not mount volume %s, device %v at mount point %s: %v", name, deviceToUse,
 			mountpoint, err)
@@ -553,6 +604,94 @@ func (d *SANStorageDriver) SnapshotList(name string) ([]storage.Snapshot, error)
 	return make([]storage.Snapshot, 0), nil
 }
 
+// CreateSnapshot creates a snapshot of the named volume. The E-series volume plugin does not support snapshots,
+// so this method always returns an error.
+func (d *SANStorageDriver) CreateSnapshot(name, snapshot string) (*storage.Snapshot, error) {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":   "CreateSnapshot",
+			"Type":     "SANStorageDriver",
+			"name":     name,
+			"snapshot": snapshot,
+		}
+		log.WithFields(fields).Debug(">>>> CreateSnapshot")
+		defer log.WithFields(fields).Debug("<<<< CreateSnapshot")
+	}
+
+	return nil, errors.New("snapshots with E-Series are not supported")
+}
+
+// DeleteSnapshot deletes a snapshot of the named volume. The E-series volume plugin does not support snapshots,
+// so this method always returns an error.
+func (d *SANStorageDriver) DeleteSnapshot(name, snapshot string) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":   "DeleteSnapshot",
+			"Type":     "SANStorageDriver",
+			"name":     name,
+			"snapshot": snapshot,
+		}
+		log.WithFields(fields).Debug(">>>> DeleteSnapshot")
+		defer log.WithFields(fields).Debug("<<<< DeleteSnapshot")
+	}
+
+	return errors.New("snapshots with E-Series are not supported")
+}
+
+// RestoreSnapshot restores the named volume from one of its snapshots. The E-series volume plugin does not
+// support snapshots, so this method always returns an error.
+func (d *SANStorageDriver) RestoreSnapshot(name, snapshot string) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":   "RestoreSnapshot",
+			"Type":     "SANStorageDriver",
+			"name":     name,
+			"snapshot": snapshot,
+		}
+		log.WithFields(fields).Debug(">>>> RestoreSnapshot")
+		defer log.WithFields(fields).Debug("<<<< RestoreSnapshot")
+	}
+
+	return errors.New("snapshots with E-Series are not supported")
+}
+
+// GetReplicationStatus returns the state of the relationship that replicates the named volume.  The E-series volume plugin does not
+// support replication, so this method always returns nil.
+func (d *SANStorageDriver) GetReplicationStatus(name string) (*storage.ReplicationStatus, error) {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "GetReplicationStatus",
+			"Type":   "SANStorageDriver",
+			"name":   name,
+		}
+		log.WithFields(fields).Debug(">>>> GetReplicationStatus")
+		defer log.WithFields(fields).Debug("<<<< GetReplicationStatus")
+	}
+
+	return nil, nil
+}
+
+// Failover switches a volume to its replica.  The E-series volume plugin does not support replication, so this
+// method always returns an error.
+func (d *SANStorageDriver) Failover(volConfig *storage.VolumeConfig) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "Failover",
+			"Type":   "SANStorageDriver",
+			"name":   volConfig.InternalName,
+		}
+		log.WithFields(fields).Debug(">>>> Failover")
+		defer log.WithFields(fields).Debug("<<<< Failover")
+	}
+
+	return errors.New("replication with E-Series is not supported")
+}
+
 // CreateClone creates a new volume from the named volume, either by direct clone or from the named snapshot. The E-series volume plugin
 // does not support cloning or snapshots, so this method always returns an error.
 func (d *SANStorageDriver) CreateClone(name, source, snapshot string, opts map[string]string) error {
@@ -680,8 +819,22 @@ func (d *SANStorageDriver) GetStorageBackendSpecs(backend *storage.Backend) erro
 		vc.Attributes[sa.Snapshots] = sa.NewBoolOffer(false)
 		vc.Attributes[sa.Clones] = sa.NewBoolOffer(false)
 		vc.Attributes[sa.Encryption] = sa.NewBoolOffer(false)
+		vc.Attributes[sa.Replication] = sa.NewBoolOffer(false)
 		vc.Attributes[sa.ProvisioningType] = sa.NewStringOffer("thick")
 
+		// Record the pool's space, which the array reports as strings
+		if freeSpace, err := strconv.ParseUint(pool.FreeSpace, 10, 64); err == nil {
+			vc.Capacity = &storage.PoolCapacity{AvailableBytes: freeSpace}
+			if totalSpace, err := strconv.ParseUint(pool.TotalRaidedSpace, 10, 64); err == nil {
+				vc.Capacity.TotalBytes = totalSpace
+			}
+		} else {
+			log.WithFields(log.Fields{
+				"pool":  pool.Label,
+				"error": err,
+			}).Warn("Could not parse free space for pool.")
+		}
+
 		backend.AddStoragePool(vc)
 
 		log.WithFields(log.Fields{
@@ -752,7 +905,7 @@ func (d *SANStorageDriver) GetVolumeOpts(
 
 	// Include mediaType request if present
 	if mediaTypeReq, ok := requests[sa.Media]; ok {
-		if mediaType, ok := mediaTypeReq.Value().(string); ok {
+		if mediaType, ok := sa.ResolveStringRequest(mediaTypeReq, sa.HDD, sa.SSD); ok {
 			if mediaType == sa.HDD {
 				opts["mediaType"] = "hdd"
 			} else if mediaType == sa.SSD {
@@ -768,8 +921,8 @@ func (d *SANStorageDriver) GetVolumeOpts(
 			log.WithFields(log.Fields{
 				"provisioner":      "E-series",
 				"method":           "GetVolumeOpts",
-				"provisioningType": mediaTypeReq.Value(),
-			}).Warnf("Expected string for %s; ignoring.", sa.Media)
+				"provisioningType": mediaTypeReq.String(),
+			}).Warnf("Expected a single value for %s; ignoring.", sa.Media)
 		}
 	}
 
@@ -836,6 +989,21 @@ func (d *SANStorageDriver) CreateFollowup(volConfig *storage.VolumeConfig) error
 	return nil
 }
 
+func (d *SANStorageDriver) Import(volConfig *storage.VolumeConfig, originalName string) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":       "Import",
+			"Type":         "SANStorageDriver",
+			"originalName": originalName,
+		}
+		log.WithFields(fields).Debug(">>>> Import")
+		defer log.WithFields(fields).Debug("<<<< Import")
+	}
+
+	return errors.New("import with E-Series is not supported")
+}
+
 func (d *SANStorageDriver) GetProtocol() trident.Protocol {
 	return trident.Block
 }
@@ -860,6 +1028,11 @@ func (d *SANStorageDriver) GetExternalConfig() interface{} {
 	}
 }
 
+// GetCommonConfig returns the settings the driver shares with all drivers.
+func (d *SANStorageDriver) GetCommonConfig() *drivers.CommonStorageDriverConfig {
+	return d.Config.CommonStorageDriverConfig
+}
+
 func (d *SANStorageDriver) uuidToBase64(UUID string) (string, error) {
 
 	// Strip out hyphens
@@ -6,9 +6,10 @@ import (
 	"bytes"
 	"encoding/gob"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"strconv"
+	"sync"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 
@@ -28,9 +29,21 @@ type StorageDriver struct {
 	initialized bool
 	Config      drivers.FakeStorageDriverConfig
 
+	// mutex serializes access to the volumes, snapshots and pools below,
+	// since the orchestrator may call the driver concurrently
+	mutex sync.Mutex
+
 	// Volumes saves info about Volumes created on this driver
 	Volumes map[string]fake.Volume
 
+	// Snapshots saves info about the snapshots of each volume, keyed by volume name
+	Snapshots map[string]map[string]storage.Snapshot
+
+	// Mounts records the mount points of attached volumes, and whether each
+	// was attached read-only, since the fake driver doesn't really mount
+	// anything.
+	Mounts map[string]bool
+
 	// DestroyedVolumes is here so that tests can check whether destroy
 	// has been called on a volume during or after bootstrapping, since
 	// different driver instances with the same config won't actually share
@@ -43,6 +56,8 @@ func NewFakeStorageDriver(config drivers.FakeStorageDriverConfig) *StorageDriver
 		initialized:      true,
 		Config:           config,
 		Volumes:          make(map[string]fake.Volume),
+		Snapshots:        make(map[string]map[string]storage.Snapshot),
+		Mounts:           make(map[string]bool),
 		DestroyedVolumes: make(map[string]bool),
 	}
 }
@@ -94,6 +109,8 @@ func (d *StorageDriver) Initialize(
 	}
 
 	d.Volumes = make(map[string]fake.Volume)
+	d.Snapshots = make(map[string]map[string]storage.Snapshot)
+	d.Mounts = make(map[string]bool)
 	d.DestroyedVolumes = make(map[string]bool)
 	d.Config.SerialNumbers = []string{d.Config.InstanceName + "_SN"}
 
@@ -114,6 +131,9 @@ func (d *StorageDriver) Terminate() {
 
 func (d *StorageDriver) Create(name string, sizeBytes uint64, opts map[string]string) error {
 
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	poolName, ok := opts[FakePoolAttribute]
 	if !ok {
 		return fmt.Errorf("no pool specified; expected %s in opts map", FakePoolAttribute)
@@ -139,18 +159,20 @@ func (d *StorageDriver) Create(name string, sizeBytes uint64, opts map[string]st
 	}
 
 	d.Volumes[name] = fake.Volume{
-		Name:      name,
-		PoolName:  poolName,
-		SizeBytes: sizeBytes,
+		Name:       name,
+		PoolName:   poolName,
+		SizeBytes:  sizeBytes,
+		Replicated: opts["replication"] == "true",
 	}
 	d.DestroyedVolumes[name] = false
 	pool.Bytes -= sizeBytes
 
 	log.WithFields(log.Fields{
-		"backend":   d.Config.InstanceName,
-		"Name":      name,
-		"PoolName":  poolName,
-		"SizeBytes": sizeBytes,
+		"backend":    d.Config.InstanceName,
+		"Name":       name,
+		"PoolName":   poolName,
+		"SizeBytes":  sizeBytes,
+		"Replicated": d.Volumes[name].Replicated,
 	}).Debug("Created fake volume.")
 
 	return nil
@@ -158,6 +180,9 @@ func (d *StorageDriver) Create(name string, sizeBytes uint64, opts map[string]st
 
 func (d *StorageDriver) CreateClone(name, source, snapshot string, opts map[string]string) error {
 
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	// Ensure source volume exists
 	sourceVolume, ok := d.Volumes[source]
 	if !ok {
@@ -205,6 +230,9 @@ func (d *StorageDriver) CreateClone(name, source, snapshot string, opts map[stri
 
 func (d *StorageDriver) Destroy(name string) error {
 
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	d.DestroyedVolumes[name] = true
 
 	volume, ok := d.Volumes[name]
@@ -219,6 +247,7 @@ func (d *StorageDriver) Destroy(name string) error {
 
 	pool.Bytes += volume.SizeBytes
 	delete(d.Volumes, name)
+	delete(d.Snapshots, name)
 
 	log.WithFields(log.Fields{
 		"backend":   d.Config.InstanceName,
@@ -230,19 +259,234 @@ func (d *StorageDriver) Destroy(name string) error {
 	return nil
 }
 
+func (d *StorageDriver) Resize(name string, sizeBytes uint64) error {
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	volume, ok := d.Volumes[name]
+	if !ok {
+		return fmt.Errorf("volume %s not found", name)
+	}
+
+	pool, ok := d.Config.Pools[volume.PoolName]
+	if !ok {
+		return fmt.Errorf("could not find pool %s", volume.PoolName)
+	}
+
+	if sizeBytes < volume.SizeBytes {
+		return fmt.Errorf("requested volume size (%d bytes) is smaller than the current volume size (%d bytes)",
+			sizeBytes, volume.SizeBytes)
+	}
+
+	deltaBytes := sizeBytes - volume.SizeBytes
+	if deltaBytes > pool.Bytes {
+		return fmt.Errorf("requested resize is too large; requested %d more bytes; have %d available in pool %s",
+			deltaBytes, pool.Bytes, volume.PoolName)
+	}
+
+	volume.SizeBytes = sizeBytes
+	d.Volumes[name] = volume
+	pool.Bytes -= deltaBytes
+
+	log.WithFields(log.Fields{
+		"backend":   d.Config.InstanceName,
+		"Name":      name,
+		"PoolName":  volume.PoolName,
+		"SizeBytes": sizeBytes,
+	}).Debug("Resized fake volume.")
+
+	return nil
+}
+
 func (d *StorageDriver) Attach(name, mountpoint string, opts map[string]string) error {
-	return errors.New("fake driver does not support attaching")
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if _, ok := d.Volumes[name]; !ok {
+		return fmt.Errorf("volume %s not found", name)
+	}
+	if _, ok := d.Mounts[mountpoint]; ok {
+		return fmt.Errorf("mount point %s is already in use", mountpoint)
+	}
+	d.Mounts[mountpoint] = drivers.IsReadOnlyAttach(opts)
+
+	log.WithFields(log.Fields{
+		"backend":    d.Config.InstanceName,
+		"Name":       name,
+		"mountpoint": mountpoint,
+		"readOnly":   d.Mounts[mountpoint],
+	}).Debug("Attached fake volume.")
+
+	return nil
 }
 
 func (d *StorageDriver) Detach(name, mountpoint string) error {
-	return errors.New("fake driver does not support detaching")
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	delete(d.Mounts, mountpoint)
+	return nil
 }
 
 func (d *StorageDriver) SnapshotList(name string) ([]storage.Snapshot, error) {
-	return nil, errors.New("fake driver does not support SnapshotList")
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if _, ok := d.Volumes[name]; !ok {
+		return nil, fmt.Errorf("volume %s not found", name)
+	}
+
+	snapshots := make([]storage.Snapshot, 0)
+	for _, snapshot := range d.Snapshots[name] {
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, nil
+}
+
+func (d *StorageDriver) CreateSnapshot(name, snapshot string) (*storage.Snapshot, error) {
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if _, ok := d.Volumes[name]; !ok {
+		return nil, fmt.Errorf("volume %s not found", name)
+	}
+
+	if _, ok := d.Snapshots[name]; !ok {
+		d.Snapshots[name] = make(map[string]storage.Snapshot)
+	}
+	if _, ok := d.Snapshots[name][snapshot]; ok {
+		return nil, fmt.Errorf("snapshot %s already exists on volume %s", snapshot, name)
+	}
+
+	snap := storage.Snapshot{
+		Name:    snapshot,
+		Created: time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+	}
+	d.Snapshots[name][snapshot] = snap
+
+	log.WithFields(log.Fields{
+		"backend":  d.Config.InstanceName,
+		"Name":     name,
+		"snapshot": snapshot,
+	}).Debug("Created fake snapshot.")
+
+	return &snap, nil
+}
+
+func (d *StorageDriver) DeleteSnapshot(name, snapshot string) error {
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if _, ok := d.Volumes[name]; !ok {
+		return fmt.Errorf("volume %s not found", name)
+	}
+
+	delete(d.Snapshots[name], snapshot)
+
+	log.WithFields(log.Fields{
+		"backend":  d.Config.InstanceName,
+		"Name":     name,
+		"snapshot": snapshot,
+	}).Debug("Deleted fake snapshot.")
+
+	return nil
+}
+
+func (d *StorageDriver) RestoreSnapshot(name, snapshot string) error {
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if _, ok := d.Volumes[name]; !ok {
+		return fmt.Errorf("volume %s not found", name)
+	}
+
+	if _, ok := d.Snapshots[name][snapshot]; !ok {
+		return fmt.Errorf("snapshot %s not found on volume %s", snapshot, name)
+	}
+
+	log.WithFields(log.Fields{
+		"backend":  d.Config.InstanceName,
+		"Name":     name,
+		"snapshot": snapshot,
+	}).Debug("Restored fake snapshot.")
+
+	return nil
+}
+
+// GetReplicationStatus reports a healthy relationship for volumes created
+// with replication, since the fake driver doesn't really replicate anything.
+func (d *StorageDriver) GetReplicationStatus(name string) (*storage.ReplicationStatus, error) {
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	volume, ok := d.Volumes[name]
+	if !ok {
+		return nil, fmt.Errorf("volume %s not found", name)
+	}
+	if !volume.Replicated {
+		return nil, nil
+	}
+
+	status := &storage.ReplicationStatus{
+		Source:      d.Config.InstanceName + ":" + name,
+		Destination: d.Config.InstanceName + "_replica:" + name,
+		State:       "snapmirrored",
+		Status:      "idle",
+		Healthy:     true,
+	}
+	if volume.FailedOver {
+		status.State = "broken-off"
+	}
+	return status, nil
+}
+
+// Failover points the access info of a replicated volume at an imaginary
+// replica.
+func (d *StorageDriver) Failover(volConfig *storage.VolumeConfig) error {
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	volume, ok := d.Volumes[volConfig.InternalName]
+	if !ok {
+		return fmt.Errorf("volume %s not found", volConfig.InternalName)
+	}
+	if !volume.Replicated {
+		return fmt.Errorf("volume %s is not replicated", volConfig.InternalName)
+	}
+	volume.FailedOver = true
+	d.Volumes[volConfig.InternalName] = volume
+
+	switch d.Config.Protocol {
+	case config.File:
+		volConfig.AccessInfo.NfsServerIP = "192.0.2.2" // unrouteable test address, see RFC 5737
+		volConfig.AccessInfo.NfsPath = "/" + volConfig.InternalName
+	case config.Block:
+		volConfig.AccessInfo.IscsiTargetPortal = "192.0.2.2"
+		volConfig.AccessInfo.IscsiTargetIQN = "iqn.2017-06.com.netapp:fake-replica"
+		volConfig.AccessInfo.IscsiLunNumber = 0
+	}
+
+	log.WithFields(log.Fields{
+		"backend": d.Config.InstanceName,
+		"Name":    volConfig.InternalName,
+	}).Debug("Failed over fake volume.")
+
+	return nil
 }
 
 func (d *StorageDriver) List() ([]string, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	vols := []string{}
 	for vol := range d.Volumes {
 		vols = append(vols, vol)
@@ -252,6 +496,9 @@ func (d *StorageDriver) List() ([]string, error) {
 
 func (d *StorageDriver) Get(name string) error {
 
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	_, ok := d.Volumes[name]
 	if !ok {
 		return fmt.Errorf("could not find volume %s", name)
@@ -261,15 +508,25 @@ func (d *StorageDriver) Get(name string) error {
 }
 
 func (d *StorageDriver) GetStorageBackendSpecs(backend *storage.Backend) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	backend.Name = d.Config.InstanceName
 	for name, pool := range d.Config.Pools {
+		// Copy the attributes, since they are read by the storage classes
+		// while the pool's capacity may be refreshed.
+		attributes := make(map[string]sa.Offer, len(pool.Attrs)+1)
+		for key, offer := range pool.Attrs {
+			attributes[key] = offer
+		}
+		attributes[sa.BackendType] = sa.NewStringOffer(d.Name())
 		vc := &storage.Pool{
 			Name:           name,
 			StorageClasses: make([]string, 0),
 			Backend:        backend,
-			Attributes:     pool.Attrs,
+			Attributes:     attributes,
+			Capacity:       &storage.PoolCapacity{AvailableBytes: pool.Bytes},
 		}
-		vc.Attributes[sa.BackendType] = sa.NewStringOffer(d.Name())
 		backend.AddStoragePool(vc)
 	}
 	return nil
@@ -284,6 +541,11 @@ func (d *StorageDriver) GetVolumeOpts(
 	if pool != nil {
 		opts[FakePoolAttribute] = pool.Name
 	}
+	if replicationReq, ok := requests[sa.Replication]; ok {
+		if replication, ok := replicationReq.Value().(bool); ok && replication {
+			opts["replication"] = "true"
+		}
+	}
 	return opts, nil
 }
 
@@ -314,6 +576,38 @@ func (d *StorageDriver) CreateFollowup(volConfig *storage.VolumeConfig) error {
 	return nil
 }
 
+func (d *StorageDriver) Import(volConfig *storage.VolumeConfig, originalName string) error {
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	volume, ok := d.Volumes[originalName]
+	if !ok {
+		return fmt.Errorf("volume %s not found", originalName)
+	}
+
+	if volConfig.InternalName != originalName {
+		if _, ok = d.Volumes[volConfig.InternalName]; ok {
+			return fmt.Errorf("volume %s already exists", volConfig.InternalName)
+		}
+		volume.Name = volConfig.InternalName
+		d.Volumes[volConfig.InternalName] = volume
+		delete(d.Volumes, originalName)
+		if snapshots, ok := d.Snapshots[originalName]; ok {
+			d.Snapshots[volConfig.InternalName] = snapshots
+			delete(d.Snapshots, originalName)
+		}
+	}
+
+	log.WithFields(log.Fields{
+		"backend":      d.Config.InstanceName,
+		"originalName": originalName,
+		"Name":         volConfig.InternalName,
+	}).Debug("Imported fake volume.")
+
+	return d.CreateFollowup(volConfig)
+}
+
 func (d *StorageDriver) GetProtocol() config.Protocol {
 	return d.Config.Protocol
 }
@@ -353,8 +647,16 @@ func (d *StorageDriver) GetExternalConfig() interface{} {
 	}
 }
 
+// GetCommonConfig returns the settings the driver shares with all drivers.
+func (d *StorageDriver) GetCommonConfig() *drivers.CommonStorageDriverConfig {
+	return d.Config.CommonStorageDriverConfig
+}
+
 func (d *StorageDriver) GetVolumeExternal(name string) (*storage.VolumeExternal, error) {
 
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	volume, ok := d.Volumes[name]
 	if !ok {
 		return nil, fmt.Errorf("fake volume %s not found", name)
@@ -370,8 +672,14 @@ func (d *StorageDriver) GetVolumeExternalWrappers(
 	defer close(channel)
 
 	// Convert all volumes to VolumeExternal and write them to the channel
+	d.mutex.Lock()
+	volumes := make([]*storage.VolumeExternal, 0, len(d.Volumes))
 	for _, volume := range d.Volumes {
-		channel <- &storage.VolumeExternalWrapper{d.getVolumeExternal(volume), nil}
+		volumes = append(volumes, d.getVolumeExternal(volume))
+	}
+	d.mutex.Unlock()
+	for _, volume := range volumes {
+		channel <- &storage.VolumeExternalWrapper{volume, nil}
 	}
 }
 
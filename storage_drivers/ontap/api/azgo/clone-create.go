@@ -0,0 +1,208 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package azgo
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// CloneCreateRequest is a structure to represent a clone-create ZAPI request object
+type CloneCreateRequest struct {
+	XMLName xml.Name `xml:"clone-create"`
+
+	DestinationPathPtr *string `xml:"destination-path"`
+	SnapshotNamePtr    *string `xml:"snapshot-name"`
+	SourcePathPtr      *string `xml:"source-path"`
+	SpaceReservePtr    *bool   `xml:"space-reserve"`
+	VolumePtr          *string `xml:"volume"`
+}
+
+// ToXML converts this object into an xml string representation
+func (o *CloneCreateRequest) ToXML() (string, error) {
+	output, err := xml.MarshalIndent(o, " ", "    ")
+	//if err != nil { log.Errorf("error: %v\n", err) }
+	return string(output), err
+}
+
+// NewCloneCreateRequest is a factory method for creating new instances of CloneCreateRequest objects
+func NewCloneCreateRequest() *CloneCreateRequest { return &CloneCreateRequest{} }
+
+// ExecuteUsing converts this object to a ZAPI XML representation and uses the supplied ZapiRunner to send to a filer
+func (o *CloneCreateRequest) ExecuteUsing(zr *ZapiRunner) (CloneCreateResponse, error) {
+
+	if zr.DebugTraceFlags["method"] {
+		fields := log.Fields{"Method": "ExecuteUsing", "Type": "CloneCreateRequest"}
+		log.WithFields(fields).Debug(">>>> ExecuteUsing")
+		defer log.WithFields(fields).Debug("<<<< ExecuteUsing")
+	}
+
+	resp, err := zr.SendZapi(o)
+	if err != nil {
+		log.Errorf("API invocation failed. %v", err.Error())
+		return CloneCreateResponse{}, err
+	}
+	defer resp.Body.Close()
+	body, readErr := ioutil.ReadAll(resp.Body)
+	if readErr != nil {
+		log.Errorf("Error reading response body. %v", readErr.Error())
+		return CloneCreateResponse{}, readErr
+	}
+	if zr.DebugTraceFlags["api"] {
+		log.Debugf("response Body:\n%s", string(body))
+	}
+
+	var n CloneCreateResponse
+	unmarshalErr := xml.Unmarshal(body, &n)
+	if unmarshalErr != nil {
+		log.WithField("body", string(body)).Warnf("Error unmarshaling response body. %v", unmarshalErr.Error())
+		//return CloneCreateResponse{}, unmarshalErr
+	}
+	if zr.DebugTraceFlags["api"] {
+		log.Debugf("clone-create result:\n%s", n.Result)
+	}
+
+	return n, nil
+}
+
+// String returns a string representation of this object's fields and implements the Stringer interface
+func (o CloneCreateRequest) String() string {
+	var buffer bytes.Buffer
+	if o.DestinationPathPtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "destination-path", *o.DestinationPathPtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("destination-path: nil\n"))
+	}
+	if o.SnapshotNamePtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "snapshot-name", *o.SnapshotNamePtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("snapshot-name: nil\n"))
+	}
+	if o.SourcePathPtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "source-path", *o.SourcePathPtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("source-path: nil\n"))
+	}
+	if o.SpaceReservePtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "space-reserve", *o.SpaceReservePtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("space-reserve: nil\n"))
+	}
+	if o.VolumePtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "volume", *o.VolumePtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("volume: nil\n"))
+	}
+	return buffer.String()
+}
+
+// DestinationPath is a fluent style 'getter' method that can be chained
+func (o *CloneCreateRequest) DestinationPath() string {
+	r := *o.DestinationPathPtr
+	return r
+}
+
+// SetDestinationPath is a fluent style 'setter' method that can be chained
+func (o *CloneCreateRequest) SetDestinationPath(newValue string) *CloneCreateRequest {
+	o.DestinationPathPtr = &newValue
+	return o
+}
+
+// SnapshotName is a fluent style 'getter' method that can be chained
+func (o *CloneCreateRequest) SnapshotName() string {
+	r := *o.SnapshotNamePtr
+	return r
+}
+
+// SetSnapshotName is a fluent style 'setter' method that can be chained
+func (o *CloneCreateRequest) SetSnapshotName(newValue string) *CloneCreateRequest {
+	o.SnapshotNamePtr = &newValue
+	return o
+}
+
+// SourcePath is a fluent style 'getter' method that can be chained
+func (o *CloneCreateRequest) SourcePath() string {
+	r := *o.SourcePathPtr
+	return r
+}
+
+// SetSourcePath is a fluent style 'setter' method that can be chained
+func (o *CloneCreateRequest) SetSourcePath(newValue string) *CloneCreateRequest {
+	o.SourcePathPtr = &newValue
+	return o
+}
+
+// SpaceReserve is a fluent style 'getter' method that can be chained
+func (o *CloneCreateRequest) SpaceReserve() bool {
+	r := *o.SpaceReservePtr
+	return r
+}
+
+// SetSpaceReserve is a fluent style 'setter' method that can be chained
+func (o *CloneCreateRequest) SetSpaceReserve(newValue bool) *CloneCreateRequest {
+	o.SpaceReservePtr = &newValue
+	return o
+}
+
+// Volume is a fluent style 'getter' method that can be chained
+func (o *CloneCreateRequest) Volume() string {
+	r := *o.VolumePtr
+	return r
+}
+
+// SetVolume is a fluent style 'setter' method that can be chained
+func (o *CloneCreateRequest) SetVolume(newValue string) *CloneCreateRequest {
+	o.VolumePtr = &newValue
+	return o
+}
+
+// CloneCreateResponse is a structure to represent a clone-create ZAPI response object
+type CloneCreateResponse struct {
+	XMLName xml.Name `xml:"netapp"`
+
+	ResponseVersion string `xml:"version,attr"`
+	ResponseXmlns   string `xml:"xmlns,attr"`
+
+	Result CloneCreateResponseResult `xml:"results"`
+}
+
+// String returns a string representation of this object's fields and implements the Stringer interface
+func (o CloneCreateResponse) String() string {
+	var buffer bytes.Buffer
+	buffer.WriteString(fmt.Sprintf("%s: %s\n", "version", o.ResponseVersion))
+	buffer.WriteString(fmt.Sprintf("%s: %s\n", "xmlns", o.ResponseXmlns))
+	buffer.WriteString(fmt.Sprintf("%s: %s\n", "results", o.Result))
+	return buffer.String()
+}
+
+// CloneCreateResponseResult is a structure to represent a clone-create ZAPI object's result
+type CloneCreateResponseResult struct {
+	XMLName xml.Name `xml:"results"`
+
+	ResultStatusAttr string `xml:"status,attr"`
+	ResultReasonAttr string `xml:"reason,attr"`
+	ResultErrnoAttr  string `xml:"errno,attr"`
+}
+
+// ToXML converts this object into an xml string representation
+func (o *CloneCreateResponse) ToXML() (string, error) {
+	output, err := xml.MarshalIndent(o, " ", "    ")
+	//if err != nil { log.Debugf("error: %v", err) }
+	return string(output), err
+}
+
+// NewCloneCreateResponse is a factory method for creating new instances of CloneCreateResponse objects
+func NewCloneCreateResponse() *CloneCreateResponse { return &CloneCreateResponse{} }
+
+// String returns a string representation of this object's fields and implements the Stringer interface
+func (o CloneCreateResponseResult) String() string {
+	var buffer bytes.Buffer
+	buffer.WriteString(fmt.Sprintf("%s: %s\n", "resultStatusAttr", o.ResultStatusAttr))
+	buffer.WriteString(fmt.Sprintf("%s: %s\n", "resultReasonAttr", o.ResultReasonAttr))
+	buffer.WriteString(fmt.Sprintf("%s: %s\n", "resultErrnoAttr", o.ResultErrnoAttr))
+	return buffer.String()
+}
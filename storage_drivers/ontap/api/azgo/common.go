@@ -5,10 +5,17 @@ package azgo
 import (
 	"bytes"
 	"crypto/tls"
+	"encoding/xml"
 	"fmt"
+	"io/ioutil"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	log "github.com/sirupsen/logrus"
+
+	"github.com/netapp/trident/metrics"
 )
 
 type ZAPIRequest interface {
@@ -73,11 +80,29 @@ func (o *ZapiRunner) SendZapi(r ZAPIRequest) (*http.Response, error) {
 	}
 
 	client := &http.Client{Transport: tr}
+	zapiName := getZapiName(zapiCommand)
+	start := time.Now()
 	resp, err := client.Do(req)
 	if err != nil {
+		metrics.ObserveStorageAPICall("ontap", zapiName, start, metrics.CodeTransportError)
 		return nil, err
 	}
 
+	// Read the whole response so that the call is timed to completion and
+	// any ZAPI error is counted, then hand the caller a fresh copy of it.
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		metrics.ObserveStorageAPICall("ontap", zapiName, start, metrics.CodeTransportError)
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if resp.StatusCode != http.StatusOK {
+		metrics.ObserveStorageAPICall("ontap", zapiName, start, strconv.Itoa(resp.StatusCode))
+	} else {
+		metrics.ObserveStorageAPICall("ontap", zapiName, start, getZapiErrno(body))
+	}
+
 	if o.DebugTraceFlags["api"] {
 		log.Debugf("response Status: %s", resp.Status)
 		log.Debugf("response Headers: %s", resp.Header)
@@ -85,3 +110,45 @@ func (o *ZapiRunner) SendZapi(r ZAPIRequest) (*http.Response, error) {
 
 	return resp, err
 }
+
+// getZapiName returns the name of the API invoked by a ZAPI command, which is
+// the name of its outermost element.
+func getZapiName(zapiCommand string) string {
+	name := strings.TrimSpace(zapiCommand)
+	name = strings.TrimPrefix(name, "<")
+	if end := strings.IndexAny(name, " \t\r\n/>"); end >= 0 {
+		name = name[:end]
+	}
+	return name
+}
+
+// getZapiErrno returns the error number from a ZAPI response, or an empty
+// string if the call passed or the response can't be parsed.
+func getZapiErrno(body []byte) string {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return ""
+		}
+		element, ok := token.(xml.StartElement)
+		if !ok || element.Name.Local != "results" {
+			continue
+		}
+		var status, errno string
+		for _, attr := range element.Attr {
+			switch attr.Name.Local {
+			case "status":
+				status = attr.Value
+			case "errno":
+				errno = attr.Value
+			}
+		}
+		if status == "passed" {
+			return ""
+		} else if errno == "" {
+			return status
+		}
+		return errno
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package azgo
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// SnapmirrorBreakRequest is a structure to represent a snapmirror-break ZAPI request object
+type SnapmirrorBreakRequest struct {
+	XMLName xml.Name `xml:"snapmirror-break"`
+
+	DestinationLocationPtr *string `xml:"destination-location"`
+}
+
+// ToXML converts this object into an xml string representation
+func (o *SnapmirrorBreakRequest) ToXML() (string, error) {
+	output, err := xml.MarshalIndent(o, " ", "    ")
+	//if err != nil { log.Errorf("error: %v\n", err) }
+	return string(output), err
+}
+
+// NewSnapmirrorBreakRequest is a factory method for creating new instances of SnapmirrorBreakRequest objects
+func NewSnapmirrorBreakRequest() *SnapmirrorBreakRequest { return &SnapmirrorBreakRequest{} }
+
+// ExecuteUsing converts this object to a ZAPI XML representation and uses the supplied ZapiRunner to send to a filer
+func (o *SnapmirrorBreakRequest) ExecuteUsing(zr *ZapiRunner) (SnapmirrorBreakResponse, error) {
+
+	if zr.DebugTraceFlags["method"] {
+		fields := log.Fields{"Method": "ExecuteUsing", "Type": "SnapmirrorBreakRequest"}
+		log.WithFields(fields).Debug(">>>> ExecuteUsing")
+		defer log.WithFields(fields).Debug("<<<< ExecuteUsing")
+	}
+
+	resp, err := zr.SendZapi(o)
+	if err != nil {
+		log.Errorf("API invocation failed. %v", err.Error())
+		return SnapmirrorBreakResponse{}, err
+	}
+	defer resp.Body.Close()
+	body, readErr := ioutil.ReadAll(resp.Body)
+	if readErr != nil {
+		log.Errorf("Error reading response body. %v", readErr.Error())
+		return SnapmirrorBreakResponse{}, readErr
+	}
+	if zr.DebugTraceFlags["api"] {
+		log.Debugf("response Body:\n%s", string(body))
+	}
+
+	var n SnapmirrorBreakResponse
+	unmarshalErr := xml.Unmarshal(body, &n)
+	if unmarshalErr != nil {
+		log.WithField("body", string(body)).Warnf("Error unmarshaling response body. %v", unmarshalErr.Error())
+		//return SnapmirrorBreakResponse{}, unmarshalErr
+	}
+	if zr.DebugTraceFlags["api"] {
+		log.Debugf("snapmirror-break result:\n%s", n.Result)
+	}
+
+	return n, nil
+}
+
+// String returns a string representation of this object's fields and implements the Stringer interface
+func (o SnapmirrorBreakRequest) String() string {
+	var buffer bytes.Buffer
+	if o.DestinationLocationPtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "destination-location", *o.DestinationLocationPtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("destination-location: nil\n"))
+	}
+	return buffer.String()
+}
+
+// DestinationLocation is a fluent style 'getter' method that can be chained
+func (o *SnapmirrorBreakRequest) DestinationLocation() string {
+	r := *o.DestinationLocationPtr
+	return r
+}
+
+// SetDestinationLocation is a fluent style 'setter' method that can be chained
+func (o *SnapmirrorBreakRequest) SetDestinationLocation(newValue string) *SnapmirrorBreakRequest {
+	o.DestinationLocationPtr = &newValue
+	return o
+}
+
+// SnapmirrorBreakResponse is a structure to represent a snapmirror-break ZAPI response object
+type SnapmirrorBreakResponse struct {
+	XMLName xml.Name `xml:"netapp"`
+
+	ResponseVersion string `xml:"version,attr"`
+	ResponseXmlns   string `xml:"xmlns,attr"`
+
+	Result SnapmirrorBreakResponseResult `xml:"results"`
+}
+
+// String returns a string representation of this object's fields and implements the Stringer interface
+func (o SnapmirrorBreakResponse) String() string {
+	var buffer bytes.Buffer
+	buffer.WriteString(fmt.Sprintf("%s: %s\n", "version", o.ResponseVersion))
+	buffer.WriteString(fmt.Sprintf("%s: %s\n", "xmlns", o.ResponseXmlns))
+	buffer.WriteString(fmt.Sprintf("%s: %s\n", "results", o.Result))
+	return buffer.String()
+}
+
+// SnapmirrorBreakResponseResult is a structure to represent a snapmirror-break ZAPI object's result
+type SnapmirrorBreakResponseResult struct {
+	XMLName xml.Name `xml:"results"`
+
+	ResultStatusAttr     string  `xml:"status,attr"`
+	ResultReasonAttr     string  `xml:"reason,attr"`
+	ResultErrnoAttr      string  `xml:"errno,attr"`
+	ResultOperationIdPtr *string `xml:"result-operation-id"`
+	ResultStatusPtr      *string `xml:"result-status"`
+}
+
+// ToXML converts this object into an xml string representation
+func (o *SnapmirrorBreakResponse) ToXML() (string, error) {
+	output, err := xml.MarshalIndent(o, " ", "    ")
+	//if err != nil { log.Debugf("error: %v", err) }
+	return string(output), err
+}
+
+// NewSnapmirrorBreakResponse is a factory method for creating new instances of SnapmirrorBreakResponse objects
+func NewSnapmirrorBreakResponse() *SnapmirrorBreakResponse { return &SnapmirrorBreakResponse{} }
+
+// String returns a string representation of this object's fields and implements the Stringer interface
+func (o SnapmirrorBreakResponseResult) String() string {
+	var buffer bytes.Buffer
+	buffer.WriteString(fmt.Sprintf("%s: %s\n", "resultStatusAttr", o.ResultStatusAttr))
+	buffer.WriteString(fmt.Sprintf("%s: %s\n", "resultReasonAttr", o.ResultReasonAttr))
+	buffer.WriteString(fmt.Sprintf("%s: %s\n", "resultErrnoAttr", o.ResultErrnoAttr))
+	if o.ResultOperationIdPtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "result-operation-id", *o.ResultOperationIdPtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("result-operation-id: nil\n"))
+	}
+	if o.ResultStatusPtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "result-status", *o.ResultStatusPtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("result-status: nil\n"))
+	}
+	return buffer.String()
+}
+
+// ResultOperationId is a fluent style 'getter' method that can be chained
+func (o *SnapmirrorBreakResponseResult) ResultOperationId() string {
+	r := *o.ResultOperationIdPtr
+	return r
+}
+
+// SetResultOperationId is a fluent style 'setter' method that can be chained
+func (o *SnapmirrorBreakResponseResult) SetResultOperationId(newValue string) *SnapmirrorBreakResponseResult {
+	o.ResultOperationIdPtr = &newValue
+	return o
+}
+
+// ResultStatus is a fluent style 'getter' method that can be chained
+func (o *SnapmirrorBreakResponseResult) ResultStatus() string {
+	r := *o.ResultStatusPtr
+	return r
+}
+
+// SetResultStatus is a fluent style 'setter' method that can be chained
+func (o *SnapmirrorBreakResponseResult) SetResultStatus(newValue string) *SnapmirrorBreakResponseResult {
+	o.ResultStatusPtr = &newValue
+	return o
+}
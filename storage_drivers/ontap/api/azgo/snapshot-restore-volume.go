@@ -0,0 +1,208 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package azgo
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// SnapshotRestoreVolumeRequest is a structure to represent a snapshot-restore-volume ZAPI request object
+type SnapshotRestoreVolumeRequest struct {
+	XMLName xml.Name `xml:"snapshot-restore-volume"`
+
+	ForcePtr                *bool   `xml:"force"`
+	PreserveLunIdsPtr       *bool   `xml:"preserve-lun-ids"`
+	SnapshotPtr             *string `xml:"snapshot"`
+	SnapshotInstanceUuidPtr *string `xml:"snapshot-instance-uuid"`
+	VolumePtr               *string `xml:"volume"`
+}
+
+// ToXML converts this object into an xml string representation
+func (o *SnapshotRestoreVolumeRequest) ToXML() (string, error) {
+	output, err := xml.MarshalIndent(o, " ", "    ")
+	//if err != nil { log.Errorf("error: %v\n", err) }
+	return string(output), err
+}
+
+// NewSnapshotRestoreVolumeRequest is a factory method for creating new instances of SnapshotRestoreVolumeRequest objects
+func NewSnapshotRestoreVolumeRequest() *SnapshotRestoreVolumeRequest { return &SnapshotRestoreVolumeRequest{} }
+
+// ExecuteUsing converts this object to a ZAPI XML representation and uses the supplied ZapiRunner to send to a filer
+func (o *SnapshotRestoreVolumeRequest) ExecuteUsing(zr *ZapiRunner) (SnapshotRestoreVolumeResponse, error) {
+
+	if zr.DebugTraceFlags["method"] {
+		fields := log.Fields{"Method": "ExecuteUsing", "Type": "SnapshotRestoreVolumeRequest"}
+		log.WithFields(fields).Debug(">>>> ExecuteUsing")
+		defer log.WithFields(fields).Debug("<<<< ExecuteUsing")
+	}
+
+	resp, err := zr.SendZapi(o)
+	if err != nil {
+		log.Errorf("API invocation failed. %v", err.Error())
+		return SnapshotRestoreVolumeResponse{}, err
+	}
+	defer resp.Body.Close()
+	body, readErr := ioutil.ReadAll(resp.Body)
+	if readErr != nil {
+		log.Errorf("Error reading response body. %v", readErr.Error())
+		return SnapshotRestoreVolumeResponse{}, readErr
+	}
+	if zr.DebugTraceFlags["api"] {
+		log.Debugf("response Body:\n%s", string(body))
+	}
+
+	var n SnapshotRestoreVolumeResponse
+	unmarshalErr := xml.Unmarshal(body, &n)
+	if unmarshalErr != nil {
+		log.WithField("body", string(body)).Warnf("Error unmarshaling response body. %v", unmarshalErr.Error())
+		//return SnapshotRestoreVolumeResponse{}, unmarshalErr
+	}
+	if zr.DebugTraceFlags["api"] {
+		log.Debugf("snapshot-restore-volume result:\n%s", n.Result)
+	}
+
+	return n, nil
+}
+
+// String returns a string representation of this object's fields and implements the Stringer interface
+func (o SnapshotRestoreVolumeRequest) String() string {
+	var buffer bytes.Buffer
+	if o.ForcePtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "force", *o.ForcePtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("force: nil\n"))
+	}
+	if o.PreserveLunIdsPtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "preserve-lun-ids", *o.PreserveLunIdsPtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("preserve-lun-ids: nil\n"))
+	}
+	if o.SnapshotPtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "snapshot", *o.SnapshotPtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("snapshot: nil\n"))
+	}
+	if o.SnapshotInstanceUuidPtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "snapshot-instance-uuid", *o.SnapshotInstanceUuidPtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("snapshot-instance-uuid: nil\n"))
+	}
+	if o.VolumePtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "volume", *o.VolumePtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("volume: nil\n"))
+	}
+	return buffer.String()
+}
+
+// Force is a fluent style 'getter' method that can be chained
+func (o *SnapshotRestoreVolumeRequest) Force() bool {
+	r := *o.ForcePtr
+	return r
+}
+
+// SetForce is a fluent style 'setter' method that can be chained
+func (o *SnapshotRestoreVolumeRequest) SetForce(newValue bool) *SnapshotRestoreVolumeRequest {
+	o.ForcePtr = &newValue
+	return o
+}
+
+// PreserveLunIds is a fluent style 'getter' method that can be chained
+func (o *SnapshotRestoreVolumeRequest) PreserveLunIds() bool {
+	r := *o.PreserveLunIdsPtr
+	return r
+}
+
+// SetPreserveLunIds is a fluent style 'setter' method that can be chained
+func (o *SnapshotRestoreVolumeRequest) SetPreserveLunIds(newValue bool) *SnapshotRestoreVolumeRequest {
+	o.PreserveLunIdsPtr = &newValue
+	return o
+}
+
+// Snapshot is a fluent style 'getter' method that can be chained
+func (o *SnapshotRestoreVolumeRequest) Snapshot() string {
+	r := *o.SnapshotPtr
+	return r
+}
+
+// SetSnapshot is a fluent style 'setter' method that can be chained
+func (o *SnapshotRestoreVolumeRequest) SetSnapshot(newValue string) *SnapshotRestoreVolumeRequest {
+	o.SnapshotPtr = &newValue
+	return o
+}
+
+// SnapshotInstanceUuid is a fluent style 'getter' method that can be chained
+func (o *SnapshotRestoreVolumeRequest) SnapshotInstanceUuid() string {
+	r := *o.SnapshotInstanceUuidPtr
+	return r
+}
+
+// SetSnapshotInstanceUuid is a fluent style 'setter' method that can be chained
+func (o *SnapshotRestoreVolumeRequest) SetSnapshotInstanceUuid(newValue string) *SnapshotRestoreVolumeRequest {
+	o.SnapshotInstanceUuidPtr = &newValue
+	return o
+}
+
+// Volume is a fluent style 'getter' method that can be chained
+func (o *SnapshotRestoreVolumeRequest) Volume() string {
+	r := *o.VolumePtr
+	return r
+}
+
+// SetVolume is a fluent style 'setter' method that can be chained
+func (o *SnapshotRestoreVolumeRequest) SetVolume(newValue string) *SnapshotRestoreVolumeRequest {
+	o.VolumePtr = &newValue
+	return o
+}
+
+// SnapshotRestoreVolumeResponse is a structure to represent a snapshot-restore-volume ZAPI response object
+type SnapshotRestoreVolumeResponse struct {
+	XMLName xml.Name `xml:"netapp"`
+
+	ResponseVersion string `xml:"version,attr"`
+	ResponseXmlns   string `xml:"xmlns,attr"`
+
+	Result SnapshotRestoreVolumeResponseResult `xml:"results"`
+}
+
+// String returns a string representation of this object's fields and implements the Stringer interface
+func (o SnapshotRestoreVolumeResponse) String() string {
+	var buffer bytes.Buffer
+	buffer.WriteString(fmt.Sprintf("%s: %s\n", "version", o.ResponseVersion))
+	buffer.WriteString(fmt.Sprintf("%s: %s\n", "xmlns", o.ResponseXmlns))
+	buffer.WriteString(fmt.Sprintf("%s: %s\n", "results", o.Result))
+	return buffer.String()
+}
+
+// SnapshotRestoreVolumeResponseResult is a structure to represent a snapshot-restore-volume ZAPI object's result
+type SnapshotRestoreVolumeResponseResult struct {
+	XMLName xml.Name `xml:"results"`
+
+	ResultStatusAttr string `xml:"status,attr"`
+	ResultReasonAttr string `xml:"reason,attr"`
+	ResultErrnoAttr  string `xml:"errno,attr"`
+}
+
+// ToXML converts this object into an xml string representation
+func (o *SnapshotRestoreVolumeResponse) ToXML() (string, error) {
+	output, err := xml.MarshalIndent(o, " ", "    ")
+	//if err != nil { log.Debugf("error: %v", err) }
+	return string(output), err
+}
+
+// NewSnapshotRestoreVolumeResponse is a factory method for creating new instances of SnapshotRestoreVolumeResponse objects
+func NewSnapshotRestoreVolumeResponse() *SnapshotRestoreVolumeResponse { return &SnapshotRestoreVolumeResponse{} }
+
+// String returns a string representation of this object's fields and implements the Stringer interface
+func (o SnapshotRestoreVolumeResponseResult) String() string {
+	var buffer bytes.Buffer
+	buffer.WriteString(fmt.Sprintf("%s: %s\n", "resultStatusAttr", o.ResultStatusAttr))
+	buffer.WriteString(fmt.Sprintf("%s: %s\n", "resultReasonAttr", o.ResultReasonAttr))
+	buffer.WriteString(fmt.Sprintf("%s: %s\n", "resultErrnoAttr", o.ResultErrnoAttr))
+	return buffer.String()
+}
@@ -709,9 +709,10 @@ func (o *ShowAggregatesType) SetVserverName(newValue string) *ShowAggregatesType
 }
 
 type AggrAttributesType struct {
-	XMLName               xml.Name                `xml:"aggr-attributes"`
-	AggrRaidAttributesPtr *AggrRaidAttributesType `xml:"aggr-raid-attributes"`
-	AggregateNamePtr      *string                 `xml:"aggregate-name"`
+	XMLName                xml.Name                 `xml:"aggr-attributes"`
+	AggrRaidAttributesPtr  *AggrRaidAttributesType  `xml:"aggr-raid-attributes"`
+	AggrSpaceAttributesPtr *AggrSpaceAttributesType `xml:"aggr-space-attributes"`
+	AggregateNamePtr       *string                  `xml:"aggregate-name"`
 }
 
 func (o *AggrAttributesType) AggrRaidAttributes() AggrRaidAttributesType {
@@ -719,6 +720,11 @@ func (o *AggrAttributesType) AggrRaidAttributes() AggrRaidAttributesType {
 	return r
 }
 
+func (o *AggrAttributesType) AggrSpaceAttributes() AggrSpaceAttributesType {
+	r := *o.AggrSpaceAttributesPtr
+	return r
+}
+
 func (o *AggrAttributesType) AggregateName() string {
 	r := *o.AggregateNamePtr
 	return r
@@ -739,6 +745,21 @@ func (o *AggrRaidAttributesType) RaidType() string {
 	return r
 }
 
+type AggrSpaceAttributesType struct {
+	SizeAvailablePtr *int `xml:"size-available"`
+	SizeTotalPtr     *int `xml:"size-total"`
+}
+
+func (o *AggrSpaceAttributesType) SizeAvailable() int {
+	r := *o.SizeAvailablePtr
+	return r
+}
+
+func (o *AggrSpaceAttributesType) SizeTotal() int {
+	r := *o.SizeTotalPtr
+	return r
+}
+
 type VolumeModifyIterInfoType struct {
 	XMLName xml.Name `xml:"volume-modify-iter-info"`
 
@@ -2186,7 +2207,8 @@ func (o *VolumeSecurityAttributesType) SetVolumeSecurityUnixAttributes(newValue
 type VolumeQosAttributesType struct {
 	XMLName xml.Name `xml:"volume-qos-attributes"`
 
-	PolicyGroupNamePtr *string `xml:"policy-group-name"`
+	AdaptivePolicyGroupNamePtr *string `xml:"adaptive-policy-group-name"`
+	PolicyGroupNamePtr         *string `xml:"policy-group-name"`
 }
 
 func (o *VolumeQosAttributesType) ToXML() (string, error) {
@@ -2201,6 +2223,11 @@ func NewVolumeQosAttributesType() *VolumeQosAttributesType { return &VolumeQosAt
 
 func (o VolumeQosAttributesType) String() string {
 	var buffer bytes.Buffer
+	if o.AdaptivePolicyGroupNamePtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "adaptive-policy-group-name", *o.AdaptivePolicyGroupNamePtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("adaptive-policy-group-name: nil\n"))
+	}
 	if o.PolicyGroupNamePtr != nil {
 		buffer.WriteString(fmt.Sprintf("%s: %v\n", "policy-group-name", *o.PolicyGroupNamePtr))
 	} else {
@@ -2209,6 +2236,16 @@ func (o VolumeQosAttributesType) String() string {
 	return buffer.String()
 }
 
+func (o *VolumeQosAttributesType) AdaptivePolicyGroupName() string {
+	r := *o.AdaptivePolicyGroupNamePtr
+	return r
+}
+
+func (o *VolumeQosAttributesType) SetAdaptivePolicyGroupName(newValue string) *VolumeQosAttributesType {
+	o.AdaptivePolicyGroupNamePtr = &newValue
+	return o
+}
+
 func (o *VolumeQosAttributesType) PolicyGroupName() string {
 	r := *o.PolicyGroupNamePtr
 	return r
@@ -10011,3 +10048,437 @@ func (o *LunInfoType) SetVserver(newValue string) *LunInfoType {
 	o.VserverPtr = &newValue
 	return o
 }
+
+// QosPolicyGroupInfoType is a structure to represent a qos-policy-group-info ZAPI object
+type QosPolicyGroupInfoType struct {
+	XMLName xml.Name `xml:"qos-policy-group-info"`
+
+	MaxThroughputPtr    *string `xml:"max-throughput"`
+	MinThroughputPtr    *string `xml:"min-throughput"`
+	NumWorkloadsPtr     *int    `xml:"num-workloads"`
+	PgidPtr             *int    `xml:"pgid"`
+	PolicyGroupPtr      *string `xml:"policy-group"`
+	PolicyGroupClassPtr *string `xml:"policy-group-class"`
+	UuidPtr             *string `xml:"uuid"`
+	VserverPtr          *string `xml:"vserver"`
+}
+
+// ToXML converts this object into an xml string representation
+func (o *QosPolicyGroupInfoType) ToXML() (string, error) {
+	output, err := xml.MarshalIndent(o, " ", "    ")
+	if err != nil {
+		log.Errorf("error: %v", err)
+	}
+	return string(output), err
+}
+
+// NewQosPolicyGroupInfoType is a factory method for creating new instances of QosPolicyGroupInfoType objects
+func NewQosPolicyGroupInfoType() *QosPolicyGroupInfoType { return &QosPolicyGroupInfoType{} }
+
+// String returns a string representation of this object's fields and implements the Stringer interface
+func (o QosPolicyGroupInfoType) String() string {
+	var buffer bytes.Buffer
+	if o.MaxThroughputPtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "max-throughput", *o.MaxThroughputPtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("max-throughput: nil\n"))
+	}
+	if o.MinThroughputPtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "min-throughput", *o.MinThroughputPtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("min-throughput: nil\n"))
+	}
+	if o.NumWorkloadsPtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "num-workloads", *o.NumWorkloadsPtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("num-workloads: nil\n"))
+	}
+	if o.PgidPtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "pgid", *o.PgidPtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("pgid: nil\n"))
+	}
+	if o.PolicyGroupPtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "policy-group", *o.PolicyGroupPtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("policy-group: nil\n"))
+	}
+	if o.PolicyGroupClassPtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "policy-group-class", *o.PolicyGroupClassPtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("policy-group-class: nil\n"))
+	}
+	if o.UuidPtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "uuid", *o.UuidPtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("uuid: nil\n"))
+	}
+	if o.VserverPtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "vserver", *o.VserverPtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("vserver: nil\n"))
+	}
+	return buffer.String()
+}
+
+// MaxThroughput is a fluent style 'getter' method that can be chained
+func (o *QosPolicyGroupInfoType) MaxThroughput() string {
+	r := *o.MaxThroughputPtr
+	return r
+}
+
+// SetMaxThroughput is a fluent style 'setter' method that can be chained
+func (o *QosPolicyGroupInfoType) SetMaxThroughput(newValue string) *QosPolicyGroupInfoType {
+	o.MaxThroughputPtr = &newValue
+	return o
+}
+
+// MinThroughput is a fluent style 'getter' method that can be chained
+func (o *QosPolicyGroupInfoType) MinThroughput() string {
+	r := *o.MinThroughputPtr
+	return r
+}
+
+// SetMinThroughput is a fluent style 'setter' method that can be chained
+func (o *QosPolicyGroupInfoType) SetMinThroughput(newValue string) *QosPolicyGroupInfoType {
+	o.MinThroughputPtr = &newValue
+	return o
+}
+
+// NumWorkloads is a fluent style 'getter' method that can be chained
+func (o *QosPolicyGroupInfoType) NumWorkloads() int {
+	r := *o.NumWorkloadsPtr
+	return r
+}
+
+// SetNumWorkloads is a fluent style 'setter' method that can be chained
+func (o *QosPolicyGroupInfoType) SetNumWorkloads(newValue int) *QosPolicyGroupInfoType {
+	o.NumWorkloadsPtr = &newValue
+	return o
+}
+
+// Pgid is a fluent style 'getter' method that can be chained
+func (o *QosPolicyGroupInfoType) Pgid() int {
+	r := *o.PgidPtr
+	return r
+}
+
+// SetPgid is a fluent style 'setter' method that can be chained
+func (o *QosPolicyGroupInfoType) SetPgid(newValue int) *QosPolicyGroupInfoType {
+	o.PgidPtr = &newValue
+	return o
+}
+
+// PolicyGroup is a fluent style 'getter' method that can be chained
+func (o *QosPolicyGroupInfoType) PolicyGroup() string {
+	r := *o.PolicyGroupPtr
+	return r
+}
+
+// SetPolicyGroup is a fluent style 'setter' method that can be chained
+func (o *QosPolicyGroupInfoType) SetPolicyGroup(newValue string) *QosPolicyGroupInfoType {
+	o.PolicyGroupPtr = &newValue
+	return o
+}
+
+// PolicyGroupClass is a fluent style 'getter' method that can be chained
+func (o *QosPolicyGroupInfoType) PolicyGroupClass() string {
+	r := *o.PolicyGroupClassPtr
+	return r
+}
+
+// SetPolicyGroupClass is a fluent style 'setter' method that can be chained
+func (o *QosPolicyGroupInfoType) SetPolicyGroupClass(newValue string) *QosPolicyGroupInfoType {
+	o.PolicyGroupClassPtr = &newValue
+	return o
+}
+
+// Uuid is a fluent style 'getter' method that can be chained
+func (o *QosPolicyGroupInfoType) Uuid() string {
+	r := *o.UuidPtr
+	return r
+}
+
+// SetUuid is a fluent style 'setter' method that can be chained
+func (o *QosPolicyGroupInfoType) SetUuid(newValue string) *QosPolicyGroupInfoType {
+	o.UuidPtr = &newValue
+	return o
+}
+
+// Vserver is a fluent style 'getter' method that can be chained
+func (o *QosPolicyGroupInfoType) Vserver() string {
+	r := *o.VserverPtr
+	return r
+}
+
+// SetVserver is a fluent style 'setter' method that can be chained
+func (o *QosPolicyGroupInfoType) SetVserver(newValue string) *QosPolicyGroupInfoType {
+	o.VserverPtr = &newValue
+	return o
+}
+
+// VolumeModifyIterAsyncInfoType is a structure to represent a volume-modify-iter-async-info ZAPI object
+type VolumeModifyIterAsyncInfoType struct {
+	XMLName xml.Name `xml:"volume-modify-iter-async-info"`
+
+	ErrorCodePtr    *int                  `xml:"error-code"`
+	ErrorMessagePtr *string               `xml:"error-message"`
+	JobidPtr        *int                  `xml:"jobid"`
+	StatusPtr       *string               `xml:"status"`
+	VolumeKeyPtr    *VolumeAttributesType `xml:"volume-key"`
+}
+
+// ToXML converts this object into an xml string representation
+func (o *VolumeModifyIterAsyncInfoType) ToXML() (string, error) {
+	output, err := xml.MarshalIndent(o, " ", "    ")
+	if err != nil {
+		log.Errorf("error: %v", err)
+	}
+	return string(output), err
+}
+
+// NewVolumeModifyIterAsyncInfoType is a factory method for creating new instances of VolumeModifyIterAsyncInfoType objects
+func NewVolumeModifyIterAsyncInfoType() *VolumeModifyIterAsyncInfoType {
+	return &VolumeModifyIterAsyncInfoType{}
+}
+
+// String returns a string representation of this object's fields and implements the Stringer interface
+func (o VolumeModifyIterAsyncInfoType) String() string {
+	var buffer bytes.Buffer
+	if o.ErrorCodePtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "error-code", *o.ErrorCodePtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("error-code: nil\n"))
+	}
+	if o.ErrorMessagePtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "error-message", *o.ErrorMessagePtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("error-message: nil\n"))
+	}
+	if o.JobidPtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "jobid", *o.JobidPtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("jobid: nil\n"))
+	}
+	if o.StatusPtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "status", *o.StatusPtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("status: nil\n"))
+	}
+	if o.VolumeKeyPtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "volume-key", *o.VolumeKeyPtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("volume-key: nil\n"))
+	}
+	return buffer.String()
+}
+
+// ErrorCode is a fluent style 'getter' method that can be chained
+func (o *VolumeModifyIterAsyncInfoType) ErrorCode() int {
+	r := *o.ErrorCodePtr
+	return r
+}
+
+// SetErrorCode is a fluent style 'setter' method that can be chained
+func (o *VolumeModifyIterAsyncInfoType) SetErrorCode(newValue int) *VolumeModifyIterAsyncInfoType {
+	o.ErrorCodePtr = &newValue
+	return o
+}
+
+// ErrorMessage is a fluent style 'getter' method that can be chained
+func (o *VolumeModifyIterAsyncInfoType) ErrorMessage() string {
+	r := *o.ErrorMessagePtr
+	return r
+}
+
+// SetErrorMessage is a fluent style 'setter' method that can be chained
+func (o *VolumeModifyIterAsyncInfoType) SetErrorMessage(newValue string) *VolumeModifyIterAsyncInfoType {
+	o.ErrorMessagePtr = &newValue
+	return o
+}
+
+// Jobid is a fluent style 'getter' method that can be chained
+func (o *VolumeModifyIterAsyncInfoType) Jobid() int {
+	r := *o.JobidPtr
+	return r
+}
+
+// SetJobid is a fluent style 'setter' method that can be chained
+func (o *VolumeModifyIterAsyncInfoType) SetJobid(newValue int) *VolumeModifyIterAsyncInfoType {
+	o.JobidPtr = &newValue
+	return o
+}
+
+// Status is a fluent style 'getter' method that can be chained
+func (o *VolumeModifyIterAsyncInfoType) Status() string {
+	r := *o.StatusPtr
+	return r
+}
+
+// SetStatus is a fluent style 'setter' method that can be chained
+func (o *VolumeModifyIterAsyncInfoType) SetStatus(newValue string) *VolumeModifyIterAsyncInfoType {
+	o.StatusPtr = &newValue
+	return o
+}
+
+// VolumeKey is a fluent style 'getter' method that can be chained
+func (o *VolumeModifyIterAsyncInfoType) VolumeKey() VolumeAttributesType {
+	r := *o.VolumeKeyPtr
+	return r
+}
+
+// SetVolumeKey is a fluent style 'setter' method that can be chained
+func (o *VolumeModifyIterAsyncInfoType) SetVolumeKey(newValue VolumeAttributesType) *VolumeModifyIterAsyncInfoType {
+	o.VolumeKeyPtr = &newValue
+	return o
+}
+
+// JobInfoType is a structure to represent a job-info ZAPI object
+type JobInfoType struct {
+	XMLName xml.Name `xml:"job-info"`
+
+	JobCompletionPtr  *string `xml:"job-completion"`
+	JobDescriptionPtr *string `xml:"job-description"`
+	JobIdPtr          *int    `xml:"job-id"`
+	JobNamePtr        *string `xml:"job-name"`
+	JobNodePtr        *string `xml:"job-node"`
+	JobStatePtr       *string `xml:"job-state"`
+	JobVserverPtr     *string `xml:"job-vserver"`
+}
+
+// ToXML converts this object into an xml string representation
+func (o *JobInfoType) ToXML() (string, error) {
+	output, err := xml.MarshalIndent(o, " ", "    ")
+	if err != nil {
+		log.Errorf("error: %v", err)
+	}
+	return string(output), err
+}
+
+// NewJobInfoType is a factory method for creating new instances of JobInfoType objects
+func NewJobInfoType() *JobInfoType { return &JobInfoType{} }
+
+// String returns a string representation of this object's fields and implements the Stringer interface
+func (o JobInfoType) String() string {
+	var buffer bytes.Buffer
+	if o.JobCompletionPtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "job-completion", *o.JobCompletionPtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("job-completion: nil\n"))
+	}
+	if o.JobDescriptionPtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "job-description", *o.JobDescriptionPtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("job-description: nil\n"))
+	}
+	if o.JobIdPtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "job-id", *o.JobIdPtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("job-id: nil\n"))
+	}
+	if o.JobNamePtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "job-name", *o.JobNamePtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("job-name: nil\n"))
+	}
+	if o.JobNodePtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "job-node", *o.JobNodePtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("job-node: nil\n"))
+	}
+	if o.JobStatePtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "job-state", *o.JobStatePtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("job-state: nil\n"))
+	}
+	if o.JobVserverPtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "job-vserver", *o.JobVserverPtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("job-vserver: nil\n"))
+	}
+	return buffer.String()
+}
+
+// JobCompletion is a fluent style 'getter' method that can be chained
+func (o *JobInfoType) JobCompletion() string {
+	r := *o.JobCompletionPtr
+	return r
+}
+
+// SetJobCompletion is a fluent style 'setter' method that can be chained
+func (o *JobInfoType) SetJobCompletion(newValue string) *JobInfoType {
+	o.JobCompletionPtr = &newValue
+	return o
+}
+
+// JobDescription is a fluent style 'getter' method that can be chained
+func (o *JobInfoType) JobDescription() string {
+	r := *o.JobDescriptionPtr
+	return r
+}
+
+// SetJobDescription is a fluent style 'setter' method that can be chained
+func (o *JobInfoType) SetJobDescription(newValue string) *JobInfoType {
+	o.JobDescriptionPtr = &newValue
+	return o
+}
+
+// JobId is a fluent style 'getter' method that can be chained
+func (o *JobInfoType) JobId() int {
+	r := *o.JobIdPtr
+	return r
+}
+
+// SetJobId is a fluent style 'setter' method that can be chained
+func (o *JobInfoType) SetJobId(newValue int) *JobInfoType {
+	o.JobIdPtr = &newValue
+	return o
+}
+
+// JobName is a fluent style 'getter' method that can be chained
+func (o *JobInfoType) JobName() string {
+	r := *o.JobNamePtr
+	return r
+}
+
+// SetJobName is a fluent style 'setter' method that can be chained
+func (o *JobInfoType) SetJobName(newValue string) *JobInfoType {
+	o.JobNamePtr = &newValue
+	return o
+}
+
+// JobNode is a fluent style 'getter' method that can be chained
+func (o *JobInfoType) JobNode() string {
+	r := *o.JobNodePtr
+	return r
+}
+
+// SetJobNode is a fluent style 'setter' method that can be chained
+func (o *JobInfoType) SetJobNode(newValue string) *JobInfoType {
+	o.JobNodePtr = &newValue
+	return o
+}
+
+// JobState is a fluent style 'getter' method that can be chained
+func (o *JobInfoType) JobState() string {
+	r := *o.JobStatePtr
+	return r
+}
+
+// SetJobState is a fluent style 'setter' method that can be chained
+func (o *JobInfoType) SetJobState(newValue string) *JobInfoType {
+	o.JobStatePtr = &newValue
+	return o
+}
+
+// JobVserver is a fluent style 'getter' method that can be chained
+func (o *JobInfoType) JobVserver() string {
+	r := *o.JobVserverPtr
+	return r
+}
+
+// SetJobVserver is a fluent style 'setter' method that can be chained
+func (o *JobInfoType) SetJobVserver(newValue string) *JobInfoType {
+	o.JobVserverPtr = &newValue
+	return o
+}
@@ -0,0 +1,426 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package azgo
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// VolumeCreateAsyncRequest is a structure to represent a volume-create-async ZAPI request object
+type VolumeCreateAsyncRequest struct {
+	XMLName xml.Name `xml:"volume-create-async"`
+
+	AggrListPtr                   []AggrNameType `xml:"aggr-list>aggr-name"`
+	AggrListMultiplierPtr         *int           `xml:"aggr-list-multiplier"`
+	EncryptPtr                    *bool          `xml:"encrypt"`
+	ExportPolicyPtr               *string        `xml:"export-policy"`
+	JunctionPathPtr               *string        `xml:"junction-path"`
+	QosAdaptivePolicyGroupNamePtr *string        `xml:"qos-adaptive-policy-group-name"`
+	QosPolicyGroupNamePtr         *string        `xml:"qos-policy-group-name"`
+	SizePtr                       *int           `xml:"size"`
+	SnapshotPolicyPtr             *string        `xml:"snapshot-policy"`
+	SpaceReservePtr               *string        `xml:"space-reserve"`
+	UnixPermissionsPtr            *string        `xml:"unix-permissions"`
+	VolumeNamePtr                 *string        `xml:"volume-name"`
+	VolumeSecurityStylePtr        *string        `xml:"volume-security-style"`
+}
+
+// ToXML converts this object into an xml string representation
+func (o *VolumeCreateAsyncRequest) ToXML() (string, error) {
+	output, err := xml.MarshalIndent(o, " ", "    ")
+	//if err != nil { log.Errorf("error: %v\n", err) }
+	return string(output), err
+}
+
+// NewVolumeCreateAsyncRequest is a factory method for creating new instances of VolumeCreateAsyncRequest objects
+func NewVolumeCreateAsyncRequest() *VolumeCreateAsyncRequest { return &VolumeCreateAsyncRequest{} }
+
+// ExecuteUsing converts this object to a ZAPI XML representation and uses the supplied ZapiRunner to send to a filer
+func (o *VolumeCreateAsyncRequest) ExecuteUsing(zr *ZapiRunner) (VolumeCreateAsyncResponse, error) {
+
+	if zr.DebugTraceFlags["method"] {
+		fields := log.Fields{"Method": "ExecuteUsing", "Type": "VolumeCreateAsyncRequest"}
+		log.WithFields(fields).Debug(">>>> ExecuteUsing")
+		defer log.WithFields(fields).Debug("<<<< ExecuteUsing")
+	}
+
+	resp, err := zr.SendZapi(o)
+	if err != nil {
+		log.Errorf("API invocation failed. %v", err.Error())
+		return VolumeCreateAsyncResponse{}, err
+	}
+	defer resp.Body.Close()
+	body, readErr := ioutil.ReadAll(resp.Body)
+	if readErr != nil {
+		log.Errorf("Error reading response body. %v", readErr.Error())
+		return VolumeCreateAsyncResponse{}, readErr
+	}
+	if zr.DebugTraceFlags["api"] {
+		log.Debugf("response Body:\n%s", string(body))
+	}
+
+	var n VolumeCreateAsyncResponse
+	unmarshalErr := xml.Unmarshal(body, &n)
+	if unmarshalErr != nil {
+		log.WithField("body", string(body)).Warnf("Error unmarshaling response body. %v", unmarshalErr.Error())
+		//return VolumeCreateAsyncResponse{}, unmarshalErr
+	}
+	if zr.DebugTraceFlags["api"] {
+		log.Debugf("volume-create-async result:\n%s", n.Result)
+	}
+
+	return n, nil
+}
+
+// String returns a string representation of this object's fields and implements the Stringer interface
+func (o VolumeCreateAsyncRequest) String() string {
+	var buffer bytes.Buffer
+	if o.AggrListPtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "aggr-list", o.AggrListPtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("aggr-list: nil\n"))
+	}
+	if o.AggrListMultiplierPtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "aggr-list-multiplier", *o.AggrListMultiplierPtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("aggr-list-multiplier: nil\n"))
+	}
+	if o.EncryptPtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "encrypt", *o.EncryptPtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("encrypt: nil\n"))
+	}
+	if o.ExportPolicyPtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "export-policy", *o.ExportPolicyPtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("export-policy: nil\n"))
+	}
+	if o.JunctionPathPtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "junction-path", *o.JunctionPathPtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("junction-path: nil\n"))
+	}
+	if o.QosAdaptivePolicyGroupNamePtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "qos-adaptive-policy-group-name", *o.QosAdaptivePolicyGroupNamePtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("qos-adaptive-policy-group-name: nil\n"))
+	}
+	if o.QosPolicyGroupNamePtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "qos-policy-group-name", *o.QosPolicyGroupNamePtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("qos-policy-group-name: nil\n"))
+	}
+	if o.SizePtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "size", *o.SizePtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("size: nil\n"))
+	}
+	if o.SnapshotPolicyPtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "snapshot-policy", *o.SnapshotPolicyPtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("snapshot-policy: nil\n"))
+	}
+	if o.SpaceReservePtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "space-reserve", *o.SpaceReservePtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("space-reserve: nil\n"))
+	}
+	if o.UnixPermissionsPtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "unix-permissions", *o.UnixPermissionsPtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("unix-permissions: nil\n"))
+	}
+	if o.VolumeNamePtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "volume-name", *o.VolumeNamePtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("volume-name: nil\n"))
+	}
+	if o.VolumeSecurityStylePtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "volume-security-style", *o.VolumeSecurityStylePtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("volume-security-style: nil\n"))
+	}
+	return buffer.String()
+}
+
+// AggrList is a fluent style 'getter' method that can be chained
+func (o *VolumeCreateAsyncRequest) AggrList() []AggrNameType {
+	r := o.AggrListPtr
+	return r
+}
+
+// SetAggrList is a fluent style 'setter' method that can be chained
+func (o *VolumeCreateAsyncRequest) SetAggrList(newValue []AggrNameType) *VolumeCreateAsyncRequest {
+	newSlice := make([]AggrNameType, len(newValue))
+	copy(newSlice, newValue)
+	o.AggrListPtr = newSlice
+	return o
+}
+
+// AggrListMultiplier is a fluent style 'getter' method that can be chained
+func (o *VolumeCreateAsyncRequest) AggrListMultiplier() int {
+	r := *o.AggrListMultiplierPtr
+	return r
+}
+
+// SetAggrListMultiplier is a fluent style 'setter' method that can be chained
+func (o *VolumeCreateAsyncRequest) SetAggrListMultiplier(newValue int) *VolumeCreateAsyncRequest {
+	o.AggrListMultiplierPtr = &newValue
+	return o
+}
+
+// Encrypt is a fluent style 'getter' method that can be chained
+func (o *VolumeCreateAsyncRequest) Encrypt() bool {
+	r := *o.EncryptPtr
+	return r
+}
+
+// SetEncrypt is a fluent style 'setter' method that can be chained
+func (o *VolumeCreateAsyncRequest) SetEncrypt(newValue bool) *VolumeCreateAsyncRequest {
+	o.EncryptPtr = &newValue
+	return o
+}
+
+// ExportPolicy is a fluent style 'getter' method that can be chained
+func (o *VolumeCreateAsyncRequest) ExportPolicy() string {
+	r := *o.ExportPolicyPtr
+	return r
+}
+
+// SetExportPolicy is a fluent style 'setter' method that can be chained
+func (o *VolumeCreateAsyncRequest) SetExportPolicy(newValue string) *VolumeCreateAsyncRequest {
+	o.ExportPolicyPtr = &newValue
+	return o
+}
+
+// JunctionPath is a fluent style 'getter' method that can be chained
+func (o *VolumeCreateAsyncRequest) JunctionPath() string {
+	r := *o.JunctionPathPtr
+	return r
+}
+
+// SetJunctionPath is a fluent style 'setter' method that can be chained
+func (o *VolumeCreateAsyncRequest) SetJunctionPath(newValue string) *VolumeCreateAsyncRequest {
+	o.JunctionPathPtr = &newValue
+	return o
+}
+
+// QosAdaptivePolicyGroupName is a fluent style 'getter' method that can be chained
+func (o *VolumeCreateAsyncRequest) QosAdaptivePolicyGroupName() string {
+	r := *o.QosAdaptivePolicyGroupNamePtr
+	return r
+}
+
+// SetQosAdaptivePolicyGroupName is a fluent style 'setter' method that can be chained
+func (o *VolumeCreateAsyncRequest) SetQosAdaptivePolicyGroupName(newValue string) *VolumeCreateAsyncRequest {
+	o.QosAdaptivePolicyGroupNamePtr = &newValue
+	return o
+}
+
+// QosPolicyGroupName is a fluent style 'getter' method that can be chained
+func (o *VolumeCreateAsyncRequest) QosPolicyGroupName() string {
+	r := *o.QosPolicyGroupNamePtr
+	return r
+}
+
+// SetQosPolicyGroupName is a fluent style 'setter' method that can be chained
+func (o *VolumeCreateAsyncRequest) SetQosPolicyGroupName(newValue string) *VolumeCreateAsyncRequest {
+	o.QosPolicyGroupNamePtr = &newValue
+	return o
+}
+
+// Size is a fluent style 'getter' method that can be chained
+func (o *VolumeCreateAsyncRequest) Size() int {
+	r := *o.SizePtr
+	return r
+}
+
+// SetSize is a fluent style 'setter' method that can be chained
+func (o *VolumeCreateAsyncRequest) SetSize(newValue int) *VolumeCreateAsyncRequest {
+	o.SizePtr = &newValue
+	return o
+}
+
+// SnapshotPolicy is a fluent style 'getter' method that can be chained
+func (o *VolumeCreateAsyncRequest) SnapshotPolicy() string {
+	r := *o.SnapshotPolicyPtr
+	return r
+}
+
+// SetSnapshotPolicy is a fluent style 'setter' method that can be chained
+func (o *VolumeCreateAsyncRequest) SetSnapshotPolicy(newValue string) *VolumeCreateAsyncRequest {
+	o.SnapshotPolicyPtr = &newValue
+	return o
+}
+
+// SpaceReserve is a fluent style 'getter' method that can be chained
+func (o *VolumeCreateAsyncRequest) SpaceReserve() string {
+	r := *o.SpaceReservePtr
+	return r
+}
+
+// SetSpaceReserve is a fluent style 'setter' method that can be chained
+func (o *VolumeCreateAsyncRequest) SetSpaceReserve(newValue string) *VolumeCreateAsyncRequest {
+	o.SpaceReservePtr = &newValue
+	return o
+}
+
+// UnixPermissions is a fluent style 'getter' method that can be chained
+func (o *VolumeCreateAsyncRequest) UnixPermissions() string {
+	r := *o.UnixPermissionsPtr
+	return r
+}
+
+// SetUnixPermissions is a fluent style 'setter' method that can be chained
+func (o *VolumeCreateAsyncRequest) SetUnixPermissions(newValue string) *VolumeCreateAsyncRequest {
+	o.UnixPermissionsPtr = &newValue
+	return o
+}
+
+// VolumeName is a fluent style 'getter' method that can be chained
+func (o *VolumeCreateAsyncRequest) VolumeName() string {
+	r := *o.VolumeNamePtr
+	return r
+}
+
+// SetVolumeName is a fluent style 'setter' method that can be chained
+func (o *VolumeCreateAsyncRequest) SetVolumeName(newValue string) *VolumeCreateAsyncRequest {
+	o.VolumeNamePtr = &newValue
+	return o
+}
+
+// VolumeSecurityStyle is a fluent style 'getter' method that can be chained
+func (o *VolumeCreateAsyncRequest) VolumeSecurityStyle() string {
+	r := *o.VolumeSecurityStylePtr
+	return r
+}
+
+// SetVolumeSecurityStyle is a fluent style 'setter' method that can be chained
+func (o *VolumeCreateAsyncRequest) SetVolumeSecurityStyle(newValue string) *VolumeCreateAsyncRequest {
+	o.VolumeSecurityStylePtr = &newValue
+	return o
+}
+
+// VolumeCreateAsyncResponse is a structure to represent a volume-create-async ZAPI response object
+type VolumeCreateAsyncResponse struct {
+	XMLName xml.Name `xml:"netapp"`
+
+	ResponseVersion string `xml:"version,attr"`
+	ResponseXmlns   string `xml:"xmlns,attr"`
+
+	Result VolumeCreateAsyncResponseResult `xml:"results"`
+}
+
+// String returns a string representation of this object's fields and implements the Stringer interface
+func (o VolumeCreateAsyncResponse) String() string {
+	var buffer bytes.Buffer
+	buffer.WriteString(fmt.Sprintf("%s: %s\n", "version", o.ResponseVersion))
+	buffer.WriteString(fmt.Sprintf("%s: %s\n", "xmlns", o.ResponseXmlns))
+	buffer.WriteString(fmt.Sprintf("%s: %s\n", "results", o.Result))
+	return buffer.String()
+}
+
+// VolumeCreateAsyncResponseResult is a structure to represent a volume-create-async ZAPI object's result
+type VolumeCreateAsyncResponseResult struct {
+	XMLName xml.Name `xml:"results"`
+
+	ResultStatusAttr      string  `xml:"status,attr"`
+	ResultReasonAttr      string  `xml:"reason,attr"`
+	ResultErrnoAttr       string  `xml:"errno,attr"`
+	ResultErrorCodePtr    *int    `xml:"result-error-code"`
+	ResultErrorMessagePtr *string `xml:"result-error-message"`
+	ResultJobidPtr        *int    `xml:"result-jobid"`
+	ResultStatusPtr       *string `xml:"result-status"`
+}
+
+// ToXML converts this object into an xml string representation
+func (o *VolumeCreateAsyncResponse) ToXML() (string, error) {
+	output, err := xml.MarshalIndent(o, " ", "    ")
+	//if err != nil { log.Debugf("error: %v", err) }
+	return string(output), err
+}
+
+// NewVolumeCreateAsyncResponse is a factory method for creating new instances of VolumeCreateAsyncResponse objects
+func NewVolumeCreateAsyncResponse() *VolumeCreateAsyncResponse { return &VolumeCreateAsyncResponse{} }
+
+// String returns a string representation of this object's fields and implements the Stringer interface
+func (o VolumeCreateAsyncResponseResult) String() string {
+	var buffer bytes.Buffer
+	buffer.WriteString(fmt.Sprintf("%s: %s\n", "resultStatusAttr", o.ResultStatusAttr))
+	buffer.WriteString(fmt.Sprintf("%s: %s\n", "resultReasonAttr", o.ResultReasonAttr))
+	buffer.WriteString(fmt.Sprintf("%s: %s\n", "resultErrnoAttr", o.ResultErrnoAttr))
+	if o.ResultErrorCodePtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "result-error-code", *o.ResultErrorCodePtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("result-error-code: nil\n"))
+	}
+	if o.ResultErrorMessagePtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "result-error-message", *o.ResultErrorMessagePtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("result-error-message: nil\n"))
+	}
+	if o.ResultJobidPtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "result-jobid", *o.ResultJobidPtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("result-jobid: nil\n"))
+	}
+	if o.ResultStatusPtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "result-status", *o.ResultStatusPtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("result-status: nil\n"))
+	}
+	return buffer.String()
+}
+
+// ResultErrorCode is a fluent style 'getter' method that can be chained
+func (o *VolumeCreateAsyncResponseResult) ResultErrorCode() int {
+	r := *o.ResultErrorCodePtr
+	return r
+}
+
+// SetResultErrorCode is a fluent style 'setter' method that can be chained
+func (o *VolumeCreateAsyncResponseResult) SetResultErrorCode(newValue int) *VolumeCreateAsyncResponseResult {
+	o.ResultErrorCodePtr = &newValue
+	return o
+}
+
+// ResultErrorMessage is a fluent style 'getter' method that can be chained
+func (o *VolumeCreateAsyncResponseResult) ResultErrorMessage() string {
+	r := *o.ResultErrorMessagePtr
+	return r
+}
+
+// SetResultErrorMessage is a fluent style 'setter' method that can be chained
+func (o *VolumeCreateAsyncResponseResult) SetResultErrorMessage(newValue string) *VolumeCreateAsyncResponseResult {
+	o.ResultErrorMessagePtr = &newValue
+	return o
+}
+
+// ResultJobid is a fluent style 'getter' method that can be chained
+func (o *VolumeCreateAsyncResponseResult) ResultJobid() int {
+	r := *o.ResultJobidPtr
+	return r
+}
+
+// SetResultJobid is a fluent style 'setter' method that can be chained
+func (o *VolumeCreateAsyncResponseResult) SetResultJobid(newValue int) *VolumeCreateAsyncResponseResult {
+	o.ResultJobidPtr = &newValue
+	return o
+}
+
+// ResultStatus is a fluent style 'getter' method that can be chained
+func (o *VolumeCreateAsyncResponseResult) ResultStatus() string {
+	r := *o.ResultStatusPtr
+	return r
+}
+
+// SetResultStatus is a fluent style 'setter' method that can be chained
+func (o *VolumeCreateAsyncResponseResult) SetResultStatus(newValue string) *VolumeCreateAsyncResponseResult {
+	o.ResultStatusPtr = &newValue
+	return o
+}
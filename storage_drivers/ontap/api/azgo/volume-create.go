@@ -36,6 +36,7 @@ type VolumeCreateRequest struct {
 	MaxDirSizePtr                   *int    `xml:"max-dir-size"`
 	MaxWriteAllocBlocksPtr          *int    `xml:"max-write-alloc-blocks"`
 	PercentageSnapshotReservePtr    *int    `xml:"percentage-snapshot-reserve"`
+	QosAdaptivePolicyGroupNamePtr   *string `xml:"qos-adaptive-policy-group-name"`
 	QosPolicyGroupNamePtr           *string `xml:"qos-policy-group-name"`
 	SizePtr                         *string `xml:"size"`
 	SnapshotPolicyPtr               *string `xml:"snapshot-policy"`
@@ -214,6 +215,11 @@ func (o VolumeCreateRequest) String() string {
 	} else {
 		buffer.WriteString(fmt.Sprintf("percentage-snapshot-reserve: nil\n"))
 	}
+	if o.QosAdaptivePolicyGroupNamePtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "qos-adaptive-policy-group-name", *o.QosAdaptivePolicyGroupNamePtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("qos-adaptive-policy-group-name: nil\n"))
+	}
 	if o.QosPolicyGroupNamePtr != nil {
 		buffer.WriteString(fmt.Sprintf("%s: %v\n", "qos-policy-group-name", *o.QosPolicyGroupNamePtr))
 	} else {
@@ -574,6 +580,18 @@ func (o *VolumeCreateRequest) SetPercentageSnapshotReserve(newValue int) *Volume
 	return o
 }
 
+// QosAdaptivePolicyGroupName is a fluent style 'getter' method that can be chained
+func (o *VolumeCreateRequest) QosAdaptivePolicyGroupName() string {
+	r := *o.QosAdaptivePolicyGroupNamePtr
+	return r
+}
+
+// SetQosAdaptivePolicyGroupName is a fluent style 'setter' method that can be chained
+func (o *VolumeCreateRequest) SetQosAdaptivePolicyGroupName(newValue string) *VolumeCreateRequest {
+	o.QosAdaptivePolicyGroupNamePtr = &newValue
+	return o
+}
+
 // QosPolicyGroupName is a fluent style 'getter' method that can be chained
 func (o *VolumeCreateRequest) QosPolicyGroupName() string {
 	r := *o.QosPolicyGroupNamePtr
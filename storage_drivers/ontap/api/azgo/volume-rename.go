@@ -0,0 +1,154 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package azgo
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// VolumeRenameRequest is a structure to represent a volume-rename ZAPI request object
+type VolumeRenameRequest struct {
+	XMLName xml.Name `xml:"volume-rename"`
+
+	NewVolumeNamePtr *string `xml:"new-volume-name"`
+	VolumePtr        *string `xml:"volume"`
+}
+
+// ToXML converts this object into an xml string representation
+func (o *VolumeRenameRequest) ToXML() (string, error) {
+	output, err := xml.MarshalIndent(o, " ", "    ")
+	//if err != nil { log.Errorf("error: %v\n", err) }
+	return string(output), err
+}
+
+// NewVolumeRenameRequest is a factory method for creating new instances of VolumeRenameRequest objects
+func NewVolumeRenameRequest() *VolumeRenameRequest { return &VolumeRenameRequest{} }
+
+// ExecuteUsing converts this object to a ZAPI XML representation and uses the supplied ZapiRunner to send to a filer
+func (o *VolumeRenameRequest) ExecuteUsing(zr *ZapiRunner) (VolumeRenameResponse, error) {
+
+	if zr.DebugTraceFlags["method"] {
+		fields := log.Fields{"Method": "ExecuteUsing", "Type": "VolumeRenameRequest"}
+		log.WithFields(fields).Debug(">>>> ExecuteUsing")
+		defer log.WithFields(fields).Debug("<<<< ExecuteUsing")
+	}
+
+	resp, err := zr.SendZapi(o)
+	if err != nil {
+		log.Errorf("API invocation failed. %v", err.Error())
+		return VolumeRenameResponse{}, err
+	}
+	defer resp.Body.Close()
+	body, readErr := ioutil.ReadAll(resp.Body)
+	if readErr != nil {
+		log.Errorf("Error reading response body. %v", readErr.Error())
+		return VolumeRenameResponse{}, readErr
+	}
+	if zr.DebugTraceFlags["api"] {
+		log.Debugf("response Body:\n%s", string(body))
+	}
+
+	var n VolumeRenameResponse
+	unmarshalErr := xml.Unmarshal(body, &n)
+	if unmarshalErr != nil {
+		log.WithField("body", string(body)).Warnf("Error unmarshaling response body. %v", unmarshalErr.Error())
+		//return VolumeRenameResponse{}, unmarshalErr
+	}
+	if zr.DebugTraceFlags["api"] {
+		log.Debugf("volume-rename result:\n%s", n.Result)
+	}
+
+	return n, nil
+}
+
+// String returns a string representation of this object's fields and implements the Stringer interface
+func (o VolumeRenameRequest) String() string {
+	var buffer bytes.Buffer
+	if o.NewVolumeNamePtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "new-volume-name", *o.NewVolumeNamePtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("new-volume-name: nil\n"))
+	}
+	if o.VolumePtr != nil {
+		buffer.WriteString(fmt.Sprintf("%s: %v\n", "volume", *o.VolumePtr))
+	} else {
+		buffer.WriteString(fmt.Sprintf("volume: nil\n"))
+	}
+	return buffer.String()
+}
+
+// NewVolumeName is a fluent style 'getter' method that can be chained
+func (o *VolumeRenameRequest) NewVolumeName() string {
+	r := *o.NewVolumeNamePtr
+	return r
+}
+
+// SetNewVolumeName is a fluent style 'setter' method that can be chained
+func (o *VolumeRenameRequest) SetNewVolumeName(newValue string) *VolumeRenameRequest {
+	o.NewVolumeNamePtr = &newValue
+	return o
+}
+
+// Volume is a fluent style 'getter' method that can be chained
+func (o *VolumeRenameRequest) Volume() string {
+	r := *o.VolumePtr
+	return r
+}
+
+// SetVolume is a fluent style 'setter' method that can be chained
+func (o *VolumeRenameRequest) SetVolume(newValue string) *VolumeRenameRequest {
+	o.VolumePtr = &newValue
+	return o
+}
+
+// VolumeRenameResponse is a structure to represent a volume-rename ZAPI response object
+type VolumeRenameResponse struct {
+	XMLName xml.Name `xml:"netapp"`
+
+	ResponseVersion string `xml:"version,attr"`
+	ResponseXmlns   string `xml:"xmlns,attr"`
+
+	Result VolumeRenameResponseResult `xml:"results"`
+}
+
+// String returns a string representation of this object's fields and implements the Stringer interface
+func (o VolumeRenameResponse) String() string {
+	var buffer bytes.Buffer
+	buffer.WriteString(fmt.Sprintf("%s: %s\n", "version", o.ResponseVersion))
+	buffer.WriteString(fmt.Sprintf("%s: %s\n", "xmlns", o.ResponseXmlns))
+	buffer.WriteString(fmt.Sprintf("%s: %s\n", "results", o.Result))
+	return buffer.String()
+}
+
+// VolumeRenameResponseResult is a structure to represent a volume-rename ZAPI object's result
+type VolumeRenameResponseResult struct {
+	XMLName xml.Name `xml:"results"`
+
+	ResultStatusAttr string `xml:"status,attr"`
+	ResultReasonAttr string `xml:"reason,attr"`
+	ResultErrnoAttr  string `xml:"errno,attr"`
+}
+
+// ToXML converts this object into an xml string representation
+func (o *VolumeRenameResponse) ToXML() (string, error) {
+	output, err := xml.MarshalIndent(o, " ", "    ")
+	//if err != nil { log.Debugf("error: %v", err) }
+	return string(output), err
+}
+
+// NewVolumeRenameResponse is a factory method for creating new instances of VolumeRenameResponse objects
+func NewVolumeRenameResponse() *VolumeRenameResponse { return &VolumeRenameResponse{} }
+
+// String returns a string representation of this object's fields and implements the Stringer interface
+func (o VolumeRenameResponseResult) String() string {
+	var buffer bytes.Buffer
+	buffer.WriteString(fmt.Sprintf("%s: %s\n", "resultStatusAttr", o.ResultStatusAttr))
+	buffer.WriteString(fmt.Sprintf("%s: %s\n", "resultReasonAttr", o.ResultReasonAttr))
+	buffer.WriteString(fmt.Sprintf("%s: %s\n", "resultErrnoAttr", o.ResultErrnoAttr))
+	return buffer.String()
+}
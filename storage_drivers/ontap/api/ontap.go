@@ -8,6 +8,7 @@ import (
 	"reflect"
 	"strings"
 	"sync"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 
@@ -17,6 +18,9 @@ import (
 
 const defaultZapiRecords = 100
 
+// maxFlexGroupJobWaitSecs is how long to wait for the jobs that create, resize, and destroy FlexGroups
+const maxFlexGroupJobWaitSecs = 300
+
 // ClientConfig holds the configuration data for Client objects
 type ClientConfig struct {
 	ManagementLIF   string
@@ -170,6 +174,8 @@ const (
 	VServerShowAggr        feature = "VSERVER_SHOW_AGGR"
 	FlexGroups             feature = "FLEX_GROUPS"
 	NetAppVolumeEncryption feature = "NETAPP_VOLUME_ENCRYPTION"
+	QosMinimumThroughput   feature = "QOS_MINIMUM_THROUGHPUT"
+	AdaptiveQosPolicies    feature = "ADAPTIVE_QOS_POLICIES"
 )
 
 // Indicate the minimum Ontapi version for each feature here
@@ -178,12 +184,19 @@ var features = map[feature]*utils.Version{
 	VServerShowAggr:        utils.MustParseSemantic("1.100.0"), // cDOT 9.0.0
 	FlexGroups:             utils.MustParseSemantic("1.100.0"), // cDOT 9.0.0
 	NetAppVolumeEncryption: utils.MustParseSemantic("1.110.0"), // cDOT 9.1.0
+	QosMinimumThroughput:   utils.MustParseSemantic("1.120.0"), // cDOT 9.2.0
+	AdaptiveQosPolicies:    utils.MustParseSemantic("1.130.0"), // cDOT 9.3.0
 }
 
 // SupportsFeature returns true if the Ontapi version supports the supplied feature
 func (d Client) SupportsFeature(feature feature) bool {
+	return supportsFeature(d, feature)
+}
+
+// supportsFeature returns true if the Ontapi version reported by a client supports the supplied feature
+func supportsFeature(client Interface, feature feature) bool {
 
-	ontapiVersion, err := d.SystemGetOntapiVersion()
+	ontapiVersion, err := client.SystemGetOntapiVersion()
 	if err != nil {
 		return false
 	}
@@ -300,10 +313,16 @@ func (d Client) LunMapAutoID(initiatorGroupName, lunPath string) (response azgo.
 	return
 }
 
+// LunMapIfNotMapped maps a LUN in an initiator group unless it is mapped there already, and returns its LUN ID
 func (d Client) LunMapIfNotMapped(initiatorGroupName, lunPath string) (int, error) {
+	return lunMapIfNotMapped(d, initiatorGroupName, lunPath)
+}
+
+// lunMapIfNotMapped maps a LUN in an initiator group using a client unless it is mapped there already
+func lunMapIfNotMapped(client Interface, initiatorGroupName, lunPath string) (int, error) {
 
 	// Read LUN maps to see if the LUN is already mapped to the igroup
-	lunMapListResponse, err := d.LunMapListInfo(lunPath)
+	lunMapListResponse, err := client.LunMapListInfo(lunPath)
 	if err != nil {
 		return -1, fmt.Errorf("problem reading maps for LUN %s: %v", lunPath, err)
 	} else if lunMapListResponse.Result.ResultStatusAttr != "passed" {
@@ -330,7 +349,7 @@ func (d Client) LunMapIfNotMapped(initiatorGroupName, lunPath string) (int, erro
 
 	// Map IFF not already mapped
 	if !alreadyMapped {
-		lunMapResponse, err := d.LunMapAutoID(initiatorGroupName, lunPath)
+		lunMapResponse, err := client.LunMapAutoID(initiatorGroupName, lunPath)
 		if err != nil {
 			return -1, fmt.Errorf("problem mapping LUN %s: %v", lunPath, err)
 		} else if lunMapResponse.Result.ResultStatusAttr != "passed" {
@@ -376,11 +395,34 @@ func (d Client) LunOnline(lunPath string) (response azgo.LunOnlineResponse, err
 	return
 }
 
-// LunDestroy destroys a lun
-// equivalent to filer::> lun destroy -vserver iscsi_vs -path /vol/v/lun0
-func (d Client) LunDestroy(lunPath string) (response azgo.LunDestroyResponse, err error) {
+// LunDestroy destroys a lun, even if it is mapped when force is set
+// equivalent to filer::> lun destroy -vserver iscsi_vs -path /vol/v/lun0 -force true
+func (d Client) LunDestroy(lunPath string, force bool) (response azgo.LunDestroyResponse, err error) {
 	response, err = azgo.NewLunDestroyRequest().
 		SetPath(lunPath).
+		SetForce(force).
+		ExecuteUsing(d.zr)
+	return
+}
+
+// LunCloneCreate clones a LUN within its Flexvol
+// equivalent to filer::> volume file clone create -vserver iscsi_vs -volume v -source-path lun0 -destination-path lun1
+func (d Client) LunCloneCreate(volumeName, sourceLun, destinationLun string, spaceReserved bool) (response azgo.CloneCreateResponse, err error) {
+	response, err = azgo.NewCloneCreateRequest().
+		SetVolume(volumeName).
+		SetSourcePath(sourceLun).
+		SetDestinationPath(destinationLun).
+		SetSpaceReserve(spaceReserved).
+		ExecuteUsing(d.zr)
+	return
+}
+
+// LunResize resizes a lun
+// equivalent to filer::> lun resize -vserver iscsi_vs -path /vol/v/lun0 -size 10g
+func (d Client) LunResize(lunPath string, sizeBytes int) (response azgo.LunResizeResponse, err error) {
+	response, err = azgo.NewLunResizeRequest().
+		SetPath(lunPath).
+		SetSize(sizeBytes).
 		ExecuteUsing(d.zr)
 	return
 }
@@ -455,6 +497,59 @@ func (d Client) LunGetAll(pathPattern string) (response azgo.LunGetIterResponse,
 	return
 }
 
+// LunCount returns the number of LUNs in the specified Flexvol
+func (d Client) LunCount(volume string) (int, error) {
+
+	// Limit the LUNs to those in the specified Flexvol
+	query := azgo.NewLunInfoType().SetVolume(volume)
+
+	// Limit the returned data to only the LUN paths
+	desiredAttributes := azgo.NewLunInfoType().SetPath("")
+
+	response, err := azgo.NewLunGetIterRequest().
+		SetMaxRecords(defaultZapiRecords).
+		SetQuery(*query).
+		SetDesiredAttributes(*desiredAttributes).
+		ExecuteUsing(d.zr)
+
+	if err = GetError(response, err); err != nil {
+		return 0, err
+	}
+
+	return response.Result.NumRecords(), nil
+}
+
+// LunExists returns true if the named LUN exists (and is unique in the matching Flexvols)
+func (d Client) LunExists(name, volumePrefix string) (bool, string, error) {
+
+	// Limit the LUNs to those matching the Flexvol name prefix and the LUN name
+	query := azgo.NewLunInfoType().SetPath(fmt.Sprintf("/vol/%s*/%s", volumePrefix, name))
+
+	// Limit the returned data to only the Flexvol names and LUN paths
+	desiredAttributes := azgo.NewLunInfoType().SetPath("").SetVolume("")
+
+	response, err := azgo.NewLunGetIterRequest().
+		SetMaxRecords(defaultZapiRecords).
+		SetQuery(*query).
+		SetDesiredAttributes(*desiredAttributes).
+		ExecuteUsing(d.zr)
+
+	// Ensure the API call succeeded
+	if err = GetError(response, err); err != nil {
+		return false, "", err
+	}
+
+	// Ensure LUN is unique
+	if response.Result.NumRecords() != 1 {
+		return false, "", nil
+	}
+
+	// Get containing Flexvol
+	flexvol := response.Result.AttributesList()[0].Volume()
+
+	return true, flexvol, nil
+}
+
 // LUN operations END
 /////////////////////////////////////////////////////////////////////////////
 
@@ -462,9 +557,10 @@ func (d Client) LunGetAll(pathPattern string) (response azgo.LunGetIterResponse,
 // VOLUME operations BEGIN
 
 // VolumeCreate creates a volume with the specified options
-// equivalent to filer::> volume create -vserver iscsi_vs -volume v -aggregate aggr1 -size 1g -state online -type RW -policy default -unix-permissions ---rwxr-xr-x -space-guarantee none -snapshot-policy none -security-style unix -encrypt false
+// equivalent to filer::> volume create -vserver iscsi_vs -volume v -aggregate aggr1 -size 1g -state online -type RW -policy default -unix-permissions ---rwxr-xr-x -space-guarantee none -snapshot-policy none -security-style unix -encrypt false -qos-policy-group pg1
 func (d Client) VolumeCreate(name, aggregateName, size, spaceReserve, snapshotPolicy, unixPermissions,
-	exportPolicy, securityStyle string, encrypt *bool) (response azgo.VolumeCreateResponse, err error) {
+	exportPolicy, securityStyle string, encrypt *bool, qosPolicy, adaptiveQosPolicy string,
+) (response azgo.VolumeCreateResponse, err error) {
 	request := azgo.NewVolumeCreateRequest().
 		SetVolume(name).
 		SetContainingAggrName(aggregateName).
@@ -480,6 +576,34 @@ func (d Client) VolumeCreate(name, aggregateName, size, spaceReserve, snapshotPo
 		request.SetEncrypt(*encrypt)
 	}
 
+	// Don't send the QoS policy names unless needed, as a volume may only have one of them.
+	if qosPolicy != "" {
+		request.SetQosPolicyGroupName(qosPolicy)
+	}
+	if adaptiveQosPolicy != "" {
+		request.SetQosAdaptivePolicyGroupName(adaptiveQosPolicy)
+	}
+
+	response, err = request.ExecuteUsing(d.zr)
+	return
+}
+
+// VolumeCreateDP creates a data protection volume to serve as the destination of a SnapMirror relationship
+// equivalent to filer::> volume create -vserver svm2 -volume v -aggregate aggr1 -size 1g -type DP -policy default
+func (d Client) VolumeCreateDP(
+	name, aggregateName, size, exportPolicy string,
+) (response azgo.VolumeCreateResponse, err error) {
+
+	request := azgo.NewVolumeCreateRequest().
+		SetVolume(name).
+		SetContainingAggrName(aggregateName).
+		SetSize(size).
+		SetVolumeType("dp")
+
+	if exportPolicy != "" {
+		request.SetExportPolicy(exportPolicy)
+	}
+
 	response, err = request.ExecuteUsing(d.zr)
 	return
 }
@@ -591,6 +715,15 @@ func (d Client) VolumeDestroy(name string, force bool) (response azgo.VolumeDest
 	return
 }
 
+// VolumeRename changes the name of a volume
+func (d Client) VolumeRename(name, newName string) (response azgo.VolumeRenameResponse, err error) {
+	response, err = azgo.NewVolumeRenameRequest().
+		SetVolume(name).
+		SetNewVolumeName(newName).
+		ExecuteUsing(d.zr)
+	return
+}
+
 // VolumeGet returns all relevant details for a single Flexvol
 // equivalent to filer::> volume show
 func (d Client) VolumeGet(name string) (azgo.VolumeAttributesType, error) {
@@ -618,12 +751,15 @@ func (d Client) VolumeGet(name string) (azgo.VolumeAttributesType, error) {
 	return response.Result.AttributesList()[0], nil
 }
 
-// VolumeGetAll returns all relevant details for all FlexVols whose names match the supplied prefix
+// VolumeGetAll returns all relevant details for all read-write FlexVols whose names match the supplied prefix
 // equivalent to filer::> volume show
 func (d Client) VolumeGetAll(prefix string) (response azgo.VolumeGetIterResponse, err error) {
 
-	// Limit the Flexvols to those matching the name prefix
-	queryVolIDAttrs := azgo.NewVolumeIdAttributesType().SetName(azgo.VolumeNameType(prefix + "*"))
+	// Limit the Flexvols to those matching the name prefix, leaving out the data protection
+	// volumes that replicate another SVM's volumes
+	queryVolIDAttrs := azgo.NewVolumeIdAttributesType().
+		SetName(azgo.VolumeNameType(prefix + "*")).
+		SetType("rw")
 	if d.SupportsFeature(FlexGroups) {
 		queryVolIDAttrs.SetStyleExtended("flexvol")
 	}
@@ -660,11 +796,14 @@ func (d Client) VolumeGetAll(prefix string) (response azgo.VolumeGetIterResponse
 	return
 }
 
-// VolumeList returns the names of all Flexvols whose names match the supplied prefix
+// VolumeList returns the names of all read-write Flexvols whose names match the supplied prefix
 func (d Client) VolumeList(prefix string) (response azgo.VolumeGetIterResponse, err error) {
 
-	// Limit the Flexvols to those matching the name prefix
-	queryVolIDAttrs := azgo.NewVolumeIdAttributesType().SetName(azgo.VolumeNameType(prefix + "*"))
+	// Limit the Flexvols to those matching the name prefix, leaving out the data protection
+	// volumes that replicate another SVM's volumes
+	queryVolIDAttrs := azgo.NewVolumeIdAttributesType().
+		SetName(azgo.VolumeNameType(prefix + "*")).
+		SetType("rw")
 	if d.SupportsFeature(FlexGroups) {
 		queryVolIDAttrs.SetStyleExtended("flexvol")
 	}
@@ -685,6 +824,7 @@ func (d Client) VolumeList(prefix string) (response azgo.VolumeGetIterResponse,
 // VolumeListByAttrs returns the names of all Flexvols matching the specified attributes
 func (d Client) VolumeListByAttrs(
 	prefix, aggregate, spaceReserve, snapshotPolicy string, snapshotDir bool, encrypt *bool,
+	qosPolicy, adaptiveQosPolicy string,
 ) (response azgo.VolumeGetIterResponse, err error) {
 
 	// Limit the Flexvols to those matching the specified attributes
@@ -708,15 +848,43 @@ func (d Client) VolumeListByAttrs(
 		query.SetEncrypt(*encrypt)
 	}
 
-	// Limit the returned data to only the Flexvol names
+	// Limit the returned data to only the Flexvol names and QoS policies
 	desiredVolIDAttrs := azgo.NewVolumeIdAttributesType().SetName("")
-	desiredAttributes := azgo.NewVolumeAttributesType().SetVolumeIdAttributes(*desiredVolIDAttrs)
+	desiredVolQosAttrs := azgo.NewVolumeQosAttributesType().SetPolicyGroupName("")
+	if d.SupportsFeature(AdaptiveQosPolicies) {
+		desiredVolQosAttrs.SetAdaptivePolicyGroupName("")
+	}
+	desiredAttributes := azgo.NewVolumeAttributesType().
+		SetVolumeIdAttributes(*desiredVolIDAttrs).
+		SetVolumeQosAttributes(*desiredVolQosAttrs)
 
 	response, err = azgo.NewVolumeGetIterRequest().
 		SetMaxRecords(defaultZapiRecords).
 		SetQuery(*query).
 		SetDesiredAttributes(*desiredAttributes).
 		ExecuteUsing(d.zr)
+	if err != nil {
+		return
+	}
+
+	// Volumes without a QoS policy can't be queried for, so the QoS policies are matched here
+	matching := make([]azgo.VolumeAttributesType, 0)
+	for _, volAttrs := range response.Result.AttributesList() {
+		volQosPolicy, volAdaptiveQosPolicy := "", ""
+		if volAttrs.VolumeQosAttributesPtr != nil {
+			volQosAttrs := volAttrs.VolumeQosAttributes()
+			if volQosAttrs.PolicyGroupNamePtr != nil {
+				volQosPolicy = volQosAttrs.PolicyGroupName()
+			}
+			if volQosAttrs.AdaptivePolicyGroupNamePtr != nil {
+				volAdaptiveQosPolicy = volQosAttrs.AdaptivePolicyGroupName()
+			}
+		}
+		if volQosPolicy == qosPolicy && volAdaptiveQosPolicy == adaptiveQosPolicy {
+			matching = append(matching, volAttrs)
+		}
+	}
+	response.Result.SetAttributesList(matching).SetNumRecords(len(matching))
 	return
 }
 
@@ -730,6 +898,295 @@ func (d Client) VolumeGetRootName() (response azgo.VolumeGetRootNameResponse, er
 // VOLUME operations END
 /////////////////////////////////////////////////////////////////////////////
 
+/////////////////////////////////////////////////////////////////////////////
+// FLEXGROUP operations BEGIN
+
+// FlexGroupCreate creates a FlexGroup spanning the specified aggregates, mounted at a junction matching its name,
+// and waits for the creation job to finish
+// equivalent to filer::> volume create -vserver nas_vs -volume fg -aggr-list aggr1,aggr2 -size 100t -state online -type RW -policy default -unix-permissions ---rwxr-xr-x -space-guarantee none -snapshot-policy none -security-style unix -encrypt false -junction-path /fg
+func (d Client) FlexGroupCreate(name string, size int, aggregates []string, spaceReserve, snapshotPolicy,
+	unixPermissions, exportPolicy, securityStyle string, encrypt *bool, qosPolicy, adaptiveQosPolicy string,
+) (response azgo.VolumeCreateAsyncResponse, err error) {
+
+	aggrList := make([]azgo.AggrNameType, 0, len(aggregates))
+	for _, aggregate := range aggregates {
+		aggrList = append(aggrList, azgo.AggrNameType(aggregate))
+	}
+
+	request := azgo.NewVolumeCreateAsyncRequest().
+		SetVolumeName(name).
+		SetAggrList(aggrList).
+		SetSize(size).
+		SetSpaceReserve(spaceReserve).
+		SetSnapshotPolicy(snapshotPolicy).
+		SetUnixPermissions(unixPermissions).
+		SetExportPolicy(exportPolicy).
+		SetVolumeSecurityStyle(securityStyle).
+		SetJunctionPath("/" + name)
+
+	// Don't send 'encrypt' unless needed, as pre-9.1 ONTAP won't accept it.
+	if encrypt != nil {
+		request.SetEncrypt(*encrypt)
+	}
+
+	// Don't send the QoS policy names unless needed, as a volume may only have one of them.
+	if qosPolicy != "" {
+		request.SetQosPolicyGroupName(qosPolicy)
+	}
+	if adaptiveQosPolicy != "" {
+		request.SetQosAdaptivePolicyGroupName(adaptiveQosPolicy)
+	}
+
+	response, err = request.ExecuteUsing(d.zr)
+	if err = GetError(response, err); err != nil {
+		return
+	}
+
+	err = d.waitForAsyncResult(response.Result.ResultStatusPtr, response.Result.ResultJobidPtr,
+		response.Result.ResultErrorMessagePtr, maxFlexGroupJobWaitSecs)
+	return
+}
+
+// FlexGroupDestroy destroys a FlexGroup and waits for the destruction job to finish
+func (d Client) FlexGroupDestroy(name string, force bool) (response azgo.VolumeDestroyAsyncResponse, err error) {
+	response, err = azgo.NewVolumeDestroyAsyncRequest().
+		SetVolumeName(name).
+		SetUnmountAndOffline(force).
+		ExecuteUsing(d.zr)
+	if err = GetError(response, err); err != nil {
+		return
+	}
+
+	err = d.waitForAsyncResult(response.Result.ResultStatusPtr, response.Result.ResultJobidPtr,
+		response.Result.ResultErrorMessagePtr, maxFlexGroupJobWaitSecs)
+	return
+}
+
+// FlexGroupSetSize sets the size of a FlexGroup and waits for the resize job to finish
+func (d Client) FlexGroupSetSize(name, newSize string) (response azgo.VolumeSizeAsyncResponse, err error) {
+	response, err = azgo.NewVolumeSizeAsyncRequest().
+		SetVolumeName(name).
+		SetNewSize(newSize).
+		ExecuteUsing(d.zr)
+	if err = GetError(response, err); err != nil {
+		return
+	}
+
+	err = d.waitForAsyncResult(response.Result.ResultStatusPtr, response.Result.ResultJobidPtr,
+		response.Result.ResultErrorMessagePtr, maxFlexGroupJobWaitSecs)
+	return
+}
+
+// FlexGroupDisableSnapshotDirectoryAccess disables access to the ".snapshot" directory of a FlexGroup
+// and waits for the modification job to finish
+func (d Client) FlexGroupDisableSnapshotDirectoryAccess(name string) (
+	response azgo.VolumeModifyIterAsyncResponse, err error,
+) {
+	ssattr := azgo.NewVolumeSnapshotAttributesType().SetSnapdirAccessEnabled(false)
+	volattr := azgo.NewVolumeAttributesType().SetVolumeSnapshotAttributes(*ssattr)
+	volidattr := azgo.NewVolumeIdAttributesType().SetName(azgo.VolumeNameType(name))
+	queryattr := azgo.NewVolumeAttributesType().SetVolumeIdAttributes(*volidattr)
+
+	response, err = azgo.NewVolumeModifyIterAsyncRequest().
+		SetQuery(*queryattr).
+		SetAttributes(*volattr).
+		SetReturnFailureList(true).
+		SetReturnSuccessList(true).
+		ExecuteUsing(d.zr)
+	if err = GetError(response, err); err != nil {
+		return
+	}
+
+	for _, failure := range response.Result.FailureList() {
+		if failure.ErrorMessagePtr != nil {
+			err = fmt.Errorf("error modifying FlexGroup %s: %s", name, failure.ErrorMessage())
+		} else {
+			err = fmt.Errorf("error modifying FlexGroup %s", name)
+		}
+		return
+	}
+	for _, success := range response.Result.SuccessList() {
+		if err = d.waitForAsyncResult(success.StatusPtr, success.JobidPtr, success.ErrorMessagePtr,
+			maxFlexGroupJobWaitSecs); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// FlexGroupExists tests for the existence of a FlexGroup
+func (d Client) FlexGroupExists(name string) (bool, error) {
+
+	// Limit the FlexGroups to the one matching the name
+	queryVolIDAttrs := azgo.NewVolumeIdAttributesType().
+		SetName(azgo.VolumeNameType(name)).
+		SetStyleExtended("flexgroup")
+	query := azgo.NewVolumeAttributesType().SetVolumeIdAttributes(*queryVolIDAttrs)
+
+	// Limit the returned data to only the FlexGroup names
+	desiredVolIDAttrs := azgo.NewVolumeIdAttributesType().SetName("")
+	desiredAttributes := azgo.NewVolumeAttributesType().SetVolumeIdAttributes(*desiredVolIDAttrs)
+
+	response, err := azgo.NewVolumeGetIterRequest().
+		SetMaxRecords(defaultZapiRecords).
+		SetQuery(*query).
+		SetDesiredAttributes(*desiredAttributes).
+		ExecuteUsing(d.zr)
+	if err = GetError(response, err); err != nil {
+		return false, err
+	}
+
+	return response.Result.NumRecords() > 0, nil
+}
+
+// FlexGroupGet returns all relevant details for a single FlexGroup
+// equivalent to filer::> volume show
+func (d Client) FlexGroupGet(name string) (azgo.VolumeAttributesType, error) {
+
+	// Limit the FlexGroups to the one matching the name
+	queryVolIDAttrs := azgo.NewVolumeIdAttributesType().
+		SetName(azgo.VolumeNameType(name)).
+		SetStyleExtended("flexgroup")
+	query := azgo.NewVolumeAttributesType().SetVolumeIdAttributes(*queryVolIDAttrs)
+
+	response, err := azgo.NewVolumeGetIterRequest().
+		SetMaxRecords(defaultZapiRecords).
+		SetQuery(*query).
+		ExecuteUsing(d.zr)
+
+	if err != nil {
+		return azgo.VolumeAttributesType{}, err
+	} else if response.Result.NumRecords() == 0 {
+		return azgo.VolumeAttributesType{}, fmt.Errorf("flexgroup %s not found", name)
+	} else if response.Result.NumRecords() > 1 {
+		return azgo.VolumeAttributesType{}, fmt.Errorf("more than one FlexGroup %s found", name)
+	}
+
+	return response.Result.AttributesList()[0], nil
+}
+
+// FlexGroupGetAll returns all relevant details for all FlexGroups whose names match the supplied prefix
+// equivalent to filer::> volume show
+func (d Client) FlexGroupGetAll(prefix string) (response azgo.VolumeGetIterResponse, err error) {
+
+	// Limit the FlexGroups to those matching the name prefix
+	queryVolIDAttrs := azgo.NewVolumeIdAttributesType().
+		SetName(azgo.VolumeNameType(prefix + "*")).
+		SetStyleExtended("flexgroup")
+	query := azgo.NewVolumeAttributesType().SetVolumeIdAttributes(*queryVolIDAttrs)
+
+	// Limit the returned data to only the data relevant to containers
+	desiredVolExportAttrs := azgo.NewVolumeExportAttributesType().
+		SetPolicy("")
+	desiredVolIDAttrs := azgo.NewVolumeIdAttributesType().
+		SetName("")
+	desiredVolSecurityUnixAttrs := azgo.NewVolumeSecurityUnixAttributesType().
+		SetPermissions("")
+	desiredVolSecurityAttrs := azgo.NewVolumeSecurityAttributesType().
+		SetVolumeSecurityUnixAttributes(*desiredVolSecurityUnixAttrs)
+	desiredVolSpaceAttrs := azgo.NewVolumeSpaceAttributesType().
+		SetSize(0)
+	desiredVolSnapshotAttrs := azgo.NewVolumeSnapshotAttributesType().
+		SetSnapdirAccessEnabled(true).
+		SetSnapshotPolicy("")
+
+	desiredAttributes := azgo.NewVolumeAttributesType().
+		SetVolumeExportAttributes(*desiredVolExportAttrs).
+		SetVolumeIdAttributes(*desiredVolIDAttrs).
+		SetVolumeSecurityAttributes(*desiredVolSecurityAttrs).
+		SetVolumeSpaceAttributes(*desiredVolSpaceAttrs).
+		SetVolumeSnapshotAttributes(*desiredVolSnapshotAttrs)
+
+	response, err = azgo.NewVolumeGetIterRequest().
+		SetMaxRecords(defaultZapiRecords).
+		SetQuery(*query).
+		SetDesiredAttributes(*desiredAttributes).
+		ExecuteUsing(d.zr)
+	return
+}
+
+// FLEXGROUP operations END
+/////////////////////////////////////////////////////////////////////////////
+
+/////////////////////////////////////////////////////////////////////////////
+// JOB operations BEGIN
+
+// JobGet returns the details of a single cluster job
+// equivalent to filer::> job show -id 1234
+func (d Client) JobGet(id int) (azgo.JobInfoType, error) {
+
+	query := azgo.NewJobInfoType().SetJobId(id)
+
+	response, err := azgo.NewJobGetIterRequest().
+		SetMaxRecords(defaultZapiRecords).
+		SetQuery(*query).
+		ExecuteUsing(d.GetNontunneledZapiRunner())
+
+	if err = GetError(response, err); err != nil {
+		return azgo.JobInfoType{}, err
+	} else if response.Result.NumRecords() == 0 {
+		return azgo.JobInfoType{}, fmt.Errorf("job %d not found", id)
+	}
+
+	return response.Result.AttributesList()[0], nil
+}
+
+// waitForAsyncResult waits for the job started by an asynchronous ZAPI to finish, given the status, job ID,
+// and error message the ZAPI returned, and returns an error if the job fails or doesn't finish in time.
+func (d Client) waitForAsyncResult(status *string, jobID *int, errorMessage *string, maxWaitSecs int) error {
+
+	if status == nil {
+		return errors.New("asynchronous operation returned no status")
+	}
+
+	switch *status {
+	case "succeeded":
+		return nil
+	case "in_progress":
+		break
+	default:
+		if errorMessage != nil {
+			return fmt.Errorf("asynchronous operation %s: %s", *status, *errorMessage)
+		}
+		return fmt.Errorf("asynchronous operation %s", *status)
+	}
+
+	if jobID == nil {
+		return errors.New("asynchronous operation in progress returned no job ID")
+	}
+
+	timeout := time.Now().Add(time.Duration(maxWaitSecs) * time.Second)
+	for {
+		job, err := d.JobGet(*jobID)
+		if err != nil {
+			return fmt.Errorf("error reading job %d: %v", *jobID, err)
+		}
+
+		if job.JobStatePtr != nil {
+			switch job.JobState() {
+			case "success":
+				return nil
+			case "failure", "error":
+				if job.JobCompletionPtr != nil {
+					return fmt.Errorf("job %d failed: %s", *jobID, job.JobCompletion())
+				}
+				return fmt.Errorf("job %d failed", *jobID)
+			}
+		}
+
+		// Don't wait forever
+		if time.Now().After(timeout) {
+			return fmt.Errorf("job %d did not finish within %d seconds", *jobID, maxWaitSecs)
+		}
+
+		log.WithField("job", *jobID).Debug("Job not yet finished, polling...")
+		time.Sleep(1 * time.Second)
+	}
+}
+
+// JOB operations END
+/////////////////////////////////////////////////////////////////////////////
+
 /////////////////////////////////////////////////////////////////////////////
 // QTREE operations BEGIN
 
@@ -1082,9 +1539,93 @@ func (d Client) SnapshotGetByVolume(volumeName string) (response azgo.SnapshotGe
 	return
 }
 
+// SnapshotDelete deletes a snapshot of a volume
+func (d Client) SnapshotDelete(name, volumeName string) (response azgo.SnapshotDeleteResponse, err error) {
+	response, err = azgo.NewSnapshotDeleteRequest().
+		SetSnapshot(name).
+		SetVolume(volumeName).
+		ExecuteUsing(d.zr)
+	return
+}
+
+// SnapshotRestoreVolume restores a volume to the state captured by a snapshot
+func (d Client) SnapshotRestoreVolume(name, volumeName string) (response azgo.SnapshotRestoreVolumeResponse, err error) {
+	response, err = azgo.NewSnapshotRestoreVolumeRequest().
+		SetVolume(volumeName).
+		SetSnapshot(name).
+		ExecuteUsing(d.zr)
+	return
+}
+
 // SNAPSHOT operations END
 /////////////////////////////////////////////////////////////////////////////
 
+/////////////////////////////////////////////////////////////////////////////
+// QOS operations BEGIN
+
+// QosPolicyGroupCreate creates a QoS policy group with the specified throughput limits.  A limit
+// is given in IOPS or in bytes per second, e.g. "5000iops" or "100MB/s", and is left unset if empty.
+// equivalent to filer::> qos policy-group create -policy-group pg1 -vserver iscsi_vs -max-throughput 5000iops -min-throughput 1000iops
+func (d Client) QosPolicyGroupCreate(name, maxThroughput, minThroughput string) (response azgo.QosPolicyGroupCreateResponse, err error) {
+
+	// QoS policy groups are managed by the cluster, so this API only works if it isn't tunneled to an
+	// SVM.  It will still fail if the non-tunneled ZapiRunner addresses a vserver management LIF,
+	// but that possibility must be handled by the caller.
+	zr := d.GetNontunneledZapiRunner()
+
+	request := azgo.NewQosPolicyGroupCreateRequest().
+		SetPolicyGroup(name).
+		SetVserver(d.config.SVM)
+
+	if maxThroughput != "" {
+		request.SetMaxThroughput(maxThroughput)
+	}
+
+	// Don't send 'min-throughput' unless needed, as pre-9.2 ONTAP won't accept it.
+	if minThroughput != "" {
+		request.SetMinThroughput(minThroughput)
+	}
+
+	response, err = request.ExecuteUsing(zr)
+	return
+}
+
+// QosPolicyGroupDelete deletes a QoS policy group, which must not be in use
+// equivalent to filer::> qos policy-group delete -policy-group pg1
+func (d Client) QosPolicyGroupDelete(name string) (response azgo.QosPolicyGroupDeleteResponse, err error) {
+	response, err = azgo.NewQosPolicyGroupDeleteRequest().
+		SetPolicyGroup(name).
+		ExecuteUsing(d.GetNontunneledZapiRunner())
+	return
+}
+
+// QosPolicyGroupGet returns the details of a single QoS policy group
+// equivalent to filer::> qos policy-group show -policy-group pg1
+func (d Client) QosPolicyGroupGet(name string) (azgo.QosPolicyGroupInfoType, error) {
+
+	query := azgo.NewQosPolicyGroupInfoType().
+		SetPolicyGroup(name).
+		SetVserver(d.config.SVM)
+
+	response, err := azgo.NewQosPolicyGroupGetIterRequest().
+		SetMaxRecords(defaultZapiRecords).
+		SetQuery(*query).
+		ExecuteUsing(d.GetNontunneledZapiRunner())
+
+	if err = GetError(response, err); err != nil {
+		return azgo.QosPolicyGroupInfoType{}, err
+	} else if response.Result.NumRecords() == 0 {
+		return azgo.QosPolicyGroupInfoType{}, fmt.Errorf("QoS policy group %s not found", name)
+	} else if response.Result.NumRecords() > 1 {
+		return azgo.QosPolicyGroupInfoType{}, fmt.Errorf("more than one QoS policy group %s found", name)
+	}
+
+	return response.Result.AttributesList()[0], nil
+}
+
+// QOS operations END
+/////////////////////////////////////////////////////////////////////////////
+
 /////////////////////////////////////////////////////////////////////////////
 // ISCSI operations BEGIN
 
@@ -1210,6 +1751,112 @@ func (d Client) SnapmirrorUpdateLoadSharingMirrors(
 	return
 }
 
+// SnapmirrorCreate creates a data protection SnapMirror relationship; it must be sent to the destination SVM
+// equivalent to filer::> snapmirror create -source-path svm1:vol1 -destination-path svm2:vol1 -type DP -schedule hourly
+func (d Client) SnapmirrorCreate(
+	sourceLocation, destinationLocation, schedule, policy string,
+) (response azgo.SnapmirrorCreateResponse, err error) {
+
+	request := azgo.NewSnapmirrorCreateRequest().
+		SetSourceLocation(sourceLocation).
+		SetDestinationLocation(destinationLocation).
+		SetRelationshipType("data_protection")
+
+	// Let ONTAP choose its defaults for whatever isn't specified
+	if schedule != "" {
+		request.SetSchedule(schedule)
+	}
+	if policy != "" {
+		request.SetPolicy(policy)
+	}
+
+	response, err = request.ExecuteUsing(d.zr)
+	return
+}
+
+// SnapmirrorInitialize starts the baseline transfer of a SnapMirror relationship
+// equivalent to filer::> snapmirror initialize -destination-path svm2:vol1
+func (d Client) SnapmirrorInitialize(
+	sourceLocation, destinationLocation string,
+) (response azgo.SnapmirrorInitializeResponse, err error) {
+
+	response, err = azgo.NewSnapmirrorInitializeRequest().
+		SetSourceLocation(sourceLocation).
+		SetDestinationLocation(destinationLocation).
+		ExecuteUsing(d.zr)
+	return
+}
+
+// SnapmirrorGet returns the SnapMirror relationship whose destination is at the specified location, or nil
+// if there is none
+// equivalent to filer::> snapmirror show -destination-path svm2:vol1
+func (d Client) SnapmirrorGet(destinationLocation string) (*azgo.SnapmirrorInfoType, error) {
+
+	query := azgo.NewSnapmirrorInfoType().SetDestinationLocation(destinationLocation)
+
+	response, err := azgo.NewSnapmirrorGetIterRequest().
+		SetMaxRecords(defaultZapiRecords).
+		SetQuery(*query).
+		ExecuteUsing(d.zr)
+
+	if err = GetError(response, err); err != nil {
+		return nil, err
+	} else if response.Result.NumRecords() == 0 {
+		return nil, nil
+	} else if response.Result.NumRecords() > 1 {
+		return nil, fmt.Errorf("more than one SnapMirror relationship for %s found", destinationLocation)
+	}
+
+	relationship := response.Result.AttributesList()[0]
+	return &relationship, nil
+}
+
+// SnapmirrorQuiesce stops further transfers to the destination of a SnapMirror relationship, letting any
+// transfer in progress finish
+// equivalent to filer::> snapmirror quiesce -destination-path svm2:vol1
+func (d Client) SnapmirrorQuiesce(destinationLocation string) (response azgo.SnapmirrorQuiesceResponse, err error) {
+	response, err = azgo.NewSnapmirrorQuiesceRequest().
+		SetDestinationLocation(destinationLocation).
+		ExecuteUsing(d.zr)
+	return
+}
+
+// SnapmirrorBreak makes the destination of a SnapMirror relationship writable
+// equivalent to filer::> snapmirror break -destination-path svm2:vol1
+func (d Client) SnapmirrorBreak(destinationLocation string) (response azgo.SnapmirrorBreakResponse, err error) {
+	response, err = azgo.NewSnapmirrorBreakRequest().
+		SetDestinationLocation(destinationLocation).
+		ExecuteUsing(d.zr)
+	return
+}
+
+// SnapmirrorDelete removes a SnapMirror relationship; it must be sent to the destination SVM
+// equivalent to filer::> snapmirror delete -destination-path svm2:vol1
+func (d Client) SnapmirrorDelete(
+	sourceLocation, destinationLocation string,
+) (response azgo.SnapmirrorDeleteResponse, err error) {
+
+	response, err = azgo.NewSnapmirrorDeleteRequest().
+		SetSourceLocation(sourceLocation).
+		SetDestinationLocation(destinationLocation).
+		ExecuteUsing(d.zr)
+	return
+}
+
+// SnapmirrorRelease removes the source's record of a deleted SnapMirror relationship; it must be sent to the
+// source SVM
+// equivalent to filer::> snapmirror release -destination-path svm2:vol1
+func (d Client) SnapmirrorRelease(
+	sourceLocation, destinationLocation string,
+) (response azgo.SnapmirrorReleaseResponse, err error) {
+
+	response, err = azgo.NewSnapmirrorReleaseRequest().
+		SetSourceLocation(sourceLocation).
+		SetDestinationLocation(destinationLocation).
+		ExecuteUsing(d.zr)
+	return
+}
+
 // SNAPMIRROR operations END
 /////////////////////////////////////////////////////////////////////////////
 
@@ -1225,8 +1872,14 @@ func (d Client) NetInterfaceGet() (response azgo.NetInterfaceGetIterResponse, er
 	return
 }
 
+// NetInterfaceGetDataLIFs returns the addresses of the data LIFs serving the specified protocol
 func (d Client) NetInterfaceGetDataLIFs(protocol string) ([]string, error) {
-	lifResponse, err := d.NetInterfaceGet()
+	return netInterfaceGetDataLIFs(d, protocol)
+}
+
+// netInterfaceGetDataLIFs returns the addresses of the data LIFs a client reports for the specified protocol
+func netInterfaceGetDataLIFs(client Interface, protocol string) ([]string, error) {
+	lifResponse, err := client.NetInterfaceGet()
 	if err = GetError(lifResponse, err); err != nil {
 		return nil, fmt.Errorf("error checking network interfaces: %v", err)
 	}
@@ -0,0 +1,2838 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package api
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netapp/trident/metrics"
+	"github.com/netapp/trident/storage_drivers/ontap/api/azgo"
+	"github.com/netapp/trident/utils"
+)
+
+// restTimeoutSecs is how long to wait for the response to a single ONTAP REST API call
+const restTimeoutSecs = 60
+
+// maxRestJobWaitSecs is how long to wait for the jobs started by asynchronous ONTAP REST API calls
+const maxRestJobWaitSecs = 300
+
+var restResourceSegmentRegex = regexp.MustCompile(`^[a-z_-]*$`)
+
+var restDurationRegex = regexp.MustCompile(`^P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)(?:\.\d+)?S)?)?$`)
+
+// RestClient is the object to use for interacting with ONTAP controllers using the ONTAP REST API.  It
+// offers the same operations as Client and reports their results in the same AZGO responses, so the
+// drivers may use either one.
+type RestClient struct {
+	config        ClientConfig
+	ontapiVersion *string
+}
+
+// NewRestClient is a factory method for creating a new instance
+func NewRestClient(config ClientConfig) *RestClient {
+	return &RestClient{
+		config:        config,
+		ontapiVersion: new(string),
+	}
+}
+
+/////////////////////////////////////////////////////////////////////////////
+// REST API plumbing BEGIN
+
+// restError describes an ONTAP REST API call that failed, either immediately or in the job it started
+type restError struct {
+	statusCode int
+	code       string
+	message    string
+	errno      string
+}
+
+func (e restError) Error() string {
+	return fmt.Sprintf("REST API status: %d, Message: %s, Code: %s", e.statusCode, e.message, e.code)
+}
+
+// zapiErrno returns the ZAPI error number that corresponds most closely to a failed REST API call
+func (e restError) zapiErrno() string {
+	if e.errno != "" {
+		return e.errno
+	}
+	switch e.statusCode {
+	case http.StatusNotFound:
+		return azgo.EOBJECTNOTFOUND
+	case http.StatusConflict:
+		return azgo.EDUPLICATEENTRY
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return azgo.EAPIPRIVILEGE
+	default:
+		return azgo.EAPIERROR
+	}
+}
+
+// withErrno overrides the ZAPI error number reported for a REST API call that failed with the specified
+// HTTP status, so that callers see the same error a ZAPI would have returned.
+func withErrno(err error, statusCode int, errno string) error {
+	if rerr, ok := err.(restError); ok && rerr.statusCode == statusCode {
+		rerr.errno = errno
+		return rerr
+	}
+	return err
+}
+
+// setRestResult records the outcome of a REST API call in the embedded result of an AZGO response, so
+// that callers may examine it with GetError and NewZapiError just as they would a ZAPI result.  Errors
+// that ONTAP didn't report, such as transport errors, are returned as is.
+func setRestResult(response interface{}, err error) error {
+
+	result := reflect.ValueOf(response).Elem().FieldByName("Result")
+
+	switch rerr := err.(type) {
+	case nil:
+		result.FieldByName("ResultStatusAttr").SetString("passed")
+	case restError:
+		result.FieldByName("ResultStatusAttr").SetString("failed")
+		result.FieldByName("ResultReasonAttr").SetString(rerr.message)
+		result.FieldByName("ResultErrnoAttr").SetString(rerr.zapiErrno())
+	default:
+		return err
+	}
+	return nil
+}
+
+// restErrorResponse is the body of the response to a failed REST API call
+type restErrorResponse struct {
+	Error struct {
+		Message string `json:"message"`
+		Code    string `json:"code"`
+	} `json:"error"`
+}
+
+// invokeAPI sends a request to the ONTAP REST API and decodes the JSON body of the response, if any, into
+// responseBody.  A response with a status of 300 or more is returned as a restError.
+func (d RestClient) invokeAPI(
+	method, resourcePath string, query url.Values, requestBody, responseBody interface{},
+) error {
+
+	if d.config.DebugTraceFlags["method"] {
+		fields := log.Fields{"Method": "invokeAPI", "Type": "RestClient"}
+		log.WithFields(fields).Debug(">>>> invokeAPI")
+		defer log.WithFields(fields).Debug("<<<< invokeAPI")
+	}
+
+	requestURL := "https://" + d.config.ManagementLIF + "/api" + resourcePath
+	if len(query) > 0 {
+		requestURL += "?" + query.Encode()
+	}
+
+	var requestJSON []byte
+	if requestBody != nil {
+		var err error
+		if requestJSON, err = json.Marshal(requestBody); err != nil {
+			return fmt.Errorf("could not encode request to %s %s: %v", method, resourcePath, err)
+		}
+	}
+
+	request, err := http.NewRequest(method, requestURL, bytes.NewBuffer(requestJSON))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Content-Type", "application/json")
+
+	// Log the request before the credentials are added to it
+	if d.config.DebugTraceFlags["api"] {
+		var prettyJSON bytes.Buffer
+		json.Indent(&prettyJSON, requestJSON, "", "  ")
+		utils.LogHTTPRequest(request, prettyJSON.Bytes())
+	}
+	request.SetBasicAuth(d.config.Username, d.config.Password)
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+		Timeout: time.Duration(restTimeoutSecs * time.Second),
+	}
+	apiMethod := method + " " + getRestResourceName(resourcePath)
+	start := time.Now()
+	response, err := client.Do(request)
+	if err != nil {
+		metrics.ObserveStorageAPICall("ontap-rest", apiMethod, start, metrics.CodeTransportError)
+		return err
+	}
+	defer response.Body.Close()
+
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		metrics.ObserveStorageAPICall("ontap-rest", apiMethod, start, metrics.CodeTransportError)
+		return err
+	}
+
+	errorCode := ""
+	if response.StatusCode >= 300 {
+		errorCode = strconv.Itoa(response.StatusCode)
+	}
+	metrics.ObserveStorageAPICall("ontap-rest", apiMethod, start, errorCode)
+
+	if d.config.DebugTraceFlags["api"] {
+		var prettyJSON bytes.Buffer
+		json.Indent(&prettyJSON, body, "", "  ")
+		utils.LogHTTPResponse(response, prettyJSON.Bytes())
+	}
+
+	if response.StatusCode >= 300 {
+		var errorResponse restErrorResponse
+		json.Unmarshal(body, &errorResponse)
+		if errorResponse.Error.Message == "" {
+			errorResponse.Error.Message = http.StatusText(response.StatusCode)
+		}
+		return restError{
+			statusCode: response.StatusCode,
+			code:       errorResponse.Error.Code,
+			message:    errorResponse.Error.Message,
+		}
+	}
+
+	if responseBody != nil && len(bytes.TrimSpace(body)) > 0 {
+		if err = json.Unmarshal(body, responseBody); err != nil {
+			return fmt.Errorf("could not decode response from %s %s: %v", method, resourcePath, err)
+		}
+	}
+
+	return nil
+}
+
+// getRestResourceName returns a resource path with the object references replaced
+// by a placeholder, so that calls to the same API can be grouped together.
+func getRestResourceName(resourcePath string) string {
+	segments := strings.Split(resourcePath, "/")
+	for i, segment := range segments {
+		if !restResourceSegmentRegex.MatchString(segment) {
+			segments[i] = "{ref}"
+		}
+	}
+	if name := strings.Join(segments, "/"); name != "" {
+		return name
+	}
+	return "/"
+}
+
+// restJobResponse is the body of the response to a REST API call that completes asynchronously
+type restJobResponse struct {
+	Job *restNamed `json:"job,omitempty"`
+}
+
+// restJob is the state of a job started by an asynchronous REST API call
+type restJob struct {
+	UUID    string `json:"uuid"`
+	State   string `json:"state"`
+	Message string `json:"message"`
+	Code    int    `json:"code"`
+}
+
+// invokeAndWait sends a request to the ONTAP REST API, and if ONTAP completes the request asynchronously,
+// waits for the job it started to finish.
+func (d RestClient) invokeAndWait(method, resourcePath string, query url.Values, requestBody interface{}) error {
+
+	var response restJobResponse
+	if err := d.invokeAPI(method, resourcePath, query, requestBody, &response); err != nil {
+		return err
+	}
+	if response.Job == nil || response.Job.UUID == "" {
+		return nil
+	}
+
+	return d.waitForJob(response.Job.UUID, maxRestJobWaitSecs)
+}
+
+// waitForJob waits for a job to finish, and returns a restError if the job fails or doesn't finish in time
+func (d RestClient) waitForJob(jobUUID string, maxWaitSecs int) error {
+
+	query := url.Values{"fields": {"state,message,code"}}
+
+	timeout := time.Now().Add(time.Duration(maxWaitSecs) * time.Second)
+	for {
+		var job restJob
+		if err := d.invokeAPI("GET", "/cluster/jobs/"+jobUUID, query, nil, &job); err != nil {
+			return fmt.Errorf("error reading job %s: %v", jobUUID, err)
+		}
+
+		switch job.State {
+		case "success":
+			return nil
+		case "failure":
+			return restError{
+				statusCode: http.StatusOK,
+				code:       strconv.Itoa(job.Code),
+				message:    job.Message,
+				errno:      azgo.EAPIERROR,
+			}
+		}
+
+		// Don't wait forever
+		if time.Now().After(timeout) {
+			return fmt.Errorf("job %s did not finish within %d seconds", jobUUID, maxWaitSecs)
+		}
+
+		log.WithField("job", jobUUID).Debug("Job not yet finished, polling...")
+		time.Sleep(1 * time.Second)
+	}
+}
+
+// restCollection is the body of the response to a REST API call that lists a collection of objects
+type restCollection struct {
+	Records    interface{} `json:"records"`
+	NumRecords int         `json:"num_records"`
+}
+
+// getRecords reads the objects in a collection that match a query into records, which must point to a
+// slice, and returns the number of objects read.
+func (d RestClient) getRecords(resourcePath string, query url.Values, fields string, records interface{}) (int, error) {
+
+	if query == nil {
+		query = url.Values{}
+	}
+	if fields != "" {
+		query.Set("fields", fields)
+	}
+
+	if err := d.invokeAPI("GET", resourcePath, query, nil, &restCollection{Records: records}); err != nil {
+		return 0, err
+	}
+
+	return reflect.ValueOf(records).Elem().Len(), nil
+}
+
+// getUniqueRecord reads the only object in a collection that matches a query into record, which must point
+// to a struct.  If no object matches, the error is a restError with a status of 404 (Not Found).
+func (d RestClient) getUniqueRecord(
+	resourcePath string, query url.Values, fields string, record interface{}, description string,
+) error {
+
+	records := reflect.New(reflect.SliceOf(reflect.TypeOf(record).Elem()))
+
+	count, err := d.getRecords(resourcePath, query, fields, records.Interface())
+	if err != nil {
+		return err
+	}
+
+	switch count {
+	case 0:
+		return restError{statusCode: http.StatusNotFound, message: description + " not found"}
+	case 1:
+		reflect.ValueOf(record).Elem().Set(records.Elem().Index(0))
+		return nil
+	default:
+		return fmt.Errorf("more than one %s found", description)
+	}
+}
+
+// svmQuery returns a query limited to the configured SVM, plus the specified parameter names and values.
+// Parameters with empty values are left out; use restQueryValue to match an empty string.
+func (d RestClient) svmQuery(namesAndValues ...string) url.Values {
+	query := url.Values{}
+	if d.config.SVM != "" {
+		query.Set("svm.name", d.config.SVM)
+	}
+	for i := 0; i+1 < len(namesAndValues); i += 2 {
+		if namesAndValues[i+1] != "" {
+			query.Set(namesAndValues[i], namesAndValues[i+1])
+		}
+	}
+	return query
+}
+
+// svm returns a reference to the configured SVM, for use in the body of a request
+func (d RestClient) svm() *restNamed {
+	if d.config.SVM == "" {
+		return nil
+	}
+	return &restNamed{Name: d.config.SVM}
+}
+
+// restQueryValue returns a value for a REST API query that matches the specified string, which the REST
+// API would otherwise ignore if it were empty
+func restQueryValue(value string) string {
+	if value == "" {
+		return `""`
+	}
+	return value
+}
+
+func newInt(value int) *int {
+	return &value
+}
+
+func newBool(value bool) *bool {
+	return &value
+}
+
+func newString(value string) *string {
+	return &value
+}
+
+// REST API plumbing END
+/////////////////////////////////////////////////////////////////////////////
+
+/////////////////////////////////////////////////////////////////////////////
+// REST API object definitions BEGIN
+
+// restNamed is a reference to an object, by name or by UUID
+type restNamed struct {
+	Name string `json:"name,omitempty"`
+	UUID string `json:"uuid,omitempty"`
+}
+
+type restIgroup struct {
+	UUID       string                `json:"uuid,omitempty"`
+	Name       string                `json:"name,omitempty"`
+	SVM        *restNamed            `json:"svm,omitempty"`
+	Protocol   string                `json:"protocol,omitempty"`
+	OsType     string                `json:"os_type,omitempty"`
+	Initiators []restIgroupInitiator `json:"initiators,omitempty"`
+}
+
+type restIgroupInitiator struct {
+	Name string `json:"name"`
+}
+
+type restLun struct {
+	UUID         string           `json:"uuid,omitempty"`
+	Name         string           `json:"name,omitempty"`
+	SVM          *restNamed       `json:"svm,omitempty"`
+	OsType       string           `json:"os_type,omitempty"`
+	Enabled      *bool            `json:"enabled,omitempty"`
+	SerialNumber string           `json:"serial_number,omitempty"`
+	Space        *restLunSpace    `json:"space,omitempty"`
+	Location     *restLunLocation `json:"location,omitempty"`
+	Clone        *restLunClone    `json:"clone,omitempty"`
+}
+
+type restLunSpace struct {
+	Size      *int                   `json:"size,omitempty"`
+	Guarantee *restLunSpaceGuarantee `json:"guarantee,omitempty"`
+}
+
+type restLunSpaceGuarantee struct {
+	Requested *bool `json:"requested,omitempty"`
+}
+
+type restLunLocation struct {
+	Volume *restNamed `json:"volume,omitempty"`
+}
+
+type restLunClone struct {
+	Source *restNamed `json:"source,omitempty"`
+}
+
+type restLunAttribute struct {
+	Name  string `json:"name,omitempty"`
+	Value string `json:"value"`
+}
+
+type restLunMap struct {
+	SVM               *restNamed `json:"svm,omitempty"`
+	Igroup            *restNamed `json:"igroup,omitempty"`
+	Lun               *restNamed `json:"lun,omitempty"`
+	LogicalUnitNumber *int       `json:"logical_unit_number,omitempty"`
+}
+
+type restVolume struct {
+	UUID                           string               `json:"uuid,omitempty"`
+	Name                           string               `json:"name,omitempty"`
+	SVM                            *restNamed           `json:"svm,omitempty"`
+	Style                          string               `json:"style,omitempty"`
+	Type                           string               `json:"type,omitempty"`
+	State                          string               `json:"state,omitempty"`
+	Size                           *int                 `json:"size,omitempty"`
+	Aggregates                     []restNamed          `json:"aggregates,omitempty"`
+	Guarantee                      *restVolumeGuarantee `json:"guarantee,omitempty"`
+	SnapshotPolicy                 *restNamed           `json:"snapshot_policy,omitempty"`
+	SnapshotDirectoryAccessEnabled *bool                `json:"snapshot_directory_access_enabled,omitempty"`
+	Space                          *restVolumeSpace     `json:"space,omitempty"`
+	NAS                            *restVolumeNAS       `json:"nas,omitempty"`
+	QoS                            *restVolumeQoS       `json:"qos,omitempty"`
+	Encryption                     *restVolumeEncrypt   `json:"encryption,omitempty"`
+	Clone                          *restVolumeClone     `json:"clone,omitempty"`
+	Quota                          *restVolumeQuota     `json:"quota,omitempty"`
+	RestoreTo                      *restVolumeRestoreTo `json:"restore_to,omitempty"`
+}
+
+type restVolumeGuarantee struct {
+	Type string `json:"type,omitempty"`
+}
+
+type restVolumeSpace struct {
+	Snapshot *restVolumeSnapshotSpace `json:"snapshot,omitempty"`
+}
+
+type restVolumeSnapshotSpace struct {
+	ReservePercent *int `json:"reserve_percent,omitempty"`
+}
+
+type restVolumeNAS struct {
+	Path            *string    `json:"path,omitempty"`
+	ExportPolicy    *restNamed `json:"export_policy,omitempty"`
+	UnixPermissions *int       `json:"unix_permissions,omitempty"`
+	SecurityStyle   string     `json:"security_style,omitempty"`
+}
+
+type restVolumeQoS struct {
+	Policy *restNamed `json:"policy,omitempty"`
+}
+
+type restVolumeEncrypt struct {
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+type restVolumeClone struct {
+	ParentVolume   *restNamed `json:"parent_volume,omitempty"`
+	ParentSnapshot *restNamed `json:"parent_snapshot,omitempty"`
+	IsFlexclone    *bool      `json:"is_flexclone,omitempty"`
+	SplitInitiated *bool      `json:"split_initiated,omitempty"`
+}
+
+type restVolumeQuota struct {
+	Enabled *bool  `json:"enabled,omitempty"`
+	State   string `json:"state,omitempty"`
+}
+
+type restVolumeRestoreTo struct {
+	Snapshot *restNamed `json:"snapshot,omitempty"`
+}
+
+// restVolumeFields are the volume fields needed to describe a volume in the same detail as ZAPI does
+const restVolumeFields = "uuid,name,style,type,state,size,aggregates.name,guarantee.type,snapshot_policy.name," +
+	"snapshot_directory_access_enabled,space.snapshot.reserve_percent,nas.path,nas.export_policy.name," +
+	"nas.unix_permissions,nas.security_style,qos.policy.name,encryption.enabled"
+
+type restQtree struct {
+	ID              *int       `json:"id,omitempty"`
+	Name            string     `json:"name,omitempty"`
+	SVM             *restNamed `json:"svm,omitempty"`
+	Volume          *restNamed `json:"volume,omitempty"`
+	SecurityStyle   string     `json:"security_style,omitempty"`
+	UnixPermissions *int       `json:"unix_permissions,omitempty"`
+	ExportPolicy    *restNamed `json:"export_policy,omitempty"`
+}
+
+const restQtreeFields = "id,name,volume.name,volume.uuid,security_style,unix_permissions,export_policy.name"
+
+type restQuotaRule struct {
+	UUID   string          `json:"uuid,omitempty"`
+	SVM    *restNamed      `json:"svm,omitempty"`
+	Volume *restNamed      `json:"volume,omitempty"`
+	Type   string          `json:"type,omitempty"`
+	Qtree  *restNamed      `json:"qtree,omitempty"`
+	Space  *restQuotaSpace `json:"space,omitempty"`
+}
+
+type restQuotaSpace struct {
+	HardLimit *int `json:"hard_limit,omitempty"`
+}
+
+type restExportPolicy struct {
+	ID   *int       `json:"id,omitempty"`
+	Name string     `json:"name,omitempty"`
+	SVM  *restNamed `json:"svm,omitempty"`
+}
+
+type restExportRule struct {
+	Index     *int               `json:"index,omitempty"`
+	Clients   []restExportClient `json:"clients,omitempty"`
+	Protocols []string           `json:"protocols,omitempty"`
+	RoRule    []string           `json:"ro_rule,omitempty"`
+	RwRule    []string           `json:"rw_rule,omitempty"`
+	Superuser []string           `json:"superuser,omitempty"`
+}
+
+type restExportClient struct {
+	Match string `json:"match"`
+}
+
+type restSnapshot struct {
+	UUID       string `json:"uuid,omitempty"`
+	Name       string `json:"name,omitempty"`
+	CreateTime string `json:"create_time,omitempty"`
+}
+
+type restQosPolicy struct {
+	UUID        string        `json:"uuid,omitempty"`
+	Name        string        `json:"name,omitempty"`
+	SVM         *restNamed    `json:"svm,omitempty"`
+	Fixed       *restQosFixed `json:"fixed,omitempty"`
+	ObjectCount *int          `json:"object_count,omitempty"`
+}
+
+type restQosFixed struct {
+	MaxThroughputIOPS *int `json:"max_throughput_iops,omitempty"`
+	MaxThroughputMBPS *int `json:"max_throughput_mbps,omitempty"`
+	MinThroughputIOPS *int `json:"min_throughput_iops,omitempty"`
+	MinThroughputMBPS *int `json:"min_throughput_mbps,omitempty"`
+}
+
+type restIscsiService struct {
+	SVM     *restNamed       `json:"svm,omitempty"`
+	Enabled *bool            `json:"enabled,omitempty"`
+	Target  *restIscsiTarget `json:"target,omitempty"`
+}
+
+type restIscsiTarget struct {
+	Name  string `json:"name,omitempty"`
+	Alias string `json:"alias,omitempty"`
+}
+
+type restSVM struct {
+	UUID       string             `json:"uuid,omitempty"`
+	Name       string             `json:"name,omitempty"`
+	Aggregates []restSVMAggregate `json:"aggregates,omitempty"`
+}
+
+type restSVMAggregate struct {
+	Name          string `json:"name,omitempty"`
+	Type          string `json:"type,omitempty"`
+	AvailableSize *int   `json:"available_size,omitempty"`
+}
+
+type restAggregate struct {
+	Name         string                     `json:"name,omitempty"`
+	Space        *restAggregateSpace        `json:"space,omitempty"`
+	BlockStorage *restAggregateBlockStorage `json:"block_storage,omitempty"`
+}
+
+type restAggregateSpace struct {
+	BlockStorage *restAggregateSpaceBlockStorage `json:"block_storage,omitempty"`
+}
+
+type restAggregateSpaceBlockStorage struct {
+	Size      *int `json:"size,omitempty"`
+	Available *int `json:"available,omitempty"`
+}
+
+type restAggregateBlockStorage struct {
+	Primary     *restAggregatePrimary     `json:"primary,omitempty"`
+	HybridCache *restAggregateHybridCache `json:"hybrid_cache,omitempty"`
+}
+
+type restAggregatePrimary struct {
+	DiskClass string `json:"disk_class,omitempty"`
+}
+
+type restAggregateHybridCache struct {
+	Enabled bool `json:"enabled"`
+}
+
+type restSnapmirror struct {
+	UUID             string                  `json:"uuid,omitempty"`
+	Source           *restSnapmirrorEndpoint `json:"source,omitempty"`
+	Destination      *restSnapmirrorEndpoint `json:"destination,omitempty"`
+	Policy           *restNamed              `json:"policy,omitempty"`
+	TransferSchedule *restNamed              `json:"transfer_schedule,omitempty"`
+	State            string                  `json:"state,omitempty"`
+	Healthy          *bool                   `json:"healthy,omitempty"`
+	UnhealthyReason  []restSnapmirrorReason  `json:"unhealthy_reason,omitempty"`
+	LagTime          string                  `json:"lag_time,omitempty"`
+	Transfer         *restSnapmirrorTransfer `json:"transfer,omitempty"`
+}
+
+type restSnapmirrorEndpoint struct {
+	Path string `json:"path,omitempty"`
+}
+
+type restSnapmirrorReason struct {
+	Message string `json:"message"`
+}
+
+type restSnapmirrorTransfer struct {
+	State string `json:"state,omitempty"`
+}
+
+const restSnapmirrorFields = "uuid,source.path,destination.path,policy.name,transfer_schedule.name,state,healthy," +
+	"unhealthy_reason,lag_time,transfer.state"
+
+// restCLISnapmirror is a SnapMirror relationship as reported by the CLI passthrough, which is the only
+// way the REST API offers to manage load-sharing mirrors
+type restCLISnapmirror struct {
+	SourcePath string `json:"source_path,omitempty"`
+	Status     string `json:"status,omitempty"`
+}
+
+type restInterface struct {
+	Name     string         `json:"name,omitempty"`
+	IP       *restIPAddress `json:"ip,omitempty"`
+	Services []string       `json:"services,omitempty"`
+}
+
+type restIPAddress struct {
+	Address string `json:"address,omitempty"`
+}
+
+type restCluster struct {
+	Version struct {
+		Full       string `json:"full"`
+		Generation int    `json:"generation"`
+		Major      int    `json:"major"`
+		Minor      int    `json:"minor"`
+	} `json:"version"`
+}
+
+type restNode struct {
+	Name         string `json:"name,omitempty"`
+	SerialNumber string `json:"serial_number,omitempty"`
+}
+
+type restEmsApplicationLog struct {
+	ComputerName        string `json:"computer_name"`
+	EventID             int    `json:"event_id"`
+	EventDescription    string `json:"event_description"`
+	EventSource         string `json:"event_source"`
+	AppVersion          string `json:"app_version"`
+	Category            string `json:"category"`
+	Severity            string `json:"severity"`
+	AutosupportRequired bool   `json:"autosupport_required"`
+}
+
+// REST API object definitions END
+/////////////////////////////////////////////////////////////////////////////
+
+/////////////////////////////////////////////////////////////////////////////
+// API feature operations BEGIN
+
+// SupportsFeature returns true if the Ontapi version supports the supplied feature
+func (d RestClient) SupportsFeature(feature feature) bool {
+	return supportsFeature(d, feature)
+}
+
+// API feature operations END
+/////////////////////////////////////////////////////////////////////////////
+
+/////////////////////////////////////////////////////////////////////////////
+// IGROUP operations BEGIN
+
+// getIgroup reads the initiator group with the specified name
+func (d RestClient) getIgroup(initiatorGroupName string) (restIgroup, error) {
+	var igroup restIgroup
+	err := d.getUniqueRecord("/protocols/san/igroups", d.svmQuery("name", initiatorGroupName), "uuid,name",
+		&igroup, "initiator group "+initiatorGroupName)
+	return igroup, err
+}
+
+// IgroupCreate creates the specified initiator group
+// equivalent to POST /api/protocols/san/igroups
+func (d RestClient) IgroupCreate(initiatorGroupName, initiatorGroupType, osType string) (response azgo.IgroupCreateResponse, err error) {
+	igroup := restIgroup{
+		Name:     initiatorGroupName,
+		SVM:      d.svm(),
+		Protocol: initiatorGroupType,
+		OsType:   osType,
+	}
+	err = d.invokeAPI("POST", "/protocols/san/igroups", nil, igroup, nil)
+	err = setRestResult(&response, withErrno(err, http.StatusConflict, azgo.EVDISK_ERROR_INITGROUP_EXISTS))
+	return
+}
+
+// IgroupAdd adds an initiator to an initiator group
+// equivalent to POST /api/protocols/san/igroups/{igroup.uuid}/initiators
+func (d RestClient) IgroupAdd(initiatorGroupName, initiator string) (response azgo.IgroupAddResponse, err error) {
+	igroup, err := d.getIgroup(initiatorGroupName)
+	if err == nil {
+		err = d.invokeAPI("POST", "/protocols/san/igroups/"+igroup.UUID+"/initiators", nil,
+			restIgroupInitiator{Name: initiator}, nil)
+	}
+	err = setRestResult(&response, withErrno(err, http.StatusConflict, azgo.EVDISK_ERROR_INITGROUP_HAS_NODE))
+	return
+}
+
+// IgroupRemove removes an initiator from an initiator group
+// equivalent to DELETE /api/protocols/san/igroups/{igroup.uuid}/initiators/{name}
+func (d RestClient) IgroupRemove(initiatorGroupName, initiator string, force bool) (response azgo.IgroupRemoveResponse, err error) {
+	igroup, err := d.getIgroup(initiatorGroupName)
+	if err == nil {
+		query := url.Values{"allow_delete_while_mapped": {strconv.FormatBool(force)}}
+		err = d.invokeAPI("DELETE", "/protocols/san/igroups/"+igroup.UUID+"/initiators/"+url.PathEscape(initiator),
+			query, nil, nil)
+	}
+	err = setRestResult(&response, err)
+	return
+}
+
+// IgroupDestroy destroys an initiator group
+// equivalent to DELETE /api/protocols/san/igroups/{uuid}
+func (d RestClient) IgroupDestroy(initiatorGroupName string) (response azgo.IgroupDestroyResponse, err error) {
+	igroup, err := d.getIgroup(initiatorGroupName)
+	if err == nil {
+		err = d.invokeAPI("DELETE", "/protocols/san/igroups/"+igroup.UUID, nil, nil, nil)
+	}
+	err = setRestResult(&response, err)
+	return
+}
+
+// IgroupList lists initiator groups
+// equivalent to GET /api/protocols/san/igroups
+func (d RestClient) IgroupList() (response azgo.IgroupGetIterResponse, err error) {
+	var igroups []restIgroup
+	_, err = d.getRecords("/protocols/san/igroups", d.svmQuery(), "uuid,name,protocol,os_type,initiators.name",
+		&igroups)
+	if err == nil {
+		igroupInfos := make([]azgo.InitiatorGroupInfoType, 0, len(igroups))
+		for _, igroup := range igroups {
+			initiators := make([]azgo.InitiatorInfoType, 0, len(igroup.Initiators))
+			for _, initiator := range igroup.Initiators {
+				initiators = append(initiators, *azgo.NewInitiatorInfoType().SetInitiatorName(initiator.Name))
+			}
+			igroupInfo := azgo.NewInitiatorGroupInfoType().
+				SetInitiatorGroupName(igroup.Name).
+				SetInitiatorGroupUuid(igroup.UUID).
+				SetInitiatorGroupType(igroup.Protocol).
+				SetInitiatorGroupOsType(igroup.OsType).
+				SetInitiators(initiators)
+			igroupInfos = append(igroupInfos, *igroupInfo)
+		}
+		response.Result.SetAttributesList(igroupInfos).SetNumRecords(len(igroupInfos))
+	}
+	err = setRestResult(&response, err)
+	return
+}
+
+// IGROUP operations END
+/////////////////////////////////////////////////////////////////////////////
+
+/////////////////////////////////////////////////////////////////////////////
+// LUN operations BEGIN
+
+// getLun reads the LUN at the specified path
+func (d RestClient) getLun(lunPath, fields string) (restLun, error) {
+	var lun restLun
+	err := d.getUniqueRecord("/storage/luns", d.svmQuery("name", lunPath), fields, &lun, "LUN "+lunPath)
+	return lun, err
+}
+
+// lunInfo converts a LUN read with the REST API into the form returned by ZAPI
+func (lun restLun) lunInfo() azgo.LunInfoType {
+	volume, size := "", 0
+	if lun.Location != nil && lun.Location.Volume != nil {
+		volume = lun.Location.Volume.Name
+	}
+	if lun.Space != nil && lun.Space.Size != nil {
+		size = *lun.Space.Size
+	}
+	return *azgo.NewLunInfoType().
+		SetPath(lun.Name).
+		SetVolume(volume).
+		SetSize(size).
+		SetUuid(lun.UUID)
+}
+
+// getLuns reads all LUNs matching a query, in the form returned by ZAPI
+func (d RestClient) getLuns(query url.Values) (response azgo.LunGetIterResponse, err error) {
+	var luns []restLun
+	_, err = d.getRecords("/storage/luns", query, "uuid,name,location.volume.name,space.size", &luns)
+	if err == nil {
+		lunInfos := make([]azgo.LunInfoType, 0, len(luns))
+		for _, lun := range luns {
+			lunInfos = append(lunInfos, lun.lunInfo())
+		}
+		response.Result.SetAttributesList(lunInfos).SetNumRecords(len(lunInfos))
+	}
+	err = setRestResult(&response, err)
+	return
+}
+
+// LunCreate creates a lun with the specified attributes
+// equivalent to POST /api/storage/luns
+func (d RestClient) LunCreate(lunPath string, sizeInBytes int, osType string, spaceReserved bool) (response azgo.LunCreateBySizeResponse, err error) {
+	lun := restLun{
+		Name:   lunPath,
+		SVM:    d.svm(),
+		OsType: osType,
+		Space: &restLunSpace{
+			Size:      newInt(sizeInBytes),
+			Guarantee: &restLunSpaceGuarantee{Requested: newBool(spaceReserved)},
+		},
+	}
+	err = setRestResult(&response, d.invokeAPI("POST", "/storage/luns", nil, lun, nil))
+	return
+}
+
+// LunGetSerialNumber returns the serial# for a lun
+// equivalent to GET /api/storage/luns?fields=serial_number
+func (d RestClient) LunGetSerialNumber(lunPath string) (response azgo.LunGetSerialNumberResponse, err error) {
+	lun, err := d.getLun(lunPath, "uuid,serial_number")
+	if err == nil {
+		response.Result.SetSerialNumber(lun.SerialNumber)
+	}
+	err = setRestResult(&response, err)
+	return
+}
+
+// lunMap maps a lun in an initiator group, with the specified LUN ID unless it is nil, and returns the
+// LUN ID in use
+func (d RestClient) lunMap(initiatorGroupName, lunPath string, lunID *int) (int, error) {
+
+	lunMap := restLunMap{
+		SVM:               d.svm(),
+		Igroup:            &restNamed{Name: initiatorGroupName},
+		Lun:               &restNamed{Name: lunPath},
+		LogicalUnitNumber: lunID,
+	}
+	if err := d.invokeAPI("POST", "/protocols/san/lun-maps", nil, lunMap, nil); err != nil {
+		return -1, err
+	}
+
+	// Read back the LUN ID, which ONTAP may have chosen
+	query := d.svmQuery("igroup.name", initiatorGroupName, "lun.name", lunPath)
+	if err := d.getUniqueRecord("/protocols/san/lun-maps", query, "logical_unit_number", &lunMap,
+		"map of LUN "+lunPath); err != nil {
+		return -1, err
+	}
+	if lunMap.LogicalUnitNumber == nil {
+		return -1, fmt.Errorf("map of LUN %s has no LUN ID", lunPath)
+	}
+
+	return *lunMap.LogicalUnitNumber, nil
+}
+
+// LunMap maps a lun to an id in an initiator group
+// equivalent to POST /api/protocols/san/lun-maps
+func (d RestClient) LunMap(initiatorGroupName, lunPath string, lunID int) (response azgo.LunMapResponse, err error) {
+	lunIDAssigned, err := d.lunMap(initiatorGroupName, lunPath, &lunID)
+	if err == nil {
+		response.Result.SetLunIdAssigned(lunIDAssigned)
+	}
+	err = setRestResult(&response, err)
+	return
+}
+
+// LunMapAutoID maps a LUN in an initiator group, allowing ONTAP to choose an available LUN ID
+// equivalent to POST /api/protocols/san/lun-maps
+func (d RestClient) LunMapAutoID(initiatorGroupName, lunPath string) (response azgo.LunMapResponse, err error) {
+	lunIDAssigned, err := d.lunMap(initiatorGroupName, lunPath, nil)
+	if err == nil {
+		response.Result.SetLunIdAssigned(lunIDAssigned)
+	}
+	err = setRestResult(&response, err)
+	return
+}
+
+// LunMapIfNotMapped maps a LUN in an initiator group unless it is mapped there already, and returns its LUN ID
+func (d RestClient) LunMapIfNotMapped(initiatorGroupName, lunPath string) (int, error) {
+	return lunMapIfNotMapped(d, initiatorGroupName, lunPath)
+}
+
+// LunMapListInfo returns lun mapping information for the specified lun
+// equivalent to GET /api/protocols/san/lun-maps
+func (d RestClient) LunMapListInfo(lunPath string) (response azgo.LunMapListInfoResponse, err error) {
+	var lunMaps []restLunMap
+	_, err = d.getRecords("/protocols/san/lun-maps", d.svmQuery("lun.name", lunPath),
+		"igroup.name,logical_unit_number", &lunMaps)
+	if err == nil {
+		igroupInfos := make([]azgo.InitiatorGroupInfoType, 0, len(lunMaps))
+		for _, lunMap := range lunMaps {
+			igroupInfo := azgo.NewInitiatorGroupInfoType()
+			if lunMap.Igroup != nil {
+				igroupInfo.SetInitiatorGroupName(lunMap.Igroup.Name)
+			}
+			if lunMap.LogicalUnitNumber != nil {
+				igroupInfo.SetLunId(*lunMap.LogicalUnitNumber)
+			}
+			igroupInfos = append(igroupInfos, *igroupInfo)
+		}
+		response.Result.SetInitiatorGroups(igroupInfos)
+	}
+	err = setRestResult(&response, err)
+	return
+}
+
+// setLunEnabled brings a lun online or takes it offline
+func (d RestClient) setLunEnabled(lunPath string, enabled bool) error {
+	lun, err := d.getLun(lunPath, "uuid")
+	if err != nil {
+		return err
+	}
+	return d.invokeAPI("PATCH", "/storage/luns/"+lun.UUID, nil, restLun{Enabled: newBool(enabled)}, nil)
+}
+
+// LunOffline offlines a lun
+// equivalent to PATCH /api/storage/luns/{uuid} enabled=false
+func (d RestClient) LunOffline(lunPath string) (response azgo.LunOfflineResponse, err error) {
+	err = setRestResult(&response, d.setLunEnabled(lunPath, false))
+	return
+}
+
+// LunOnline onlines a lun
+// equivalent to PATCH /api/storage/luns/{uuid} enabled=true
+func (d RestClient) LunOnline(lunPath string) (response azgo.LunOnlineResponse, err error) {
+	err = setRestResult(&response, d.setLunEnabled(lunPath, true))
+	return
+}
+
+// LunDestroy destroys a lun, even if it is mapped when force is set
+// equivalent to DELETE /api/storage/luns/{uuid}
+func (d RestClient) LunDestroy(lunPath string, force bool) (response azgo.LunDestroyResponse, err error) {
+	lun, err := d.getLun(lunPath, "uuid")
+	if err == nil {
+		query := url.Values{"allow_delete_while_mapped": {strconv.FormatBool(force)}}
+		err = d.invokeAPI("DELETE", "/storage/luns/"+lun.UUID, query, nil, nil)
+	}
+	err = setRestResult(&response, err)
+	return
+}
+
+// LunCloneCreate clones a LUN within its Flexvol
+// equivalent to POST /api/storage/luns clone.source.name=/vol/v/lun0
+func (d RestClient) LunCloneCreate(volumeName, sourceLun, destinationLun string, spaceReserved bool) (response azgo.CloneCreateResponse, err error) {
+	lun := restLun{
+		Name:  fmt.Sprintf("/vol/%s/%s", volumeName, destinationLun),
+		SVM:   d.svm(),
+		Clone: &restLunClone{Source: &restNamed{Name: fmt.Sprintf("/vol/%s/%s", volumeName, sourceLun)}},
+		Space: &restLunSpace{
+			Guarantee: &restLunSpaceGuarantee{Requested: newBool(spaceReserved)},
+		},
+	}
+	err = setRestResult(&response, d.invokeAPI("POST", "/storage/luns", nil, lun, nil))
+	return
+}
+
+// LunResize resizes a lun
+// equivalent to PATCH /api/storage/luns/{uuid} space.size=10737418240
+func (d RestClient) LunResize(lunPath string, sizeBytes int) (response azgo.LunResizeResponse, err error) {
+	lun, err := d.getLun(lunPath, "uuid")
+	if err == nil {
+		err = d.invokeAPI("PATCH", "/storage/luns/"+lun.UUID, nil,
+			restLun{Space: &restLunSpace{Size: newInt(sizeBytes)}}, nil)
+	}
+	err = setRestResult(&response, err)
+	return
+}
+
+// LunSetAttribute sets a named attribute for a given LUN.
+func (d RestClient) LunSetAttribute(lunPath, name, value string) (response azgo.LunSetAttributeResponse, err error) {
+	lun, err := d.getLun(lunPath, "uuid")
+	if err == nil {
+		attributesPath := "/storage/luns/" + lun.UUID + "/attributes"
+		err = d.invokeAPI("POST", attributesPath, nil, restLunAttribute{Name: name, Value: value}, nil)
+
+		// An attribute that already exists must be modified instead
+		if rerr, ok := err.(restError); ok && rerr.statusCode == http.StatusConflict {
+			err = d.invokeAPI("PATCH", attributesPath+"/"+url.PathEscape(name), nil,
+				restLunAttribute{Value: value}, nil)
+		}
+	}
+	err = setRestResult(&response, err)
+	return
+}
+
+// LunGetAttribute gets a named attribute for a given LUN.
+func (d RestClient) LunGetAttribute(lunPath, name string) (response azgo.LunGetAttributeResponse, err error) {
+	lun, err := d.getLun(lunPath, "uuid")
+	if err == nil {
+		var attribute restLunAttribute
+		err = d.invokeAPI("GET", "/storage/luns/"+lun.UUID+"/attributes/"+url.PathEscape(name), nil, nil,
+			&attribute)
+		if err == nil {
+			response.Result.SetValue(attribute.Value)
+		}
+		err = withErrno(err, http.StatusNotFound, azgo.EVDISK_ERROR_NO_SUCH_ATTRIBUTE)
+	}
+	err = setRestResult(&response, err)
+	return
+}
+
+// LunGet returns all relevant details for a single LUN
+// equivalent to GET /api/storage/luns
+func (d RestClient) LunGet(path string) (azgo.LunInfoType, error) {
+	lun, err := d.getLun(path, "uuid,name,location.volume.name,space.size")
+	if err != nil {
+		return azgo.LunInfoType{}, err
+	}
+	return lun.lunInfo(), nil
+}
+
+// LunGetAll returns all relevant details for all LUNs whose paths match the supplied pattern
+// equivalent to GET /api/storage/luns
+func (d RestClient) LunGetAll(pathPattern string) (response azgo.LunGetIterResponse, err error) {
+	return d.getLuns(d.svmQuery("name", pathPattern))
+}
+
+// LunCount returns the number of LUNs in the specified Flexvol
+func (d RestClient) LunCount(volume string) (int, error) {
+	var luns []restLun
+	return d.getRecords("/storage/luns", d.svmQuery("location.volume.name", volume), "uuid", &luns)
+}
+
+// LunExists returns true if the named LUN exists (and is unique in the matching Flexvols)
+func (d RestClient) LunExists(name, volumePrefix string) (bool, string, error) {
+
+	response, err := d.getLuns(d.svmQuery("name", fmt.Sprintf("/vol/%s*/%s", volumePrefix, name)))
+	if err = GetError(response, err); err != nil {
+		return false, "", err
+	}
+
+	// Ensure LUN is unique
+	if response.Result.NumRecords() != 1 {
+		return false, "", nil
+	}
+
+	return true, response.Result.AttributesList()[0].Volume(), nil
+}
+
+// LUN operations END
+/////////////////////////////////////////////////////////////////////////////
+
+/////////////////////////////////////////////////////////////////////////////
+// VOLUME operations BEGIN
+
+// getVolume reads the volume with the specified name and style, or of any style if style is empty.  If
+// the volume doesn't exist, the error reports the same error number ZAPI does.
+func (d RestClient) getVolume(name, style, fields string) (restVolume, error) {
+	query := d.svmQuery("name", name)
+	if style != "" {
+		query.Set("style", style)
+	}
+	var volume restVolume
+	err := d.getUniqueRecord("/storage/volumes", query, fields, &volume, "volume "+name)
+	return volume, withErrno(err, http.StatusNotFound, azgo.EVOLUMEDOESNOTEXIST)
+}
+
+// modifyVolume applies the changes described by a partial volume to the named volume
+func (d RestClient) modifyVolume(name string, changes restVolume) error {
+	volume, err := d.getVolume(name, "", "uuid")
+	if err != nil {
+		return err
+	}
+	return d.invokeAndWait("PATCH", "/storage/volumes/"+volume.UUID, nil, changes)
+}
+
+// volumeAttributes converts a volume read with the REST API into the form returned by ZAPI
+func (v restVolume) volumeAttributes() azgo.VolumeAttributesType {
+
+	aggregate := ""
+	if len(v.Aggregates) > 0 {
+		aggregate = v.Aggregates[0].Name
+	}
+	idAttrs := azgo.NewVolumeIdAttributesType().
+		SetName(azgo.VolumeNameType(v.Name)).
+		SetContainingAggregateName(aggregate).
+		SetUuid(azgo.UuidType(v.UUID)).
+		SetStyleExtended(v.Style).
+		SetType(v.Type)
+
+	spaceAttrs := azgo.NewVolumeSpaceAttributesType().SetSize(0).SetPercentageSnapshotReserve(0)
+	if v.Size != nil {
+		spaceAttrs.SetSize(*v.Size)
+	}
+	if v.Space != nil && v.Space.Snapshot != nil && v.Space.Snapshot.ReservePercent != nil {
+		spaceAttrs.SetPercentageSnapshotReserve(*v.Space.Snapshot.ReservePercent)
+	}
+	if v.Guarantee != nil {
+		spaceAttrs.SetSpaceGuarantee(v.Guarantee.Type)
+	}
+
+	snapshotAttrs := azgo.NewVolumeSnapshotAttributesType().SetSnapshotPolicy("").SetSnapdirAccessEnabled(false)
+	if v.SnapshotPolicy != nil {
+		snapshotAttrs.SetSnapshotPolicy(v.SnapshotPolicy.Name)
+	}
+	if v.SnapshotDirectoryAccessEnabled != nil {
+		snapshotAttrs.SetSnapdirAccessEnabled(*v.SnapshotDirectoryAccessEnabled)
+	}
+
+	exportAttrs := azgo.NewVolumeExportAttributesType().SetPolicy("")
+	securityUnixAttrs := azgo.NewVolumeSecurityUnixAttributesType().SetPermissions("")
+	securityAttrs := azgo.NewVolumeSecurityAttributesType()
+	if v.NAS != nil {
+		if v.NAS.Path != nil && *v.NAS.Path != "" {
+			idAttrs.SetJunctionPath(azgo.JunctionPathType(*v.NAS.Path))
+		}
+		if v.NAS.ExportPolicy != nil {
+			exportAttrs.SetPolicy(v.NAS.ExportPolicy.Name)
+		}
+		if v.NAS.UnixPermissions != nil {
+			securityUnixAttrs.SetPermissions(symbolicUnixPermissions(*v.NAS.UnixPermissions))
+		}
+		if v.NAS.SecurityStyle != "" {
+			securityAttrs.SetStyle(v.NAS.SecurityStyle)
+		}
+	}
+	securityAttrs.SetVolumeSecurityUnixAttributes(*securityUnixAttrs)
+
+	volumeAttrs := azgo.NewVolumeAttributesType().
+		SetVolumeIdAttributes(*idAttrs).
+		SetVolumeSpaceAttributes(*spaceAttrs).
+		SetVolumeSnapshotAttributes(*snapshotAttrs).
+		SetVolumeExportAttributes(*exportAttrs).
+		SetVolumeSecurityAttributes(*securityAttrs)
+
+	if v.State != "" {
+		volumeAttrs.SetVolumeStateAttributes(*azgo.NewVolumeStateAttributesType().SetState(v.State))
+	}
+	if v.QoS != nil && v.QoS.Policy != nil && v.QoS.Policy.Name != "" {
+		volumeAttrs.SetVolumeQosAttributes(*azgo.NewVolumeQosAttributesType().SetPolicyGroupName(v.QoS.Policy.Name))
+	}
+	if v.Encryption != nil && v.Encryption.Enabled != nil {
+		volumeAttrs.SetEncrypt(*v.Encryption.Enabled)
+	}
+
+	return *volumeAttrs
+}
+
+// getVolumes reads all volumes matching a query, in the form returned by ZAPI
+func (d RestClient) getVolumes(query url.Values, fields string) (response azgo.VolumeGetIterResponse, err error) {
+	var volumes []restVolume
+	_, err = d.getRecords("/storage/volumes", query, fields, &volumes)
+	if err == nil {
+		volumeAttrs := make([]azgo.VolumeAttributesType, 0, len(volumes))
+		for _, volume := range volumes {
+			volumeAttrs = append(volumeAttrs, volume.volumeAttributes())
+		}
+		response.Result.SetAttributesList(volumeAttrs).SetNumRecords(len(volumeAttrs))
+	}
+	err = setRestResult(&response, err)
+	return
+}
+
+// newVolume returns the description of a volume to be created with the specified options
+func (d RestClient) newVolume(name string, aggregates []string, sizeBytes int, spaceReserve, snapshotPolicy,
+	unixPermissions, exportPolicy, securityStyle string, encrypt *bool, qosPolicy, adaptiveQosPolicy string,
+) (restVolume, error) {
+
+	volume := restVolume{
+		Name:      name,
+		SVM:       d.svm(),
+		Size:      newInt(sizeBytes),
+		Guarantee: &restVolumeGuarantee{Type: spaceReserve},
+		NAS: &restVolumeNAS{
+			ExportPolicy:  &restNamed{Name: exportPolicy},
+			SecurityStyle: securityStyle,
+		},
+	}
+	for _, aggregate := range aggregates {
+		volume.Aggregates = append(volume.Aggregates, restNamed{Name: aggregate})
+	}
+	if snapshotPolicy != "" {
+		volume.SnapshotPolicy = &restNamed{Name: snapshotPolicy}
+	}
+	if unixPermissions != "" {
+		permissions, err := restUnixPermissions(unixPermissions)
+		if err != nil {
+			return restVolume{}, err
+		}
+		volume.NAS.UnixPermissions = &permissions
+	}
+	if encrypt != nil {
+		volume.Encryption = &restVolumeEncrypt{Enabled: encrypt}
+	}
+
+	// Fixed and adaptive QoS policies share one name space, and a volume may only have one of them.
+	if qosPolicy != "" {
+		volume.QoS = &restVolumeQoS{Policy: &restNamed{Name: qosPolicy}}
+	} else if adaptiveQosPolicy != "" {
+		volume.QoS = &restVolumeQoS{Policy: &restNamed{Name: adaptiveQosPolicy}}
+	}
+
+	return volume, nil
+}
+
+// VolumeCreate creates a volume with the specified options
+// equivalent to POST /api/storage/volumes
+func (d RestClient) VolumeCreate(name, aggregateName, size, spaceReserve, snapshotPolicy, unixPermissions,
+	exportPolicy, securityStyle string, encrypt *bool, qosPolicy, adaptiveQosPolicy string,
+) (response azgo.VolumeCreateResponse, err error) {
+
+	sizeBytes, err := parseRestSize(size)
+	if err != nil {
+		return
+	}
+
+	volume, err := d.newVolume(name, []string{aggregateName}, sizeBytes, spaceReserve, snapshotPolicy,
+		unixPermissions, exportPolicy, securityStyle, encrypt, qosPolicy, adaptiveQosPolicy)
+	if err != nil {
+		return
+	}
+	volume.Style = "flexvol"
+
+	err = setRestResult(&response, d.invokeAndWait("POST", "/storage/volumes", nil, volume))
+	return
+}
+
+// VolumeCreateDP creates a data protection volume to serve as the destination of a SnapMirror relationship
+// equivalent to POST /api/storage/volumes type=dp
+func (d RestClient) VolumeCreateDP(
+	name, aggregateName, size, exportPolicy string,
+) (response azgo.VolumeCreateResponse, err error) {
+
+	sizeBytes, err := parseRestSize(size)
+	if err != nil {
+		return
+	}
+
+	volume := restVolume{
+		Name:       name,
+		SVM:        d.svm(),
+		Type:       "dp",
+		Size:       newInt(sizeBytes),
+		Aggregates: []restNamed{{Name: aggregateName}},
+	}
+	if exportPolicy != "" {
+		volume.NAS = &restVolumeNAS{ExportPolicy: &restNamed{Name: exportPolicy}}
+	}
+
+	err = setRestResult(&response, d.invokeAndWait("POST", "/storage/volumes", nil, volume))
+	return
+}
+
+// VolumeCloneCreate clones a volume from a snapshot
+// equivalent to POST /api/storage/volumes clone.is_flexclone=true
+func (d RestClient) VolumeCloneCreate(name, source, snapshot string) (response azgo.VolumeCloneCreateResponse, err error) {
+	volume := restVolume{
+		Name: name,
+		SVM:  d.svm(),
+		Clone: &restVolumeClone{
+			ParentVolume: &restNamed{Name: source},
+			IsFlexclone:  newBool(true),
+		},
+	}
+	if snapshot != "" {
+		volume.Clone.ParentSnapshot = &restNamed{Name: snapshot}
+	}
+	err = setRestResult(&response, d.invokeAndWait("POST", "/storage/volumes", nil, volume))
+	return
+}
+
+// VolumeCloneSplitStart splits a cloned volume from its parent
+// equivalent to PATCH /api/storage/volumes/{uuid} clone.split_initiated=true
+func (d RestClient) VolumeCloneSplitStart(name string) (response azgo.VolumeCloneSplitStartResponse, err error) {
+
+	// The split continues in the background, so don't wait for it
+	volume, err := d.getVolume(name, "", "uuid")
+	if err == nil {
+		err = d.invokeAPI("PATCH", "/storage/volumes/"+volume.UUID, nil,
+			restVolume{Clone: &restVolumeClone{SplitInitiated: newBool(true)}}, nil)
+	}
+	err = setRestResult(&response, err)
+	return
+}
+
+// VolumeDisableSnapshotDirectoryAccess disables access to the ".snapshot" directory
+// Disable '.snapshot' to allow official mysql container's chmod-in-init to work
+func (d RestClient) VolumeDisableSnapshotDirectoryAccess(name string) (response azgo.VolumeModifyIterResponse, err error) {
+	err = setRestResult(&response, d.modifyVolume(name, restVolume{SnapshotDirectoryAccessEnabled: newBool(false)}))
+	return
+}
+
+// VolumeExists tests for the existence of a Flexvol
+func (d RestClient) VolumeExists(name string) (bool, error) {
+	return d.volumeExists(name, "flexvol")
+}
+
+// volumeExists tests for the existence of a volume of the specified style
+func (d RestClient) volumeExists(name, style string) (bool, error) {
+	if _, err := d.getVolume(name, style, "uuid"); err != nil {
+		if rerr, ok := err.(restError); ok && rerr.statusCode == http.StatusNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// VolumeSize retrieves the size of the specified volume
+func (d RestClient) VolumeSize(name string) (response azgo.VolumeSizeResponse, err error) {
+	volume, err := d.getVolume(name, "", "uuid,size")
+	if err == nil && volume.Size != nil {
+		response.Result.SetVolumeSize(strconv.Itoa(*volume.Size))
+	}
+	err = setRestResult(&response, err)
+	return
+}
+
+// setVolumeSize sets the size of a volume to a number of bytes, optionally with units, or grows it by
+// that amount if the size is prefixed with '+', and returns the new size
+func (d RestClient) setVolumeSize(name, newSize string) (int, error) {
+
+	volume, err := d.getVolume(name, "", "uuid,size")
+	if err != nil {
+		return 0, err
+	}
+
+	sizeBytes, err := parseRestSize(strings.TrimPrefix(newSize, "+"))
+	if err != nil {
+		return 0, err
+	}
+	if strings.HasPrefix(newSize, "+") {
+		if volume.Size == nil {
+			return 0, fmt.Errorf("could not read size of volume %s", name)
+		}
+		sizeBytes += *volume.Size
+	}
+
+	if err = d.invokeAndWait("PATCH", "/storage/volumes/"+volume.UUID, nil,
+		restVolume{Size: newInt(sizeBytes)}); err != nil {
+		return 0, err
+	}
+	return sizeBytes, nil
+}
+
+// SetVolumeSize sets the size of the specified volume
+func (d RestClient) SetVolumeSize(name, newSize string) (response azgo.VolumeSizeResponse, err error) {
+	sizeBytes, err := d.setVolumeSize(name, newSize)
+	if err == nil {
+		response.Result.SetVolumeSize(strconv.Itoa(sizeBytes))
+	}
+	err = setRestResult(&response, err)
+	return
+}
+
+// VolumeMount mounts a volume at the specified junction
+func (d RestClient) VolumeMount(name, junctionPath string) (response azgo.VolumeMountResponse, err error) {
+	err = setRestResult(&response, d.modifyVolume(name, restVolume{NAS: &restVolumeNAS{Path: &junctionPath}}))
+	return
+}
+
+// VolumeUnmount unmounts a volume from the specified junction
+func (d RestClient) VolumeUnmount(name string, force bool) (response azgo.VolumeUnmountResponse, err error) {
+	err = setRestResult(&response, d.modifyVolume(name, restVolume{NAS: &restVolumeNAS{Path: newString("")}}))
+	return
+}
+
+// VolumeOffline offlines a volume
+func (d RestClient) VolumeOffline(name string) (response azgo.VolumeOfflineResponse, err error) {
+	err = setRestResult(&response, d.modifyVolume(name, restVolume{State: "offline"}))
+	return
+}
+
+// destroyVolume destroys a volume of the specified style, unmounting and offlining it first if force is set
+func (d RestClient) destroyVolume(name, style string, force bool) error {
+
+	volume, err := d.getVolume(name, style, "uuid")
+	if err != nil {
+		return err
+	}
+
+	if force {
+		changes := restVolume{State: "offline", NAS: &restVolumeNAS{Path: newString("")}}
+		if err = d.invokeAndWait("PATCH", "/storage/volumes/"+volume.UUID, nil, changes); err != nil {
+			return err
+		}
+	}
+
+	return d.invokeAndWait("DELETE", "/storage/volumes/"+volume.UUID, nil, nil)
+}
+
+// VolumeDestroy destroys a volume
+func (d RestClient) VolumeDestroy(name string, force bool) (response azgo.VolumeDestroyResponse, err error) {
+	err = setRestResult(&response, d.destroyVolume(name, "", force))
+	return
+}
+
+// VolumeRename changes the name of a volume
+func (d RestClient) VolumeRename(name, newName string) (response azgo.VolumeRenameResponse, err error) {
+	err = setRestResult(&response, d.modifyVolume(name, restVolume{Name: newName}))
+	return
+}
+
+// VolumeGet returns all relevant details for a single Flexvol
+// equivalent to GET /api/storage/volumes style=flexvol
+func (d RestClient) VolumeGet(name string) (azgo.VolumeAttributesType, error) {
+	volume, err := d.getVolume(name, "flexvol", restVolumeFields)
+	if err != nil {
+		return azgo.VolumeAttributesType{}, err
+	}
+	return volume.volumeAttributes(), nil
+}
+
+// VolumeGetAll returns all relevant details for all read-write FlexVols whose names match the supplied prefix
+// equivalent to GET /api/storage/volumes style=flexvol type=rw
+func (d RestClient) VolumeGetAll(prefix string) (response azgo.VolumeGetIterResponse, err error) {
+	return d.getVolumes(d.svmQuery("name", prefix+"*", "style", "flexvol", "type", "rw"), restVolumeFields)
+}
+
+// VolumeList returns the names of all read-write Flexvols whose names match the supplied prefix
+func (d RestClient) VolumeList(prefix string) (response azgo.VolumeGetIterResponse, err error) {
+	return d.getVolumes(d.svmQuery("name", prefix+"*", "style", "flexvol", "type", "rw"), "uuid,name")
+}
+
+// VolumeListByAttrs returns the names of all Flexvols matching the specified attributes
+func (d RestClient) VolumeListByAttrs(
+	prefix, aggregate, spaceReserve, snapshotPolicy string, snapshotDir bool, encrypt *bool,
+	qosPolicy, adaptiveQosPolicy string,
+) (response azgo.VolumeGetIterResponse, err error) {
+
+	query := d.svmQuery(
+		"name", prefix+"*",
+		"style", "flexvol",
+		"aggregates.name", aggregate,
+		"guarantee.type", spaceReserve,
+		"snapshot_policy.name", snapshotPolicy,
+		"snapshot_directory_access_enabled", strconv.FormatBool(snapshotDir),
+	)
+	if encrypt != nil {
+		query.Set("encryption.enabled", strconv.FormatBool(*encrypt))
+	}
+
+	response, err = d.getVolumes(query, "uuid,name,qos.policy.name")
+	if err != nil {
+		return
+	}
+
+	// Volumes without a QoS policy can't be queried for, so the QoS policies are matched here
+	wantedQosPolicy := qosPolicy
+	if adaptiveQosPolicy != "" {
+		wantedQosPolicy = adaptiveQosPolicy
+	}
+	matching := make([]azgo.VolumeAttributesType, 0)
+	for _, volAttrs := range response.Result.AttributesList() {
+		volQosPolicy := ""
+		if volAttrs.VolumeQosAttributesPtr != nil && volAttrs.VolumeQosAttributesPtr.PolicyGroupNamePtr != nil {
+			volQosPolicy = volAttrs.VolumeQosAttributesPtr.PolicyGroupName()
+		}
+		if volQosPolicy == wantedQosPolicy {
+			matching = append(matching, volAttrs)
+		}
+	}
+	response.Result.SetAttributesList(matching).SetNumRecords(len(matching))
+	return
+}
+
+// VolumeGetRootName gets the name of the root volume of a vserver
+func (d RestClient) VolumeGetRootName() (response azgo.VolumeGetRootNameResponse, err error) {
+	var volume restVolume
+	err = d.getUniqueRecord("/storage/volumes", d.svmQuery("is_svm_root", "true"), "uuid,name", &volume,
+		"SVM root volume")
+	if err == nil {
+		response.Result.SetVolume(volume.Name)
+	}
+	err = setRestResult(&response, err)
+	return
+}
+
+// VOLUME operations END
+/////////////////////////////////////////////////////////////////////////////
+
+/////////////////////////////////////////////////////////////////////////////
+// FLEXGROUP operations BEGIN
+
+// FlexGroupCreate creates a FlexGroup spanning the specified aggregates, mounted at a junction matching its name,
+// and waits for the creation job to finish
+// equivalent to POST /api/storage/volumes style=flexgroup
+func (d RestClient) FlexGroupCreate(name string, size int, aggregates []string, spaceReserve, snapshotPolicy,
+	unixPermissions, exportPolicy, securityStyle string, encrypt *bool, qosPolicy, adaptiveQosPolicy string,
+) (response azgo.VolumeCreateAsyncResponse, err error) {
+
+	volume, err := d.newVolume(name, aggregates, size, spaceReserve, snapshotPolicy, unixPermissions,
+		exportPolicy, securityStyle, encrypt, qosPolicy, adaptiveQosPolicy)
+	if err != nil {
+		return
+	}
+	volume.Style = "flexgroup"
+	volume.NAS.Path = newString("/" + name)
+
+	err = setRestResult(&response, d.invokeAndWait("POST", "/storage/volumes", nil, volume))
+	return
+}
+
+// FlexGroupDestroy destroys a FlexGroup and waits for the destruction job to finish
+func (d RestClient) FlexGroupDestroy(name string, force bool) (response azgo.VolumeDestroyAsyncResponse, err error) {
+	err = setRestResult(&response, d.destroyVolume(name, "flexgroup", force))
+	return
+}
+
+// FlexGroupSetSize sets the size of a FlexGroup and waits for the resize job to finish
+func (d RestClient) FlexGroupSetSize(name, newSize string) (response azgo.VolumeSizeAsyncResponse, err error) {
+	sizeBytes, err := d.setVolumeSize(name, newSize)
+	if err == nil {
+		response.Result.SetVolumeSize(strconv.Itoa(sizeBytes))
+	}
+	err = setRestResult(&response, err)
+	return
+}
+
+// FlexGroupDisableSnapshotDirectoryAccess disables access to the ".snapshot" directory of a FlexGroup
+// and waits for the modification job to finish
+func (d RestClient) FlexGroupDisableSnapshotDirectoryAccess(name string) (
+	response azgo.VolumeModifyIterAsyncResponse, err error,
+) {
+	err = setRestResult(&response, d.modifyVolume(name, restVolume{SnapshotDirectoryAccessEnabled: newBool(false)}))
+	return
+}
+
+// FlexGroupExists tests for the existence of a FlexGroup
+func (d RestClient) FlexGroupExists(name string) (bool, error) {
+	return d.volumeExists(name, "flexgroup")
+}
+
+// FlexGroupGet returns all relevant details for a single FlexGroup
+// equivalent to GET /api/storage/volumes style=flexgroup
+func (d RestClient) FlexGroupGet(name string) (azgo.VolumeAttributesType, error) {
+	volume, err := d.getVolume(name, "flexgroup", restVolumeFields)
+	if err != nil {
+		return azgo.VolumeAttributesType{}, err
+	}
+	return volume.volumeAttributes(), nil
+}
+
+// FlexGroupGetAll returns all relevant details for all FlexGroups whose names match the supplied prefix
+// equivalent to GET /api/storage/volumes style=flexgroup
+func (d RestClient) FlexGroupGetAll(prefix string) (response azgo.VolumeGetIterResponse, err error) {
+	return d.getVolumes(d.svmQuery("name", prefix+"*", "style", "flexgroup"), restVolumeFields)
+}
+
+// FLEXGROUP operations END
+/////////////////////////////////////////////////////////////////////////////
+
+/////////////////////////////////////////////////////////////////////////////
+// JOB operations BEGIN
+
+// JobGet isn't available with the REST API, which identifies jobs by UUID rather than by number.  The
+// RestClient waits for the jobs it starts itself.
+func (d RestClient) JobGet(id int) (azgo.JobInfoType, error) {
+	return azgo.JobInfoType{}, fmt.Errorf("job %d not found; the REST API identifies jobs by UUID", id)
+}
+
+// JOB operations END
+/////////////////////////////////////////////////////////////////////////////
+
+/////////////////////////////////////////////////////////////////////////////
+// QTREE operations BEGIN
+
+// parseQtreePath splits a qtree path of the form /vol/<flexvol>/<qtree> into its Flexvol and qtree names
+func parseQtreePath(path string) (volume, qtree string, err error) {
+	parts := strings.Split(strings.TrimPrefix(path, "/vol/"), "/")
+	if !strings.HasPrefix(path, "/vol/") || len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid qtree path %s", path)
+	}
+	return parts[0], parts[1], nil
+}
+
+// getQtree reads the qtree at the specified path
+func (d RestClient) getQtree(path string) (restQtree, error) {
+	volume, name, err := parseQtreePath(path)
+	if err != nil {
+		return restQtree{}, err
+	}
+	var qtree restQtree
+	err = d.getUniqueRecord("/storage/qtrees", d.svmQuery("volume.name", volume, "name", name),
+		"id,name,volume.uuid", &qtree, "qtree "+path)
+	if err == nil && (qtree.ID == nil || qtree.Volume == nil) {
+		err = fmt.Errorf("could not identify qtree %s", path)
+	}
+	return qtree, err
+}
+
+// qtreeInfo converts a qtree read with the REST API into the form returned by ZAPI
+func (q restQtree) qtreeInfo() azgo.QtreeInfoType {
+	volume, exportPolicy, mode := "", "", ""
+	if q.Volume != nil {
+		volume = q.Volume.Name
+	}
+	if q.ExportPolicy != nil {
+		exportPolicy = q.ExportPolicy.Name
+	}
+	if q.UnixPermissions != nil {
+		mode = fmt.Sprintf("%04d", *q.UnixPermissions)
+	}
+	qtreeInfo := azgo.NewQtreeInfoType().
+		SetQtree(q.Name).
+		SetVolume(volume).
+		SetSecurityStyle(q.SecurityStyle).
+		SetMode(mode).
+		SetExportPolicy(exportPolicy)
+	if q.ID != nil {
+		qtreeInfo.SetId(*q.ID)
+	}
+	return *qtreeInfo
+}
+
+// getQtrees reads all qtrees matching a query, in the form returned by ZAPI
+func (d RestClient) getQtrees(query url.Values, fields string) (response azgo.QtreeListIterResponse, err error) {
+	var qtrees []restQtree
+	_, err = d.getRecords("/storage/qtrees", query, fields, &qtrees)
+	if err == nil {
+		qtreeInfos := make([]azgo.QtreeInfoType, 0, len(qtrees))
+		for _, qtree := range qtrees {
+			qtreeInfos = append(qtreeInfos, qtree.qtreeInfo())
+		}
+		response.Result.SetAttributesList(qtreeInfos).SetNumRecords(len(qtreeInfos))
+	}
+	err = setRestResult(&response, err)
+	return
+}
+
+// QtreeCreate creates a qtree with the specified options
+// equivalent to POST /api/storage/qtrees
+func (d RestClient) QtreeCreate(name, volumeName, unixPermissions, exportPolicy,
+	securityStyle string) (response azgo.QtreeCreateResponse, err error) {
+
+	qtree := restQtree{
+		Name:          name,
+		SVM:           d.svm(),
+		Volume:        &restNamed{Name: volumeName},
+		SecurityStyle: securityStyle,
+		ExportPolicy:  &restNamed{Name: exportPolicy},
+	}
+	if unixPermissions != "" {
+		permissions, err := restUnixPermissions(unixPermissions)
+		if err != nil {
+			return response, err
+		}
+		qtree.UnixPermissions = &permissions
+	}
+
+	err = setRestResult(&response, d.invokeAndWait("POST", "/storage/qtrees", nil, qtree))
+	return
+}
+
+// QtreeRename renames a qtree
+// equivalent to PATCH /api/storage/qtrees/{volume.uuid}/{id} name=q2
+func (d RestClient) QtreeRename(path, newPath string) (response azgo.QtreeRenameResponse, err error) {
+	_, newName, err := parseQtreePath(newPath)
+	if err != nil {
+		return
+	}
+	qtree, err := d.getQtree(path)
+	if err == nil {
+		err = d.invokeAndWait("PATCH", fmt.Sprintf("/storage/qtrees/%s/%d", qtree.Volume.UUID, *qtree.ID), nil,
+			restQtree{Name: newName})
+	}
+	err = setRestResult(&response, err)
+	return
+}
+
+// QtreeDestroyAsync destroys a qtree in the background
+// equivalent to DELETE /api/storage/qtrees/{volume.uuid}/{id}
+func (d RestClient) QtreeDestroyAsync(path string, force bool) (response azgo.QtreeDeleteAsyncResponse, err error) {
+
+	// The deletion continues in the background, so don't wait for it
+	qtree, err := d.getQtree(path)
+	if err == nil {
+		err = d.invokeAPI("DELETE", fmt.Sprintf("/storage/qtrees/%s/%d", qtree.Volume.UUID, *qtree.ID), nil,
+			nil, nil)
+	}
+	err = setRestResult(&response, err)
+	return
+}
+
+// QtreeList returns the names of all Qtrees whose names match the supplied prefix
+// equivalent to GET /api/storage/qtrees
+func (d RestClient) QtreeList(prefix, volumePrefix string) (response azgo.QtreeListIterResponse, err error) {
+	return d.getQtrees(d.svmQuery("volume.name", volumePrefix+"*", "name", prefix+"*"), "id,name,volume.name")
+}
+
+// QtreeCount returns the number of Qtrees in the specified Flexvol, not including the Flexvol itself
+func (d RestClient) QtreeCount(volume string) (int, error) {
+
+	response, err := d.getQtrees(d.svmQuery("volume.name", volume), "id,name,volume.name")
+	if err = GetError(response, err); err != nil {
+		return 0, err
+	}
+
+	// The Flexvol itself is reported as a qtree without a name
+	count := 0
+	for _, qtree := range response.Result.AttributesList() {
+		if qtree.Qtree() != "" {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// QtreeExists returns true if the named Qtree exists (and is unique in the matching Flexvols)
+func (d RestClient) QtreeExists(name, volumePrefix string) (bool, string, error) {
+
+	response, err := d.getQtrees(d.svmQuery("volume.name", volumePrefix+"*", "name", name), "id,name,volume.name")
+	if err = GetError(response, err); err != nil {
+		return false, "", err
+	}
+
+	// Ensure qtree is unique
+	if response.Result.NumRecords() != 1 {
+		return false, "", nil
+	}
+
+	return true, response.Result.AttributesList()[0].Volume(), nil
+}
+
+// QtreeGet returns all relevant details for a single qtree
+// equivalent to GET /api/storage/qtrees
+func (d RestClient) QtreeGet(name, volumePrefix string) (azgo.QtreeInfoType, error) {
+	var qtree restQtree
+	if err := d.getUniqueRecord("/storage/qtrees", d.svmQuery("volume.name", volumePrefix+"*", "name", name),
+		restQtreeFields, &qtree, "qtree "+name); err != nil {
+		return azgo.QtreeInfoType{}, err
+	}
+	return qtree.qtreeInfo(), nil
+}
+
+// QtreeGetAll returns all relevant details for all qtrees whose Flexvol names match the supplied prefix
+// equivalent to GET /api/storage/qtrees
+func (d RestClient) QtreeGetAll(volumePrefix string) (response azgo.QtreeListIterResponse, err error) {
+	return d.getQtrees(d.svmQuery("volume.name", volumePrefix+"*"), restQtreeFields)
+}
+
+// setQuotaEnabled enables or disables quotas on a Flexvol
+func (d RestClient) setQuotaEnabled(volume string, enabled bool) error {
+	return d.modifyVolume(volume, restVolume{Quota: &restVolumeQuota{Enabled: newBool(enabled)}})
+}
+
+// QuotaOn enables quotas on a Flexvol
+// equivalent to PATCH /api/storage/volumes/{uuid} quota.enabled=true
+func (d RestClient) QuotaOn(volume string) (response azgo.QuotaOnResponse, err error) {
+	err = setRestResult(&response, d.setQuotaEnabled(volume, true))
+	return
+}
+
+// QuotaOff disables quotas on a Flexvol
+// equivalent to PATCH /api/storage/volumes/{uuid} quota.enabled=false
+func (d RestClient) QuotaOff(volume string) (response azgo.QuotaOffResponse, err error) {
+	err = setRestResult(&response, d.setQuotaEnabled(volume, false))
+	return
+}
+
+// QuotaResize resizes quotas on a Flexvol.  The REST API resizes quotas whenever their rules change, so
+// there is nothing to do.
+func (d RestClient) QuotaResize(volume string) (response azgo.QuotaResizeResponse, err error) {
+	err = setRestResult(&response, nil)
+	return
+}
+
+// QuotaStatus returns the quota status for a Flexvol
+// equivalent to GET /api/storage/volumes?fields=quota.state
+func (d RestClient) QuotaStatus(volume string) (response azgo.QuotaStatusResponse, err error) {
+	vol, err := d.getVolume(volume, "", "uuid,quota.state")
+	if err == nil {
+		state := ""
+		if vol.Quota != nil {
+			state = vol.Quota.State
+		}
+		response.Result.SetStatus(state)
+	}
+	err = setRestResult(&response, err)
+	return
+}
+
+// getQuotaRules reads the tree quota rules matching a query, in the form returned by ZAPI
+func (d RestClient) getQuotaRules(query url.Values) (response azgo.QuotaListEntriesIterResponse, err error) {
+
+	query.Set("type", "tree")
+
+	var rules []restQuotaRule
+	_, err = d.getRecords("/storage/quota/rules", query, "uuid,volume.name,qtree.name,space.hard_limit", &rules)
+	if err == nil {
+		quotaEntries := make([]azgo.QuotaEntryType, 0, len(rules))
+		for _, rule := range rules {
+			quotaEntries = append(quotaEntries, rule.quotaEntry())
+		}
+		response.Result.SetAttributesList(quotaEntries).SetNumRecords(len(quotaEntries))
+	}
+	err = setRestResult(&response, err)
+	return
+}
+
+// quotaEntry converts a tree quota rule read with the REST API into the form returned by ZAPI, which
+// identifies the qtree by its path and reports the disk limit in KB
+func (r restQuotaRule) quotaEntry() azgo.QuotaEntryType {
+	volume, qtree, target, diskLimit := "", "", "", "-"
+	if r.Volume != nil {
+		volume = r.Volume.Name
+	}
+	if r.Qtree != nil && r.Qtree.Name != "" {
+		qtree = r.Qtree.Name
+		target = fmt.Sprintf("/vol/%s/%s", volume, qtree)
+	}
+	if r.Space != nil && r.Space.HardLimit != nil {
+		diskLimit = strconv.Itoa(*r.Space.HardLimit / 1024)
+	}
+	return *azgo.NewQuotaEntryType().
+		SetQuotaType("tree").
+		SetQuotaTarget(target).
+		SetVolume(volume).
+		SetQtree(qtree).
+		SetDiskLimit(diskLimit)
+}
+
+// QuotaSetEntry creates a new quota rule with an optional hard disk limit, or sets the limit of an existing one
+// equivalent to POST /api/storage/quota/rules
+func (d RestClient) QuotaSetEntry(qtreeName, volumeName, quotaTarget, quotaType, diskLimit string) (response azgo.QuotaSetEntryResponse, err error) {
+
+	// A rule for a qtree targets its path, and the default rule for a Flexvol targets nothing
+	qtree := ""
+	if quotaTarget != "" {
+		if volumeName, qtree, err = parseQtreePath(quotaTarget); err != nil {
+			return
+		}
+	}
+
+	rule := restQuotaRule{
+		SVM:    d.svm(),
+		Volume: &restNamed{Name: volumeName},
+		Type:   quotaType,
+		Qtree:  &restNamed{Name: qtree},
+	}
+
+	// A limit of "-" or an empty limit means there is none
+	if diskLimit != "" && diskLimit != "-" {
+		diskLimitKB, err := strconv.Atoi(diskLimit)
+		if err != nil {
+			return response, fmt.Errorf("invalid disk limit %s: %v", diskLimit, err)
+		}
+		rule.Space = &restQuotaSpace{HardLimit: newInt(diskLimitKB * 1024)}
+	}
+
+	var existingRule restQuotaRule
+	query := d.svmQuery("volume.name", volumeName, "type", quotaType, "qtree.name", restQueryValue(qtree))
+	err = d.getUniqueRecord("/storage/quota/rules", query, "uuid", &existingRule, "quota rule")
+	if rerr, ok := err.(restError); ok && rerr.statusCode == http.StatusNotFound {
+		err = d.invokeAndWait("POST", "/storage/quota/rules", nil, rule)
+	} else if err == nil && rule.Space != nil {
+		err = d.invokeAndWait("PATCH", "/storage/quota/rules/"+existingRule.UUID, nil,
+			restQuotaRule{Space: rule.Space})
+	}
+
+	err = setRestResult(&response, err)
+	return
+}
+
+// QuotaEntryGet returns the disk limit for a single qtree
+// equivalent to GET /api/storage/quota/rules
+func (d RestClient) QuotaEntryGet(target string) (azgo.QuotaEntryType, error) {
+
+	volume, qtree, err := parseQtreePath(target)
+	if err != nil {
+		return azgo.QuotaEntryType{}, err
+	}
+
+	response, err := d.getQuotaRules(d.svmQuery("volume.name", volume, "qtree.name", qtree))
+	if err = GetError(response, err); err != nil {
+		return azgo.QuotaEntryType{}, err
+	} else if response.Result.NumRecords() == 0 {
+		return azgo.QuotaEntryType{}, fmt.Errorf("tree quota for %s not found", target)
+	} else if response.Result.NumRecords() > 1 {
+		return azgo.QuotaEntryType{}, fmt.Errorf("more than one tree quota for %s found", target)
+	}
+
+	return response.Result.AttributesList()[0], nil
+}
+
+// QuotaEntryList returns the disk limit quotas for a Flexvol
+// equivalent to GET /api/storage/quota/rules
+func (d RestClient) QuotaEntryList(volume string) (response azgo.QuotaListEntriesIterResponse, err error) {
+	return d.getQuotaRules(d.svmQuery("volume.name", volume))
+}
+
+// QTREE operations END
+/////////////////////////////////////////////////////////////////////////////
+
+/////////////////////////////////////////////////////////////////////////////
+// EXPORT POLICY operations BEGIN
+
+// getExportPolicy reads the export policy with the specified name
+func (d RestClient) getExportPolicy(policy string) (restExportPolicy, error) {
+	var exportPolicy restExportPolicy
+	err := d.getUniqueRecord("/protocols/nfs/export-policies", d.svmQuery("name", policy), "id,name",
+		&exportPolicy, "export policy "+policy)
+	if err == nil && exportPolicy.ID == nil {
+		err = fmt.Errorf("could not identify export policy %s", policy)
+	}
+	return exportPolicy, err
+}
+
+// ExportPolicyCreate creates an export policy
+// equivalent to POST /api/protocols/nfs/export-policies
+func (d RestClient) ExportPolicyCreate(policy string) (response azgo.ExportPolicyCreateResponse, err error) {
+	exportPolicy := restExportPolicy{Name: policy, SVM: d.svm()}
+	err = setRestResult(&response, d.invokeAPI("POST", "/protocols/nfs/export-policies", nil, exportPolicy, nil))
+	return
+}
+
+// ExportRuleCreate creates a rule in an export policy
+// equivalent to POST /api/protocols/nfs/export-policies/{id}/rules
+func (d RestClient) ExportRuleCreate(
+	policy, clientMatch string,
+	protocols, roSecFlavors, rwSecFlavors, suSecFlavors []string,
+) (response azgo.ExportRuleCreateResponse, err error) {
+
+	exportPolicy, err := d.getExportPolicy(policy)
+	if err == nil {
+		rule := restExportRule{
+			Clients:   []restExportClient{{Match: clientMatch}},
+			Protocols: protocols,
+			RoRule:    roSecFlavors,
+			RwRule:    rwSecFlavors,
+			Superuser: suSecFlavors,
+		}
+		err = d.invokeAPI("POST", fmt.Sprintf("/protocols/nfs/export-policies/%d/rules", *exportPolicy.ID), nil,
+			rule, nil)
+	}
+	err = setRestResult(&response, err)
+	return
+}
+
+// ExportRuleGetIterRequest returns the export rules in an export policy
+// equivalent to GET /api/protocols/nfs/export-policies/{id}/rules
+func (d RestClient) ExportRuleGetIterRequest(policy string) (response azgo.ExportRuleGetIterResponse, err error) {
+
+	exportPolicy, err := d.getExportPolicy(policy)
+	if err == nil {
+		var rules []restExportRule
+		_, err = d.getRecords(fmt.Sprintf("/protocols/nfs/export-policies/%d/rules", *exportPolicy.ID), nil,
+			"index,clients,protocols,ro_rule,rw_rule,superuser", &rules)
+		if err == nil {
+			ruleInfos := make([]azgo.ExportRuleInfoType, 0, len(rules))
+			for _, rule := range rules {
+				ruleInfos = append(ruleInfos, rule.exportRuleInfo(policy))
+			}
+			response.Result.SetAttributesList(ruleInfos).SetNumRecords(len(ruleInfos))
+		}
+	}
+	err = setRestResult(&response, err)
+	return
+}
+
+// exportRuleInfo converts an export rule read with the REST API into the form returned by ZAPI
+func (r restExportRule) exportRuleInfo(policy string) azgo.ExportRuleInfoType {
+
+	clientMatches := make([]string, 0, len(r.Clients))
+	for _, client := range r.Clients {
+		clientMatches = append(clientMatches, client.Match)
+	}
+
+	var protocols []azgo.AccessProtocolType
+	for _, p := range r.Protocols {
+		protocols = append(protocols, azgo.AccessProtocolType(p))
+	}
+
+	ruleInfo := azgo.NewExportRuleInfoType().
+		SetPolicyName(azgo.ExportPolicyNameType(policy)).
+		SetClientMatch(strings.Join(clientMatches, ",")).
+		SetProtocol(protocols).
+		SetRoRule(securityFlavors(r.RoRule)).
+		SetRwRule(securityFlavors(r.RwRule)).
+		SetSuperUserSecurity(securityFlavors(r.Superuser))
+	if r.Index != nil {
+		ruleInfo.SetRuleIndex(*r.Index)
+	}
+	return *ruleInfo
+}
+
+func securityFlavors(flavors []string) []azgo.SecurityFlavorType {
+	var flavorTypes []azgo.SecurityFlavorType
+	for _, f := range flavors {
+		flavorTypes = append(flavorTypes, azgo.SecurityFlavorType(f))
+	}
+	return flavorTypes
+}
+
+// EXPORT POLICY operations END
+/////////////////////////////////////////////////////////////////////////////
+
+/////////////////////////////////////////////////////////////////////////////
+// SNAPSHOT operations BEGIN
+
+// getSnapshot reads the named snapshot of a volume, and returns it along with the volume's UUID
+func (d RestClient) getSnapshot(name, volumeName string) (restSnapshot, string, error) {
+	volume, err := d.getVolume(volumeName, "", "uuid")
+	if err != nil {
+		return restSnapshot{}, "", err
+	}
+	var snapshot restSnapshot
+	err = d.getUniqueRecord("/storage/volumes/"+volume.UUID+"/snapshots", url.Values{"name": {name}}, "uuid,name",
+		&snapshot, fmt.Sprintf("snapshot %s of volume %s", name, volumeName))
+	return snapshot, volume.UUID, err
+}
+
+// SnapshotCreate creates a snapshot of a volume
+// equivalent to POST /api/storage/volumes/{uuid}/snapshots
+func (d RestClient) SnapshotCreate(name, volumeName string) (response azgo.SnapshotCreateResponse, err error) {
+	volume, err := d.getVolume(volumeName, "", "uuid")
+	if err == nil {
+		err = d.invokeAndWait("POST", "/storage/volumes/"+volume.UUID+"/snapshots", nil, restSnapshot{Name: name})
+	}
+	err = setRestResult(&response, err)
+	return
+}
+
+// SnapshotGetByVolume returns the list of snapshots associated with a volume
+// equivalent to GET /api/storage/volumes/{uuid}/snapshots
+func (d RestClient) SnapshotGetByVolume(volumeName string) (response azgo.SnapshotGetIterResponse, err error) {
+	volume, err := d.getVolume(volumeName, "", "uuid")
+	if err == nil {
+		var snapshots []restSnapshot
+		_, err = d.getRecords("/storage/volumes/"+volume.UUID+"/snapshots", nil, "uuid,name,create_time",
+			&snapshots)
+		if err == nil {
+			snapshotInfos := make([]azgo.SnapshotInfoType, 0, len(snapshots))
+			for _, snapshot := range snapshots {
+				accessTime := 0
+				if createTime, err := time.Parse(time.RFC3339, snapshot.CreateTime); err == nil {
+					accessTime = int(createTime.Unix())
+				}
+				snapshotInfo := azgo.NewSnapshotInfoType().
+					SetName(snapshot.Name).
+					SetVolume(volumeName).
+					SetAccessTime(accessTime)
+				snapshotInfos = append(snapshotInfos, *snapshotInfo)
+			}
+			response.Result.SetAttributesList(snapshotInfos).SetNumRecords(len(snapshotInfos))
+		}
+	}
+	err = setRestResult(&response, err)
+	return
+}
+
+// SnapshotDelete deletes a snapshot of a volume
+// equivalent to DELETE /api/storage/volumes/{uuid}/snapshots/{uuid}
+func (d RestClient) SnapshotDelete(name, volumeName string) (response azgo.SnapshotDeleteResponse, err error) {
+	snapshot, volumeUUID, err := d.getSnapshot(name, volumeName)
+	if err == nil {
+		err = d.invokeAndWait("DELETE", "/storage/volumes/"+volumeUUID+"/snapshots/"+snapshot.UUID, nil, nil)
+	}
+	err = setRestResult(&response, err)
+	return
+}
+
+// SnapshotRestoreVolume restores a volume to the state captured by a snapshot
+// equivalent to PATCH /api/storage/volumes/{uuid} restore_to.snapshot.name=snap1
+func (d RestClient) SnapshotRestoreVolume(name, volumeName string) (response azgo.SnapshotRestoreVolumeResponse, err error) {
+	_, volumeUUID, err := d.getSnapshot(name, volumeName)
+	if err == nil {
+		err = d.invokeAndWait("PATCH", "/storage/volumes/"+volumeUUID, nil,
+			restVolume{RestoreTo: &restVolumeRestoreTo{Snapshot: &restNamed{Name: name}}})
+	}
+	err = setRestResult(&response, err)
+	return
+}
+
+// SNAPSHOT operations END
+/////////////////////////////////////////////////////////////////////////////
+
+/////////////////////////////////////////////////////////////////////////////
+// QOS operations BEGIN
+
+// parseRestThroughput parses a throughput limit given in IOPS or in MB/s, e.g. "5000iops" or "100MB/s",
+// and returns it in the form the REST API expects
+func parseRestThroughput(throughput string) (iops, mbps *int, err error) {
+	limit := strings.ToLower(strings.TrimSpace(throughput))
+	switch {
+	case strings.HasSuffix(limit, "iops"):
+		value, err := strconv.Atoi(strings.TrimSuffix(limit, "iops"))
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid throughput limit %s", throughput)
+		}
+		return &value, nil, nil
+	case strings.HasSuffix(limit, "mb/s"):
+		value, err := strconv.Atoi(strings.TrimSuffix(limit, "mb/s"))
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid throughput limit %s", throughput)
+		}
+		return nil, &value, nil
+	default:
+		return nil, nil, fmt.Errorf("throughput limit %s is not in IOPS or MB/s", throughput)
+	}
+}
+
+// formatRestThroughput returns a throughput limit read with the REST API in the form ZAPI reports it,
+// or the specified value if there is no limit
+func formatRestThroughput(iops, mbps *int, unset string) string {
+	switch {
+	case iops != nil && *iops > 0:
+		return fmt.Sprintf("%dIOPS", *iops)
+	case mbps != nil && *mbps > 0:
+		return fmt.Sprintf("%dMB/S", *mbps)
+	default:
+		return unset
+	}
+}
+
+// QosPolicyGroupCreate creates a QoS policy group with the specified throughput limits.  A limit
+// is given in IOPS or in bytes per second, e.g. "5000iops" or "100MB/s", and is left unset if empty.
+// equivalent to POST /api/storage/qos/policies
+func (d RestClient) QosPolicyGroupCreate(name, maxThroughput, minThroughput string) (response azgo.QosPolicyGroupCreateResponse, err error) {
+
+	fixed := &restQosFixed{}
+	if maxThroughput != "" {
+		if fixed.MaxThroughputIOPS, fixed.MaxThroughputMBPS, err = parseRestThroughput(maxThroughput); err != nil {
+			return
+		}
+	}
+	if minThroughput != "" {
+		if fixed.MinThroughputIOPS, fixed.MinThroughputMBPS, err = parseRestThroughput(minThroughput); err != nil {
+			return
+		}
+	}
+
+	policy := restQosPolicy{Name: name, SVM: d.svm(), Fixed: fixed}
+	err = setRestResult(&response, d.invokeAndWait("POST", "/storage/qos/policies", nil, policy))
+	return
+}
+
+// QosPolicyGroupDelete deletes a QoS policy group, which must not be in use
+// equivalent to DELETE /api/storage/qos/policies/{uuid}
+func (d RestClient) QosPolicyGroupDelete(name string) (response azgo.QosPolicyGroupDeleteResponse, err error) {
+	var policy restQosPolicy
+	err = d.getUniqueRecord("/storage/qos/policies", d.svmQuery("name", name), "uuid,name", &policy,
+		"QoS policy group "+name)
+	if err == nil {
+		err = d.invokeAndWait("DELETE", "/storage/qos/policies/"+policy.UUID, nil, nil)
+	}
+	err = setRestResult(&response, err)
+	return
+}
+
+// QosPolicyGroupGet returns the details of a single QoS policy group
+// equivalent to GET /api/storage/qos/policies
+func (d RestClient) QosPolicyGroupGet(name string) (azgo.QosPolicyGroupInfoType, error) {
+
+	var policy restQosPolicy
+	if err := d.getUniqueRecord("/storage/qos/policies", d.svmQuery("name", name), "uuid,name,fixed,object_count",
+		&policy, "QoS policy group "+name); err != nil {
+		return azgo.QosPolicyGroupInfoType{}, err
+	}
+
+	policyGroup := azgo.NewQosPolicyGroupInfoType().
+		SetPolicyGroup(policy.Name).
+		SetUuid(policy.UUID).
+		SetVserver(d.config.SVM)
+	if policy.Fixed != nil {
+		policyGroup.SetMaxThroughput(
+			formatRestThroughput(policy.Fixed.MaxThroughputIOPS, policy.Fixed.MaxThroughputMBPS, "INF"))
+		policyGroup.SetMinThroughput(
+			formatRestThroughput(policy.Fixed.MinThroughputIOPS, policy.Fixed.MinThroughputMBPS, "0"))
+	}
+	if policy.ObjectCount != nil {
+		policyGroup.SetNumWorkloads(*policy.ObjectCount)
+	}
+
+	return *policyGroup, nil
+}
+
+// QOS operations END
+/////////////////////////////////////////////////////////////////////////////
+
+/////////////////////////////////////////////////////////////////////////////
+// ISCSI operations BEGIN
+
+// IscsiServiceGetIterRequest returns information about an iSCSI target
+// equivalent to GET /api/protocols/san/iscsi/services
+func (d RestClient) IscsiServiceGetIterRequest() (response azgo.IscsiServiceGetIterResponse, err error) {
+	var services []restIscsiService
+	_, err = d.getRecords("/protocols/san/iscsi/services", d.svmQuery(), "svm.name,enabled,target", &services)
+	if err == nil {
+		serviceInfos := make([]azgo.IscsiServiceInfoType, 0, len(services))
+		for _, service := range services {
+			serviceInfo := &azgo.IscsiServiceInfoType{IsAvailablePtr: service.Enabled}
+			if service.SVM != nil {
+				serviceInfo.VserverPtr = newString(service.SVM.Name)
+			}
+			if service.Target != nil {
+				serviceInfo.NodeNamePtr = newString(service.Target.Name)
+				serviceInfo.AliasNamePtr = newString(service.Target.Alias)
+			}
+			serviceInfos = append(serviceInfos, *serviceInfo)
+		}
+		response.Result.SetAttributesList(serviceInfos).SetNumRecords(len(serviceInfos))
+	}
+	err = setRestResult(&response, err)
+	return
+}
+
+// ISCSI operations END
+/////////////////////////////////////////////////////////////////////////////
+
+/////////////////////////////////////////////////////////////////////////////
+// VSERVER operations BEGIN
+
+// getSVMs reads the SVMs matching a query
+func (d RestClient) getSVMs(query url.Values, fields string) ([]restSVM, error) {
+	var svms []restSVM
+	_, err := d.getRecords("/svm/svms", query, fields, &svms)
+	return svms, err
+}
+
+// VserverGetIterRequest returns the vservers on the system
+// equivalent to GET /api/svm/svms
+func (d RestClient) VserverGetIterRequest() (response azgo.VserverGetIterResponse, err error) {
+	svms, err := d.getSVMs(nil, "uuid,name,aggregates.name")
+	if err == nil {
+		vserverInfos := make([]azgo.VserverInfoType, 0, len(svms))
+		for _, svm := range svms {
+			aggrNames := make([]azgo.AggrNameType, 0, len(svm.Aggregates))
+			aggrInfos := make([]azgo.VserverAggrInfoType, 0, len(svm.Aggregates))
+			for _, aggr := range svm.Aggregates {
+				aggrNames = append(aggrNames, azgo.AggrNameType(aggr.Name))
+				aggrInfos = append(aggrInfos, *azgo.NewVserverAggrInfoType().SetAggrName(azgo.AggrNameType(aggr.Name)))
+			}
+			vserverInfo := azgo.NewVserverInfoType().
+				SetVserverName(svm.Name).
+				SetUuid(azgo.UuidType(svm.UUID)).
+				SetAggrList(aggrNames).
+				SetVserverAggrInfoList(aggrInfos)
+			vserverInfos = append(vserverInfos, *vserverInfo)
+		}
+		response.Result.SetAttributesList(vserverInfos).SetNumRecords(len(vserverInfos))
+	}
+	err = setRestResult(&response, err)
+	return
+}
+
+// GetVserverAggregateNames returns an array of names of the aggregates assigned to the configured vserver.
+func (d RestClient) GetVserverAggregateNames() ([]string, error) {
+
+	svms, err := d.getSVMs(url.Values{"name": {d.config.SVM}}, "uuid,name,aggregates.name")
+	if err != nil {
+		return nil, err
+	}
+	if len(svms) != 1 {
+		return nil, fmt.Errorf("could not find SVM %s", d.config.SVM)
+	}
+
+	aggrNames := make([]string, 0, len(svms[0].Aggregates))
+	for _, aggr := range svms[0].Aggregates {
+		aggrNames = append(aggrNames, aggr.Name)
+	}
+
+	return aggrNames, nil
+}
+
+// VserverShowAggrGetIterRequest returns the aggregates on the vserver.
+// equivalent to GET /api/svm/svms?fields=aggregates
+func (d RestClient) VserverShowAggrGetIterRequest() (response azgo.VserverShowAggrGetIterResponse, err error) {
+	svms, err := d.getSVMs(url.Values{"name": {d.config.SVM}}, "uuid,name,aggregates")
+	if err == nil {
+		aggrInfos := make([]azgo.ShowAggregatesType, 0)
+		for _, svm := range svms {
+			for _, aggr := range svm.Aggregates {
+				aggrInfo := azgo.NewShowAggregatesType().
+					SetAggregateName(azgo.AggrNameType(aggr.Name)).
+					SetAggregateType(azgo.AggregatetypeType(aggr.Type)).
+					SetVserverName(svm.Name)
+				if aggr.AvailableSize != nil {
+					aggrInfo.SetAvailableSize(azgo.SizeType(*aggr.AvailableSize))
+				}
+				aggrInfos = append(aggrInfos, *aggrInfo)
+			}
+		}
+		response.Result.SetAttributesList(aggrInfos).SetNumRecords(len(aggrInfos))
+	}
+	err = setRestResult(&response, err)
+	return
+}
+
+// VSERVER operations END
+/////////////////////////////////////////////////////////////////////////////
+
+/////////////////////////////////////////////////////////////////////////////
+// AGGREGATE operations BEGIN
+
+// aggregateType returns the type ZAPI reports for an aggregate read with the REST API
+func (a restAggregate) aggregateType() string {
+	if a.BlockStorage == nil {
+		return ""
+	}
+	if a.BlockStorage.HybridCache != nil && a.BlockStorage.HybridCache.Enabled {
+		return "hybrid"
+	}
+	if a.BlockStorage.Primary != nil {
+		switch a.BlockStorage.Primary.DiskClass {
+		case "solid_state", "ssd":
+			return "ssd"
+		case "":
+			return ""
+		default:
+			return "hdd"
+		}
+	}
+	return ""
+}
+
+// AggrGetIterRequest returns the aggregates on the system
+// equivalent to GET /api/storage/aggregates
+func (d RestClient) AggrGetIterRequest() (response azgo.AggrGetIterResponse, err error) {
+	var aggrs []restAggregate
+	_, err = d.getRecords("/storage/aggregates", nil,
+		"name,space.block_storage.size,space.block_storage.available,block_storage.primary.disk_class,"+
+			"block_storage.hybrid_cache.enabled", &aggrs)
+	if err == nil {
+		aggrInfos := make([]azgo.AggrAttributesType, 0, len(aggrs))
+		for _, aggr := range aggrs {
+			aggrInfo := &azgo.AggrAttributesType{
+				AggregateNamePtr:      newString(aggr.Name),
+				AggrRaidAttributesPtr: &azgo.AggrRaidAttributesType{AggregateTypePtr: newString(aggr.aggregateType())},
+			}
+			if aggr.Space != nil && aggr.Space.BlockStorage != nil {
+				aggrInfo.AggrSpaceAttributesPtr = &azgo.AggrSpaceAttributesType{
+					SizeAvailablePtr: aggr.Space.BlockStorage.Available,
+					SizeTotalPtr:     aggr.Space.BlockStorage.Size,
+				}
+			}
+			aggrInfos = append(aggrInfos, *aggrInfo)
+		}
+		response.Result.SetAttributesList(aggrInfos).SetNumRecords(len(aggrInfos))
+	}
+	err = setRestResult(&response, err)
+	return
+}
+
+// AGGREGATE operations END
+/////////////////////////////////////////////////////////////////////////////
+
+/////////////////////////////////////////////////////////////////////////////
+// SNAPMIRROR operations BEGIN
+
+// getSnapmirror reads the SnapMirror relationship whose destination is at the specified location
+func (d RestClient) getSnapmirror(destinationLocation string, query url.Values) (restSnapmirror, error) {
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("destination.path", destinationLocation)
+	var relationship restSnapmirror
+	err := d.getUniqueRecord("/snapmirror/relationships", query, restSnapmirrorFields, &relationship,
+		"SnapMirror relationship for "+destinationLocation)
+	return relationship, err
+}
+
+// setSnapmirrorState moves the SnapMirror relationship whose destination is at the specified location
+// to a new state
+func (d RestClient) setSnapmirrorState(destinationLocation, state string) error {
+	relationship, err := d.getSnapmirror(destinationLocation, nil)
+	if err != nil {
+		return err
+	}
+	return d.invokeAndWait("PATCH", "/snapmirror/relationships/"+relationship.UUID, nil,
+		restSnapmirror{State: state})
+}
+
+// snapmirrorInfo converts a SnapMirror relationship read with the REST API into the form returned by ZAPI
+func (r restSnapmirror) snapmirrorInfo() azgo.SnapmirrorInfoType {
+
+	info := azgo.NewSnapmirrorInfoType()
+	if r.Source != nil {
+		info.SetSourceLocation(r.Source.Path)
+	}
+	if r.Destination != nil {
+		info.SetDestinationLocation(r.Destination.Path)
+	}
+	if r.Policy != nil {
+		info.SetPolicy(r.Policy.Name)
+	}
+	if r.TransferSchedule != nil {
+		info.SetSchedule(r.TransferSchedule.Name)
+	}
+
+	// ZAPI reports a paused relationship as quiesced, and one that is transferring data as such
+	switch r.State {
+	case "":
+	case "broken_off":
+		info.SetMirrorState("broken-off")
+	case "paused":
+		info.SetMirrorState("snapmirrored").SetRelationshipStatus("quiesced")
+	default:
+		info.SetMirrorState(r.State)
+	}
+	if info.RelationshipStatusPtr == nil {
+		if r.Transfer != nil && r.Transfer.State == "transferring" {
+			info.SetRelationshipStatus("transferring")
+		} else {
+			info.SetRelationshipStatus("idle")
+		}
+	}
+
+	if r.Healthy != nil {
+		info.SetIsHealthy(*r.Healthy)
+	}
+	if len(r.UnhealthyReason) > 0 {
+		reasons := make([]string, 0, len(r.UnhealthyReason))
+		for _, reason := range r.UnhealthyReason {
+			reasons = append(reasons, reason.Message)
+		}
+		info.SetUnhealthyReason(strings.Join(reasons, "; "))
+	}
+	if lagTime, err := parseRestDuration(r.LagTime); err == nil {
+		info.SetLagTime(lagTime)
+	}
+
+	return *info
+}
+
+// SnapmirrorGetLoadSharingMirrors gets load-sharing SnapMirror relationships for a volume.  The REST API
+// only manages load-sharing mirrors through its CLI passthrough.
+// equivalent to GET /api/private/cli/snapmirror?type=LS
+func (d RestClient) SnapmirrorGetLoadSharingMirrors(volume string) (response azgo.SnapmirrorGetIterResponse, err error) {
+	query := url.Values{"type": {"LS"}, "source_volume": {volume}}
+	if d.config.SVM != "" {
+		query.Set("source_vserver", d.config.SVM)
+	}
+
+	var mirrors []restCLISnapmirror
+	_, err = d.getRecords("/private/cli/snapmirror", query, "source_path,status", &mirrors)
+	if err == nil {
+		mirrorInfos := make([]azgo.SnapmirrorInfoType, 0, len(mirrors))
+		for _, mirror := range mirrors {
+			mirrorInfo := azgo.NewSnapmirrorInfoType().
+				SetSourceLocation(mirror.SourcePath).
+				SetRelationshipStatus(strings.ToLower(mirror.Status))
+			mirrorInfos = append(mirrorInfos, *mirrorInfo)
+		}
+		response.Result.SetAttributesList(mirrorInfos).SetNumRecords(len(mirrorInfos))
+	}
+	err = setRestResult(&response, err)
+	return
+}
+
+// SnapmirrorUpdateLoadSharingMirrors updates the destination volumes of a set of load-sharing mirrors
+// equivalent to POST /api/private/cli/snapmirror/update-ls-set
+func (d RestClient) SnapmirrorUpdateLoadSharingMirrors(
+	sourceLocation string,
+) (response azgo.SnapmirrorUpdateLsSetResponse, err error) {
+
+	request := map[string]string{"source_path": sourceLocation}
+	err = setRestResult(&response, d.invokeAPI("POST", "/private/cli/snapmirror/update-ls-set", nil, request, nil))
+	return
+}
+
+// SnapmirrorCreate creates a data protection SnapMirror relationship; it must be sent to the destination SVM
+// equivalent to POST /api/snapmirror/relationships
+func (d RestClient) SnapmirrorCreate(
+	sourceLocation, destinationLocation, schedule, policy string,
+) (response azgo.SnapmirrorCreateResponse, err error) {
+
+	relationship := restSnapmirror{
+		Source:      &restSnapmirrorEndpoint{Path: sourceLocation},
+		Destination: &restSnapmirrorEndpoint{Path: destinationLocation},
+	}
+
+	// Let ONTAP choose its defaults for whatever isn't specified
+	if schedule != "" {
+		relationship.TransferSchedule = &restNamed{Name: schedule}
+	}
+	if policy != "" {
+		relationship.Policy = &restNamed{Name: policy}
+	}
+
+	err = setRestResult(&response, d.invokeAndWait("POST", "/snapmirror/relationships", nil, relationship))
+	return
+}
+
+// SnapmirrorInitialize starts the baseline transfer of a SnapMirror relationship
+// equivalent to PATCH /api/snapmirror/relationships/{uuid} state=snapmirrored
+func (d RestClient) SnapmirrorInitialize(
+	sourceLocation, destinationLocation string,
+) (response azgo.SnapmirrorInitializeResponse, err error) {
+	err = setRestResult(&response, d.setSnapmirrorState(destinationLocation, "snapmirrored"))
+	return
+}
+
+// SnapmirrorGet returns the SnapMirror relationship whose destination is at the specified location, or nil
+// if there is none
+// equivalent to GET /api/snapmirror/relationships
+func (d RestClient) SnapmirrorGet(destinationLocation string) (*azgo.SnapmirrorInfoType, error) {
+
+	relationship, err := d.getSnapmirror(destinationLocation, nil)
+	if rerr, ok := err.(restError); ok && rerr.statusCode == http.StatusNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	info := relationship.snapmirrorInfo()
+	return &info, nil
+}
+
+// SnapmirrorQuiesce stops further transfers to the destination of a SnapMirror relationship, letting any
+// transfer in progress finish
+// equivalent to PATCH /api/snapmirror/relationships/{uuid} state=paused
+func (d RestClient) SnapmirrorQuiesce(destinationLocation string) (response azgo.SnapmirrorQuiesceResponse, err error) {
+	err = setRestResult(&response, d.setSnapmirrorState(destinationLocation, "paused"))
+	return
+}
+
+// SnapmirrorBreak makes the destination of a SnapMirror relationship writable
+// equivalent to PATCH /api/snapmirror/relationships/{uuid} state=broken_off
+func (d RestClient) SnapmirrorBreak(destinationLocation string) (response azgo.SnapmirrorBreakResponse, err error) {
+	err = setRestResult(&response, d.setSnapmirrorState(destinationLocation, "broken_off"))
+	return
+}
+
+// SnapmirrorDelete removes a SnapMirror relationship; it must be sent to the destination SVM
+// equivalent to DELETE /api/snapmirror/relationships/{uuid}?destination_only=true
+func (d RestClient) SnapmirrorDelete(
+	sourceLocation, destinationLocation string,
+) (response azgo.SnapmirrorDeleteResponse, err error) {
+
+	relationship, err := d.getSnapmirror(destinationLocation, nil)
+	if err == nil {
+		err = d.invokeAndWait("DELETE", "/snapmirror/relationships/"+relationship.UUID,
+			url.Values{"destination_only": {"true"}}, nil)
+	}
+	err = setRestResult(&response, err)
+	return
+}
+
+// SnapmirrorRelease removes the source's record of a deleted SnapMirror relationship; it must be sent to the
+// source SVM
+// equivalent to DELETE /api/snapmirror/relationships/{uuid}?source_only=true
+func (d RestClient) SnapmirrorRelease(
+	sourceLocation, destinationLocation string,
+) (response azgo.SnapmirrorReleaseResponse, err error) {
+
+	relationship, err := d.getSnapmirror(destinationLocation, url.Values{"list_destinations_only": {"true"}})
+	if err == nil {
+		err = d.invokeAndWait("DELETE", "/snapmirror/relationships/"+relationship.UUID,
+			url.Values{"source_only": {"true"}}, nil)
+	}
+	err = setRestResult(&response, err)
+	return
+}
+
+// SNAPMIRROR operations END
+/////////////////////////////////////////////////////////////////////////////
+
+/////////////////////////////////////////////////////////////////////////////
+// MISC operations BEGIN
+
+// NetInterfaceGet returns the list of network interfaces with associated metadata
+// equivalent to GET /api/network/ip/interfaces
+func (d RestClient) NetInterfaceGet() (response azgo.NetInterfaceGetIterResponse, err error) {
+	var interfaces []restInterface
+	_, err = d.getRecords("/network/ip/interfaces", d.svmQuery(), "name,ip.address,services", &interfaces)
+	if err == nil {
+		interfaceInfos := make([]azgo.NetInterfaceInfoType, 0, len(interfaces))
+		for _, lif := range interfaces {
+			address := ""
+			if lif.IP != nil {
+				address = lif.IP.Address
+			}
+
+			// The REST API reports the data protocols a LIF serves among its services, e.g. "data_nfs"
+			dataProtocols := make([]azgo.DataProtocolType, 0)
+			for _, service := range lif.Services {
+				switch service {
+				case "data_nfs", "data_cifs", "data_iscsi", "data_fcp":
+					dataProtocols = append(dataProtocols, azgo.DataProtocolType(strings.TrimPrefix(service, "data_")))
+				}
+			}
+
+			interfaceInfo := azgo.NewNetInterfaceInfoType().
+				SetInterfaceName(lif.Name).
+				SetAddress(azgo.IpAddressType(address)).
+				SetDataProtocols(dataProtocols)
+			interfaceInfos = append(interfaceInfos, *interfaceInfo)
+		}
+		response.Result.SetAttributesList(interfaceInfos).SetNumRecords(len(interfaceInfos))
+	}
+	err = setRestResult(&response, err)
+	return
+}
+
+// NetInterfaceGetDataLIFs returns the addresses of the data LIFs serving the specified protocol
+func (d RestClient) NetInterfaceGetDataLIFs(protocol string) ([]string, error) {
+	return netInterfaceGetDataLIFs(d, protocol)
+}
+
+// getCluster reads the version of the cluster
+func (d RestClient) getCluster() (restCluster, error) {
+	var cluster restCluster
+	err := d.invokeAPI("GET", "/cluster", url.Values{"fields": {"version"}}, nil, &cluster)
+	return cluster, err
+}
+
+// SystemGetVersion returns the system version
+// equivalent to GET /api/cluster?fields=version
+func (d RestClient) SystemGetVersion() (response azgo.SystemGetVersionResponse, err error) {
+	cluster, err := d.getCluster()
+	if err == nil {
+		response.Result.SetVersion(cluster.Version.Full).SetIsClustered(true)
+	}
+	err = setRestResult(&response, err)
+	return
+}
+
+// SystemGetOntapiVersion gets the ONTAPI version that corresponds to the ONTAP version reported by the REST
+// API, and caches & returns the result.  ONTAP 9.x reports ONTAPI version 1.(100+10x).
+func (d RestClient) SystemGetOntapiVersion() (string, error) {
+
+	if *d.ontapiVersion == "" {
+		cluster, err := d.getCluster()
+		if err != nil {
+			return "", fmt.Errorf("could not read ONTAP version: %v", err)
+		}
+		version := cluster.Version
+		if version.Generation < 9 {
+			return "", fmt.Errorf("ONTAP version %s does not support the REST API", version.Full)
+		}
+		*d.ontapiVersion = fmt.Sprintf("1.%d", (version.Generation-8)*100+version.Major*10)
+	}
+
+	return *d.ontapiVersion, nil
+}
+
+// ListNodeSerialNumbers returns the serial numbers of the nodes in the cluster
+// equivalent to GET /api/cluster/nodes?fields=serial_number
+func (d RestClient) ListNodeSerialNumbers() ([]string, error) {
+
+	serialNumbers := make([]string, 0, 0)
+
+	var nodes []restNode
+	count, err := d.getRecords("/cluster/nodes", nil, "name,serial_number", &nodes)
+	if err != nil {
+		return serialNumbers, err
+	}
+	if count == 0 {
+		return serialNumbers, errors.New("could not get node info")
+	}
+
+	for _, node := range nodes {
+		if node.SerialNumber != "" {
+			serialNumbers = append(serialNumbers, node.SerialNumber)
+		}
+	}
+
+	if len(serialNumbers) == 0 {
+		return serialNumbers, errors.New("could not get node serial numbers")
+	}
+
+	log.WithFields(log.Fields{
+		"Count":         len(serialNumbers),
+		"SerialNumbers": strings.Join(serialNumbers, ","),
+	}).Debug("Read serial numbers.")
+
+	return serialNumbers, nil
+}
+
+// EmsAutosupportLog generates an auto support message with the supplied parameters
+// equivalent to POST /api/support/ems/application-logs
+func (d RestClient) EmsAutosupportLog(
+	appVersion string,
+	autoSupport bool,
+	category string,
+	computerName string,
+	eventDescription string,
+	eventID int,
+	eventSource string,
+	logLevel int) (response azgo.EmsAutosupportLogResponse, err error) {
+
+	// The REST API names the syslog levels that ZAPI numbers, but offers fewer of them
+	severity := "notice"
+	switch logLevel {
+	case 0:
+		severity = "emergency"
+	case 1:
+		severity = "alert"
+	case 2, 3:
+		severity = "error"
+	case 6:
+		severity = "informational"
+	case 7:
+		severity = "debug"
+	}
+
+	emsLog := restEmsApplicationLog{
+		ComputerName:        computerName,
+		EventID:             eventID,
+		EventDescription:    eventDescription,
+		EventSource:         eventSource,
+		AppVersion:          appVersion,
+		Category:            category,
+		Severity:            severity,
+		AutosupportRequired: autoSupport,
+	}
+	err = setRestResult(&response, d.invokeAPI("POST", "/support/ems/application-logs", nil, emsLog, nil))
+	return
+}
+
+// MISC operations END
+/////////////////////////////////////////////////////////////////////////////
+
+/////////////////////////////////////////////////////////////////////////////
+// Value conversions BEGIN
+
+// parseRestSize converts a size given as a number of bytes, optionally with units, e.g. "1g", into bytes
+func parseRestSize(size string) (int, error) {
+	sizeBytes, err := utils.ConvertSizeToBytes(size)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %s: %v", size, err)
+	}
+	return strconv.Atoi(sizeBytes)
+}
+
+// restUnixPermissions converts Unix permissions given in symbolic form, e.g. "---rwxr-xr-x", or in octal,
+// e.g. "0755", into the form used by the REST API, in which the octal digits are written as a decimal
+// number, e.g. 755
+func restUnixPermissions(permissions string) (int, error) {
+
+	if len(permissions) == 9 || len(permissions) == 12 {
+		symbolic := permissions[len(permissions)-9:]
+		value := 0
+		for i := 0; i < 9; i += 3 {
+			digit := 0
+			for j, bit := range []byte{'r', 'w', 'x'} {
+				switch symbolic[i+j] {
+				case bit:
+					digit |= 4 >> uint(j)
+				case '-':
+				default:
+					return 0, fmt.Errorf("invalid Unix permissions %s", permissions)
+				}
+			}
+			value = value*10 + digit
+		}
+		return value, nil
+	}
+
+	octal, err := strconv.ParseUint(permissions, 8, 16)
+	if err != nil || octal > 07777 {
+		return 0, fmt.Errorf("invalid Unix permissions %s", permissions)
+	}
+	return strconv.Atoi(strconv.FormatUint(octal, 8))
+}
+
+// symbolicUnixPermissions converts Unix permissions read with the REST API, e.g. 755, into the symbolic form
+// reported by ZAPI, e.g. "---rwxr-xr-x"
+func symbolicUnixPermissions(permissions int) string {
+	digits := fmt.Sprintf("%03d", permissions%1000)
+	symbolic := "---"
+	for _, digit := range digits {
+		value := int(digit - '0')
+		for j, bit := range "rwx" {
+			if value&(4>>uint(j)) != 0 {
+				symbolic += string(bit)
+			} else {
+				symbolic += "-"
+			}
+		}
+	}
+	return symbolic
+}
+
+// parseRestDuration converts a duration reported by the REST API in ISO 8601 form, e.g. "PT1H30M", into seconds
+func parseRestDuration(duration string) (int, error) {
+	match := restDurationRegex.FindStringSubmatch(duration)
+	if match == nil || duration == "P" || duration == "PT" {
+		return 0, fmt.Errorf("invalid duration %s", duration)
+	}
+	seconds := 0
+	for i, unitSecs := range []int{24 * 60 * 60, 60 * 60, 60, 1} {
+		if match[i+1] != "" {
+			value, err := strconv.Atoi(match[i+1])
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %s", duration)
+			}
+			seconds += value * unitSecs
+		}
+	}
+	return seconds, nil
+}
+
+// Value conversions END
+/////////////////////////////////////////////////////////////////////////////
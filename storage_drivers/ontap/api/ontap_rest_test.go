@@ -0,0 +1,593 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/netapp/trident/storage_drivers/ontap/api/azgo"
+)
+
+// restStubRequest is a request received by the REST API stub
+type restStubRequest struct {
+	method string
+	path   string
+	query  url.Values
+	body   []byte
+}
+
+// restStubHandler answers a request to the REST API stub with a status and a JSON body
+type restStubHandler func(r *http.Request) (int, string)
+
+// respond returns a handler that always gives the same answer
+func respond(status int, body string) restStubHandler {
+	return func(*http.Request) (int, string) {
+		return status, body
+	}
+}
+
+// newRestServer returns a server that answers each REST API call using the handler given for its method and
+// path, e.g. "GET /api/storage/volumes", and records the requests it receives.  Calls without a handler
+// are answered as ONTAP answers requests for unknown resources.
+func newRestServer(t *testing.T, handlers map[string]restStubHandler, requests *[]restStubRequest) *httptest.Server {
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		if username, password, ok := r.BasicAuth(); !ok || username != "admin" || password != "password" {
+			t.Errorf("%s %s: missing credentials", r.Method, r.URL.Path)
+		}
+
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("could not read request: %v", err)
+		}
+		if requests != nil {
+			*requests = append(*requests, restStubRequest{r.Method, r.URL.Path, r.URL.Query(), body})
+		}
+
+		status, response := http.StatusNotFound, `{"error": {"message": "API not found", "code": "3"}}`
+		if handler, ok := handlers[r.Method+" "+r.URL.Path]; ok {
+			status, response = handler(r)
+		}
+		w.Header().Set("Content-Type", "application/hal+json")
+		w.WriteHeader(status)
+		fmt.Fprint(w, response)
+	}))
+}
+
+func newTestRestClient(server *httptest.Server) *RestClient {
+	return NewRestClient(ClientConfig{
+		ManagementLIF: server.Listener.Addr().String(),
+		SVM:           "svm0",
+		Username:      "admin",
+		Password:      "password",
+	})
+}
+
+// findRequest returns the first request the stub received with the specified method and path
+func findRequest(requests []restStubRequest, method, path string) *restStubRequest {
+	for _, request := range requests {
+		if request.method == method && request.path == path {
+			return &request
+		}
+	}
+	return nil
+}
+
+func TestRestClientSystemGetOntapiVersion(t *testing.T) {
+	for _, test := range []struct {
+		version  string
+		expected string
+		errored  bool
+	}{
+		{`{"full": "NetApp Release 9.1", "generation": 9, "major": 1, "minor": 0}`, "1.110", false},
+		{`{"full": "NetApp Release 9.8P1", "generation": 9, "major": 8, "minor": 0}`, "1.180", false},
+		{`{"full": "NetApp Release 8.3.2", "generation": 8, "major": 3, "minor": 2}`, "", true},
+	} {
+		server := newRestServer(t, map[string]restStubHandler{
+			"GET /api/cluster": respond(http.StatusOK, `{"version": `+test.version+`}`),
+		}, nil)
+
+		ontapiVersion, err := newTestRestClient(server).SystemGetOntapiVersion()
+		if ontapiVersion != test.expected {
+			t.Errorf("%s: expected %s, got %s", test.version, test.expected, ontapiVersion)
+		}
+		if (err != nil) != test.errored {
+			t.Errorf("%s: expected error %v, got %v", test.version, test.errored, err)
+		}
+		server.Close()
+	}
+}
+
+func TestRestClientVolumeCreate(t *testing.T) {
+	for _, test := range []struct {
+		name           string
+		job            string
+		expectedStatus string
+		expectedErrno  string
+	}{
+		{"job succeeds", `{"uuid": "job1", "state": "success"}`, "passed", ""},
+		{"job fails", `{"uuid": "job1", "state": "failure", "message": "no space", "code": 917927}`,
+			"failed", azgo.EAPIERROR},
+	} {
+		var requests []restStubRequest
+		server := newRestServer(t, map[string]restStubHandler{
+			"POST /api/storage/volumes":  respond(http.StatusAccepted, `{"job": {"uuid": "job1"}}`),
+			"GET /api/cluster/jobs/job1": respond(http.StatusOK, test.job),
+		}, &requests)
+
+		encrypt := true
+		response, err := newTestRestClient(server).VolumeCreate("vol1", "aggr1", "1g", "none", "default",
+			"---rwxr-xr-x", "default", "unix", &encrypt, "", "adaptive1")
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		if response.Result.ResultStatusAttr != test.expectedStatus {
+			t.Errorf("%s: expected status %s, got %s", test.name, test.expectedStatus, response.Result.ResultStatusAttr)
+		}
+		if response.Result.ResultErrnoAttr != test.expectedErrno {
+			t.Errorf("%s: expected errno %s, got %s", test.name, test.expectedErrno, response.Result.ResultErrnoAttr)
+		}
+
+		request := findRequest(requests, "POST", "/api/storage/volumes")
+		if request == nil {
+			t.Fatalf("%s: volume not created", test.name)
+		}
+		var volume restVolume
+		if err := json.Unmarshal(request.body, &volume); err != nil {
+			t.Fatalf("%s: could not decode request: %v", test.name, err)
+		}
+		for _, check := range []struct {
+			field    string
+			expected interface{}
+			actual   interface{}
+		}{
+			{"name", "vol1", volume.Name},
+			{"svm", &restNamed{Name: "svm0"}, volume.SVM},
+			{"style", "flexvol", volume.Style},
+			{"size", 1073741824, *volume.Size},
+			{"aggregates", []restNamed{{Name: "aggr1"}}, volume.Aggregates},
+			{"guarantee", "none", volume.Guarantee.Type},
+			{"snapshot policy", "default", volume.SnapshotPolicy.Name},
+			{"permissions", 755, *volume.NAS.UnixPermissions},
+			{"export policy", "default", volume.NAS.ExportPolicy.Name},
+			{"security style", "unix", volume.NAS.SecurityStyle},
+			{"encryption", true, *volume.Encryption.Enabled},
+			{"QoS policy", "adaptive1", volume.QoS.Policy.Name},
+		} {
+			if !reflect.DeepEqual(check.expected, check.actual) {
+				t.Errorf("%s: expected %s %v, got %v", test.name, check.field, check.expected, check.actual)
+			}
+		}
+		server.Close()
+	}
+}
+
+func TestRestClientVolumeGet(t *testing.T) {
+	var requests []restStubRequest
+	server := newRestServer(t, map[string]restStubHandler{
+		"GET /api/storage/volumes": respond(http.StatusOK, `{"records": [{
+			"uuid": "7d8b1ab0-0aa6-11e9-b3da-005056a7a1b6",
+			"name": "vol1",
+			"style": "flexvol",
+			"type": "rw",
+			"state": "online",
+			"size": 2147483648,
+			"aggregates": [{"name": "aggr1"}],
+			"guarantee": {"type": "none"},
+			"snapshot_policy": {"name": "none"},
+			"snapshot_directory_access_enabled": false,
+			"space": {"snapshot": {"reserve_percent": 10}},
+			"nas": {"path": "/vol1", "export_policy": {"name": "default"}, "unix_permissions": 750,
+				"security_style": "unix"},
+			"qos": {"policy": {"name": "gold"}},
+			"encryption": {"enabled": true}
+		}], "num_records": 1}`),
+	}, &requests)
+	defer server.Close()
+
+	volume, err := newTestRestClient(server).VolumeGet("vol1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if query := requests[0].query; query.Get("name") != "vol1" || query.Get("svm.name") != "svm0" ||
+		query.Get("style") != "flexvol" {
+		t.Errorf("unexpected query %v", query)
+	}
+
+	idAttrs := volume.VolumeIdAttributes()
+	spaceAttrs := volume.VolumeSpaceAttributes()
+	snapshotAttrs := volume.VolumeSnapshotAttributes()
+	exportAttrs := volume.VolumeExportAttributes()
+	securityAttrs := volume.VolumeSecurityAttributes()
+	securityUnixAttrs := securityAttrs.VolumeSecurityUnixAttributes()
+	qosAttrs := volume.VolumeQosAttributes()
+	stateAttrs := volume.VolumeStateAttributes()
+
+	for _, check := range []struct {
+		field    string
+		expected interface{}
+		actual   interface{}
+	}{
+		{"name", azgo.VolumeNameType("vol1"), idAttrs.Name()},
+		{"aggregate", "aggr1", idAttrs.ContainingAggregateName()},
+		{"junction path", azgo.JunctionPathType("/vol1"), idAttrs.JunctionPath()},
+		{"size", 2147483648, spaceAttrs.Size()},
+		{"snapshot reserve", 10, spaceAttrs.PercentageSnapshotReserve()},
+		{"space guarantee", "none", spaceAttrs.SpaceGuarantee()},
+		{"snapshot policy", "none", snapshotAttrs.SnapshotPolicy()},
+		{"snapshot directory", false, snapshotAttrs.SnapdirAccessEnabled()},
+		{"export policy", "default", exportAttrs.Policy()},
+		{"security style", "unix", securityAttrs.Style()},
+		{"permissions", "---rwxr-x---", securityUnixAttrs.Permissions()},
+		{"QoS policy", "gold", qosAttrs.PolicyGroupName()},
+		{"encryption", true, volume.Encrypt()},
+		{"state", "online", stateAttrs.State()},
+	} {
+		if !reflect.DeepEqual(check.expected, check.actual) {
+			t.Errorf("expected %s %v, got %v", check.field, check.expected, check.actual)
+		}
+	}
+}
+
+func TestRestClientVolumeGetAll(t *testing.T) {
+	var requests []restStubRequest
+	server := newRestServer(t, map[string]restStubHandler{
+		"GET /api/storage/volumes": respond(http.StatusOK, `{"records": [
+			{"uuid": "7d8b1ab0-0aa6-11e9-b3da-005056a7a1b6", "name": "trident_vol1", "type": "rw"}
+		], "num_records": 1}`),
+	}, &requests)
+	defer server.Close()
+
+	response, err := newTestRestClient(server).VolumeGetAll("trident_")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count := len(response.Result.AttributesList()); count != 1 {
+		t.Errorf("expected 1 volume, got %d", count)
+	}
+
+	// Replicas of another SVM's volumes share their names, so only read-write volumes may be listed
+	if query := requests[0].query; query.Get("name") != "trident_*" || query.Get("style") != "flexvol" ||
+		query.Get("type") != "rw" {
+		t.Errorf("unexpected query %v", query)
+	}
+}
+
+func TestRestClientErrors(t *testing.T) {
+	server := newRestServer(t, map[string]restStubHandler{
+		"GET /api/storage/volumes": func(r *http.Request) (int, string) {
+			if r.URL.Query().Get("name") == "vol1" {
+				return http.StatusOK, `{"records": [{"uuid": "uuid1", "name": "vol1"}], "num_records": 1}`
+			}
+			return http.StatusOK, `{"records": [], "num_records": 0}`
+		},
+		"GET /api/storage/volumes/uuid1/snapshots": respond(http.StatusOK, `{"records": [], "num_records": 0}`),
+		"POST /api/protocols/san/igroups": respond(http.StatusConflict,
+			`{"error": {"message": "The initiator group already exists.", "code": "5374734"}}`),
+		"GET /api/storage/luns": respond(http.StatusOK,
+			`{"records": [{"uuid": "lun1", "name": "/vol/vol1/lun0"}], "num_records": 1}`),
+		"GET /api/storage/luns/lun1/attributes/fsType": respond(http.StatusNotFound,
+			`{"error": {"message": "entry doesn't exist", "code": "4"}}`),
+		"POST /api/storage/qos/policies": respond(http.StatusForbidden,
+			`{"error": {"message": "not authorized for that command", "code": "6"}}`),
+	}, nil)
+	defer server.Close()
+	client := newTestRestClient(server)
+
+	for _, test := range []struct {
+		name     string
+		call     func() (interface{}, error)
+		expected string
+	}{
+		{"missing volume", func() (interface{}, error) {
+			return client.VolumeSize("vol2")
+		}, azgo.EVOLUMEDOESNOTEXIST},
+		{"missing snapshot", func() (interface{}, error) {
+			return client.SnapshotDelete("snap1", "vol1")
+		}, azgo.EOBJECTNOTFOUND},
+		{"duplicate igroup", func() (interface{}, error) {
+			return client.IgroupCreate("trident", "iscsi", "linux")
+		}, azgo.EVDISK_ERROR_INITGROUP_EXISTS},
+		{"missing LUN attribute", func() (interface{}, error) {
+			return client.LunGetAttribute("/vol/vol1/lun0", "fsType")
+		}, azgo.EVDISK_ERROR_NO_SUCH_ATTRIBUTE},
+		{"unauthorized", func() (interface{}, error) {
+			return client.QosPolicyGroupCreate("gold", "5000iops", "")
+		}, azgo.EAPIPRIVILEGE},
+	} {
+		response, err := test.call()
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		zerr := NewZapiError(response)
+		if zerr.IsPassed() {
+			t.Errorf("%s: expected failure", test.name)
+		} else if zerr.Code() != test.expected {
+			t.Errorf("%s: expected errno %s, got %s", test.name, test.expected, zerr.Code())
+		}
+	}
+
+	if exists, err := client.VolumeExists("vol2"); exists || err != nil {
+		t.Errorf("missing volume: expected not to exist, got %v, %v", exists, err)
+	}
+}
+
+func TestRestClientTransportError(t *testing.T) {
+	server := newRestServer(t, nil, nil)
+	client := newTestRestClient(server)
+	server.Close()
+
+	// Errors that ONTAP didn't report are returned to the caller rather than recorded in the response
+	if _, err := client.VolumeSize("vol1"); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestRestClientLunMapIfNotMapped(t *testing.T) {
+	for _, test := range []struct {
+		name         string
+		mappedIgroup string
+		expectedID   int
+		expectedPost bool
+	}{
+		{"already mapped", "trident", 3, false},
+		{"not mapped", "ig0", 5, true},
+	} {
+		var requests []restStubRequest
+		server := newRestServer(t, map[string]restStubHandler{
+			"GET /api/protocols/san/lun-maps": func(r *http.Request) (int, string) {
+				if r.URL.Query().Get("igroup.name") == "trident" {
+					return http.StatusOK, `{"records": [{"logical_unit_number": 5}], "num_records": 1}`
+				}
+				return http.StatusOK, fmt.Sprintf(`{"records": [{"igroup": {"name": "%s"}, "logical_unit_number": 3}],
+					"num_records": 1}`, test.mappedIgroup)
+			},
+			"POST /api/protocols/san/lun-maps": respond(http.StatusCreated, ""),
+		}, &requests)
+
+		lunID, err := newTestRestClient(server).LunMapIfNotMapped("trident", "/vol/vol1/lun0")
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+		} else if lunID != test.expectedID {
+			t.Errorf("%s: expected LUN ID %d, got %d", test.name, test.expectedID, lunID)
+		}
+
+		post := findRequest(requests, "POST", "/api/protocols/san/lun-maps")
+		if (post != nil) != test.expectedPost {
+			t.Errorf("%s: expected map %v, got %v", test.name, test.expectedPost, post != nil)
+		} else if post != nil {
+			var lunMap restLunMap
+			json.Unmarshal(post.body, &lunMap)
+			if lunMap.Igroup.Name != "trident" || lunMap.Lun.Name != "/vol/vol1/lun0" || lunMap.LogicalUnitNumber != nil {
+				t.Errorf("%s: unexpected map request %s", test.name, post.body)
+			}
+		}
+		server.Close()
+	}
+}
+
+func TestRestClientNetInterfaceGetDataLIFs(t *testing.T) {
+	server := newRestServer(t, map[string]restStubHandler{
+		"GET /api/network/ip/interfaces": respond(http.StatusOK, `{"records": [
+			{"name": "mgmt", "ip": {"address": "10.0.0.1"}, "services": ["management_https"]},
+			{"name": "nfs1", "ip": {"address": "10.0.0.2"}, "services": ["data_core", "data_nfs", "data_cifs"]},
+			{"name": "iscsi1", "ip": {"address": "10.0.0.3"}, "services": ["data_core", "data_iscsi"]}
+		], "num_records": 3}`),
+	}, nil)
+	defer server.Close()
+	client := newTestRestClient(server)
+
+	for _, test := range []struct {
+		protocol string
+		expected []string
+	}{
+		{"nfs", []string{"10.0.0.2"}},
+		{"iscsi", []string{"10.0.0.3"}},
+		{"fcp", []string{}},
+	} {
+		dataLIFs, err := client.NetInterfaceGetDataLIFs(test.protocol)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.protocol, err)
+		} else if !reflect.DeepEqual(dataLIFs, test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.protocol, test.expected, dataLIFs)
+		}
+	}
+}
+
+func TestRestClientSnapmirrorGet(t *testing.T) {
+	for _, test := range []struct {
+		name              string
+		relationship      string
+		expectedState     string
+		expectedStatus    string
+		expectedLagTime   int
+		expectedNotExists bool
+	}{
+		{"mirrored", `{"state": "snapmirrored", "lag_time": "PT1H2M3S", "healthy": true}`,
+			"snapmirrored", "idle", 3723, false},
+		{"transferring", `{"state": "snapmirrored", "transfer": {"state": "transferring"}, "lag_time": "P1D"}`,
+			"snapmirrored", "transferring", 86400, false},
+		{"quiesced", `{"state": "paused", "lag_time": "PT30S"}`, "snapmirrored", "quiesced", 30, false},
+		{"broken off", `{"state": "broken_off", "lag_time": "PT0S"}`, "broken-off", "idle", 0, false},
+		{"no relationship", "", "", "", 0, true},
+	} {
+		records, numRecords := "", 0
+		if test.relationship != "" {
+			records, numRecords = test.relationship, 1
+		}
+		server := newRestServer(t, map[string]restStubHandler{
+			"GET /api/snapmirror/relationships": respond(http.StatusOK,
+				fmt.Sprintf(`{"records": [%s], "num_records": %d}`, records, numRecords)),
+		}, nil)
+
+		relationship, err := newTestRestClient(server).SnapmirrorGet("svm1:vol1")
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+		} else if test.expectedNotExists {
+			if relationship != nil {
+				t.Errorf("%s: expected no relationship, got %v", test.name, relationship)
+			}
+		} else if relationship == nil {
+			t.Errorf("%s: expected relationship", test.name)
+		} else if relationship.MirrorState() != test.expectedState ||
+			relationship.RelationshipStatus() != test.expectedStatus ||
+			relationship.LagTime() != test.expectedLagTime {
+			t.Errorf("%s: expected %s/%s/%d, got %s/%s/%d", test.name,
+				test.expectedState, test.expectedStatus, test.expectedLagTime,
+				relationship.MirrorState(), relationship.RelationshipStatus(), relationship.LagTime())
+		}
+		server.Close()
+	}
+}
+
+func TestRestClientQuotas(t *testing.T) {
+	var requests []restStubRequest
+	server := newRestServer(t, map[string]restStubHandler{
+		"GET /api/storage/quota/rules": func(r *http.Request) (int, string) {
+			if r.URL.Query().Get("qtree.name") == "qtree2" {
+				return http.StatusOK, `{"records": [], "num_records": 0}`
+			}
+			return http.StatusOK, `{"records": [
+				{"uuid": "rule0", "volume": {"name": "vol1"}, "qtree": {"name": ""}},
+				{"uuid": "rule1", "volume": {"name": "vol1"}, "qtree": {"name": "qtree1"},
+					"space": {"hard_limit": 1073741824}}
+			], "num_records": 2}`
+		},
+		"POST /api/storage/quota/rules": respond(http.StatusCreated, ""),
+	}, &requests)
+	defer server.Close()
+	client := newTestRestClient(server)
+
+	response, err := client.QuotaEntryList("vol1")
+	if err = GetError(response, err); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if query := requests[0].query; query.Get("type") != "tree" || query.Get("volume.name") != "vol1" {
+		t.Errorf("unexpected query %v", query)
+	}
+	entries := response.Result.AttributesList()
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 quota rules, got %d", len(entries))
+	}
+	for i, expected := range []struct {
+		target    string
+		diskLimit string
+	}{
+		{"", "-"},
+		{"/vol/vol1/qtree1", "1048576"},
+	} {
+		if entries[i].QuotaTarget() != expected.target || entries[i].DiskLimit() != expected.diskLimit {
+			t.Errorf("expected quota rule %s with limit %s, got %s with limit %s",
+				expected.target, expected.diskLimit, entries[i].QuotaTarget(), entries[i].DiskLimit())
+		}
+	}
+
+	setResponse, err := client.QuotaSetEntry("", "vol1", "/vol/vol1/qtree2", "tree", "2048")
+	if err = GetError(setResponse, err); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	post := findRequest(requests, "POST", "/api/storage/quota/rules")
+	if post == nil {
+		t.Fatal("quota rule not created")
+	}
+	var rule restQuotaRule
+	json.Unmarshal(post.body, &rule)
+	if rule.Volume.Name != "vol1" || rule.Qtree.Name != "qtree2" || rule.Type != "tree" ||
+		*rule.Space.HardLimit != 2048*1024 {
+		t.Errorf("unexpected quota rule %s", post.body)
+	}
+}
+
+func TestRestClientSetVolumeSize(t *testing.T) {
+	var requests []restStubRequest
+	server := newRestServer(t, map[string]restStubHandler{
+		"GET /api/storage/volumes": respond(http.StatusOK,
+			`{"records": [{"uuid": "uuid1", "name": "vol1", "size": 1073741824}], "num_records": 1}`),
+		"PATCH /api/storage/volumes/uuid1": respond(http.StatusOK, ""),
+	}, &requests)
+	defer server.Close()
+	client := newTestRestClient(server)
+
+	for _, test := range []struct {
+		newSize  string
+		expected int
+	}{
+		{"+1073741824", 2147483648},
+		{"+512m", 1610612736},
+		{"5368709120", 5368709120},
+	} {
+		requests = nil
+		response, err := client.SetVolumeSize("vol1", test.newSize)
+		if err = GetError(response, err); err != nil {
+			t.Errorf("%s: unexpected error: %v", test.newSize, err)
+			continue
+		}
+		patch := findRequest(requests, "PATCH", "/api/storage/volumes/uuid1")
+		if patch == nil {
+			t.Errorf("%s: volume not resized", test.newSize)
+			continue
+		}
+		var volume restVolume
+		json.Unmarshal(patch.body, &volume)
+		if volume.Size == nil || *volume.Size != test.expected {
+			t.Errorf("%s: unexpected resize request %s", test.newSize, patch.body)
+		}
+		if response.Result.VolumeSize() != fmt.Sprintf("%d", test.expected) {
+			t.Errorf("%s: expected size %d, got %s", test.newSize, test.expected, response.Result.VolumeSize())
+		}
+	}
+}
+
+func TestRestUnixPermissions(t *testing.T) {
+	for _, test := range []struct {
+		permissions string
+		expected    int
+		errored     bool
+	}{
+		{"---rwxr-xr-x", 755, false},
+		{"rwxr-x---", 750, false},
+		{"---rwxrwxrwx", 777, false},
+		{"0755", 755, false},
+		{"700", 700, false},
+		{"---rwxr-xr-q", 0, true},
+		{"0999", 0, true},
+		{"", 0, true},
+	} {
+		permissions, err := restUnixPermissions(test.permissions)
+		if (err != nil) != test.errored {
+			t.Errorf("%s: expected error %v, got %v", test.permissions, test.errored, err)
+		} else if permissions != test.expected {
+			t.Errorf("%s: expected %d, got %d", test.permissions, test.expected, permissions)
+		}
+		if !test.errored && test.permissions[0] == '-' {
+			if symbolic := symbolicUnixPermissions(permissions); symbolic != test.permissions {
+				t.Errorf("%d: expected %s, got %s", permissions, test.permissions, symbolic)
+			}
+		}
+	}
+}
+
+func TestGetRestResourceName(t *testing.T) {
+	for _, test := range []struct {
+		path     string
+		expected string
+	}{
+		{"/storage/volumes", "/storage/volumes"},
+		{"/storage/volumes/7d8b1ab0-0aa6-11e9-b3da-005056a7a1b6/snapshots", "/storage/volumes/{ref}/snapshots"},
+		{"/protocols/nfs/export-policies/42/rules", "/protocols/nfs/export-policies/{ref}/rules"},
+		{"/cluster", "/cluster"},
+	} {
+		if name := getRestResourceName(test.path); name != test.expected {
+			t.Errorf("%s: expected %s, got %s", test.path, test.expected, name)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package api
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// newZapiServer returns a server that answers each ZAPI with the results given for it, which are the
+// attributes and contents of a results element, and records the names of the ZAPIs it receives.
+func newZapiServer(t *testing.T, results map[string]string, calls *[]string) *httptest.Server {
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		// The ZAPI is the first element inside the netapp element
+		zapiName := ""
+		decoder := xml.NewDecoder(r.Body)
+		for depth := 0; zapiName == ""; {
+			token, err := decoder.Token()
+			if err != nil {
+				t.Errorf("could not read ZAPI request: %v", err)
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if element, ok := token.(xml.StartElement); ok {
+				if depth == 1 {
+					zapiName = element.Name.Local
+				}
+				depth++
+			}
+		}
+		if calls != nil {
+			*calls = append(*calls, zapiName)
+		}
+
+		result, ok := results[zapiName]
+		if !ok {
+			result = `status="failed" errno="13005" reason="Unable to find API"`
+		}
+		w.Header().Set("Content-Type", "text/xml")
+		fmt.Fprintf(w, `<?xml version='1.0' encoding='UTF-8'?>`+
+			`<netapp version='1.130' xmlns='http://www.netapp.com/filer/admin'><results %s</results></netapp>`, result)
+	}))
+}
+
+func newTestClient(server *httptest.Server) *Client {
+	return NewClient(ClientConfig{
+		ManagementLIF: server.Listener.Addr().String(),
+		SVM:           "svm0",
+		Username:      "admin",
+		Password:      "password",
+	})
+}
+
+func TestClientSupportsFeature(t *testing.T) {
+	for _, test := range []struct {
+		ontapiMinor int
+		feature     feature
+		expected    bool
+	}{
+		{100, FlexGroups, true},
+		{110, QosMinimumThroughput, false},
+		{120, QosMinimumThroughput, true},
+		{130, AdaptiveQosPolicies, true},
+		{130, feature("UNKNOWN"), false},
+	} {
+		server := newZapiServer(t, map[string]string{
+			"system-get-ontapi-version": fmt.Sprintf(
+				`status="passed"><major-version>1</major-version><minor-version>%d</minor-version>`,
+				test.ontapiMinor),
+		}, nil)
+
+		if supported := newTestClient(server).SupportsFeature(test.feature); supported != test.expected {
+			t.Errorf("1.%d %s: expected %v, got %v", test.ontapiMinor, test.feature, test.expected, supported)
+		}
+		server.Close()
+	}
+}
+
+func TestClientVolumeExists(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		result   string
+		expected bool
+		errored  bool
+	}{
+		{"exists", `status="passed"><volume-size>1g</volume-size>`, true, false},
+		{"no volume", `status="failed" errno="13040" reason="Volume vol1 does not exist">`, false, false},
+		{"no object", `status="failed" errno="15661" reason="entry doesn't exist">`, false, false},
+		{"no privilege", `status="failed" errno="13003" reason="Insufficient privileges">`, false, true},
+	} {
+		server := newZapiServer(t, map[string]string{"volume-size": test.result}, nil)
+
+		exists, err := newTestClient(server).VolumeExists("vol1")
+		if exists != test.expected {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, exists)
+		}
+		if (err != nil) != test.errored {
+			t.Errorf("%s: expected error %v, got %v", test.name, test.errored, err)
+		}
+		server.Close()
+	}
+}
+
+func TestClientLunMapIfNotMapped(t *testing.T) {
+	for _, test := range []struct {
+		name          string
+		igroups       string
+		expectedID    int
+		expectedCalls []string
+	}{
+		{
+			"already mapped",
+			`<initiator-group-info><initiator-group-name>ig0</initiator-group-name><lun-id>1</lun-id></initiator-group-info>` +
+				`<initiator-group-info><initiator-group-name>trident</initiator-group-name><lun-id>3</lun-id></initiator-group-info>`,
+			3,
+			[]string{"lun-map-list-info"},
+		},
+		{
+			"not mapped",
+			`<initiator-group-info><initiator-group-name>ig0</initiator-group-name><lun-id>1</lun-id></initiator-group-info>`,
+			5,
+			[]string{"lun-map-list-info", "lun-map"},
+		},
+	} {
+		var calls []string
+		server := newZapiServer(t, map[string]string{
+			"lun-map-list-info": `status="passed"><initiator-groups>` + test.igroups + `</initiator-groups>`,
+			"lun-map":           `status="passed"><lun-id-assigned>5</lun-id-assigned>`,
+		}, &calls)
+
+		lunID, err := newTestClient(server).LunMapIfNotMapped("trident", "/vol/vol1/lun0")
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+		} else if lunID != test.expectedID {
+			t.Errorf("%s: expected LUN ID %d, got %d", test.name, test.expectedID, lunID)
+		}
+		if !reflect.DeepEqual(calls, test.expectedCalls) {
+			t.Errorf("%s: expected calls %v, got %v", test.name, test.expectedCalls, calls)
+		}
+		server.Close()
+	}
+}
+
+func TestClientNetInterfaceGetDataLIFs(t *testing.T) {
+	server := newZapiServer(t, map[string]string{
+		"net-interface-get-iter": `status="passed"><attributes-list>` +
+			`<net-interface-info><address>10.0.0.1</address><interface-name>mgmt</interface-name>` +
+			`<data-protocols><data-protocol>none</data-protocol></data-protocols></net-interface-info>` +
+			`<net-interface-info><address>10.0.0.2</address><interface-name>nfs1</interface-name>` +
+			`<data-protocols><data-protocol>nfs</data-protocol><data-protocol>cifs</data-protocol></data-protocols>` +
+			`</net-interface-info>` +
+			`<net-interface-info><address>10.0.0.3</address><interface-name>iscsi1</interface-name>` +
+			`<data-protocols><data-protocol>iscsi</data-protocol></data-protocols></net-interface-info>` +
+			`</attributes-list><num-records>3</num-records>`,
+	}, nil)
+	defer server.Close()
+
+	dataLIFs, err := newTestClient(server).NetInterfaceGetDataLIFs("nfs")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []string{"10.0.0.2"}; !reflect.DeepEqual(dataLIFs, expected) {
+		t.Errorf("expected %v, got %v", expected, dataLIFs)
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package api
+
+import (
+	"github.com/netapp/trident/storage_drivers/ontap/api/azgo"
+)
+
+// Interface is the set of operations the ONTAP drivers perform on a storage controller.  It is implemented
+// by Client, which uses ZAPI, and by RestClient, which uses the ONTAP REST API.  Both report their results
+// in AZGO responses, so callers may use GetError and NewZapiError regardless of the API in use.
+type Interface interface {
+	SupportsFeature(feature feature) bool
+
+	IgroupCreate(initiatorGroupName, initiatorGroupType, osType string) (azgo.IgroupCreateResponse, error)
+	IgroupAdd(initiatorGroupName, initiator string) (azgo.IgroupAddResponse, error)
+	IgroupRemove(initiatorGroupName, initiator string, force bool) (azgo.IgroupRemoveResponse, error)
+	IgroupDestroy(initiatorGroupName string) (azgo.IgroupDestroyResponse, error)
+	IgroupList() (azgo.IgroupGetIterResponse, error)
+
+	LunCreate(lunPath string, sizeInBytes int, osType string, spaceReserved bool) (azgo.LunCreateBySizeResponse, error)
+	LunGetSerialNumber(lunPath string) (azgo.LunGetSerialNumberResponse, error)
+	LunMap(initiatorGroupName, lunPath string, lunID int) (azgo.LunMapResponse, error)
+	LunMapAutoID(initiatorGroupName, lunPath string) (azgo.LunMapResponse, error)
+	LunMapIfNotMapped(initiatorGroupName, lunPath string) (int, error)
+	LunMapListInfo(lunPath string) (azgo.LunMapListInfoResponse, error)
+	LunOffline(lunPath string) (azgo.LunOfflineResponse, error)
+	LunOnline(lunPath string) (azgo.LunOnlineResponse, error)
+	LunDestroy(lunPath string, force bool) (azgo.LunDestroyResponse, error)
+	LunCloneCreate(volumeName, sourceLun, destinationLun string, spaceReserved bool) (azgo.CloneCreateResponse, error)
+	LunResize(lunPath string, sizeBytes int) (azgo.LunResizeResponse, error)
+	LunSetAttribute(lunPath, name, value string) (azgo.LunSetAttributeResponse, error)
+	LunGetAttribute(lunPath, name string) (azgo.LunGetAttributeResponse, error)
+	LunGet(path string) (azgo.LunInfoType, error)
+	LunGetAll(pathPattern string) (azgo.LunGetIterResponse, error)
+	LunCount(volume string) (int, error)
+	LunExists(name, volumePrefix string) (bool, string, error)
+
+	VolumeCreate(name, aggregateName, size, spaceReserve, snapshotPolicy, unixPermissions,
+		exportPolicy, securityStyle string, encrypt *bool, qosPolicy, adaptiveQosPolicy string,
+	) (azgo.VolumeCreateResponse, error)
+	VolumeCreateDP(name, aggregateName, size, exportPolicy string) (azgo.VolumeCreateResponse, error)
+	VolumeCloneCreate(name, source, snapshot string) (azgo.VolumeCloneCreateResponse, error)
+	VolumeCloneSplitStart(name string) (azgo.VolumeCloneSplitStartResponse, error)
+	VolumeDisableSnapshotDirectoryAccess(name string) (azgo.VolumeModifyIterResponse, error)
+	VolumeExists(name string) (bool, error)
+	VolumeSize(name string) (azgo.VolumeSizeResponse, error)
+	SetVolumeSize(name, newSize string) (azgo.VolumeSizeResponse, error)
+	VolumeMount(name, junctionPath string) (azgo.VolumeMountResponse, error)
+	VolumeUnmount(name string, force bool) (azgo.VolumeUnmountResponse, error)
+	VolumeOffline(name string) (azgo.VolumeOfflineResponse, error)
+	VolumeDestroy(name string, force bool) (azgo.VolumeDestroyResponse, error)
+	VolumeRename(name, newName string) (azgo.VolumeRenameResponse, error)
+	VolumeGet(name string) (azgo.VolumeAttributesType, error)
+	VolumeGetAll(prefix string) (azgo.VolumeGetIterResponse, error)
+	VolumeList(prefix string) (azgo.VolumeGetIterResponse, error)
+	VolumeListByAttrs(prefix, aggregate, spaceReserve, snapshotPolicy string, snapshotDir bool, encrypt *bool,
+		qosPolicy, adaptiveQosPolicy string,
+	) (azgo.VolumeGetIterResponse, error)
+	VolumeGetRootName() (azgo.VolumeGetRootNameResponse, error)
+
+	FlexGroupCreate(name string, size int, aggregates []string, spaceReserve, snapshotPolicy,
+		unixPermissions, exportPolicy, securityStyle string, encrypt *bool, qosPolicy, adaptiveQosPolicy string,
+	) (azgo.VolumeCreateAsyncResponse, error)
+	FlexGroupDestroy(name string, force bool) (azgo.VolumeDestroyAsyncResponse, error)
+	FlexGroupSetSize(name, newSize string) (azgo.VolumeSizeAsyncResponse, error)
+	FlexGroupDisableSnapshotDirectoryAccess(name string) (azgo.VolumeModifyIterAsyncResponse, error)
+	FlexGroupExists(name string) (bool, error)
+	FlexGroupGet(name string) (azgo.VolumeAttributesType, error)
+	FlexGroupGetAll(prefix string) (azgo.VolumeGetIterResponse, error)
+
+	JobGet(id int) (azgo.JobInfoType, error)
+
+	QtreeCreate(name, volumeName, unixPermissions, exportPolicy, securityStyle string) (azgo.QtreeCreateResponse, error)
+	QtreeRename(path, newPath string) (azgo.QtreeRenameResponse, error)
+	QtreeDestroyAsync(path string, force bool) (azgo.QtreeDeleteAsyncResponse, error)
+	QtreeList(prefix, volumePrefix string) (azgo.QtreeListIterResponse, error)
+	QtreeCount(volume string) (int, error)
+	QtreeExists(name, volumePrefix string) (bool, string, error)
+	QtreeGet(name, volumePrefix string) (azgo.QtreeInfoType, error)
+	QtreeGetAll(volumePrefix string) (azgo.QtreeListIterResponse, error)
+
+	QuotaOn(volume string) (azgo.QuotaOnResponse, error)
+	QuotaOff(volume string) (azgo.QuotaOffResponse, error)
+	QuotaResize(volume string) (azgo.QuotaResizeResponse, error)
+	QuotaStatus(volume string) (azgo.QuotaStatusResponse, error)
+	QuotaSetEntry(qtreeName, volumeName, quotaTarget, quotaType, diskLimit string) (azgo.QuotaSetEntryResponse, error)
+	QuotaEntryGet(target string) (azgo.QuotaEntryType, error)
+	QuotaEntryList(volume string) (azgo.QuotaListEntriesIterResponse, error)
+
+	ExportPolicyCreate(policy string) (azgo.ExportPolicyCreateResponse, error)
+	ExportRuleCreate(policy, clientMatch string, protocols, roSecFlavors, rwSecFlavors, suSecFlavors []string,
+	) (azgo.ExportRuleCreateResponse, error)
+	ExportRuleGetIterRequest(policy string) (azgo.ExportRuleGetIterResponse, error)
+
+	SnapshotCreate(name, volumeName string) (azgo.SnapshotCreateResponse, error)
+	SnapshotGetByVolume(volumeName string) (azgo.SnapshotGetIterResponse, error)
+	SnapshotDelete(name, volumeName string) (azgo.SnapshotDeleteResponse, error)
+	SnapshotRestoreVolume(name, volumeName string) (azgo.SnapshotRestoreVolumeResponse, error)
+
+	QosPolicyGroupCreate(name, maxThroughput, minThroughput string) (azgo.QosPolicyGroupCreateResponse, error)
+	QosPolicyGroupDelete(name string) (azgo.QosPolicyGroupDeleteResponse, error)
+	QosPolicyGroupGet(name string) (azgo.QosPolicyGroupInfoType, error)
+
+	IscsiServiceGetIterRequest() (azgo.IscsiServiceGetIterResponse, error)
+
+	VserverGetIterRequest() (azgo.VserverGetIterResponse, error)
+	GetVserverAggregateNames() ([]string, error)
+	VserverShowAggrGetIterRequest() (azgo.VserverShowAggrGetIterResponse, error)
+
+	AggrGetIterRequest() (azgo.AggrGetIterResponse, error)
+
+	SnapmirrorGetLoadSharingMirrors(volume string) (azgo.SnapmirrorGetIterResponse, error)
+	SnapmirrorUpdateLoadSharingMirrors(sourceLocation string) (azgo.SnapmirrorUpdateLsSetResponse, error)
+	SnapmirrorCreate(sourceLocation, destinationLocation, schedule, policy string) (azgo.SnapmirrorCreateResponse, error)
+	SnapmirrorInitialize(sourceLocation, destinationLocation string) (azgo.SnapmirrorInitializeResponse, error)
+	SnapmirrorGet(destinationLocation string) (*azgo.SnapmirrorInfoType, error)
+	SnapmirrorQuiesce(destinationLocation string) (azgo.SnapmirrorQuiesceResponse, error)
+	SnapmirrorBreak(destinationLocation string) (azgo.SnapmirrorBreakResponse, error)
+	SnapmirrorDelete(sourceLocation, destinationLocation string) (azgo.SnapmirrorDeleteResponse, error)
+	SnapmirrorRelease(sourceLocation, destinationLocation string) (azgo.SnapmirrorReleaseResponse, error)
+
+	NetInterfaceGet() (azgo.NetInterfaceGetIterResponse, error)
+	NetInterfaceGetDataLIFs(protocol string) ([]string, error)
+	SystemGetVersion() (azgo.SystemGetVersionResponse, error)
+	SystemGetOntapiVersion() (string, error)
+	ListNodeSerialNumbers() ([]string, error)
+	EmsAutosupportLog(appVersion string, autoSupport bool, category string, computerName string,
+		eventDescription string, eventID int, eventSource string, logLevel int,
+	) (azgo.EmsAutosupportLogResponse, error)
+}
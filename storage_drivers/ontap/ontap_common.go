@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"net"
 	"os"
 	"os/exec"
@@ -38,7 +39,7 @@ type Telemetry struct {
 
 type StorageDriver interface {
 	GetConfig() *drivers.OntapStorageDriverConfig
-	GetAPI() *api.Client
+	GetAPI() api.Interface
 	GetTelemetry() *Telemetry
 	Name() string
 }
@@ -79,7 +80,7 @@ func InitializeOntapTelemetry(d StorageDriver) *Telemetry {
 
 // InitializeOntapDriver sets up the API client and performs all other initialization tasks
 // that are common to all the ONTAP drivers.
-func InitializeOntapDriver(config *drivers.OntapStorageDriverConfig) (*api.Client, error) {
+func InitializeOntapDriver(config *drivers.OntapStorageDriverConfig) (api.Interface, error) {
 
 	if config.DebugTraceFlags["method"] {
 		fields := log.Fields{"Method": "InitializeOntapDriver", "Type": "ontap_common"}
@@ -133,9 +134,9 @@ func InitializeOntapDriver(config *drivers.OntapStorageDriverConfig) (*api.Clien
 	return client, nil
 }
 
-// InitializeOntapAPI returns an ontap.Client ZAPI client.  If the SVM isn't specified in the config
-// file, this method attempts to derive the one to use.
-func InitializeOntapAPI(config *drivers.OntapStorageDriverConfig) (*api.Client, error) {
+// InitializeOntapAPI returns an ONTAP client, using ZAPI or the REST API as configured.  If the SVM isn't
+// specified in the config file, this method attempts to derive the one to use.
+func InitializeOntapAPI(config *drivers.OntapStorageDriverConfig) (api.Interface, error) {
 
 	if config.DebugTraceFlags["method"] {
 		fields := log.Fields{"Method": "InitializeOntapAPI", "Type": "ontap_common"}
@@ -143,13 +144,7 @@ func InitializeOntapAPI(config *drivers.OntapStorageDriverConfig) (*api.Client,
 		defer log.WithFields(fields).Debug("<<<< InitializeOntapAPI")
 	}
 
-	client := api.NewClient(api.ClientConfig{
-		ManagementLIF:   config.ManagementLIF,
-		SVM:             config.SVM,
-		Username:        config.Username,
-		Password:        config.Password,
-		DebugTraceFlags: config.DebugTraceFlags,
-	})
+	client := newOntapAPI(config, config.ManagementLIF, config.SVM)
 
 	if config.SVM != "" {
 		log.WithField("SVM", config.SVM).Debug("Using specified SVM.")
@@ -168,20 +163,33 @@ func InitializeOntapAPI(config *drivers.OntapStorageDriverConfig) (*api.Client,
 
 	// Update everything to use our derived SVM
 	config.SVM = vserverResponse.Result.AttributesList()[0].VserverName()
-	client = api.NewClient(api.ClientConfig{
-		ManagementLIF:   config.ManagementLIF,
-		SVM:             config.SVM,
+	client = newOntapAPI(config, config.ManagementLIF, config.SVM)
+	log.WithField("SVM", config.SVM).Debug("Using derived SVM.")
+
+	return client, nil
+}
+
+// newOntapAPI returns a client for the specified SVM, which uses the ONTAP REST API if the config asks for it
+// and ZAPI otherwise.
+func newOntapAPI(config *drivers.OntapStorageDriverConfig, managementLIF, svm string) api.Interface {
+
+	clientConfig := api.ClientConfig{
+		ManagementLIF:   managementLIF,
+		SVM:             svm,
 		Username:        config.Username,
 		Password:        config.Password,
 		DebugTraceFlags: config.DebugTraceFlags,
-	})
-	log.WithField("SVM", config.SVM).Debug("Using derived SVM.")
+	}
 
-	return client, nil
+	if config.UseREST {
+		log.WithField("SVM", svm).Debug("Using ONTAP REST API.")
+		return api.NewRestClient(clientConfig)
+	}
+	return api.NewClient(clientConfig)
 }
 
 // ValidateAggregate returns an error if the configured aggregate is not available to the Vserver.
-func ValidateAggregate(api *api.Client, config *drivers.OntapStorageDriverConfig) error {
+func ValidateAggregate(api api.Interface, config *drivers.OntapStorageDriverConfig) error {
 
 	if config.DebugTraceFlags["method"] {
 		fields := log.Fields{"Method": "ValidateAggregate", "Type": "ontap_common"}
@@ -215,8 +223,8 @@ func ValidateAggregate(api *api.Client, config *drivers.OntapStorageDriverConfig
 	return fmt.Errorf("aggregate %s does not exist or is not assigned to SVM %s", config.Aggregate, config.SVM)
 }
 
-// ValidateNASDriver contains the validation logic shared between ontap-nas and ontap-nas-economy.
-func ValidateNASDriver(api *api.Client, config *drivers.OntapStorageDriverConfig) error {
+// ValidateNASDriver contains the validation logic shared between ontap-nas, ontap-nas-economy, and ontap-nas-flexgroup.
+func ValidateNASDriver(api api.Interface, config *drivers.OntapStorageDriverConfig) error {
 
 	if config.DebugTraceFlags["method"] {
 		fields := log.Fields{"Method": "ValidateNASDriver", "Type": "ontap_common"}
@@ -246,7 +254,57 @@ func ValidateNASDriver(api *api.Client, config *drivers.OntapStorageDriverConfig
 
 	}
 
+	// FlexGroups span all of the SVM's aggregates, so they don't need one to be configured
+	if config.DriverContext == trident.ContextDocker && config.StorageDriverName != drivers.OntapNASFlexGroupStorageDriverName {
+		// Make sure the configured aggregate is available
+		err = ValidateAggregate(api, config)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ValidateSANDriver contains the validation logic shared between ontap-san and ontap-san-economy.
+func ValidateSANDriver(api api.Interface, config *drivers.OntapStorageDriverConfig) error {
+
+	if config.DebugTraceFlags["method"] {
+		fields := log.Fields{"Method": "ValidateSANDriver", "Type": "ontap_common"}
+		log.WithFields(fields).Debug(">>>> ValidateSANDriver")
+		defer log.WithFields(fields).Debug("<<<< ValidateSANDriver")
+	}
+
+	dataLIFs, err := api.NetInterfaceGetDataLIFs("iscsi")
+	if err != nil {
+		return err
+	}
+
+	if len(dataLIFs) == 0 {
+		return fmt.Errorf("no iSCSI data LIFs found on SVM %s", config.SVM)
+	} else {
+		log.WithField("dataLIFs", dataLIFs).Debug("Found iSCSI LIFs.")
+	}
+
+	// If they didn't set a LIF to use in the config, we'll set it to the first iSCSI LIF we happen to find
+	if config.DataLIF == "" {
+		config.DataLIF = dataLIFs[0]
+	} else {
+		err := ValidateDataLIFs(config, dataLIFs)
+		if err != nil {
+			return fmt.Errorf("data LIF validation failed: %v", err)
+		}
+
+		config.DataLIF = dataLIFs[0]
+	}
+
 	if config.DriverContext == trident.ContextDocker {
+		// Make sure this host is logged into the ONTAP iSCSI target
+		err := utils.EnsureIscsiSession(config.DataLIF)
+		if err != nil {
+			return fmt.Errorf("error establishing iSCSI session: %v", err)
+		}
+
 		// Make sure the configured aggregate is available
 		err = ValidateAggregate(api, config)
 		if err != nil {
@@ -316,6 +374,9 @@ func PopulateConfigurationDefaults(config *drivers.OntapStorageDriverConfig) err
 		prefix := drivers.GetDefaultStoragePrefix(config.DriverContext)
 		config.StoragePrefix = &prefix
 	}
+	if err := drivers.ValidateOrphanPolicy(config.CommonStorageDriverConfig); err != nil {
+		return err
+	}
 
 	if config.SpaceReserve == "" {
 		config.SpaceReserve = DefaultSpaceReserve
@@ -362,27 +423,117 @@ func PopulateConfigurationDefaults(config *drivers.OntapStorageDriverConfig) err
 		config.Encryption = DefaultEncryption
 	}
 
+	if config.QosPolicy != "" && config.AdaptiveQosPolicy != "" {
+		return errors.New("only one of qosPolicy and adaptiveQosPolicy may be set")
+	}
+
+	if err := populateVirtualPoolDefaults(config); err != nil {
+		return err
+	}
+
 	log.WithFields(log.Fields{
-		"StoragePrefix":   *config.StoragePrefix,
-		"SpaceReserve":    config.SpaceReserve,
-		"SnapshotPolicy":  config.SnapshotPolicy,
-		"UnixPermissions": config.UnixPermissions,
-		"SnapshotDir":     config.SnapshotDir,
-		"ExportPolicy":    config.ExportPolicy,
-		"SecurityStyle":   config.SecurityStyle,
-		"NfsMountOptions": config.NfsMountOptions,
-		"SplitOnClone":    config.SplitOnClone,
-		"FileSystemType":  config.FileSystemType,
-		"Encryption":      config.Encryption,
+		"StoragePrefix":     *config.StoragePrefix,
+		"SpaceReserve":      config.SpaceReserve,
+		"SnapshotPolicy":    config.SnapshotPolicy,
+		"UnixPermissions":   config.UnixPermissions,
+		"SnapshotDir":       config.SnapshotDir,
+		"ExportPolicy":      config.ExportPolicy,
+		"SecurityStyle":     config.SecurityStyle,
+		"NfsMountOptions":   config.NfsMountOptions,
+		"SplitOnClone":      config.SplitOnClone,
+		"FileSystemType":    config.FileSystemType,
+		"Encryption":        config.Encryption,
+		"QosPolicy":         config.QosPolicy,
+		"AdaptiveQosPolicy": config.AdaptiveQosPolicy,
 	}).Debugf("Configuration defaults")
 
 	return nil
 }
 
+// populateVirtualPoolDefaults validates the virtual pools in the config, and
+// gives each the backend's defaults for the settings it doesn't set.
+func populateVirtualPoolDefaults(config *drivers.OntapStorageDriverConfig) error {
+
+	poolNames := make(map[string]bool, len(config.Storage))
+	for i := range config.Storage {
+		pool := &config.Storage[i]
+
+		if pool.Name == "" {
+			return fmt.Errorf("virtual pool %d has no name", i)
+		}
+		if poolNames[pool.Name] {
+			return fmt.Errorf("virtual pool name %s is used more than once", pool.Name)
+		}
+		poolNames[pool.Name] = true
+
+		if err := drivers.ValidateLabels(pool.Labels); err != nil {
+			return fmt.Errorf("virtual pool %s: %v", pool.Name, err)
+		}
+
+		if pool.SpaceReserve == "" {
+			pool.SpaceReserve = config.SpaceReserve
+		}
+		if pool.SnapshotPolicy == "" {
+			pool.SnapshotPolicy = config.SnapshotPolicy
+		}
+		if pool.UnixPermissions == "" {
+			pool.UnixPermissions = config.UnixPermissions
+		}
+		if pool.SnapshotDir == "" {
+			pool.SnapshotDir = config.SnapshotDir
+		}
+		if pool.ExportPolicy == "" {
+			pool.ExportPolicy = config.ExportPolicy
+		}
+		if pool.SecurityStyle == "" {
+			pool.SecurityStyle = config.SecurityStyle
+		}
+		if pool.SplitOnClone == "" {
+			pool.SplitOnClone = config.SplitOnClone
+		} else if _, err := strconv.ParseBool(pool.SplitOnClone); err != nil {
+			return fmt.Errorf("virtual pool %s: invalid boolean value for splitOnClone: %v", pool.Name, err)
+		}
+		if pool.FileSystemType == "" {
+			pool.FileSystemType = config.FileSystemType
+		}
+		if pool.Encryption == "" {
+			pool.Encryption = config.Encryption
+		}
+		if pool.QosPolicy == "" && pool.AdaptiveQosPolicy == "" {
+			pool.QosPolicy = config.QosPolicy
+			pool.AdaptiveQosPolicy = config.AdaptiveQosPolicy
+		} else if pool.QosPolicy != "" && pool.AdaptiveQosPolicy != "" {
+			return fmt.Errorf("virtual pool %s: only one of qosPolicy and adaptiveQosPolicy may be set", pool.Name)
+		}
+	}
+
+	return nil
+}
+
+// getPoolQosPolicies returns the QoS policy group and adaptive QoS policy group that volumes
+// in a pool are placed in by default.
+func getPoolQosPolicies(config *drivers.OntapStorageDriverConfig, poolName string) (string, string) {
+	if virtualPool := getVirtualPool(config, poolName); virtualPool != nil {
+		return virtualPool.QosPolicy, virtualPool.AdaptiveQosPolicy
+	}
+	return config.QosPolicy, config.AdaptiveQosPolicy
+}
+
+// getVirtualPool returns the virtual pool with the given name, or nil if the
+// config defines no such pool.
+func getVirtualPool(config *drivers.OntapStorageDriverConfig, name string) *drivers.OntapStorageDriverPool {
+	for i := range config.Storage {
+		if config.Storage[i].Name == name {
+			return &config.Storage[i]
+		}
+	}
+	return nil
+}
+
 // ValidateEncryptionAttribute returns true/false if encryption is being requested of a backend that
 // supports NetApp Volume Encryption, and nil otherwise so that the ZAPIs may be sent without
 // any reference to encryption.
-func ValidateEncryptionAttribute(encryption string, client *api.Client) (*bool, error) {
+func ValidateEncryptionAttribute(encryption string, client api.Interface) (*bool, error) {
 
 	enableEncryption, err := strconv.ParseBool(encryption)
 	if err != nil {
@@ -400,6 +551,233 @@ func ValidateEncryptionAttribute(encryption string, client *api.Client) (*bool,
 	}
 }
 
+// QoS limits that may be set for a single volume, e.g. "maxIOPS=5000,minIOPS=1000"
+const (
+	qosMaxIOPS       = "maxIOPS"
+	qosMinIOPS       = "minIOPS"
+	qosMaxThroughput = "maxThroughput"
+	qosMinThroughput = "minThroughput"
+)
+
+// parseQosLimits parses the QoS limits of a single volume into the maximum and minimum
+// throughput of a QoS policy group.  Each limit is given either in IOPS or, as throughput,
+// in MB/s, but not both.  The positional form the SolidFire driver accepts, min,max,burst
+// in IOPS, is understood as well.
+func parseQosLimits(qos string) (maxThroughput, minThroughput string, err error) {
+
+	var limits map[string]int
+	if strings.Contains(qos, "=") {
+		limits, err = parseNamedQosLimits(qos)
+	} else {
+		limits, err = parsePositionalQosLimits(qos)
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	maxIOPS, hasMaxIOPS := limits[qosMaxIOPS]
+	minIOPS, hasMinIOPS := limits[qosMinIOPS]
+	maxMBps, hasMaxMBps := limits[qosMaxThroughput]
+	minMBps, hasMinMBps := limits[qosMinThroughput]
+
+	if hasMaxIOPS && hasMaxMBps {
+		return "", "", fmt.Errorf("only one of %s and %s may be set", qosMaxIOPS, qosMaxThroughput)
+	}
+	if hasMinIOPS && hasMinMBps {
+		return "", "", fmt.Errorf("only one of %s and %s may be set", qosMinIOPS, qosMinThroughput)
+	}
+	if hasMaxIOPS && hasMinIOPS && minIOPS > maxIOPS {
+		return "", "", fmt.Errorf("%s may not exceed %s", qosMinIOPS, qosMaxIOPS)
+	}
+	if hasMaxMBps && hasMinMBps && minMBps > maxMBps {
+		return "", "", fmt.Errorf("%s may not exceed %s", qosMinThroughput, qosMaxThroughput)
+	}
+
+	if hasMaxIOPS {
+		maxThroughput = fmt.Sprintf("%diops", maxIOPS)
+	} else if hasMaxMBps {
+		maxThroughput = fmt.Sprintf("%dMB/s", maxMBps)
+	}
+	if hasMinIOPS {
+		minThroughput = fmt.Sprintf("%diops", minIOPS)
+	} else if hasMinMBps {
+		minThroughput = fmt.Sprintf("%dMB/s", minMBps)
+	}
+	return maxThroughput, minThroughput, nil
+}
+
+// parseNamedQosLimits parses QoS limits of the form maxIOPS=5000,minIOPS=1000.
+func parseNamedQosLimits(qos string) (map[string]int, error) {
+
+	limits := make(map[string]int)
+	for _, limit := range strings.Split(qos, ",") {
+		pair := strings.SplitN(limit, "=", 2)
+		if len(pair) != 2 {
+			return nil, fmt.Errorf("invalid QoS limit %s; expected the form name=value", limit)
+		}
+		key := strings.TrimSpace(pair[0])
+		switch key {
+		case qosMaxIOPS, qosMinIOPS, qosMaxThroughput, qosMinThroughput:
+		default:
+			return nil, fmt.Errorf("unknown QoS limit %s; expected one of %s, %s, %s or %s",
+				key, qosMaxIOPS, qosMinIOPS, qosMaxThroughput, qosMinThroughput)
+		}
+		value, err := strconv.Atoi(strings.TrimSpace(pair[1]))
+		if err != nil || value <= 0 {
+			return nil, fmt.Errorf("invalid value for QoS limit %s; expected a positive integer", key)
+		}
+		limits[key] = value
+	}
+	return limits, nil
+}
+
+// parsePositionalQosLimits parses QoS limits of the form min,max[,burst] in IOPS, where 0
+// leaves a limit unset.  ONTAP has no burst limit, so any burst is ignored.
+func parsePositionalQosLimits(qos string) (map[string]int, error) {
+
+	fields := strings.Split(qos, ",")
+	if len(fields) < 2 || len(fields) > 3 {
+		return nil, fmt.Errorf("invalid QoS limits %s; expected the form min,max[,burst] or name=value", qos)
+	}
+	values := make([]int, len(fields))
+	for i, field := range fields {
+		value, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || value < 0 {
+			return nil, fmt.Errorf("invalid QoS limits %s; expected non-negative integers", qos)
+		}
+		values[i] = value
+	}
+	if len(values) == 3 && values[2] > 0 {
+		log.WithField("qos", qos).Warning("ONTAP doesn't support burst IOPS; ignoring.")
+	}
+
+	limits := make(map[string]int)
+	if values[0] > 0 {
+		limits[qosMinIOPS] = values[0]
+	}
+	if values[1] > 0 {
+		limits[qosMaxIOPS] = values[1]
+	}
+	if len(limits) == 0 {
+		return nil, fmt.Errorf("invalid QoS limits %s; expected a minimum or maximum IOPS", qos)
+	}
+	return limits, nil
+}
+
+// getQosPolicies returns the existing QoS policy group or adaptive QoS policy group a new
+// volume should be placed in, if any.
+func getQosPolicies(d StorageDriver, opts map[string]string) (qosPolicy, adaptiveQosPolicy string, err error) {
+
+	config := d.GetConfig()
+	qosPolicy = utils.GetV(opts, "qosPolicy", config.QosPolicy)
+	adaptiveQosPolicy = utils.GetV(opts, "adaptiveQosPolicy", config.AdaptiveQosPolicy)
+
+	if qosPolicy != "" && adaptiveQosPolicy != "" {
+		return "", "", errors.New("a volume may not have both a QoS policy and an adaptive QoS policy")
+	}
+	if adaptiveQosPolicy != "" && !d.GetAPI().SupportsFeature(api.AdaptiveQosPolicies) {
+		return "", "", errors.New("adaptive QoS policies are not supported on this storage backend")
+	}
+	return qosPolicy, adaptiveQosPolicy, nil
+}
+
+// ensureQosPolicyGroup returns the QoS policy group or adaptive QoS policy group a new volume
+// should be placed in.  If the volume has QoS limits of its own, a policy group with those
+// limits is created for it, named after the volume, and created is true.
+func ensureQosPolicyGroup(
+	d StorageDriver, name string, opts map[string]string,
+) (qosPolicy, adaptiveQosPolicy string, created bool, err error) {
+
+	qos := utils.GetV(opts, "qos", "")
+	if qos == "" {
+		qosPolicy, adaptiveQosPolicy, err = getQosPolicies(d, opts)
+		return qosPolicy, adaptiveQosPolicy, false, err
+	}
+
+	maxThroughput, minThroughput, err := parseQosLimits(qos)
+	if err != nil {
+		return "", "", false, err
+	}
+	if minThroughput != "" && !d.GetAPI().SupportsFeature(api.QosMinimumThroughput) {
+		return "", "", false, errors.New("minimum QoS limits are not supported on this storage backend")
+	}
+
+	log.WithFields(log.Fields{
+		"policyGroup":   name,
+		"maxThroughput": maxThroughput,
+		"minThroughput": minThroughput,
+	}).Debug("Creating QoS policy group.")
+
+	createResponse, err := d.GetAPI().QosPolicyGroupCreate(name, maxThroughput, minThroughput)
+	if err = api.GetError(createResponse, err); err != nil {
+		// Handle case where the Create is passed to every Docker Swarm node
+		if zerr, ok := err.(api.ZapiError); ok && zerr.Code() == azgo.EDUPLICATEENTRY {
+			log.WithField("policyGroup", name).Warn("QoS policy group already exists, using it.")
+			return name, "", false, nil
+		}
+		return "", "", false, fmt.Errorf("error creating QoS policy group: %v", err)
+	}
+	return name, "", true, nil
+}
+
+// deleteQosPolicyGroup deletes the QoS policy group created for a volume, if there is one
+// and it is no longer in use.  A failure is only logged, since the volume is already gone.
+func deleteQosPolicyGroup(d StorageDriver, name string) {
+
+	policyGroup, err := d.GetAPI().QosPolicyGroupGet(name)
+	if err != nil {
+		log.WithField("policyGroup", name).Debugf("No QoS policy group to delete. %v", err)
+		return
+	}
+	if policyGroup.NumWorkloadsPtr != nil && policyGroup.NumWorkloads() > 0 {
+		log.WithField("policyGroup", name).Warn("QoS policy group is still in use; not deleting it.")
+		return
+	}
+
+	deleteResponse, err := d.GetAPI().QosPolicyGroupDelete(name)
+	if err = api.GetError(deleteResponse, err); err != nil {
+		log.WithField("policyGroup", name).Warnf("Could not delete QoS policy group. %v", err)
+	}
+}
+
+// getQosIOPSOffer returns the range of IOPS offered by an existing QoS policy group.  The
+// result is nil if the policy group limits throughput rather than IOPS.
+func getQosIOPSOffer(client api.Interface, qosPolicy string) (sa.Offer, error) {
+
+	policyGroup, err := client.QosPolicyGroupGet(qosPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	minIOPS, maxIOPS := 0, math.MaxInt32
+	if policyGroup.MinThroughputPtr != nil {
+		if minIOPS, err = parseQosIOPS(policyGroup.MinThroughput(), 0); err != nil {
+			return nil, nil
+		}
+	}
+	if policyGroup.MaxThroughputPtr != nil {
+		if maxIOPS, err = parseQosIOPS(policyGroup.MaxThroughput(), math.MaxInt32); err != nil {
+			return nil, nil
+		}
+	}
+	return sa.NewIntOffer(minIOPS, maxIOPS), nil
+}
+
+// parseQosIOPS parses a throughput limit of a QoS policy group as reported by ONTAP, such as
+// "5000IOPS" or "INF", into IOPS.  A limit that isn't set is reported as unset.
+func parseQosIOPS(throughput string, unset int) (int, error) {
+
+	throughput = strings.ToUpper(strings.TrimSpace(throughput))
+	switch throughput {
+	case "", "0", "INF":
+		return unset, nil
+	}
+	if !strings.HasSuffix(throughput, "IOPS") {
+		return 0, fmt.Errorf("throughput limit %s is not in IOPS", throughput)
+	}
+	return strconv.Atoi(strings.TrimSuffix(throughput, "IOPS"))
+}
+
 // EmsHeartbeat logs an ASUP message on a timer
 // view them via filer::> event log show -severity NOTICE
 func EmsHeartbeat(driver StorageDriver) {
@@ -453,7 +831,7 @@ func StartEmsHeartbeat(d StorageDriver) {
 
 // Create a volume clone
 func CreateOntapClone(
-	name, source, snapshot string, split bool, config *drivers.OntapStorageDriverConfig, client *api.Client,
+	name, source, snapshot string, split bool, config *drivers.OntapStorageDriverConfig, client api.Interface,
 ) error {
 
 	if config.DebugTraceFlags["method"] {
@@ -521,7 +899,7 @@ func CreateOntapClone(
 }
 
 // Return the list of snapshots associated with the named volume
-func GetSnapshotList(name string, config *drivers.OntapStorageDriverConfig, client *api.Client) ([]storage.Snapshot, error) {
+func GetSnapshotList(name string, config *drivers.OntapStorageDriverConfig, client api.Interface) ([]storage.Snapshot, error) {
 
 	if config.DebugTraceFlags["method"] {
 		fields := log.Fields{
@@ -558,8 +936,454 @@ func GetSnapshotList(name string, config *drivers.OntapStorageDriverConfig, clie
 	return snapshots, nil
 }
 
+// CreateSnapshot creates a snapshot of the named volume and returns its description
+func CreateSnapshot(
+	name, snapshot string, config *drivers.OntapStorageDriverConfig, client api.Interface,
+) (*storage.Snapshot, error) {
+
+	if config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":   "CreateSnapshot",
+			"Type":     "ontap_common",
+			"name":     name,
+			"snapshot": snapshot,
+		}
+		log.WithFields(fields).Debug(">>>> CreateSnapshot")
+		defer log.WithFields(fields).Debug("<<<< CreateSnapshot")
+	}
+
+	snapResponse, err := client.SnapshotCreate(snapshot, name)
+	if err = api.GetError(snapResponse, err); err != nil {
+		return nil, fmt.Errorf("error creating snapshot: %v", err)
+	}
+
+	// Read the snapshot back so we can report its creation time
+	snapshots, err := GetSnapshotList(name, config, client)
+	if err != nil {
+		return nil, err
+	}
+	for _, snap := range snapshots {
+		if snap.Name == snapshot {
+			return &snap, nil
+		}
+	}
+
+	return nil, fmt.Errorf("could not find snapshot %s after creating it in volume %s", snapshot, name)
+}
+
+// DeleteSnapshot deletes a snapshot of the named volume
+func DeleteSnapshot(name, snapshot string, config *drivers.OntapStorageDriverConfig, client api.Interface) error {
+
+	if config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":   "DeleteSnapshot",
+			"Type":     "ontap_common",
+			"name":     name,
+			"snapshot": snapshot,
+		}
+		log.WithFields(fields).Debug(">>>> DeleteSnapshot")
+		defer log.WithFields(fields).Debug("<<<< DeleteSnapshot")
+	}
+
+	snapResponse, err := client.SnapshotDelete(snapshot, name)
+	if err != nil {
+		return fmt.Errorf("error deleting snapshot: %v", err)
+	}
+	if zerr := api.NewZapiError(snapResponse); !zerr.IsPassed() {
+		if zerr.Code() == azgo.EOBJECTNOTFOUND {
+			log.WithFields(log.Fields{
+				"snapshot": snapshot,
+				"volume":   name,
+			}).Warn("Snapshot already deleted.")
+			return nil
+		}
+		return fmt.Errorf("error deleting snapshot: %v", zerr)
+	}
+
+	return nil
+}
+
+// RestoreSnapshot restores the named volume to the state captured by one of its snapshots
+func RestoreSnapshot(name, snapshot string, config *drivers.OntapStorageDriverConfig, client api.Interface) error {
+
+	if config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":   "RestoreSnapshot",
+			"Type":     "ontap_common",
+			"name":     name,
+			"snapshot": snapshot,
+		}
+		log.WithFields(fields).Debug(">>>> RestoreSnapshot")
+		defer log.WithFields(fields).Debug("<<<< RestoreSnapshot")
+	}
+
+	snapResponse, err := client.SnapshotRestoreVolume(snapshot, name)
+	if err != nil {
+		return fmt.Errorf("error restoring snapshot: %v", err)
+	}
+	if zerr := api.NewZapiError(snapResponse); !zerr.IsPassed() {
+		if zerr.Code() == azgo.EOBJECTNOTFOUND {
+			return fmt.Errorf("snapshot %s does not exist in volume %s", snapshot, name)
+		}
+		return fmt.Errorf("error restoring snapshot: %v", zerr)
+	}
+
+	return nil
+}
+
+const DefaultReplicationSchedule = "hourly"
+const ReplicationQuiesceTimeoutSecs = 120
+
+// InitializeReplicationAPI returns a client for the peer SVM to which volumes are replicated, or nil if the
+// backend doesn't replicate volumes.  The replication settings not given in the config are filled in, using the
+// first data LIF serving the specified protocol and the first aggregate assigned to the peer SVM.
+func InitializeReplicationAPI(config *drivers.OntapStorageDriverConfig, protocol string) (api.Interface, error) {
+
+	if config.DebugTraceFlags["method"] {
+		fields := log.Fields{"Method": "InitializeReplicationAPI", "Type": "ontap_common"}
+		log.WithFields(fields).Debug(">>>> InitializeReplicationAPI")
+		defer log.WithFields(fields).Debug("<<<< InitializeReplicationAPI")
+	}
+
+	if config.ReplicationSVM == "" {
+		return nil, nil
+	}
+	if config.ReplicationManagementLIF == "" {
+		config.ReplicationManagementLIF = config.ManagementLIF
+	}
+	if config.ReplicationSchedule == "" {
+		config.ReplicationSchedule = DefaultReplicationSchedule
+	}
+
+	client := newOntapAPI(config, config.ReplicationManagementLIF, config.ReplicationSVM)
+
+	if _, err := client.SystemGetOntapiVersion(); err != nil {
+		return nil, fmt.Errorf("could not reach replication SVM %s: %v", config.ReplicationSVM, err)
+	}
+
+	// Make sure the replicas have somewhere to go
+	vserverAggrs, err := client.GetVserverAggregateNames()
+	if err != nil {
+		return nil, err
+	}
+	if len(vserverAggrs) == 0 {
+		return nil, fmt.Errorf("replication SVM %s has no assigned aggregates", config.ReplicationSVM)
+	}
+	if config.ReplicationAggregate == "" {
+		config.ReplicationAggregate = vserverAggrs[0]
+	} else if !containsString(vserverAggrs, config.ReplicationAggregate) {
+		return nil, fmt.Errorf("aggregate %s does not exist or is not assigned to SVM %s",
+			config.ReplicationAggregate, config.ReplicationSVM)
+	}
+
+	// Find the LIF through which the replicas are reached after a failover
+	dataLIFs, err := client.NetInterfaceGetDataLIFs(protocol)
+	if err != nil {
+		return nil, err
+	}
+	if len(dataLIFs) == 0 {
+		return nil, fmt.Errorf("no %s data LIFs found on replication SVM %s", protocol, config.ReplicationSVM)
+	}
+	if config.ReplicationDataLIF == "" {
+		config.ReplicationDataLIF = dataLIFs[0]
+	} else if !containsString(dataLIFs, config.ReplicationDataLIF) {
+		return nil, fmt.Errorf("could not find %s data LIF %s on replication SVM %s",
+			protocol, config.ReplicationDataLIF, config.ReplicationSVM)
+	}
+
+	log.WithFields(log.Fields{
+		"ReplicationSVM":       config.ReplicationSVM,
+		"ReplicationDataLIF":   config.ReplicationDataLIF,
+		"ReplicationAggregate": config.ReplicationAggregate,
+		"ReplicationSchedule":  config.ReplicationSchedule,
+		"ReplicationPolicy":    config.ReplicationPolicy,
+	}).Debug("Volumes may be replicated.")
+
+	return client, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// replicationLocations returns the SnapMirror locations of the named volume and of its replica.
+func replicationLocations(name string, config *drivers.OntapStorageDriverConfig) (source, destination string) {
+	return config.SVM + ":" + name, config.ReplicationSVM + ":" + name
+}
+
+// CreateReplica creates a data protection volume of the same name on the replication SVM and starts mirroring
+// the named volume to it.  If mirroring can't be started, the replica is destroyed again.
+func CreateReplica(
+	name, size, exportPolicy string, config *drivers.OntapStorageDriverConfig, peer api.Interface,
+) error {
+
+	if config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "CreateReplica",
+			"Type":   "ontap_common",
+			"name":   name,
+		}
+		log.WithFields(fields).Debug(">>>> CreateReplica")
+		defer log.WithFields(fields).Debug("<<<< CreateReplica")
+	}
+
+	if peer == nil {
+		return errors.New("replication is not configured for this backend")
+	}
+
+	source, destination := replicationLocations(name, config)
+
+	log.WithFields(log.Fields{
+		"source":      source,
+		"destination": destination,
+		"aggregate":   config.ReplicationAggregate,
+		"schedule":    config.ReplicationSchedule,
+	}).Debug("Creating replica.")
+
+	volCreateResponse, err := peer.VolumeCreateDP(name, config.ReplicationAggregate, size, exportPolicy)
+	if err = api.GetError(volCreateResponse, err); err != nil {
+		return fmt.Errorf("error creating replica volume: %v", err)
+	}
+
+	createResponse, err := peer.SnapmirrorCreate(source, destination, config.ReplicationSchedule,
+		config.ReplicationPolicy)
+	if err = api.GetError(createResponse, err); err != nil {
+		destroyReplicaVolume(name, config, peer)
+		return fmt.Errorf("error creating SnapMirror relationship: %v", err)
+	}
+
+	initResponse, err := peer.SnapmirrorInitialize(source, destination)
+	if err = api.GetError(initResponse, err); err != nil {
+		deleteResponse, deleteErr := peer.SnapmirrorDelete(source, destination)
+		if deleteErr = api.GetError(deleteResponse, deleteErr); deleteErr != nil {
+			log.WithField("destination", destination).Warnf("Could not delete SnapMirror relationship. %v",
+				deleteErr)
+		}
+		destroyReplicaVolume(name, config, peer)
+		return fmt.Errorf("error initializing SnapMirror relationship: %v", err)
+	}
+
+	return nil
+}
+
+// ReleaseReplica releases the replication relationship of the named volume on the volume's own SVM, so that
+// the volume may be destroyed, and returns whether the volume is replicated.  The relationship itself and the
+// replica are left for DestroyReplica.
+func ReleaseReplica(
+	name string, config *drivers.OntapStorageDriverConfig, client, peer api.Interface,
+) (bool, error) {
+
+	if config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "ReleaseReplica",
+			"Type":   "ontap_common",
+			"name":   name,
+		}
+		log.WithFields(fields).Debug(">>>> ReleaseReplica")
+		defer log.WithFields(fields).Debug("<<<< ReleaseReplica")
+	}
+
+	if peer == nil {
+		return false, nil
+	}
+
+	source, destination := replicationLocations(name, config)
+
+	relationship, err := peer.SnapmirrorGet(destination)
+	if err != nil {
+		return false, fmt.Errorf("error checking for SnapMirror relationship: %v", err)
+	}
+	if relationship == nil {
+		log.WithField("volume", name).Debug("Volume is not replicated.")
+		return false, nil
+	}
+
+	// The source only needs to forget the relationship, so don't fail if it can't be reached
+	releaseResponse, err := client.SnapmirrorRelease(source, destination)
+	if err = api.GetError(releaseResponse, err); err != nil {
+		log.WithField("destination", destination).Warnf("Could not release SnapMirror relationship. %v", err)
+	}
+
+	return true, nil
+}
+
+// DestroyReplica removes the replication relationship of the named volume along with its replica.  It must only
+// be called once the volume itself is destroyed, since a replica that was failed over to holds the only live
+// copy of the volume.
+func DestroyReplica(name string, config *drivers.OntapStorageDriverConfig, peer api.Interface) error {
+
+	if config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "DestroyReplica",
+			"Type":   "ontap_common",
+			"name":   name,
+		}
+		log.WithFields(fields).Debug(">>>> DestroyReplica")
+		defer log.WithFields(fields).Debug("<<<< DestroyReplica")
+	}
+
+	source, destination := replicationLocations(name, config)
+
+	deleteResponse, err := peer.SnapmirrorDelete(source, destination)
+	if err = api.GetError(deleteResponse, err); err != nil {
+		return fmt.Errorf("error deleting SnapMirror relationship: %v", err)
+	}
+
+	return destroyReplicaVolume(name, config, peer)
+}
+
+// destroyReplicaVolume destroys the replica of the named volume.
+func destroyReplicaVolume(name string, config *drivers.OntapStorageDriverConfig, peer api.Interface) error {
+
+	volDestroyResponse, err := peer.VolumeDestroy(name, true)
+	if err != nil {
+		return fmt.Errorf("error destroying replica volume %v: %v", name, err)
+	}
+	if zerr := api.NewZapiError(volDestroyResponse); !zerr.IsPassed() {
+		if zerr.Code() == azgo.EVOLUMEDOESNOTEXIST {
+			log.WithField("volume", name).Warn("Replica volume already deleted.")
+		} else {
+			log.WithFields(log.Fields{
+				"volume": name,
+				"SVM":    config.ReplicationSVM,
+			}).Errorf("Could not destroy replica volume; it must be deleted manually. %v", zerr)
+			return fmt.Errorf("error destroying replica volume %v: %v", name, zerr)
+		}
+	}
+
+	return nil
+}
+
+// GetReplicationStatus returns the state of the relationship that replicates the named volume, or nil if the
+// volume isn't replicated.
+func GetReplicationStatus(
+	name string, config *drivers.OntapStorageDriverConfig, peer api.Interface,
+) (*storage.ReplicationStatus, error) {
+
+	if config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "GetReplicationStatus",
+			"Type":   "ontap_common",
+			"name":   name,
+		}
+		log.WithFields(fields).Debug(">>>> GetReplicationStatus")
+		defer log.WithFields(fields).Debug("<<<< GetReplicationStatus")
+	}
+
+	if peer == nil {
+		return nil, nil
+	}
+
+	source, destination := replicationLocations(name, config)
+
+	relationship, err := peer.SnapmirrorGet(destination)
+	if err != nil {
+		return nil, fmt.Errorf("error getting SnapMirror relationship: %v", err)
+	}
+	if relationship == nil {
+		return nil, nil
+	}
+
+	status := &storage.ReplicationStatus{
+		Source:      source,
+		Destination: destination,
+	}
+	if relationship.MirrorStatePtr != nil {
+		status.State = relationship.MirrorState()
+	}
+	if relationship.RelationshipStatusPtr != nil {
+		status.Status = relationship.RelationshipStatus()
+	}
+	if relationship.SchedulePtr != nil {
+		status.Schedule = relationship.Schedule()
+	}
+	if relationship.IsHealthyPtr != nil {
+		status.Healthy = relationship.IsHealthy()
+	}
+	if relationship.UnhealthyReasonPtr != nil {
+		status.UnhealthyReason = relationship.UnhealthyReason()
+	}
+	if relationship.LagTimePtr != nil {
+		status.LagTime = relationship.LagTime()
+	}
+
+	return status, nil
+}
+
+// BreakReplica stops the replication of the named volume and makes its replica writable.  Any transfer in
+// progress is allowed to finish first.  Breaking a relationship that is already broken does nothing.
+func BreakReplica(name string, config *drivers.OntapStorageDriverConfig, peer api.Interface) error {
+
+	if config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "BreakReplica",
+			"Type":   "ontap_common",
+			"name":   name,
+		}
+		log.WithFields(fields).Debug(">>>> BreakReplica")
+		defer log.WithFields(fields).Debug("<<<< BreakReplica")
+	}
+
+	if peer == nil {
+		return errors.New("replication is not configured for this backend")
+	}
+
+	_, destination := replicationLocations(name, config)
+
+	relationship, err := peer.SnapmirrorGet(destination)
+	if err != nil {
+		return fmt.Errorf("error getting SnapMirror relationship: %v", err)
+	}
+	if relationship == nil {
+		return fmt.Errorf("volume %s is not replicated", name)
+	}
+	if relationship.MirrorStatePtr != nil && relationship.MirrorState() == "broken-off" {
+		log.WithField("destination", destination).Debug("SnapMirror relationship already broken.")
+		return nil
+	}
+
+	quiesceResponse, err := peer.SnapmirrorQuiesce(destination)
+	if err = api.GetError(quiesceResponse, err); err != nil {
+		return fmt.Errorf("error quiescing SnapMirror relationship: %v", err)
+	}
+
+	// Wait for any transfer in progress to finish
+	timeout := time.Now().Add(ReplicationQuiesceTimeoutSecs * time.Second)
+	for {
+		relationship, err = peer.SnapmirrorGet(destination)
+		if err != nil {
+			return fmt.Errorf("error getting SnapMirror relationship: %v", err)
+		}
+		if relationship == nil {
+			return fmt.Errorf("SnapMirror relationship for %s disappeared", destination)
+		}
+		if relationship.RelationshipStatusPtr != nil && relationship.RelationshipStatus() == "quiesced" {
+			break
+		}
+		if time.Now().After(timeout) {
+			return fmt.Errorf("SnapMirror relationship for %s was not quiesced after %d seconds",
+				destination, ReplicationQuiesceTimeoutSecs)
+		}
+		log.WithField("destination", destination).Debug("SnapMirror relationship not yet quiesced, polling...")
+		time.Sleep(1 * time.Second)
+	}
+
+	breakResponse, err := peer.SnapmirrorBreak(destination)
+	if err = api.GetError(breakResponse, err); err != nil {
+		return fmt.Errorf("error breaking SnapMirror relationship: %v", err)
+	}
+
+	log.WithField("destination", destination).Info("Broke SnapMirror relationship.")
+	return nil
+}
+
 // Return the list of volumes associated with the tenant
-func GetVolumeList(client *api.Client, config *drivers.OntapStorageDriverConfig) ([]string, error) {
+func GetVolumeList(client api.Interface, config *drivers.OntapStorageDriverConfig) ([]string, error) {
 
 	if config.DebugTraceFlags["method"] {
 		fields := log.Fields{"Method": "GetVolumeList", "Type": "ontap_common"}
@@ -588,7 +1412,7 @@ func GetVolumeList(client *api.Client, config *drivers.OntapStorageDriverConfig)
 
 // GetVolume checks for the existence of a volume.  It returns nil if the volume
 // exists and an error if it does not (or the API call fails).
-func GetVolume(name string, client *api.Client, config *drivers.OntapStorageDriverConfig) error {
+func GetVolume(name string, client api.Interface, config *drivers.OntapStorageDriverConfig) error {
 
 	if config.DebugTraceFlags["method"] {
 		fields := log.Fields{"Method": "GetVolume", "Type": "ontap_common"}
@@ -608,8 +1432,9 @@ func GetVolume(name string, client *api.Client, config *drivers.OntapStorageDriv
 	return nil
 }
 
-// MountVolume accepts the mount info for an NFS share and mounts it on the local host.
-func MountVolume(exportPath, mountpoint string, config *drivers.OntapStorageDriverConfig) error {
+// MountVolume accepts the mount info for an NFS share and mounts it on the local host,
+// read-only if readOnly is set.
+func MountVolume(exportPath, mountpoint string, config *drivers.OntapStorageDriverConfig, readOnly bool) error {
 
 	if config.DebugTraceFlags["method"] {
 		fields := log.Fields{
@@ -617,6 +1442,7 @@ func MountVolume(exportPath, mountpoint string, config *drivers.OntapStorageDriv
 			"Type":       "ontap_common",
 			"exportPath": exportPath,
 			"mountpoint": mountpoint,
+			"readOnly":   readOnly,
 		}
 		log.WithFields(fields).Debug(">>>> MountVolume")
 		defer log.WithFields(fields).Debug("<<<< MountVolume")
@@ -628,9 +1454,16 @@ func MountVolume(exportPath, mountpoint string, config *drivers.OntapStorageDriv
 	var cmd string
 	switch runtime.GOOS {
 	case utils.Linux:
+		if readOnly {
+			nfsMountOptions = strings.TrimSpace("-r " + nfsMountOptions)
+		}
 		cmd = fmt.Sprintf("mount -v %s %s %s", nfsMountOptions, exportPath, mountpoint)
 	case utils.Darwin:
-		cmd = fmt.Sprintf("mount -v -o rw %s -t nfs %s %s", nfsMountOptions, exportPath, mountpoint)
+		access := "rw"
+		if readOnly {
+			access = "ro"
+		}
+		cmd = fmt.Sprintf("mount -v -o %s %s -t nfs %s %s", access, nfsMountOptions, exportPath, mountpoint)
 	default:
 		return fmt.Errorf("unsupported operating system: %v", runtime.GOOS)
 	}
@@ -671,7 +1504,7 @@ func UnmountVolume(mountpoint string, config *drivers.OntapStorageDriverConfig)
 
 // UpdateLoadSharingMirrors checks for the present of LS mirrors on the SVM root volume, and if
 // present, starts an update and waits for them to become idle.
-func UpdateLoadSharingMirrors(client *api.Client) {
+func UpdateLoadSharingMirrors(client api.Interface) {
 
 	// We care about LS mirrors on the SVM root volume, so get the root volume name
 	rootVolumeResponse, err := client.VolumeGetRootName()
@@ -826,6 +1659,56 @@ func getStorageBackendSpecsCommon(
 			" not match pools on this backend: %v.", aggrErr)
 	}
 
+	// Offer virtual pools instead of the aggregates, if the config defines any
+	if len(config.Storage) > 0 {
+		if storagePools, err = getVirtualPools(config, backend, storagePools); err != nil {
+			return
+		}
+	} else if driverName == drivers.OntapNASFlexGroupStorageDriverName {
+		// A FlexGroup spans all of the SVM's aggregates, so the SVM is offered as a single pool
+		var pool *storage.Pool
+		if pool, err = newAggregatesPool(backend, config.SVM, vserverAggrs, storagePools, true); err != nil {
+			return
+		}
+		storagePools = map[string]*storage.Pool{pool.Name: pool}
+	}
+
+	// Offer the IOPS each pool's volumes may be given
+	iopsOffers := make(map[string]sa.Offer)
+	for _, pool := range storagePools {
+		qosPolicy, adaptiveQosPolicy := getPoolQosPolicies(config, pool.Name)
+		switch {
+		case adaptiveQosPolicy != "":
+			// The IOPS of an adaptive QoS policy depend on the size of each volume
+			continue
+		case qosPolicy != "":
+			offer, ok := iopsOffers[qosPolicy]
+			if !ok {
+				if offer, err = getQosIOPSOffer(client, qosPolicy); err != nil {
+					log.WithFields(log.Fields{
+						"pool":      pool.Name,
+						"qosPolicy": qosPolicy,
+					}).Warnf("Could not read QoS policy group; storage classes requesting IOPS will not "+
+						"match this pool. %v", err)
+					err = nil
+				}
+				iopsOffers[qosPolicy] = offer
+			}
+			if offer != nil {
+				pool.Attributes[sa.IOPS] = offer
+			}
+		case driverName == drivers.OntapNASQtreeStorageDriverName,
+			driverName == drivers.OntapSANEconomyStorageDriverName:
+			// Volumes share their Flexvols, so they can't be given IOPS of their own
+			continue
+		case driverName == drivers.OntapNASFlexGroupStorageDriverName:
+			// FlexGroups aren't given QoS policy groups of their own
+			continue
+		default:
+			pool.Attributes[sa.IOPS] = sa.NewIntOffer(0, math.MaxInt32)
+		}
+	}
+
 	// Add attributes common to each pool and register pools with backend
 	for _, pool := range storagePools {
 
@@ -839,6 +1722,144 @@ func getStorageBackendSpecsCommon(
 	return
 }
 
+// getVirtualPools defines a storage pool for each virtual pool in the config,
+// given the pools for the aggregates it may use.
+func getVirtualPools(
+	config *drivers.OntapStorageDriverConfig, backend *storage.Backend, aggrPools map[string]*storage.Pool,
+) (map[string]*storage.Pool, error) {
+
+	// A FlexGroup spans all of its pool's aggregates
+	spanned := config.StorageDriverName == drivers.OntapNASFlexGroupStorageDriverName
+
+	virtualPools := make(map[string]*storage.Pool)
+	for _, virtualPool := range config.Storage {
+
+		aggregates := virtualPool.Aggregates
+		if len(aggregates) == 0 {
+			for aggrName := range aggrPools {
+				aggregates = append(aggregates, aggrName)
+			}
+		}
+
+		pool, err := newAggregatesPool(backend, virtualPool.Name, aggregates, aggrPools, spanned)
+		if err != nil {
+			return nil, err
+		}
+		for label, value := range virtualPool.Labels {
+			pool.Attributes[sa.LabelAttribute(label)] = sa.NewStringOffer(value)
+		}
+
+		log.WithFields(log.Fields{
+			"pool":       virtualPool.Name,
+			"aggregates": aggregates,
+			"labels":     virtualPool.Labels,
+		}).Debug("Defined virtual pool.")
+
+		virtualPools[virtualPool.Name] = pool
+	}
+
+	return virtualPools, nil
+}
+
+// newAggregatesPool defines a storage pool that uses several aggregates,
+// given the pools for those aggregates.  The pool offers the media of all of
+// its aggregates.  If its volumes span the aggregates, as FlexGroups do, the
+// pool offers the space available in all of them; otherwise, as each volume
+// is placed in a single aggregate, it offers the most space available in any
+// one of them.
+func newAggregatesPool(
+	backend *storage.Backend, name string, aggregates []string, aggrPools map[string]*storage.Pool, spanned bool,
+) (*storage.Pool, error) {
+
+	pool := storage.NewStoragePool(backend, name)
+	media := make(map[string]bool)
+	for _, aggrName := range aggregates {
+		aggrPool, ok := aggrPools[aggrName]
+		if !ok {
+			return nil, fmt.Errorf("pool %s uses aggregate %s, which isn't available to this backend",
+				name, aggrName)
+		}
+		if offer, ok := aggrPool.Attributes[sa.Media]; ok {
+			for _, aggrMedia := range []string{sa.HDD, sa.Hybrid, sa.SSD} {
+				if offer.Matches(sa.NewStringRequest(aggrMedia)) {
+					media[aggrMedia] = true
+				}
+			}
+		}
+		if aggrPool.Capacity == nil {
+			continue
+		}
+		if spanned {
+			if pool.Capacity == nil {
+				pool.Capacity = &storage.PoolCapacity{}
+			}
+			pool.Capacity.AvailableBytes += aggrPool.Capacity.AvailableBytes
+			pool.Capacity.TotalBytes += aggrPool.Capacity.TotalBytes
+		} else if pool.Capacity == nil || aggrPool.Capacity.AvailableBytes > pool.Capacity.AvailableBytes {
+			pool.Capacity = &storage.PoolCapacity{AvailableBytes: aggrPool.Capacity.AvailableBytes}
+		}
+	}
+	if len(media) > 0 {
+		mediaOffers := make([]string, 0, len(media))
+		for _, aggrMedia := range []string{sa.HDD, sa.Hybrid, sa.SSD} {
+			if media[aggrMedia] {
+				mediaOffers = append(mediaOffers, aggrMedia)
+			}
+		}
+		pool.Attributes[sa.Media] = sa.NewStringOffer(mediaOffers...)
+	}
+
+	return pool, nil
+}
+
+// selectAggregate returns the aggregate of a virtual pool with the most space
+// available.  If the space can't be read, the first aggregate is returned.
+func selectAggregate(d StorageDriver, virtualPool *drivers.OntapStorageDriverPool) (string, error) {
+
+	aggregates := virtualPool.Aggregates
+	if len(aggregates) == 0 {
+		if d.GetConfig().Aggregate != "" {
+			return d.GetConfig().Aggregate, nil
+		}
+		var err error
+		if aggregates, err = d.GetAPI().GetVserverAggregateNames(); err != nil {
+			return "", fmt.Errorf("could not read the aggregates of virtual pool %s: %v", virtualPool.Name, err)
+		}
+		if len(aggregates) == 0 {
+			return "", fmt.Errorf("SVM %s has no assigned aggregates", d.GetConfig().SVM)
+		}
+	}
+
+	aggrPools := make(map[string]*storage.Pool, len(aggregates))
+	for _, aggrName := range aggregates {
+		aggrPools[aggrName] = storage.NewStoragePool(nil, aggrName)
+	}
+	var err error
+	if d.GetAPI().SupportsFeature(api.VServerShowAggr) {
+		err = getVserverAggregateAttributes(d, &aggrPools)
+	} else {
+		err = getClusterAggregateAttributes(d, &aggrPools)
+	}
+	if err != nil {
+		log.WithFields(log.Fields{
+			"pool":      virtualPool.Name,
+			"aggregate": aggregates[0],
+		}).Warnf("Could not read the space in the aggregates of the virtual pool; using the first one: %v", err)
+		return aggregates[0], nil
+	}
+
+	selected := aggregates[0]
+	var mostAvailable uint64
+	for _, aggrName := range aggregates {
+		capacity := aggrPools[aggrName].Capacity
+		if capacity != nil && capacity.AvailableBytes > mostAvailable {
+			selected = aggrName
+			mostAvailable = capacity.AvailableBytes
+		}
+	}
+	return selected, nil
+}
+
 // getVserverAggregateAttributes gets pool attributes using vserver-show-aggr-get-iter, which will only succeed on Data ONTAP 9 and later.
 // If the aggregate attributes are read successfully, the pools passed to this function are updated accordingly.
 func getVserverAggregateAttributes(d StorageDriver, storagePools *map[string]*storage.Pool) error {
@@ -861,6 +1882,11 @@ func getVserverAggregateAttributes(d StorageDriver, storagePools *map[string]*st
 			continue
 		}
 
+		// Record the space available in the aggregate
+		if aggr.AvailableSizePtr != nil {
+			pool.Capacity = &storage.PoolCapacity{AvailableBytes: uint64(aggr.AvailableSize())}
+		}
+
 		// Get the storage attributes (i.e. MediaType) corresponding to the aggregate type
 		storageAttrs, ok := ontapPerformanceClasses[ontapPerformanceClass(aggrType)]
 		if !ok {
@@ -910,6 +1936,15 @@ func getClusterAggregateAttributes(d StorageDriver, storagePools *map[string]*st
 			continue
 		}
 
+		// Record the space in the aggregate
+		if aggrSpaceAttrs := aggr.AggrSpaceAttributesPtr; aggrSpaceAttrs != nil &&
+			aggrSpaceAttrs.SizeAvailablePtr != nil {
+			pool.Capacity = &storage.PoolCapacity{AvailableBytes: uint64(aggrSpaceAttrs.SizeAvailable())}
+			if aggrSpaceAttrs.SizeTotalPtr != nil {
+				pool.Capacity.TotalBytes = uint64(aggrSpaceAttrs.SizeTotal())
+			}
+		}
+
 		// Get the storage attributes (i.e. MediaType) corresponding to the aggregate type
 		storageAttrs, ok := ontapPerformanceClasses[ontapPerformanceClass(aggrType)]
 		if !ok {
@@ -935,17 +1970,46 @@ func getClusterAggregateAttributes(d StorageDriver, storagePools *map[string]*st
 	return nil
 }
 
+// getVolumeOptsCommon returns the options for creating a volume in a pool.  A
+// virtual pool's defaults come first, so that the storage class requests and
+// the volume config may override them.
 func getVolumeOptsCommon(
+	d StorageDriver,
 	volConfig *storage.VolumeConfig,
 	pool *storage.Pool,
 	requests map[string]sa.Request,
-) map[string]string {
+) (map[string]string, error) {
 	opts := make(map[string]string)
 	if pool != nil {
-		opts["aggregate"] = pool.Name
+		flexGroup := d.Name() == drivers.OntapNASFlexGroupStorageDriverName
+		if virtualPool := getVirtualPool(d.GetConfig(), pool.Name); virtualPool != nil {
+			if flexGroup {
+				// A FlexGroup spans all of its pool's aggregates, or all of the SVM's if none are listed
+				opts["aggregate"] = strings.Join(virtualPool.Aggregates, ",")
+			} else {
+				aggregate, err := selectAggregate(d, virtualPool)
+				if err != nil {
+					return nil, err
+				}
+				opts["aggregate"] = aggregate
+			}
+			opts["spaceReserve"] = virtualPool.SpaceReserve
+			opts["snapshotPolicy"] = virtualPool.SnapshotPolicy
+			opts["unixPermissions"] = virtualPool.UnixPermissions
+			opts["snapshotDir"] = virtualPool.SnapshotDir
+			opts["exportPolicy"] = virtualPool.ExportPolicy
+			opts["securityStyle"] = virtualPool.SecurityStyle
+			opts["splitOnClone"] = virtualPool.SplitOnClone
+			opts["fileSystemType"] = virtualPool.FileSystemType
+			opts["encryption"] = virtualPool.Encryption
+			opts["qosPolicy"] = virtualPool.QosPolicy
+			opts["adaptiveQosPolicy"] = virtualPool.AdaptiveQosPolicy
+		} else if !flexGroup {
+			opts["aggregate"] = pool.Name
+		}
 	}
 	if provisioningTypeReq, ok := requests[sa.ProvisioningType]; ok {
-		if p, ok := provisioningTypeReq.Value().(string); ok {
+		if p, ok := sa.ResolveStringRequest(provisioningTypeReq, "thick", "thin"); ok {
 			if p == "thin" {
 				opts["spaceReserve"] = "none"
 			} else if p == "thick" {
@@ -963,8 +2027,8 @@ func getVolumeOptsCommon(
 			log.WithFields(log.Fields{
 				"provisioner":      "ONTAP",
 				"method":           "getVolumeOptsCommon",
-				"provisioningType": provisioningTypeReq.Value(),
-			}).Warnf("Expected string for %s; ignoring.", sa.ProvisioningType)
+				"provisioningType": provisioningTypeReq.String(),
+			}).Warnf("Expected a single value for %s; ignoring.", sa.ProvisioningType)
 		}
 	}
 	if encryptionReq, ok := requests[sa.Encryption]; ok {
@@ -980,6 +2044,45 @@ func getVolumeOptsCommon(
 			}).Warnf("Expected bool for %s; ignoring.", sa.Encryption)
 		}
 	}
+	if replicationReq, ok := requests[sa.Replication]; ok {
+		if replication, ok := replicationReq.Value().(bool); ok {
+			if replication {
+				opts["replication"] = "true"
+			}
+		} else {
+			log.WithFields(log.Fields{
+				"provisioner": "ONTAP",
+				"method":      "getVolumeOptsCommon",
+				"replication": replicationReq.Value(),
+			}).Warnf("Expected bool for %s; ignoring.", sa.Replication)
+		}
+	}
+	if iopsReq, ok := requests[sa.IOPS]; ok && pool != nil {
+		// A pool whose volumes share a QoS policy only matches the IOPS it offers,
+		// so a volume needs QoS limits of its own only in other pools.
+		if qosPolicy, adaptiveQosPolicy := getPoolQosPolicies(d.GetConfig(), pool.Name); qosPolicy == "" &&
+			adaptiveQosPolicy == "" {
+			if floor, ceiling, ok := sa.IntRequestLimits(iopsReq); !ok {
+				log.WithFields(log.Fields{
+					"provisioner": "ONTAP",
+					"method":      "getVolumeOptsCommon",
+					"iops":        iopsReq.String(),
+				}).Warnf("Expected a bounded request for %s; ignoring.", sa.IOPS)
+			} else {
+				// An exact request is a limit; a range guarantees its floor and limits the volume to its ceiling.
+				limits := make([]string, 0, 2)
+				if ceiling > 0 {
+					limits = append(limits, fmt.Sprintf("%s=%d", qosMaxIOPS, ceiling))
+				}
+				if floor > 0 && floor != ceiling {
+					limits = append(limits, fmt.Sprintf("%s=%d", qosMinIOPS, floor))
+				}
+				if len(limits) > 0 {
+					opts["qos"] = strings.Join(limits, ",")
+				}
+			}
+		}
+	}
 	if volConfig.SnapshotPolicy != "" {
 		opts["snapshotPolicy"] = volConfig.SnapshotPolicy
 	}
@@ -1007,8 +2110,16 @@ func getVolumeOptsCommon(
 	if volConfig.Encryption != "" {
 		opts["encryption"] = volConfig.Encryption
 	}
+	if volConfig.QoSType != "" {
+		opts["qosPolicy"] = volConfig.QoSType
+		opts["adaptiveQosPolicy"] = ""
+		delete(opts, "qos")
+	}
+	if volConfig.QoS != "" {
+		opts["qos"] = volConfig.QoS
+	}
 
-	return opts
+	return opts, nil
 }
 
 func getInternalVolumeNameCommon(commonConfig *drivers.CommonStorageDriverConfig, name string) string {
@@ -1044,17 +2155,21 @@ func getExternalConfig(config drivers.OntapStorageDriverConfig) interface{} {
 
 	return &struct {
 		*drivers.CommonStorageDriverConfigExternal
-		ManagementLIF string `json:"managementLIF"`
-		DataLIF       string `json:"dataLIF"`
-		IgroupName    string `json:"igroupName"`
-		SVM           string `json:"svm"`
+		ManagementLIF  string                           `json:"managementLIF"`
+		DataLIF        string                           `json:"dataLIF"`
+		IgroupName     string                           `json:"igroupName"`
+		SVM            string                           `json:"svm"`
+		ReplicationSVM string                           `json:"replicationSVM,omitempty"`
+		Storage        []drivers.OntapStorageDriverPool `json:"storage,omitempty"`
 	}{
 		CommonStorageDriverConfigExternal: drivers.GetCommonStorageDriverConfigExternal(
 			config.CommonStorageDriverConfig,
 		),
-		ManagementLIF: config.ManagementLIF,
-		DataLIF:       config.DataLIF,
-		IgroupName:    config.IgroupName,
-		SVM:           config.SVM,
+		ManagementLIF:  config.ManagementLIF,
+		DataLIF:        config.DataLIF,
+		IgroupName:     config.IgroupName,
+		SVM:            config.SVM,
+		ReplicationSVM: config.ReplicationSVM,
+		Storage:        config.Storage,
 	}
 }
@@ -0,0 +1,311 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package ontap
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/netapp/trident/storage"
+	sa "github.com/netapp/trident/storage_attribute"
+	drivers "github.com/netapp/trident/storage_drivers"
+)
+
+func TestGetVolumeOptsCommonIOPS(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		request  sa.Request
+		expected string
+	}{
+		{"exact", sa.NewIntRequest(5000), "maxIOPS=5000"},
+		{"at least", sa.NewIntMinRequest(1000), "minIOPS=1000"},
+		{"at most", sa.NewIntMaxRequest(5000), "maxIOPS=5000"},
+		{"range", sa.NewIntRangeRequest(1000, 5000), "maxIOPS=5000,minIOPS=1000"},
+		{"unbounded", sa.NewIntNotRequest(5000), ""},
+	} {
+		d := &NASStorageDriver{}
+		requests := map[string]sa.Request{sa.IOPS: test.request}
+
+		opts, err := getVolumeOptsCommon(d, &storage.VolumeConfig{}, &storage.Pool{Name: "aggr1"}, requests)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+		} else if opts["qos"] != test.expected {
+			t.Errorf("%s: expected QoS %q, got %q", test.name, test.expected, opts["qos"])
+		}
+	}
+}
+
+func TestParseQosLimits(t *testing.T) {
+	for _, test := range []struct {
+		qos           string
+		maxThroughput string
+		minThroughput string
+		errored       bool
+	}{
+		{"maxIOPS=5000", "5000iops", "", false},
+		{"minIOPS=1000", "", "1000iops", false},
+		{"maxIOPS=5000,minIOPS=1000", "5000iops", "1000iops", false},
+		{" maxThroughput = 200 , minThroughput = 100 ", "200MB/s", "100MB/s", false},
+		{"maxIOPS=5000,minThroughput=100", "5000iops", "100MB/s", false},
+		{"maxIOPS=5000,maxThroughput=200", "", "", true},
+		{"minIOPS=1000,minThroughput=100", "", "", true},
+		{"maxIOPS=1000,minIOPS=5000", "", "", true},
+		{"maxThroughput=100,minThroughput=200", "", "", true},
+		{"maxIOPS=0", "", "", true},
+		{"maxIOPS=lots", "", "", true},
+		{"burstIOPS=5000", "", "", true},
+		{"1000,5000", "5000iops", "1000iops", false},
+		{"1000,5000,8000", "5000iops", "1000iops", false},
+		{"0,5000,0", "5000iops", "", false},
+		{"1000,0", "", "1000iops", false},
+		{"0,0,8000", "", "", true},
+		{"5000,1000", "", "", true},
+		{"5000", "", "", true},
+		{"1000,5000,8000,0", "", "", true},
+		{"1000,-1", "", "", true},
+	} {
+		maxThroughput, minThroughput, err := parseQosLimits(test.qos)
+		if (err != nil) != test.errored {
+			t.Errorf("%s: expected error %v, got %v", test.qos, test.errored, err)
+		}
+		if maxThroughput != test.maxThroughput || minThroughput != test.minThroughput {
+			t.Errorf("%s: expected %q and %q, got %q and %q", test.qos,
+				test.maxThroughput, test.minThroughput, maxThroughput, minThroughput)
+		}
+	}
+}
+
+func TestParseQosIOPS(t *testing.T) {
+	for _, test := range []struct {
+		throughput string
+		expected   int
+		errored    bool
+	}{
+		{"", -1, false},
+		{"0", -1, false},
+		{"INF", -1, false},
+		{"5000IOPS", 5000, false},
+		{" 5000iops ", 5000, false},
+		{"100MB/S", 0, true},
+		{"lotsIOPS", 0, true},
+	} {
+		iops, err := parseQosIOPS(test.throughput, -1)
+		if (err != nil) != test.errored {
+			t.Errorf("%q: expected error %v, got %v", test.throughput, test.errored, err)
+		} else if !test.errored && iops != test.expected {
+			t.Errorf("%q: expected %d, got %d", test.throughput, test.expected, iops)
+		}
+	}
+}
+
+func TestPopulateVirtualPoolDefaults(t *testing.T) {
+	newConfig := func(pools ...drivers.OntapStorageDriverPool) *drivers.OntapStorageDriverConfig {
+		config := &drivers.OntapStorageDriverConfig{Storage: pools}
+		config.OntapStorageDriverConfigDefaults = drivers.OntapStorageDriverConfigDefaults{
+			SpaceReserve:    "none",
+			SnapshotPolicy:  "none",
+			UnixPermissions: "---rwxrwxrwx",
+			SnapshotDir:     "false",
+			ExportPolicy:    "default",
+			SecurityStyle:   "unix",
+			SplitOnClone:    "false",
+			FileSystemType:  "ext4",
+			Encryption:      "false",
+			QosPolicy:       "silver",
+		}
+		return config
+	}
+
+	config := newConfig(
+		drivers.OntapStorageDriverPool{Name: "inherited"},
+		drivers.OntapStorageDriverPool{
+			Name: "overridden",
+			OntapStorageDriverConfigDefaults: drivers.OntapStorageDriverConfigDefaults{
+				SpaceReserve:      "volume",
+				SnapshotPolicy:    "default",
+				SplitOnClone:      "true",
+				Encryption:        "true",
+				AdaptiveQosPolicy: "gold",
+			},
+		},
+	)
+	if err := populateVirtualPoolDefaults(config); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if inherited := config.Storage[0].OntapStorageDriverConfigDefaults; !reflect.DeepEqual(inherited,
+		config.OntapStorageDriverConfigDefaults) {
+		t.Errorf("expected the backend defaults %+v, got %+v", config.OntapStorageDriverConfigDefaults, inherited)
+	}
+
+	expected := config.OntapStorageDriverConfigDefaults
+	expected.SpaceReserve = "volume"
+	expected.SnapshotPolicy = "default"
+	expected.SplitOnClone = "true"
+	expected.Encryption = "true"
+	expected.QosPolicy = ""
+	expected.AdaptiveQosPolicy = "gold"
+	if overridden := config.Storage[1].OntapStorageDriverConfigDefaults; !reflect.DeepEqual(overridden, expected) {
+		t.Errorf("expected %+v, got %+v", expected, overridden)
+	}
+
+	for _, test := range []struct {
+		name  string
+		pools []drivers.OntapStorageDriverPool
+	}{
+		{"no name", []drivers.OntapStorageDriverPool{{}}},
+		{"duplicate name", []drivers.OntapStorageDriverPool{{Name: "gold"}, {Name: "gold"}}},
+		{"empty label", []drivers.OntapStorageDriverPool{{Name: "gold", Labels: map[string]string{"tier": ""}}}},
+		{"invalid splitOnClone", []drivers.OntapStorageDriverPool{{Name: "gold",
+			OntapStorageDriverConfigDefaults: drivers.OntapStorageDriverConfigDefaults{SplitOnClone: "maybe"}}}},
+		{"both QoS policies", []drivers.OntapStorageDriverPool{{Name: "gold",
+			OntapStorageDriverConfigDefaults: drivers.OntapStorageDriverConfigDefaults{QosPolicy: "gold",
+				AdaptiveQosPolicy: "gold"}}}},
+	} {
+		if err := populateVirtualPoolDefaults(newConfig(test.pools...)); err == nil {
+			t.Errorf("%s: expected an error", test.name)
+		}
+	}
+}
+
+func TestGetVirtualPools(t *testing.T) {
+	newAggrPool := func(name, media string, available, total uint64) *storage.Pool {
+		pool := storage.NewStoragePool(nil, name)
+		pool.Attributes[sa.Media] = sa.NewStringOffer(media)
+		pool.Capacity = &storage.PoolCapacity{AvailableBytes: available, TotalBytes: total}
+		return pool
+	}
+	aggrPools := map[string]*storage.Pool{
+		"aggr1": newAggrPool("aggr1", sa.HDD, 100, 1000),
+		"aggr2": newAggrPool("aggr2", sa.SSD, 300, 1000),
+		"aggr3": newAggrPool("aggr3", sa.SSD, 200, 1000),
+	}
+
+	for _, test := range []struct {
+		name       string
+		driverName string
+		aggregates []string
+		media      []string
+		capacity   storage.PoolCapacity
+	}{
+		{"one aggregate", drivers.OntapNASStorageDriverName, []string{"aggr1"},
+			[]string{sa.HDD}, storage.PoolCapacity{AvailableBytes: 100}},
+		{"several aggregates", drivers.OntapNASStorageDriverName, []string{"aggr1", "aggr3"},
+			[]string{sa.HDD, sa.SSD}, storage.PoolCapacity{AvailableBytes: 200}},
+		{"all aggregates", drivers.OntapNASStorageDriverName, nil,
+			[]string{sa.HDD, sa.SSD}, storage.PoolCapacity{AvailableBytes: 300}},
+		{"spanned aggregates", drivers.OntapNASFlexGroupStorageDriverName, []string{"aggr2", "aggr3"},
+			[]string{sa.SSD}, storage.PoolCapacity{AvailableBytes: 500, TotalBytes: 2000}},
+	} {
+		config := &drivers.OntapStorageDriverConfig{
+			CommonStorageDriverConfig: &drivers.CommonStorageDriverConfig{StorageDriverName: test.driverName},
+			Storage: []drivers.OntapStorageDriverPool{{
+				Name:       "gold",
+				Labels:     map[string]string{"tier": "gold"},
+				Aggregates: test.aggregates,
+			}},
+		}
+
+		pools, err := getVirtualPools(config, nil, aggrPools)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		pool, ok := pools["gold"]
+		if !ok || len(pools) != 1 {
+			t.Errorf("%s: expected only pool gold, got %v", test.name, pools)
+			continue
+		}
+
+		for _, media := range []string{sa.HDD, sa.Hybrid, sa.SSD} {
+			expected := false
+			for _, offered := range test.media {
+				expected = expected || media == offered
+			}
+			if matches := pool.Attributes[sa.Media].Matches(sa.NewStringRequest(media)); matches != expected {
+				t.Errorf("%s: expected media %s offered %v, got %v", test.name, media, expected, matches)
+			}
+		}
+		if pool.Capacity == nil || *pool.Capacity != test.capacity {
+			t.Errorf("%s: expected capacity %+v, got %+v", test.name, test.capacity, pool.Capacity)
+		}
+		if label, ok := pool.Attributes[sa.LabelAttribute("tier")]; !ok ||
+			!label.Matches(sa.NewStringRequest("gold")) {
+			t.Errorf("%s: expected label tier=gold, got %v", test.name, label)
+		}
+	}
+
+	config := &drivers.OntapStorageDriverConfig{
+		CommonStorageDriverConfig: &drivers.CommonStorageDriverConfig{
+			StorageDriverName: drivers.OntapNASStorageDriverName,
+		},
+		Storage: []drivers.OntapStorageDriverPool{{Name: "gold", Aggregates: []string{"aggr1", "aggr9"}}},
+	}
+	if _, err := getVirtualPools(config, nil, aggrPools); err == nil {
+		t.Error("expected an error for an aggregate that isn't available")
+	}
+}
+
+func TestGetVolumeOptsCommonVirtualPool(t *testing.T) {
+	defaults := drivers.OntapStorageDriverConfigDefaults{
+		SpaceReserve:   "none",
+		SnapshotPolicy: "none",
+		ExportPolicy:   "default",
+		SecurityStyle:  "unix",
+		QosPolicy:      "silver",
+	}
+	goldDefaults := defaults
+	goldDefaults.SpaceReserve = "volume"
+	goldDefaults.SnapshotPolicy = "default"
+	goldDefaults.QosPolicy = "gold"
+
+	for _, test := range []struct {
+		name      string
+		driver    StorageDriver
+		volConfig storage.VolumeConfig
+		expected  map[string]string
+	}{
+		{
+			"pool overrides backend",
+			&NASStorageDriver{},
+			storage.VolumeConfig{},
+			map[string]string{"aggregate": "aggr1", "spaceReserve": "volume", "snapshotPolicy": "default",
+				"qosPolicy": "gold"},
+		},
+		{
+			"volume overrides pool",
+			&NASStorageDriver{},
+			storage.VolumeConfig{SnapshotPolicy: "hourly", QoSType: "bronze"},
+			map[string]string{"aggregate": "aggr1", "spaceReserve": "volume", "snapshotPolicy": "hourly",
+				"qosPolicy": "bronze"},
+		},
+		{
+			"FlexGroup spans the pool's aggregates",
+			&NASFlexGroupStorageDriver{},
+			storage.VolumeConfig{},
+			map[string]string{"aggregate": "aggr1,aggr2", "spaceReserve": "volume", "snapshotPolicy": "default",
+				"qosPolicy": "gold"},
+		},
+	} {
+		config := test.driver.GetConfig()
+		config.OntapStorageDriverConfigDefaults = defaults
+		config.Storage = []drivers.OntapStorageDriverPool{{Name: "gold", OntapStorageDriverConfigDefaults: goldDefaults}}
+		if test.driver.Name() == drivers.OntapNASFlexGroupStorageDriverName {
+			config.Storage[0].Aggregates = []string{"aggr1", "aggr2"}
+		} else {
+			// A pool without aggregates of its own uses the backend's aggregate
+			config.Aggregate = "aggr1"
+		}
+
+		opts, err := getVolumeOptsCommon(test.driver, &test.volConfig, &storage.Pool{Name: "gold"}, nil)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		for key, expected := range test.expected {
+			if opts[key] != expected {
+				t.Errorf("%s: expected %s %q, got %q", test.name, key, expected, opts[key])
+			}
+		}
+	}
+}
@@ -3,6 +3,7 @@
 package ontap
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -22,15 +23,19 @@ import (
 type NASStorageDriver struct {
 	initialized bool
 	Config      drivers.OntapStorageDriverConfig
-	API         *api.Client
+	API         api.Interface
 	Telemetry   *Telemetry
+
+	// ReplicationAPI reaches the peer SVM to which volumes are replicated,
+	// and is nil if replication isn't configured
+	ReplicationAPI api.Interface
 }
 
 func (d *NASStorageDriver) GetConfig() *drivers.OntapStorageDriverConfig {
 	return &d.Config
 }
 
-func (d *NASStorageDriver) GetAPI() *api.Client {
+func (d *NASStorageDriver) GetAPI() api.Interface {
 	return d.API
 }
 
@@ -71,6 +76,11 @@ func (d *NASStorageDriver) Initialize(
 		return fmt.Errorf("error validating %s driver: %v", d.Name(), err)
 	}
 
+	d.ReplicationAPI, err = InitializeReplicationAPI(&d.Config, "nfs")
+	if err != nil {
+		return fmt.Errorf("error initializing %s driver replication: %v", d.Name(), err)
+	}
+
 	// Set up the autosupport heartbeat
 	d.Telemetry = InitializeOntapTelemetry(d)
 	StartEmsHeartbeat(d)
@@ -151,34 +161,51 @@ func (d *NASStorageDriver) Create(name string, sizeBytes uint64, opts map[string
 	aggregate := utils.GetV(opts, "aggregate", d.Config.Aggregate)
 	securityStyle := utils.GetV(opts, "securityStyle", d.Config.SecurityStyle)
 	encryption := utils.GetV(opts, "encryption", d.Config.Encryption)
+	replication := utils.GetV(opts, "replication", "false")
 
 	enableSnapshotDir, err := strconv.ParseBool(snapshotDir)
 	if err != nil {
 		return fmt.Errorf("invalid boolean value for snapshotDir: %v", err)
 	}
 
+	replicate, err := strconv.ParseBool(replication)
+	if err != nil {
+		return fmt.Errorf("invalid boolean value for replication: %v", err)
+	}
+	if replicate && d.ReplicationAPI == nil {
+		return errors.New("replication is not configured for this backend")
+	}
+
 	encrypt, err := ValidateEncryptionAttribute(encryption, d.API)
 	if err != nil {
 		return err
 	}
 
+	qosPolicy, adaptiveQosPolicy, createdQosPolicy, err := ensureQosPolicyGroup(d, name, opts)
+	if err != nil {
+		return err
+	}
+
 	log.WithFields(log.Fields{
-		"name":            name,
-		"size":            size,
-		"spaceReserve":    spaceReserve,
-		"snapshotPolicy":  snapshotPolicy,
-		"unixPermissions": unixPermissions,
-		"snapshotDir":     enableSnapshotDir,
-		"exportPolicy":    exportPolicy,
-		"aggregate":       aggregate,
-		"securityStyle":   securityStyle,
-		"encryption":      encryption,
+		"name":              name,
+		"size":              size,
+		"spaceReserve":      spaceReserve,
+		"snapshotPolicy":    snapshotPolicy,
+		"unixPermissions":   unixPermissions,
+		"snapshotDir":       enableSnapshotDir,
+		"exportPolicy":      exportPolicy,
+		"aggregate":         aggregate,
+		"securityStyle":     securityStyle,
+		"encryption":        encryption,
+		"qosPolicy":         qosPolicy,
+		"adaptiveQosPolicy": adaptiveQosPolicy,
+		"replication":       replicate,
 	}).Debug("Creating Flexvol.")
 
 	// Create the volume
 	volCreateResponse, err := d.API.VolumeCreate(
 		name, aggregate, size, spaceReserve, snapshotPolicy,
-		unixPermissions, exportPolicy, securityStyle, encrypt)
+		unixPermissions, exportPolicy, securityStyle, encrypt, qosPolicy, adaptiveQosPolicy)
 
 	if err = api.GetError(volCreateResponse, err); err != nil {
 		if zerr, ok := err.(api.ZapiError); ok {
@@ -188,6 +215,9 @@ func (d *NASStorageDriver) Create(name string, sizeBytes uint64, opts map[string
 				return nil
 			}
 		}
+		if createdQosPolicy {
+			deleteQosPolicyGroup(d, name)
+		}
 		return fmt.Errorf("error creating volume: %v", err)
 	}
 
@@ -208,6 +238,21 @@ func (d *NASStorageDriver) Create(name string, sizeBytes uint64, opts map[string
 	// If LS mirrors are present on the SVM root volume, update them
 	UpdateLoadSharingMirrors(d.API)
 
+	// Mirror the volume to the replication SVM
+	if replicate {
+		if err = CreateReplica(name, size, exportPolicy, &d.Config, d.ReplicationAPI); err != nil {
+			// Don't leave behind a volume that isn't protected as requested
+			volDestroyResponse, destroyErr := d.API.VolumeDestroy(name, true)
+			if destroyErr = api.GetError(volDestroyResponse, destroyErr); destroyErr != nil {
+				log.WithField("volume", name).Warnf("Could not destroy unreplicated volume. %v", destroyErr)
+			}
+			if createdQosPolicy {
+				deleteQosPolicyGroup(d, name)
+			}
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -256,6 +301,11 @@ func (d *NASStorageDriver) Destroy(name string) error {
 	// user to keep the volume around until all of the clones are gone? If we do that, need a
 	// way to list the clones. Maybe volume inspect.
 
+	replicated, err := ReleaseReplica(name, &d.Config, d.API, d.ReplicationAPI)
+	if err != nil {
+		return err
+	}
+
 	volDestroyResponse, err := d.API.VolumeDestroy(name, true)
 	if err != nil {
 		return fmt.Errorf("error destroying volume %v: %v", name, err)
@@ -270,6 +320,54 @@ func (d *NASStorageDriver) Destroy(name string) error {
 		}
 	}
 
+	// Delete the QoS policy group created for the volume, if any
+	deleteQosPolicyGroup(d, name)
+
+	// Remove the volume's replica, if any, only now that the volume is gone
+	if replicated {
+		if err = DestroyReplica(name, &d.Config, d.ReplicationAPI); err != nil {
+			return fmt.Errorf("volume %v was destroyed, but its replica was not: %v", name, err)
+		}
+	}
+
+	return nil
+}
+
+// Resize expands the volume size
+func (d *NASStorageDriver) Resize(name string, sizeBytes uint64) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":    "Resize",
+			"Type":      "NASStorageDriver",
+			"name":      name,
+			"sizeBytes": sizeBytes,
+		}
+		log.WithFields(fields).Debug(">>>> Resize")
+		defer log.WithFields(fields).Debug("<<<< Resize")
+	}
+
+	volAttrs, err := d.API.VolumeGet(name)
+	if err != nil {
+		return fmt.Errorf("error checking for existing volume: %v", err)
+	}
+	volSpaceAttrs := volAttrs.VolumeSpaceAttributes()
+	currentSizeBytes := uint64(volSpaceAttrs.Size())
+
+	if sizeBytes < currentSizeBytes {
+		return fmt.Errorf("requested volume size (%d bytes) is smaller than the current volume size (%d bytes)",
+			sizeBytes, currentSizeBytes)
+	}
+	if sizeBytes == currentSizeBytes {
+		log.WithField("volume", name).Debug("Volume already has the requested size.")
+		return nil
+	}
+
+	resizeResponse, err := d.API.SetVolumeSize(name, strconv.FormatUint(sizeBytes, 10))
+	if err = api.GetError(resizeResponse.Result, err); err != nil {
+		return fmt.Errorf("error resizing volume %v: %v", name, err)
+	}
+
 	return nil
 }
 
@@ -290,7 +388,7 @@ func (d *NASStorageDriver) Attach(name, mountpoint string, opts map[string]strin
 
 	exportPath := fmt.Sprintf("%s:/%s", d.Config.DataLIF, name)
 
-	return MountVolume(exportPath, mountpoint, &d.Config)
+	return MountVolume(exportPath, mountpoint, &d.Config, drivers.IsReadOnlyAttach(opts))
 }
 
 // Detach the volume
@@ -326,6 +424,57 @@ func (d *NASStorageDriver) SnapshotList(name string) ([]storage.Snapshot, error)
 	return GetSnapshotList(name, &d.Config, d.API)
 }
 
+// CreateSnapshot creates a snapshot of the named volume
+func (d *NASStorageDriver) CreateSnapshot(name, snapshot string) (*storage.Snapshot, error) {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":   "CreateSnapshot",
+			"Type":     "NASStorageDriver",
+			"name":     name,
+			"snapshot": snapshot,
+		}
+		log.WithFields(fields).Debug(">>>> CreateSnapshot")
+		defer log.WithFields(fields).Debug("<<<< CreateSnapshot")
+	}
+
+	return CreateSnapshot(name, snapshot, &d.Config, d.API)
+}
+
+// DeleteSnapshot deletes a snapshot of the named volume
+func (d *NASStorageDriver) DeleteSnapshot(name, snapshot string) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":   "DeleteSnapshot",
+			"Type":     "NASStorageDriver",
+			"name":     name,
+			"snapshot": snapshot,
+		}
+		log.WithFields(fields).Debug(">>>> DeleteSnapshot")
+		defer log.WithFields(fields).Debug("<<<< DeleteSnapshot")
+	}
+
+	return DeleteSnapshot(name, snapshot, &d.Config, d.API)
+}
+
+// RestoreSnapshot restores the named volume to the state captured by one of its snapshots
+func (d *NASStorageDriver) RestoreSnapshot(name, snapshot string) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":   "RestoreSnapshot",
+			"Type":     "NASStorageDriver",
+			"name":     name,
+			"snapshot": snapshot,
+		}
+		log.WithFields(fields).Debug(">>>> RestoreSnapshot")
+		defer log.WithFields(fields).Debug("<<<< RestoreSnapshot")
+	}
+
+	return RestoreSnapshot(name, snapshot, &d.Config, d.API)
+}
+
 // Return the list of volumes associated with this tenant
 func (d *NASStorageDriver) List() ([]string, error) {
 
@@ -350,6 +499,64 @@ func (d *NASStorageDriver) Get(name string) error {
 	return GetVolume(name, d.API, &d.Config)
 }
 
+// GetReplicationStatus returns the state of the relationship that replicates the named volume
+func (d *NASStorageDriver) GetReplicationStatus(name string) (*storage.ReplicationStatus, error) {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "GetReplicationStatus",
+			"Type":   "NASStorageDriver",
+			"name":   name,
+		}
+		log.WithFields(fields).Debug(">>>> GetReplicationStatus")
+		defer log.WithFields(fields).Debug("<<<< GetReplicationStatus")
+	}
+
+	return GetReplicationStatus(name, &d.Config, d.ReplicationAPI)
+}
+
+// Failover makes the volume's replica writable, mounts it in the replication SVM's namespace, and points the
+// volume's access info at it
+func (d *NASStorageDriver) Failover(volConfig *storage.VolumeConfig) error {
+
+	name := volConfig.InternalName
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "Failover",
+			"Type":   "NASStorageDriver",
+			"name":   name,
+		}
+		log.WithFields(fields).Debug(">>>> Failover")
+		defer log.WithFields(fields).Debug("<<<< Failover")
+	}
+
+	if err := BreakReplica(name, &d.Config, d.ReplicationAPI); err != nil {
+		return err
+	}
+
+	// The replica may already be mounted by an earlier attempt
+	volAttrs, err := d.ReplicationAPI.VolumeGet(name)
+	if err != nil {
+		return fmt.Errorf("error checking replica volume: %v", err)
+	}
+	junctionPath := "/" + name
+	volIDAttrs := volAttrs.VolumeIdAttributesPtr
+	if volIDAttrs != nil && volIDAttrs.JunctionPathPtr != nil && volIDAttrs.JunctionPath() != "" {
+		junctionPath = string(volIDAttrs.JunctionPath())
+	} else {
+		mountResponse, err := d.ReplicationAPI.VolumeMount(name, junctionPath)
+		if err = api.GetError(mountResponse, err); err != nil {
+			return fmt.Errorf("error mounting replica volume to junction: %v", err)
+		}
+		UpdateLoadSharingMirrors(d.ReplicationAPI)
+	}
+
+	volConfig.AccessInfo.NfsServerIP = d.Config.ReplicationDataLIF
+	volConfig.AccessInfo.NfsPath = junctionPath
+	return nil
+}
+
 // Retrieve storage backend capabilities
 func (d *NASStorageDriver) GetStorageBackendSpecs(backend *storage.Backend) error {
 
@@ -365,6 +572,7 @@ func (d *NASStorageDriver) GetStoragePoolAttributes() map[string]sa.Offer {
 		sa.Snapshots:        sa.NewBoolOffer(true),
 		sa.Clones:           sa.NewBoolOffer(true),
 		sa.Encryption:       sa.NewBoolOffer(d.API.SupportsFeature(api.NetAppVolumeEncryption)),
+		sa.Replication:      sa.NewBoolOffer(d.ReplicationAPI != nil),
 		sa.ProvisioningType: sa.NewStringOffer("thick", "thin"),
 	}
 }
@@ -374,7 +582,7 @@ func (d *NASStorageDriver) GetVolumeOpts(
 	pool *storage.Pool,
 	requests map[string]sa.Request,
 ) (map[string]string, error) {
-	return getVolumeOptsCommon(volConfig, pool, requests), nil
+	return getVolumeOptsCommon(d, volConfig, pool, requests)
 }
 
 func (d *NASStorageDriver) GetInternalVolumeName(name string) string {
@@ -394,6 +602,45 @@ func (d *NASStorageDriver) CreateFollowup(
 	return nil
 }
 
+func (d *NASStorageDriver) Import(volConfig *storage.VolumeConfig, originalName string) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":       "Import",
+			"Type":         "NASStorageDriver",
+			"originalName": originalName,
+			"newName":      volConfig.InternalName,
+		}
+		log.WithFields(fields).Debug(">>>> Import")
+		defer log.WithFields(fields).Debug("<<<< Import")
+	}
+
+	volAttrs, err := d.API.VolumeGet(originalName)
+	if err != nil {
+		return err
+	}
+
+	// Imported volumes may be mounted anywhere in the SVM namespace, and
+	// renaming a volume doesn't change its junction path.
+	volIDAttrs := volAttrs.VolumeIdAttributesPtr
+	if volIDAttrs == nil || volIDAttrs.JunctionPathPtr == nil || volIDAttrs.JunctionPath() == "" {
+		return fmt.Errorf("volume %s is not mounted", originalName)
+	}
+	junctionPath := string(volIDAttrs.JunctionPath())
+
+	if volConfig.InternalName != originalName {
+		renameResponse, err := d.API.VolumeRename(originalName, volConfig.InternalName)
+		if err = api.GetError(renameResponse, err); err != nil {
+			return fmt.Errorf("error renaming volume %s to %s: %v", originalName, volConfig.InternalName, err)
+		}
+	}
+
+	volConfig.AccessInfo.NfsServerIP = d.Config.DataLIF
+	volConfig.AccessInfo.NfsPath = junctionPath
+	volConfig.FileSystem = ""
+	return nil
+}
+
 func (d *NASStorageDriver) GetProtocol() trident.Protocol {
 	return trident.File
 }
@@ -409,6 +656,11 @@ func (d *NASStorageDriver) GetExternalConfig() interface{} {
 	return getExternalConfig(d.Config)
 }
 
+// GetCommonConfig returns the settings the driver shares with all drivers.
+func (d *NASStorageDriver) GetCommonConfig() *drivers.CommonStorageDriverConfig {
+	return d.Config.CommonStorageDriverConfig
+}
+
 // GetVolumeExternal queries the storage backend for all relevant info about
 // a single container volume managed by this driver and returns a VolumeExternal
 // representation of the volume.
@@ -439,8 +691,13 @@ func (d *NASStorageDriver) GetVolumeExternalWrappers(
 		return
 	}
 
-	// Convert all volumes to VolumeExternal and write them to the channel
+	// Convert all volumes to VolumeExternal and write them to the channel, skipping the Flexvols
+	// that hold the qtrees or LUNs of any ontap-nas-economy or ontap-san-economy backends on the SVM
 	for _, volume := range volumesResponse.Result.AttributesList() {
+		volumeName := string(volume.VolumeIdAttributesPtr.Name())
+		if isQtreeFlexvolName(volumeName) || isLunFlexvolName(volumeName) {
+			continue
+		}
 		channel <- &storage.VolumeExternalWrapper{d.getVolumeExternal(&volume), nil}
 	}
 }
@@ -459,7 +716,7 @@ func (d *NASStorageDriver) getVolumeExternal(
 	volumeSnapshotAttrs := volumeAttrs.VolumeSnapshotAttributesPtr
 
 	internalName := string(volumeIDAttrs.Name())
-	name := internalName[len(*d.Config.StoragePrefix):]
+	name := strings.TrimPrefix(internalName, *d.Config.StoragePrefix)
 
 	volumeConfig := &storage.VolumeConfig{
 		Version:         trident.OrchestratorAPIVersion,
@@ -0,0 +1,698 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package ontap
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	trident "github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage"
+	sa "github.com/netapp/trident/storage_attribute"
+	drivers "github.com/netapp/trident/storage_drivers"
+	"github.com/netapp/trident/storage_drivers/ontap/api"
+	"github.com/netapp/trident/storage_drivers/ontap/api/azgo"
+	"github.com/netapp/trident/utils"
+)
+
+// NASFlexGroupStorageDriver is for NFS FlexGroup storage provisioning
+type NASFlexGroupStorageDriver struct {
+	initialized bool
+	Config      drivers.OntapStorageDriverConfig
+	API         api.Interface
+	Telemetry   *Telemetry
+}
+
+func (d *NASFlexGroupStorageDriver) GetConfig() *drivers.OntapStorageDriverConfig {
+	return &d.Config
+}
+
+func (d *NASFlexGroupStorageDriver) GetAPI() api.Interface {
+	return d.API
+}
+
+func (d *NASFlexGroupStorageDriver) GetTelemetry() *Telemetry {
+	return d.Telemetry
+}
+
+// Name is for returning the name of this driver
+func (d *NASFlexGroupStorageDriver) Name() string {
+	return drivers.OntapNASFlexGroupStorageDriverName
+}
+
+// Initialize from the provided config
+func (d *NASFlexGroupStorageDriver) Initialize(
+	context trident.DriverContext, configJSON string, commonConfig *drivers.CommonStorageDriverConfig,
+) error {
+
+	if commonConfig.DebugTraceFlags["method"] {
+		fields := log.Fields{"Method": "Initialize", "Type": "NASFlexGroupStorageDriver"}
+		log.WithFields(fields).Debug(">>>> Initialize")
+		defer log.WithFields(fields).Debug("<<<< Initialize")
+	}
+
+	// Parse the config
+	config, err := InitializeOntapConfig(context, configJSON, commonConfig)
+	if err != nil {
+		return fmt.Errorf("error initializing %s driver: %v", d.Name(), err)
+	}
+
+	d.API, err = InitializeOntapDriver(config)
+	if err != nil {
+		return fmt.Errorf("error initializing %s driver: %v", d.Name(), err)
+	}
+	d.Config = *config
+
+	err = d.validate()
+	if err != nil {
+		return fmt.Errorf("error validating %s driver: %v", d.Name(), err)
+	}
+
+	// Set up the autosupport heartbeat
+	d.Telemetry = InitializeOntapTelemetry(d)
+	StartEmsHeartbeat(d)
+
+	d.initialized = true
+	return nil
+}
+
+func (d *NASFlexGroupStorageDriver) Initialized() bool {
+	return d.initialized
+}
+
+func (d *NASFlexGroupStorageDriver) Terminate() {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{"Method": "Terminate", "Type": "NASFlexGroupStorageDriver"}
+		log.WithFields(fields).Debug(">>>> Terminate")
+		defer log.WithFields(fields).Debug("<<<< Terminate")
+	}
+
+	d.initialized = false
+}
+
+// Validate the driver configuration and execution environment
+func (d *NASFlexGroupStorageDriver) validate() error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{"Method": "validate", "Type": "NASFlexGroupStorageDriver"}
+		log.WithFields(fields).Debug(">>>> validate")
+		defer log.WithFields(fields).Debug("<<<< validate")
+	}
+
+	if !d.API.SupportsFeature(api.FlexGroups) {
+		return errors.New("ONTAP 9 or later is required for FlexGroups")
+	}
+
+	// FlexGroups span several aggregates, which may be limited with virtual pools
+	if d.Config.Aggregate != "" {
+		return errors.New("the aggregate may not be set for FlexGroups; use virtual pools to limit " +
+			"the aggregates they span")
+	}
+
+	err := ValidateNASDriver(d.API, &d.Config)
+	if err != nil {
+		return fmt.Errorf("driver validation failed: %v", err)
+	}
+
+	return nil
+}
+
+// Create a FlexGroup with the specified options
+func (d *NASFlexGroupStorageDriver) Create(name string, sizeBytes uint64, opts map[string]string) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":    "Create",
+			"Type":      "NASFlexGroupStorageDriver",
+			"name":      name,
+			"sizeBytes": sizeBytes,
+			"opts":      opts,
+		}
+		log.WithFields(fields).Debug(">>>> Create")
+		defer log.WithFields(fields).Debug("<<<< Create")
+	}
+
+	// If the volume already exists, bail out
+	volExists, err := d.API.FlexGroupExists(name)
+	if err != nil {
+		return fmt.Errorf("error checking for existing FlexGroup: %v", err)
+	}
+	if volExists {
+		return fmt.Errorf("FlexGroup %s already exists", name)
+	}
+
+	if sizeBytes < MinimumVolumeSizeBytes {
+		return fmt.Errorf("requested volume size (%d bytes) is too small; the minimum volume size is %d bytes",
+			sizeBytes, MinimumVolumeSizeBytes)
+	}
+
+	// get options with default fallback values
+	// see also: ontap_common.go#PopulateConfigurationDefaults
+	spaceReserve := utils.GetV(opts, "spaceReserve", d.Config.SpaceReserve)
+	snapshotPolicy := utils.GetV(opts, "snapshotPolicy", d.Config.SnapshotPolicy)
+	unixPermissions := utils.GetV(opts, "unixPermissions", d.Config.UnixPermissions)
+	snapshotDir := utils.GetV(opts, "snapshotDir", d.Config.SnapshotDir)
+	exportPolicy := utils.GetV(opts, "exportPolicy", d.Config.ExportPolicy)
+	aggregate := utils.GetV(opts, "aggregate", "")
+	securityStyle := utils.GetV(opts, "securityStyle", d.Config.SecurityStyle)
+	encryption := utils.GetV(opts, "encryption", d.Config.Encryption)
+
+	enableSnapshotDir, err := strconv.ParseBool(snapshotDir)
+	if err != nil {
+		return fmt.Errorf("invalid boolean value for snapshotDir: %v", err)
+	}
+
+	encrypt, err := ValidateEncryptionAttribute(encryption, d.API)
+	if err != nil {
+		return err
+	}
+
+	// FlexGroups may be placed in an existing QoS policy, but don't get policy groups of their own
+	if utils.GetV(opts, "qos", "") != "" {
+		return errors.New("volumes of the ONTAP NAS FlexGroup driver can't have QoS limits of their own; " +
+			"use a QoS policy instead")
+	}
+	qosPolicy, adaptiveQosPolicy, err := getQosPolicies(d, opts)
+	if err != nil {
+		return err
+	}
+
+	// Span the listed aggregates, or all of the SVM's aggregates if none are listed
+	var aggregates []string
+	if aggregate != "" {
+		aggregates = strings.Split(aggregate, ",")
+	} else {
+		aggregates, err = d.API.GetVserverAggregateNames()
+		if err != nil {
+			return fmt.Errorf("error reading the aggregates of SVM %s: %v", d.Config.SVM, err)
+		}
+		if len(aggregates) == 0 {
+			return fmt.Errorf("SVM %s has no assigned aggregates", d.Config.SVM)
+		}
+	}
+
+	log.WithFields(log.Fields{
+		"name":              name,
+		"size":              sizeBytes,
+		"spaceReserve":      spaceReserve,
+		"snapshotPolicy":    snapshotPolicy,
+		"unixPermissions":   unixPermissions,
+		"snapshotDir":       enableSnapshotDir,
+		"exportPolicy":      exportPolicy,
+		"aggregates":        aggregates,
+		"securityStyle":     securityStyle,
+		"encryption":        encryption,
+		"qosPolicy":         qosPolicy,
+		"adaptiveQosPolicy": adaptiveQosPolicy,
+	}).Debug("Creating FlexGroup.")
+
+	// Create the FlexGroup, which is mounted at a junction matching its name
+	volCreateResponse, err := d.API.FlexGroupCreate(
+		name, int(sizeBytes), aggregates, spaceReserve, snapshotPolicy,
+		unixPermissions, exportPolicy, securityStyle, encrypt, qosPolicy, adaptiveQosPolicy)
+
+	if err != nil {
+		if zerr, ok := err.(api.ZapiError); ok {
+			// Handle case where the Create is passed to every Docker Swarm node
+			if zerr.Code() == azgo.EAPIERROR && strings.HasSuffix(strings.TrimSpace(zerr.Reason()), "Job exists") {
+				log.WithField("volume", name).Warn("FlexGroup create job already exists, skipping FlexGroup create on this node.")
+				return nil
+			}
+		}
+		log.WithField("result", volCreateResponse.Result).Debug("FlexGroup create failed.")
+		return fmt.Errorf("error creating FlexGroup: %v", err)
+	}
+
+	// Disable '.snapshot' to allow official mysql container's chmod-in-init to work
+	if !enableSnapshotDir {
+		_, err := d.API.FlexGroupDisableSnapshotDirectoryAccess(name)
+		if err != nil {
+			return fmt.Errorf("error disabling snapshot directory access: %v", err)
+		}
+	}
+
+	// If LS mirrors are present on the SVM root volume, update them
+	UpdateLoadSharingMirrors(d.API)
+
+	return nil
+}
+
+// Create a volume clone
+func (d *NASFlexGroupStorageDriver) CreateClone(name, source, snapshot string, opts map[string]string) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":   "CreateClone",
+			"Type":     "NASFlexGroupStorageDriver",
+			"name":     name,
+			"source":   source,
+			"snapshot": snapshot,
+			"opts":     opts,
+		}
+		log.WithFields(fields).Debug(">>>> CreateClone")
+		defer log.WithFields(fields).Debug("<<<< CreateClone")
+	}
+
+	return errors.New("cloning with the ONTAP NAS FlexGroup driver is not supported")
+}
+
+// Destroy the FlexGroup
+func (d *NASFlexGroupStorageDriver) Destroy(name string) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "Destroy",
+			"Type":   "NASFlexGroupStorageDriver",
+			"name":   name,
+		}
+		log.WithFields(fields).Debug(">>>> Destroy")
+		defer log.WithFields(fields).Debug("<<<< Destroy")
+	}
+
+	_, err := d.API.FlexGroupDestroy(name, true)
+	if err != nil {
+
+		// It's not an error if the FlexGroup no longer exists
+		if zerr, ok := err.(api.ZapiError); ok && zerr.Code() == azgo.EVOLUMEDOESNOTEXIST {
+			log.WithField("volume", name).Warn("FlexGroup already deleted.")
+			return nil
+		}
+		return fmt.Errorf("error destroying FlexGroup %v: %v", name, err)
+	}
+
+	return nil
+}
+
+// Resize expands the FlexGroup size
+func (d *NASFlexGroupStorageDriver) Resize(name string, sizeBytes uint64) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":    "Resize",
+			"Type":      "NASFlexGroupStorageDriver",
+			"name":      name,
+			"sizeBytes": sizeBytes,
+		}
+		log.WithFields(fields).Debug(">>>> Resize")
+		defer log.WithFields(fields).Debug("<<<< Resize")
+	}
+
+	volAttrs, err := d.API.FlexGroupGet(name)
+	if err != nil {
+		return fmt.Errorf("error checking for existing FlexGroup: %v", err)
+	}
+	volSpaceAttrs := volAttrs.VolumeSpaceAttributes()
+	currentSizeBytes := uint64(volSpaceAttrs.Size())
+
+	if sizeBytes < currentSizeBytes {
+		return fmt.Errorf("requested volume size (%d bytes) is smaller than the current volume size (%d bytes)",
+			sizeBytes, currentSizeBytes)
+	}
+	if sizeBytes == currentSizeBytes {
+		log.WithField("volume", name).Debug("FlexGroup already has the requested size.")
+		return nil
+	}
+
+	_, err = d.API.FlexGroupSetSize(name, strconv.FormatUint(sizeBytes, 10))
+	if err != nil {
+		return fmt.Errorf("error resizing FlexGroup %v: %v", name, err)
+	}
+
+	return nil
+}
+
+// Attach the FlexGroup
+func (d *NASFlexGroupStorageDriver) Attach(name, mountpoint string, opts map[string]string) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":     "Attach",
+			"Type":       "NASFlexGroupStorageDriver",
+			"name":       name,
+			"mountpoint": mountpoint,
+			"opts":       opts,
+		}
+		log.WithFields(fields).Debug(">>>> Attach")
+		defer log.WithFields(fields).Debug("<<<< Attach")
+	}
+
+	exportPath := fmt.Sprintf("%s:/%s", d.Config.DataLIF, name)
+
+	return MountVolume(exportPath, mountpoint, &d.Config, drivers.IsReadOnlyAttach(opts))
+}
+
+// Detach the FlexGroup
+func (d *NASFlexGroupStorageDriver) Detach(name, mountpoint string) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":     "Detach",
+			"Type":       "NASFlexGroupStorageDriver",
+			"name":       name,
+			"mountpoint": mountpoint,
+		}
+		log.WithFields(fields).Debug(">>>> Detach")
+		defer log.WithFields(fields).Debug("<<<< Detach")
+	}
+
+	return UnmountVolume(mountpoint, &d.Config)
+}
+
+// Return the list of snapshots associated with the named FlexGroup
+func (d *NASFlexGroupStorageDriver) SnapshotList(name string) ([]storage.Snapshot, error) {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "SnapshotList",
+			"Type":   "NASFlexGroupStorageDriver",
+			"name":   name,
+		}
+		log.WithFields(fields).Debug(">>>> SnapshotList")
+		defer log.WithFields(fields).Debug("<<<< SnapshotList")
+	}
+
+	return GetSnapshotList(name, &d.Config, d.API)
+}
+
+// CreateSnapshot creates a snapshot of the named FlexGroup
+func (d *NASFlexGroupStorageDriver) CreateSnapshot(name, snapshot string) (*storage.Snapshot, error) {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":   "CreateSnapshot",
+			"Type":     "NASFlexGroupStorageDriver",
+			"name":     name,
+			"snapshot": snapshot,
+		}
+		log.WithFields(fields).Debug(">>>> CreateSnapshot")
+		defer log.WithFields(fields).Debug("<<<< CreateSnapshot")
+	}
+
+	return CreateSnapshot(name, snapshot, &d.Config, d.API)
+}
+
+// DeleteSnapshot deletes a snapshot of the named FlexGroup
+func (d *NASFlexGroupStorageDriver) DeleteSnapshot(name, snapshot string) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":   "DeleteSnapshot",
+			"Type":     "NASFlexGroupStorageDriver",
+			"name":     name,
+			"snapshot": snapshot,
+		}
+		log.WithFields(fields).Debug(">>>> DeleteSnapshot")
+		defer log.WithFields(fields).Debug("<<<< DeleteSnapshot")
+	}
+
+	return DeleteSnapshot(name, snapshot, &d.Config, d.API)
+}
+
+// RestoreSnapshot restores the named FlexGroup to the state captured by one of its snapshots
+func (d *NASFlexGroupStorageDriver) RestoreSnapshot(name, snapshot string) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":   "RestoreSnapshot",
+			"Type":     "NASFlexGroupStorageDriver",
+			"name":     name,
+			"snapshot": snapshot,
+		}
+		log.WithFields(fields).Debug(">>>> RestoreSnapshot")
+		defer log.WithFields(fields).Debug("<<<< RestoreSnapshot")
+	}
+
+	return RestoreSnapshot(name, snapshot, &d.Config, d.API)
+}
+
+// Return the list of FlexGroups associated with this tenant
+func (d *NASFlexGroupStorageDriver) List() ([]string, error) {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{"Method": "List", "Type": "NASFlexGroupStorageDriver"}
+		log.WithFields(fields).Debug(">>>> List")
+		defer log.WithFields(fields).Debug("<<<< List")
+	}
+
+	prefix := *d.Config.StoragePrefix
+
+	volResponse, err := d.API.FlexGroupGetAll(prefix)
+	if err = api.GetError(volResponse, err); err != nil {
+		return nil, fmt.Errorf("error enumerating FlexGroups: %v", err)
+	}
+
+	var volumes []string
+	for _, volume := range volResponse.Result.AttributesList() {
+		volIDAttrs := volume.VolumeIdAttributes()
+		volName := string(volIDAttrs.Name())[len(prefix):]
+		volumes = append(volumes, volName)
+	}
+
+	return volumes, nil
+}
+
+// Test for the existence of a FlexGroup
+func (d *NASFlexGroupStorageDriver) Get(name string) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{"Method": "Get", "Type": "NASFlexGroupStorageDriver"}
+		log.WithFields(fields).Debug(">>>> Get")
+		defer log.WithFields(fields).Debug("<<<< Get")
+	}
+
+	volExists, err := d.API.FlexGroupExists(name)
+	if err != nil {
+		return fmt.Errorf("error checking for existing FlexGroup: %v", err)
+	}
+	if !volExists {
+		log.WithField("flexgroup", name).Debug("FlexGroup not found.")
+		return fmt.Errorf("FlexGroup %s does not exist", name)
+	}
+
+	return nil
+}
+
+// GetReplicationStatus returns the state of the relationship that replicates the named volume.  This driver
+// doesn't replicate volumes, so this method always returns nil.
+func (d *NASFlexGroupStorageDriver) GetReplicationStatus(name string) (*storage.ReplicationStatus, error) {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "GetReplicationStatus",
+			"Type":   "NASFlexGroupStorageDriver",
+			"name":   name,
+		}
+		log.WithFields(fields).Debug(">>>> GetReplicationStatus")
+		defer log.WithFields(fields).Debug("<<<< GetReplicationStatus")
+	}
+
+	return nil, nil
+}
+
+// Failover switches a volume to its replica.  This driver doesn't replicate volumes, so this method always
+// returns an error.
+func (d *NASFlexGroupStorageDriver) Failover(volConfig *storage.VolumeConfig) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "Failover",
+			"Type":   "NASFlexGroupStorageDriver",
+			"name":   volConfig.InternalName,
+		}
+		log.WithFields(fields).Debug(">>>> Failover")
+		defer log.WithFields(fields).Debug("<<<< Failover")
+	}
+
+	return errors.New("replication with the ONTAP NAS FlexGroup driver is not supported")
+}
+
+// Retrieve storage backend capabilities
+func (d *NASFlexGroupStorageDriver) GetStorageBackendSpecs(backend *storage.Backend) error {
+
+	backend.Name = "ontapnasfg_" + d.Config.DataLIF
+	poolAttrs := d.GetStoragePoolAttributes()
+	return getStorageBackendSpecsCommon(d, backend, poolAttrs)
+}
+
+func (d *NASFlexGroupStorageDriver) GetStoragePoolAttributes() map[string]sa.Offer {
+
+	return map[string]sa.Offer{
+		sa.BackendType:      sa.NewStringOffer(d.Name()),
+		sa.Snapshots:        sa.NewBoolOffer(true),
+		sa.Clones:           sa.NewBoolOffer(false),
+		sa.Encryption:       sa.NewBoolOffer(d.API.SupportsFeature(api.NetAppVolumeEncryption)),
+		sa.Replication:      sa.NewBoolOffer(false),
+		sa.ProvisioningType: sa.NewStringOffer("thick", "thin"),
+	}
+}
+
+func (d *NASFlexGroupStorageDriver) GetVolumeOpts(
+	volConfig *storage.VolumeConfig,
+	pool *storage.Pool,
+	requests map[string]sa.Request,
+) (map[string]string, error) {
+	return getVolumeOptsCommon(d, volConfig, pool, requests)
+}
+
+func (d *NASFlexGroupStorageDriver) GetInternalVolumeName(name string) string {
+	return getInternalVolumeNameCommon(d.Config.CommonStorageDriverConfig, name)
+}
+
+func (d *NASFlexGroupStorageDriver) CreatePrepare(volConfig *storage.VolumeConfig) bool {
+	return createPrepareCommon(d, volConfig)
+}
+
+func (d *NASFlexGroupStorageDriver) CreateFollowup(
+	volConfig *storage.VolumeConfig,
+) error {
+	volConfig.AccessInfo.NfsServerIP = d.Config.DataLIF
+	volConfig.AccessInfo.NfsPath = "/" + volConfig.InternalName
+	volConfig.FileSystem = ""
+	return nil
+}
+
+func (d *NASFlexGroupStorageDriver) Import(volConfig *storage.VolumeConfig, originalName string) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":       "Import",
+			"Type":         "NASFlexGroupStorageDriver",
+			"originalName": originalName,
+			"newName":      volConfig.InternalName,
+		}
+		log.WithFields(fields).Debug(">>>> Import")
+		defer log.WithFields(fields).Debug("<<<< Import")
+	}
+
+	volAttrs, err := d.API.FlexGroupGet(originalName)
+	if err != nil {
+		return err
+	}
+
+	// Imported FlexGroups may be mounted anywhere in the SVM namespace, and
+	// renaming a FlexGroup doesn't change its junction path.
+	volIDAttrs := volAttrs.VolumeIdAttributesPtr
+	if volIDAttrs == nil || volIDAttrs.JunctionPathPtr == nil || volIDAttrs.JunctionPath() == "" {
+		return fmt.Errorf("FlexGroup %s is not mounted", originalName)
+	}
+	junctionPath := string(volIDAttrs.JunctionPath())
+
+	if volConfig.InternalName != originalName {
+		renameResponse, err := d.API.VolumeRename(originalName, volConfig.InternalName)
+		if err = api.GetError(renameResponse, err); err != nil {
+			return fmt.Errorf("error renaming FlexGroup %s to %s: %v", originalName, volConfig.InternalName, err)
+		}
+	}
+
+	volConfig.AccessInfo.NfsServerIP = d.Config.DataLIF
+	volConfig.AccessInfo.NfsPath = junctionPath
+	volConfig.FileSystem = ""
+	return nil
+}
+
+func (d *NASFlexGroupStorageDriver) GetProtocol() trident.Protocol {
+	return trident.File
+}
+
+func (d *NASFlexGroupStorageDriver) StoreConfig(
+	b *storage.PersistentStorageBackendConfig,
+) {
+	drivers.SanitizeCommonStorageDriverConfig(d.Config.CommonStorageDriverConfig)
+	b.OntapConfig = &d.Config
+}
+
+func (d *NASFlexGroupStorageDriver) GetExternalConfig() interface{} {
+	return getExternalConfig(d.Config)
+}
+
+// GetCommonConfig returns the settings the driver shares with all drivers.
+func (d *NASFlexGroupStorageDriver) GetCommonConfig() *drivers.CommonStorageDriverConfig {
+	return d.Config.CommonStorageDriverConfig
+}
+
+// GetVolumeExternal queries the storage backend for all relevant info about
+// a single container volume managed by this driver and returns a VolumeExternal
+// representation of the volume.
+func (d *NASFlexGroupStorageDriver) GetVolumeExternal(name string) (*storage.VolumeExternal, error) {
+
+	volumeAttributes, err := d.API.FlexGroupGet(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.getVolumeExternal(&volumeAttributes), nil
+}
+
+// GetVolumeExternalWrappers queries the storage backend for all relevant info about
+// container volumes managed by this driver.  It then writes a VolumeExternal
+// representation of each volume to the supplied channel, closing the channel
+// when finished.
+func (d *NASFlexGroupStorageDriver) GetVolumeExternalWrappers(
+	channel chan *storage.VolumeExternalWrapper) {
+
+	// Let the caller know we're done by closing the channel
+	defer close(channel)
+
+	// Get all FlexGroups matching the storage prefix
+	volumesResponse, err := d.API.FlexGroupGetAll(*d.Config.StoragePrefix)
+	if err = api.GetError(volumesResponse, err); err != nil {
+		channel <- &storage.VolumeExternalWrapper{nil, err}
+		return
+	}
+
+	// Convert all FlexGroups to VolumeExternal and write them to the channel
+	for _, volume := range volumesResponse.Result.AttributesList() {
+		channel <- &storage.VolumeExternalWrapper{d.getVolumeExternal(&volume), nil}
+	}
+}
+
+// getExternalVolume is a private method that accepts info about a FlexGroup
+// as returned by the storage backend and formats it as a VolumeExternal
+// object.  FlexGroups span their pool's aggregates, so the pool reported is
+// the SVM.  A FlexGroup can't be traced back to one of several virtual pools,
+// so the pool is left unset if the config defines any.
+func (d *NASFlexGroupStorageDriver) getVolumeExternal(
+	volumeAttrs *azgo.VolumeAttributesType) *storage.VolumeExternal {
+
+	volumeExportAttrs := volumeAttrs.VolumeExportAttributesPtr
+	volumeIDAttrs := volumeAttrs.VolumeIdAttributesPtr
+	volumeSecurityAttrs := volumeAttrs.VolumeSecurityAttributesPtr
+	volumeSecurityUnixAttrs := volumeSecurityAttrs.VolumeSecurityUnixAttributesPtr
+	volumeSpaceAttrs := volumeAttrs.VolumeSpaceAttributesPtr
+	volumeSnapshotAttrs := volumeAttrs.VolumeSnapshotAttributesPtr
+
+	internalName := string(volumeIDAttrs.Name())
+	name := strings.TrimPrefix(internalName, *d.Config.StoragePrefix)
+
+	volumeConfig := &storage.VolumeConfig{
+		Version:         trident.OrchestratorAPIVersion,
+		Name:            name,
+		InternalName:    internalName,
+		Size:            strconv.FormatInt(int64(volumeSpaceAttrs.Size()), 10),
+		Protocol:        trident.File,
+		SnapshotPolicy:  volumeSnapshotAttrs.SnapshotPolicy(),
+		ExportPolicy:    volumeExportAttrs.Policy(),
+		SnapshotDir:     strconv.FormatBool(volumeSnapshotAttrs.SnapdirAccessEnabled()),
+		UnixPermissions: volumeSecurityUnixAttrs.Permissions(),
+		StorageClass:    "",
+		AccessMode:      trident.ReadWriteMany,
+		AccessInfo:      storage.VolumeAccessInfo{},
+		BlockSize:       "",
+		FileSystem:      "",
+	}
+
+	pool := d.Config.SVM
+	if len(d.Config.Storage) > 0 {
+		pool = drivers.UnsetPool
+	}
+
+	return &storage.VolumeExternal{
+		Config: volumeConfig,
+		Pool:   pool,
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package ontap
+
+import (
+	"testing"
+
+	drivers "github.com/netapp/trident/storage_drivers"
+	"github.com/netapp/trident/storage_drivers/ontap/api/azgo"
+)
+
+func TestFlexGroupGetVolumeExternalPool(t *testing.T) {
+	unixAttrs := azgo.NewVolumeSecurityUnixAttributesType().SetPermissions("---rwxr-xr-x")
+	volumeAttrs := azgo.NewVolumeAttributesType().
+		SetVolumeExportAttributes(*azgo.NewVolumeExportAttributesType().SetPolicy("default")).
+		SetVolumeIdAttributes(*azgo.NewVolumeIdAttributesType().SetName("trident_vol1")).
+		SetVolumeSecurityAttributes(*azgo.NewVolumeSecurityAttributesType().
+			SetVolumeSecurityUnixAttributes(*unixAttrs)).
+		SetVolumeSpaceAttributes(*azgo.NewVolumeSpaceAttributesType().SetSize(1073741824)).
+		SetVolumeSnapshotAttributes(*azgo.NewVolumeSnapshotAttributesType().
+			SetSnapdirAccessEnabled(false).
+			SetSnapshotPolicy("none"))
+
+	for _, test := range []struct {
+		name     string
+		storage  []drivers.OntapStorageDriverPool
+		expected string
+	}{
+		{"no virtual pools", nil, "svm0"},
+		{"virtual pools", []drivers.OntapStorageDriverPool{{Name: "gold"}, {Name: "silver"}}, drivers.UnsetPool},
+	} {
+		prefix := "trident_"
+		d := &NASFlexGroupStorageDriver{}
+		d.Config.SVM = "svm0"
+		d.Config.Storage = test.storage
+		d.Config.CommonStorageDriverConfig = &drivers.CommonStorageDriverConfig{StoragePrefix: &prefix}
+
+		volume := d.getVolumeExternal(volumeAttrs)
+		if volume.Pool != test.expected {
+			t.Errorf("%s: expected pool %q, got %q", test.name, test.expected, volume.Pool)
+		}
+		if volume.Config.Name != "vol1" {
+			t.Errorf("%s: expected name vol1, got %s", test.name, volume.Config.Name)
+		}
+	}
+}
@@ -38,7 +38,7 @@ const (
 type NASQtreeStorageDriver struct {
 	initialized         bool
 	Config              drivers.OntapStorageDriverConfig
-	API                 *api.Client
+	API                 api.Interface
 	Telemetry           *Telemetry
 	quotaResizeMap      map[string]bool
 	provMutex           *sync.Mutex
@@ -47,11 +47,22 @@ type NASQtreeStorageDriver struct {
 	housekeepingTasks   map[string]*time.Ticker
 }
 
+// isQtreeFlexvolName returns whether a Flexvol name is one of those the
+// ontap-nas-economy driver gives the Flexvols in which it creates qtrees.
+func isQtreeFlexvolName(name string) bool {
+	for _, artifactPrefix := range []string{artifactPrefixDocker, artifactPrefixKubernetes} {
+		if strings.HasPrefix(name, artifactPrefix+"_qtree_pool_") {
+			return true
+		}
+	}
+	return false
+}
+
 func (d *NASQtreeStorageDriver) GetConfig() *drivers.OntapStorageDriverConfig {
 	return &d.Config
 }
 
-func (d *NASQtreeStorageDriver) GetAPI() *api.Client {
+func (d *NASQtreeStorageDriver) GetAPI() api.Interface {
 	return d.API
 }
 
@@ -96,7 +107,7 @@ func (d *NASQtreeStorageDriver) Initialize(
 	switch context {
 	case trident.ContextDocker:
 		artifactPrefix = artifactPrefixDocker
-	case trident.ContextKubernetes:
+	case trident.ContextKubernetes, trident.ContextCSI:
 		artifactPrefix = artifactPrefixKubernetes
 	}
 
@@ -298,9 +309,19 @@ func (d *NASQtreeStorageDriver) Create(name string, sizeBytes uint64, opts map[s
 		return err
 	}
 
+	// Qtrees share the QoS policy of their Flexvol, so they can't have QoS limits of their own
+	if utils.GetV(opts, "qos", "") != "" {
+		return errors.New("volumes of the ONTAP NAS Economy driver can't have QoS limits of their own; " +
+			"use a QoS policy instead")
+	}
+	qosPolicy, adaptiveQosPolicy, err := getQosPolicies(d, opts)
+	if err != nil {
+		return err
+	}
+
 	// Make sure we have a Flexvol for the new qtree
 	flexvol, err := d.ensureFlexvolForQtree(
-		aggregate, spaceReserve, snapshotPolicy, enableSnapshotDir, encrypt)
+		aggregate, spaceReserve, snapshotPolicy, enableSnapshotDir, encrypt, qosPolicy, adaptiveQosPolicy)
 	if err != nil {
 		log.Errorf("Flexvol location/creation failed. %v", err)
 		return createError
@@ -426,6 +447,91 @@ func (d *NASQtreeStorageDriver) Destroy(name string) error {
 	return nil
 }
 
+// Resize expands the qtree quota, growing the containing Flexvol as needed
+func (d *NASQtreeStorageDriver) Resize(name string, sizeBytes uint64) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":    "Resize",
+			"Type":      "NASQtreeStorageDriver",
+			"name":      name,
+			"sizeBytes": sizeBytes,
+		}
+		log.WithFields(fields).Debug(">>>> Resize")
+		defer log.WithFields(fields).Debug("<<<< Resize")
+	}
+
+	// Ensure any Flexvol we resize won't be pruned or modified by another workflow
+	d.provMutex.Lock()
+	defer d.provMutex.Unlock()
+
+	// Generic user-facing message
+	resizeError := errors.New("volume resize failed")
+
+	exists, flexvol, err := d.API.QtreeExists(name, d.FlexvolNamePrefix())
+	if err != nil {
+		log.Errorf("Error checking for existing qtree. %v", err)
+		return resizeError
+	}
+	if !exists {
+		return fmt.Errorf("volume %s does not exist", name)
+	}
+
+	// Get the current hard disk limit for the qtree
+	target := fmt.Sprintf("/vol/%s/%s", flexvol, name)
+	quota, err := d.API.QuotaEntryGet(target)
+	if err != nil {
+		log.Errorf("Error getting qtree quota. %v", err)
+		return resizeError
+	}
+	currentSizeKB, err := strconv.ParseUint(quota.DiskLimit(), 10, 64)
+	if err != nil {
+		log.Errorf("Error parsing qtree quota disk limit. %v", err)
+		return resizeError
+	}
+	currentSizeBytes := currentSizeKB * 1024
+
+	if sizeBytes < currentSizeBytes {
+		return fmt.Errorf("requested volume size (%d bytes) is smaller than the current volume size (%d bytes)",
+			sizeBytes, currentSizeBytes)
+	}
+	if sizeBytes == currentSizeBytes {
+		log.WithField("qtree", name).Debug("Qtree already has the requested size.")
+		return nil
+	}
+
+	// Grow the Flexvol to contain the larger qtree
+	deltaBytes := sizeBytes - currentSizeBytes
+	flexvolSizeBytes, err := d.getOptimalSizeForFlexvol(flexvol, deltaBytes)
+	if err != nil {
+		log.Warnf("Could not calculate optimal Flexvol size. %v", err)
+
+		// Lacking the optimal size, just grow the Flexvol by the size increase
+		resizeResponse, err := d.API.SetVolumeSize(flexvol, "+"+strconv.FormatUint(deltaBytes, 10))
+		if err = api.GetError(resizeResponse.Result, err); err != nil {
+			log.Errorf("Flexvol resize failed. %v", err)
+			return resizeError
+		}
+	} else {
+
+		// Got optimal size, so just set the Flexvol to that value
+		flexvolSizeStr := strconv.FormatUint(flexvolSizeBytes, 10)
+		resizeResponse, err := d.API.SetVolumeSize(flexvol, flexvolSizeStr)
+		if err = api.GetError(resizeResponse.Result, err); err != nil {
+			log.Errorf("Flexvol resize failed. %v", err)
+			return resizeError
+		}
+	}
+
+	// Update the quota; the housekeeping task will resize the quotas on the Flexvol
+	if err = d.addQuotaForQtree(name, flexvol, sizeBytes); err != nil {
+		log.Errorf("Qtree quota update failed. %v", err)
+		return resizeError
+	}
+
+	return nil
+}
+
 // Attach the volume
 func (d *NASQtreeStorageDriver) Attach(name, mountpoint string, opts map[string]string) error {
 
@@ -454,7 +560,7 @@ func (d *NASQtreeStorageDriver) Attach(name, mountpoint string, opts map[string]
 
 	exportPath := fmt.Sprintf("%s:/%s/%s", d.Config.DataLIF, flexvol, name)
 
-	return MountVolume(exportPath, mountpoint, &d.Config)
+	return MountVolume(exportPath, mountpoint, &d.Config, drivers.IsReadOnlyAttach(opts))
 }
 
 // Detach the volume
@@ -499,6 +605,60 @@ func (d *NASQtreeStorageDriver) SnapshotList(name string) ([]storage.Snapshot, e
 	return []storage.Snapshot{}, nil
 }
 
+// CreateSnapshot creates a snapshot of the named volume.  Qtrees can't have snapshots, so this method always
+// returns an error.
+func (d *NASQtreeStorageDriver) CreateSnapshot(name, snapshot string) (*storage.Snapshot, error) {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":   "CreateSnapshot",
+			"Type":     "NASQtreeStorageDriver",
+			"name":     name,
+			"snapshot": snapshot,
+		}
+		log.WithFields(fields).Debug(">>>> CreateSnapshot")
+		defer log.WithFields(fields).Debug("<<<< CreateSnapshot")
+	}
+
+	return nil, errors.New("snapshots are not supported for qtrees")
+}
+
+// DeleteSnapshot deletes a snapshot of the named volume.  Qtrees can't have snapshots, so this method always
+// returns an error.
+func (d *NASQtreeStorageDriver) DeleteSnapshot(name, snapshot string) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":   "DeleteSnapshot",
+			"Type":     "NASQtreeStorageDriver",
+			"name":     name,
+			"snapshot": snapshot,
+		}
+		log.WithFields(fields).Debug(">>>> DeleteSnapshot")
+		defer log.WithFields(fields).Debug("<<<< DeleteSnapshot")
+	}
+
+	return errors.New("snapshots are not supported for qtrees")
+}
+
+// RestoreSnapshot restores the named volume from one of its snapshots.  Qtrees can't have snapshots, so this
+// method always returns an error.
+func (d *NASQtreeStorageDriver) RestoreSnapshot(name, snapshot string) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":   "RestoreSnapshot",
+			"Type":     "NASQtreeStorageDriver",
+			"name":     name,
+			"snapshot": snapshot,
+		}
+		log.WithFields(fields).Debug(">>>> RestoreSnapshot")
+		defer log.WithFields(fields).Debug("<<<< RestoreSnapshot")
+	}
+
+	return errors.New("snapshots are not supported for qtrees")
+}
+
 // Return the list of volumes associated with this tenant
 func (d *NASQtreeStorageDriver) List() ([]string, error) {
 
@@ -561,10 +721,12 @@ func (d *NASQtreeStorageDriver) Get(name string) error {
 // qtree or it creates a new Flexvol with the needed attributes.
 func (d *NASQtreeStorageDriver) ensureFlexvolForQtree(
 	aggregate, spaceReserve, snapshotPolicy string, enableSnapshotDir bool, encrypt *bool,
+	qosPolicy, adaptiveQosPolicy string,
 ) (string, error) {
 
 	// Check if a suitable Flexvol already exists
-	flexvol, err := d.getFlexvolForQtree(aggregate, spaceReserve, snapshotPolicy, enableSnapshotDir, encrypt)
+	flexvol, err := d.getFlexvolForQtree(aggregate, spaceReserve, snapshotPolicy, enableSnapshotDir, encrypt,
+		qosPolicy, adaptiveQosPolicy)
 	if err != nil {
 		return "", fmt.Errorf("error finding Flexvol for qtree: %v", err)
 	}
@@ -575,7 +737,8 @@ func (d *NASQtreeStorageDriver) ensureFlexvolForQtree(
 	}
 
 	// Nothing found, so create a suitable Flexvol
-	flexvol, err = d.createFlexvolForQtree(aggregate, spaceReserve, snapshotPolicy, enableSnapshotDir, encrypt)
+	flexvol, err = d.createFlexvolForQtree(aggregate, spaceReserve, snapshotPolicy, enableSnapshotDir, encrypt,
+		qosPolicy, adaptiveQosPolicy)
 	if err != nil {
 		return "", fmt.Errorf("error creating Flexvol for qtree: %v", err)
 	}
@@ -589,6 +752,7 @@ func (d *NASQtreeStorageDriver) ensureFlexvolForQtree(
 // quota.
 func (d *NASQtreeStorageDriver) createFlexvolForQtree(
 	aggregate, spaceReserve, snapshotPolicy string, enableSnapshotDir bool, encrypt *bool,
+	qosPolicy, adaptiveQosPolicy string,
 ) (string, error) {
 
 	flexvol := d.FlexvolNamePrefix() + utils.RandomString(10)
@@ -603,22 +767,24 @@ func (d *NASQtreeStorageDriver) createFlexvolForQtree(
 	}
 
 	log.WithFields(log.Fields{
-		"name":            flexvol,
-		"aggregate":       aggregate,
-		"size":            size,
-		"spaceReserve":    spaceReserve,
-		"snapshotPolicy":  snapshotPolicy,
-		"unixPermissions": unixPermissions,
-		"snapshotDir":     enableSnapshotDir,
-		"exportPolicy":    exportPolicy,
-		"securityStyle":   securityStyle,
-		"encryption":      encryption,
+		"name":              flexvol,
+		"aggregate":         aggregate,
+		"size":              size,
+		"spaceReserve":      spaceReserve,
+		"snapshotPolicy":    snapshotPolicy,
+		"unixPermissions":   unixPermissions,
+		"snapshotDir":       enableSnapshotDir,
+		"exportPolicy":      exportPolicy,
+		"securityStyle":     securityStyle,
+		"encryption":        encryption,
+		"qosPolicy":         qosPolicy,
+		"adaptiveQosPolicy": adaptiveQosPolicy,
 	}).Debug("Creating Flexvol for qtrees.")
 
 	// Create the Flexvol
 	createResponse, err := d.API.VolumeCreate(
 		flexvol, aggregate, size, spaceReserve, snapshotPolicy,
-		unixPermissions, exportPolicy, securityStyle, encrypt)
+		unixPermissions, exportPolicy, securityStyle, encrypt, qosPolicy, adaptiveQosPolicy)
 	if err = api.GetError(createResponse, err); err != nil {
 		return "", fmt.Errorf("error creating Flexvol: %v", err)
 	}
@@ -659,11 +825,13 @@ func (d *NASQtreeStorageDriver) createFlexvolForQtree(
 // is returned at random.
 func (d *NASQtreeStorageDriver) getFlexvolForQtree(
 	aggregate, spaceReserve, snapshotPolicy string, enableSnapshotDir bool, encrypt *bool,
+	qosPolicy, adaptiveQosPolicy string,
 ) (string, error) {
 
 	// Get all volumes matching the specified attributes
 	volListResponse, err := d.API.VolumeListByAttrs(
-		d.FlexvolNamePrefix(), aggregate, spaceReserve, snapshotPolicy, enableSnapshotDir, encrypt)
+		d.FlexvolNamePrefix(), aggregate, spaceReserve, snapshotPolicy, enableSnapshotDir, encrypt,
+		qosPolicy, adaptiveQosPolicy)
 
 	if err = api.GetError(volListResponse, err); err != nil {
 		return "", fmt.Errorf("error enumerating Flexvols: %v", err)
@@ -1043,6 +1211,40 @@ func (d *NASQtreeStorageDriver) ensureDefaultExportPolicyRule() error {
 	return nil
 }
 
+// GetReplicationStatus returns the state of the relationship that replicates the named volume.  This driver
+// doesn't replicate volumes, so this method always returns nil.
+func (d *NASQtreeStorageDriver) GetReplicationStatus(name string) (*storage.ReplicationStatus, error) {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "GetReplicationStatus",
+			"Type":   "NASQtreeStorageDriver",
+			"name":   name,
+		}
+		log.WithFields(fields).Debug(">>>> GetReplicationStatus")
+		defer log.WithFields(fields).Debug("<<<< GetReplicationStatus")
+	}
+
+	return nil, nil
+}
+
+// Failover switches a volume to its replica.  This driver doesn't replicate volumes, so this method always
+// returns an error.
+func (d *NASQtreeStorageDriver) Failover(volConfig *storage.VolumeConfig) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "Failover",
+			"Type":   "NASQtreeStorageDriver",
+			"name":   volConfig.InternalName,
+		}
+		log.WithFields(fields).Debug(">>>> Failover")
+		defer log.WithFields(fields).Debug("<<<< Failover")
+	}
+
+	return errors.New("replication is not supported for qtrees")
+}
+
 // Retrieve storage backend capabilities
 func (d *NASQtreeStorageDriver) GetStorageBackendSpecs(backend *storage.Backend) error {
 
@@ -1058,6 +1260,7 @@ func (d *NASQtreeStorageDriver) GetStoragePoolAttributes() map[string]sa.Offer {
 		sa.Snapshots:        sa.NewBoolOffer(false),
 		sa.Clones:           sa.NewBoolOffer(false),
 		sa.Encryption:       sa.NewBoolOffer(d.API.SupportsFeature(api.NetAppVolumeEncryption)),
+		sa.Replication:      sa.NewBoolOffer(false),
 		sa.ProvisioningType: sa.NewStringOffer("thick", "thin"),
 	}
 }
@@ -1067,7 +1270,7 @@ func (d *NASQtreeStorageDriver) GetVolumeOpts(
 	pool *storage.Pool,
 	requests map[string]sa.Request,
 ) (map[string]string, error) {
-	return getVolumeOptsCommon(volConfig, pool, requests), nil
+	return getVolumeOptsCommon(d, volConfig, pool, requests)
 }
 
 func (d *NASQtreeStorageDriver) GetInternalVolumeName(name string) string {
@@ -1096,6 +1299,21 @@ func (d *NASQtreeStorageDriver) CreateFollowup(volConfig *storage.VolumeConfig)
 	return nil
 }
 
+func (d *NASQtreeStorageDriver) Import(volConfig *storage.VolumeConfig, originalName string) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":       "Import",
+			"Type":         "NASQtreeStorageDriver",
+			"originalName": originalName,
+		}
+		log.WithFields(fields).Debug(">>>> Import")
+		defer log.WithFields(fields).Debug("<<<< Import")
+	}
+
+	return errors.New("import is not supported for qtrees")
+}
+
 func (d *NASQtreeStorageDriver) GetProtocol() trident.Protocol {
 	return trident.File
 }
@@ -1109,6 +1327,11 @@ func (d *NASQtreeStorageDriver) GetExternalConfig() interface{} {
 	return getExternalConfig(d.Config)
 }
 
+// GetCommonConfig returns the settings the driver shares with all drivers.
+func (d *NASQtreeStorageDriver) GetCommonConfig() *drivers.CommonStorageDriverConfig {
+	return d.Config.CommonStorageDriverConfig
+}
+
 // GetVolumeExternal queries the storage backend for all relevant info about
 // a single container volume managed by this driver and returns a VolumeExternal
 // representation of the volume.
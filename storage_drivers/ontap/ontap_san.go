@@ -3,6 +3,7 @@
 package ontap
 
 import (
+	"errors"
 	"fmt"
 	"os/exec"
 	"strconv"
@@ -29,15 +30,19 @@ func lunPath(name string) string {
 type SANStorageDriver struct {
 	initialized bool
 	Config      drivers.OntapStorageDriverConfig
-	API         *api.Client
+	API         api.Interface
 	Telemetry   *Telemetry
+
+	// ReplicationAPI reaches the peer SVM to which volumes are replicated,
+	// and is nil if replication isn't configured
+	ReplicationAPI api.Interface
 }
 
 func (d *SANStorageDriver) GetConfig() *drivers.OntapStorageDriverConfig {
 	return &d.Config
 }
 
-func (d *SANStorageDriver) GetAPI() *api.Client {
+func (d *SANStorageDriver) GetAPI() api.Interface {
 	return d.API
 }
 
@@ -82,6 +87,11 @@ func (d *SANStorageDriver) Initialize(
 		return fmt.Errorf("error validating %s driver: %v", d.Name(), err)
 	}
 
+	d.ReplicationAPI, err = InitializeReplicationAPI(&d.Config, "iscsi")
+	if err != nil {
+		return fmt.Errorf("error initializing %s driver replication: %v", d.Name(), err)
+	}
+
 	// Set up the autosupport heartbeat
 	d.Telemetry = InitializeOntapTelemetry(d)
 	StartEmsHeartbeat(d)
@@ -114,41 +124,9 @@ func (d *SANStorageDriver) validate() error {
 		defer log.WithFields(fields).Debug("<<<< validate")
 	}
 
-	dataLIFs, err := d.API.NetInterfaceGetDataLIFs("iscsi")
+	err := ValidateSANDriver(d.API, &d.Config)
 	if err != nil {
-		return err
-	}
-
-	if len(dataLIFs) == 0 {
-		return fmt.Errorf("no iSCSI data LIFs found on SVM %s", d.Config.SVM)
-	} else {
-		log.WithField("dataLIFs", dataLIFs).Debug("Found iSCSI LIFs.")
-	}
-
-	// If they didn't set a LIF to use in the config, we'll set it to the first iSCSI LIF we happen to find
-	if d.Config.DataLIF == "" {
-		d.Config.DataLIF = dataLIFs[0]
-	} else {
-		err := ValidateDataLIFs(&d.Config, dataLIFs)
-		if err != nil {
-			return fmt.Errorf("data LIF validation failed: %v", err)
-		}
-
-		d.Config.DataLIF = dataLIFs[0]
-	}
-
-	if d.Config.DriverContext == trident.ContextDocker {
-		// Make sure this host is logged into the ONTAP iSCSI target
-		err := utils.EnsureIscsiSession(d.Config.DataLIF)
-		if err != nil {
-			return fmt.Errorf("error establishing iSCSI session: %v", err)
-		}
-
-		// Make sure the configured aggregate is available
-		err = ValidateAggregate(d.API, &d.Config)
-		if err != nil {
-			return err
-		}
+		return fmt.Errorf("driver validation failed: %v", err)
 	}
 
 	return nil
@@ -194,12 +172,21 @@ func (d *SANStorageDriver) Create(name string, sizeBytes uint64, opts map[string
 	aggregate := utils.GetV(opts, "aggregate", d.Config.Aggregate)
 	securityStyle := utils.GetV(opts, "securityStyle", d.Config.SecurityStyle)
 	encryption := utils.GetV(opts, "encryption", d.Config.Encryption)
+	replication := utils.GetV(opts, "replication", "false")
 
 	encrypt, err := ValidateEncryptionAttribute(encryption, d.API)
 	if err != nil {
 		return err
 	}
 
+	replicate, err := strconv.ParseBool(replication)
+	if err != nil {
+		return fmt.Errorf("invalid boolean value for replication: %v", err)
+	}
+	if replicate && d.ReplicationAPI == nil {
+		return errors.New("replication is not configured for this backend")
+	}
+
 	// Check for a supported file system type
 	fstype := strings.ToLower(utils.GetV(opts, "fstype|fileSystemType", d.Config.FileSystemType))
 	switch fstype {
@@ -209,23 +196,31 @@ func (d *SANStorageDriver) Create(name string, sizeBytes uint64, opts map[string
 		return fmt.Errorf("unsupported fileSystemType option: %s", fstype)
 	}
 
+	qosPolicy, adaptiveQosPolicy, createdQosPolicy, err := ensureQosPolicyGroup(d, name, opts)
+	if err != nil {
+		return err
+	}
+
 	log.WithFields(log.Fields{
-		"name":            name,
-		"size":            size,
-		"spaceReserve":    spaceReserve,
-		"snapshotPolicy":  snapshotPolicy,
-		"unixPermissions": unixPermissions,
-		"snapshotDir":     snapshotDir,
-		"exportPolicy":    exportPolicy,
-		"aggregate":       aggregate,
-		"securityStyle":   securityStyle,
-		"encryption":      encryption,
+		"name":              name,
+		"size":              size,
+		"spaceReserve":      spaceReserve,
+		"snapshotPolicy":    snapshotPolicy,
+		"unixPermissions":   unixPermissions,
+		"snapshotDir":       snapshotDir,
+		"exportPolicy":      exportPolicy,
+		"aggregate":         aggregate,
+		"securityStyle":     securityStyle,
+		"encryption":        encryption,
+		"qosPolicy":         qosPolicy,
+		"adaptiveQosPolicy": adaptiveQosPolicy,
+		"replication":       replicate,
 	}).Debug("Creating Flexvol.")
 
 	// Create the volume
 	volCreateResponse, err := d.API.VolumeCreate(
 		name, aggregate, size, spaceReserve, snapshotPolicy,
-		unixPermissions, exportPolicy, securityStyle, encrypt)
+		unixPermissions, exportPolicy, securityStyle, encrypt, qosPolicy, adaptiveQosPolicy)
 
 	if err = api.GetError(volCreateResponse, err); err != nil {
 		if zerr, ok := err.(api.ZapiError); ok {
@@ -236,6 +231,9 @@ func (d *SANStorageDriver) Create(name string, sizeBytes uint64, opts map[string
 				return nil
 			}
 		}
+		if createdQosPolicy {
+			deleteQosPolicyGroup(d, name)
+		}
 		return fmt.Errorf("error creating volume: %v", err)
 	}
 
@@ -251,7 +249,7 @@ func (d *SANStorageDriver) Create(name string, sizeBytes uint64, opts map[string
 	// Save the fstype in a LUN attribute so we know what to do in Attach
 	attrResponse, err := d.API.LunSetAttribute(lunPath, LUNAttributeFSType, fstype)
 	if err = api.GetError(attrResponse, err); err != nil {
-		defer d.API.LunDestroy(lunPath)
+		defer d.API.LunDestroy(lunPath, false)
 		return fmt.Errorf("error saving file system type for LUN: %v", err)
 	}
 	// Save the context
@@ -260,6 +258,21 @@ func (d *SANStorageDriver) Create(name string, sizeBytes uint64, opts map[string
 		log.WithField("name", name).Warning("Failed to save the driver context attribute for new volume.")
 	}
 
+	// Mirror the volume to the replication SVM
+	if replicate {
+		if err = CreateReplica(name, size, exportPolicy, &d.Config, d.ReplicationAPI); err != nil {
+			// Don't leave behind a volume that isn't protected as requested
+			volDestroyResponse, destroyErr := d.API.VolumeDestroy(name, true)
+			if destroyErr = api.GetError(volDestroyResponse, destroyErr); destroyErr != nil {
+				log.WithField("volume", name).Warnf("Could not destroy unreplicated volume. %v", destroyErr)
+			}
+			if createdQosPolicy {
+				deleteQosPolicyGroup(d, name)
+			}
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -301,6 +314,11 @@ func (d *SANStorageDriver) Destroy(name string) error {
 		defer log.WithFields(fields).Debug("<<<< Destroy")
 	}
 
+	replicated, err := ReleaseReplica(name, &d.Config, d.API, d.ReplicationAPI)
+	if err != nil {
+		return err
+	}
+
 	// Validate Flexvol exists before trying to destroy
 	volExists, err := d.API.VolumeExists(name)
 	if err != nil {
@@ -308,212 +326,102 @@ func (d *SANStorageDriver) Destroy(name string) error {
 	}
 	if !volExists {
 		log.WithField("volume", name).Debug("Volume already deleted, skipping destroy.")
-		return nil
-	}
+	} else {
+		// Delete the Flexvol & LUN
+		volDestroyResponse, err := d.API.VolumeDestroy(name, true)
+		if err != nil {
+			return fmt.Errorf("error destroying volume %v: %v", name, err)
+		}
+		if zerr := api.NewZapiError(volDestroyResponse); !zerr.IsPassed() {
+			// Handle case where the Destroy is passed to every Docker Swarm node
+			if zerr.Code() == azgo.EVOLUMEDOESNOTEXIST {
+				log.WithField("volume", name).Warn("Volume already deleted.")
+			} else {
+				return fmt.Errorf("error destroying volume %v: %v", name, zerr)
+			}
+		}
 
-	// Delete the Flexvol & LUN
-	volDestroyResponse, err := d.API.VolumeDestroy(name, true)
-	if err != nil {
-		return fmt.Errorf("error destroying volume %v: %v", name, err)
-	}
-	if zerr := api.NewZapiError(volDestroyResponse); !zerr.IsPassed() {
-		// Handle case where the Destroy is passed to every Docker Swarm node
-		if zerr.Code() == azgo.EVOLUMEDOESNOTEXIST {
-			log.WithField("volume", name).Warn("Volume already deleted.")
-		} else {
-			return fmt.Errorf("error destroying volume %v: %v", name, zerr)
+		// Delete the QoS policy group created for the volume, if any
+		deleteQosPolicyGroup(d, name)
+
+		// Perform rediscovery to remove the deleted LUN
+		if d.Config.DriverContext == trident.ContextDocker {
+			utils.MultipathFlush() // flush unused paths
+			utils.IscsiRescan(true)
 		}
 	}
 
-	// Perform rediscovery to remove the deleted LUN
-	if d.Config.DriverContext == trident.ContextDocker {
-		utils.MultipathFlush() // flush unused paths
-		utils.IscsiRescan(true)
+	// Remove the volume's replica, if any, only now that the volume is gone
+	if replicated {
+		if err = DestroyReplica(name, &d.Config, d.ReplicationAPI); err != nil {
+			return fmt.Errorf("volume %v was destroyed, but its replica was not: %v", name, err)
+		}
 	}
 
 	return nil
 }
 
-// Attach the lun
-func (d *SANStorageDriver) Attach(name, mountpoint string, opts map[string]string) error {
+// Resize expands the Flexvol and the LUN within it
+func (d *SANStorageDriver) Resize(name string, sizeBytes uint64) error {
 
 	if d.Config.DebugTraceFlags["method"] {
 		fields := log.Fields{
-			"Method":     "Attach",
-			"Type":       "SANStorageDriver",
-			"name":       name,
-			"mountpoint": mountpoint,
-			"opts":       opts,
+			"Method":    "Resize",
+			"Type":      "SANStorageDriver",
+			"name":      name,
+			"sizeBytes": sizeBytes,
 		}
-		log.WithFields(fields).Debug(">>>> Attach")
-		defer log.WithFields(fields).Debug("<<<< Attach")
-	}
-
-	// Error if no iSCSI session exists for the specified iscsi portal
-	sessionExists, err := utils.IscsiSessionExists(d.Config.DataLIF)
-	if err != nil {
-		return fmt.Errorf("unexpected iSCSI session error: %v", err)
-	}
-	if !sessionExists {
-		return fmt.Errorf("expected iSCSI session %v not found; please login to the iSCSI portal", d.Config.DataLIF)
+		log.WithFields(fields).Debug(">>>> Resize")
+		defer log.WithFields(fields).Debug("<<<< Resize")
 	}
 
-	igroupName := d.Config.IgroupName
 	lunPath := lunPath(name)
 
-	// Get the fstype
-	fstype := DefaultFileSystemType
-	attrResponse, err := d.API.LunGetAttribute(lunPath, LUNAttributeFSType)
-	if err = api.GetError(attrResponse, err); err != nil {
-		log.WithFields(log.Fields{
-			"LUN":    lunPath,
-			"fstype": fstype,
-		}).Warn("LUN attribute fstype not found, using default.")
-	} else {
-		fstype = attrResponse.Result.Value()
-		log.WithFields(log.Fields{"LUN": lunPath, "fstype": fstype}).Debug("Found LUN attribute fstype.")
-	}
-
-	// Create igroup
-	igroupResponse, err := d.API.IgroupCreate(igroupName, "iscsi", "linux")
-	if err != nil {
-		return fmt.Errorf("error creating igroup: %v", err)
-	}
-	if zerr := api.NewZapiError(igroupResponse); !zerr.IsPassed() {
-		// Handle case where the igroup already exists
-		if zerr.Code() != azgo.EVDISK_ERROR_INITGROUP_EXISTS {
-			return fmt.Errorf("error creating igroup %v: %v", igroupName, zerr)
-		}
-	}
-
-	// Lookup host IQNs
-	iqns, err := utils.GetInitiatorIqns()
-	if err != nil {
-		return fmt.Errorf("error determining host initiator IQNs: %v", err)
-	}
-
-	// Add each IQN found to group
-	for _, iqn := range iqns {
-		igroupAddResponse, err := d.API.IgroupAdd(igroupName, iqn)
-		if err := api.GetError(igroupAddResponse, err); err != nil {
-			if zerr, ok := err.(api.ZapiError); ok {
-				if zerr.Code() == azgo.EVDISK_ERROR_INITGROUP_HAS_NODE {
-					continue
-				}
-			}
-			return fmt.Errorf("error adding IQN %v to igroup %v: %v", iqn, igroupName, err)
-		}
-	}
-
-	// Map LUN
-	lunID, err := d.API.LunMapIfNotMapped(igroupName, lunPath)
+	lunAttrs, err := d.API.LunGet(lunPath)
 	if err != nil {
-		return err
+		return fmt.Errorf("error checking for existing LUN: %v", err)
 	}
+	currentSizeBytes := uint64(lunAttrs.Size())
 
-	// Perform discovery to see the created/mapped LUN
-	utils.IscsiRescan(false)
-
-	// Lookup all the SCSI device information
-	info, err := utils.GetDeviceInfoForLuns()
-	if err != nil {
-		return fmt.Errorf("error getting SCSI device information: %v", err)
+	if sizeBytes < currentSizeBytes {
+		return fmt.Errorf("requested volume size (%d bytes) is smaller than the current volume size (%d bytes)",
+			sizeBytes, currentSizeBytes)
 	}
-
-	// Lookup all the iSCSI session information
-	sessionInfo, err := utils.GetIscsiSessionInfo()
-	if err != nil {
-		return fmt.Errorf("error getting iSCSI session information: %v", err)
+	if sizeBytes == currentSizeBytes {
+		log.WithField("volume", name).Debug("Volume already has the requested size.")
+		return nil
 	}
 
-	sessionInfoToUse := utils.IscsiSessionInfo{}
-	for i, e := range sessionInfo {
-		if e.PortalIP == d.Config.DataLIF {
-			sessionInfoToUse = sessionInfo[i]
-		}
+	// Grow the Flexvol first so that it can contain the larger LUN
+	volResizeResponse, err := d.API.SetVolumeSize(name, strconv.FormatUint(sizeBytes, 10))
+	if err = api.GetError(volResizeResponse.Result, err); err != nil {
+		return fmt.Errorf("error resizing volume %v: %v", name, err)
 	}
 
-	for i, e := range info {
-		log.WithFields(log.Fields{
-			"i":                i,
-			"scsiHost":         e.Host,
-			"scsiChannel":      e.Channel,
-			"scsiTarget":       e.Target,
-			"scsiLun":          e.LUN,
-			"multipathDevFile": e.MultipathDevice,
-			"devFile":          e.Device,
-			"fsType":           e.Filesystem,
-			"iqn":              e.IQN,
-		}).Debug("Found")
+	lunResizeResponse, err := d.API.LunResize(lunPath, int(sizeBytes))
+	if err = api.GetError(lunResizeResponse, err); err != nil {
+		return fmt.Errorf("error resizing LUN %v: %v", lunPath, err)
 	}
 
-	// look for the expected mapped lun
-	for i, e := range info {
-
-		log.WithFields(log.Fields{
-			"i":                i,
-			"scsiHost":         e.Host,
-			"scsiChannel":      e.Channel,
-			"scsiTarget":       e.Target,
-			"scsiLun":          e.LUN,
-			"multipathDevFile": e.MultipathDevice,
-			"devFile":          e.Device,
-			"fsType":           e.Filesystem,
-			"iqn":              e.IQN,
-		}).Debug("Checking")
-
-		if e.LUN != strconv.Itoa(lunID) {
-			log.Debugf("Skipping... lun id %v != %v", e.LUN, lunID)
-			continue
-		}
-
-		if !strings.HasPrefix(e.IQN, sessionInfoToUse.TargetName) {
-			log.Debugf("Skipping... %v doesn't start with %v", e.IQN, sessionInfoToUse.TargetName)
-			continue
-		}
-
-		// If we're here then, we should be on the right info element:
-		// *) we have the expected LUN ID
-		// *) we have the expected iscsi session target
-		log.Debugf("Using... %v", e)
-
-		// Make sure we use the proper device (multipath if in use)
-		deviceToUse := e.Device
-		if e.MultipathDevice != "" {
-			deviceToUse = e.MultipathDevice
-		}
-
-		if deviceToUse == "" {
-			return fmt.Errorf("could not determine device to use for %v", name)
-		}
+	return nil
+}
 
-		// Put a filesystem on it if there isn't one already there
-		if e.Filesystem == "" {
-			log.WithFields(log.Fields{"LUN": lunPath, "fstype": fstype}).Debug("Formatting LUN.")
-			err := utils.FormatVolume(deviceToUse, fstype)
-			if err != nil {
-				return fmt.Errorf("error formatting LUN %v, device %v: %v", name, deviceToUse, err)
-			}
-		} else if e.Filesystem != fstype {
-			log.WithFields(log.Fields{
-				"LUN":             lunPath,
-				"existingFstype":  e.Filesystem,
-				"requestedFstype": fstype,
-			}).Warn("LUN already formatted with a different file system type.")
-		} else {
-			log.WithFields(log.Fields{"LUN": lunPath, "fstype": e.Filesystem}).Debug("LUN already formatted.")
-		}
+// Attach the lun
+func (d *SANStorageDriver) Attach(name, mountpoint string, opts map[string]string) error {
 
-		// Mount it
-		err := utils.Mount(deviceToUse, mountpoint)
-		if err != nil {
-			return fmt.Errorf("error mounting LUN %v, device %v, mountpoint %v: %v", name, deviceToUse, mountpoint,
-				err)
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":     "Attach",
+			"Type":       "SANStorageDriver",
+			"name":       name,
+			"mountpoint": mountpoint,
+			"opts":       opts,
 		}
-		return nil
+		log.WithFields(fields).Debug(">>>> Attach")
+		defer log.WithFields(fields).Debug("<<<< Attach")
 	}
 
-	return fmt.Errorf("attach failed, device not found: %v", name)
+	return attachOntapSANLun(name, lunPath(name), mountpoint, drivers.IsReadOnlyAttach(opts), &d.Config, d.API)
 }
 
 // Detach the volume
@@ -557,6 +465,57 @@ func (d *SANStorageDriver) SnapshotList(name string) ([]storage.Snapshot, error)
 	return GetSnapshotList(name, &d.Config, d.API)
 }
 
+// CreateSnapshot creates a snapshot of the named volume
+func (d *SANStorageDriver) CreateSnapshot(name, snapshot string) (*storage.Snapshot, error) {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":   "CreateSnapshot",
+			"Type":     "SANStorageDriver",
+			"name":     name,
+			"snapshot": snapshot,
+		}
+		log.WithFields(fields).Debug(">>>> CreateSnapshot")
+		defer log.WithFields(fields).Debug("<<<< CreateSnapshot")
+	}
+
+	return CreateSnapshot(name, snapshot, &d.Config, d.API)
+}
+
+// DeleteSnapshot deletes a snapshot of the named volume
+func (d *SANStorageDriver) DeleteSnapshot(name, snapshot string) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":   "DeleteSnapshot",
+			"Type":     "SANStorageDriver",
+			"name":     name,
+			"snapshot": snapshot,
+		}
+		log.WithFields(fields).Debug(">>>> DeleteSnapshot")
+		defer log.WithFields(fields).Debug("<<<< DeleteSnapshot")
+	}
+
+	return DeleteSnapshot(name, snapshot, &d.Config, d.API)
+}
+
+// RestoreSnapshot restores the named volume to the state captured by one of its snapshots
+func (d *SANStorageDriver) RestoreSnapshot(name, snapshot string) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":   "RestoreSnapshot",
+			"Type":     "SANStorageDriver",
+			"name":     name,
+			"snapshot": snapshot,
+		}
+		log.WithFields(fields).Debug(">>>> RestoreSnapshot")
+		defer log.WithFields(fields).Debug("<<<< RestoreSnapshot")
+	}
+
+	return RestoreSnapshot(name, snapshot, &d.Config, d.API)
+}
+
 // Return the list of volumes associated with this tenant
 func (d *SANStorageDriver) List() ([]string, error) {
 
@@ -581,6 +540,49 @@ func (d *SANStorageDriver) Get(name string) error {
 	return GetVolume(name, d.API, &d.Config)
 }
 
+// GetReplicationStatus returns the state of the relationship that replicates the named volume
+func (d *SANStorageDriver) GetReplicationStatus(name string) (*storage.ReplicationStatus, error) {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "GetReplicationStatus",
+			"Type":   "SANStorageDriver",
+			"name":   name,
+		}
+		log.WithFields(fields).Debug(">>>> GetReplicationStatus")
+		defer log.WithFields(fields).Debug("<<<< GetReplicationStatus")
+	}
+
+	return GetReplicationStatus(name, &d.Config, d.ReplicationAPI)
+}
+
+// Failover makes the volume's replica writable, maps its LUN to the igroup of the same name on the replication
+// SVM, and points the volume's access info at it
+func (d *SANStorageDriver) Failover(volConfig *storage.VolumeConfig) error {
+
+	name := volConfig.InternalName
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "Failover",
+			"Type":   "SANStorageDriver",
+			"name":   name,
+		}
+		log.WithFields(fields).Debug(">>>> Failover")
+		defer log.WithFields(fields).Debug("<<<< Failover")
+	}
+
+	if err := BreakReplica(name, &d.Config, d.ReplicationAPI); err != nil {
+		return err
+	}
+
+	replicaConfig := d.Config
+	replicaConfig.SVM = d.Config.ReplicationSVM
+	replicaConfig.DataLIF = d.Config.ReplicationDataLIF
+
+	return mapOntapSANLun(volConfig, lunPath(name), &replicaConfig, d.ReplicationAPI)
+}
+
 // Retrieve storage backend capabilities
 func (d *SANStorageDriver) GetStorageBackendSpecs(backend *storage.Backend) error {
 
@@ -596,6 +598,7 @@ func (d *SANStorageDriver) GetStoragePoolAttributes() map[string]sa.Offer {
 		sa.Snapshots:        sa.NewBoolOffer(true),
 		sa.Clones:           sa.NewBoolOffer(true),
 		sa.Encryption:       sa.NewBoolOffer(d.API.SupportsFeature(api.NetAppVolumeEncryption)),
+		sa.Replication:      sa.NewBoolOffer(d.ReplicationAPI != nil),
 		sa.ProvisioningType: sa.NewStringOffer("thick", "thin"),
 	}
 }
@@ -605,7 +608,7 @@ func (d *SANStorageDriver) GetVolumeOpts(
 	pool *storage.Pool,
 	requests map[string]sa.Request,
 ) (map[string]string, error) {
-	return getVolumeOptsCommon(volConfig, pool, requests), nil
+	return getVolumeOptsCommon(d, volConfig, pool, requests)
 }
 
 func (d *SANStorageDriver) GetInternalVolumeName(name string) string {
@@ -617,51 +620,41 @@ func (d *SANStorageDriver) CreatePrepare(volConfig *storage.VolumeConfig) bool {
 }
 
 func (d *SANStorageDriver) CreateFollowup(volConfig *storage.VolumeConfig) error {
-	return d.mapOntapSANLun(volConfig)
+	return mapOntapSANLun(volConfig, lunPath(volConfig.InternalName), &d.Config, d.API)
 }
 
-func (d *SANStorageDriver) mapOntapSANLun(volConfig *storage.VolumeConfig) error {
-	var (
-		targetIQN string
-		lunID     int
-	)
+func (d *SANStorageDriver) Import(volConfig *storage.VolumeConfig, originalName string) error {
 
-	response, err := d.API.IscsiServiceGetIterRequest()
-	if response.Result.ResultStatusAttr != "passed" || err != nil {
-		return fmt.Errorf("problem retrieving iSCSI services: %v, %v",
-			err, response.Result.ResultErrnoAttr)
-	}
-	for _, serviceInfo := range response.Result.AttributesList() {
-		if serviceInfo.Vserver() == d.Config.SVM {
-			targetIQN = serviceInfo.NodeName()
-			log.WithFields(log.Fields{
-				"volume":    volConfig.Name,
-				"targetIQN": targetIQN,
-			}).Debug("Successfully discovered target IQN for the volume.")
-			break
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":       "Import",
+			"Type":         "SANStorageDriver",
+			"originalName": originalName,
+			"newName":      volConfig.InternalName,
 		}
+		log.WithFields(fields).Debug(">>>> Import")
+		defer log.WithFields(fields).Debug("<<<< Import")
 	}
 
-	// Map LUN
-	lunPath := fmt.Sprintf("/vol/%v/lun0", volConfig.InternalName)
-	lunID, err = d.API.LunMapIfNotMapped(d.Config.IgroupName, lunPath)
-	if err != nil {
+	if _, err := d.API.VolumeGet(originalName); err != nil {
 		return err
 	}
 
-	volConfig.AccessInfo.IscsiTargetPortal = d.Config.DataLIF
-	volConfig.AccessInfo.IscsiTargetIQN = targetIQN
-	volConfig.AccessInfo.IscsiLunNumber = int32(lunID)
-	volConfig.AccessInfo.IscsiIgroup = d.Config.IgroupName
-	log.WithFields(log.Fields{
-		"volume":          volConfig.Name,
-		"volume_internal": volConfig.InternalName,
-		"targetIQN":       volConfig.AccessInfo.IscsiTargetIQN,
-		"lunNumber":       volConfig.AccessInfo.IscsiLunNumber,
-		"igroup":          volConfig.AccessInfo.IscsiIgroup,
-	}).Debug("Successfully mapped ONTAP LUN.")
+	// This driver only manages Flexvols containing a single LUN named lun0
+	lunPath := fmt.Sprintf("/vol/%v/lun0", originalName)
+	if _, err := d.API.LunGet(lunPath); err != nil {
+		return fmt.Errorf("could not find LUN %s; only volumes with a LUN named lun0 may be imported: %v",
+			lunPath, err)
+	}
 
-	return nil
+	if volConfig.InternalName != originalName {
+		renameResponse, err := d.API.VolumeRename(originalName, volConfig.InternalName)
+		if err = api.GetError(renameResponse, err); err != nil {
+			return fmt.Errorf("error renaming volume %s to %s: %v", originalName, volConfig.InternalName, err)
+		}
+	}
+
+	return mapOntapSANLun(volConfig, fmt.Sprintf("/vol/%v/lun0", volConfig.InternalName), &d.Config, d.API)
 }
 
 func (d *SANStorageDriver) GetProtocol() trident.Protocol {
@@ -679,6 +672,11 @@ func (d *SANStorageDriver) GetExternalConfig() interface{} {
 	return getExternalConfig(d.Config)
 }
 
+// GetCommonConfig returns the settings the driver shares with all drivers.
+func (d *SANStorageDriver) GetCommonConfig() *drivers.CommonStorageDriverConfig {
+	return d.Config.CommonStorageDriverConfig
+}
+
 // GetVolumeExternal queries the storage backend for all relevant info about
 // a single container volume managed by this driver and returns a VolumeExternal
 // representation of the volume.
@@ -754,7 +752,7 @@ func (d *SANStorageDriver) getVolumeExternal(
 	volumeSnapshotAttrs := volumeAttrs.VolumeSnapshotAttributesPtr
 
 	internalName := string(volumeIDAttrs.Name())
-	name := internalName[len(*d.Config.StoragePrefix):]
+	name := strings.TrimPrefix(internalName, *d.Config.StoragePrefix)
 
 	volumeConfig := &storage.VolumeConfig{
 		Version:         trident.OrchestratorAPIVersion,
@@ -778,3 +776,224 @@ func (d *SANStorageDriver) getVolumeExternal(
 		Pool:   volumeIDAttrs.ContainingAggregateName(),
 	}
 }
+
+// attachOntapSANLun maps a LUN to this host's igroup, formats it if needed, and mounts it,
+// read-only if readOnly is set.
+func attachOntapSANLun(
+	name, lunPath, mountpoint string, readOnly bool, config *drivers.OntapStorageDriverConfig, client api.Interface,
+) error {
+
+	// Error if no iSCSI session exists for the specified iscsi portal
+	sessionExists, err := utils.IscsiSessionExists(config.DataLIF)
+	if err != nil {
+		return fmt.Errorf("unexpected iSCSI session error: %v", err)
+	}
+	if !sessionExists {
+		return fmt.Errorf("expected iSCSI session %v not found; please login to the iSCSI portal", config.DataLIF)
+	}
+
+	igroupName := config.IgroupName
+
+	// Get the fstype
+	fstype := DefaultFileSystemType
+	attrResponse, err := client.LunGetAttribute(lunPath, LUNAttributeFSType)
+	if err = api.GetError(attrResponse, err); err != nil {
+		log.WithFields(log.Fields{
+			"LUN":    lunPath,
+			"fstype": fstype,
+		}).Warn("LUN attribute fstype not found, using default.")
+	} else {
+		fstype = attrResponse.Result.Value()
+		log.WithFields(log.Fields{"LUN": lunPath, "fstype": fstype}).Debug("Found LUN attribute fstype.")
+	}
+
+	// Create igroup
+	igroupResponse, err := client.IgroupCreate(igroupName, "iscsi", "linux")
+	if err != nil {
+		return fmt.Errorf("error creating igroup: %v", err)
+	}
+	if zerr := api.NewZapiError(igroupResponse); !zerr.IsPassed() {
+		// Handle case where the igroup already exists
+		if zerr.Code() != azgo.EVDISK_ERROR_INITGROUP_EXISTS {
+			return fmt.Errorf("error creating igroup %v: %v", igroupName, zerr)
+		}
+	}
+
+	// Lookup host IQNs
+	iqns, err := utils.GetInitiatorIqns()
+	if err != nil {
+		return fmt.Errorf("error determining host initiator IQNs: %v", err)
+	}
+
+	// Add each IQN found to group
+	for _, iqn := range iqns {
+		igroupAddResponse, err := client.IgroupAdd(igroupName, iqn)
+		if err := api.GetError(igroupAddResponse, err); err != nil {
+			if zerr, ok := err.(api.ZapiError); ok {
+				if zerr.Code() == azgo.EVDISK_ERROR_INITGROUP_HAS_NODE {
+					continue
+				}
+			}
+			return fmt.Errorf("error adding IQN %v to igroup %v: %v", iqn, igroupName, err)
+		}
+	}
+
+	// Map LUN
+	lunID, err := client.LunMapIfNotMapped(igroupName, lunPath)
+	if err != nil {
+		return err
+	}
+
+	// Perform discovery to see the created/mapped LUN
+	utils.IscsiRescan(false)
+
+	// Lookup all the SCSI device information
+	info, err := utils.GetDeviceInfoForLuns()
+	if err != nil {
+		return fmt.Errorf("error getting SCSI device information: %v", err)
+	}
+
+	// Lookup all the iSCSI session information
+	sessionInfo, err := utils.GetIscsiSessionInfo()
+	if err != nil {
+		return fmt.Errorf("error getting iSCSI session information: %v", err)
+	}
+
+	sessionInfoToUse := utils.IscsiSessionInfo{}
+	for i, e := range sessionInfo {
+		if e.PortalIP == config.DataLIF {
+			sessionInfoToUse = sessionInfo[i]
+		}
+	}
+
+	for i, e := range info {
+		log.WithFields(log.Fields{
+			"i":                i,
+			"scsiHost":         e.Host,
+			"scsiChannel":      e.Channel,
+			"scsiTarget":       e.Target,
+			"scsiLun":          e.LUN,
+			"multipathDevFile": e.MultipathDevice,
+			"devFile":          e.Device,
+			"fsType":           e.Filesystem,
+			"iqn":              e.IQN,
+		}).Debug("Found")
+	}
+
+	// look for the expected mapped lun
+	for i, e := range info {
+
+		log.WithFields(log.Fields{
+			"i":                i,
+			"scsiHost":         e.Host,
+			"scsiChannel":      e.Channel,
+			"scsiTarget":       e.Target,
+			"scsiLun":          e.LUN,
+			"multipathDevFile": e.MultipathDevice,
+			"devFile":          e.Device,
+			"fsType":           e.Filesystem,
+			"iqn":              e.IQN,
+		}).Debug("Checking")
+
+		if e.LUN != strconv.Itoa(lunID) {
+			log.Debugf("Skipping... lun id %v != %v", e.LUN, lunID)
+			continue
+		}
+
+		if !strings.HasPrefix(e.IQN, sessionInfoToUse.TargetName) {
+			log.Debugf("Skipping... %v doesn't start with %v", e.IQN, sessionInfoToUse.TargetName)
+			continue
+		}
+
+		// If we're here then, we should be on the right info element:
+		// *) we have the expected LUN ID
+		// *) we have the expected iscsi session target
+		log.Debugf("Using... %v", e)
+
+		// Make sure we use the proper device (multipath if in use)
+		deviceToUse := e.Device
+		if e.MultipathDevice != "" {
+			deviceToUse = e.MultipathDevice
+		}
+
+		if deviceToUse == "" {
+			return fmt.Errorf("could not determine device to use for %v", name)
+		}
+
+		// Put a filesystem on it if there isn't one already there
+		if e.Filesystem == "" {
+			if readOnly {
+				return fmt.Errorf("LUN %v has no file system, so it cannot be mounted read-only", name)
+			}
+			log.WithFields(log.Fields{"LUN": lunPath, "fstype": fstype}).Debug("Formatting LUN.")
+			err := utils.FormatVolume(deviceToUse, fstype)
+			if err != nil {
+				return fmt.Errorf("error formatting LUN %v, device %v: %v", name, deviceToUse, err)
+			}
+		} else if e.Filesystem != fstype {
+			log.WithFields(log.Fields{
+				"LUN":             lunPath,
+				"existingFstype":  e.Filesystem,
+				"requestedFstype": fstype,
+			}).Warn("LUN already formatted with a different file system type.")
+		} else {
+			log.WithFields(log.Fields{"LUN": lunPath, "fstype": e.Filesystem}).Debug("LUN already formatted.")
+		}
+
+		// Mount it
+		err := utils.Mount(deviceToUse, mountpoint, readOnly)
+		if err != nil {
+			return fmt.Errorf("error mounting LUN %v, device %v, mountpoint %v: %v", name, deviceToUse, mountpoint,
+				err)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("attach failed, device not found: %v", name)
+}
+
+// mapOntapSANLun maps a LUN to the backend's igroup and records the iSCSI access info on the volume config.
+func mapOntapSANLun(
+	volConfig *storage.VolumeConfig, lunPath string, config *drivers.OntapStorageDriverConfig, client api.Interface,
+) error {
+	var (
+		targetIQN string
+		lunID     int
+	)
+
+	response, err := client.IscsiServiceGetIterRequest()
+	if response.Result.ResultStatusAttr != "passed" || err != nil {
+		return fmt.Errorf("problem retrieving iSCSI services: %v, %v",
+			err, response.Result.ResultErrnoAttr)
+	}
+	for _, serviceInfo := range response.Result.AttributesList() {
+		if serviceInfo.Vserver() == config.SVM {
+			targetIQN = serviceInfo.NodeName()
+			log.WithFields(log.Fields{
+				"volume":    volConfig.Name,
+				"targetIQN": targetIQN,
+			}).Debug("Successfully discovered target IQN for the volume.")
+			break
+		}
+	}
+
+	// Map LUN
+	lunID, err = client.LunMapIfNotMapped(config.IgroupName, lunPath)
+	if err != nil {
+		return err
+	}
+
+	volConfig.AccessInfo.IscsiTargetPortal = config.DataLIF
+	volConfig.AccessInfo.IscsiTargetIQN = targetIQN
+	volConfig.AccessInfo.IscsiLunNumber = int32(lunID)
+	volConfig.AccessInfo.IscsiIgroup = config.IgroupName
+	log.WithFields(log.Fields{
+		"volume":          volConfig.Name,
+		"volume_internal": volConfig.InternalName,
+		"targetIQN":       volConfig.AccessInfo.IscsiTargetIQN,
+		"lunNumber":       volConfig.AccessInfo.IscsiLunNumber,
+		"igroup":          volConfig.AccessInfo.IscsiIgroup,
+	}).Debug("Successfully mapped ONTAP LUN.")
+
+	return nil
+}
@@ -0,0 +1,1267 @@
+// Copyright 2018 NetApp, Inc. All Rights Reserved.
+
+package ontap
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"os/exec"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	trident "github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage"
+	sa "github.com/netapp/trident/storage_attribute"
+	drivers "github.com/netapp/trident/storage_drivers"
+	"github.com/netapp/trident/storage_drivers/ontap/api"
+	"github.com/netapp/trident/storage_drivers/ontap/api/azgo"
+	"github.com/netapp/trident/utils"
+)
+
+const maxLunsPerFlexvol = 100
+const defaultResizeFlexvolsPeriodSecs = uint64(60) // default to 1 minute
+
+// SANEconomyStorageDriver is for iSCSI storage provisioning of LUNs in shared Flexvols
+type SANEconomyStorageDriver struct {
+	initialized       bool
+	Config            drivers.OntapStorageDriverConfig
+	API               api.Interface
+	Telemetry         *Telemetry
+	flexvolResizeMap  map[string]bool
+	provMutex         *sync.Mutex
+	flexvolNamePrefix string
+	housekeepingTasks map[string]*time.Ticker
+}
+
+// isLunFlexvolName returns whether a Flexvol name is one of those the
+// ontap-san-economy driver gives the Flexvols in which it creates LUNs.
+func isLunFlexvolName(name string) bool {
+	for _, artifactPrefix := range []string{artifactPrefixDocker, artifactPrefixKubernetes} {
+		if strings.HasPrefix(name, artifactPrefix+"_lun_pool_") {
+			return true
+		}
+	}
+	return false
+}
+
+// economyLunPath returns the path of a LUN in one of this driver's Flexvols
+func economyLunPath(flexvol, name string) string {
+	return fmt.Sprintf("/vol/%s/%s", flexvol, name)
+}
+
+func (d *SANEconomyStorageDriver) GetConfig() *drivers.OntapStorageDriverConfig {
+	return &d.Config
+}
+
+func (d *SANEconomyStorageDriver) GetAPI() api.Interface {
+	return d.API
+}
+
+func (d *SANEconomyStorageDriver) GetTelemetry() *Telemetry {
+	return d.Telemetry
+}
+
+// Name is for returning the name of this driver
+func (d *SANEconomyStorageDriver) Name() string {
+	return drivers.OntapSANEconomyStorageDriverName
+}
+
+func (d *SANEconomyStorageDriver) FlexvolNamePrefix() string {
+	return d.flexvolNamePrefix
+}
+
+// Initialize from the provided config
+func (d *SANEconomyStorageDriver) Initialize(
+	context trident.DriverContext, configJSON string, commonConfig *drivers.CommonStorageDriverConfig,
+) error {
+
+	if commonConfig.DebugTraceFlags["method"] {
+		fields := log.Fields{"Method": "Initialize", "Type": "SANEconomyStorageDriver"}
+		log.WithFields(fields).Debug(">>>> Initialize")
+		defer log.WithFields(fields).Debug("<<<< Initialize")
+	}
+
+	// Parse the config
+	config, err := InitializeOntapConfig(context, configJSON, commonConfig)
+	if err != nil {
+		return fmt.Errorf("error initializing %s driver: %v", d.Name(), err)
+	}
+
+	if config.IgroupName == "" {
+		config.IgroupName = drivers.GetDefaultIgroupName(context)
+	}
+
+	d.API, err = InitializeOntapDriver(config)
+	if err != nil {
+		return fmt.Errorf("error initializing %s driver: %v", d.Name(), err)
+	}
+	d.Config = *config
+
+	// Remap context for artifact naming so the names remain stable over time
+	var artifactPrefix string
+	switch context {
+	case trident.ContextDocker:
+		artifactPrefix = artifactPrefixDocker
+	case trident.ContextKubernetes, trident.ContextCSI:
+		artifactPrefix = artifactPrefixKubernetes
+	}
+
+	// Set up internal driver state
+	d.flexvolResizeMap = make(map[string]bool)
+	d.provMutex = &sync.Mutex{}
+	d.flexvolNamePrefix = fmt.Sprintf("%s_lun_pool_%s_", artifactPrefix, *d.Config.StoragePrefix)
+	d.flexvolNamePrefix = strings.Replace(d.flexvolNamePrefix, "__", "_", -1)
+
+	log.WithFields(log.Fields{
+		"FlexvolNamePrefix": d.flexvolNamePrefix,
+	}).Debugf("SAN economy driver settings.")
+
+	err = d.validate()
+	if err != nil {
+		return fmt.Errorf("error validating %s driver: %v", d.Name(), err)
+	}
+
+	// Ensure all Flexvols fit their LUNs after a driver restart
+	d.queueAllFlexvolsForResize()
+
+	// Do periodic housekeeping like cleaning up unused Flexvols
+	d.startHousekeepingTasks()
+
+	d.initialized = true
+	return nil
+}
+
+func (d *SANEconomyStorageDriver) Initialized() bool {
+	return d.initialized
+}
+
+func (d *SANEconomyStorageDriver) Terminate() {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{"Method": "Terminate", "Type": "SANEconomyStorageDriver"}
+		log.WithFields(fields).Debug(">>>> Terminate")
+		defer log.WithFields(fields).Debug("<<<< Terminate")
+	}
+
+	// Stop housekeeping tasks
+	for taskName, ticker := range d.housekeepingTasks {
+		ticker.Stop()
+		log.WithField("task", taskName).Debug("Stopped housekeeping task.")
+	}
+
+	// Run the housekeeping tasks one last time
+	d.pruneUnusedFlexvols()
+	d.resizeFlexvols()
+
+	d.initialized = false
+}
+
+// Validate the driver configuration and execution environment
+func (d *SANEconomyStorageDriver) validate() error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{"Method": "validate", "Type": "SANEconomyStorageDriver"}
+		log.WithFields(fields).Debug(">>>> validate")
+		defer log.WithFields(fields).Debug("<<<< validate")
+	}
+
+	err := ValidateSANDriver(d.API, &d.Config)
+	if err != nil {
+		return fmt.Errorf("driver validation failed: %v", err)
+	}
+
+	return nil
+}
+
+func (d *SANEconomyStorageDriver) startHousekeepingTasks() {
+
+	d.housekeepingTasks = make(map[string]*time.Ticker)
+
+	// Send EMS message on a configurable schedule
+	d.Telemetry = InitializeOntapTelemetry(d)
+	StartEmsHeartbeat(d)
+
+	// Read background task timings from config file, use defaults if missing or invalid
+	pruneFlexvolsPeriodSecs := defaultPruneFlexvolsPeriodSecs
+	if d.Config.SANEconomyPruneFlexvolsPeriod != "" {
+		i, err := strconv.ParseUint(d.Config.SANEconomyPruneFlexvolsPeriod, 10, 64)
+		if err != nil {
+			log.WithField("interval", d.Config.SANEconomyPruneFlexvolsPeriod).Warnf(
+				"Invalid Flexvol pruning interval. %v", err)
+		} else {
+			pruneFlexvolsPeriodSecs = i
+		}
+	}
+	log.WithFields(log.Fields{
+		"IntervalSeconds": pruneFlexvolsPeriodSecs,
+	}).Debug("Configured Flexvol pruning period.")
+
+	resizeFlexvolsPeriodSecs := defaultResizeFlexvolsPeriodSecs
+	if d.Config.SANEconomyFlexvolResizePeriod != "" {
+		i, err := strconv.ParseUint(d.Config.SANEconomyFlexvolResizePeriod, 10, 64)
+		if err != nil {
+			log.WithField("interval", d.Config.SANEconomyFlexvolResizePeriod).Warnf(
+				"Invalid Flexvol resize interval. %v", err)
+		} else {
+			resizeFlexvolsPeriodSecs = i
+		}
+	}
+	log.WithFields(log.Fields{
+		"IntervalSeconds": resizeFlexvolsPeriodSecs,
+	}).Debug("Configured Flexvol resize period.")
+
+	// Keep the system devoid of Flexvols with no LUNs
+	d.pruneUnusedFlexvols()
+	pruneTicker := time.NewTicker(time.Duration(pruneFlexvolsPeriodSecs) * time.Second)
+	d.housekeepingTasks["pruneTask"] = pruneTicker
+	go func() {
+		for range pruneTicker.C {
+			d.pruneUnusedFlexvols()
+		}
+	}()
+
+	// Keep the Flexvols no larger than their LUNs need
+	d.resizeFlexvols()
+	resizeTicker := time.NewTicker(time.Duration(resizeFlexvolsPeriodSecs) * time.Second)
+	d.housekeepingTasks["resizeTask"] = resizeTicker
+	go func() {
+		for range resizeTicker.C {
+			d.resizeFlexvols()
+		}
+	}()
+}
+
+// Create a LUN in a shared Flexvol with the specified options
+func (d *SANEconomyStorageDriver) Create(name string, sizeBytes uint64, opts map[string]string) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":    "Create",
+			"Type":      "SANEconomyStorageDriver",
+			"name":      name,
+			"sizeBytes": sizeBytes,
+			"opts":      opts,
+		}
+		log.WithFields(fields).Debug(">>>> Create")
+		defer log.WithFields(fields).Debug("<<<< Create")
+	}
+
+	// Ensure any Flexvol we create won't be pruned before we place a LUN on it
+	d.provMutex.Lock()
+	defer d.provMutex.Unlock()
+
+	// Generic user-facing message
+	createError := errors.New("volume creation failed")
+
+	// Ensure volume doesn't already exist
+	exists, existsInFlexvol, err := d.API.LunExists(name, d.FlexvolNamePrefix())
+	if err != nil {
+		log.Errorf("Error checking for existing volume: %v.", err)
+		return createError
+	}
+	if exists {
+		log.WithFields(log.Fields{"LUN": name, "flexvol": existsInFlexvol}).Debug("LUN already exists.")
+		return fmt.Errorf("volume %s already exists", name)
+	}
+
+	if sizeBytes < MinimumVolumeSizeBytes {
+		return fmt.Errorf("requested volume size (%d bytes) is too small; the minimum volume size is %d bytes",
+			sizeBytes, MinimumVolumeSizeBytes)
+	}
+
+	// Get Flexvol options with default fallback values
+	// see also: ontap_common.go#PopulateConfigurationDefaults
+	size := strconv.FormatUint(sizeBytes, 10)
+	aggregate := utils.GetV(opts, "aggregate", d.Config.Aggregate)
+	spaceReserve := utils.GetV(opts, "spaceReserve", d.Config.SpaceReserve)
+	snapshotPolicy := utils.GetV(opts, "snapshotPolicy", d.Config.SnapshotPolicy)
+	encryption := utils.GetV(opts, "encryption", d.Config.Encryption)
+
+	encrypt, err := ValidateEncryptionAttribute(encryption, d.API)
+	if err != nil {
+		return err
+	}
+
+	// Check for a supported file system type
+	fstype := strings.ToLower(utils.GetV(opts, "fstype|fileSystemType", d.Config.FileSystemType))
+	switch fstype {
+	case "xfs", "ext3", "ext4":
+		log.WithFields(log.Fields{"fileSystemType": fstype, "name": name}).Debug("Filesystem format.")
+	default:
+		return fmt.Errorf("unsupported fileSystemType option: %s", fstype)
+	}
+
+	// LUNs share the QoS policy of their Flexvol, so they can't have QoS limits of their own
+	if utils.GetV(opts, "qos", "") != "" {
+		return errors.New("volumes of the ONTAP SAN Economy driver can't have QoS limits of their own; " +
+			"use a QoS policy instead")
+	}
+	qosPolicy, adaptiveQosPolicy, err := getQosPolicies(d, opts)
+	if err != nil {
+		return err
+	}
+
+	// Make sure we have a Flexvol for the new LUN
+	flexvol, err := d.ensureFlexvolForLun(
+		aggregate, spaceReserve, snapshotPolicy, encrypt, qosPolicy, adaptiveQosPolicy)
+	if err != nil {
+		log.Errorf("Flexvol location/creation failed. %v", err)
+		return createError
+	}
+
+	// Grow the Flexvol as needed
+	if err = d.growFlexvolForLun(flexvol, sizeBytes); err != nil {
+		log.Errorf("Flexvol resize failed. %v", err)
+		return createError
+	}
+
+	lunPath := economyLunPath(flexvol, name)
+	osType := "linux"
+
+	// Create the LUN
+	lunCreateResponse, err := d.API.LunCreate(lunPath, int(sizeBytes), osType, false)
+	if err = api.GetError(lunCreateResponse, err); err != nil {
+		log.Errorf("LUN creation failed. %v", err)
+
+		// Give back the space reserved for the LUN
+		d.flexvolResizeMap[flexvol] = true
+		return createError
+	}
+
+	// Save the fstype in a LUN attribute so we know what to do in Attach
+	attrResponse, err := d.API.LunSetAttribute(lunPath, LUNAttributeFSType, fstype)
+	if err = api.GetError(attrResponse, err); err != nil {
+		defer d.API.LunDestroy(lunPath, true)
+		d.flexvolResizeMap[flexvol] = true
+		return fmt.Errorf("error saving file system type for LUN: %v", err)
+	}
+	// Save the context
+	attrResponse, err = d.API.LunSetAttribute(lunPath, "context", string(d.Config.DriverContext))
+	if err = api.GetError(attrResponse, err); err != nil {
+		log.WithField("name", name).Warning("Failed to save the driver context attribute for new volume.")
+	}
+
+	log.WithFields(log.Fields{"LUN": lunPath, "size": size}).Debug("Created LUN.")
+
+	return nil
+}
+
+// CreateClone creates a file clone of a LUN, which is placed in the same Flexvol as its source
+func (d *SANEconomyStorageDriver) CreateClone(name, source, snapshot string, opts map[string]string) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":   "CreateClone",
+			"Type":     "SANEconomyStorageDriver",
+			"name":     name,
+			"source":   source,
+			"snapshot": snapshot,
+			"opts":     opts,
+		}
+		log.WithFields(fields).Debug(">>>> CreateClone")
+		defer log.WithFields(fields).Debug("<<<< CreateClone")
+	}
+
+	// LUNs in shared Flexvols have no snapshots of their own to clone from
+	if snapshot != "" {
+		return errors.New("cloning from a snapshot is not supported with the ONTAP SAN Economy driver")
+	}
+
+	// Ensure the Flexvol we clone within won't be pruned or modified by another workflow
+	d.provMutex.Lock()
+	defer d.provMutex.Unlock()
+
+	// Generic user-facing message
+	cloneError := errors.New("volume clone failed")
+
+	exists, flexvol, err := d.API.LunExists(source, d.FlexvolNamePrefix())
+	if err != nil {
+		log.Errorf("Error checking for existing LUN. %v", err)
+		return cloneError
+	}
+	if !exists {
+		return fmt.Errorf("source volume %s does not exist", source)
+	}
+
+	exists, _, err = d.API.LunExists(name, d.FlexvolNamePrefix())
+	if err != nil {
+		log.Errorf("Error checking for existing LUN. %v", err)
+		return cloneError
+	}
+	if exists {
+		return fmt.Errorf("volume %s already exists", name)
+	}
+
+	sourcePath := economyLunPath(flexvol, source)
+	lunAttrs, err := d.API.LunGet(sourcePath)
+	if err != nil {
+		log.Errorf("Error reading source LUN. %v", err)
+		return cloneError
+	}
+
+	// The clone shares blocks with its source, but the Flexvol must still be able to contain it
+	if err = d.growFlexvolForLun(flexvol, uint64(lunAttrs.Size())); err != nil {
+		log.Errorf("Flexvol resize failed. %v", err)
+		return cloneError
+	}
+
+	cloneResponse, err := d.API.LunCloneCreate(flexvol, source, name, false)
+	if err = api.GetError(cloneResponse, err); err != nil {
+		log.Errorf("LUN clone failed. %v", err)
+		d.flexvolResizeMap[flexvol] = true
+		return cloneError
+	}
+
+	// Carry the source's fstype over to the clone so Attach mounts it the same way
+	lunPath := economyLunPath(flexvol, name)
+	fstypeResponse, err := d.API.LunGetAttribute(sourcePath, LUNAttributeFSType)
+	if err = api.GetError(fstypeResponse, err); err == nil {
+		attrResponse, err := d.API.LunSetAttribute(lunPath, LUNAttributeFSType, fstypeResponse.Result.Value())
+		if err = api.GetError(attrResponse, err); err != nil {
+			log.WithField("name", name).Warning("Failed to save the file system type attribute for new clone.")
+		}
+	}
+	attrResponse, err := d.API.LunSetAttribute(lunPath, "context", string(d.Config.DriverContext))
+	if err = api.GetError(attrResponse, err); err != nil {
+		log.WithField("name", name).Warning("Failed to save the driver context attribute for new clone.")
+	}
+
+	return nil
+}
+
+// Destroy the LUN, leaving its Flexvol to be shrunk or pruned by the housekeeping tasks
+func (d *SANEconomyStorageDriver) Destroy(name string) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "Destroy",
+			"Type":   "SANEconomyStorageDriver",
+			"name":   name,
+		}
+		log.WithFields(fields).Debug(">>>> Destroy")
+		defer log.WithFields(fields).Debug("<<<< Destroy")
+	}
+
+	// Ensure the housekeeping tasks don't interfere with this workflow
+	d.provMutex.Lock()
+	defer d.provMutex.Unlock()
+
+	// Generic user-facing message
+	deleteError := errors.New("volume deletion failed")
+
+	exists, flexvol, err := d.API.LunExists(name, d.FlexvolNamePrefix())
+	if err != nil {
+		log.Errorf("Error checking for existing LUN. %v", err)
+		return deleteError
+	}
+	if !exists {
+		log.WithField("LUN", name).Warn("LUN not found.")
+		return nil
+	}
+
+	// Destroy the LUN, even if it is still mapped
+	lunPath := economyLunPath(flexvol, name)
+	destroyResponse, err := d.API.LunDestroy(lunPath, true)
+	if err = api.GetError(destroyResponse, err); err != nil {
+		log.Errorf("LUN delete failed. %v", err)
+		return deleteError
+	}
+
+	// Mark this Flexvol as needing a resize to give back the LUN's space
+	d.flexvolResizeMap[flexvol] = true
+
+	// Perform rediscovery to remove the deleted LUN
+	if d.Config.DriverContext == trident.ContextDocker {
+		utils.MultipathFlush() // flush unused paths
+		utils.IscsiRescan(true)
+	}
+
+	return nil
+}
+
+// Resize expands the LUN, growing the containing Flexvol as needed
+func (d *SANEconomyStorageDriver) Resize(name string, sizeBytes uint64) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":    "Resize",
+			"Type":      "SANEconomyStorageDriver",
+			"name":      name,
+			"sizeBytes": sizeBytes,
+		}
+		log.WithFields(fields).Debug(">>>> Resize")
+		defer log.WithFields(fields).Debug("<<<< Resize")
+	}
+
+	// Ensure any Flexvol we resize won't be pruned or modified by another workflow
+	d.provMutex.Lock()
+	defer d.provMutex.Unlock()
+
+	// Generic user-facing message
+	resizeError := errors.New("volume resize failed")
+
+	exists, flexvol, err := d.API.LunExists(name, d.FlexvolNamePrefix())
+	if err != nil {
+		log.Errorf("Error checking for existing LUN. %v", err)
+		return resizeError
+	}
+	if !exists {
+		return fmt.Errorf("volume %s does not exist", name)
+	}
+
+	lunPath := economyLunPath(flexvol, name)
+	lunAttrs, err := d.API.LunGet(lunPath)
+	if err != nil {
+		log.Errorf("Error reading LUN. %v", err)
+		return resizeError
+	}
+	currentSizeBytes := uint64(lunAttrs.Size())
+
+	if sizeBytes < currentSizeBytes {
+		return fmt.Errorf("requested volume size (%d bytes) is smaller than the current volume size (%d bytes)",
+			sizeBytes, currentSizeBytes)
+	}
+	if sizeBytes == currentSizeBytes {
+		log.WithField("LUN", name).Debug("LUN already has the requested size.")
+		return nil
+	}
+
+	// Grow the Flexvol first so that it can contain the larger LUN
+	if err = d.growFlexvolForLun(flexvol, sizeBytes-currentSizeBytes); err != nil {
+		log.Errorf("Flexvol resize failed. %v", err)
+		return resizeError
+	}
+
+	lunResizeResponse, err := d.API.LunResize(lunPath, int(sizeBytes))
+	if err = api.GetError(lunResizeResponse, err); err != nil {
+		log.Errorf("LUN resize failed. %v", err)
+		d.flexvolResizeMap[flexvol] = true
+		return resizeError
+	}
+
+	return nil
+}
+
+// Attach the LUN
+func (d *SANEconomyStorageDriver) Attach(name, mountpoint string, opts map[string]string) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":     "Attach",
+			"Type":       "SANEconomyStorageDriver",
+			"name":       name,
+			"mountpoint": mountpoint,
+			"opts":       opts,
+		}
+		log.WithFields(fields).Debug(">>>> Attach")
+		defer log.WithFields(fields).Debug("<<<< Attach")
+	}
+
+	// Check if the LUN exists, and find its Flexvol so we can build the LUN path
+	exists, flexvol, err := d.API.LunExists(name, d.FlexvolNamePrefix())
+	if err != nil {
+		log.Errorf("Error checking for existing LUN. %v", err)
+		return errors.New("volume mount failed")
+	}
+	if !exists {
+		log.WithField("LUN", name).Debug("LUN not found.")
+		return fmt.Errorf("volume %s not found", name)
+	}
+
+	lunPath := economyLunPath(flexvol, name)
+
+	return attachOntapSANLun(name, lunPath, mountpoint, drivers.IsReadOnlyAttach(opts), &d.Config, d.API)
+}
+
+// Detach the volume
+func (d *SANEconomyStorageDriver) Detach(name, mountpoint string) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":     "Detach",
+			"Type":       "SANEconomyStorageDriver",
+			"name":       name,
+			"mountpoint": mountpoint,
+		}
+		log.WithFields(fields).Debug(">>>> Detach")
+		defer log.WithFields(fields).Debug("<<<< Detach")
+	}
+
+	cmd := fmt.Sprintf("umount %s", mountpoint)
+	log.WithField("command", cmd).Debug("Unmounting volume.")
+
+	if out, err := exec.Command("sh", "-c", cmd).CombinedOutput(); err != nil {
+		log.WithField("output", string(out)).Debug("Unmount failed.")
+		return fmt.Errorf("error unmounting volume %v, mountpoint %v: %v", name, mountpoint, err)
+	}
+
+	return nil
+}
+
+// Return the list of snapshots associated with the named volume
+func (d *SANEconomyStorageDriver) SnapshotList(name string) ([]storage.Snapshot, error) {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "SnapshotList",
+			"Type":   "SANEconomyStorageDriver",
+			"name":   name,
+		}
+		log.WithFields(fields).Debug(">>>> SnapshotList")
+		defer log.WithFields(fields).Debug("<<<< SnapshotList")
+	}
+
+	// LUNs in shared Flexvols can't have snapshots, so return an empty list
+	return []storage.Snapshot{}, nil
+}
+
+// CreateSnapshot creates a snapshot of the named volume.  LUNs in shared Flexvols can't have snapshots,
+// so this method always returns an error.
+func (d *SANEconomyStorageDriver) CreateSnapshot(name, snapshot string) (*storage.Snapshot, error) {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":   "CreateSnapshot",
+			"Type":     "SANEconomyStorageDriver",
+			"name":     name,
+			"snapshot": snapshot,
+		}
+		log.WithFields(fields).Debug(">>>> CreateSnapshot")
+		defer log.WithFields(fields).Debug("<<<< CreateSnapshot")
+	}
+
+	return nil, errors.New("snapshots are not supported with the ONTAP SAN Economy driver")
+}
+
+// DeleteSnapshot deletes a snapshot of the named volume.  LUNs in shared Flexvols can't have snapshots,
+// so this method always returns an error.
+func (d *SANEconomyStorageDriver) DeleteSnapshot(name, snapshot string) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":   "DeleteSnapshot",
+			"Type":     "SANEconomyStorageDriver",
+			"name":     name,
+			"snapshot": snapshot,
+		}
+		log.WithFields(fields).Debug(">>>> DeleteSnapshot")
+		defer log.WithFields(fields).Debug("<<<< DeleteSnapshot")
+	}
+
+	return errors.New("snapshots are not supported with the ONTAP SAN Economy driver")
+}
+
+// RestoreSnapshot restores the named volume from one of its snapshots.  LUNs in shared Flexvols can't
+// have snapshots, so this method always returns an error.
+func (d *SANEconomyStorageDriver) RestoreSnapshot(name, snapshot string) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":   "RestoreSnapshot",
+			"Type":     "SANEconomyStorageDriver",
+			"name":     name,
+			"snapshot": snapshot,
+		}
+		log.WithFields(fields).Debug(">>>> RestoreSnapshot")
+		defer log.WithFields(fields).Debug("<<<< RestoreSnapshot")
+	}
+
+	return errors.New("snapshots are not supported with the ONTAP SAN Economy driver")
+}
+
+// Return the list of volumes associated with this tenant
+func (d *SANEconomyStorageDriver) List() ([]string, error) {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{"Method": "List", "Type": "SANEconomyStorageDriver"}
+		log.WithFields(fields).Debug(">>>> List")
+		defer log.WithFields(fields).Debug("<<<< List")
+	}
+
+	// Generic user-facing message
+	listError := errors.New("volume list failed")
+
+	prefix := *d.Config.StoragePrefix
+	volumes := make([]string, 0)
+
+	// Get all LUNs in all Flexvols managed by this driver
+	lunsResponse, err := d.API.LunGetAll(economyLunPath(d.FlexvolNamePrefix()+"*", prefix+"*"))
+	if err = api.GetError(lunsResponse, err); err != nil {
+		log.Errorf("LUN list failed. %v", err)
+		return volumes, listError
+	}
+
+	// AttributesList() returns []LunInfoType
+	for _, lun := range lunsResponse.Result.AttributesList() {
+		vol := path.Base(lun.Path())[len(prefix):]
+		volumes = append(volumes, vol)
+	}
+
+	return volumes, nil
+}
+
+// Test for the existence of a volume
+func (d *SANEconomyStorageDriver) Get(name string) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{"Method": "Get", "Type": "SANEconomyStorageDriver"}
+		log.WithFields(fields).Debug(">>>> Get")
+		defer log.WithFields(fields).Debug("<<<< Get")
+	}
+
+	// Generic user-facing message
+	getError := fmt.Errorf("volume %s not found", name)
+
+	exists, flexvol, err := d.API.LunExists(name, d.FlexvolNamePrefix())
+	if err != nil {
+		log.Errorf("Error checking for existing LUN. %v", err)
+		return getError
+	}
+	if !exists {
+		log.WithField("LUN", name).Debug("LUN not found.")
+		return getError
+	}
+
+	log.WithFields(log.Fields{"LUN": name, "flexvol": flexvol}).Debug("LUN found.")
+
+	return nil
+}
+
+// ensureFlexvolForLun accepts a set of Flexvol characteristics and either finds one to contain a new
+// LUN or it creates a new Flexvol with the needed attributes.
+func (d *SANEconomyStorageDriver) ensureFlexvolForLun(
+	aggregate, spaceReserve, snapshotPolicy string, encrypt *bool, qosPolicy, adaptiveQosPolicy string,
+) (string, error) {
+
+	// Check if a suitable Flexvol already exists
+	flexvol, err := d.getFlexvolForLun(aggregate, spaceReserve, snapshotPolicy, encrypt,
+		qosPolicy, adaptiveQosPolicy)
+	if err != nil {
+		return "", fmt.Errorf("error finding Flexvol for LUN: %v", err)
+	}
+
+	// Found one!
+	if flexvol != "" {
+		return flexvol, nil
+	}
+
+	// Nothing found, so create a suitable Flexvol
+	flexvol, err = d.createFlexvolForLun(aggregate, spaceReserve, snapshotPolicy, encrypt,
+		qosPolicy, adaptiveQosPolicy)
+	if err != nil {
+		return "", fmt.Errorf("error creating Flexvol for LUN: %v", err)
+	}
+
+	return flexvol, nil
+}
+
+// createFlexvolForLun creates a new Flexvol matching the specified attributes for
+// the purpose of containing LUNs supplied as container volumes by this driver.
+// Once this method returns, the Flexvol exists and has its snapshot directory hidden.
+func (d *SANEconomyStorageDriver) createFlexvolForLun(
+	aggregate, spaceReserve, snapshotPolicy string, encrypt *bool, qosPolicy, adaptiveQosPolicy string,
+) (string, error) {
+
+	flexvol := d.FlexvolNamePrefix() + utils.RandomString(10)
+	size := "1g"
+	unixPermissions := d.Config.UnixPermissions
+	exportPolicy := d.Config.ExportPolicy
+	securityStyle := d.Config.SecurityStyle
+
+	encryption := false
+	if encrypt != nil {
+		encryption = *encrypt
+	}
+
+	log.WithFields(log.Fields{
+		"name":              flexvol,
+		"aggregate":         aggregate,
+		"size":              size,
+		"spaceReserve":      spaceReserve,
+		"snapshotPolicy":    snapshotPolicy,
+		"unixPermissions":   unixPermissions,
+		"exportPolicy":      exportPolicy,
+		"securityStyle":     securityStyle,
+		"encryption":        encryption,
+		"qosPolicy":         qosPolicy,
+		"adaptiveQosPolicy": adaptiveQosPolicy,
+	}).Debug("Creating Flexvol for LUNs.")
+
+	// Create the Flexvol
+	createResponse, err := d.API.VolumeCreate(
+		flexvol, aggregate, size, spaceReserve, snapshotPolicy,
+		unixPermissions, exportPolicy, securityStyle, encrypt, qosPolicy, adaptiveQosPolicy)
+	if err = api.GetError(createResponse, err); err != nil {
+		return "", fmt.Errorf("error creating Flexvol: %v", err)
+	}
+
+	// Disable '.snapshot' so that all of this driver's Flexvols may be found by the same query
+	snapDirResponse, err := d.API.VolumeDisableSnapshotDirectoryAccess(flexvol)
+	if err = api.GetError(snapDirResponse, err); err != nil {
+		defer d.API.VolumeDestroy(flexvol, true)
+		return "", fmt.Errorf("error disabling snapshot directory access: %v", err)
+	}
+
+	return flexvol, nil
+}
+
+// getFlexvolForLun returns a Flexvol (from the set of existing Flexvols) that
+// matches the specified Flexvol attributes and does not already contain more
+// than the maximum number of LUNs.  No matching Flexvols is not considered an
+// error.  If more than one matching Flexvol is found, one of those is returned
+// at random.
+func (d *SANEconomyStorageDriver) getFlexvolForLun(
+	aggregate, spaceReserve, snapshotPolicy string, encrypt *bool, qosPolicy, adaptiveQosPolicy string,
+) (string, error) {
+
+	// Get all volumes matching the specified attributes
+	volListResponse, err := d.API.VolumeListByAttrs(
+		d.FlexvolNamePrefix(), aggregate, spaceReserve, snapshotPolicy, false, encrypt,
+		qosPolicy, adaptiveQosPolicy)
+
+	if err = api.GetError(volListResponse, err); err != nil {
+		return "", fmt.Errorf("error enumerating Flexvols: %v", err)
+	}
+
+	// Weed out the Flexvols already having too many LUNs
+	var volumes []string
+	for _, volAttrs := range volListResponse.Result.AttributesList() {
+		volIDAttrs := volAttrs.VolumeIdAttributes()
+		volName := string(volIDAttrs.Name())
+
+		count, err := d.API.LunCount(volName)
+		if err != nil {
+			return "", fmt.Errorf("error enumerating LUNs: %v", err)
+		}
+
+		if count < maxLunsPerFlexvol {
+			volumes = append(volumes, volName)
+		}
+	}
+
+	// Pick a Flexvol.  If there are multiple matches, pick one at random.
+	switch len(volumes) {
+	case 0:
+		return "", nil
+	case 1:
+		return volumes[0], nil
+	default:
+		rand.Seed(time.Now().UnixNano())
+		return volumes[rand.Intn(len(volumes))], nil
+	}
+}
+
+// growFlexvolForLun grows a Flexvol so that it can contain the LUNs in it plus newLunSizeBytes
+// more, falling back to growing it by newLunSizeBytes if the optimal size can't be calculated.
+func (d *SANEconomyStorageDriver) growFlexvolForLun(flexvol string, newLunSizeBytes uint64) error {
+
+	flexvolSizeBytes, err := d.getOptimalSizeForFlexvol(flexvol, newLunSizeBytes)
+	if err != nil {
+		log.Warnf("Could not calculate optimal Flexvol size. %v", err)
+
+		// Lacking the optimal size, just grow the Flexvol to contain the new LUN
+		resizeResponse, err := d.API.SetVolumeSize(flexvol, "+"+strconv.FormatUint(newLunSizeBytes, 10))
+		if err = api.GetError(resizeResponse.Result, err); err != nil {
+			return err
+		}
+		return nil
+	}
+
+	// Got optimal size, so just set the Flexvol to that value
+	flexvolSizeStr := strconv.FormatUint(flexvolSizeBytes, 10)
+	resizeResponse, err := d.API.SetVolumeSize(flexvol, flexvolSizeStr)
+	if err = api.GetError(resizeResponse.Result, err); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// getOptimalSizeForFlexvol sums up the sizes of all the LUNs on a Flexvol and adds the size of
+// the new LUN being added as well as the current Flexvol snapshot reserve.  This value may be used
+// to grow (or shrink) the Flexvol as LUNs are being added or removed.
+func (d *SANEconomyStorageDriver) getOptimalSizeForFlexvol(
+	flexvol string, newLunSizeBytes uint64,
+) (uint64, error) {
+
+	// Get more info about the Flexvol
+	volAttrs, err := d.API.VolumeGet(flexvol)
+	if err != nil {
+		return 0, err
+	}
+	volSpaceAttrs := volAttrs.VolumeSpaceAttributes()
+	snapReserveMultiplier := 1.0 + (float64(volSpaceAttrs.PercentageSnapshotReserve()) / 100.0)
+
+	totalLunSizeBytes, err := d.getTotalLunSize(flexvol)
+	if err != nil {
+		return 0, err
+	}
+
+	usableSpaceBytes := float64(newLunSizeBytes + totalLunSizeBytes)
+	flexvolSizeBytes := uint64(usableSpaceBytes * snapReserveMultiplier)
+
+	log.WithFields(log.Fields{
+		"flexvol":               flexvol,
+		"snapReserveMultiplier": snapReserveMultiplier,
+		"totalLunSizeBytes":     totalLunSizeBytes,
+		"newLunSizeBytes":       newLunSizeBytes,
+		"flexvolSizeBytes":      flexvolSizeBytes,
+	}).Debug("Calculated optimal size for Flexvol with new LUN.")
+
+	return flexvolSizeBytes, nil
+}
+
+// getTotalLunSize returns the sum of the sizes of all LUNs on a Flexvol
+func (d *SANEconomyStorageDriver) getTotalLunSize(flexvol string) (uint64, error) {
+
+	lunsResponse, err := d.API.LunGetAll(economyLunPath(flexvol, "*"))
+	if err = api.GetError(lunsResponse, err); err != nil {
+		return 0, err
+	}
+
+	var totalSizeBytes uint64
+
+	for _, lun := range lunsResponse.Result.AttributesList() {
+		totalSizeBytes += uint64(lun.Size())
+	}
+
+	return totalSizeBytes, nil
+}
+
+// queueAllFlexvolsForResize flags every Flexvol managed by this driver as
+// needing a resize.  This is called once on driver startup to handle the
+// case where the driver was shut down with pending resize operations.
+func (d *SANEconomyStorageDriver) queueAllFlexvolsForResize() {
+
+	// Get list of Flexvols managed by this driver
+	volumeListResponse, err := d.API.VolumeList(d.FlexvolNamePrefix())
+	if err = api.GetError(volumeListResponse, err); err != nil {
+		log.Errorf("Error listing Flexvols: %v", err)
+	}
+
+	for _, volAttrs := range volumeListResponse.Result.AttributesList() {
+		volIDAttrs := volAttrs.VolumeIdAttributes()
+		flexvol := string(volIDAttrs.Name())
+		d.flexvolResizeMap[flexvol] = true
+	}
+}
+
+// resizeFlexvols may be called by a background task, or by a method that changed
+// the LUN population on a Flexvol.  Flexvols needing an update must be flagged
+// in flexvolResizeMap.  Any failures that occur are simply logged, and the resize
+// operation will be attempted each time this method is called until it succeeds.
+func (d *SANEconomyStorageDriver) resizeFlexvols() {
+
+	// Ensure we don't resize any Flexvol that is involved in a LUN provisioning workflow
+	d.provMutex.Lock()
+	defer d.provMutex.Unlock()
+
+	log.Debug("Housekeeping, resizing Flexvols.")
+
+	for flexvol, resize := range d.flexvolResizeMap {
+
+		if resize {
+			flexvolSizeBytes, err := d.getOptimalSizeForFlexvol(flexvol, 0)
+			if err != nil {
+				log.WithFields(log.Fields{"flexvol": flexvol, "error": err}).Debug("Error sizing Flexvol.")
+				continue
+			}
+
+			// An empty Flexvol will be pruned, so there is no need to resize it
+			if flexvolSizeBytes == 0 {
+				delete(d.flexvolResizeMap, flexvol)
+				continue
+			}
+
+			resizeResponse, err := d.API.SetVolumeSize(flexvol, strconv.FormatUint(flexvolSizeBytes, 10))
+			if err != nil {
+				log.WithFields(log.Fields{"flexvol": flexvol, "error": err}).Debug("Error resizing Flexvol.")
+				continue
+			}
+			if zerr := api.NewZapiError(resizeResponse.Result); !zerr.IsPassed() {
+
+				if zerr.Code() == azgo.EVOLUMEDOESNOTEXIST {
+					// Volume gone, so no need to try again
+					log.WithField("flexvol", flexvol).Debug("Volume does not exist.")
+					delete(d.flexvolResizeMap, flexvol)
+				} else {
+					log.WithFields(log.Fields{"flexvol": flexvol, "error": zerr}).Debug("Error resizing Flexvol.")
+				}
+
+				continue
+			}
+
+			log.WithFields(log.Fields{
+				"flexvol":          flexvol,
+				"flexvolSizeBytes": flexvolSizeBytes,
+			}).Debug("Resized Flexvol.")
+
+			// Resize succeeded, so no need to try again
+			delete(d.flexvolResizeMap, flexvol)
+		}
+	}
+}
+
+// pruneUnusedFlexvols is called periodically by a background task.  Any Flexvols
+// that are managed by this driver (discovered by virtue of having a well-known
+// hardcoded prefix on their names) that have no LUNs are deleted.
+func (d *SANEconomyStorageDriver) pruneUnusedFlexvols() {
+
+	// Ensure we don't prune any Flexvol that is involved in a LUN provisioning workflow
+	d.provMutex.Lock()
+	defer d.provMutex.Unlock()
+
+	log.Debug("Housekeeping, checking for managed Flexvols with no LUNs.")
+
+	// Get list of Flexvols managed by this driver
+	volumeListResponse, err := d.API.VolumeList(d.FlexvolNamePrefix())
+	if err = api.GetError(volumeListResponse, err); err != nil {
+		log.Errorf("Error listing Flexvols. %v", err)
+	}
+
+	var flexvols []string
+	for _, volAttrs := range volumeListResponse.Result.AttributesList() {
+		volIDAttrs := volAttrs.VolumeIdAttributes()
+		volName := string(volIDAttrs.Name())
+		flexvols = append(flexvols, volName)
+	}
+
+	// Destroy any Flexvol if it is devoid of LUNs
+	for _, flexvol := range flexvols {
+		lunCount, err := d.API.LunCount(flexvol)
+		if err == nil && lunCount == 0 {
+			log.WithField("flexvol", flexvol).Debug("Housekeeping, deleting managed Flexvol with no LUNs.")
+			d.API.VolumeDestroy(flexvol, true)
+		}
+	}
+}
+
+// GetReplicationStatus returns the state of the relationship that replicates the named volume.  This driver
+// doesn't replicate volumes, so this method always returns nil.
+func (d *SANEconomyStorageDriver) GetReplicationStatus(name string) (*storage.ReplicationStatus, error) {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "GetReplicationStatus",
+			"Type":   "SANEconomyStorageDriver",
+			"name":   name,
+		}
+		log.WithFields(fields).Debug(">>>> GetReplicationStatus")
+		defer log.WithFields(fields).Debug("<<<< GetReplicationStatus")
+	}
+
+	return nil, nil
+}
+
+// Failover switches a volume to its replica.  This driver doesn't replicate volumes, so this method always
+// returns an error.
+func (d *SANEconomyStorageDriver) Failover(volConfig *storage.VolumeConfig) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "Failover",
+			"Type":   "SANEconomyStorageDriver",
+			"name":   volConfig.InternalName,
+		}
+		log.WithFields(fields).Debug(">>>> Failover")
+		defer log.WithFields(fields).Debug("<<<< Failover")
+	}
+
+	return errors.New("replication is not supported with the ONTAP SAN Economy driver")
+}
+
+// Retrieve storage backend capabilities
+func (d *SANEconomyStorageDriver) GetStorageBackendSpecs(backend *storage.Backend) error {
+
+	backend.Name = "ontapsaneco_" + d.Config.DataLIF
+	poolAttrs := d.GetStoragePoolAttributes()
+	return getStorageBackendSpecsCommon(d, backend, poolAttrs)
+}
+
+func (d *SANEconomyStorageDriver) GetStoragePoolAttributes() map[string]sa.Offer {
+
+	return map[string]sa.Offer{
+		sa.BackendType:      sa.NewStringOffer(d.Name()),
+		sa.Snapshots:        sa.NewBoolOffer(false),
+		sa.Clones:           sa.NewBoolOffer(true),
+		sa.Encryption:       sa.NewBoolOffer(d.API.SupportsFeature(api.NetAppVolumeEncryption)),
+		sa.Replication:      sa.NewBoolOffer(false),
+		sa.ProvisioningType: sa.NewStringOffer("thick", "thin"),
+	}
+}
+
+func (d *SANEconomyStorageDriver) GetVolumeOpts(
+	volConfig *storage.VolumeConfig,
+	pool *storage.Pool,
+	requests map[string]sa.Request,
+) (map[string]string, error) {
+	return getVolumeOptsCommon(d, volConfig, pool, requests)
+}
+
+func (d *SANEconomyStorageDriver) GetInternalVolumeName(name string) string {
+	return getInternalVolumeNameCommon(d.Config.CommonStorageDriverConfig, name)
+}
+
+func (d *SANEconomyStorageDriver) CreatePrepare(volConfig *storage.VolumeConfig) bool {
+	return createPrepareCommon(d, volConfig)
+}
+
+func (d *SANEconomyStorageDriver) CreateFollowup(volConfig *storage.VolumeConfig) error {
+
+	// Determine which Flexvol contains the LUN
+	exists, flexvol, err := d.API.LunExists(volConfig.InternalName, d.FlexvolNamePrefix())
+	if err != nil {
+		return fmt.Errorf("could not determine if LUN %s exists: %v", volConfig.InternalName, err)
+	}
+	if !exists {
+		return fmt.Errorf("could not find LUN %s", volConfig.InternalName)
+	}
+
+	return mapOntapSANLun(volConfig, economyLunPath(flexvol, volConfig.InternalName), &d.Config, d.API)
+}
+
+func (d *SANEconomyStorageDriver) Import(volConfig *storage.VolumeConfig, originalName string) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":       "Import",
+			"Type":         "SANEconomyStorageDriver",
+			"originalName": originalName,
+		}
+		log.WithFields(fields).Debug(">>>> Import")
+		defer log.WithFields(fields).Debug("<<<< Import")
+	}
+
+	return errors.New("import is not supported with the ONTAP SAN Economy driver")
+}
+
+func (d *SANEconomyStorageDriver) GetProtocol() trident.Protocol {
+	return trident.Block
+}
+
+func (d *SANEconomyStorageDriver) StoreConfig(b *storage.PersistentStorageBackendConfig) {
+	drivers.SanitizeCommonStorageDriverConfig(d.Config.CommonStorageDriverConfig)
+	b.OntapConfig = &d.Config
+}
+
+func (d *SANEconomyStorageDriver) GetExternalConfig() interface{} {
+	return getExternalConfig(d.Config)
+}
+
+// GetCommonConfig returns the settings the driver shares with all drivers.
+func (d *SANEconomyStorageDriver) GetCommonConfig() *drivers.CommonStorageDriverConfig {
+	return d.Config.CommonStorageDriverConfig
+}
+
+// GetVolumeExternal queries the storage backend for all relevant info about
+// a single container volume managed by this driver and returns a VolumeExternal
+// representation of the volume.
+func (d *SANEconomyStorageDriver) GetVolumeExternal(name string) (*storage.VolumeExternal, error) {
+
+	exists, flexvol, err := d.API.LunExists(name, d.FlexvolNamePrefix())
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("LUN %s not found", name)
+	}
+
+	volumeAttrs, err := d.API.VolumeGet(flexvol)
+	if err != nil {
+		return nil, err
+	}
+
+	lunAttrs, err := d.API.LunGet(economyLunPath(flexvol, name))
+	if err != nil {
+		return nil, err
+	}
+
+	return d.getVolumeExternal(&lunAttrs, &volumeAttrs), nil
+}
+
+// GetVolumeExternalWrappers queries the storage backend for all relevant info about
+// container volumes managed by this driver.  It then writes a VolumeExternal
+// representation of each volume to the supplied channel, closing the channel
+// when finished.
+func (d *SANEconomyStorageDriver) GetVolumeExternalWrappers(
+	channel chan *storage.VolumeExternalWrapper) {
+
+	// Let the caller know we're done by closing the channel
+	defer close(channel)
+
+	// Get all Flexvols managed by this driver
+	volumesResponse, err := d.API.VolumeGetAll(d.FlexvolNamePrefix())
+	if err = api.GetError(volumesResponse, err); err != nil {
+		channel <- &storage.VolumeExternalWrapper{nil, err}
+		return
+	}
+
+	// Get all LUNs matching the storage prefix in those Flexvols
+	lunPathPattern := economyLunPath(d.FlexvolNamePrefix()+"*", *d.Config.StoragePrefix+"*")
+	lunsResponse, err := d.API.LunGetAll(lunPathPattern)
+	if err = api.GetError(lunsResponse, err); err != nil {
+		channel <- &storage.VolumeExternalWrapper{nil, err}
+		return
+	}
+
+	// Make a map of volumes for faster correlation with LUNs
+	volumeMap := make(map[string]azgo.VolumeAttributesType)
+	for _, volumeAttrs := range volumesResponse.Result.AttributesList() {
+		internalName := string(volumeAttrs.VolumeIdAttributesPtr.Name())
+		volumeMap[internalName] = volumeAttrs
+	}
+
+	// Convert all LUNs to VolumeExternal and write them to the channel
+	for _, lun := range lunsResponse.Result.AttributesList() {
+
+		volume, ok := volumeMap[lun.Volume()]
+		if !ok {
+			log.WithField("path", lun.Path()).Warning("Flexvol not found for LUN.")
+			continue
+		}
+
+		channel <- &storage.VolumeExternalWrapper{d.getVolumeExternal(&lun, &volume), nil}
+	}
+}
+
+// getExternalVolume is a private method that accepts info about a volume
+// as returned by the storage backend and formats it as a VolumeExternal
+// object.
+func (d *SANEconomyStorageDriver) getVolumeExternal(
+	lunAttrs *azgo.LunInfoType, volumeAttrs *azgo.VolumeAttributesType,
+) *storage.VolumeExternal {
+
+	volumeIDAttrs := volumeAttrs.VolumeIdAttributesPtr
+	volumeSnapshotAttrs := volumeAttrs.VolumeSnapshotAttributesPtr
+
+	internalName := path.Base(lunAttrs.Path())
+	name := strings.TrimPrefix(internalName, *d.Config.StoragePrefix)
+
+	volumeConfig := &storage.VolumeConfig{
+		Version:         trident.OrchestratorAPIVersion,
+		Name:            name,
+		InternalName:    internalName,
+		Size:            strconv.FormatInt(int64(lunAttrs.Size()), 10),
+		Protocol:        trident.Block,
+		SnapshotPolicy:  volumeSnapshotAttrs.SnapshotPolicy(),
+		ExportPolicy:    "",
+		SnapshotDir:     "false",
+		UnixPermissions: "",
+		StorageClass:    "",
+		AccessMode:      trident.ReadWriteOnce,
+		AccessInfo:      storage.VolumeAccessInfo{},
+		BlockSize:       "",
+		FileSystem:      "",
+	}
+
+	return &storage.VolumeExternal{
+		Config: volumeConfig,
+		Pool:   volumeIDAttrs.ContainingAggregateName(),
+	}
+}
@@ -10,11 +10,13 @@ import (
 	"io/ioutil"
 	"math/rand"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	log "github.com/sirupsen/logrus"
 
+	"github.com/netapp/trident/metrics"
 	"github.com/netapp/trident/utils"
 )
 
@@ -112,15 +114,20 @@ func (c *Client) Request(method string, params interface{}, id int) ([]byte, err
 		Transport: tr,
 		Timeout:   time.Duration(httpTimeoutSeconds * time.Second),
 	}
+	start := time.Now()
+	errorCode := ""
+	defer func() { metrics.ObserveStorageAPICall("solidfire", method, start, errorCode) }()
 	response, err = httpClient.Do(request)
 	if err != nil {
 		log.Errorf("Error response from SolidFire API request: %v", err)
+		errorCode = metrics.CodeTransportError
 		return nil, errors.New("device API error")
 	}
 
 	// Handle HTTP errors such as 401 (Unauthorized)
 	httpError := utils.NewHTTPError(response)
 	if httpError != nil {
+		errorCode = strconv.Itoa(response.StatusCode)
 		log.WithFields(log.Fields{
 			"request":        method,
 			"responseCode":   response.StatusCode,
@@ -132,6 +139,7 @@ func (c *Client) Request(method string, params interface{}, id int) ([]byte, err
 	defer response.Body.Close()
 	responseBody, err := ioutil.ReadAll(response.Body)
 	if err != nil {
+		errorCode = metrics.CodeTransportError
 		return responseBody, err
 	}
 
@@ -156,6 +164,7 @@ func (c *Client) Request(method string, params interface{}, id int) ([]byte, err
 			"message": apiError.Fields.Message,
 			"name":    apiError.Fields.Name,
 		}).Error("Error detected in API response.")
+		errorCode = apiError.Fields.Name
 		return nil, apiError
 	}
 
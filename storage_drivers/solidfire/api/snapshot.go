@@ -11,12 +11,16 @@ import (
 
 func (c *Client) CreateSnapshot(req *CreateSnapshotRequest) (snapshot Snapshot, err error) {
 	response, err := c.Request("CreateSnapshot", req, NewReqID())
+	if err != nil {
+		log.Errorf("Error in CreateSnapshot: %+v", err)
+		return Snapshot{}, errors.New("failed to create snapshot")
+	}
 	var result CreateSnapshotResult
 	if err := json.Unmarshal([]byte(response), &result); err != nil {
 		log.Errorf("Error detected unmarshalling CreateSnapshot json response: %+v", err)
 		return Snapshot{}, errors.New("json decode error")
 	}
-	return c.GetSnapshot(req.VolumeID, result.Result.SnapshotID, "")
+	return c.GetSnapshot(result.Result.SnapshotID, req.VolumeID, "")
 }
 
 func (c *Client) GetSnapshot(snapID, volID int64, sfName string) (s Snapshot, err error) {
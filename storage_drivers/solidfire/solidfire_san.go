@@ -528,6 +528,48 @@ func (d *SANStorageDriver) Destroy(name string) error {
 	return nil
 }
 
+// Resize expands the requested docker volume
+func (d *SANStorageDriver) Resize(name string, sizeBytes uint64) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":    "Resize",
+			"Type":      "SANStorageDriver",
+			"name":      name,
+			"sizeBytes": sizeBytes,
+		}
+		log.WithFields(fields).Debug(">>>> Resize")
+		defer log.WithFields(fields).Debug("<<<< Resize")
+	}
+
+	v, err := d.GetVolume(name)
+	if err != nil {
+		log.Errorf("Unable to locate volume for resize operation: %+v", err)
+		return err
+	}
+
+	if int64(sizeBytes) < v.TotalSize {
+		return fmt.Errorf("requested volume size (%d bytes) is smaller than the current volume size (%d bytes)",
+			sizeBytes, v.TotalSize)
+	}
+	if int64(sizeBytes) == v.TotalSize {
+		log.WithField("volume", name).Debug("Volume already has the requested size.")
+		return nil
+	}
+
+	var req api.ModifyVolumeRequest
+	req.VolumeID = v.VolumeID
+	req.AccountID = v.AccountID
+	req.TotalSize = int64(sizeBytes)
+	err = d.Client.ModifyVolume(&req)
+	if err != nil {
+		log.Errorf("Error during resize operation: %+v", err)
+		return err
+	}
+
+	return nil
+}
+
 // Attach the lun
 func (d *SANStorageDriver) Attach(name, mountpoint string, opts map[string]string) error {
 
@@ -543,6 +585,8 @@ func (d *SANStorageDriver) Attach(name, mountpoint string, opts map[string]strin
 		defer log.WithFields(fields).Debug("<<<< Attach")
 	}
 
+	readOnly := drivers.IsReadOnlyAttach(opts)
+
 	v, err := d.GetVolume(name)
 	if err != nil {
 		log.Errorf("Unable to locate volume for mount operation: %+v", err)
@@ -580,6 +624,9 @@ func (d *SANStorageDriver) Attach(name, mountpoint string, opts map[string]strin
 	// Put a filesystem on it if there isn't one already there
 	existingFstype := utils.GetFSType(device)
 	if existingFstype == "" {
+		if readOnly {
+			return fmt.Errorf("volume %s has no file system, so it cannot be mounted read-only", name)
+		}
 		log.WithFields(log.Fields{"LUN": path, "fstype": fstype}).Debug("Formatting LUN.")
 		err := utils.FormatVolume(device, fstype)
 		if err != nil {
@@ -596,7 +643,7 @@ func (d *SANStorageDriver) Attach(name, mountpoint string, opts map[string]strin
 		log.WithFields(log.Fields{"LUN": path, "fstype": existingFstype}).Debug("LUN already formatted.")
 	}
 
-	if mountErr := utils.Mount(device, mountpoint); mountErr != nil {
+	if mountErr := utils.Mount(device, mountpoint, readOnly); mountErr != nil {
 		log.Errorf("Unable to mount device: (device: %s, mountpoint: %s, error: %+v", device, mountpoint, err)
 		return errors.New("unable to mount device")
 	}
@@ -700,6 +747,160 @@ func (d *SANStorageDriver) SnapshotList(name string) ([]storage.Snapshot, error)
 	return snapshots, nil
 }
 
+// CreateSnapshot creates a snapshot of the named volume
+func (d *SANStorageDriver) CreateSnapshot(name, snapshot string) (*storage.Snapshot, error) {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":   "CreateSnapshot",
+			"Type":     "SANStorageDriver",
+			"name":     name,
+			"snapshot": snapshot,
+		}
+		log.WithFields(fields).Debug(">>>> CreateSnapshot")
+		defer log.WithFields(fields).Debug("<<<< CreateSnapshot")
+	}
+
+	v, err := d.GetVolume(name)
+	if err != nil {
+		log.Errorf("Unable to locate parent volume in snapshot create: %+v", err)
+		return nil, errors.New("volume not found")
+	}
+
+	// Check to see if the snapshot already exists
+	s, err := d.Client.GetSnapshot(0, v.VolumeID, snapshot)
+	if err == nil && s.SnapshotID != 0 {
+		log.Warningf("found existing snapshot by name: %s", snapshot)
+		return nil, errors.New("snapshot with requested name already exists")
+	}
+
+	var req api.CreateSnapshotRequest
+	req.VolumeID = v.VolumeID
+	req.Name = snapshot
+
+	s, err = d.Client.CreateSnapshot(&req)
+	if err != nil {
+		log.Errorf("Failed to create snapshot: %+v", err)
+		return nil, errors.New("error creating snapshot")
+	}
+
+	return &storage.Snapshot{Name: s.Name, Created: s.CreateTime}, nil
+}
+
+// DeleteSnapshot deletes a snapshot of the named volume
+func (d *SANStorageDriver) DeleteSnapshot(name, snapshot string) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":   "DeleteSnapshot",
+			"Type":     "SANStorageDriver",
+			"name":     name,
+			"snapshot": snapshot,
+		}
+		log.WithFields(fields).Debug(">>>> DeleteSnapshot")
+		defer log.WithFields(fields).Debug("<<<< DeleteSnapshot")
+	}
+
+	v, err := d.GetVolume(name)
+	if err != nil {
+		log.Errorf("Unable to locate parent volume in snapshot delete: %+v", err)
+		return errors.New("volume not found")
+	}
+
+	s, err := d.Client.GetSnapshot(0, v.VolumeID, snapshot)
+	if err != nil {
+		log.Errorf("Unable to locate snapshot: %+v", err)
+		return errors.New("error deleting snapshot")
+	}
+	if s.SnapshotID == 0 {
+		log.WithFields(log.Fields{
+			"snapshot": snapshot,
+			"volume":   name,
+		}).Warn("Snapshot already deleted.")
+		return nil
+	}
+
+	if err = d.Client.DeleteSnapshot(s.SnapshotID); err != nil {
+		log.Errorf("Failed to delete snapshot: %+v", err)
+		return errors.New("error deleting snapshot")
+	}
+
+	return nil
+}
+
+// RestoreSnapshot restores the named volume to the state captured by one of its snapshots
+func (d *SANStorageDriver) RestoreSnapshot(name, snapshot string) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":   "RestoreSnapshot",
+			"Type":     "SANStorageDriver",
+			"name":     name,
+			"snapshot": snapshot,
+		}
+		log.WithFields(fields).Debug(">>>> RestoreSnapshot")
+		defer log.WithFields(fields).Debug("<<<< RestoreSnapshot")
+	}
+
+	v, err := d.GetVolume(name)
+	if err != nil {
+		log.Errorf("Unable to locate parent volume in snapshot restore: %+v", err)
+		return errors.New("volume not found")
+	}
+
+	s, err := d.Client.GetSnapshot(0, v.VolumeID, snapshot)
+	if err != nil || s.SnapshotID == 0 {
+		log.Errorf("Unable to locate requested snapshot: %+v", err)
+		return errors.New("error restoring snapshot, snapshot not found")
+	}
+
+	var req api.RollbackToSnapshotRequest
+	req.VolumeID = v.VolumeID
+	req.SnapshotID = s.SnapshotID
+	req.SaveCurrentState = false
+
+	if _, err = d.Client.RollbackToSnapshot(&req); err != nil {
+		log.Errorf("Failed to restore snapshot: %+v", err)
+		return errors.New("error restoring snapshot")
+	}
+
+	return nil
+}
+
+// GetReplicationStatus returns the state of the relationship that replicates the named volume.  This driver doesn't replicate
+// volumes, so this method always returns nil.
+func (d *SANStorageDriver) GetReplicationStatus(name string) (*storage.ReplicationStatus, error) {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "GetReplicationStatus",
+			"Type":   "SANStorageDriver",
+			"name":   name,
+		}
+		log.WithFields(fields).Debug(">>>> GetReplicationStatus")
+		defer log.WithFields(fields).Debug("<<<< GetReplicationStatus")
+	}
+
+	return nil, nil
+}
+
+// Failover switches a volume to its replica.  This driver doesn't replicate volumes, so this method always
+// returns an error.
+func (d *SANStorageDriver) Failover(volConfig *storage.VolumeConfig) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "Failover",
+			"Type":   "SANStorageDriver",
+			"name":   volConfig.InternalName,
+		}
+		log.WithFields(fields).Debug(">>>> Failover")
+		defer log.WithFields(fields).Debug("<<<< Failover")
+	}
+
+	return errors.New("replication with SolidFire is not supported")
+}
+
 // Get tests for the existence of a volume
 func (d *SANStorageDriver) Get(name string) error {
 
@@ -795,8 +996,26 @@ func (d *SANStorageDriver) GetStorageBackendSpecs(backend *storage.Backend) erro
 			},
 		}
 	}
+
+	// All pools draw on the cluster's space, so they all report its capacity
+	var capacity *storage.PoolCapacity
+	clusterCapacity, err := d.Client.GetClusterCapacity()
+	if err != nil {
+		log.WithField("backend", backend.Name).Warnf("Could not read cluster capacity: %v", err)
+	} else if clusterCapacity != nil {
+		capacity = &storage.PoolCapacity{TotalBytes: uint64(clusterCapacity.MaxOverProvisionableSpace)}
+		if clusterCapacity.MaxOverProvisionableSpace > clusterCapacity.ProvisionedSpace {
+			capacity.AvailableBytes = uint64(clusterCapacity.MaxOverProvisionableSpace -
+				clusterCapacity.ProvisionedSpace)
+		}
+	}
+
 	for _, volType := range volTypes {
 		pool := storage.NewStoragePool(backend, volType.Type)
+		if capacity != nil {
+			poolCapacity := *capacity
+			pool.Capacity = &poolCapacity
+		}
 
 		pool.Attributes[sa.Media] = sa.NewStringOffer(sa.SSD)
 		pool.Attributes[sa.IOPS] = sa.NewIntOffer(int(volType.QOS.MinIOPS),
@@ -804,6 +1023,7 @@ func (d *SANStorageDriver) GetStorageBackendSpecs(backend *storage.Backend) erro
 		pool.Attributes[sa.Snapshots] = sa.NewBoolOffer(true)
 		pool.Attributes[sa.Clones] = sa.NewBoolOffer(true)
 		pool.Attributes[sa.Encryption] = sa.NewBoolOffer(false)
+		pool.Attributes[sa.Replication] = sa.NewBoolOffer(false)
 		pool.Attributes[sa.ProvisioningType] = sa.NewStringOffer("thin")
 		pool.Attributes[sa.BackendType] = sa.NewStringOffer(d.Name())
 		backend.AddStoragePool(pool)
@@ -848,6 +1068,46 @@ func (d *SANStorageDriver) CreateFollowup(volConfig *storage.VolumeConfig) error
 	return d.mapSolidfireLun(volConfig)
 }
 
+func (d *SANStorageDriver) Import(volConfig *storage.VolumeConfig, originalName string) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":       "Import",
+			"Type":         "SANStorageDriver",
+			"originalName": originalName,
+			"newName":      volConfig.InternalName,
+		}
+		log.WithFields(fields).Debug(">>>> Import")
+		defer log.WithFields(fields).Debug("<<<< Import")
+	}
+
+	v, err := d.GetVolume(originalName)
+	if err != nil {
+		return fmt.Errorf("could not find SolidFire volume %s: %v", originalName, err)
+	}
+
+	// Volumes are found by their docker-name attribute, so that is what
+	// gets renamed.
+	if volConfig.InternalName != originalName {
+		attrs, _ := v.Attributes.(map[string]interface{})
+		if attrs == nil {
+			attrs = make(map[string]interface{})
+		}
+		attrs["docker-name"] = volConfig.InternalName
+
+		var req api.ModifyVolumeRequest
+		req.VolumeID = v.VolumeID
+		req.AccountID = v.AccountID
+		req.Attributes = attrs
+		if err = d.Client.ModifyVolume(&req); err != nil {
+			return fmt.Errorf("could not rename SolidFire volume %s to %s: %v", originalName,
+				volConfig.InternalName, err)
+		}
+	}
+
+	return d.mapSolidfireLun(volConfig)
+}
+
 func (d *SANStorageDriver) mapSolidfireLun(volConfig *storage.VolumeConfig) error {
 	// Add the newly created volume to the default VAG
 	name := volConfig.InternalName
@@ -972,6 +1232,11 @@ func (d *SANStorageDriver) GetExternalConfig() interface{} {
 	}
 }
 
+// GetCommonConfig returns the settings the driver shares with all drivers.
+func (d *SANStorageDriver) GetCommonConfig() *drivers.CommonStorageDriverConfig {
+	return d.Config.CommonStorageDriverConfig
+}
+
 // Find/Return items that exist in b but NOT a
 func diffSlices(a, b []int64) []int64 {
 	var r []int64
@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 
@@ -23,6 +25,9 @@ type CommonStorageDriverConfig struct {
 	DisableDelete                     bool                  `json:"disableDelete"`
 	StoragePrefixRaw                  json.RawMessage       `json:"storagePrefix,string"`
 	StoragePrefix                     *string               `json:"-"`
+	OrphanPolicy                      string                `json:"orphanPolicy"`
+	OrphanGracePeriod                 string                `json:"orphanGracePeriod"`
+	Labels                            map[string]string     `json:"labels"`
 	SerialNumbers                     []string              `json:"-"`
 	DriverContext                     trident.DriverContext `json:"-"`
 	CommonStorageDriverConfigDefaults `json:"defaults"`
@@ -68,24 +73,46 @@ type OntapStorageDriverConfig struct {
 	SVM                              string `json:"svm"`
 	Username                         string `json:"username"`
 	Password                         string `json:"password"`
+	UseREST                          bool   `json:"useREST"` // use the ONTAP REST API instead of ZAPI
 	Aggregate                        string `json:"aggregate"`
-	UsageHeartbeat                   string `json:"usageHeartbeat"`           // in hours, default to 24.0
-	QtreePruneFlexvolsPeriod         string `json:"qtreePruneFlexvolsPeriod"` // in seconds, default to 600
-	QtreeQuotaResizePeriod           string `json:"qtreeQuotaResizePeriod"`   // in seconds, default to 60
+	UsageHeartbeat                   string `json:"usageHeartbeat"`                // in hours, default to 24.0
+	QtreePruneFlexvolsPeriod         string `json:"qtreePruneFlexvolsPeriod"`      // in seconds, default to 600
+	QtreeQuotaResizePeriod           string `json:"qtreeQuotaResizePeriod"`        // in seconds, default to 60
+	SANEconomyPruneFlexvolsPeriod    string `json:"sanEconomyPruneFlexvolsPeriod"` // in seconds, default to 600
+	SANEconomyFlexvolResizePeriod    string `json:"sanEconomyFlexvolResizePeriod"` // in seconds, default to 60
 	NfsMountOptions                  string `json:"nfsMountOptions"`
+	ReplicationSVM                   string `json:"replicationSVM"`
+	ReplicationManagementLIF         string `json:"replicationManagementLIF"` // defaults to managementLIF
+	ReplicationDataLIF               string `json:"replicationDataLIF"`
+	ReplicationAggregate             string `json:"replicationAggregate"`
+	ReplicationSchedule              string `json:"replicationSchedule"` // defaults to hourly
+	ReplicationPolicy                string `json:"replicationPolicy"`
+	OntapStorageDriverConfigDefaults `json:"defaults"`
+	Storage                          []OntapStorageDriverPool `json:"storage"`
+}
+
+// OntapStorageDriverPool is a virtual storage pool, which offers volumes with
+// its own defaults and labels from a subset of the SVM's aggregates.  Defaults
+// that a pool doesn't set are inherited from the backend.
+type OntapStorageDriverPool struct {
+	Name                             string            `json:"name"`
+	Labels                           map[string]string `json:"labels"`
+	Aggregates                       []string          `json:"aggregates"`
 	OntapStorageDriverConfigDefaults `json:"defaults"`
 }
 
 type OntapStorageDriverConfigDefaults struct {
-	SpaceReserve    string `json:"spaceReserve"`
-	SnapshotPolicy  string `json:"snapshotPolicy"`
-	UnixPermissions string `json:"unixPermissions"`
-	SnapshotDir     string `json:"snapshotDir"`
-	ExportPolicy    string `json:"exportPolicy"`
-	SecurityStyle   string `json:"securityStyle"`
-	SplitOnClone    string `json:"splitOnClone"`
-	FileSystemType  string `json:"fileSystemType"`
-	Encryption      string `json:"encryption"`
+	SpaceReserve      string `json:"spaceReserve"`
+	SnapshotPolicy    string `json:"snapshotPolicy"`
+	UnixPermissions   string `json:"unixPermissions"`
+	SnapshotDir       string `json:"snapshotDir"`
+	ExportPolicy      string `json:"exportPolicy"`
+	SecurityStyle     string `json:"securityStyle"`
+	SplitOnClone      string `json:"splitOnClone"`
+	FileSystemType    string `json:"fileSystemType"`
+	Encryption        string `json:"encryption"`
+	QosPolicy         string `json:"qosPolicy"`
+	AdaptiveQosPolicy string `json:"adaptiveQosPolicy"`
 }
 
 // SolidfireStorageDriverConfig holds settings for SolidfireStorageDrivers
@@ -184,16 +211,61 @@ func ValidateCommonSettings(configJSON string) (*CommonStorageDriverConfig, erro
 		log.Debug("Storage prefix is absent, will use default prefix.")
 	}
 
+	// Ensure the orphan policy and grace period are valid
+	switch config.OrphanPolicy {
+	case "":
+		config.OrphanPolicy = DefaultOrphanPolicy
+	case OrphanPolicyIgnore, OrphanPolicyReport, OrphanPolicyDelete:
+	default:
+		return nil, fmt.Errorf("invalid config value for orphan policy: %s; expected one of %s, %s or %s",
+			config.OrphanPolicy, OrphanPolicyIgnore, OrphanPolicyReport, OrphanPolicyDelete)
+	}
+	if config.OrphanGracePeriod == "" {
+		config.OrphanGracePeriod = DefaultOrphanGracePeriod
+	} else if _, err = time.ParseDuration(config.OrphanGracePeriod); err != nil {
+		return nil, fmt.Errorf("invalid config value for orphan grace period: %v", err)
+	}
+
+	// Ensure the labels can be matched by storage class attributes
+	if err = ValidateLabels(config.Labels); err != nil {
+		return nil, err
+	}
+
 	log.Debugf("Parsed commonConfig: %+v", *config)
 
 	return config, nil
 }
 
+// ValidateLabels ensures that the names and values of storage pool labels
+// can be matched by storage class attributes.
+func ValidateLabels(labels map[string]string) error {
+	for label, value := range labels {
+		if label == "" || strings.TrimSpace(label) != label {
+			return fmt.Errorf("invalid config value for label name: '%s'", label)
+		}
+		if value == "" {
+			return fmt.Errorf("invalid config value for label %s: the value is empty", label)
+		}
+	}
+	return nil
+}
+
+// ValidateOrphanPolicy ensures that a driver whose objects are known by their
+// storage prefix has one, if it is to delete orphaned objects; otherwise every
+// object on the storage would be taken for one of Trident's.  It must be
+// called once the default storage prefix has been applied.
+func ValidateOrphanPolicy(c *CommonStorageDriverConfig) error {
+	if c.OrphanPolicy == OrphanPolicyDelete && (c.StoragePrefix == nil || *c.StoragePrefix == "") {
+		return fmt.Errorf("the %s orphan policy requires a storage prefix", OrphanPolicyDelete)
+	}
+	return nil
+}
+
 func GetDefaultStoragePrefix(context trident.DriverContext) string {
 	switch context {
 	default:
 		fallthrough
-	case trident.ContextKubernetes:
+	case trident.ContextKubernetes, trident.ContextCSI:
 		return DefaultTridentStoragePrefix
 	case trident.ContextDocker:
 		return DefaultDockerStoragePrefix
@@ -204,7 +276,7 @@ func GetDefaultIgroupName(context trident.DriverContext) string {
 	switch context {
 	default:
 		fallthrough
-	case trident.ContextKubernetes:
+	case trident.ContextKubernetes, trident.ContextCSI:
 		return DefaultTridentIgroupName
 	case trident.ContextDocker:
 		return DefaultDockerIgroupName
@@ -212,10 +284,13 @@ func GetDefaultIgroupName(context trident.DriverContext) string {
 }
 
 type CommonStorageDriverConfigExternal struct {
-	Version           int      `json:"version"`
-	StorageDriverName string   `json:"storageDriverName"`
-	StoragePrefix     *string  `json:"storagePrefix"`
-	SerialNumbers     []string `json:"serialNumbers"`
+	Version           int               `json:"version"`
+	StorageDriverName string            `json:"storageDriverName"`
+	StoragePrefix     *string           `json:"storagePrefix"`
+	OrphanPolicy      string            `json:"orphanPolicy"`
+	OrphanGracePeriod string            `json:"orphanGracePeriod"`
+	Labels            map[string]string `json:"labels"`
+	SerialNumbers     []string          `json:"serialNumbers"`
 }
 
 func SanitizeCommonStorageDriverConfig(c *CommonStorageDriverConfig) {
@@ -234,10 +309,20 @@ func GetCommonStorageDriverConfigExternal(
 		Version:           c.Version,
 		StorageDriverName: c.StorageDriverName,
 		StoragePrefix:     c.StoragePrefix,
+		OrphanPolicy:      c.OrphanPolicy,
+		OrphanGracePeriod: c.OrphanGracePeriod,
+		Labels:            c.Labels,
 		SerialNumbers:     c.SerialNumbers,
 	}
 }
 
+// IsReadOnlyAttach returns whether the options passed to a driver's Attach
+// method ask for the volume to be mounted read-only.
+func IsReadOnlyAttach(opts map[string]string) bool {
+	readOnly, _ := strconv.ParseBool(opts[AttachOptionReadOnly])
+	return readOnly
+}
+
 func GetCommonInternalVolumeName(c *CommonStorageDriverConfig, name string) string {
 
 	prefixToUse := trident.OrchestratorName
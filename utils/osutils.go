@@ -687,12 +687,14 @@ func FormatVolume(device, fstype string) error {
 	return err
 }
 
-// Mount attaches the supplied device at the supplied location
-func Mount(device, mountpoint string) error {
+// Mount attaches the supplied device at the supplied location, read-only if
+// readOnly is set
+func Mount(device, mountpoint string, readOnly bool) error {
 
 	log.WithFields(log.Fields{
 		"device":     device,
 		"mountpoint": mountpoint,
+		"readOnly":   readOnly,
 	}).Debug(">>>> osutils.Mount")
 	defer log.Debug("<<<< osutils.Mount")
 
@@ -700,13 +702,62 @@ func Mount(device, mountpoint string) error {
 	if err != nil {
 		log.Warning("Mkdir failed.")
 	}
-	_, err = InvokeShellCommand("mount", device, mountpoint)
+	if readOnly {
+		_, err = InvokeShellCommand("mount", "-o", "ro", device, mountpoint)
+	} else {
+		_, err = InvokeShellCommand("mount", device, mountpoint)
+	}
 	if err != nil {
 		log.Error("Mount failed.")
 	}
 	return err
 }
 
+// BindMount makes the supplied directory also accessible at the supplied
+// location, read-only if readOnly is set
+func BindMount(source, mountpoint string, readOnly bool) error {
+
+	log.WithFields(log.Fields{
+		"source":     source,
+		"mountpoint": mountpoint,
+		"readOnly":   readOnly,
+	}).Debug(">>>> osutils.BindMount")
+	defer log.Debug("<<<< osutils.BindMount")
+
+	_, err := InvokeShellCommand("mkdir", "-p", mountpoint)
+	if err != nil {
+		log.Warning("Mkdir failed.")
+	}
+	if _, err = InvokeShellCommand("mount", "--bind", source, mountpoint); err != nil {
+		log.Error("Bind mount failed.")
+		return err
+	}
+	if readOnly {
+		// A bind mount only becomes read-only when it is remounted
+		if _, err = InvokeShellCommand("mount", "-o", "remount,bind,ro", mountpoint); err != nil {
+			log.Error("Read-only remount failed.")
+			Umount(mountpoint)
+			return err
+		}
+	}
+	return nil
+}
+
+// IsMounted returns whether anything is mounted at the supplied location
+func IsMounted(mountpoint string) (bool, error) {
+
+	dfOutput, err := GetDFOutput()
+	if err != nil {
+		return false, err
+	}
+	for _, e := range dfOutput {
+		if e.Target == mountpoint {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // Umount detaches from the supplied location
 func Umount(mountpoint string) error {
 